@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// newFlagSet is a flag set for a headless subcommand. -d and --destination
+// both set dest; the usage line is printed on a parse error or -h.
+func newFlagSet(name, usage string, dest *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(dest, "d", "", "destination `NAME` (deploy.NAME.yml)")
+	fs.StringVar(dest, "destination", "", "destination `NAME` (deploy.NAME.yml)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: "+usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseCLI parses args into fs and returns the project directory: the one
+// optional positional argument, or the working directory. The int is the
+// exit code to return when ok is false.
+func parseCLI(fs *flag.FlagSet, args []string) (dir string, code int, ok bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", 0, false
+		}
+		return "", 2, false
+	}
+	switch fs.NArg() {
+	case 0:
+		cwd, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return "", 1, false
+		}
+		return cwd, 0, true
+	case 1:
+		return fs.Arg(0), 0, true
+	}
+	fmt.Fprintf(os.Stderr, "Error: unexpected arguments %q (options go before the project path)\n", fs.Args()[1:])
+	fs.Usage()
+	return "", 2, false
+}
+
+// runStatus implements `lazykamal status`: the status panel's version and
+// container calls for one destination, or every listed one, printed as a
+// summary or JSON. It exits 1 when any call failed.
+func runStatus(args []string) int {
+	var name string
+	fs := newFlagSet("status", "lazykamal status [-d NAME] [--json] [path]", &name)
+	asJSON := fs.Bool("json", false, "print JSON instead of a summary")
+	dir, code, ok := parseCLI(fs, args)
+	if !ok {
+		return code
+	}
+	if err := checkKamalInstalled(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	cfg, err := kamal.LoadProjectConfig(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	var dests []kamal.DeployDestination
+	if name != "" {
+		d, err := kamal.ResolveDestination(dir, name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		dests = []kamal.DeployDestination{*d}
+	} else {
+		all, err := kamal.FindDeployConfigs(dir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		dests, _ = cfg.ApplyDestinations(all, false)
+		if len(dests) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no config/deploy.yml found in %s\n", dir)
+			return 1
+		}
+	}
+
+	statuses := make([]kamal.AppStatus, len(dests))
+	failed := false
+	for i := range dests {
+		opts := kamal.RunOpts(dir, &dests[i])
+		opts.Defaults = cfg.Commands
+		statuses[i] = kamal.FetchStatus(opts, &dests[i])
+		failed = failed || len(statuses[i].Errors) > 0
+	}
+	if *asJSON {
+		data, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		for i, s := range statuses {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Print(s.Summary())
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// runDeploy implements `lazykamal deploy`: kamal deploy against a resolved
+// destination with the .lazykamal.yml defaults the TUI applies, output
+// streamed to stdout and kamal's exit code returned. Ctrl+C cancels it.
+func runDeploy(args []string) int {
+	var name string
+	fs := newFlagSet("deploy", "lazykamal deploy [-d NAME] [--skip-push] [--yes] [path]", &name)
+	skipPush := fs.Bool("skip-push", false, "deploy the image already in the registry (--skip-push)")
+	yes := fs.Bool("yes", false, "deploy a destination marked protected in .lazykamal.yml")
+	dir, code, ok := parseCLI(fs, args)
+	if !ok {
+		return code
+	}
+	if err := checkKamalInstalled(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	cfg, err := kamal.LoadProjectConfig(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	d, err := kamal.ResolveDestination(dir, name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	marked, _ := cfg.ApplyDestinations([]kamal.DeployDestination{*d}, true)
+	dest := &marked[0]
+	if dest.Protected && !*yes {
+		fmt.Fprintf(os.Stderr, "Error: %s is protected in .lazykamal.yml; pass --yes to deploy it\n", dest.Label())
+		return 1
+	}
+
+	subcommand := []string{"deploy"}
+	if *skipPush {
+		subcommand = append(subcommand, "--skip-push")
+	}
+	opts := kamal.RunOpts(dir, dest)
+	opts.Defaults = cfg.Commands
+	opts.OnRun = func(args, _ []string) {
+		fmt.Fprintln(os.Stderr, "$ "+kamal.CommandLine(args))
+	}
+
+	stopCh := make(chan struct{})
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		<-sigCh
+		close(stopCh)
+	}()
+
+	res, err := kamal.RunKamalStreamWithStop(subcommand, opts, func(line string) {
+		fmt.Println(line)
+	}, stopCh)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		if res.ExitCode > 0 {
+			return res.ExitCode
+		}
+		return 1
+	}
+	return res.ExitCode
+}
+
+// runInitConfig implements `lazykamal init-config`: it writes the commented
+// .lazykamal.yml template, then loads it back and prints anything that would
+// be ignored. An existing file is kept unless --force is given.
+func runInitConfig(args []string) int {
+	fs := flag.NewFlagSet("init-config", flag.ContinueOnError)
+	force := fs.Bool("force", false, "replace an existing "+kamal.ProjectConfigFile)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: lazykamal init-config [--force] [path]")
+		fs.PrintDefaults()
+	}
+	dir, code, ok := parseCLI(fs, args)
+	if !ok {
+		return code
+	}
+	path, err := kamal.WriteProjectConfigTemplate(dir, *force)
+	if errors.Is(err, kamal.ErrProjectConfigExists) {
+		fmt.Fprintf(os.Stderr, "Error: %s already exists; pass --force to replace it\n", path)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	fmt.Println("Wrote " + path)
+	warnings, err := kamal.CheckProjectConfig(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	for _, w := range warnings {
+		fmt.Fprintln(os.Stderr, "Warning:", w)
+	}
+	return 0
+}
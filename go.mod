@@ -7,6 +7,6 @@ require github.com/jroimartin/gocui v0.5.0
 require gopkg.in/yaml.v3 v3.0.1
 
 require (
-	github.com/mattn/go-runewidth v0.0.9 // indirect
+	github.com/mattn/go-runewidth v0.0.9
 	github.com/nsf/termbox-go v1.1.1 // indirect
 )
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/jroimartin/gocui"
 	"github.com/shuvro/lazykamal/pkg/gui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+	"github.com/shuvro/lazykamal/pkg/ssh"
 	"github.com/shuvro/lazykamal/pkg/upgrade"
 )
 
@@ -25,6 +29,13 @@ func checkKamalInstalled() error {
 }
 
 func main() {
+	// --no-color and --ascii apply to every mode, so take them out first.
+	var style gui.Style
+	args := os.Args[1:]
+	style.NoColor, args = takeFlag(args, "--no-color")
+	style.ASCII, args = takeFlag(args, "--ascii")
+	os.Args = append(os.Args[:1], args...)
+
 	// Handle --version flag
 	if len(os.Args) == 2 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
 		fmt.Println("lazykamal", version)
@@ -71,28 +82,39 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Handle --server flag for server mode
-	for i, arg := range os.Args[1:] {
-		if arg == "--server" || arg == "-s" {
-			if i+2 >= len(os.Args) {
-				fmt.Fprintln(os.Stderr, "Error: --server requires a host argument")
-				fmt.Fprintln(os.Stderr, "Usage: lazykamal --server [user@]host[:port]")
-				os.Exit(1)
-			}
-			host := os.Args[i+2]
-			runServerMode(host)
-			os.Exit(0)
-		}
-		if strings.HasPrefix(arg, "--server=") {
-			host := strings.TrimPrefix(arg, "--server=")
-			runServerMode(host)
-			os.Exit(0)
-		}
-		if strings.HasPrefix(arg, "-s=") {
-			host := strings.TrimPrefix(arg, "-s=")
-			runServerMode(host)
-			os.Exit(0)
+	// Handle exec: run a raw kamal command against a resolved destination
+	if len(os.Args) >= 2 && os.Args[1] == "exec" {
+		os.Exit(runExec(os.Args[2:]))
+	}
+
+	// Handle status and deploy: headless commands for scripts and CI
+	if len(os.Args) >= 2 && os.Args[1] == "status" {
+		os.Exit(runStatus(os.Args[2:]))
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "deploy" {
+		os.Exit(runDeploy(os.Args[2:]))
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "init-config" {
+		os.Exit(runInitConfig(os.Args[2:]))
+	}
+
+	// Handle --server flag and server subcommand for server mode
+	if host, ok, err := parseServer(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		fmt.Fprintln(os.Stderr, "Usage: lazykamal --server [user@]host[:port]")
+		os.Exit(2)
+	} else if ok {
+		runServerMode(host, style)
+		os.Exit(0)
+	}
+
+	// --demo runs the TUI on a throwaway project with canned kamal output
+	if demo, rest := takeFlag(args, "--demo"); demo {
+		if len(rest) > 0 {
+			fmt.Fprintf(os.Stderr, "Error: --demo takes no other arguments, got %q\n", rest)
+			os.Exit(2)
 		}
+		os.Exit(runDemo(style))
 	}
 
 	// Check that kamal is installed before starting the TUI
@@ -101,41 +123,153 @@ func main() {
 		os.Exit(1)
 	}
 
-	g, err := gui.New(version)
+	only, args, err := parseOnly(args)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
+	noSummary, args := takeFlag(args, "--no-summary")
+
+	gui.PrepareTerminal(os.Stderr, style)
+	g, err := gui.New(version)
+	if err != nil {
+		gui.ReportStartupError(os.Stderr, err)
+		os.Exit(1)
+	}
 
 	// Set working directory if provided
-	if len(os.Args) > 1 && os.Args[1] != "" && os.Args[1][0] != '-' {
-		if err := g.SetCwd(os.Args[1]); err != nil {
-			fmt.Fprintln(os.Stderr, "Error:", err)
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		if err := g.SetCwd(args[0]); err != nil {
+			g.Close()
+			gui.ReportStartupError(os.Stderr, err)
 			os.Exit(1)
 		}
 	}
+	if len(only) > 0 {
+		g.SetOnly(only)
+	}
 
-	// Setup graceful shutdown
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	if err := runTUI(g); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+	if !noSummary {
+		fmt.Print(g.SessionSummary())
+	}
+}
 
-	// Run in goroutine so we can handle signals
-	errCh := make(chan error, 1)
-	go func() {
-		errCh <- g.Run()
-	}()
+// runDemo starts the TUI on the demo project in a temporary directory,
+// removed on quit. kamal need not be installed.
+func runDemo(style gui.Style) int {
+	dir, err := os.MkdirTemp("", "lazykamal-demo-")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	if err := kamal.WriteDemoProject(dir); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	gui.PrepareTerminal(os.Stderr, style)
+	g, err := gui.NewDemo(version, dir)
+	if err != nil {
+		gui.ReportStartupError(os.Stderr, err)
+		return 1
+	}
+	if err := runTUI(g); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	return 0
+}
 
-	// Wait for either GUI exit or signal
-	select {
-	case err := <-errCh:
-		if err != nil && err != gocui.ErrQuit {
-			fmt.Fprintln(os.Stderr, "Error:", err)
-			os.Exit(1)
+// takeFlag removes every occurrence of the boolean flag name from args and
+// reports whether it was there.
+func takeFlag(args []string, name string) (bool, []string) {
+	found := false
+	var rest []string
+	for _, arg := range args {
+		if arg == name {
+			found = true
+			continue
 		}
-	case sig := <-sigCh:
-		fmt.Fprintf(os.Stderr, "\nReceived %s, shutting down...\n", sig)
-		g.Close()
+		rest = append(rest, arg)
 	}
+	return found, rest
+}
+
+// parseServer finds the server mode target in args: `server HOST`,
+// --server HOST, -s HOST, --server=HOST or -s=HOST. Server mode takes no
+// other arguments, so a project path next to it is an error rather than
+// silently ignored.
+func parseServer(args []string) (string, bool, error) {
+	if len(args) > 0 && args[0] == "server" {
+		if len(args) < 2 {
+			return "", false, fmt.Errorf("server requires a host argument")
+		}
+		if len(args) > 2 {
+			return "", false, fmt.Errorf("unexpected arguments %q after the server host", args[2:])
+		}
+		return args[1], true, nil
+	}
+	host, found := "", false
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--server" || arg == "-s":
+			if i+1 >= len(args) {
+				return "", false, fmt.Errorf("%s requires a host argument", arg)
+			}
+			i++
+			host, found = args[i], true
+		case strings.HasPrefix(arg, "--server="):
+			host, found = strings.TrimPrefix(arg, "--server="), true
+		case strings.HasPrefix(arg, "-s="):
+			host, found = strings.TrimPrefix(arg, "-s="), true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if !found {
+		return "", false, nil
+	}
+	for _, arg := range rest {
+		if !strings.HasPrefix(arg, "-") {
+			return "", false, fmt.Errorf("both a project path (%s) and --server were given; server mode discovers apps on the host, pass one or the other", arg)
+		}
+		return "", false, fmt.Errorf("%s is not supported in server mode", arg)
+	}
+	return host, true, nil
+}
+
+// parseOnly pulls the repeatable --only GLOB (or --only=GLOB) flags out of
+// args and returns the patterns and the remaining arguments.
+func parseOnly(args []string) ([]string, []string, error) {
+	var only, rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		pattern := ""
+		switch {
+		case arg == "--only":
+			if i+1 >= len(args) {
+				return nil, nil, fmt.Errorf("--only requires a glob argument, e.g. --only 'myapp*'")
+			}
+			i++
+			pattern = args[i]
+		case strings.HasPrefix(arg, "--only="):
+			pattern = strings.TrimPrefix(arg, "--only=")
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+		if err := kamal.ValidateGlob(pattern); err != nil {
+			return nil, nil, fmt.Errorf("--only %q: %w", pattern, err)
+		}
+		only = append(only, pattern)
+	}
+	return only, rest, nil
 }
 
 func printHelp() {
@@ -145,11 +279,27 @@ Usage:
   lazykamal [path]              Project mode: Start TUI in the specified directory
   lazykamal                     Project mode: Start TUI in the current directory
   lazykamal --server HOST       Server mode: Connect to server and discover all apps
+  lazykamal server HOST         Same as --server HOST
+  lazykamal --only 'myapp*'     Project mode: Only list apps whose label matches
+  lazykamal exec -d NAME -- ARGS  Run kamal ARGS against destination NAME, resolved as in the TUI
+  lazykamal status [-d NAME] [--json] [path]
+                                Print versions and containers of NAME (or every app) without the TUI
+  lazykamal deploy [-d NAME] [--skip-push] [path]
+                                Deploy NAME without the TUI, streaming kamal's output
+  lazykamal init-config [--force] [path]
+                                Write a commented .lazykamal.yml documenting every option
 
 Options:
   -h, --help            Show this help message
   -v, --version         Show version information
-  -s, --server HOST     Server mode: SSH to HOST and show all Kamal apps
+  -s, --server HOST     Server mode: SSH to HOST ([user@]host[:port]) and show
+                        all Kamal apps; needs ssh on PATH, takes no project path
+  --only GLOB           Only list apps whose label matches GLOB (repeatable)
+  --demo                Try the TUI offline on a sample project with canned
+                        kamal output; kamal need not be installed
+  --no-summary          Do not print the session recap on quit
+  --no-color            Turn colors off (also NO_COLOR=1)
+  --ascii               Use ASCII icons, spinner and frames instead of Unicode
   --upgrade             Upgrade to the latest version
   --check-update        Check if an update is available
   --uninstall           Remove lazykamal from your system
@@ -158,6 +308,7 @@ Server Mode Examples:
   lazykamal --server 100.70.90.101
   lazykamal --server user@myserver.com
   lazykamal -s deploy@production:2222
+  lazykamal server deploy@production
 
 Keyboard Shortcuts:
   ↑/↓         Navigate menus
@@ -168,41 +319,108 @@ Keyboard Shortcuts:
   j/k         Scroll log down/up
   J/K         Scroll status down/up
   c           Clear log
+  /           Filter apps (Apps screen)
   ?           Show help overlay
   q           Quit
 
 For more information, visit: https://github.com/shuvro/lazykamal`)
 }
 
-func runServerMode(host string) {
-	fmt.Printf("Connecting to %s...\n", host)
-
-	g, err := gui.NewServerMode(version, host)
+// runExec implements `lazykamal exec`: it resolves the destination the
+// way the TUI does and runs kamal with its global options plus the given
+// arguments, passing output through and returning kamal's exit code.
+func runExec(args []string) int {
+	e, err := kamal.ParseExecArgs(args)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		fmt.Fprintln(os.Stderr, "Usage: lazykamal exec [--destination NAME] -- KAMAL_ARGS...")
+		return 2
 	}
+	if err := checkKamalInstalled(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	dest, err := kamal.ResolveDestination(cwd, e.Destination)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return 1
+	}
+	code, err := kamal.Passthrough(cwd, kamal.PassthroughArgs(cwd, dest, e.Args), os.Stdin, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
+	return code
+}
 
-	// Setup graceful shutdown
+// tui is the project or server mode GUI as main drives it.
+type tui interface {
+	Run() error
+	Stop()
+	Close()
+}
+
+// stopTimeout is how long a signal waits for the main loop to end before
+// the terminal is restored directly.
+const stopTimeout = 3 * time.Second
+
+// runTUI runs t until it quits or SIGINT/SIGTERM arrives. A signal stops
+// the main loop and waits for Run to restore the terminal, so the shell is
+// not left in raw mode; Close is the fallback when the loop does not end
+// in time, e.g. while an external program has the terminal.
+func runTUI(t tui) error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
 
-	// Run in goroutine so we can handle signals
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- g.Run()
+		errCh <- t.Run()
 	}()
 
-	// Wait for either GUI exit or signal
+	var err error
 	select {
-	case err := <-errCh:
-		if err != nil && err != gocui.ErrQuit {
-			fmt.Fprintln(os.Stderr, "Error:", err)
-			os.Exit(1)
-		}
+	case err = <-errCh:
 	case sig := <-sigCh:
-		fmt.Fprintf(os.Stderr, "\nReceived %s, shutting down...\n", sig)
-		g.Close()
+		t.Stop()
+		select {
+		case err = <-errCh:
+		case <-time.After(stopTimeout):
+			t.Close()
+		}
+		fmt.Fprintf(os.Stderr, "Received %s, shut down\n", sig)
+	}
+	if err != nil && !errors.Is(err, gocui.ErrQuit) {
+		return err
+	}
+	return nil
+}
+
+func runServerMode(host string, style gui.Style) {
+	if err := ssh.ValidateHost(host); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		fmt.Fprintln(os.Stderr, "Usage: lazykamal --server [user@]host[:port]")
+		os.Exit(2)
+	}
+	if err := ssh.CheckInstalled(); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	gui.PrepareTerminal(os.Stderr, style)
+	g, err := gui.NewServerMode(version, host)
+	if err != nil {
+		gui.ReportStartupError(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := runTUI(g); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
 }
 
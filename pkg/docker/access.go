@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// AccessProblem classifies why docker could not be used on the remote host.
+type AccessProblem int
+
+const (
+	AccessOK       AccessProblem = iota // not a docker access problem
+	AccessDenied                        // the user cannot reach the Docker daemon socket
+	AccessNotFound                      // the docker CLI is not installed or not on PATH
+)
+
+// ClassifyAccessError reports whether err, from a docker command run over
+// SSH, means the user lacks permission on the daemon or docker is missing.
+// The stderr of the remote command is part of the error message.
+func ClassifyAccessError(err error) AccessProblem {
+	if err == nil {
+		return AccessOK
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "permission denied while trying to connect to the docker daemon"),
+		strings.Contains(msg, "got permission denied") && strings.Contains(msg, "docker.sock"):
+		return AccessDenied
+	case strings.Contains(msg, "docker: command not found"),
+		strings.Contains(msg, "docker: not found"),
+		strings.Contains(msg, "command not found: docker"):
+		return AccessNotFound
+	}
+	return AccessOK
+}
+
+// AccessGuidance explains how to fix problem for user on host, one line per
+// element.
+func AccessGuidance(problem AccessProblem, user, host string) []string {
+	switch problem {
+	case AccessDenied:
+		return []string{
+			fmt.Sprintf("%s cannot reach the Docker daemon on %s (permission denied).", user, host),
+			fmt.Sprintf("Add %s to the docker group, then reconnect:", user),
+			fmt.Sprintf("  ssh %s@%s sudo usermod -aG docker %s", user, host, user),
+			fmt.Sprintf("or connect as root: lazykamal --server root@%s", host),
+		}
+	case AccessNotFound:
+		return []string{
+			fmt.Sprintf("docker is not installed on %s (or not on %s's PATH).", host, user),
+			"Install it with `kamal server bootstrap` from your project, or see https://docs.docker.com/engine/install/",
+		}
+	}
+	return nil
+}
+
+// CanSudo reports whether client's user may run sudo without a password,
+// which is what sudo-docker mode needs.
+func CanSudo(client *ssh.Client) bool {
+	_, err := client.Run("sudo -n true")
+	return err == nil
+}
@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+func TestClassifyAccessError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want AccessProblem
+	}{
+		{nil, AccessOK},
+		{errors.New("exit status 1: permission denied while trying to connect to the Docker daemon socket at unix:///var/run/docker.sock: Get \"http://%2Fvar%2Frun%2Fdocker.sock/v1.24/containers/json\": dial unix /var/run/docker.sock: connect: permission denied"), AccessDenied},
+		{errors.New("exit status 1: Got permission denied while trying to connect to the Docker daemon socket at unix:///var/run/docker.sock"), AccessDenied},
+		{errors.New("exit status 127: bash: line 1: docker: command not found"), AccessNotFound},
+		{errors.New("exit status 127: sh: 1: docker: not found"), AccessNotFound},
+		{errors.New("exit status 127: zsh:1: command not found: docker"), AccessNotFound},
+		{errors.New("exit status 255: ssh: connect to host 10.0.0.1 port 22: Connection refused"), AccessOK},
+		{errors.New("command timed out after 30s"), AccessOK},
+	}
+	for _, tt := range tests {
+		if got := ClassifyAccessError(tt.err); got != tt.want {
+			t.Errorf("ClassifyAccessError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestBinSudo(t *testing.T) {
+	client := &ssh.Client{Host: "h"}
+	if got := dockerCommand(client, "ps"); got != "docker ps" {
+		t.Errorf("dockerCommand() = %q, want plain docker", got)
+	}
+	client.Sudo = true
+	if got := dockerCommand(client, "stop", "a b"); got != "sudo -n docker stop 'a b'" {
+		t.Errorf("dockerCommand(sudo) = %q", got)
+	}
+	if got := refreshCommand(client, []string{"app"}); got[:len("sudo -n docker ps")] != "sudo -n docker ps" {
+		t.Errorf("refreshCommand(sudo) = %q", got)
+	}
+	if got := ProxyRecreateCommand(client, ProxyContainer{}, "basecamp/kamal-proxy:latest"); got != "sudo -n docker run --detach --name kamal-proxy basecamp/kamal-proxy:latest" {
+		t.Errorf("ProxyRecreateCommand(sudo) = %q", got)
+	}
+}
@@ -0,0 +1,226 @@
+package docker
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// RunningImage identifies the image a container runs: the local image ID
+// (the digest of its config) and the registry digest it was pulled by, when
+// docker recorded one. Tags can be moved; these cannot.
+type RunningImage struct {
+	ID         string
+	RepoDigest string
+}
+
+// ShortDigest shortens "sha256:<hex>" to the first 12 hex digits, as docker
+// shortens image IDs.
+func ShortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return digest
+}
+
+// imageRepo strips the tag and digest from an image reference
+// ("registry:5000/app:v1" -> "registry:5000/app").
+func imageRepo(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > 0 && !strings.Contains(image[i:], "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// repoDigestsCommand inspects images in one docker call, printing
+// "<image id> <repo>@<digest> ..." per line.
+func repoDigestsCommand(client *ssh.Client, imageIDs []string) string {
+	args := append([]string{"image", "inspect", "--format", `{{.Id}} {{join .RepoDigests " "}}`}, imageIDs...)
+	return dockerCommand(client, args...)
+}
+
+// parseRepoDigests maps image IDs to their RepoDigests from
+// repoDigestsCommand output.
+func parseRepoDigests(output string) map[string][]string {
+	digests := map[string][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "sha256:") {
+			continue
+		}
+		digests[fields[0]] = fields[1:]
+	}
+	return digests
+}
+
+// pickRepoDigest returns the digest of the entry in repoDigests for image's
+// repository. An image pulled from a single repository has one entry, which
+// is used even when docker wrote the repository name differently.
+func pickRepoDigest(repoDigests []string, image string) string {
+	repo := imageRepo(image)
+	for _, rd := range repoDigests {
+		if name, digest, ok := strings.Cut(rd, "@"); ok && name == repo {
+			return digest
+		}
+	}
+	if len(repoDigests) == 1 {
+		if _, digest, ok := strings.Cut(repoDigests[0], "@"); ok {
+			return digest
+		}
+	}
+	return ""
+}
+
+// RunningImages fetches the image of every container in apps with two bulk
+// docker inspects, one for the containers and one for their images.
+func RunningImages(client *ssh.Client, apps []App) (map[string]RunningImage, error) {
+	ids, err := ImageDigests(client, apps)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var imageIDs []string
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			imageIDs = append(imageIDs, id)
+		}
+	}
+	if len(imageIDs) == 0 {
+		return map[string]RunningImage{}, nil
+	}
+	sort.Strings(imageIDs)
+	output, err := client.Run(repoDigestsCommand(client, imageIDs))
+	if err != nil {
+		return nil, err
+	}
+	return runningImages(apps, ids, parseRepoDigests(output)), nil
+}
+
+// runningImages combines the container -> image ID map with each image's
+// RepoDigests.
+func runningImages(apps []App, imageIDs map[string]string, repoDigests map[string][]string) map[string]RunningImage {
+	out := map[string]RunningImage{}
+	add := func(containers []Container) {
+		for _, c := range containers {
+			id, ok := imageIDs[c.ID]
+			if !ok {
+				continue
+			}
+			out[c.ID] = RunningImage{ID: id, RepoDigest: pickRepoDigest(repoDigests[id], c.Image)}
+		}
+	}
+	for _, app := range apps {
+		add(app.Containers)
+		for _, acc := range app.Accessories {
+			add(acc.Containers)
+		}
+	}
+	return out
+}
+
+// RegistryManifest is what the registry holds for a tag: the manifest
+// digests and the image config digests they point to, one of each per
+// platform for a multi-platform tag.
+type RegistryManifest struct {
+	Digests []string
+	Configs []string
+}
+
+// manifestInspectCommand asks the registry for image's manifest. The
+// verbose form includes the digests, which the plain form leaves out.
+func manifestInspectCommand(client *ssh.Client, image string) string {
+	return dockerCommand(client, "manifest", "inspect", "-v", image)
+}
+
+// parseManifestInspect parses `docker manifest inspect -v` output: one
+// object for a single manifest, an array of them for a manifest list.
+func parseManifestInspect(output string) (RegistryManifest, error) {
+	type entry struct {
+		Descriptor struct {
+			Digest string `json:"digest"`
+		}
+		SchemaV2Manifest *struct {
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+		}
+		OCIManifest *struct {
+			Config struct {
+				Digest string `json:"digest"`
+			} `json:"config"`
+		}
+	}
+	output = strings.TrimSpace(output)
+	var entries []entry
+	if strings.HasPrefix(output, "[") {
+		if err := json.Unmarshal([]byte(output), &entries); err != nil {
+			return RegistryManifest{}, err
+		}
+	} else {
+		var e entry
+		if err := json.Unmarshal([]byte(output), &e); err != nil {
+			return RegistryManifest{}, err
+		}
+		entries = []entry{e}
+	}
+	var m RegistryManifest
+	for _, e := range entries {
+		if e.Descriptor.Digest != "" {
+			m.Digests = append(m.Digests, e.Descriptor.Digest)
+		}
+		switch {
+		case e.SchemaV2Manifest != nil && e.SchemaV2Manifest.Config.Digest != "":
+			m.Configs = append(m.Configs, e.SchemaV2Manifest.Config.Digest)
+		case e.OCIManifest != nil && e.OCIManifest.Config.Digest != "":
+			m.Configs = append(m.Configs, e.OCIManifest.Config.Digest)
+		}
+	}
+	return m, nil
+}
+
+// InspectManifest fetches the registry's manifest for image, running
+// `docker manifest inspect` on the server so its registry login is used.
+func InspectManifest(client *ssh.Client, image string) (RegistryManifest, error) {
+	output, err := client.Run(manifestInspectCommand(client, image))
+	if err != nil {
+		return RegistryManifest{}, err
+	}
+	return parseManifestInspect(output)
+}
+
+// DigestVerdict is the outcome of comparing a running image to the registry.
+type DigestVerdict int
+
+const (
+	DigestUnknown  DigestVerdict = iota // nothing to compare
+	DigestMatch                         // the tag still names the running image
+	DigestMismatch                      // the tag was moved, e.g. force-pushed
+)
+
+// CompareDigest compares a running image to the registry manifest for its
+// tag. The image ID is matched against the config digests, which works for
+// manifest lists too; the repo digest against the manifest digests, for
+// images whose ID docker computed differently.
+func CompareDigest(running RunningImage, m RegistryManifest) DigestVerdict {
+	if (running.ID == "" && running.RepoDigest == "") || (len(m.Digests) == 0 && len(m.Configs) == 0) {
+		return DigestUnknown
+	}
+	for _, c := range m.Configs {
+		if c == running.ID {
+			return DigestMatch
+		}
+	}
+	for _, d := range m.Digests {
+		if d == running.RepoDigest {
+			return DigestMatch
+		}
+	}
+	return DigestMismatch
+}
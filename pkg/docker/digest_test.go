@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+func TestShortDigest(t *testing.T) {
+	if got := ShortDigest("sha256:0123456789abcdef0123"); got != "0123456789ab" {
+		t.Errorf("ShortDigest() = %q", got)
+	}
+	if got := ShortDigest("abc"); got != "abc" {
+		t.Errorf("ShortDigest(short) = %q", got)
+	}
+}
+
+func TestImageRepo(t *testing.T) {
+	tests := map[string]string{
+		"registry.io/app:v1":         "registry.io/app",
+		"localhost:5000/app:v1":      "localhost:5000/app",
+		"localhost:5000/app":         "localhost:5000/app",
+		"app@sha256:abc":             "app",
+		"registry.io/app:v1@sha256:": "registry.io/app",
+	}
+	for in, want := range tests {
+		if got := imageRepo(in); got != want {
+			t.Errorf("imageRepo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRepoDigestsCommand(t *testing.T) {
+	got := repoDigestsCommand(&ssh.Client{}, []string{"sha256:aa", "sha256:bb"})
+	want := `docker image inspect --format '{{.Id}} {{join .RepoDigests " "}}' sha256:aa sha256:bb`
+	if got != want {
+		t.Errorf("repoDigestsCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestRunningImages(t *testing.T) {
+	apps := []App{{
+		Service: "app",
+		Containers: []Container{
+			{ID: "c1", Image: "registry.io/app:v2"},
+			{ID: "c2", Image: "registry.io/app:v1"},
+		},
+		Accessories: []Accessory{{Name: "db", Containers: []Container{{ID: "c3", Image: "postgres:16"}}}},
+	}}
+	ids := map[string]string{"c1": "sha256:img2", "c2": "sha256:img1", "c3": "sha256:pg"}
+	output := "sha256:img1 registry.io/app@sha256:d1\n" +
+		"sha256:img2 mirror.io/app@sha256:m2 registry.io/app@sha256:d2\n" +
+		"sha256:pg \n"
+	want := map[string]RunningImage{
+		"c1": {ID: "sha256:img2", RepoDigest: "sha256:d2"},
+		"c2": {ID: "sha256:img1", RepoDigest: "sha256:d1"},
+		"c3": {ID: "sha256:pg"},
+	}
+	if got := runningImages(apps, ids, parseRepoDigests(output)); !reflect.DeepEqual(got, want) {
+		t.Errorf("runningImages() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPickRepoDigestSingleEntry(t *testing.T) {
+	if got := pickRepoDigest([]string{"docker.io/library/app@sha256:d"}, "app:v1"); got != "sha256:d" {
+		t.Errorf("pickRepoDigest() = %q, want sha256:d", got)
+	}
+	if got := pickRepoDigest([]string{"a@sha256:1", "b@sha256:2"}, "c:v1"); got != "" {
+		t.Errorf("pickRepoDigest() = %q, want empty", got)
+	}
+}
+
+func TestParseManifestInspect(t *testing.T) {
+	single := `{
+  "Ref": "registry.io/app:v1",
+  "Descriptor": {"mediaType": "application/vnd.docker.distribution.manifest.v2+json", "digest": "sha256:m1", "size": 1}
+  ,"SchemaV2Manifest": {"schemaVersion": 2, "config": {"digest": "sha256:cfg1"}, "layers": []}
+}`
+	m, err := parseManifestInspect(single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RegistryManifest{Digests: []string{"sha256:m1"}, Configs: []string{"sha256:cfg1"}}); !reflect.DeepEqual(m, want) {
+		t.Errorf("single = %+v, want %+v", m, want)
+	}
+
+	list := `[
+  {"Ref": "registry.io/app:v1@sha256:a", "Descriptor": {"digest": "sha256:a"}, "OCIManifest": {"config": {"digest": "sha256:cfgA"}}},
+  {"Ref": "registry.io/app:v1@sha256:b", "Descriptor": {"digest": "sha256:b"}, "OCIManifest": {"config": {"digest": "sha256:cfgB"}}}
+]`
+	m, err = parseManifestInspect(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (RegistryManifest{Digests: []string{"sha256:a", "sha256:b"}, Configs: []string{"sha256:cfgA", "sha256:cfgB"}}); !reflect.DeepEqual(m, want) {
+		t.Errorf("list = %+v, want %+v", m, want)
+	}
+
+	if _, err := parseManifestInspect("no such manifest"); err == nil {
+		t.Error("parseManifestInspect(garbage) = nil error")
+	}
+}
+
+func TestCompareDigest(t *testing.T) {
+	m := RegistryManifest{Digests: []string{"sha256:a", "sha256:b"}, Configs: []string{"sha256:cfgA", "sha256:cfgB"}}
+	tests := []struct {
+		name    string
+		running RunningImage
+		m       RegistryManifest
+		want    DigestVerdict
+	}{
+		{"config matches", RunningImage{ID: "sha256:cfgB"}, m, DigestMatch},
+		{"repo digest matches", RunningImage{ID: "sha256:other", RepoDigest: "sha256:a"}, m, DigestMatch},
+		{"moved tag", RunningImage{ID: "sha256:old", RepoDigest: "sha256:old-m"}, m, DigestMismatch},
+		{"nothing running", RunningImage{}, m, DigestUnknown},
+		{"empty manifest", RunningImage{ID: "sha256:cfgA"}, RegistryManifest{}, DigestUnknown},
+	}
+	for _, tt := range tests {
+		if got := CompareDigest(tt.running, tt.m); got != tt.want {
+			t.Errorf("%s: CompareDigest() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/shuvro/lazykamal/pkg/ssh"
 )
@@ -18,6 +20,9 @@ type Container struct {
 	State   string
 	Labels  map[string]string
 	Created string
+	// Grouping explains where discovery put the container, e.g.
+	// `accessory "db" of "myapp": no role label; ...`.
+	Grouping string
 }
 
 // App represents a Kamal-deployed application
@@ -35,11 +40,14 @@ type Accessory struct {
 	Containers []Container
 }
 
+// containerListFormat is the docker ps --format template parsed by parseContainers.
+const containerListFormat = `'{"ID":"{{.ID}}","Name":"{{.Names}}","Image":"{{.Image}}","Status":"{{.Status}}","State":"{{.State}}","Labels":"{{.Labels}}","Created":"{{.CreatedAt}}"}'`
+
 // DiscoverApps discovers all Kamal-deployed apps on a remote server
 func DiscoverApps(client *ssh.Client) ([]App, error) {
 	// Get all containers with their labels in JSON format
 	// This is a single SSH command that gets everything we need
-	cmd := `docker ps -a --format '{"ID":"{{.ID}}","Name":"{{.Names}}","Image":"{{.Image}}","Status":"{{.Status}}","State":"{{.State}}","Labels":"{{.Labels}}","Created":"{{.CreatedAt}}"}'`
+	cmd := Bin(client) + " ps -a --format " + containerListFormat
 
 	output, err := client.Run(cmd)
 	if err != nil {
@@ -153,7 +161,8 @@ func groupContainers(containers []Container) []App {
 		}
 
 		// Smart detection: find if this service is an accessory of another
-		baseApp, accessoryType := detectBaseApp(service, allServices)
+		baseApp, role, reason := classifyContainer(c, allServices)
+		c.Grouping = reason
 
 		// Initialize app map
 		if appMap[baseApp] == nil {
@@ -168,12 +177,6 @@ func groupContainers(containers []Container) []App {
 
 		app := appMap[baseApp][destination]
 
-		// Use role label if present, otherwise use detected accessory type
-		role := c.Labels["role"]
-		if role == "" {
-			role = accessoryType
-		}
-
 		// Categorize by role
 		if role == "" || role == "web" {
 			app.Containers = append(app.Containers, c)
@@ -216,7 +219,9 @@ func groupContainers(containers []Container) []App {
 //   - "repoengine-postgres" with "repoengine" existing -> accessory
 //   - "repoengine-custom-worker" with "repoengine" existing -> accessory
 //   - "my-cool-app" with no "my-cool" or "my" existing -> main app
-func detectBaseApp(service string, allServices map[string]bool) (baseApp string, accessoryType string) {
+//
+// reason says which of these applied, for the container label view.
+func detectBaseApp(service string, allServices map[string]bool) (baseApp, accessoryType, reason string) {
 	// If this service name contains a hyphen, check if a parent exists
 	// We try progressively shorter prefixes
 	// e.g., "myapp-foo-bar" -> try "myapp-foo", then "myapp"
@@ -231,18 +236,36 @@ func detectBaseApp(service string, allServices map[string]bool) (baseApp string,
 		if allServices[potentialBase] {
 			// Found a parent! This service is an accessory
 			accessory := strings.Join(parts[i:], "-")
-			return potentialBase, accessory
+			return potentialBase, accessory, fmt.Sprintf("service %q extends deployed service %q", service, potentialBase)
 		}
 	}
 
 	// No parent found - this is a main app
-	return service, ""
+	return service, "", fmt.Sprintf("no other deployed service is a prefix of %q", service)
+}
+
+// classifyContainer returns the app a container is grouped under, its role
+// ("" or "web" for app containers, the accessory name otherwise) and why.
+// The role label wins over the accessory name detectBaseApp derives.
+func classifyContainer(c Container, allServices map[string]bool) (baseApp, role, reason string) {
+	baseApp, accessoryType, why := detectBaseApp(c.Labels["service"], allServices)
+	role = c.Labels["role"]
+	source := fmt.Sprintf("role label %q", role)
+	if role == "" {
+		role = accessoryType
+		source = "no role label"
+	}
+	kind := "app container"
+	if role != "" && role != "web" {
+		kind = fmt.Sprintf("accessory %q", role)
+	}
+	return baseApp, role, fmt.Sprintf("%s of %q: %s; %s", kind, baseApp, source, why)
 }
 
 // checkProxyStatus checks if kamal-proxy is running for the app
 func checkProxyStatus(client *ssh.Client) string {
 	// Check if kamal-proxy container is running (global, not per-app)
-	cmd := `docker ps --filter "name=kamal-proxy" --format "{{.Status}}" | head -1`
+	cmd := Bin(client) + ` ps --filter "name=kamal-proxy" --format "{{.Status}}" | head -1`
 	output, err := client.Run(cmd)
 	if err != nil {
 		return "unknown"
@@ -260,45 +283,76 @@ func checkProxyStatus(client *ssh.Client) string {
 	return output
 }
 
-// GetContainerLogs gets logs from a container
-func GetContainerLogs(client *ssh.Client, containerID string, lines int, follow bool) (string, error) {
-	cmd := fmt.Sprintf("docker logs --tail %d", lines)
+// Bin is the docker executable for commands sent through client: plain
+// `docker`, or `sudo -n docker` when the session was switched to sudo. A nil
+// client means plain docker.
+func Bin(client *ssh.Client) string {
+	if client != nil && client.Sudo {
+		return "sudo -n docker"
+	}
+	return "docker"
+}
+
+// dockerCommand builds a docker command line with every argument quoted for
+// the remote shell.
+func dockerCommand(client *ssh.Client, args ...string) string {
+	return Bin(client) + " " + ssh.QuoteArgs(args...)
+}
+
+// containerLogsCommand builds the docker logs command used by GetContainerLogs.
+func containerLogsCommand(client *ssh.Client, containerID string, lines int, follow bool) string {
+	args := []string{"logs", "--tail", strconv.Itoa(lines)}
 	if follow {
-		cmd += " -f"
+		args = append(args, "-f")
 	}
-	cmd += " " + containerID
+	return dockerCommand(client, append(args, containerID)...)
+}
 
-	return client.Run(cmd)
+// GetContainerLogs gets logs from a container
+func GetContainerLogs(client *ssh.Client, containerID string, lines int, follow bool) (string, error) {
+	return client.Run(containerLogsCommand(client, containerID, lines, follow))
 }
 
 // StreamContainerLogs streams logs from a container
 func StreamContainerLogs(client *ssh.Client, containerID string, onLine func(string), stopCh <-chan struct{}) error {
-	cmd := fmt.Sprintf("docker logs -f --tail 100 %s 2>&1", containerID)
+	cmd := dockerCommand(client, "logs", "-f", "--tail", "100", containerID) + " 2>&1"
 	return client.RunStream(cmd, onLine, stopCh)
 }
 
 // RestartContainer restarts a container
 func RestartContainer(client *ssh.Client, containerID string) error {
-	_, err := client.Run(fmt.Sprintf("docker restart %s", containerID))
+	_, err := client.Run(dockerCommand(client, "restart", containerID))
 	return err
 }
 
 // StopContainer stops a container
 func StopContainer(client *ssh.Client, containerID string) error {
-	_, err := client.Run(fmt.Sprintf("docker stop %s", containerID))
+	_, err := client.Run(dockerCommand(client, "stop", containerID))
 	return err
 }
 
 // StartContainer starts a container
 func StartContainer(client *ssh.Client, containerID string) error {
-	_, err := client.Run(fmt.Sprintf("docker start %s", containerID))
+	_, err := client.Run(dockerCommand(client, "start", containerID))
+	return err
+}
+
+// RemoveContainer removes a stopped container
+func RemoveContainer(client *ssh.Client, containerID string) error {
+	_, err := client.Run(dockerCommand(client, "rm", containerID))
+	return err
+}
+
+// PullImage pulls an image on the server. Pulls can be slow, so allow 5 minutes.
+func PullImage(client *ssh.Client, image string) error {
+	_, err := client.RunWithTimeout(dockerCommand(client, "pull", image), 5*time.Minute)
 	return err
 }
 
-// ExecInContainer executes a command in a container
-func ExecInContainer(client *ssh.Client, containerID string, command string) (string, error) {
-	cmd := fmt.Sprintf("docker exec %s %s", containerID, command)
-	return client.Run(cmd)
+// ExecInContainer executes a command in a container. Each element of command
+// is passed as one literal argument.
+func ExecInContainer(client *ssh.Client, containerID string, command ...string) (string, error) {
+	return client.Run(dockerCommand(client, append([]string{"exec", containerID}, command...)...))
 }
 
 // GetAppVersion gets the current version/image tag of an app
@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// shellArgs runs line through sh with docker replaced by an argument printer
+// and returns the arguments docker would have received.
+func shellArgs(t *testing.T, line string) []string {
+	t.Helper()
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	line = strings.Replace(line, "docker ", `printf '%s\0' `, 1)
+	out, err := exec.Command(sh, "-c", line).Output()
+	if err != nil {
+		t.Fatalf("sh -c %q failed: %v", line, err)
+	}
+	return strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+}
+
+func TestDockerCommandInjection(t *testing.T) {
+	names := []string{
+		"foo; rm -rf /",
+		"foo && touch /tmp/pwned",
+		"$(reboot)",
+		"`reboot`",
+		"web's \"main\"",
+		"a\nb",
+	}
+	for _, name := range names {
+		args := shellArgs(t, dockerCommand(nil, "stop", name))
+		if len(args) != 2 || args[0] != "stop" || args[1] != name {
+			t.Errorf("dockerCommand(stop, %q) reached docker as %q", name, args)
+		}
+	}
+}
+
+func TestContainerLogsCommand(t *testing.T) {
+	if got, want := containerLogsCommand(nil, "abc123", 50, false), "docker logs --tail 50 abc123"; got != want {
+		t.Errorf("containerLogsCommand() = %q, want %q", got, want)
+	}
+	if got, want := containerLogsCommand(nil, "abc123", 10, true), "docker logs --tail 10 -f abc123"; got != want {
+		t.Errorf("containerLogsCommand(follow) = %q, want %q", got, want)
+	}
+	args := shellArgs(t, containerLogsCommand(nil, "x; id", 5, false))
+	if args[len(args)-1] != "x; id" {
+		t.Errorf("containerLogsCommand() passed %q, want literal container name", args)
+	}
+}
+
+func TestRefreshCommandInjection(t *testing.T) {
+	line := refreshCommand(nil, []string{"my'app; rm -rf /"})
+	line = strings.Replace(line, "--format "+containerListFormat, "", 1)
+	args := shellArgs(t, line)
+	want := []string{"ps", "-a", "--filter", "label=service=my'app; rm -rf /"}
+	if strings.Join(args, "|") != strings.Join(want, "|") {
+		t.Errorf("refreshCommand() reached docker as %q, want %q", args, want)
+	}
+}
+
+func TestGroupContainers(t *testing.T) {
+	apps := groupContainers([]Container{
+		labeled("w", "running", "myapp", "", "web"),
+		labeled("p", "running", "myapp-postgres", "", ""),
+		labeled("j", "running", "myapp", "", "job"),
+		labeled("c", "running", "my-cool-app", "staging", ""),
+		{ID: "n", Labels: map[string]string{"com.example": "x"}},
+	})
+	if len(apps) != 2 {
+		t.Fatalf("groupContainers() = %d apps, want 2", len(apps))
+	}
+	grouping := map[string]string{}
+	for _, app := range apps {
+		for _, c := range app.Containers {
+			grouping[c.ID] = app.Service + "/" + app.Destination + " app: " + c.Grouping
+		}
+		for _, acc := range app.Accessories {
+			for _, c := range acc.Containers {
+				grouping[c.ID] = app.Service + "/" + app.Destination + " " + acc.Name + ": " + c.Grouping
+			}
+		}
+	}
+	want := map[string]string{
+		"w": `myapp/production app: app container of "myapp": role label "web"; no other deployed service is a prefix of "myapp"`,
+		"p": `myapp/production postgres: accessory "postgres" of "myapp": no role label; service "myapp-postgres" extends deployed service "myapp"`,
+		"j": `myapp/production job: accessory "job" of "myapp": role label "job"; no other deployed service is a prefix of "myapp"`,
+		"c": `my-cool-app/staging app: app container of "my-cool-app": no role label; no other deployed service is a prefix of "my-cool-app"`,
+	}
+	for id, w := range want {
+		if grouping[id] != w {
+			t.Errorf("container %s = %s\nwant %s", id, grouping[id], w)
+		}
+	}
+	if _, ok := grouping["n"]; ok {
+		t.Error("container without a service label was grouped")
+	}
+}
+
+func TestDetectBaseApp(t *testing.T) {
+	services := map[string]bool{"myapp": true, "myapp-foo": true}
+	tests := []struct{ service, base, accessory, reason string }{
+		{"myapp-foo-bar", "myapp-foo", "bar", `service "myapp-foo-bar" extends deployed service "myapp-foo"`},
+		{"myapp-custom-worker", "myapp", "custom-worker", `service "myapp-custom-worker" extends deployed service "myapp"`},
+		{"other-app", "other-app", "", `no other deployed service is a prefix of "other-app"`},
+	}
+	for _, tt := range tests {
+		base, accessory, reason := detectBaseApp(tt.service, services)
+		if base != tt.base || accessory != tt.accessory || reason != tt.reason {
+			t.Errorf("detectBaseApp(%q) = %q, %q, %q; want %q, %q, %q", tt.service, base, accessory, reason, tt.base, tt.accessory, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package docker
+
+// HealthState summarizes how many of a group's containers are running.
+type HealthState int
+
+const (
+	HealthOK       HealthState = iota // all containers running
+	HealthDegraded                    // some containers running
+	HealthDown                        // no containers running
+)
+
+// AppHealth is the combined health of an app's web containers and accessories.
+type AppHealth struct {
+	Web         HealthState
+	Accessories HealthState // worst state across all accessories; HealthOK if there are none
+	Unhealthy   []string    // names of accessories that are not fully running
+}
+
+// groupHealth maps running/total counts to a HealthState. An empty group is
+// Down: an accessory with no containers is not serving anything.
+func groupHealth(running, total int) HealthState {
+	switch {
+	case total == 0 || running == 0:
+		return HealthDown
+	case running < total:
+		return HealthDegraded
+	default:
+		return HealthOK
+	}
+}
+
+// ComputeHealth derives web and accessory health from container states.
+func ComputeHealth(app App) AppHealth {
+	h := AppHealth{
+		Web:         groupHealth(CountRunning(app.Containers), len(app.Containers)),
+		Accessories: HealthOK,
+	}
+	for _, acc := range app.Accessories {
+		state := groupHealth(CountRunning(acc.Containers), len(acc.Containers))
+		if state == HealthOK {
+			continue
+		}
+		h.Unhealthy = append(h.Unhealthy, acc.Name)
+		if state > h.Accessories {
+			h.Accessories = state
+		}
+	}
+	return h
+}
+
+// Worst returns the worse of the web and accessory states.
+func (h AppHealth) Worst() HealthState {
+	if h.Accessories > h.Web {
+		return h.Accessories
+	}
+	return h.Web
+}
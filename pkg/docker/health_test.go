@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func containers(states ...string) []Container {
+	var cs []Container
+	for _, s := range states {
+		cs = append(cs, Container{State: s})
+	}
+	return cs
+}
+
+func TestComputeHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		app           App
+		wantWeb       HealthState
+		wantAcc       HealthState
+		wantUnhealthy []string
+		wantWorst     HealthState
+	}{
+		{
+			name:      "all running, no accessories",
+			app:       App{Containers: containers("running", "running")},
+			wantWeb:   HealthOK,
+			wantAcc:   HealthOK,
+			wantWorst: HealthOK,
+		},
+		{
+			name: "web ok, dead postgres",
+			app: App{
+				Containers: containers("running"),
+				Accessories: []Accessory{
+					{Name: "postgres", Containers: containers("exited")},
+					{Name: "redis", Containers: containers("running")},
+				},
+			},
+			wantWeb:       HealthOK,
+			wantAcc:       HealthDown,
+			wantUnhealthy: []string{"postgres"},
+			wantWorst:     HealthDown,
+		},
+		{
+			name: "zero-container accessory is down",
+			app: App{
+				Containers:  containers("running"),
+				Accessories: []Accessory{{Name: "redis"}},
+			},
+			wantWeb:       HealthOK,
+			wantAcc:       HealthDown,
+			wantUnhealthy: []string{"redis"},
+			wantWorst:     HealthDown,
+		},
+		{
+			name: "partially running accessory is degraded",
+			app: App{
+				Containers:  containers("running"),
+				Accessories: []Accessory{{Name: "worker", Containers: containers("running", "exited")}},
+			},
+			wantWeb:       HealthOK,
+			wantAcc:       HealthDegraded,
+			wantUnhealthy: []string{"worker"},
+			wantWorst:     HealthDegraded,
+		},
+		{
+			name: "web degraded, accessories fine",
+			app: App{
+				Containers:  containers("running", "exited"),
+				Accessories: []Accessory{{Name: "redis", Containers: containers("running")}},
+			},
+			wantWeb:   HealthDegraded,
+			wantAcc:   HealthOK,
+			wantWorst: HealthDegraded,
+		},
+		{
+			name:      "no web containers",
+			app:       App{},
+			wantWeb:   HealthDown,
+			wantAcc:   HealthOK,
+			wantWorst: HealthDown,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := ComputeHealth(tt.app)
+			if h.Web != tt.wantWeb || h.Accessories != tt.wantAcc {
+				t.Errorf("ComputeHealth() web=%d acc=%d, want web=%d acc=%d", h.Web, h.Accessories, tt.wantWeb, tt.wantAcc)
+			}
+			if !reflect.DeepEqual(h.Unhealthy, tt.wantUnhealthy) {
+				t.Errorf("Unhealthy = %v, want %v", h.Unhealthy, tt.wantUnhealthy)
+			}
+			if h.Worst() != tt.wantWorst {
+				t.Errorf("Worst() = %d, want %d", h.Worst(), tt.wantWorst)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package docker
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// Inventory is a snapshot of the Kamal apps discovered on one host, for
+// export.
+type Inventory struct {
+	LazykamalVersion string         `json:"lazykamal_version"`
+	ExportedAt       time.Time      `json:"exported_at"`
+	Host             InventoryHost  `json:"host"`
+	Apps             []InventoryApp `json:"apps"`
+}
+
+// InventoryHost is the SSH target the inventory was taken from.
+type InventoryHost struct {
+	Host string `json:"host"`
+	User string `json:"user,omitempty"`
+	Port string `json:"port"`
+}
+
+// InventoryApp is one service/destination with its containers.
+type InventoryApp struct {
+	Service     string               `json:"service"`
+	Destination string               `json:"destination,omitempty"`
+	ProxyStatus string               `json:"proxy_status"`
+	Containers  []InventoryContainer `json:"containers"`
+	Accessories []InventoryAccessory `json:"accessories,omitempty"`
+}
+
+// InventoryAccessory is an accessory of an app and its containers.
+type InventoryAccessory struct {
+	Name       string               `json:"name"`
+	Containers []InventoryContainer `json:"containers"`
+}
+
+// InventoryContainer is one container as listed by docker ps, plus the ID
+// (sha256 digest) of the image it runs.
+type InventoryContainer struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Role        string `json:"role,omitempty"`
+	Image       string `json:"image"`
+	Tag         string `json:"tag"`
+	ImageDigest string `json:"image_digest,omitempty"`
+	State       string `json:"state"`
+	Status      string `json:"status"`
+	Created     string `json:"created"`
+}
+
+// NewInventory builds the export from the apps already discovered. digests
+// maps container ID to image digest (see ImageDigests); missing entries are
+// left empty.
+func NewInventory(apps []App, client *ssh.Client, version string, now time.Time, digests map[string]string) Inventory {
+	inv := Inventory{
+		LazykamalVersion: version,
+		ExportedAt:       now.UTC(),
+		Host:             InventoryHost{Host: client.Host, User: client.User, Port: client.Port},
+		Apps:             []InventoryApp{},
+	}
+	for _, app := range apps {
+		ia := InventoryApp{
+			Service:     app.Service,
+			Destination: app.Destination,
+			ProxyStatus: app.ProxyStatus,
+			Containers:  inventoryContainers(app.Containers, digests),
+		}
+		for _, acc := range app.Accessories {
+			ia.Accessories = append(ia.Accessories, InventoryAccessory{Name: acc.Name, Containers: inventoryContainers(acc.Containers, digests)})
+		}
+		inv.Apps = append(inv.Apps, ia)
+	}
+	return inv
+}
+
+func inventoryContainers(containers []Container, digests map[string]string) []InventoryContainer {
+	out := []InventoryContainer{}
+	for _, c := range containers {
+		out = append(out, InventoryContainer{
+			ID:          c.ID,
+			Name:        c.Name,
+			Role:        c.Labels["role"],
+			Image:       c.Image,
+			Tag:         ImageTag(c.Image),
+			ImageDigest: digests[c.ID],
+			State:       c.State,
+			Status:      c.Status,
+			Created:     c.Created,
+		})
+	}
+	return out
+}
+
+// WriteJSON writes the inventory as indented JSON.
+func (inv Inventory) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(inv)
+}
+
+// inventoryCSVHeader is the CSV column order: one row per container, with
+// the export metadata repeated so every row stands alone.
+var inventoryCSVHeader = []string{
+	"lazykamal_version", "exported_at", "host",
+	"service", "destination", "proxy_status", "accessory",
+	"container_id", "container_name", "role", "image", "tag", "image_digest", "state", "status", "created",
+}
+
+// WriteCSV writes one row per container; accessory containers carry the
+// accessory name.
+func (inv Inventory) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(inventoryCSVHeader); err != nil {
+		return err
+	}
+	exported := inv.ExportedAt.Format(time.RFC3339)
+	row := func(app InventoryApp, accessory string, c InventoryContainer) []string {
+		return []string{
+			inv.LazykamalVersion, exported, inv.Host.Host,
+			app.Service, app.Destination, app.ProxyStatus, accessory,
+			c.ID, c.Name, c.Role, c.Image, c.Tag, c.ImageDigest, c.State, c.Status, c.Created,
+		}
+	}
+	for _, app := range inv.Apps {
+		for _, c := range app.Containers {
+			if err := cw.Write(row(app, "", c)); err != nil {
+				return err
+			}
+		}
+		for _, acc := range app.Accessories {
+			for _, c := range acc.Containers {
+				if err := cw.Write(row(app, acc.Name, c)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// InventoryPath is the default export file name:
+// lazykamal-inventory-<host>-<timestamp>.<ext> in the current directory.
+func InventoryPath(host, ext string, now time.Time) string {
+	name := unsafeFileChars.ReplaceAllString(host, "_")
+	return "lazykamal-inventory-" + name + "-" + now.Format("20060102-150405") + "." + ext
+}
+
+// inventoryContainerIDs lists every container ID in apps, sorted.
+func inventoryContainerIDs(apps []App) []string {
+	var ids []string
+	for _, app := range apps {
+		for _, c := range app.Containers {
+			ids = append(ids, c.ID)
+		}
+		for _, acc := range app.Accessories {
+			for _, c := range acc.Containers {
+				ids = append(ids, c.ID)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// imageDigestsCommand inspects every container in one docker call, printing
+// "<container id> <image digest>" per line.
+func imageDigestsCommand(client *ssh.Client, ids []string) string {
+	args := append([]string{"inspect", "--type", "container", "--format", "{{.Id}} {{.Image}}"}, ids...)
+	return dockerCommand(client, args...)
+}
+
+// parseImageDigests maps the short IDs in ids to the image digest from
+// imageDigestsCommand output, which prints full container IDs.
+func parseImageDigests(output string, ids []string) map[string]string {
+	digests := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		full, digest, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		for _, id := range ids {
+			if id != "" && strings.HasPrefix(full, id) {
+				digests[id] = digest
+			}
+		}
+	}
+	return digests
+}
+
+// ImageDigests fetches the image digest of every container in apps with a
+// single docker inspect.
+func ImageDigests(client *ssh.Client, apps []App) (map[string]string, error) {
+	ids := inventoryContainerIDs(apps)
+	if len(ids) == 0 {
+		return map[string]string{}, nil
+	}
+	output, err := client.Run(imageDigestsCommand(client, ids))
+	if err != nil {
+		return nil, err
+	}
+	return parseImageDigests(output, ids), nil
+}
@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+func testInventory() Inventory {
+	apps := []App{{
+		Service:     "myapp",
+		Destination: "production",
+		ProxyStatus: "running",
+		Containers: []Container{{
+			ID: "abc123", Name: "myapp-web-production-v1", Image: "registry.io/myapp:v1",
+			State: "running", Status: "Up 2 hours", Created: "2024-05-01 10:00:00 +0000 UTC",
+			Labels: map[string]string{"role": "web"},
+		}},
+		Accessories: []Accessory{{
+			Name: "db",
+			Containers: []Container{{
+				ID: "def456", Name: "myapp-db", Image: "postgres:16", State: "running", Status: "Up 3 days",
+				Labels: map[string]string{},
+			}},
+		}},
+	}}
+	client := &ssh.Client{Host: "10.0.1.5", User: "deploy", Port: "22"}
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return NewInventory(apps, client, "1.2.3", now, map[string]string{"abc123": "sha256:feed"})
+}
+
+func TestInventoryJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testInventory().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got Inventory
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(got, testInventory()) {
+		t.Errorf("JSON round trip = %+v, want %+v", got, testInventory())
+	}
+	for _, want := range []string{`"lazykamal_version": "1.2.3"`, `"exported_at": "2024-05-01T12:00:00Z"`, `"tag": "v1"`, `"image_digest": "sha256:feed"`, `"role": "web"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("JSON missing %s:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestInventoryCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testInventory().WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("CSV has %d rows, want header + 2: %v", len(rows), rows)
+	}
+	if !reflect.DeepEqual(rows[0], inventoryCSVHeader) {
+		t.Errorf("header = %v", rows[0])
+	}
+	want := []string{"1.2.3", "2024-05-01T12:00:00Z", "10.0.1.5", "myapp", "production", "running", "",
+		"abc123", "myapp-web-production-v1", "web", "registry.io/myapp:v1", "v1", "sha256:feed", "running", "Up 2 hours", "2024-05-01 10:00:00 +0000 UTC"}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("app row = %v\nwant      %v", rows[1], want)
+	}
+	if rows[2][6] != "db" || rows[2][12] != "" {
+		t.Errorf("accessory row = %v", rows[2])
+	}
+}
+
+func TestImageDigests(t *testing.T) {
+	ids := []string{"abc123", "def456"}
+	if got := shellArgs(t, imageDigestsCommand(nil, ids)); !reflect.DeepEqual(got, []string{"inspect", "--type", "container", "--format", "{{.Id}} {{.Image}}", "abc123", "def456"}) {
+		t.Errorf("imageDigestsCommand() args = %q", got)
+	}
+	output := "abc123ffffffff sha256:1111\ndef456eeeeeeee sha256:2222\n"
+	want := map[string]string{"abc123": "sha256:1111", "def456": "sha256:2222"}
+	if got := parseImageDigests(output, ids); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseImageDigests() = %v, want %v", got, want)
+	}
+}
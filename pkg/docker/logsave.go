@@ -0,0 +1,98 @@
+package docker
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// LogSaveDir is where saved container logs are written, relative to the
+// current directory.
+const LogSaveDir = "lazykamal-logs"
+
+// progressEvery is how many bytes pass between progress callbacks.
+const progressEvery = 256 * 1024
+
+// ProgressWriter counts bytes written through it and reports the running
+// total to OnProgress every Every bytes.
+type ProgressWriter struct {
+	W          io.Writer
+	Every      int64
+	OnProgress func(total int64)
+	n          int64
+	next       int64
+}
+
+func (p *ProgressWriter) Write(b []byte) (int, error) {
+	n, err := p.W.Write(b)
+	p.n += int64(n)
+	if p.OnProgress != nil && p.n >= p.next {
+		p.OnProgress(p.n)
+		p.next = p.n + p.Every
+	}
+	return n, err
+}
+
+// N returns the number of bytes written so far.
+func (p *ProgressWriter) N() int64 { return p.n }
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SavedLogPath returns dir/<container>-<timestamp>.log with the container
+// name reduced to file-safe characters.
+func SavedLogPath(dir, container string, now time.Time) string {
+	name := unsafeFileChars.ReplaceAllString(container, "_")
+	if name == "" {
+		name = "container"
+	}
+	return filepath.Join(dir, name+"-"+now.Format("20060102-150405")+".log")
+}
+
+// saveStream creates path and lets stream write into it through a
+// ProgressWriter. Data goes straight to disk; nothing is buffered in memory.
+// A partial file is kept when stream fails after writing something.
+func saveStream(path string, stream func(io.Writer) error, onProgress func(int64)) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return 0, err
+	}
+	pw := &ProgressWriter{W: f, Every: progressEvery, OnProgress: onProgress}
+	streamErr := stream(pw)
+	closeErr := f.Close()
+	if streamErr == nil {
+		streamErr = closeErr
+	}
+	if streamErr != nil && pw.N() == 0 {
+		os.Remove(path)
+	}
+	return pw.N(), streamErr
+}
+
+// containerLogsToFileCommand builds the docker logs command for SaveContainerLogs.
+// lines <= 0 fetches the full log. stderr is merged so the file matches what
+// `docker logs` prints.
+func containerLogsToFileCommand(client *ssh.Client, containerID string, lines int) string {
+	args := []string{"logs"}
+	if lines > 0 {
+		args = append(args, "--tail", strconv.Itoa(lines))
+	}
+	return dockerCommand(client, append(args, containerID)...) + " 2>&1"
+}
+
+// SaveContainerLogs streams a container's logs over SSH into
+// dir/<name>-<timestamp>.log and returns the path and bytes written.
+func SaveContainerLogs(client *ssh.Client, containerID, name string, lines int, dir string, onProgress func(int64), stopCh <-chan struct{}) (string, int64, error) {
+	path := SavedLogPath(dir, name, time.Now())
+	n, err := saveStream(path, func(w io.Writer) error {
+		return client.RunToWriter(containerLogsToFileCommand(client, containerID, lines), w, stopCh)
+	}, onProgress)
+	return path, n, err
+}
@@ -0,0 +1,122 @@
+package docker
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fakeLogStream produces size bytes of log-like lines without holding them.
+type fakeLogStream struct {
+	size, off int64
+}
+
+func (f *fakeLogStream) Read(p []byte) (int, error) {
+	if f.off >= f.size {
+		return 0, io.EOF
+	}
+	line := []byte("2024-05-01T10:00:00Z web: GET /up 200 1ms\n")
+	n := 0
+	for n < len(p) && f.off < f.size {
+		p[n] = line[f.off%int64(len(line))]
+		n++
+		f.off++
+	}
+	return n, nil
+}
+
+func TestSaveStreamMultiMegabyte(t *testing.T) {
+	const size = 8<<20 + 123 // 8 MiB and a partial chunk
+	path := filepath.Join(t.TempDir(), LogSaveDir, "web-1.log")
+
+	var progress []int64
+	n, err := saveStream(path, func(w io.Writer) error {
+		_, err := io.Copy(w, &fakeLogStream{size: size})
+		return err
+	}, func(total int64) { progress = append(progress, total) })
+	if err != nil {
+		t.Fatalf("saveStream() error: %v", err)
+	}
+	if n != size {
+		t.Errorf("saveStream() wrote %d bytes, want %d", n, size)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil || fi.Size() != size {
+		t.Fatalf("file size = %v, %v; want %d", fi, err, size)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, want 0600", fi.Mode().Perm())
+	}
+
+	want := sha256.New()
+	io.Copy(want, &fakeLogStream{size: size})
+	got := sha256.New()
+	f, _ := os.Open(path)
+	io.Copy(got, f)
+	f.Close()
+	if !reflect.DeepEqual(got.Sum(nil), want.Sum(nil)) {
+		t.Error("saved file content differs from the stream")
+	}
+
+	if len(progress) < size/progressEvery {
+		t.Errorf("got %d progress callbacks, want at least %d", len(progress), size/progressEvery)
+	}
+	for i := 1; i < len(progress); i++ {
+		if progress[i] <= progress[i-1] {
+			t.Fatalf("progress not increasing: %v", progress[i-1:i+1])
+		}
+	}
+}
+
+func TestSaveStreamFailure(t *testing.T) {
+	dir := t.TempDir()
+	boom := errors.New("connection reset")
+
+	empty := filepath.Join(dir, "empty.log")
+	if _, err := saveStream(empty, func(io.Writer) error { return boom }, nil); err != boom {
+		t.Errorf("saveStream() error = %v, want %v", err, boom)
+	}
+	if _, err := os.Stat(empty); !os.IsNotExist(err) {
+		t.Errorf("empty file kept after failure: %v", err)
+	}
+
+	partial := filepath.Join(dir, "partial.log")
+	n, err := saveStream(partial, func(w io.Writer) error {
+		w.Write([]byte("some lines\n"))
+		return boom
+	}, nil)
+	if err != boom || n != 11 {
+		t.Errorf("saveStream() = %d, %v; want 11, %v", n, err, boom)
+	}
+	if _, err := os.Stat(partial); err != nil {
+		t.Errorf("partial file removed: %v", err)
+	}
+
+	if _, err := saveStream(partial, func(io.Writer) error { return nil }, nil); err == nil {
+		t.Error("saveStream() over an existing file = nil error, want error")
+	}
+}
+
+func TestSavedLogPath(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 5, 0, time.UTC)
+	got := SavedLogPath("lazykamal-logs", "myapp-web-9f8e/../x y", now)
+	want := filepath.Join("lazykamal-logs", "myapp-web-9f8e_.._x_y-20240501-100005.log")
+	if got != want {
+		t.Errorf("SavedLogPath() = %q, want %q", got, want)
+	}
+}
+
+func TestContainerLogsToFileCommand(t *testing.T) {
+	if got := shellArgs(t, containerLogsToFileCommand(nil, "abc; reboot", 0)); !reflect.DeepEqual(got, []string{"logs", "abc; reboot"}) {
+		t.Errorf("full log args = %q", got)
+	}
+	if got := shellArgs(t, containerLogsToFileCommand(nil, "abc", 5000)); !reflect.DeepEqual(got, []string{"logs", "--tail", "5000", "abc"}) {
+		t.Errorf("tail args = %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// ContainerPort is a TCP port a container exposes and where an SSH tunnel
+// ending on its host reaches it.
+type ContainerPort struct {
+	Port     int    // port inside the container
+	HostIP   string // address docker published it on; "" when only exposed
+	HostPort int    // published port on the host; 0 when only exposed
+	// TargetHost and TargetPort are the address the tunnel connects to
+	// from the host: the published port, or the container's own address
+	// on its network when the port is not published.
+	TargetHost string
+	TargetPort int
+}
+
+// Published reports whether docker maps the port onto the host.
+func (p ContainerPort) Published() bool { return p.HostPort != 0 }
+
+// Target is TargetHost:TargetPort.
+func (p ContainerPort) Target() string {
+	return fmt.Sprintf("%s:%d", p.TargetHost, p.TargetPort)
+}
+
+// portsCommand prints a container's port map as JSON, then the addresses
+// it has on its networks.
+func portsCommand(client *ssh.Client, containerID string) string {
+	return dockerCommand(client, "inspect", "--format",
+		`{{json .NetworkSettings.Ports}}{{range .NetworkSettings.Networks}} {{.IPAddress}}{{end}}`, containerID)
+}
+
+// ContainerPorts lists the TCP ports of a container, lowest first. UDP
+// ports are left out: SSH only forwards TCP.
+func ContainerPorts(client *ssh.Client, containerID string) ([]ContainerPort, error) {
+	out, err := client.Run(portsCommand(client, containerID))
+	if err != nil {
+		return nil, err
+	}
+	return parsePorts(out)
+}
+
+// parsePorts reads portsCommand output.
+func parsePorts(output string) ([]ContainerPort, error) {
+	fields := strings.Fields(strings.TrimSpace(output))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("docker inspect printed nothing")
+	}
+	var bindings map[string][]struct {
+		HostIP   string `json:"HostIp"`
+		HostPort string `json:"HostPort"`
+	}
+	if err := json.Unmarshal([]byte(fields[0]), &bindings); err != nil {
+		return nil, fmt.Errorf("reading port map: %w", err)
+	}
+	containerIP := ""
+	for _, ip := range fields[1:] {
+		if ip != "" {
+			containerIP = ip
+			break
+		}
+	}
+
+	var ports []ContainerPort
+	for spec, binds := range bindings {
+		port, proto, _ := strings.Cut(spec, "/")
+		n, err := strconv.Atoi(port)
+		if err != nil || (proto != "" && proto != "tcp") {
+			continue
+		}
+		p := ContainerPort{Port: n, TargetHost: containerIP, TargetPort: n}
+		// Docker lists the IPv4 and IPv6 bindings of one port separately;
+		// the first is enough.
+		if len(binds) > 0 {
+			hostPort, err := strconv.Atoi(binds[0].HostPort)
+			if err == nil && hostPort > 0 {
+				p.HostIP, p.HostPort = binds[0].HostIP, hostPort
+				p.TargetHost, p.TargetPort = forwardHost(p.HostIP), hostPort
+			}
+		}
+		if p.TargetHost == "" {
+			continue
+		}
+		ports = append(ports, p)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i].Port < ports[j].Port })
+	return ports, nil
+}
+
+// forwardHost is where a tunnel on the host reaches a port published on
+// hostIP: loopback when it listens on every address.
+func forwardHost(hostIP string) string {
+	switch hostIP {
+	case "", "0.0.0.0", "::":
+		return "127.0.0.1"
+	}
+	return hostIP
+}
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+func TestPortsCommand(t *testing.T) {
+	got := portsCommand(&ssh.Client{}, "c1")
+	want := `docker inspect --format '{{json .NetworkSettings.Ports}}{{range .NetworkSettings.Networks}} {{.IPAddress}}{{end}}' c1`
+	if got != want {
+		t.Errorf("portsCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestParsePorts(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []ContainerPort
+	}{
+		{
+			name:   "published on loopback and all addresses",
+			output: `{"5432/tcp":[{"HostIp":"127.0.0.1","HostPort":"5432"}],"9187/tcp":[{"HostIp":"0.0.0.0","HostPort":"19187"},{"HostIp":"::","HostPort":"19187"}]} 172.18.0.3` + "\n",
+			want: []ContainerPort{
+				{Port: 5432, HostIP: "127.0.0.1", HostPort: 5432, TargetHost: "127.0.0.1", TargetPort: 5432},
+				{Port: 9187, HostIP: "0.0.0.0", HostPort: 19187, TargetHost: "127.0.0.1", TargetPort: 19187},
+			},
+		},
+		{
+			name:   "exposed only, udp skipped",
+			output: `{"3000/tcp":null,"8125/udp":null,"80/tcp":[]} 172.18.0.7`,
+			want: []ContainerPort{
+				{Port: 80, TargetHost: "172.18.0.7", TargetPort: 80},
+				{Port: 3000, TargetHost: "172.18.0.7", TargetPort: 3000},
+			},
+		},
+		{
+			name:   "published on a private address",
+			output: `{"6379/tcp":[{"HostIp":"10.0.1.5","HostPort":"6379"}]} 172.18.0.4`,
+			want:   []ContainerPort{{Port: 6379, HostIP: "10.0.1.5", HostPort: 6379, TargetHost: "10.0.1.5", TargetPort: 6379}},
+		},
+		{
+			name:   "exposed on a stopped container has no address",
+			output: `{"3000/tcp":null}`,
+			want:   nil,
+		},
+		{name: "no ports", output: `{}  172.18.0.2`, want: nil},
+		{name: "null ports", output: `null`, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePorts(tt.output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsePorts() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parsePorts(""); err == nil {
+		t.Error("parsePorts(\"\") succeeded")
+	}
+	if _, err := parsePorts("Error: No such object: c1"); err == nil {
+		t.Error("parsePorts(error text) succeeded")
+	}
+}
@@ -0,0 +1,240 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// ProxyImageRepo is the image repository kamal-proxy is published under.
+const ProxyImageRepo = "basecamp/kamal-proxy"
+
+// proxyTagsURL lists the most recent kamal-proxy tags on Docker Hub.
+const proxyTagsURL = "https://hub.docker.com/v2/repositories/" + ProxyImageRepo + "/tags?page_size=50&ordering=last_updated"
+
+// ProxyContainer holds the settings of a running kamal-proxy container that
+// must survive recreating it from a newer image.
+type ProxyContainer struct {
+	ID      string
+	Name    string
+	Image   string
+	Network string
+	Restart string
+	Ports   []string // docker --publish values, e.g. "80:80" or "127.0.0.1:443:443/udp"
+	Env     []string // KEY=VALUE
+	Volumes []string // docker --volume values
+	Cmd     []string
+}
+
+// InspectProxy reads the configuration of the kamal-proxy container.
+func InspectProxy(client *ssh.Client) (*ProxyContainer, error) {
+	output, err := client.Run(Bin(client) + " inspect --type container kamal-proxy")
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect kamal-proxy: %w", err)
+	}
+	return parseProxyInspect(output)
+}
+
+// parseProxyInspect parses `docker inspect` JSON output for a single container.
+func parseProxyInspect(output string) (*ProxyContainer, error) {
+	var items []struct {
+		ID     string `json:"Id"`
+		Name   string `json:"Name"`
+		Config struct {
+			Image string   `json:"Image"`
+			Env   []string `json:"Env"`
+			Cmd   []string `json:"Cmd"`
+		} `json:"Config"`
+		HostConfig struct {
+			Binds         []string `json:"Binds"`
+			NetworkMode   string   `json:"NetworkMode"`
+			RestartPolicy struct {
+				Name string `json:"Name"`
+			} `json:"RestartPolicy"`
+			PortBindings map[string][]struct {
+				HostIP   string `json:"HostIp"`
+				HostPort string `json:"HostPort"`
+			} `json:"PortBindings"`
+		} `json:"HostConfig"`
+	}
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse inspect output: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("kamal-proxy container not found")
+	}
+	it := items[0]
+
+	pc := &ProxyContainer{
+		ID:      it.ID,
+		Name:    strings.TrimPrefix(it.Name, "/"),
+		Image:   it.Config.Image,
+		Network: it.HostConfig.NetworkMode,
+		Restart: it.HostConfig.RestartPolicy.Name,
+		Volumes: it.HostConfig.Binds,
+		Cmd:     it.Config.Cmd,
+	}
+	if pc.Restart == "no" {
+		pc.Restart = ""
+	}
+	if pc.Network == "default" {
+		pc.Network = ""
+	}
+	for _, env := range it.Config.Env {
+		// PATH comes from the image itself; the new image sets its own.
+		if strings.HasPrefix(env, "PATH=") {
+			continue
+		}
+		pc.Env = append(pc.Env, env)
+	}
+	for containerPort, bindings := range it.HostConfig.PortBindings {
+		target := strings.TrimSuffix(containerPort, "/tcp")
+		for _, b := range bindings {
+			publish := b.HostPort + ":" + target
+			if b.HostIP != "" {
+				publish = b.HostIP + ":" + publish
+			}
+			pc.Ports = append(pc.Ports, publish)
+		}
+	}
+	sort.Strings(pc.Ports)
+	return pc, nil
+}
+
+// ProxyRecreateArgs builds the `docker run` argv that recreates the proxy
+// container from image while keeping its name, network, ports, env and volumes.
+func ProxyRecreateArgs(pc ProxyContainer, image string) []string {
+	name := pc.Name
+	if name == "" {
+		name = "kamal-proxy"
+	}
+	args := []string{"docker", "run", "--detach", "--name", name}
+	if pc.Network != "" {
+		args = append(args, "--network", pc.Network)
+	}
+	if pc.Restart != "" {
+		args = append(args, "--restart", pc.Restart)
+	}
+	for _, p := range pc.Ports {
+		args = append(args, "--publish", p)
+	}
+	for _, e := range pc.Env {
+		args = append(args, "--env", e)
+	}
+	for _, v := range pc.Volumes {
+		args = append(args, "--volume", v)
+	}
+	args = append(args, image)
+	return append(args, pc.Cmd...)
+}
+
+// ProxyRecreateCommand returns ProxyRecreateArgs as a shell-safe command
+// line, run through sudo when the client's session uses it.
+func ProxyRecreateCommand(client *ssh.Client, pc ProxyContainer, image string) string {
+	cmd := ssh.QuoteArgs(ProxyRecreateArgs(pc, image)...)
+	if client != nil && client.Sudo {
+		return "sudo -n " + cmd
+	}
+	return cmd
+}
+
+// ImageTag returns the tag part of an image reference ("repo:v1" -> "v1").
+func ImageTag(image string) string {
+	if idx := strings.LastIndex(image, ":"); idx > 0 && !strings.Contains(image[idx:], "/") {
+		return image[idx+1:]
+	}
+	return "latest"
+}
+
+// CompareVersions compares two "vMAJOR.MINOR.PATCH" tags. It returns -1, 0 or 1,
+// and ok=false if either tag is not a version (e.g. "latest").
+func CompareVersions(a, b string) (cmp int, ok bool) {
+	va, okA := parseVersion(a)
+	vb, okB := parseVersion(b)
+	if !okA || !okB {
+		return 0, false
+	}
+	for i := range va {
+		if va[i] < vb[i] {
+			return -1, true
+		}
+		if va[i] > vb[i] {
+			return 1, true
+		}
+	}
+	return 0, true
+}
+
+func parseVersion(tag string) ([3]int, bool) {
+	var v [3]int
+	tag = strings.TrimPrefix(tag, "v")
+	if tag == "" {
+		return v, false
+	}
+	parts := strings.Split(tag, ".")
+	if len(parts) > 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// latestVersionTag returns the highest version among tags, or "" if none parse.
+func latestVersionTag(tags []string) string {
+	best := ""
+	for _, t := range tags {
+		if _, ok := parseVersion(t); !ok {
+			continue
+		}
+		if best == "" {
+			best = t
+			continue
+		}
+		if c, _ := CompareVersions(t, best); c > 0 {
+			best = t
+		}
+	}
+	return best
+}
+
+// LatestProxyVersion asks Docker Hub for the newest published kamal-proxy tag.
+func LatestProxyVersion() (string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(proxyTagsURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to query registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to query registry: HTTP %d", resp.StatusCode)
+	}
+	var body struct {
+		Results []struct {
+			Name string `json:"name"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse registry response: %w", err)
+	}
+	tags := make([]string, 0, len(body.Results))
+	for _, r := range body.Results {
+		tags = append(tags, r.Name)
+	}
+	latest := latestVersionTag(tags)
+	if latest == "" {
+		return "", fmt.Errorf("no versioned kamal-proxy tags found")
+	}
+	return latest, nil
+}
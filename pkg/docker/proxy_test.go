@@ -0,0 +1,134 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+const proxyInspectFixture = `[
+  {
+    "Id": "4f1c2d3e",
+    "Name": "/kamal-proxy",
+    "Config": {
+      "Image": "basecamp/kamal-proxy:v0.8.0",
+      "Env": ["PATH=/usr/local/sbin:/usr/bin", "KAMAL_PROXY_TLS=1"],
+      "Cmd": ["kamal-proxy", "run"]
+    },
+    "HostConfig": {
+      "Binds": ["kamal-proxy-config:/home/kamal-proxy/.config/kamal-proxy"],
+      "NetworkMode": "kamal",
+      "RestartPolicy": {"Name": "unless-stopped"},
+      "PortBindings": {
+        "443/tcp": [{"HostIp": "", "HostPort": "443"}],
+        "80/tcp": [{"HostIp": "", "HostPort": "80"}],
+        "443/udp": [{"HostIp": "127.0.0.1", "HostPort": "8443"}]
+      }
+    }
+  }
+]`
+
+func TestParseProxyInspect(t *testing.T) {
+	pc, err := parseProxyInspect(proxyInspectFixture)
+	if err != nil {
+		t.Fatalf("parseProxyInspect() error = %v", err)
+	}
+	if pc.Name != "kamal-proxy" {
+		t.Errorf("Name = %q, want kamal-proxy", pc.Name)
+	}
+	if pc.Network != "kamal" || pc.Restart != "unless-stopped" {
+		t.Errorf("Network/Restart = %q/%q", pc.Network, pc.Restart)
+	}
+	wantPorts := []string{"127.0.0.1:8443:443/udp", "443:443", "80:80"}
+	if !reflect.DeepEqual(pc.Ports, wantPorts) {
+		t.Errorf("Ports = %v, want %v", pc.Ports, wantPorts)
+	}
+	if !reflect.DeepEqual(pc.Env, []string{"KAMAL_PROXY_TLS=1"}) {
+		t.Errorf("Env = %v, want PATH filtered out", pc.Env)
+	}
+}
+
+func TestParseProxyInspectEmpty(t *testing.T) {
+	if _, err := parseProxyInspect("[]"); err == nil {
+		t.Error("expected error for empty inspect output")
+	}
+	if _, err := parseProxyInspect("not json"); err == nil {
+		t.Error("expected error for invalid inspect output")
+	}
+}
+
+func TestProxyRecreateCommand(t *testing.T) {
+	pc := ProxyContainer{
+		Name:    "kamal-proxy",
+		Network: "kamal",
+		Restart: "unless-stopped",
+		Ports:   []string{"443:443", "80:80"},
+		Env:     []string{"GREETING=hello world"},
+		Volumes: []string{"kamal-proxy-config:/home/kamal-proxy/.config/kamal-proxy"},
+		Cmd:     []string{"kamal-proxy", "run"},
+	}
+	got := ProxyRecreateCommand(nil, pc, "basecamp/kamal-proxy:v0.9.0")
+	want := "docker run --detach --name kamal-proxy --network kamal --restart unless-stopped" +
+		" --publish 443:443 --publish 80:80 --env 'GREETING=hello world'" +
+		" --volume kamal-proxy-config:/home/kamal-proxy/.config/kamal-proxy" +
+		" basecamp/kamal-proxy:v0.9.0 kamal-proxy run"
+	if got != want {
+		t.Errorf("ProxyRecreateCommand() =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestProxyRecreateCommandDefaults(t *testing.T) {
+	got := ProxyRecreateCommand(nil, ProxyContainer{}, "basecamp/kamal-proxy:latest")
+	want := "docker run --detach --name kamal-proxy basecamp/kamal-proxy:latest"
+	if got != want {
+		t.Errorf("ProxyRecreateCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"basecamp/kamal-proxy:v0.8.0", "v0.8.0"},
+		{"basecamp/kamal-proxy", "latest"},
+		{"registry:5000/kamal-proxy", "latest"},
+		{"registry:5000/kamal-proxy:v1.2", "v1.2"},
+	}
+	for _, tt := range tests {
+		if got := ImageTag(tt.image); got != tt.want {
+			t.Errorf("ImageTag(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		want   int
+		wantOK bool
+	}{
+		{"v0.8.0", "v0.8.0", 0, true},
+		{"v0.8.0", "v0.9.0", -1, true},
+		{"v0.10.0", "v0.9.2", 1, true},
+		{"0.8", "v0.8.0", 0, true},
+		{"v1.0.0", "v0.99.99", 1, true},
+		{"latest", "v0.8.0", 0, false},
+		{"v0.8.0-rc1", "v0.8.0", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := CompareVersions(tt.a, tt.b)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("CompareVersions(%q, %q) = (%d, %v), want (%d, %v)", tt.a, tt.b, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestLatestVersionTag(t *testing.T) {
+	tags := []string{"latest", "v0.8.0", "v0.10.1", "v0.9.0", "edge"}
+	if got := latestVersionTag(tags); got != "v0.10.1" {
+		t.Errorf("latestVersionTag() = %q, want v0.10.1", got)
+	}
+	if got := latestVersionTag([]string{"latest"}); got != "" {
+		t.Errorf("latestVersionTag() = %q, want empty", got)
+	}
+}
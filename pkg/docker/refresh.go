@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// RefreshApp re-lists only the containers belonging to app, filtering docker ps
+// by service label instead of running a full discovery. The returned App keeps
+// the same service, destination and proxy status.
+func RefreshApp(client *ssh.Client, app App) (App, error) {
+	output, err := client.Run(refreshCommand(client, appServices(app)))
+	if err != nil {
+		return app, fmt.Errorf("failed to list containers for %s: %w", app.Service, err)
+	}
+	return regroupApp(app, parseContainers(output)), nil
+}
+
+// appServices returns the distinct service labels used by app's web and
+// accessory containers. The app's own service is always included.
+func appServices(app App) []string {
+	seen := map[string]bool{app.Service: true}
+	services := []string{app.Service}
+	add := func(cs []Container) {
+		for _, c := range cs {
+			if s := c.Labels["service"]; s != "" && !seen[s] {
+				seen[s] = true
+				services = append(services, s)
+			}
+		}
+	}
+	add(app.Containers)
+	for _, acc := range app.Accessories {
+		add(acc.Containers)
+	}
+	sort.Strings(services[1:])
+	return services
+}
+
+// refreshCommand builds one docker ps per service label. Multiple label filters
+// in a single docker ps are ANDed, so services are listed separately.
+func refreshCommand(client *ssh.Client, services []string) string {
+	cmds := make([]string, len(services))
+	for i, s := range services {
+		cmds[i] = fmt.Sprintf("%s ps -a --filter %s --format %s", Bin(client), ssh.Quote("label=service="+s), containerListFormat)
+	}
+	return strings.Join(cmds, "; ")
+}
+
+// regroupApp builds a fresh App from containers, keeping only those in app's
+// destination. Roles follow groupContainers: the role label wins, otherwise
+// a service named "<app>-<name>" is the accessory <name>.
+func regroupApp(app App, containers []Container) App {
+	out := App{Service: app.Service, Destination: app.Destination, ProxyStatus: app.ProxyStatus}
+	services := map[string]bool{app.Service: true}
+	for _, c := range containers {
+		services[c.Labels["service"]] = true
+	}
+	for _, c := range containers {
+		dest := c.Labels["destination"]
+		if dest == "" {
+			dest = "production"
+		}
+		if dest != app.Destination {
+			continue
+		}
+		_, _, c.Grouping = classifyContainer(c, services)
+		role := c.Labels["role"]
+		if service := c.Labels["service"]; role == "" && service != app.Service {
+			role = strings.TrimPrefix(service, app.Service+"-")
+		}
+		if role == "" || role == "web" {
+			out.Containers = append(out.Containers, c)
+			continue
+		}
+		found := false
+		for i := range out.Accessories {
+			if out.Accessories[i].Name == role {
+				out.Accessories[i].Containers = append(out.Accessories[i].Containers, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			out.Accessories = append(out.Accessories, Accessory{Name: role, Containers: []Container{c}})
+		}
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package docker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func labeled(id, state, service, destination, role string) Container {
+	labels := map[string]string{"service": service}
+	if destination != "" {
+		labels["destination"] = destination
+	}
+	if role != "" {
+		labels["role"] = role
+	}
+	return Container{ID: id, State: state, Labels: labels}
+}
+
+func TestAppServices(t *testing.T) {
+	app := App{
+		Service:    "myapp",
+		Containers: []Container{labeled("a", "running", "myapp", "", "web")},
+		Accessories: []Accessory{
+			{Name: "redis", Containers: []Container{labeled("r", "running", "myapp-redis", "", "")}},
+			{Name: "db", Containers: []Container{labeled("d", "running", "myapp-db", "", "")}},
+		},
+	}
+	want := []string{"myapp", "myapp-db", "myapp-redis"}
+	if got := appServices(app); !reflect.DeepEqual(got, want) {
+		t.Errorf("appServices() = %v, want %v", got, want)
+	}
+}
+
+func TestRefreshCommand(t *testing.T) {
+	got := refreshCommand(nil, []string{"myapp", "myapp-db"})
+	if n := strings.Count(got, "docker ps -a --filter label=service="); n != 2 {
+		t.Fatalf("refreshCommand() has %d docker ps invocations, want 2: %s", n, got)
+	}
+	if !strings.Contains(got, "label=service=myapp-db --format") {
+		t.Errorf("refreshCommand() missing accessory filter: %s", got)
+	}
+
+	got = refreshCommand(nil, []string{"my app;rm -rf /"})
+	if !strings.Contains(got, "'label=service=my app;rm -rf /'") {
+		t.Errorf("refreshCommand() did not quote the service label: %s", got)
+	}
+}
+
+func TestRegroupApp(t *testing.T) {
+	app := App{Service: "myapp", Destination: "production", ProxyStatus: "running"}
+	fresh := []Container{
+		labeled("w1", "exited", "myapp", "", "web"),
+		labeled("w2", "running", "myapp", "production", ""),
+		labeled("s1", "running", "myapp", "staging", "web"),
+		labeled("j1", "running", "myapp", "", "job"),
+		labeled("p1", "running", "myapp-postgres", "", ""),
+	}
+	got := regroupApp(app, fresh)
+
+	if got.Service != "myapp" || got.Destination != "production" || got.ProxyStatus != "running" {
+		t.Errorf("regroupApp() identity = %q/%q/%q", got.Service, got.Destination, got.ProxyStatus)
+	}
+	var webIDs []string
+	for _, c := range got.Containers {
+		webIDs = append(webIDs, c.ID)
+	}
+	if !reflect.DeepEqual(webIDs, []string{"w1", "w2"}) {
+		t.Errorf("web containers = %v, want [w1 w2]", webIDs)
+	}
+	if got.Containers[0].State != "exited" {
+		t.Errorf("web container state = %q, want refreshed state exited", got.Containers[0].State)
+	}
+	var accNames []string
+	for _, acc := range got.Accessories {
+		accNames = append(accNames, acc.Name)
+	}
+	if !reflect.DeepEqual(accNames, []string{"job", "postgres"}) {
+		t.Errorf("accessories = %v, want [job postgres]", accNames)
+	}
+}
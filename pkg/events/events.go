@@ -0,0 +1,235 @@
+// Package events publishes lazykamal activity as newline-delimited JSON on a
+// local Unix socket so external tools (dashboards, chat bots) can follow
+// deploys without scraping the TUI.
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event types.
+const (
+	CommandStarted  = "command_started"
+	CommandFinished = "command_finished"
+	LockAcquired    = "lock_acquired"
+	Deployed        = "deployed"
+	PostDeploy      = "post_deploy"      // Success is the verdict, Error the failures
+	ConfirmPending  = "confirm_pending"  // Command is the dialog title
+	ConfirmAnswered = "confirm_answered" // Success is true for yes
+	StatusReply     = "status"
+)
+
+// Event is one JSON line on the socket.
+type Event struct {
+	Type        string    `json:"type"`
+	Time        time.Time `json:"time"`
+	Command     string    `json:"command,omitempty"`
+	Destination string    `json:"destination,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	DurationMs  int64     `json:"duration_ms,omitempty"`
+	Success     *bool     `json:"success,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Message     string    `json:"message,omitempty"`
+	Running     bool      `json:"running,omitempty"` // status replies only
+	Pending     string    `json:"pending,omitempty"` // status replies: open confirm dialog
+}
+
+// Result returns a pointer for Event.Success.
+func Result(ok bool) *bool { return &ok }
+
+const (
+	clientBuffer = 64              // events queued per client before it is dropped
+	writeTimeout = 2 * time.Second // a client that stalls longer is dropped
+)
+
+// Server accepts subscribers and fans events out to them. Publish never
+// blocks: a client whose queue is full is disconnected instead.
+type Server struct {
+	ln      net.Listener
+	status  func() Event
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  bool
+}
+
+type client struct {
+	conn net.Conn
+	out  chan []byte
+	once sync.Once
+}
+
+// Listen creates the socket at path (removing a stale socket left by a
+// previous run) and starts accepting clients. status answers "status"
+// requests; it must be safe to call from any goroutine.
+func Listen(path string, status func() Event) (*Server, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, errors.New(path + " exists and is not a socket")
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, errors.New(path + " is in use by another process")
+		}
+		os.Remove(path)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	s := newServer(status)
+	s.ln = ln
+	go s.accept()
+	return s, nil
+}
+
+func newServer(status func() Event) *Server {
+	return &Server{status: status, clients: map[*client]struct{}{}}
+}
+
+func (s *Server) accept() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.serve(conn)
+	}
+}
+
+// serve registers conn as a subscriber and starts its reader and writer.
+func (s *Server) serve(conn net.Conn) {
+	c := &client{conn: conn, out: make(chan []byte, clientBuffer)}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	go s.write(c)
+	go s.read(c)
+}
+
+func (s *Server) write(c *client) {
+	for line := range c.out {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if _, err := c.conn.Write(line); err != nil {
+			s.drop(c)
+			return
+		}
+	}
+}
+
+// read answers "status" requests; any other line is ignored. The client is
+// dropped when it disconnects.
+func (s *Server) read(c *client) {
+	sc := bufio.NewScanner(c.conn)
+	for sc.Scan() {
+		if strings.TrimSpace(sc.Text()) != "status" || s.status == nil {
+			continue
+		}
+		e := s.status()
+		e.Type = StatusReply
+		if e.Time.IsZero() {
+			e.Time = time.Now()
+		}
+		s.send(c, e)
+	}
+	s.drop(c)
+}
+
+func (s *Server) send(c *client, e Event) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.out <- line:
+	default:
+		s.dropLocked(c)
+	}
+}
+
+// Publish sends e to every connected client. It is a no-op on a nil Server,
+// so callers need not check whether the socket is enabled.
+func (s *Server) Publish(e Event) {
+	if s == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	s.mu.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+	for _, c := range clients {
+		s.send(c, e)
+	}
+}
+
+func (s *Server) drop(c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropLocked(c)
+}
+
+func (s *Server) dropLocked(c *client) {
+	if _, ok := s.clients[c]; !ok {
+		return
+	}
+	delete(s.clients, c)
+	c.once.Do(func() {
+		close(c.out)
+		c.conn.Close()
+	})
+}
+
+// Clients returns the number of connected subscribers.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// Close stops accepting clients, disconnects the existing ones and removes
+// the socket file.
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.clients {
+		s.dropLocked(c)
+	}
+	s.mu.Unlock()
+	if s.ln == nil {
+		return nil
+	}
+	path := s.ln.Addr().String()
+	err := s.ln.Close()
+	os.Remove(path)
+	return err
+}
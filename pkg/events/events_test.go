@@ -0,0 +1,153 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readEvent(t *testing.T, r *bufio.Reader) Event {
+	t.Helper()
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var e Event
+	if err := json.Unmarshal(line, &e); err != nil {
+		t.Fatalf("unmarshal %q: %v", line, err)
+	}
+	return e
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPublishAndStatus(t *testing.T) {
+	s := newServer(func() Event {
+		return Event{Running: true, Command: "Deploy", Destination: "production"}
+	})
+	defer s.Close()
+	server, conn := net.Pipe()
+	defer conn.Close()
+	s.serve(server)
+	r := bufio.NewReader(conn)
+
+	s.Publish(Event{Type: CommandFinished, Command: "Deploy", DurationMs: 1500, Success: Result(true)})
+	e := readEvent(t, r)
+	if e.Type != CommandFinished || e.Command != "Deploy" || e.DurationMs != 1500 || e.Success == nil || !*e.Success || e.Time.IsZero() {
+		t.Errorf("published event = %+v", e)
+	}
+
+	if _, err := conn.Write([]byte("status\n")); err != nil {
+		t.Fatal(err)
+	}
+	e = readEvent(t, r)
+	if e.Type != StatusReply || !e.Running || e.Command != "Deploy" || e.Destination != "production" {
+		t.Errorf("status reply = %+v", e)
+	}
+}
+
+func TestSlowClientIsDropped(t *testing.T) {
+	s := newServer(nil)
+	defer s.Close()
+	server, conn := net.Pipe() // synchronous: nothing is read, so writes stall
+	defer conn.Close()
+	s.serve(server)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < clientBuffer*2; i++ {
+			s.Publish(Event{Type: CommandStarted})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a slow client")
+	}
+	waitFor(t, func() bool { return s.Clients() == 0 })
+}
+
+func TestDisconnectedClientIsRemoved(t *testing.T) {
+	s := newServer(nil)
+	defer s.Close()
+	server, conn := net.Pipe()
+	s.serve(server)
+	if s.Clients() != 1 {
+		t.Fatalf("Clients() = %d, want 1", s.Clients())
+	}
+	conn.Close()
+	waitFor(t, func() bool { return s.Clients() == 0 })
+	s.Publish(Event{Type: CommandStarted}) // must not panic or block
+}
+
+func TestListenUnixSocket(t *testing.T) {
+	dir, err := os.MkdirTemp("", "lk") // short path: socket paths are length-limited
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events.sock")
+
+	s, err := Listen(path, func() Event { return Event{Destination: "staging"} })
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("socket mode = %v, %v; want 0600", fi.Mode().Perm(), err)
+	}
+	if _, err := Listen(path, nil); err == nil {
+		t.Error("second Listen() on a live socket = nil error, want in use")
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	waitFor(t, func() bool { return s.Clients() == 1 })
+	s.Publish(Event{Type: LockAcquired, Destination: "staging"})
+	if e := readEvent(t, r); e.Type != LockAcquired {
+		t.Errorf("event = %+v, want lock_acquired", e)
+	}
+	conn.Write([]byte("status\n"))
+	if e := readEvent(t, r); e.Type != StatusReply || e.Destination != "staging" {
+		t.Errorf("status reply = %+v", e)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket still exists after Close: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Listen(path, nil); err == nil {
+		t.Error("Listen() over a regular file = nil error, want error")
+	}
+}
+
+func TestNilServer(t *testing.T) {
+	var s *Server
+	s.Publish(Event{Type: CommandStarted})
+	if err := s.Close(); err != nil {
+		t.Errorf("Close() = %v", err)
+	}
+}
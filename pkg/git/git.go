@@ -0,0 +1,60 @@
+// Package git reads the state of the working tree kamal builds from: the
+// current branch, commit and whether there are uncommitted changes.
+package git
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// timeout bounds each git command, so a slow filesystem or a hung git
+// never holds up the status poll.
+const timeout = 5 * time.Second
+
+// Head is the checked-out commit of a repository.
+type Head struct {
+	Branch string // "HEAD" when detached
+	Commit string // abbreviated hash
+	Dirty  bool   // tracked or untracked changes are not committed
+}
+
+// String is h as shown in the header, e.g. "main@a1b2c3d (dirty)".
+func (h Head) String() string {
+	s := h.Branch + "@" + h.Commit
+	if h.Dirty {
+		s += " (dirty)"
+	}
+	return s
+}
+
+// Current returns the head of the repository dir is in, and false when dir
+// is not in a git repository, git is not installed or a command fails.
+func Current(dir string) (Head, bool) {
+	branch, ok := output(dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if !ok {
+		return Head{}, false
+	}
+	commit, ok := output(dir, "rev-parse", "--short", "HEAD")
+	if !ok {
+		return Head{}, false
+	}
+	status, ok := output(dir, "status", "--porcelain")
+	if !ok {
+		return Head{}, false
+	}
+	return Head{Branch: branch, Commit: commit, Dirty: status != ""}, true
+}
+
+// output runs git -C dir args and returns its trimmed stdout, and false on
+// any error or non-zero exit.
+func output(dir string, args ...string) (string, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(out)), true
+}
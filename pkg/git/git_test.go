@@ -0,0 +1,62 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestHeadString(t *testing.T) {
+	tests := []struct {
+		head Head
+		want string
+	}{
+		{Head{Branch: "main", Commit: "a1b2c3d"}, "main@a1b2c3d"},
+		{Head{Branch: "main", Commit: "a1b2c3d", Dirty: true}, "main@a1b2c3d (dirty)"},
+		{Head{Branch: "HEAD", Commit: "a1b2c3d"}, "HEAD@a1b2c3d"},
+	}
+	for _, tt := range tests {
+		if got := tt.head.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.head, got, tt.want)
+		}
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if _, ok := Current(dir); ok {
+		t.Fatal("Current reported a head outside a repository")
+	}
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	file := filepath.Join(dir, "deploy.yml")
+	if err := os.WriteFile(file, []byte("service: app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	head, ok := Current(dir)
+	if !ok || head.Branch != "main" || !regexp.MustCompile(`^[0-9a-f]{7,}$`).MatchString(head.Commit) || head.Dirty {
+		t.Fatalf("Current = %+v, %v; want a clean main", head, ok)
+	}
+	if err := os.WriteFile(file, []byte("service: other\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if head, _ := Current(dir); !head.Dirty {
+		t.Errorf("Current = %+v after an edit, want dirty", head)
+	}
+}
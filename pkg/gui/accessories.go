@@ -0,0 +1,160 @@
+package gui
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+// accessoryTemplate is inserted by 't' on the empty Accessory screen.
+var accessoryTemplate = []string{
+	"  redis:",
+	"    image: redis:7",
+	"    host: 192.168.0.1",
+	"    port: \"127.0.0.1:6379:6379\"",
+	"    directories:",
+	"      - data:/data",
+}
+
+// hasAccessories reports whether the selected destination defines any
+// accessory. Without a destination the menu is shown as usual.
+func (gui *GUI) hasAccessories() bool {
+	dest := gui.selectedDestination()
+	return dest == nil || len(dest.Accessories()) > 0
+}
+
+// pickAccessory asks which accessory the Accessory menu acts on, "all"
+// first so the previous behavior is one Enter away. It reports false when
+// there is nothing to pick and the menu should open directly.
+func (gui *GUI) pickAccessory() bool {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return false
+	}
+	names := dest.Accessories()
+	if len(names) == 0 {
+		gui.accessory = ""
+		return false
+	}
+	gui.showPicker(accessoryPicker(names, gui.accessoryStates(dest), func(name string) {
+		gui.accessory = name
+		gui.screen = ScreenAccessory
+		gui.submenuIdx = 0
+	}))
+	return true
+}
+
+// accessoryPicker lists "all" and then every accessory, including those
+// inherited from the base deploy.yml, with a health dot once the status
+// poll has checked it. Picking "all" passes "".
+func accessoryPicker(names []string, states map[string]string, onPick func(name string)) *listPicker {
+	p := &listPicker{
+		Title:   "Accessory",
+		Message: "Run the Accessory actions on one accessory or on all of them.",
+		Items:   []pickerItem{{Label: "all", Value: ""}},
+		OnDone: func(values []string) {
+			if len(values) > 0 {
+				onPick(values[0])
+			}
+		},
+	}
+	for _, name := range names {
+		label := name
+		if state, ok := states[name]; ok {
+			label = accessoryDot(state) + " " + name + dim(" ("+state+")")
+		}
+		p.Items = append(p.Items, pickerItem{Label: label, Value: name})
+	}
+	return p
+}
+
+// accessoryArg is the accessory argument for kamal: the picked accessory
+// or "all".
+func (gui *GUI) accessoryArg() string {
+	if gui.accessory == "" {
+		return "all"
+	}
+	return gui.accessory
+}
+
+// accessoryLabel names the target in command names, keeping the "All" of
+// the original names.
+func (gui *GUI) accessoryLabel() string {
+	if gui.accessory == "" {
+		return "All"
+	}
+	return gui.accessory
+}
+
+var allWord = regexp.MustCompile(`\ball\b`)
+
+// forAccessory rewrites a menu label or command for a single accessory:
+// "Boot all" becomes "Boot redis". Unchanged when name is "".
+func forAccessory(s, name string) string {
+	if name == "" {
+		return s
+	}
+	return allWord.ReplaceAllLiteralString(s, name)
+}
+
+// accessoryMessage rewords a confirmation for a single accessory.
+func (gui *GUI) accessoryMessage(message string) string {
+	if gui.accessory == "" {
+		return message
+	}
+	return strings.Replace(message, "all accessories", "accessory "+gui.accessory, 1)
+}
+
+// insertAccessoryTemplate adds accessoryTemplate under an existing top-level
+// "accessories:" key, or appends a new section. It returns the new lines and
+// the row of the first inserted line.
+func insertAccessoryTemplate(lines []string) ([]string, int) {
+	for i, l := range lines {
+		if strings.TrimRight(l, " ") == "accessories:" {
+			out := append([]string{}, lines[:i+1]...)
+			out = append(out, accessoryTemplate...)
+			return append(out, lines[i+1:]...), i + 1
+		}
+	}
+	out := append([]string{}, lines...)
+	for len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+		out = out[:len(out)-1]
+	}
+	out = append(out, "", "accessories:")
+	row := len(out)
+	out = append(out, accessoryTemplate...)
+	return append(out, ""), row
+}
+
+// keyAccessoryConfig handles e (edit) and t (edit with template) on the
+// Accessory screen when no accessories are configured. Accessories usually
+// live in the shared deploy.yml, so the base config is opened when there is one.
+func (gui *GUI) keyAccessoryConfig(template bool) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if gui.screen != ScreenAccessory || gui.hasAccessories() {
+			return nil
+		}
+		dest := gui.selectedDestination()
+		path := dest.ConfigPath
+		if dest.BasePath != "" {
+			path = dest.BasePath
+		}
+		if err := validatePath(gui.cwd, path); err != nil {
+			gui.logError("Security: " + err.Error())
+			return nil
+		}
+		if !gui.openEditor(path) {
+			return nil
+		}
+		if template {
+			gui.editor.record()
+			gui.editor.Lines, gui.editor.Row = insertAccessoryTemplate(gui.editor.Lines)
+			gui.editor.Dirty = true
+			gui.appendLog([]string{"Inserted an accessory template into " + path + " (^S save, ^Q/Esc quit)"})
+			return nil
+		}
+		gui.appendLog([]string{"Editing " + path + " (^S save, ^Q/Esc quit)"})
+		return nil
+	}
+}
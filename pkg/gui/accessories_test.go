@@ -0,0 +1,77 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestInsertAccessoryTemplate(t *testing.T) {
+	lines, row := insertAccessoryTemplate([]string{"service: myapp", "", ""})
+	if row != 3 || lines[2] != "accessories:" || lines[3] != accessoryTemplate[0] {
+		t.Errorf("append: row=%d lines=%q", row, lines)
+	}
+
+	lines, row = insertAccessoryTemplate([]string{"service: myapp", "accessories:", "builder:", "  arch: amd64"})
+	if row != 2 || lines[2] != accessoryTemplate[0] || lines[len(lines)-2] != "builder:" {
+		t.Errorf("existing key: row=%d lines=%q", row, lines)
+	}
+	if n := strings.Count(strings.Join(lines, "\n"), "accessories:"); n != 1 {
+		t.Errorf("accessories: appears %d times, want 1", n)
+	}
+}
+
+func TestAccessoryPicker(t *testing.T) {
+	var picked = "unset"
+	p := accessoryPicker([]string{"postgres", "redis"}, map[string]string{"redis": kamal.AccessoryDown}, func(name string) { picked = name })
+	if len(p.Items) != 3 || p.Items[0].Label != "all" {
+		t.Fatalf("items = %+v, want all first", p.Items)
+	}
+	if p.Items[1].Label != "postgres" || stripANSI(p.Items[2].Label) != iconRunning+" redis (down)" {
+		t.Errorf("labels = %q, %q, want postgres unchecked and redis down", p.Items[1].Label, p.Items[2].Label)
+	}
+	p.OnDone(p.selected())
+	if picked != "" {
+		t.Errorf("all picked %q, want empty", picked)
+	}
+	p.move(2)
+	p.OnDone(p.selected())
+	if picked != "redis" {
+		t.Errorf("picked %q, want redis", picked)
+	}
+}
+
+func TestForAccessory(t *testing.T) {
+	tests := []struct{ in, name, want string }{
+		{"Boot all", "redis", "Boot redis"},
+		{"Exec: sh (all)", "redis", "Exec: sh (redis)"},
+		{"kamal accessory logs all", "redis", "kamal accessory logs redis"},
+		{"Upgrade", "redis", "Upgrade"},
+		{"Boot all", "", "Boot all"},
+	}
+	for _, tt := range tests {
+		if got := forAccessory(tt.in, tt.name); got != tt.want {
+			t.Errorf("forAccessory(%q, %q) = %q, want %q", tt.in, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExecAccessoryTarget(t *testing.T) {
+	gui := &GUI{accessory: "redis"}
+	if gui.accessoryArg() != "redis" || gui.accessoryLabel() != "redis" {
+		t.Errorf("arg/label = %q/%q", gui.accessoryArg(), gui.accessoryLabel())
+	}
+	if got := gui.accessoryMessage("Stop all accessories?"); got != "Stop accessory redis?" {
+		t.Errorf("message = %q", got)
+	}
+	gui.accessory = ""
+	if gui.accessoryArg() != "all" || gui.accessoryLabel() != "All" {
+		t.Errorf("all: arg/label = %q/%q", gui.accessoryArg(), gui.accessoryLabel())
+	}
+	for _, item := range menus[ScreenAccessory] {
+		if want := item.Label == "Details all" || item.Label == "Logs all"; item.ReadOnly != want {
+			t.Errorf("%s: ReadOnly = %v, want %v", item.Label, item.ReadOnly, want)
+		}
+	}
+}
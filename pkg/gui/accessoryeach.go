@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// accessoryEach is Accessory Details or Logs for all accessories: one
+// command per configured accessory, kamal.AccessoryJobs at a time, each
+// logged under its own header with the failures summed up at the end.
+// Without configured accessories it falls back to `kamal accessory ACTION all`.
+func (gui *GUI) accessoryEach(action string, opts kamal.RunOptions) func(stopCh <-chan struct{}) (kamal.Result, error) {
+	var names []string
+	if dest := gui.selectedDestination(); dest != nil {
+		names = dest.Accessories()
+	}
+	if len(names) == 0 {
+		return func(stopCh <-chan struct{}) (kamal.Result, error) {
+			return kamal.RunKamalWithStop([]string{"accessory", action, "all"}, opts, stopCh)
+		}
+	}
+	return func(stopCh <-chan struct{}) (kamal.Result, error) {
+		gui.logInfo(fmt.Sprintf("→ kamal accessory %s for %s (%d at a time)", action, strings.Join(names, ", "), kamal.AccessoryJobs))
+		results := kamal.RunAccessoryEach(action, names, opts, kamal.AccessoryJobs, stopCh)
+		gui.appendLog(accessorySections(results, gui.ansi))
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+		select {
+		case <-stopCh:
+			return kamal.Result{ExitCode: -1}, errors.New("cancelled")
+		default:
+		}
+		return accessoryEachResult(results), nil
+	}
+}
+
+// accessorySections renders each accessory's output under a header, in
+// order, followed by one line per accessory that failed.
+func accessorySections(results []kamal.AccessoryResult, mode ansiMode) []string {
+	var lines, failures []string
+	for _, r := range results {
+		header := cyan("── " + r.Name + " ──")
+		if r.Failed() {
+			header += " " + red("failed")
+			failures = append(failures, statusLine("error", r.Name+": "+accessoryError(r)))
+		}
+		lines = append(lines, header)
+		for _, l := range cleanOutputLines(r.Result.Lines(), mode) {
+			if strings.TrimSpace(l) == "" {
+				continue
+			}
+			lines = append(lines, "  "+l)
+		}
+		if r.Err != nil {
+			lines = append(lines, "  "+red(r.Err.Error()))
+		}
+	}
+	if len(failures) > 0 {
+		lines = append(lines, fmt.Sprintf("%d of %d accessories failed:", len(failures), len(results)))
+		lines = append(lines, failures...)
+	}
+	return lines
+}
+
+// accessoryError explains a failed accessory in one line: its SSHKit host
+// error when there is one.
+func accessoryError(r kamal.AccessoryResult) string {
+	if r.Err == nil {
+		plain := r.Result.Lines()
+		for i, l := range plain {
+			plain[i] = stripANSI(l)
+		}
+		if errs := kamal.ParseHostErrors(plain); len(errs) > 0 {
+			return hostErrorLine(errs[0])
+		}
+	}
+	return truncate(firstErrorLine(r.Result, r.Err), 120)
+}
+
+// accessoryEachResult is the command's result as history records it: the
+// sections' plain output, exiting with the first failure's code.
+func accessoryEachResult(results []kamal.AccessoryResult) kamal.Result {
+	var b strings.Builder
+	res := kamal.Result{Streamed: true}
+	for _, r := range results {
+		fmt.Fprintf(&b, "── %s ──\n", r.Name)
+		if out := r.Result.Combined(); out != "" {
+			b.WriteString(strings.TrimSuffix(out, "\n") + "\n")
+		}
+		if res.ExitCode == 0 && r.Failed() {
+			res.ExitCode = r.Result.ExitCode
+			if res.ExitCode == 0 {
+				res.ExitCode = 1
+			}
+		}
+	}
+	res.Stdout = b.String()
+	return res
+}
@@ -0,0 +1,49 @@
+package gui
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestAccessorySections(t *testing.T) {
+	plainStyle(t)
+	results := []kamal.AccessoryResult{
+		{Name: "db", Result: kamal.Result{Stdout: "Container db running\n"}},
+		{Name: "redis", Result: kamal.Result{
+			Stdout:   "  INFO [abc] Running docker ps on 10.0.0.2\n",
+			Stderr:   "ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.0.2: Net::SSH::AuthenticationFailed",
+			ExitCode: 1,
+		}},
+		{Name: "search", Err: errors.New("command cancelled")},
+	}
+	got := accessorySections(results, ansiStrip)
+	want := []string{
+		"── db ──",
+		"  Container db running",
+		"── redis ── failed",
+		"    INFO [abc] Running docker ps on 10.0.0.2",
+		"  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.0.2: Net::SSH::AuthenticationFailed",
+		"── search ── failed",
+		"  command cancelled",
+		"2 of 3 accessories failed:",
+		"[ERR] redis: host 10.0.0.2: Net::SSH::AuthenticationFailed",
+		"[ERR] search: command cancelled",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("accessorySections() =\n%q\nwant\n%q", got, want)
+	}
+
+	res := accessoryEachResult(results)
+	if res.ExitCode != 1 || !res.Streamed {
+		t.Errorf("accessoryEachResult() = %+v", res)
+	}
+	if want := "── db ──\nContainer db running\n── redis ──\n"; res.Stdout[:len(want)] != want {
+		t.Errorf("accessoryEachResult().Stdout = %q", res.Stdout)
+	}
+	if res := accessoryEachResult(results[:1]); res.ExitCode != 0 {
+		t.Errorf("accessoryEachResult(ok) exit = %d", res.ExitCode)
+	}
+}
@@ -0,0 +1,125 @@
+package gui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// accessoryRecheck is how often the status poll re-runs `kamal accessory
+// details all`. Accessories rarely change on their own, and accessory
+// commands run from the TUI invalidate the result anyway.
+const accessoryRecheck = time.Minute
+
+// touchesAccessories reports whether command name can start, stop or
+// replace accessory containers.
+func touchesAccessories(name string) bool {
+	return strings.HasPrefix(name, "Accessory ") || strings.HasPrefix(name, "Setup") || strings.Contains(name, "with accessories") || name == removeEverythingName
+}
+
+// checkAccessories runs `kamal accessory details all` for dest from the
+// status poll when it has accessories and the last check is older than
+// accessoryRecheck.
+func (gui *GUI) checkAccessories(dest *kamal.DeployDestination, opts kamal.RunOptions) {
+	names := dest.Accessories()
+	if len(names) == 0 {
+		return
+	}
+	key := hostsKey(dest)
+	if !gui.accessoryHealth.claim(key, time.Now(), accessoryRecheck) {
+		return
+	}
+	opts.Defaults = nil // a --verbose default would bury the tables in log lines
+	r, err := kamal.AccessoryDetails(opts, "all")
+	if err != nil || r.ExitCode != 0 {
+		return
+	}
+	gui.accessoryHealth.set(key, kamal.ParseAccessoryDetails(r.Stdout, dest.Service, names), time.Now())
+}
+
+// accessoryDot is an accessory health state as a colored dot.
+func accessoryDot(state string) string {
+	switch state {
+	case kamal.AccessoryRunning:
+		return green(iconRunning)
+	case kamal.AccessoryDegraded:
+		return yellow(iconRunning)
+	case kamal.AccessoryDown:
+		return red(iconRunning)
+	}
+	return dim(iconPending)
+}
+
+// accessoryState describes h for the status panel: "running", "down",
+// "unhealthy", "down on 10.0.0.6" or "not found".
+func accessoryState(h kamal.AccessoryHealth) string {
+	switch h.State() {
+	case kamal.AccessoryRunning:
+		return "running"
+	case kamal.AccessoryDown:
+		return "down"
+	case kamal.AccessoryUnknown:
+		return "not found"
+	}
+	if len(h.Down) == 0 {
+		return "unhealthy"
+	}
+	var hosts []string
+	for _, host := range h.Down {
+		if host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if len(hosts) == 0 {
+		return "down on some hosts"
+	}
+	return "down on " + strings.Join(hosts, ", ")
+}
+
+// accessoriesStatus is the Accessories section of the Live status panel: a
+// dot, the name and the state of each accessory, aligned.
+func accessoriesStatus(health []kamal.AccessoryHealth) string {
+	width := 0
+	for _, h := range health {
+		if w := visibleWidth(h.Name); w > width {
+			width = w
+		}
+	}
+	var b strings.Builder
+	b.WriteString(" Accessories:\n")
+	for _, h := range health {
+		state := accessoryState(h)
+		switch h.State() {
+		case kamal.AccessoryDegraded:
+			state = yellow(state)
+		case kamal.AccessoryDown:
+			state = red(state)
+		case kamal.AccessoryUnknown:
+			state = dim(state)
+		}
+		b.WriteString("   " + accessoryDot(h.State()) + " " + padRight(h.Name, width) + "  " + state + "\n")
+	}
+	return b.String()
+}
+
+// accessoryStatusFor is the status panel's Accessories section for dest, or
+// "" before the first successful check.
+func (gui *GUI) accessoryStatusFor(dest *kamal.DeployDestination) string {
+	health, ok := gui.accessoryHealth.current(hostsKey(dest))
+	if !ok || len(health) == 0 {
+		return ""
+	}
+	return accessoriesStatus(health)
+}
+
+// accessoryStates is the last known health state of each of dest's
+// accessories, for the accessory picker; empty before the first check.
+func (gui *GUI) accessoryStates(dest *kamal.DeployDestination) map[string]string {
+	health, _ := gui.accessoryHealth.current(hostsKey(dest))
+	states := map[string]string{}
+	for _, h := range health {
+		states[h.Name] = h.State()
+	}
+	return states
+}
@@ -0,0 +1,68 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestAccessoriesStatus(t *testing.T) {
+	plainStyle(t)
+	got := accessoriesStatus([]kamal.AccessoryHealth{
+		{Name: "db", Up: []string{"10.0.0.5"}, Status: "Up 2 days"},
+		{Name: "redis", Up: []string{"10.0.0.5"}, Down: []string{"10.0.0.6"}},
+		{Name: "search", Up: []string{"10.0.0.5"}, Status: "Up 1 hour (unhealthy)"},
+		{Name: "cache", Down: []string{"10.0.0.5"}},
+		{Name: "queue"},
+	})
+	want := strings.Join([]string{
+		" Accessories:",
+		"   * db      running",
+		"   * redis   down on 10.0.0.6",
+		"   * search  unhealthy",
+		"   * cache   down",
+		"   o queue   not found",
+		"",
+	}, "\n")
+	if got != want {
+		t.Errorf("accessoriesStatus() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestTouchesAccessories(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Accessory Reboot redis": true, "Setup": true, "App Boot (with accessories)": true, "App Boot": false, "Deploy": false,
+	} {
+		if got := touchesAccessories(name); got != want {
+			t.Errorf("touchesAccessories(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestCheckAccessoriesCachesPerDestination(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	dest := gui.selectedDestination()
+	gui.checkAccessories(dest, gui.runOpts())
+	gui.checkAccessories(dest, gui.runOpts())
+	if n := len(runner.Calls()); n != 1 {
+		t.Errorf("%d accessory details runs, want 1 within accessoryRecheck", n)
+	}
+	section := stripANSI(gui.accessoryStatusFor(dest))
+	if !strings.Contains(section, "db     running") || !strings.Contains(section, "redis  running") {
+		t.Errorf("status section =\n%s", section)
+	}
+	if states := gui.accessoryStates(dest); states["redis"] != kamal.AccessoryRunning {
+		t.Errorf("accessoryStates() = %v", states)
+	}
+
+	gui.accessoryHealth.forget()
+	if gui.accessoryStatusFor(dest) != "" {
+		t.Error("section shown after forget")
+	}
+	gui.checkAccessories(dest, gui.runOpts())
+	if n := len(runner.Calls()); n != 2 {
+		t.Errorf("%d accessory details runs after forget, want 2", n)
+	}
+}
@@ -0,0 +1,71 @@
+package gui
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ansiMode is how escape sequences in command output are handled.
+type ansiMode int
+
+const (
+	ansiStrip ansiMode = iota // remove them; our own highlighting is applied
+	ansiKeep                  // pass them through unchanged
+)
+
+// ansiModeFromEnv reads LAZYKAMAL_ANSI: "keep" passes the colors of
+// kamal and docker output through, anything else strips them.
+func ansiModeFromEnv() ansiMode {
+	if strings.EqualFold(os.Getenv("LAZYKAMAL_ANSI"), "keep") {
+		return ansiKeep
+	}
+	return ansiStrip
+}
+
+// ansiSequence matches CSI sequences (colors, cursor movement), OSC
+// sequences (titles, hyperlinks) and two-byte escapes.
+var ansiSequence = regexp.MustCompile("\x1b\\[[0-?]*[ -/]*[@-~]|\x1b\\][^\x07\x1b]*(?:\x07|\x1b\\\\)|\x1b[@-Z\\\\-_]")
+
+// stripANSI removes escape sequences and carriage returns from s.
+func stripANSI(s string) string {
+	if !strings.ContainsAny(s, "\x1b\r") {
+		return s
+	}
+	return strings.ReplaceAll(ansiSequence.ReplaceAllString(s, ""), "\r", "")
+}
+
+// cleanOutput prepares one line of command output for the log. Redaction
+// always runs on the stripped text, so escape codes inside a key name
+// cannot hide a secret. In ansiKeep mode the original line is kept unless
+// redaction changed it.
+func cleanOutput(line string, mode ansiMode) string {
+	plain := stripANSI(line)
+	redacted := sanitizeLogLine(plain)
+	if mode == ansiKeep && redacted == plain {
+		return line
+	}
+	return highlightOutput(redacted)
+}
+
+// cleanOutputLines is cleanOutput for each line.
+func cleanOutputLines(lines []string, mode ansiMode) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = cleanOutput(l, mode)
+	}
+	return out
+}
+
+// highlightOutput colors SSHKit's ERROR and WARN lines the way kamal does
+// before their escape codes were stripped.
+func highlightOutput(line string) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "ERROR"):
+		return red(line)
+	case strings.HasPrefix(trimmed, "WARN"):
+		return yellow(line)
+	}
+	return line
+}
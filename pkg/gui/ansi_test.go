@@ -0,0 +1,70 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// Colored kamal output as SSHKit prints it to a terminal.
+const (
+	coloredInfo  = "  \x1b[0;34;49mINFO\x1b[0m [\x1b[0;32;49m8a9b1c2d\x1b[0m] Running \x1b[1;33;49mdocker login\x1b[0m on \x1b[0;34;49m10.0.1.5\x1b[0m"
+	coloredError = " \x1b[0;31;49mERROR\x1b[0m [8a9b1c2d] Finished in 0.5 seconds with exit status 1 (\x1b[1;31;49mfailed\x1b[0m)."
+	wrappedKey   = "  DEBUG [8a9b1c2d] \t\x1b[1mRAILS_MASTER_\x1b[0mKEY=0123456789abcdef other=1"
+	osc8Link     = "See \x1b]8;;https://kamal-deploy.org\x1b\\kamal docs\x1b]8;;\x1b\\ for details\r"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{coloredInfo, "  INFO [8a9b1c2d] Running docker login on 10.0.1.5"},
+		{osc8Link, "See kamal docs for details"},
+		{"\x1b[2K\x1b[1Gprogress 50%", "progress 50%"},
+		{"plain line", "plain line"},
+	}
+	for _, tt := range tests {
+		if got := stripANSI(tt.in); got != tt.want {
+			t.Errorf("stripANSI(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCleanOutput(t *testing.T) {
+	got := cleanOutput(coloredInfo, ansiStrip)
+	if got != "  INFO [8a9b1c2d] Running docker login on 10.0.1.5" {
+		t.Errorf("cleanOutput(info, strip) = %q", got)
+	}
+	if got := cleanOutput(coloredInfo, ansiKeep); got != coloredInfo {
+		t.Errorf("cleanOutput(info, keep) = %q, want the original", got)
+	}
+
+	// ERROR lines get our own color back, and only ours.
+	got = cleanOutput(coloredError, ansiStrip)
+	if got != red(" ERROR [8a9b1c2d] Finished in 0.5 seconds with exit status 1 (failed).") {
+		t.Errorf("cleanOutput(error, strip) = %q", got)
+	}
+
+	// A secret whose key name is split by escape codes is still redacted,
+	// in both modes.
+	for _, mode := range []ansiMode{ansiStrip, ansiKeep} {
+		got := cleanOutput(wrappedKey, mode)
+		if strings.Contains(got, "0123456789abcdef") || !strings.Contains(got, "RAILS_MASTER_KEY=[REDACTED]") {
+			t.Errorf("cleanOutput(wrapped secret, %d) = %q, want it redacted", mode, got)
+		}
+		if strings.Contains(got, "\x1b[1m") {
+			t.Errorf("cleanOutput(wrapped secret, %d) = %q, kept kamal's escape codes", mode, got)
+		}
+	}
+}
+
+func TestAppendLogFromResultStripsColors(t *testing.T) {
+	gui := &GUI{}
+	gui.appendLogFromResult(kamal.Result{Stdout: coloredInfo + "\n" + wrappedKey + "\n"})
+	for _, e := range gui.logLines {
+		if strings.Contains(e.text, "\x1b[0;34;49m") || strings.Contains(e.text, "0123456789abcdef") {
+			t.Errorf("log line %q: want colors stripped and secret redacted", e.text)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package gui
+
+import "github.com/shuvro/lazykamal/pkg/kamal"
+
+// promptAppExec asks for a command and runs it with `kamal app exec` in a
+// new container of the current version, logging its output. The last
+// command is offered again.
+func (gui *GUI) promptAppExec() {
+	gui.showInput(newInput(
+		"App Exec",
+		"Command to run in a new app container, e.g. bin/rails db:migrate:status. It runs without a terminal, so an interactive console exits right away.",
+		gui.lastExec,
+		gui.appExec,
+	))
+}
+
+func (gui *GUI) appExec(command string) {
+	gui.lastExec = command
+	args := []string{"app", "exec", command}
+	opts := gui.commandOpts(args)
+	gui.runCommand("App Exec: "+command, func(stopCh <-chan struct{}) (kamal.Result, error) {
+		return kamal.RunKamalWithStop(args, opts, stopCh)
+	})
+}
@@ -0,0 +1,204 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// sortDestinations orders dests for the Apps list: by service, and within
+// a service production last. A service that only has production comes
+// after the others, so production is never the first row and never the
+// default selection while anything else is listed. The order is otherwise
+// kept.
+func sortDestinations(dests []kamal.DeployDestination) []kamal.DeployDestination {
+	onlyProduction := map[string]bool{}
+	for _, d := range dests {
+		if _, seen := onlyProduction[d.Service]; !seen {
+			onlyProduction[d.Service] = true
+		}
+		if !isProduction(&d) {
+			onlyProduction[d.Service] = false
+		}
+	}
+	out := append([]kamal.DeployDestination(nil), dests...)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if onlyProduction[a.Service] != onlyProduction[b.Service] {
+			return !onlyProduction[a.Service]
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return !isProduction(&a) && isProduction(&b)
+	})
+	return out
+}
+
+// appRow is one line of the Apps list: a destination, or the header of a
+// service's group.
+type appRow struct {
+	dest      int    // index into the destinations; -1 for a header
+	service   string // the group's service
+	count     int    // destinations in the group, on headers
+	collapsed bool   // on headers: the group's destinations are not listed
+}
+
+// appRows lays out sorted dests for the Apps list. Destinations that all
+// share one service are listed flat; otherwise each service gets a header
+// with its destinations beneath, unless collapsed names it.
+func appRows(dests []kamal.DeployDestination, collapsed map[string]bool) []appRow {
+	rows := make([]appRow, 0, len(dests))
+	grouped := false
+	for _, d := range dests {
+		if d.Service != dests[0].Service {
+			grouped = true
+			break
+		}
+	}
+	header := -1
+	for i, d := range dests {
+		if !grouped {
+			rows = append(rows, appRow{dest: i, service: d.Service})
+			continue
+		}
+		if i == 0 || dests[i-1].Service != d.Service {
+			header = len(rows)
+			rows = append(rows, appRow{dest: -1, service: d.Service, collapsed: collapsed[d.Service]})
+		}
+		rows[header].count++
+		if !rows[header].collapsed {
+			rows = append(rows, appRow{dest: i, service: d.Service})
+		}
+	}
+	return rows
+}
+
+// selectable reports whether the cursor can rest on r: destinations, and
+// the header of a collapsed group, which stands for its destinations.
+func (r appRow) selectable() bool {
+	return r.dest >= 0 || r.collapsed
+}
+
+// appCursor is the row position of selected among the selectable rows: its
+// own row, or its group's header while the group is collapsed.
+func appCursor(rows []appRow, dests []kamal.DeployDestination, selected int) int {
+	n := 0
+	for _, r := range rows {
+		if !r.selectable() {
+			continue
+		}
+		if r.dest == selected || (r.dest < 0 && selected < len(dests) && dests[selected].Service == r.service) {
+			return n
+		}
+		n++
+	}
+	return 0
+}
+
+// appAt is the destination the n-th selectable row selects. A collapsed
+// header keeps current when it is one of the group's, and otherwise
+// selects the group's first destination.
+func appAt(rows []appRow, dests []kamal.DeployDestination, n, current int) int {
+	for _, r := range rows {
+		if !r.selectable() {
+			continue
+		}
+		if n > 0 {
+			n--
+			continue
+		}
+		if r.dest >= 0 {
+			return r.dest
+		}
+		if current < len(dests) && dests[current].Service == r.service {
+			return current
+		}
+		for i, d := range dests {
+			if d.Service == r.service {
+				return i
+			}
+		}
+	}
+	return current
+}
+
+// appSelectableCount is how many rows the cursor can rest on.
+func appSelectableCount(rows []appRow) int {
+	n := 0
+	for _, r := range rows {
+		if r.selectable() {
+			n++
+		}
+	}
+	return n
+}
+
+// currentAppRows is the Apps list as it is drawn now.
+func (gui *GUI) currentAppRows() []appRow {
+	return appRows(gui.destinations, gui.collapsedApps)
+}
+
+// appHeader renders a service's group header.
+func appHeader(r appRow, selected bool) string {
+	marker := "▾"
+	if r.collapsed {
+		marker = "▸"
+	}
+	label := fmt.Sprintf("%s %s (%d)", marker, r.service, r.count)
+	if r.service == "" {
+		label = fmt.Sprintf("%s (no service) (%d)", marker, r.count)
+	}
+	if selected {
+		return glyphs(iconArrow + " " + label)
+	}
+	return dim(glyphs("  " + label))
+}
+
+// keyAppGroup collapses (expand false) or expands the selected
+// destination's group on the Apps list, keeping the selection.
+func (gui *GUI) keyAppGroup(expand bool) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if gui.screen != ScreenApps || !gui.navigating() || gui.logFocus {
+			return nil
+		}
+		dest := gui.selectedDestination()
+		if dest == nil {
+			return nil
+		}
+		if !appsGrouped(gui.currentAppRows()) {
+			return nil
+		}
+		if gui.collapsedApps == nil {
+			gui.collapsedApps = map[string]bool{}
+		}
+		if expand {
+			delete(gui.collapsedApps, dest.Service)
+		} else {
+			gui.collapsedApps[dest.Service] = true
+		}
+		return nil
+	}
+}
+
+// appsGrouped reports whether rows has group headers.
+func appsGrouped(rows []appRow) bool {
+	for _, r := range rows {
+		if r.dest < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// cursorOnCollapsedGroup reports whether the Apps cursor is on a collapsed
+// group's header, and which service it is.
+func (gui *GUI) cursorOnCollapsedGroup() (string, bool) {
+	dest := gui.selectedDestination()
+	if dest == nil || !gui.collapsedApps[dest.Service] || !appsGrouped(gui.currentAppRows()) {
+		return "", false
+	}
+	return dest.Service, true
+}
@@ -0,0 +1,165 @@
+package gui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// dests builds destinations from "service/name" pairs; a trailing "!"
+// marks one protected.
+func dests(specs ...string) []kamal.DeployDestination {
+	var out []kamal.DeployDestination
+	for _, s := range specs {
+		service, name, _ := strings.Cut(strings.TrimSuffix(s, "!"), "/")
+		out = append(out, kamal.DeployDestination{
+			Service:    service,
+			Name:       name,
+			ConfigPath: service + "/config/deploy." + name + ".yml",
+			Protected:  strings.HasSuffix(s, "!"),
+		})
+	}
+	return out
+}
+
+func destSpecs(list []kamal.DeployDestination) []string {
+	var out []string
+	for _, d := range list {
+		s := d.Service + "/" + d.Name
+		if d.Protected {
+			s += "!"
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestSortDestinations(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"production last", []string{"app/production", "app/staging"}, []string{"app/staging", "app/production"}},
+		{"services ascending", []string{"web/staging", "api/staging", "api/production"}, []string{"api/staging", "api/production", "web/staging"}},
+		{"order kept otherwise", []string{"app/staging", "app/dev", "app/qa"}, []string{"app/staging", "app/dev", "app/qa"}},
+		{"protected counts as production", []string{"app/live!", "app/staging"}, []string{"app/staging", "app/live!"}},
+		{"production-only service goes last", []string{"aaa/production", "zzz/staging"}, []string{"zzz/staging", "aaa/production"}},
+		{"only production", []string{"b/production", "a/production"}, []string{"a/production", "b/production"}},
+		{"empty", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := dests(tt.in...)
+			got := destSpecs(sortDestinations(in))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortDestinations(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if !reflect.DeepEqual(destSpecs(in), tt.in) {
+				t.Errorf("sortDestinations modified its input: %q", destSpecs(in))
+			}
+		})
+	}
+}
+
+func TestSortDestinationsProductionNeverFirst(t *testing.T) {
+	for _, in := range [][]string{
+		{"app/production", "app/staging"},
+		{"a/production", "b/production", "c/staging"},
+		{"z/production", "a/staging", "a/production"},
+		{"x/live!", "y/prod!", "y/beta"},
+	} {
+		got := sortDestinations(dests(in...))
+		if isProduction(&got[0]) {
+			t.Errorf("sortDestinations(%q) starts with %s", in, got[0].Label())
+		}
+	}
+}
+
+// rowSpecs describes rows: "[svc 2]" for a header ("[svc 2 +]" collapsed),
+// and "service/name" for a destination.
+func rowSpecs(list []kamal.DeployDestination, rows []appRow) []string {
+	var out []string
+	for _, r := range rows {
+		switch {
+		case r.collapsed:
+			out = append(out, "["+r.service+" "+string(rune('0'+r.count))+" +]")
+		case r.dest < 0:
+			out = append(out, "["+r.service+" "+string(rune('0'+r.count))+"]")
+		default:
+			out = append(out, destSpecs(list[r.dest : r.dest+1])[0])
+		}
+	}
+	return out
+}
+
+func TestAppRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        []string
+		collapsed map[string]bool
+		want      []string
+	}{
+		{"one service is flat", []string{"app/staging", "app/production"}, nil, []string{"app/staging", "app/production"}},
+		{"one service ignores collapse", []string{"app/staging"}, map[string]bool{"app": true}, []string{"app/staging"}},
+		{"grouped", []string{"api/staging", "api/production", "web/staging"}, nil,
+			[]string{"[api 2]", "api/staging", "api/production", "[web 1]", "web/staging"}},
+		{"collapsed", []string{"api/staging", "api/production", "web/staging"}, map[string]bool{"api": true},
+			[]string{"[api 2 +]", "[web 1]", "web/staging"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := dests(tt.in...)
+			got := rowSpecs(list, appRows(list, tt.collapsed))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appRows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppsSelectionAcrossCollapse(t *testing.T) {
+	gui := &GUI{screen: ScreenApps}
+	gui.destinations = sortDestinations(dests("web/staging", "api/production", "api/staging"))
+	// api/staging, api/production, web/staging
+	gui.selectDestination("api/config/deploy.production.yml")
+	if gui.selectedApp != 1 || gui.selection() != 1 {
+		t.Fatalf("selectedApp = %d, selection = %d; want 1, 1", gui.selectedApp, gui.selection())
+	}
+
+	// ← collapses api; the header stands for the selected destination.
+	_ = gui.keyAppGroup(false)(nil, nil)
+	if !gui.collapsedApps["api"] || gui.selectedApp != 1 || gui.selection() != 0 {
+		t.Fatalf("after collapse: collapsed %v, selectedApp %d, selection %d", gui.collapsedApps, gui.selectedApp, gui.selection())
+	}
+	if last, _ := gui.menuLast(); last != 1 {
+		t.Errorf("menuLast = %d with api collapsed, want 1", last)
+	}
+	gui.setSelection(1)
+	if d := gui.selectedDestination(); d.ConfigPath != "web/config/deploy.staging.yml" {
+		t.Errorf("down from the header selected %s", d.ConfigPath)
+	}
+	gui.setSelection(0)
+	if d := gui.selectedDestination(); d.ConfigPath != "api/config/deploy.staging.yml" {
+		t.Errorf("up onto the collapsed header selected %s, want the group's first", d.ConfigPath)
+	}
+
+	// Selecting by name inside a collapsed group keeps it collapsed.
+	gui.selectDestination("api/config/deploy.production.yml")
+	if gui.selection() != 0 || !gui.collapsedApps["api"] {
+		t.Errorf("selection = %d, collapsed %v", gui.selection(), gui.collapsedApps)
+	}
+
+	// Enter on the collapsed header expands it instead of opening the menu.
+	_ = gui.keyEnter(nil, nil)
+	if gui.screen != ScreenApps || gui.collapsedApps["api"] || gui.selection() != 1 {
+		t.Errorf("Enter on a collapsed group: screen %v, collapsed %v, selection %d", gui.screen, gui.collapsedApps, gui.selection())
+	}
+	_ = gui.keyAppGroup(false)(nil, nil)
+	_ = gui.keyAppGroup(true)(nil, nil)
+	if gui.collapsedApps["api"] || gui.selectedApp != 1 {
+		t.Errorf("→ did not expand: collapsed %v, selectedApp %d", gui.collapsedApps, gui.selectedApp)
+	}
+}
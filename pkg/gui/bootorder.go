@@ -0,0 +1,135 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// bootApp is App Boot with accessory awareness: it checks which of the
+// accessories the app depends on are down and offers to boot them first,
+// in dependency order. Without accessories it is a plain `kamal app boot`.
+func (gui *GUI) bootApp() {
+	opts := gui.commandOpts([]string{"app", "boot"})
+	plain := func(stopCh <-chan struct{}) (kamal.Result, error) {
+		return kamal.RunKamalWithStop([]string{"app", "boot"}, opts, stopCh)
+	}
+	dest := gui.selectedDestination()
+	if dest == nil || len(dest.Accessories()) == 0 {
+		gui.runCommand("App Boot", plain)
+		return
+	}
+	accessories := dest.Accessories()
+	deps, unknown := kamal.AccessoryDependencies(accessories, gui.projectConfig().DependsOn)
+	for _, name := range unknown {
+		gui.appendLog([]string{statusLine("warning", fmt.Sprintf("%s: depends_on names %q, which is not an accessory of %s (ignored)", kamal.ProjectConfigFile, name, dest.Label()))})
+	}
+	order, err := kamal.BootOrder(deps, []string{kamal.AppNode})
+	if err != nil {
+		gui.logError("App Boot: " + err.Error() + " in " + kamal.ProjectConfigFile)
+		return
+	}
+	needed := order[:len(order)-1] // the app is last
+
+	gui.cmdMu.Lock()
+	if gui.bootChecking {
+		gui.cmdMu.Unlock()
+		return
+	}
+	gui.bootChecking = true
+	gui.cmdMu.Unlock()
+	gui.logInfo("Checking accessories before App Boot…")
+	go func() {
+		res, err := kamal.RunKamalWithStop([]string{"accessory", "details", "all"}, opts, nil)
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.cmdMu.Lock()
+			gui.bootChecking = false
+			busy := gui.running
+			gui.cmdMu.Unlock()
+			if busy {
+				gui.logInfo("App Boot skipped: another command is running")
+				return nil
+			}
+			if err != nil || res.ExitCode != 0 {
+				gui.appendLog([]string{statusLine("warning", "Could not check accessories; booting the app alone")})
+				gui.runCommand("App Boot", plain)
+				return nil
+			}
+			missing := missingAccessories(needed, kamal.RunningAccessories(res.Stdout, dest.Service, accessories))
+			if len(missing) == 0 {
+				gui.runCommand("App Boot", plain)
+				return nil
+			}
+			gui.confirmBootChain(missing, opts, plain)
+			return nil
+		})
+	}()
+}
+
+// confirmBootChain asks whether to boot the missing accessories before the
+// app. No (Enter on it, or n) boots the app alone with plain; Esc cancels.
+func (gui *GUI) confirmBootChain(missing []string, opts kamal.RunOptions, plain func(stopCh <-chan struct{}) (kamal.Result, error)) {
+	gui.appendLog([]string{statusLine("warning", "Accessories not running: "+strings.Join(missing, ", "))})
+	gui.prevScreen = gui.screen
+	gui.showConfirm("Boot accessories first?", bootConfirmMessage(missing), func() {
+		gui.runCommand("App Boot (with accessories)", gui.bootChain(missing, opts))
+	}, func() {
+		gui.runCommand("App Boot", plain)
+	})
+}
+
+// missingAccessories keeps the names in order that are not running.
+func missingAccessories(order []string, running map[string]bool) []string {
+	var missing []string
+	for _, name := range order {
+		if !running[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+func bootConfirmMessage(missing []string) string {
+	return fmt.Sprintf("%s not running. Boot in order %s → app?\nNo boots the app alone; Esc cancels.",
+		strings.Join(missing, ", "), strings.Join(missing, " → "))
+}
+
+// bootChain boots each accessory and then the app, streaming the output
+// and stopping at the first step that fails.
+func (gui *GUI) bootChain(accessories []string, opts kamal.RunOptions) func(stopCh <-chan struct{}) (kamal.Result, error) {
+	return func(stopCh <-chan struct{}) (kamal.Result, error) {
+		steps := make([][]string, 0, len(accessories)+1)
+		for _, a := range accessories {
+			steps = append(steps, []string{"accessory", "boot", a})
+		}
+		steps = append(steps, []string{"app", "boot"})
+		for _, step := range steps {
+			label := strings.Join(step, " ")
+			gui.logInfo("→ kamal " + label)
+			start := time.Now()
+			err := kamal.RunKamalStream(step, opts, func(line string) {
+				gui.appendLog([]string{"  " + cleanOutput(line, gui.ansi)})
+				gui.g.Update(func(*gocui.Gui) error { return nil })
+			}, stopCh)
+			select {
+			case <-stopCh:
+				return kamal.Result{ExitCode: -1}, fmt.Errorf("cancelled during %s", label)
+			default:
+			}
+			var exitErr *kamal.ExitError
+			if errors.As(err, &exitErr) {
+				gui.logError(fmt.Sprintf("%s failed; remaining steps skipped", label))
+				return kamal.Result{ExitCode: exitErr.Code}, nil
+			}
+			if err != nil {
+				return kamal.Result{}, err
+			}
+			gui.appendLog([]string{dim(fmt.Sprintf("  %s done in %s", label, formatDuration(time.Since(start))))})
+		}
+		return kamal.Result{}, nil
+	}
+}
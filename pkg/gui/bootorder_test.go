@@ -0,0 +1,51 @@
+package gui
+
+import (
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestMissingAccessories(t *testing.T) {
+	order := []string{"db", "redis", "search"}
+	got := missingAccessories(order, map[string]bool{"redis": true})
+	if want := []string{"db", "search"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("missingAccessories() = %v, want %v", got, want)
+	}
+	if got := missingAccessories(order, map[string]bool{"db": true, "redis": true, "search": true}); got != nil {
+		t.Errorf("missingAccessories(all running) = %v, want nil", got)
+	}
+}
+
+func TestBootConfirmMessage(t *testing.T) {
+	msg := bootConfirmMessage([]string{"db", "search"})
+	if !strings.Contains(msg, "db → search → app") {
+		t.Errorf("bootConfirmMessage() = %q, want the boot order", msg)
+	}
+}
+
+func TestBootConfirmKeys(t *testing.T) {
+	for _, tt := range []struct {
+		key   interface{}
+		plain bool
+	}{
+		{'n', true},           // boots the app alone, as the message says
+		{gocui.KeyEsc, false}, // cancels
+	} {
+		gui := newFakeGUI(t, &kamal.FakeRunner{})
+		var booted atomic.Bool
+		gui.confirmBootChain([]string{"db"}, kamal.RunOptions{}, func(<-chan struct{}) (kamal.Result, error) {
+			booted.Store(true)
+			return kamal.Result{}, nil
+		})
+		pressConfirmKey(t, gui, tt.key)
+		waitIdle(t, gui)
+		if booted.Load() != tt.plain || gui.confirm != nil {
+			t.Errorf("%v: booted the app alone = %v, want %v (dialog open: %v)", tt.key, booted.Load(), tt.plain, gui.confirm != nil)
+		}
+	}
+}
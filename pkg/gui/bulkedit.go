@@ -0,0 +1,150 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// bulkEditPlan is the outcome of setting one path in one deploy config.
+type bulkEditPlan struct {
+	path   string // relative to the project, for display
+	edit   kamal.FileEdit
+	change kamal.YAMLChange
+	err    error
+}
+
+func (p bulkEditPlan) changed() bool { return p.err == nil && p.change.Line > 0 }
+
+// bulkEditFiles is the base deploy.yml followed by every destination
+// overlay found on disk, hidden ones included.
+func (gui *GUI) bulkEditFiles() []string {
+	var files []string
+	seen := map[string]bool{}
+	add := func(p string) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			files = append(files, p)
+		}
+	}
+	for _, d := range gui.discovered {
+		add(d.BasePath)
+	}
+	for _, d := range gui.discovered {
+		add(d.ConfigPath)
+	}
+	return files
+}
+
+// bulkEdit asks for a dotted YAML path and a value, logs the diff each
+// deploy config would get, lets the user pick which files to change and
+// writes them together after one confirm.
+func (gui *GUI) bulkEdit() {
+	files := gui.bulkEditFiles()
+	if len(files) == 0 {
+		gui.logError("No deploy configs found")
+		return
+	}
+	in := newInput("Bulk edit", fmt.Sprintf("Dotted YAML path to set in %d deploy config(s), e.g. registry.server or env.clear.WEB_CONCURRENCY.", len(files)), "", func(path string) {
+		gui.showInput(newInput("Bulk edit", "Value for "+path+". It is written as typed when YAML reads it as one value, quoted otherwise.", "", func(value string) {
+			gui.previewBulkEdit(files, path, value)
+		}))
+	})
+	in.Validate = func(s string) error { _, err := kamal.SplitYAMLPath(s); return err }
+	gui.showInput(in)
+}
+
+// planBulkEdit computes the edit for each file without writing anything.
+func planBulkEdit(cwd string, files []string, path, value string) []bulkEditPlan {
+	plans := make([]bulkEditPlan, len(files))
+	for i, f := range files {
+		plans[i].path = f
+		if rel, err := filepath.Rel(cwd, f); err == nil && !strings.HasPrefix(rel, "..") {
+			plans[i].path = filepath.ToSlash(rel)
+		}
+		if err := validatePath(cwd, f); err != nil {
+			plans[i].err = err
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			plans[i].err = err
+			continue
+		}
+		after, change, err := kamal.SetYAMLPath(string(data), path, value)
+		plans[i].edit = kamal.FileEdit{Path: f, Before: string(data), After: after}
+		plans[i].change, plans[i].err = change, err
+	}
+	return plans
+}
+
+// bulkEditLines renders every file's diff for the log.
+func bulkEditLines(path, value string, plans []bulkEditPlan) []string {
+	lines := []string{cyan("── Bulk edit: " + path + " = " + value + " ──")}
+	for _, p := range plans {
+		switch {
+		case p.err != nil:
+			lines = append(lines, "  "+statusLine("warning", p.path+": skipped, "+p.err.Error()))
+		case !p.changed():
+			lines = append(lines, "  "+dim(p.path+": already set"))
+		default:
+			lines = append(lines, "  "+yellow(p.path)+dim(fmt.Sprintf(" @@ line %d", p.change.Line)))
+			for _, l := range p.change.Old {
+				lines = append(lines, "    "+red("- "+l))
+			}
+			for _, l := range p.change.New {
+				lines = append(lines, "    "+green("+ "+l))
+			}
+		}
+	}
+	return lines
+}
+
+// previewBulkEdit logs the diffs, then offers the changed files with all of
+// them checked. The chosen ones are written after a single confirm.
+func (gui *GUI) previewBulkEdit(files []string, path, value string) {
+	plans := planBulkEdit(gui.cwd, files, path, value)
+	gui.appendLog(bulkEditLines(path, value, plans))
+	var items []pickerItem
+	byPath := map[string]bulkEditPlan{}
+	for _, p := range plans {
+		if p.changed() {
+			byPath[p.edit.Path] = p
+			items = append(items, pickerItem{Label: p.path, Value: p.edit.Path, Checked: true})
+		}
+	}
+	if len(items) == 0 {
+		gui.logInfo("Bulk edit: nothing to change")
+		return
+	}
+	gui.showPicker(&listPicker{
+		Title:   "Bulk edit: apply to",
+		Message: "The diffs are in the log. Uncheck the files to leave out.",
+		Items:   items,
+		Multi:   true,
+		OnDone: func(values []string) {
+			if len(values) == 0 {
+				gui.logInfo("Bulk edit: no files selected")
+				return
+			}
+			var edits []kamal.FileEdit
+			var names []string
+			for _, v := range values {
+				edits = append(edits, byPath[v].edit)
+				names = append(names, byPath[v].path)
+			}
+			gui.prevScreen = gui.screen
+			gui.showConfirm("Confirm Bulk edit", fmt.Sprintf("Set %s = %s in %s?", path, value, strings.Join(names, ", ")), func() {
+				if err := kamal.WriteFileEdits(edits); err != nil {
+					gui.logError("Bulk edit failed: " + err.Error())
+					return
+				}
+				gui.logSuccess(fmt.Sprintf("Bulk edit: %s set in %d file(s)", path, len(edits)))
+				gui.refreshDestinations()
+			}, nil)
+		},
+	})
+}
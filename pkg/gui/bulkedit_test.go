@@ -0,0 +1,47 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPlanBulkEdit(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "config"), 0o755)
+	base := filepath.Join(dir, "config", "deploy.yml")
+	staging := filepath.Join(dir, "config", "deploy.staging.yml")
+	production := filepath.Join(dir, "config", "deploy.production.yml")
+	os.WriteFile(base, []byte("service: app\nregistry:\n  server: ghcr.io\n"), 0o644)
+	os.WriteFile(staging, []byte("servers:\n  - 10.0.0.1\n"), 0o644)
+	os.WriteFile(production, []byte("registry: [oops]\n"), 0o644)
+
+	plans := planBulkEdit(dir, []string{base, staging, production}, "registry.server", "registry.example.com")
+	if !plans[0].changed() || plans[0].path != "config/deploy.yml" {
+		t.Errorf("base plan = %+v", plans[0])
+	}
+	if !plans[1].changed() || !strings.HasSuffix(plans[1].edit.After, "registry:\n  server: registry.example.com\n") {
+		t.Errorf("staging plan = %+v", plans[1])
+	}
+	if plans[2].err == nil {
+		t.Errorf("production plan = %+v, want an error", plans[2])
+	}
+
+	out := stripANSI(strings.Join(bulkEditLines("registry.server", "registry.example.com", plans), "\n"))
+	for _, want := range []string{
+		"config/deploy.yml @@ line 3",
+		"- " + "  server: ghcr.io",
+		"+ " + "  server: registry.example.com",
+		"config/deploy.production.yml: skipped",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("bulkEditLines() missing %q:\n%s", want, out)
+		}
+	}
+
+	again := planBulkEdit(dir, []string{base}, "service", "app")
+	if again[0].changed() || again[0].err != nil {
+		t.Errorf("unchanged plan = %+v", again[0])
+	}
+}
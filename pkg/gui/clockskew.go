@@ -0,0 +1,212 @@
+package gui
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+const (
+	skewThreshold = 30 * time.Second // smaller differences are not reported
+	skewRecheck   = time.Hour
+	skewCommand   = "date +%s"
+	skewHint      = "  Lock, audit and log timestamps from the server are off by that much; live log lines show the local time."
+)
+
+// clockSkew estimates how far the remote clock is ahead of the local one
+// (negative when behind) from a `date +%s` reply, taking the remote time as
+// read midway between sent and received. The result is whole seconds, the
+// resolution of the reply.
+func clockSkew(sent, received time.Time, remoteEpoch int64) time.Duration {
+	local := sent.Add(received.Sub(sent) / 2)
+	return (time.Unix(remoteEpoch, 0).Sub(local)).Round(time.Second)
+}
+
+// parseEpoch finds the `date +%s` reply in command output: the last line
+// that is a bare integer, so kamal's surrounding log lines are skipped.
+func parseEpoch(output string) (int64, bool) {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if n, err := strconv.ParseInt(strings.TrimSpace(lines[i]), 10, 64); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// skewWarning describes skew for the header, or "" when it is within
+// skewThreshold.
+func skewWarning(skew time.Duration) string {
+	switch {
+	case skew >= skewThreshold:
+		return "server clock " + formatDuration(skew) + " ahead"
+	case skew <= -skewThreshold:
+		return "server clock " + formatDuration(-skew) + " behind"
+	}
+	return ""
+}
+
+var logTimestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+
+// annotateSkew appends the local-clock equivalent after the first timestamp
+// in a streamed log line when the server clock is off by skewThreshold or
+// more. Timestamps without a zone are corrected as-is.
+func annotateSkew(line string, skew time.Duration) string {
+	if skewWarning(skew) == "" {
+		return line
+	}
+	loc := logTimestamp.FindStringIndex(line)
+	if loc == nil {
+		return line
+	}
+	ts, ok := parseLogTimestamp(line[loc[0]:loc[1]])
+	if !ok {
+		return line
+	}
+	corrected := ts.Add(-skew).Format("15:04:05")
+	return line[:loc[1]] + dim(" [local "+corrected+"]") + line[loc[1]:]
+}
+
+func parseLogTimestamp(s string) (time.Time, bool) {
+	s = strings.Replace(s, " ", "T", 1)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999Z0700", "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// skewProbe holds the last clock skew measurement. key names what it was
+// measured against (the destination config in project mode) so a different
+// app is probed afresh.
+type skewProbe struct {
+	mu      sync.Mutex
+	skew    time.Duration
+	key     string
+	checked time.Time
+}
+
+// claim reports whether key is due for a measurement and, if so, marks it
+// checked so a failed or concurrent probe waits for the next recheck.
+func (p *skewProbe) claim(key string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.key == key && now.Sub(p.checked) < skewRecheck {
+		return false
+	}
+	if p.key != key {
+		p.key, p.skew = key, 0
+	}
+	p.checked = now
+	return true
+}
+
+// set records a measurement and returns the warning when it newly appeared
+// or changed, for logging.
+func (p *skewProbe) set(key string, skew time.Duration, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	prev := skewWarning(p.skew)
+	if p.key != key {
+		prev = ""
+	}
+	p.key, p.skew, p.checked = key, skew, now
+	if warn := skewWarning(skew); warn != prev {
+		return warn
+	}
+	return ""
+}
+
+// current returns the last skew measured for key.
+func (p *skewProbe) current(key string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.key != key {
+		return 0
+	}
+	return p.skew
+}
+
+// checkClockSkew measures the primary host's clock through
+// `kamal server exec` once per destination and hour, from the status poll.
+// kamal's own startup delays the command, so the receive time is used.
+func (gui *GUI) checkClockSkew(dest *kamal.DeployDestination, opts kamal.RunOptions) {
+	key := hostsKey(dest)
+	if !gui.skew.claim(key, time.Now()) {
+		return
+	}
+	opts.Primary = true
+	opts.Defaults = nil // a --verbose default would garble the epoch
+	r, err := kamal.RunKamal([]string{"server", "exec", skewCommand}, opts)
+	received := time.Now()
+	if err != nil || r.ExitCode != 0 {
+		return
+	}
+	epoch, ok := parseEpoch(r.Stdout)
+	if !ok {
+		return
+	}
+	if warn := gui.skew.set(key, clockSkew(received, received, epoch), received); warn != "" {
+		gui.appendLogFor(dest, []string{statusLine("warning", dest.Label()+": "+warn), dim(skewHint)})
+	}
+}
+
+// skewWarningFor is the header warning for the selected destination.
+func (gui *GUI) skewWarningFor() string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return ""
+	}
+	return skewWarning(gui.skew.current(hostsKey(dest)))
+}
+
+// liveSkew is the skew to annotate live log lines with.
+func (gui *GUI) liveSkew() time.Duration {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return 0
+	}
+	return gui.skew.current(hostsKey(dest))
+}
+
+// probeClockSkew measures the server clock over SSH and logs a warning when
+// it newly exceeds skewThreshold.
+func (gui *ServerGUI) probeClockSkew() {
+	sent := time.Now()
+	out, err := gui.client.RunWithTimeout(skewCommand, 10*time.Second)
+	received := time.Now()
+	if err != nil {
+		return
+	}
+	epoch, ok := parseEpoch(out)
+	if !ok {
+		return
+	}
+	if warn := gui.skew.set(gui.host, clockSkew(sent, received, epoch), received); warn != "" {
+		gui.appendLog([]string{statusLine("warning", warn), dim(skewHint)})
+	}
+}
+
+// watchClockSkew re-measures the server clock every skewRecheck while
+// connected.
+func (gui *ServerGUI) watchClockSkew() {
+	t := time.NewTicker(skewRecheck)
+	go func() {
+		defer t.Stop()
+		for {
+			select {
+			case <-gui.done:
+				return
+			case <-t.C:
+				gui.probeClockSkew()
+				gui.g.Update(func(*gocui.Gui) error { return nil })
+			}
+		}
+	}()
+}
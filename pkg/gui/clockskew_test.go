@@ -0,0 +1,105 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockSkew(t *testing.T) {
+	sent := time.Unix(1714557600, 0)
+	received := sent.Add(2 * time.Second)
+	tests := []struct {
+		remote int64
+		want   time.Duration
+	}{
+		{1714557601, 0},
+		{1714557601 + 125, 125 * time.Second},
+		{1714557601 - 45, -45 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := clockSkew(sent, received, tt.remote); got != tt.want {
+			t.Errorf("clockSkew(remote %d) = %v, want %v", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestParseEpoch(t *testing.T) {
+	out := "Running 'date +%s' on 10.0.1.5...\n  INFO [ab12] Running /usr/bin/env date +%s on 10.0.1.5\nApp Host: 10.0.1.5\n1714557605\n\n"
+	if got, ok := parseEpoch(out); !ok || got != 1714557605 {
+		t.Errorf("parseEpoch() = %d, %v", got, ok)
+	}
+	if _, ok := parseEpoch("date: command not found"); ok {
+		t.Error("parseEpoch(no number) = ok")
+	}
+}
+
+func TestSkewWarning(t *testing.T) {
+	tests := []struct {
+		skew time.Duration
+		want string
+	}{
+		{0, ""},
+		{29 * time.Second, ""},
+		{-29 * time.Second, ""},
+		{30 * time.Second, "server clock 30.0s ahead"},
+		{-125 * time.Second, "server clock 2m5s behind"},
+	}
+	for _, tt := range tests {
+		if got := skewWarning(tt.skew); got != tt.want {
+			t.Errorf("skewWarning(%v) = %q, want %q", tt.skew, got, tt.want)
+		}
+	}
+}
+
+func TestAnnotateSkew(t *testing.T) {
+	tests := []struct {
+		line string
+		skew time.Duration
+		want string // substring of the result
+	}{
+		{"2024-05-01T10:02:05Z web: GET /up", 2 * time.Minute, "2024-05-01T10:02:05Z" + dim(" [local 10:00:05]") + " web: GET /up"},
+		{"I, [2024-05-01T10:02:05.123456 #1]  INFO -- : Started", -time.Minute, "[local 10:03:05]"},
+		{"2024-05-01 10:02:05 +0000 worker done", 5 * time.Minute, "[local 09:57:05]"},
+	}
+	for _, tt := range tests {
+		if got := annotateSkew(tt.line, tt.skew); !strings.Contains(got, tt.want) {
+			t.Errorf("annotateSkew(%q, %v) = %q, want it to contain %q", tt.line, tt.skew, got, tt.want)
+		}
+	}
+	for _, line := range []string{"no timestamp here", "2024-05-01T10:02:05Z within threshold"} {
+		skew := 2 * time.Minute
+		if strings.Contains(line, "within") {
+			skew = 10 * time.Second
+		}
+		if got := annotateSkew(line, skew); got != line {
+			t.Errorf("annotateSkew(%q, %v) = %q, want unchanged", line, skew, got)
+		}
+	}
+}
+
+func TestSkewProbe(t *testing.T) {
+	var p skewProbe
+	now := time.Now()
+	if !p.claim("a", now) {
+		t.Fatal("first claim = false")
+	}
+	if p.claim("a", now.Add(time.Minute)) {
+		t.Error("claim within the recheck interval = true")
+	}
+	if warn := p.set("a", 2*time.Minute, now); warn != "server clock 2m0s ahead" {
+		t.Errorf("set() = %q, want the new warning", warn)
+	}
+	if warn := p.set("a", 2*time.Minute, now); warn != "" {
+		t.Errorf("set() repeated = %q, want no new warning", warn)
+	}
+	if p.current("b") != 0 || p.current("a") != 2*time.Minute {
+		t.Errorf("current() = %v/%v", p.current("a"), p.current("b"))
+	}
+	if !p.claim("b", now) || p.current("b") != 0 {
+		t.Error("claim for another key did not reset the probe")
+	}
+	if !p.claim("b", now.Add(skewRecheck)) {
+		t.Error("claim after the recheck interval = false")
+	}
+}
@@ -0,0 +1,75 @@
+package gui
+
+import (
+	"sync"
+	"time"
+)
+
+// suspendGap is how late a spinner tick, due every 100ms, must arrive by the
+// wall clock for the command's duration to count as unreliable: the machine
+// slept, or its clock was stepped, in between.
+const suspendGap = 30 * time.Second
+
+// durationUnreliable flags the duration of a command timed across a suspend.
+const durationUnreliable = "duration unreliable: system suspended"
+
+// cmdClock times the running command. Elapsed time comes from the monotonic
+// reading time.Now() carries, so clock steps never move the header's timer.
+// The monotonic clock stops while the machine sleeps, though, so the
+// spinner's ticks compare wall times to notice a suspend and flag the
+// duration instead of recording it as if nothing happened.
+type cmdClock struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	start     time.Time
+	last      time.Time // wall time of the last tick
+	suspended bool
+}
+
+func newCmdClock(now func() time.Time) *cmdClock {
+	start := now()
+	return &cmdClock{now: now, start: start, last: start.Round(0)}
+}
+
+// elapsed is the time since the command started, never negative.
+func (c *cmdClock) elapsed() time.Duration {
+	if c == nil {
+		return 0
+	}
+	d := c.now().Sub(c.start)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// tick is called by the spinner on every frame. Round(0) drops the
+// monotonic reading, so the gap is measured by the wall clock, which keeps
+// running through a suspend.
+func (c *cmdClock) tick() {
+	now := c.now().Round(0)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gap := now.Sub(c.last); gap > suspendGap || gap < -suspendGap {
+		c.suspended = true
+	}
+	c.last = now
+}
+
+// unreliable reports whether a suspend or clock step was seen.
+func (c *cmdClock) unreliable() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.suspended
+}
+
+// tookText is formatDuration(took), flagged when clock saw a suspend.
+func tookText(took time.Duration, unreliable bool) string {
+	if unreliable {
+		return formatDuration(took) + " (" + durationUnreliable + ")"
+	}
+	return formatDuration(took)
+}
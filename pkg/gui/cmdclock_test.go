@@ -0,0 +1,55 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock is a wall clock the test moves by hand.
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestCmdClockDetectsSuspend(t *testing.T) {
+	tests := []struct {
+		name       string
+		steps      []time.Duration // wall time between spinner ticks
+		unreliable bool
+	}{
+		{"steady ticks", []time.Duration{100 * time.Millisecond, 100 * time.Millisecond, 120 * time.Millisecond}, false},
+		{"slow but awake", []time.Duration{2 * time.Second, 5 * time.Second}, false},
+		{"laptop slept", []time.Duration{100 * time.Millisecond, 2 * time.Hour, 100 * time.Millisecond}, true},
+		{"clock stepped back", []time.Duration{100 * time.Millisecond, -10 * time.Minute}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{t: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+			c := newCmdClock(clock.now)
+			for _, step := range tt.steps {
+				clock.t = clock.t.Add(step)
+				c.tick()
+			}
+			if c.unreliable() != tt.unreliable {
+				t.Errorf("unreliable = %v, want %v", c.unreliable(), tt.unreliable)
+			}
+			if c.elapsed() < 0 {
+				t.Errorf("elapsed = %v, want never negative", c.elapsed())
+			}
+		})
+	}
+}
+
+func TestUnreliableDurationIsFlagged(t *testing.T) {
+	if got := tookText(3*time.Second, false); got != "3.0s" {
+		t.Errorf("tookText = %q", got)
+	}
+	cmd := sessionCommand{Name: "Deploy", Destination: "app (staging)", Took: 7 * time.Minute, Unreliable: true, Outcome: "ok"}
+	if got := summaryCommand(cmd); !strings.Contains(got, "7m0s ("+durationUnreliable+")") {
+		t.Errorf("summaryCommand = %q, want the duration flagged", got)
+	}
+	e := historyEntry{Name: "Deploy", DurationMs: 1000, Unreliable: true}
+	if got := stripANSI(e.summary()); !strings.Contains(got, durationUnreliable) {
+		t.Errorf("history row = %q, want the duration flagged", got)
+	}
+}
@@ -0,0 +1,279 @@
+package gui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+const (
+	historyShown       = 50   // entries on the History screen, and kept in memory
+	historyOutputLines = 500  // output lines kept per entry, newest last
+	defaultHistoryMax  = 1000 // entries left in the file after pruning
+)
+
+// historyEntry is one command run from project mode. It is a JSON line in
+// the history file; the output is only kept in memory, for this session.
+type historyEntry struct {
+	Name        string    `json:"name"`
+	Project     string    `json:"project"`
+	Destination string    `json:"destination,omitempty"`
+	Start       time.Time `json:"start"`
+	DurationMs  int64     `json:"duration_ms"`
+	ExitCode    int       `json:"exit_code"`                     // -1 when it did not exit by itself
+	Error       string    `json:"error,omitempty"`               // first error line, or "cancelled"
+	Unreliable  bool      `json:"duration_unreliable,omitempty"` // timed across a suspend
+	output      []string
+}
+
+// newHistoryEntry is the entry for a command that ended with res and err,
+// outcome as sessionOutcome has it; output is what it printed, as logged.
+func newHistoryEntry(name, dest string, start time.Time, took time.Duration, res kamal.Result, err error, outcome string, output []string) historyEntry {
+	e := historyEntry{Name: name, Destination: dest, Start: start, DurationMs: took.Milliseconds(), ExitCode: res.ExitCode, output: output}
+	if err != nil {
+		e.ExitCode = -1
+	}
+	switch outcome {
+	case "ok":
+	case "cancelled":
+		e.Error = outcome
+	default:
+		e.Error = sanitizeLogLine(firstErrorLine(res, err))
+	}
+	if e.output == nil {
+		e.output = []string{}
+	}
+	return e
+}
+
+func (e historyEntry) ok() bool { return e.ExitCode == 0 && e.Error == "" }
+
+// summary is the entry as a row of the History screen.
+func (e historyEntry) summary() string {
+	icon := green(iconSuccess)
+	switch {
+	case e.Error == "cancelled":
+		icon = yellow(iconWarning)
+	case !e.ok():
+		icon = red(iconError)
+	}
+	line := icon + " " + e.Start.Local().Format("Jan 02 15:04") + "  " + e.Name
+	if e.Destination != "" {
+		line += "  " + e.Destination
+	}
+	line += dim("  " + tookText(time.Duration(e.DurationMs)*time.Millisecond, e.Unreliable))
+	if !e.ok() && e.Error != "cancelled" {
+		status := "exit " + strconv.Itoa(e.ExitCode)
+		if e.ExitCode < 0 {
+			status = "error"
+		}
+		line += "  " + red(status) + dim(": "+truncate(e.Error, 60))
+	}
+	return line
+}
+
+// commandHistory is every command run from project mode: the last
+// historyShown of this project in memory, with their output while this
+// session lasts, and all of them appended to a JSON lines file shared by
+// every project, best effort.
+type commandHistory struct {
+	mu      sync.Mutex
+	path    string
+	project string
+	entries []historyEntry // oldest first
+}
+
+// defaultCommandHistoryPath is history.jsonl in lazykamal's directory under
+// $XDG_CONFIG_HOME (~/.config), or "" when there is no home directory.
+func defaultCommandHistoryPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "lazykamal", "history.jsonl")
+}
+
+// historyMaxFromEnv reads LAZYKAMAL_HISTORY_MAX, the number of entries
+// kept in the history file; 0 keeps them all.
+func historyMaxFromEnv() int {
+	n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("LAZYKAMAL_HISTORY_MAX")))
+	if err != nil || n < 0 {
+		return defaultHistoryMax
+	}
+	return n
+}
+
+// loadCommandHistory prunes the file at path to its last max entries (all
+// of them when max is 0) and reads the recent ones of project. A missing
+// file starts an empty history; lines it cannot read are skipped.
+func loadCommandHistory(path, project string, max int) *commandHistory {
+	h := &commandHistory{path: path, project: project}
+	if path == "" {
+		return h
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h
+	}
+	entries := parseHistory(data)
+	if max > 0 && len(entries) > max {
+		entries = entries[len(entries)-max:]
+		_ = writeHistory(path, entries)
+	}
+	for _, e := range entries {
+		if e.Project == project {
+			h.entries = append(h.entries, e)
+		}
+	}
+	if len(h.entries) > historyShown {
+		h.entries = h.entries[len(h.entries)-historyShown:]
+	}
+	return h
+}
+
+// parseHistory decodes JSON lines, skipping blank and malformed ones: the
+// file is appended to by every session and may end in a torn line.
+func parseHistory(data []byte) []historyEntry {
+	var out []historyEntry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		var e historyEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && e.Name != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func marshalHistory(entries []historyEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeHistory replaces the file at path with entries, through a temporary
+// file so a crash leaves the old one whole.
+func writeHistory(path string, entries []historyEntry) error {
+	data, err := marshalHistory(entries)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// add records e, keeping the end of its output, and appends it to the
+// file. A nil history records nothing.
+func (h *commandHistory) add(e historyEntry) error {
+	if h == nil {
+		return nil
+	}
+	if len(e.output) > historyOutputLines {
+		e.output = e.output[len(e.output)-historyOutputLines:]
+	}
+	e.Project = h.project
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries = append(h.entries, e)
+	if len(h.entries) > historyShown {
+		h.entries = h.entries[len(h.entries)-historyShown:]
+	}
+	if h.path == "" {
+		return nil
+	}
+	data, err := marshalHistory([]historyEntry{e})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (h *commandHistory) snapshot() []historyEntry {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]historyEntry(nil), h.entries...)
+}
+
+// showHistory lists the project's recent commands, newest first. Enter
+// logs an entry's output when this session still has it.
+func (gui *GUI) showHistory() {
+	entries := gui.commands.snapshot()
+	if len(entries) == 0 {
+		gui.logInfo("History: no command run from lazykamal in this project yet")
+		return
+	}
+	items := make([]pickerItem, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		items = append(items, pickerItem{Label: entries[i].summary(), Value: strconv.Itoa(i)})
+	}
+	gui.showPicker(&listPicker{
+		Title:   fmt.Sprintf("History (%d)", len(entries)),
+		Message: "Commands run in this project, newest first. Enter logs one's output.",
+		Items:   items,
+		OnDone: func(values []string) {
+			if len(values) != 1 {
+				return
+			}
+			i, err := strconv.Atoi(values[0])
+			if err != nil || i >= len(entries) {
+				return
+			}
+			gui.logHistoryOutput(entries[i])
+		},
+	})
+}
+
+// logHistoryOutput appends e's output to the log under a heading.
+func (gui *GUI) logHistoryOutput(e historyEntry) {
+	title := e.Name
+	if e.Destination != "" {
+		title += " on " + e.Destination
+	}
+	title += ", " + e.Start.Local().Format("Jan 02 15:04:05")
+	if e.output == nil {
+		gui.logInfo("The output of " + title + " is no longer buffered")
+		return
+	}
+	lines := append([]string{cyan(glyphs("── History: " + title + " ──"))}, e.output...)
+	if len(e.output) == 0 {
+		lines = append(lines, dim("  (no output)"))
+	}
+	gui.appendLog(lines)
+}
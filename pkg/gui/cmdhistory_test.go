@@ -0,0 +1,150 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestNewHistoryEntry(t *testing.T) {
+	start := time.Date(2026, 3, 4, 10, 11, 12, 0, time.UTC)
+	tests := []struct {
+		name     string
+		res      kamal.Result
+		err      error
+		outcome  string
+		wantExit int
+		wantErr  string
+	}{
+		{"ok", kamal.Result{Stdout: "done"}, nil, "ok", 0, ""},
+		{"exit", kamal.Result{Stderr: "\n  ERROR: lock held\nmore", ExitCode: 1}, nil, "exit 1", 1, "ERROR: lock held"},
+		{"error", kamal.Result{}, errors.New("kamal not found"), "error", -1, "kamal not found"},
+		{"cancelled", kamal.Result{ExitCode: -1}, nil, "cancelled", -1, "cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newHistoryEntry("Deploy", "myapp (staging)", start, 61*time.Second, tt.res, tt.err, tt.outcome, nil)
+			if e.ExitCode != tt.wantExit || e.Error != tt.wantErr {
+				t.Errorf("exit %d, error %q; want %d, %q", e.ExitCode, e.Error, tt.wantExit, tt.wantErr)
+			}
+			if e.DurationMs != 61000 || !e.Start.Equal(start) || e.Destination != "myapp (staging)" {
+				t.Errorf("entry = %+v", e)
+			}
+			if e.output == nil {
+				t.Error("captured output is nil, as if no longer buffered")
+			}
+		})
+	}
+}
+
+func TestHistorySummary(t *testing.T) {
+	plainStyle(t)
+	at := time.Date(2026, 3, 4, 10, 11, 12, 0, time.Local)
+	tests := []struct {
+		entry historyEntry
+		want  []string
+	}{
+		{historyEntry{Name: "Deploy", Destination: "myapp (staging)", Start: at, DurationMs: 61000}, []string{"[OK]", "Mar 04 10:11", "Deploy", "myapp (staging)"}},
+		{historyEntry{Name: "App Stop", Start: at, ExitCode: 1, Error: "ERROR: lock held"}, []string{"[ERR]", "exit 1: ERROR: lock held"}},
+		{historyEntry{Name: "Deploy", Start: at, ExitCode: -1, Error: "cancelled"}, []string{"! Mar 04", "Deploy"}},
+	}
+	for _, tt := range tests {
+		got := tt.entry.summary()
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("summary %q lacks %q", got, want)
+			}
+		}
+	}
+}
+
+func TestCommandHistoryAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lazykamal", "history.jsonl")
+	h := loadCommandHistory(path, "/srv/app", 0)
+	long := make([]string, historyOutputLines+10)
+	for i := range long {
+		long[i] = fmt.Sprint(i)
+	}
+	for i := 0; i < historyShown+5; i++ {
+		if err := h.add(historyEntry{Name: fmt.Sprint("cmd ", i), output: long}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries := h.snapshot()
+	if len(entries) != historyShown || entries[0].Name != "cmd 5" {
+		t.Fatalf("kept %d entries from %q, want the last %d", len(entries), entries[0].Name, historyShown)
+	}
+	if out := entries[0].output; len(out) != historyOutputLines || out[len(out)-1] != long[len(long)-1] {
+		t.Errorf("kept %d output lines ending %q, want the last %d", len(out), out[len(out)-1], historyOutputLines)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != historyShown+5 {
+		t.Errorf("file has %d lines, want every entry", n)
+	}
+	if strings.Contains(string(data), `"output"`) || !strings.Contains(string(data), `"project":"/srv/app"`) {
+		t.Errorf("unexpected encoding:\n%s", strings.SplitN(string(data), "\n", 2)[0])
+	}
+}
+
+func TestLoadCommandHistoryPrunes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	var entries []historyEntry
+	for i := 0; i < 8; i++ {
+		project := "/srv/app"
+		if i%2 == 1 {
+			project = "/srv/other"
+		}
+		entries = append(entries, historyEntry{Name: fmt.Sprint("cmd ", i), Project: project})
+	}
+	data, err := marshalHistory(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A session that crashed mid-write leaves a torn last line.
+	if err := os.WriteFile(path, append(data, `{"name":"torn`...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := loadCommandHistory(path, "/srv/app", 5)
+	var names []string
+	for _, e := range h.snapshot() {
+		names = append(names, e.Name)
+		if e.output != nil {
+			t.Errorf("%s from the file has output", e.Name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "cmd 4,cmd 6" {
+		t.Errorf("loaded %s, want this project's entries among the last 5", got)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left := parseHistory(data)
+	if len(left) != 5 || left[0].Name != "cmd 3" {
+		t.Errorf("file pruned to %d entries from %+v, want the last 5", len(left), left)
+	}
+
+	if h := loadCommandHistory(filepath.Join(t.TempDir(), "missing.jsonl"), "/srv/app", 5); len(h.snapshot()) != 0 {
+		t.Error("a missing file loaded entries")
+	}
+}
+
+func TestHistoryMaxFromEnv(t *testing.T) {
+	for value, want := range map[string]int{"": defaultHistoryMax, "200": 200, "0": 0, "-1": defaultHistoryMax, "many": defaultHistoryMax} {
+		t.Setenv("LAZYKAMAL_HISTORY_MAX", value)
+		if got := historyMaxFromEnv(); got != want {
+			t.Errorf("LAZYKAMAL_HISTORY_MAX=%q: %d, want %d", value, got, want)
+		}
+	}
+}
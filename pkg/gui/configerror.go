@@ -0,0 +1,59 @@
+package gui
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// configErrorLine finds the line number in a YAML error, e.g. "yaml: line 3:
+// did not find expected key".
+var configErrorLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// configErrorText is dest's config error with the file relative to cwd, or
+// "" when its config loaded.
+func configErrorText(cwd string, dest *kamal.DeployDestination) string {
+	if dest == nil || dest.ConfigErr == nil {
+		return ""
+	}
+	path := dest.ConfigErr.Path
+	if rel, err := filepath.Rel(cwd, path); err == nil && !strings.HasPrefix(rel, "..") {
+		path = filepath.ToSlash(rel)
+	}
+	return path + ": " + dest.ConfigErr.Err.Error()
+}
+
+// refuseBrokenConfig logs why nothing runs against dest and reports whether
+// its config is broken.
+func (gui *GUI) refuseBrokenConfig(dest *kamal.DeployDestination) bool {
+	if dest == nil || dest.ConfigErr == nil {
+		return false
+	}
+	gui.logError("Config error, commands are disabled for " + dest.Label() + ": " + configErrorText(gui.cwd, dest))
+	return true
+}
+
+// editBrokenConfig opens the file dest's config error names in the editor,
+// at the line the error mentions. Saving and leaving the editor reloads the
+// destinations, which clears the error once the file parses.
+func (gui *GUI) editBrokenConfig(dest *kamal.DeployDestination) {
+	path := dest.ConfigErr.Path
+	if err := validatePath(gui.cwd, path); err != nil {
+		gui.logError("Security: " + err.Error())
+		return
+	}
+	if !gui.openEditor(path) {
+		return
+	}
+	if m := configErrorLine.FindStringSubmatch(dest.ConfigErr.Err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		e := gui.editor
+		e.Row = clampIndex(n-1, len(e.Lines)-1)
+		e.centerOn(e.Row)
+	}
+	gui.appendLog([]string{red(configErrorText(gui.cwd, dest))})
+	gui.appendLog([]string{"Editing " + path + " (^S save, ^Q/Esc quit)"})
+}
@@ -0,0 +1,87 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// withBrokenDestination adds config/deploy.broken.yml, which does not parse
+// on line 3, to the demo project and selects it.
+func withBrokenDestination(t *testing.T, gui *GUI) string {
+	t.Helper()
+	path := filepath.Join(gui.cwd, "config", "deploy.broken.yml")
+	if err := os.WriteFile(path, []byte("servers:\n  web:\n    - [10.0.3.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gui.loadDestinations()
+	gui.selectDestination(path)
+	return path
+}
+
+func TestBrokenConfigIsListed(t *testing.T) {
+	gui := newFakeGUI(t, &kamal.FakeRunner{})
+	withBrokenDestination(t, gui)
+	var names []string
+	for _, d := range gui.destinations {
+		names = append(names, d.Name)
+	}
+	dest := gui.selectedDestination()
+	if dest == nil || dest.Name != "broken" || dest.ConfigErr == nil || len(names) != 3 {
+		t.Fatalf("destinations %q, selected %+v; want broken listed with its error beside the others", names, dest)
+	}
+	if got := configErrorText(gui.cwd, dest); !strings.HasPrefix(got, "config/deploy.broken.yml: yaml: line ") {
+		t.Errorf("configErrorText = %q", got)
+	}
+}
+
+func TestBrokenConfigRunsNothing(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	withBrokenDestination(t, gui)
+
+	ran := false
+	gui.startCommand("App Details", true, func(<-chan struct{}) (kamal.Result, error) {
+		ran = true
+		return kamal.Result{}, nil
+	}, nil)
+	waitIdle(t, gui)
+	gui.refreshStatus()
+	_ = gui.keyMain(nil, nil)
+	if ran || len(runner.Calls()) != 0 || gui.screen != ScreenApps {
+		t.Errorf("ran %v, kamal calls %q, screen %v; want nothing run from the Apps list", ran, runner.Calls(), gui.screen)
+	}
+	if log := plainLog(gui); !strings.Contains(log, "Config error, commands are disabled for demo (broken)") {
+		t.Errorf("Output does not explain why:\n%s", log)
+	}
+}
+
+func TestEnterEditsBrokenConfig(t *testing.T) {
+	gui := newFakeGUI(t, &kamal.FakeRunner{})
+	path := withBrokenDestination(t, gui)
+	m := configErrorLine.FindStringSubmatch(gui.selectedDestination().ConfigErr.Error())
+	if m == nil {
+		t.Fatalf("no line in %v", gui.selectedDestination().ConfigErr)
+	}
+
+	_ = gui.keyEnter(nil, nil)
+	if gui.screen != ScreenEditor || gui.editor == nil || gui.editor.Path != path {
+		t.Fatalf("Enter on a broken destination: screen %v, editor %+v", gui.screen, gui.editor)
+	}
+	if want := m[1]; strconv.Itoa(gui.editor.Row+1) != want {
+		t.Errorf("editor opened on line %d, want the error's line %s", gui.editor.Row+1, want)
+	}
+
+	gui.editor.Lines[2] = "    - 10.0.3.1"
+	if !gui.editorSave() {
+		t.Fatal("the fixed file was not saved")
+	}
+	gui.editorQuit()
+	if dest := gui.selectedDestination(); gui.screen != ScreenApps || dest == nil || dest.Name != "broken" || dest.ConfigErr != nil {
+		t.Errorf("after the fix: screen %v, selected %+v", gui.screen, dest)
+	}
+}
@@ -2,34 +2,125 @@ package gui
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/events"
 )
 
 const viewConfirm = "confirm"
 
-// ConfirmDialog state
+// confirmState is the yes/no dialog shared by project and server mode.
+// With RequireText set, Yes stays disabled until that text is typed into
+// the dialog's field; y and n are then typed rather than answering.
 type confirmState struct {
-	Title    string
-	Message  string
-	OnYes    func()
-	OnNo     func()
-	Selected int // 0 = Yes, 1 = No
+	Title       string
+	Message     string
+	OnYes       func()
+	OnNo        func()
+	OnClose     func(confirmAnswer) // runs last however the dialog closes, e.g. to clear what it left shown
+	Selected    int                 // 0 = Yes, 1 = No
+	RequireText string
+	typed       []rune // discarded with the dialog
 }
 
-func (gui *GUI) showConfirm(title, message string, onYes, onNo func()) {
-	gui.confirm = &confirmState{
-		Title:    title,
-		Message:  message,
-		OnYes:    onYes,
-		OnNo:     onNo,
-		Selected: 1, // Default to "No" for safety
+// confirmAnswer is how a confirm dialog was closed.
+type confirmAnswer int
+
+const (
+	confirmNo confirmAnswer = iota
+	confirmYes
+	confirmDismissed // Esc, or n without an OnNo: closed without running OnNo
+)
+
+func (a confirmAnswer) String() string {
+	switch a {
+	case confirmYes:
+		return "yes"
+	case confirmDismissed:
+		return "no (dismissed)"
+	default:
+		return "no"
 	}
-	gui.screen = ScreenConfirm
 }
 
-func (gui *GUI) renderConfirmDialog(g *gocui.Gui) error {
-	if gui.confirm == nil {
+// confirmDecision records one answered confirm dialog.
+type confirmDecision struct {
+	Title  string
+	Answer confirmAnswer
+	Via    string // key that answered, e.g. "y" or "enter"
+}
+
+func (d confirmDecision) String() string {
+	return fmt.Sprintf("CONFIRM: %s → %s (selected via %s)", d.Title, d.Answer, d.Via)
+}
+
+// debugEnabled reports whether LAZYKAMAL_DEBUG is set, which adds
+// diagnostics such as confirm decisions to the visible log.
+func debugEnabled() bool {
+	return os.Getenv("LAZYKAMAL_DEBUG") != ""
+}
+
+// newConfirm returns a dialog with "No" selected for safety.
+func newConfirm(title, message string, onYes, onNo func()) *confirmState {
+	return &confirmState{Title: title, Message: message, OnYes: onYes, OnNo: onNo, Selected: 1}
+}
+
+func (c *confirmState) move(delta int) {
+	c.Selected += delta
+	if c.Selected < 0 {
+		c.Selected = 0
+	}
+	if c.Selected > 1 {
+		c.Selected = 1
+	}
+}
+
+// selectedAnswer is what Enter answers with.
+func (c *confirmState) selectedAnswer() confirmAnswer {
+	if c.Selected == 0 {
+		return confirmYes
+	}
+	return confirmNo
+}
+
+// yesEnabled reports whether Yes can be answered: always, unless the
+// required text has not been typed exactly.
+func (c *confirmState) yesEnabled() bool {
+	return c.RequireText == "" || string(c.typed) == c.RequireText
+}
+
+// typeRune adds r to the typed text, up to the length of the required one.
+func (c *confirmState) typeRune(r rune) {
+	if len(c.typed) < len([]rune(c.RequireText)) {
+		c.typed = append(c.typed, r)
+	}
+}
+
+func (c *confirmState) backspace() {
+	if len(c.typed) > 0 {
+		c.typed = c.typed[:len(c.typed)-1]
+	}
+}
+
+// resolve runs the callback for answer and returns the decision. Every
+// confirm dialog in both modes is answered through here.
+func (c *confirmState) resolve(answer confirmAnswer, via string) confirmDecision {
+	switch {
+	case answer == confirmYes && c.OnYes != nil:
+		c.OnYes()
+	case answer == confirmNo && c.OnNo != nil:
+		c.OnNo()
+	}
+	if c.OnClose != nil {
+		c.OnClose(answer)
+	}
+	return confirmDecision{Title: c.Title, Answer: answer, Via: via}
+}
+
+// renderConfirm draws c centered in view.
+func renderConfirm(g *gocui.Gui, view string, c *confirmState) error {
+	if c == nil {
 		return nil
 	}
 
@@ -37,41 +128,56 @@ func (gui *GUI) renderConfirmDialog(g *gocui.Gui) error {
 
 	// Dialog dimensions
 	width := 50
-	height := 7
 	if width > maxX-4 {
 		width = maxX - 4
 	}
+	msgLines := wrapText(glyphs(c.Message), width-3)
+	height := 6 + len(msgLines)
+	if c.RequireText != "" {
+		height += 3
+	}
+	r := centeredRect(maxX, maxY, width, height, 4, 2)
+	if !r.valid() {
+		return nil
+	}
 
-	x0 := (maxX - width) / 2
-	y0 := (maxY - height) / 2
-	x1 := x0 + width
-	y1 := y0 + height
-
-	if v, err := g.SetView(viewConfirm, x0, y0, x1, y1); err != nil {
+	if v, err := setView(g, view, r); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
 		v.Frame = true
-		v.Title = " " + gui.confirm.Title + " "
 		v.FgColor = gocui.ColorWhite
 	}
 
-	v, _ := g.View(viewConfirm)
+	v, _ := g.View(view)
 	if v == nil {
 		return nil
 	}
+	v.Title = " " + c.Title + " "
 	v.Clear()
 
 	// Message
 	fmt.Fprintln(v)
-	fmt.Fprintf(v, " %s\n", gui.confirm.Message)
+	for _, l := range msgLines {
+		fmt.Fprintf(v, " %s\n", l)
+	}
+	if c.RequireText != "" {
+		fmt.Fprintln(v)
+		fmt.Fprintf(v, " Type %s to enable Yes:\n", bold(c.RequireText))
+		fmt.Fprintf(v, " %s %s_\n", cyan(iconArrow), string(c.typed))
+	}
 	fmt.Fprintln(v)
 
 	// Buttons
 	yesStyle := "  [ Yes ]  "
 	noStyle := "  [ No ]  "
 
-	if gui.confirm.Selected == 0 {
+	if !c.yesEnabled() {
+		yesStyle = "  " + dim("[ Yes ]") + "  "
+		if c.Selected == 0 {
+			yesStyle = " " + cyan(iconArrow) + dim("[ Yes ]") + "  "
+		}
+	} else if c.Selected == 0 {
 		yesStyle = " " + cyan(iconArrow) + green("[ Yes ]") + "  "
 	} else {
 		noStyle = " " + cyan(iconArrow) + red("[ No ]") + "  "
@@ -79,34 +185,112 @@ func (gui *GUI) renderConfirmDialog(g *gocui.Gui) error {
 
 	fmt.Fprintf(v, "       %s    %s\n", yesStyle, noStyle)
 
-	g.SetCurrentView(viewConfirm)
+	g.SetCurrentView(view)
 	return nil
 }
 
-func (gui *GUI) confirmLeft() {
-	if gui.confirm != nil && gui.confirm.Selected > 0 {
-		gui.confirm.Selected--
+// confirmKey is one key of a confirm dialog and what it does.
+type confirmKey struct {
+	key interface{}
+	fn  func(*gocui.Gui, *gocui.View) error
+}
+
+// confirmKeys are the dialog keys: ←/→ move, Enter answers the selection,
+// y answers yes, n answers no where the dialog has an OnNo (else it
+// dismisses, as Esc always does). In a dialog with a required text,
+// characters and Backspace edit the field instead and only Esc dismisses;
+// Yes answers once the text matches. current returns the open dialog (nil
+// when none) and answer closes it.
+func confirmKeys(current func() *confirmState, answer func(confirmAnswer, string)) []confirmKey {
+	on := func(fn func(c *confirmState)) func(*gocui.Gui, *gocui.View) error {
+		return func(*gocui.Gui, *gocui.View) error {
+			if c := current(); c != nil {
+				fn(c)
+			}
+			return nil
+		}
+	}
+	keys := []confirmKey{
+		{gocui.KeyArrowLeft, on(func(c *confirmState) { c.move(-1) })},
+		{gocui.KeyArrowRight, on(func(c *confirmState) { c.move(1) })},
+		{gocui.KeyEnter, on(func(c *confirmState) {
+			if a := c.selectedAnswer(); a != confirmYes || c.yesEnabled() {
+				answer(a, "enter")
+			}
+		})},
+		{gocui.KeyEsc, on(func(*confirmState) { answer(confirmDismissed, "esc") })},
+		{gocui.KeyBackspace, on(func(c *confirmState) { c.backspace() })},
+		{gocui.KeyBackspace2, on(func(c *confirmState) { c.backspace() })},
+	}
+	for r := rune(33); r < 127; r++ {
+		r := r
+		keys = append(keys, confirmKey{r, on(func(c *confirmState) {
+			switch {
+			case c.RequireText != "":
+				c.typeRune(r)
+			case r == 'y':
+				c.Selected = 0
+				answer(confirmYes, "y")
+			case r == 'n' && c.OnNo != nil:
+				answer(confirmNo, "n")
+			case r == 'n':
+				answer(confirmDismissed, "n")
+			}
+		})})
 	}
+	return keys
 }
 
-func (gui *GUI) confirmRight() {
-	if gui.confirm != nil && gui.confirm.Selected < 1 {
-		gui.confirm.Selected++
+// bindConfirmKeys binds confirmKeys on view.
+func bindConfirmKeys(g *gocui.Gui, view string, current func() *confirmState, answer func(confirmAnswer, string)) error {
+	for _, k := range confirmKeys(current, answer) {
+		if err := g.SetKeybinding(view, k.key, gocui.ModNone, k.fn); err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+func (gui *GUI) showConfirm(title, message string, onYes, onNo func()) {
+	gui.confirm = newConfirm(title, message, onYes, onNo)
+	gui.screen = ScreenConfirm
+	gui.cmdMu.Lock()
+	gui.pendingConfirm = title
+	gui.cmdMu.Unlock()
+	gui.events.Publish(events.Event{Type: events.ConfirmPending, Command: title, Message: message, Destination: gui.eventDestination()})
 }
 
-func (gui *GUI) confirmEnter() {
-	if gui.confirm == nil {
+func (gui *GUI) renderConfirmDialog(g *gocui.Gui) error {
+	return renderConfirm(g, viewConfirm, gui.confirm)
+}
+
+// answerConfirm closes the open dialog with answer and records the
+// decision: on the event socket, and in the log with LAZYKAMAL_DEBUG set.
+func (gui *GUI) answerConfirm(answer confirmAnswer, via string) {
+	c := gui.confirm
+	if c == nil {
 		return
 	}
-
-	if gui.confirm.Selected == 0 && gui.confirm.OnYes != nil {
-		gui.confirm.OnYes()
-	} else if gui.confirm.Selected == 1 && gui.confirm.OnNo != nil {
-		gui.confirm.OnNo()
+	if answer == confirmYes {
+		gui.confirmNote = "yes (via " + via + ")"
+		if c.RequireText != "" {
+			gui.confirmNote = "typed " + c.RequireText
+		}
 	}
-
+	d := c.resolve(answer, via)
+	gui.confirmNote = ""
+	gui.journalDeclined(d)
 	gui.closeConfirm()
+	gui.events.Publish(events.Event{
+		Type:        events.ConfirmAnswered,
+		Command:     d.Title,
+		Message:     d.String(),
+		Destination: gui.eventDestination(),
+		Success:     events.Result(d.Answer == confirmYes),
+	})
+	if gui.debug {
+		gui.appendLog([]string{dim(d.String())})
+	}
 }
 
 func (gui *GUI) closeConfirm() {
@@ -114,6 +298,9 @@ func (gui *GUI) closeConfirm() {
 	gui.confirm = nil
 	gui.screen = gui.prevScreen
 	gui.g.SetCurrentView(viewMain)
+	gui.cmdMu.Lock()
+	gui.pendingConfirm = ""
+	gui.cmdMu.Unlock()
 }
 
 // getDestructiveMessage returns a warning message for destructive actions
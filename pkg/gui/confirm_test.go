@@ -0,0 +1,147 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// pressConfirmKey sends key to the open dialog through its real binding.
+func pressConfirmKey(t *testing.T, gui *GUI, key interface{}) {
+	t.Helper()
+	for _, k := range confirmKeys(func() *confirmState { return gui.confirm }, gui.answerConfirm) {
+		if k.key == key {
+			if err := k.fn(nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+	}
+	t.Fatalf("no confirm binding for %v", key)
+}
+
+func TestConfirmStateTransitions(t *testing.T) {
+	var ran []string
+	c := newConfirm("Confirm Deploy", "Deploy?", func() { ran = append(ran, "yes") }, func() { ran = append(ran, "no") })
+	if c.Selected != 1 || c.selectedAnswer() != confirmNo {
+		t.Fatalf("new dialog selects %d, want No", c.Selected)
+	}
+	c.move(1)
+	if c.Selected != 1 {
+		t.Errorf("move right past No: Selected = %d", c.Selected)
+	}
+	c.move(-1)
+	c.move(-1)
+	if c.Selected != 0 || c.selectedAnswer() != confirmYes {
+		t.Errorf("move left to Yes: Selected = %d", c.Selected)
+	}
+
+	tests := []struct {
+		answer confirmAnswer
+		via    string
+		ran    string
+		line   string
+	}{
+		{confirmYes, "y", "yes", "CONFIRM: Confirm Deploy → yes (selected via y)"},
+		{confirmNo, "enter", "no", "CONFIRM: Confirm Deploy → no (selected via enter)"},
+		{confirmDismissed, "esc", "", "CONFIRM: Confirm Deploy → no (dismissed) (selected via esc)"},
+	}
+	closed := confirmAnswer(-1)
+	c.OnClose = func(a confirmAnswer) { closed = a }
+	for _, tt := range tests {
+		ran = nil
+		d := c.resolve(tt.answer, tt.via)
+		if closed != tt.answer {
+			t.Errorf("resolve(%v): OnClose got %v", tt.answer, closed)
+		}
+		if got := d.String(); got != tt.line {
+			t.Errorf("resolve(%v) = %q, want %q", tt.answer, got, tt.line)
+		}
+		if (tt.ran == "" && len(ran) != 0) || (tt.ran != "" && (len(ran) != 1 || ran[0] != tt.ran)) {
+			t.Errorf("resolve(%v) ran %v, want %q", tt.answer, ran, tt.ran)
+		}
+	}
+
+	// Dialogs without callbacks resolve without panicking.
+	if d := newConfirm("Target hosts", "", nil, nil).resolve(confirmNo, "enter"); d.Answer != confirmNo {
+		t.Errorf("resolve without callbacks = %+v", d)
+	}
+}
+
+func TestConfirmRequireText(t *testing.T) {
+	c := newConfirm("Confirm App Remove", "Remove?", nil, nil)
+	if !c.yesEnabled() {
+		t.Fatal("a plain dialog disables Yes")
+	}
+	c.RequireText = "myapp"
+	for _, r := range "myap" {
+		c.typeRune(r)
+	}
+	if c.yesEnabled() {
+		t.Errorf("typed %q: Yes enabled before the whole name", string(c.typed))
+	}
+	c.typeRune('x')
+	c.backspace()
+	if c.yesEnabled() || string(c.typed) != "myap" {
+		t.Errorf("after a typo and Backspace: typed %q", string(c.typed))
+	}
+	c.typeRune('p')
+	c.typeRune('s')
+	if !c.yesEnabled() || string(c.typed) != "myapp" {
+		t.Errorf("typed %q: want Yes enabled, the field capped at the name", string(c.typed))
+	}
+}
+
+func TestConfirmTextStaysInTheDialog(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	gui.screen = ScreenApp
+	gui.appendLog([]string{"before"})
+	gui.runWithConfirm("App Stop", "Stop the application?", nil)
+	// Every global single key, as if the service were named after them.
+	var text []rune
+	for _, k := range gui.runeKeys() {
+		text = append(text, k.key)
+	}
+	gui.confirm.RequireText = string(text)
+
+	type state struct {
+		screen                   Screen
+		logs, logScroll, status  int
+		zone                     displayZone
+		destOnly, showAll, focus bool
+		search                   string
+		overlay                  bool
+	}
+	snapshot := func() state {
+		return state{gui.screen, len(gui.logLines), gui.logScroll, gui.statusScroll, gui.zone,
+			gui.logDestOnly, gui.showAllDests, gui.logFocus, gui.search.term,
+			gui.input != nil || gui.picker != nil || gui.pager != nil}
+	}
+	before := snapshot()
+	// gocui runs the global bindings for the key, then the dialog's.
+	for _, r := range text {
+		for _, k := range gui.runeKeys() {
+			if k.key != r {
+				continue
+			}
+			if err := k.fn(nil, nil); err != nil {
+				t.Fatalf("typing %q: %v", r, err)
+			}
+		}
+		if gui.confirm == nil {
+			t.Fatalf("typing %q closed the dialog", r)
+		}
+		gui.confirm.typeRune(r)
+	}
+	if after := snapshot(); after != before {
+		t.Errorf("typing the confirm text changed the GUI:\n before %+v\n after  %+v", before, after)
+	}
+	if !gui.confirm.yesEnabled() {
+		t.Errorf("typed %q, want %q", string(gui.confirm.typed), gui.confirm.RequireText)
+	}
+	waitIdle(t, gui)
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Errorf("typing ran %q", calls)
+	}
+}
@@ -0,0 +1,99 @@
+package gui
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// mainMenuConnect and mainMenuHistory are the indexes of "Connect to
+// server →" and "History" in the main menu, the items that do not open a
+// command screen.
+const (
+	mainMenuConnect = 7
+	mainMenuHistory = 8
+)
+
+// serverModeTarget is host as a server mode target, [user@]host[:port].
+func serverModeTarget(host, user, port string) string {
+	target := host
+	if user != "" {
+		target = user + "@" + host
+	}
+	if port != "" && port != "22" {
+		target += ":" + port
+	}
+	return target
+}
+
+// connectServer lists the selected destination's hosts and opens server
+// mode on the one picked.
+func (gui *GUI) connectServer() {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return
+	}
+	servers := dest.Servers()
+	if len(servers) == 0 {
+		gui.logInfo("No servers listed in " + dest.ConfigSource(gui.cwd))
+		return
+	}
+	items := make([]pickerItem, 0, len(servers))
+	for _, s := range servers {
+		items = append(items, pickerItem{Label: s.Host + dim(" ("+strings.Join(s.Roles, ", ")+")"), Value: s.Host})
+	}
+	user, port := dest.SSHUser(), dest.SSHPort()
+	gui.showPicker(&listPicker{
+		Title:   "Connect to server: " + dest.Label(),
+		Message: "Open server mode on a host; quit it to come back here.",
+		Items:   items,
+		OnDone: func(values []string) {
+			if len(values) == 1 {
+				gui.runServerMode(values[0], user, port)
+			}
+		},
+	})
+}
+
+// runServerMode hands the terminal to a server mode TUI on host and returns
+// to project mode when it quits. Without a user in the config, the one ssh
+// would use is looked up. Failing to connect is logged here.
+func (gui *GUI) runServerMode(host, user, port string) {
+	if gui.demo {
+		gui.logInfo("Server mode connects over ssh; it is not part of the demo")
+		return
+	}
+	if user == "" {
+		user = ssh.DetectUser(host)
+	}
+	target := serverModeTarget(host, user, port)
+	if err := ssh.ValidateHost(target); err != nil {
+		gui.logError(err.Error())
+		return
+	}
+	gui.logInfo("Opening server mode on " + target)
+	gui.handoff.requestRun(gui.g, func() error {
+		s, err := NewServerMode(gui.version, target)
+		if err != nil {
+			return err
+		}
+		if err := s.Run(); err != nil && !errors.Is(err, gocui.ErrQuit) {
+			return err
+		}
+		return nil
+	}, func(err error) {
+		if err != nil {
+			gui.logStartupError(err)
+			return
+		}
+		gui.logInfo("Back from server mode on " + target)
+	})
+}
+
+// logStartupError logs a failure to start server mode the way
+// ReportStartupError prints it, with the next steps dimmed below.
+func (gui *GUI) logStartupError(err error) {
+	gui.appendLog(startupErrorLines(err))
+}
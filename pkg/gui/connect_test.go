@@ -0,0 +1,36 @@
+package gui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServerModeTarget(t *testing.T) {
+	tests := []struct {
+		host, user, port string
+		want             string
+	}{
+		{"10.0.0.1", "", "", "10.0.0.1"},
+		{"10.0.0.1", "deploy", "", "deploy@10.0.0.1"},
+		{"web1", "deploy", "22", "deploy@web1"},
+		{"web1", "deploy", "2222", "deploy@web1:2222"},
+		{"web1", "", "2222", "web1:2222"},
+	}
+	for _, tt := range tests {
+		if got := serverModeTarget(tt.host, tt.user, tt.port); got != tt.want {
+			t.Errorf("serverModeTarget(%q, %q, %q) = %q, want %q", tt.host, tt.user, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestLogStartupError(t *testing.T) {
+	gui := &GUI{}
+	gui.logStartupError(&StartupError{Stage: StageSSH, SSH: "deploy@web1", Err: errors.New("Permission denied (publickey).")})
+	if len(gui.logLines) < 3 {
+		t.Fatalf("logged %d lines, want the error and its next steps", len(gui.logLines))
+	}
+	if !strings.Contains(gui.logLines[0].text, "SSH authentication failed") {
+		t.Errorf("first line = %q", gui.logLines[0].text)
+	}
+}
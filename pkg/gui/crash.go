@@ -0,0 +1,51 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+)
+
+// crashLogPath is where the stack trace of a panic in the TUI is written.
+func crashLogPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "lazykamal", "crash.log")
+}
+
+// recoverCrash is deferred first in Run, so it runs after the deferred
+// Close restored the terminal. A panic in the main loop, e.g. in a render
+// function, becomes an error naming the crash log instead of a stack trace
+// dumped over a raw-mode screen.
+func recoverCrash(version string, err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	path := crashLogPath()
+	if werr := writeCrashLog(path, version, r, debug.Stack()); werr != nil {
+		*err = fmt.Errorf("lazykamal crashed: %v (could not write %s: %v)\n%s", r, path, werr, debug.Stack())
+		return
+	}
+	*err = fmt.Errorf("lazykamal crashed: %v\nThe stack trace is in %s; please include it when reporting the crash", r, path)
+}
+
+// writeCrashLog appends one crash report to path.
+func writeCrashLog(path, version string, r interface{}, stack []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "=== %s lazykamal %s\npanic: %v\n\n%s\n", time.Now().Format(time.RFC3339), version, r, stack)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package gui
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestRecoverCrash(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	closed := false
+	run := func() (err error) {
+		defer recoverCrash("v1.2.3", &err)
+		defer func() { closed = true }()
+		var entries []logEntry
+		_ = entries[3] // index out of range, as a render bug would
+		return nil
+	}
+	err := run()
+	if err == nil {
+		t.Fatal("panic was not turned into an error")
+	}
+	if !closed {
+		t.Error("deferred Close did not run before recovery")
+	}
+	path := crashLogPath()
+	if !strings.Contains(err.Error(), path) {
+		t.Errorf("error %q does not name the crash log %s", err, path)
+	}
+	data, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Fatal(rerr)
+	}
+	for _, want := range []string{"lazykamal v1.2.3", "index out of range", "TestRecoverCrash"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("crash log missing %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestRecoverCrashWithoutPanic(t *testing.T) {
+	run := func() (err error) {
+		defer recoverCrash("dev", &err)
+		return nil
+	}
+	if err := run(); err != nil {
+		t.Errorf("run() = %v, want nil", err)
+	}
+}
@@ -0,0 +1,120 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/events"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// deploySummary fetches what is running after a successful deploy and renders
+// it as a block in the log. before holds the host -> version map recorded
+// right before the deploy started. It returns the deployed version label, or
+// "" when it could not be fetched.
+func (gui *GUI) deploySummary(opts kamal.RunOptions, before map[string]string, took time.Duration, stopCh <-chan struct{}) string {
+	verRes, err := kamal.RunKamalWithStop([]string{"app", "version"}, opts, stopCh)
+	if err != nil {
+		return ""
+	}
+	ctrRes, err := kamal.RunKamalWithStop([]string{"app", "containers"}, opts, stopCh)
+	if err != nil {
+		return ""
+	}
+	after := kamal.ParseAppVersions(verRes.Combined())
+	gui.events.Publish(events.Event{Type: events.Deployed, Destination: gui.eventDestination(), Version: versionLabel(after), DurationMs: took.Milliseconds()})
+	gui.appendLog(deploySummaryLines(before, after, kamal.ParseAppContainers(ctrRes.Combined()), took))
+	return versionLabel(after)
+}
+
+// deploySummaryLines renders the post-deploy summary: new version, running
+// containers per host with their uptime, and the total deploy duration. When
+// the version did not change, a yellow warning is added.
+func deploySummaryLines(before, after map[string]string, containers []kamal.HostContainer, took time.Duration) []string {
+	version := versionLabel(after)
+	lines := []string{
+		green("── Deploy summary ──"),
+		green(fmt.Sprintf("  Version:  %s", version)),
+	}
+
+	seen := map[string]bool{}
+	var hosts []string
+	for h := range after {
+		seen[h] = true
+		hosts = append(hosts, h)
+	}
+	for _, c := range containers {
+		if !seen[c.Host] {
+			seen[c.Host] = true
+			hosts = append(hosts, c.Host)
+		}
+	}
+	sort.Strings(hosts)
+	for _, h := range hosts {
+		var running []string
+		for _, c := range containers {
+			if c.Host == h && c.Running() {
+				running = append(running, fmt.Sprintf("%s %s", truncate(c.ID, 12), compactUptime(c.Status)))
+			}
+		}
+		if len(running) == 0 {
+			running = []string{"no running containers"}
+		}
+		lines = append(lines, green(fmt.Sprintf("  %s: %s", h, strings.Join(running, ", "))))
+	}
+	lines = append(lines, green(fmt.Sprintf("  Took:     %s", formatDuration(took))))
+
+	if len(before) > 0 && versionLabel(before) == version {
+		lines = append(lines, yellow("  "+iconWarning+" deployed but version unchanged — did the build use a stale SHA?"))
+	}
+	return lines
+}
+
+// versionLabel returns the single version running everywhere, or a sorted
+// comma-separated list when hosts disagree.
+func versionLabel(versions map[string]string) string {
+	seen := map[string]bool{}
+	var list []string
+	for _, v := range versions {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	if len(list) == 0 {
+		return "unknown"
+	}
+	sort.Strings(list)
+	return strings.Join(list, ", ")
+}
+
+// compactUptime shortens docker's status to e.g. "up 12s", "up 3m (healthy)".
+func compactUptime(status string) string {
+	if !strings.HasPrefix(status, "Up ") {
+		return strings.ToLower(status)
+	}
+	rest := strings.TrimPrefix(status, "Up ")
+	suffix := ""
+	if i := strings.Index(rest, " ("); i >= 0 {
+		suffix = rest[i:]
+		rest = rest[:i]
+	}
+	units := map[string]string{"second": "s", "minute": "m", "hour": "h", "day": "d", "week": "w", "month": "mo", "year": "y"}
+	fields := strings.Fields(rest)
+	if len(fields) >= 2 {
+		n := fields[len(fields)-2]
+		unit := strings.TrimSuffix(fields[len(fields)-1], "s")
+		if u, ok := units[unit]; ok {
+			if n == "a" || n == "an" {
+				n = "~1" // "About a minute"
+			}
+			if fields[0] == "Less" {
+				n = "<1" // "Less than a second"
+			}
+			return "up " + n + u + suffix
+		}
+	}
+	return "up " + rest + suffix
+}
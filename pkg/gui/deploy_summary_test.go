@@ -0,0 +1,80 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestCompactUptime(t *testing.T) {
+	tests := []struct {
+		status string
+		want   string
+	}{
+		{"Up 12 seconds", "up 12s"},
+		{"Up 3 minutes (healthy)", "up 3m (healthy)"},
+		{"Up About a minute", "up ~1m"},
+		{"Up About an hour", "up ~1h"},
+		{"Up Less than a second", "up <1s"},
+		{"Up 2 days", "up 2d"},
+		{"Exited (1) 5 seconds ago", "exited (1) 5 seconds ago"},
+	}
+	for _, tt := range tests {
+		if got := compactUptime(tt.status); got != tt.want {
+			t.Errorf("compactUptime(%q) = %q, want %q", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestDeploySummaryLines(t *testing.T) {
+	after := map[string]string{"10.0.0.1": "9f8e7d6c", "10.0.0.2": "9f8e7d6c"}
+	containers := []kamal.HostContainer{
+		{Host: "10.0.0.1", ID: "3f2a1b0c9d8e", Status: "Up 12 seconds"},
+		{Host: "10.0.0.1", ID: "7a6b5c4d3e2f", Status: "Exited (0) 10 seconds ago"},
+		{Host: "10.0.0.2", ID: "aa11bb22cc33", Status: "Up 14 seconds"},
+	}
+	before := map[string]string{"10.0.0.1": "1a2b3c4d", "10.0.0.2": "1a2b3c4d"}
+
+	out := strings.Join(deploySummaryLines(before, after, containers, 95*time.Second), "\n")
+	for _, want := range []string{"Version:  9f8e7d6c", "10.0.0.1: 3f2a1b0c9d8e up 12s", "10.0.0.2: aa11bb22cc33 up 14s", "Took:     1m35s"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "7a6b5c4d3e2f") {
+		t.Errorf("summary should skip stopped containers:\n%s", out)
+	}
+	if strings.Contains(out, "version unchanged") {
+		t.Errorf("summary should not warn when the version changed:\n%s", out)
+	}
+
+	out = strings.Join(deploySummaryLines(after, after, containers, time.Minute), "\n")
+	if !strings.Contains(out, "deployed but version unchanged") {
+		t.Errorf("summary should warn when the version is unchanged:\n%s", out)
+	}
+}
+
+func TestVersionLabel(t *testing.T) {
+	if got := versionLabel(map[string]string{"a": "v2", "b": "v1", "c": "v2"}); got != "v1, v2" {
+		t.Errorf("versionLabel(mixed) = %q, want %q", got, "v1, v2")
+	}
+	if got := versionLabel(nil); got != "unknown" {
+		t.Errorf("versionLabel(nil) = %q, want unknown", got)
+	}
+}
+
+func TestRollbackCandidate(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"9f8e7d6c", "9f8e7d6c"},
+		{"v1, v2", ""},
+		{"unknown", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := rollbackCandidate(tt.in); got != tt.want {
+			t.Errorf("rollbackCandidate(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package gui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// historyKeep is how many durations are kept per destination and command.
+const historyKeep = 50
+
+// etaCommands are the commands whose durations are recorded and estimated.
+var etaCommands = map[string]bool{
+	"Deploy":              true,
+	"Deploy (skip push)":  true,
+	"Deploy (no cache)":   true,
+	"Redeploy":            true,
+	"Redeploy (no cache)": true,
+	"Setup":               true,
+	"Setup (no cache)":    true,
+}
+
+// deployHistory records how long successful deploys took, per destination
+// config and command, in the user's cache directory. It is best effort: a
+// missing or unreadable file only means no ETA.
+type deployHistory struct {
+	mu        sync.Mutex
+	path      string
+	durations map[string][]float64 // seconds, oldest first
+}
+
+// defaultHistoryPath is deploy-durations.json in the user cache directory,
+// or "" when there is none.
+func defaultHistoryPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "lazykamal", "deploy-durations.json")
+}
+
+// loadDeployHistory reads the history at path. A missing or corrupt file
+// starts an empty one.
+func loadDeployHistory(path string) *deployHistory {
+	h := &deployHistory{path: path, durations: map[string][]float64{}}
+	if path == "" {
+		return h
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &h.durations)
+	}
+	if h.durations == nil {
+		h.durations = map[string][]float64{}
+	}
+	return h
+}
+
+func historyKey(configPath, command string) string {
+	return configPath + "\x00" + command
+}
+
+// samples returns the recorded durations for command on the destination
+// config, oldest first.
+func (h *deployHistory) samples(configPath, command string) []time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	secs := h.durations[historyKey(configPath, command)]
+	out := make([]time.Duration, len(secs))
+	for i, s := range secs {
+		out[i] = time.Duration(s * float64(time.Second))
+	}
+	return out
+}
+
+// record adds a successful run and saves the history.
+func (h *deployHistory) record(configPath, command string, took time.Duration) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := historyKey(configPath, command)
+	secs := append(h.durations[key], took.Seconds())
+	if len(secs) > historyKeep {
+		secs = secs[len(secs)-historyKeep:]
+	}
+	h.durations[key] = secs
+	if h.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(h.durations)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0o644)
+}
@@ -3,11 +3,13 @@ package gui
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/jroimartin/gocui"
+	"gopkg.in/yaml.v3"
 )
 
 const viewEditor = "editor"
@@ -33,7 +35,19 @@ type editorState struct {
 	Scroll      int
 	Dirty       bool
 	PrevScreen  Screen
-	ConfirmQuit bool // show "Quit without saving? (y/n)"
+	ConfirmQuit bool             // show "Quit without saving? (y/n)"
+	ConfirmSave bool             // the YAML does not parse: show Notice and "Save anyway? (y/n)"
+	Notice      string           // status line message: a YAML error, "not found"
+	Height      int              // lines shown at the last render
+	Searching   bool             // the search prompt (Ctrl+W) has the keyboard
+	SearchInput string           // the term being typed
+	LastSearch  string           // repeated by F3
+	GoingTo     bool             // the go-to-line prompt (Ctrl+G) has the keyboard
+	GotoInput   string           // the line number being typed
+	Saved       []string         // the lines as on disk, for Dirty after undo
+	Secret      bool             // a secrets file: values are painted masked
+	Revealed    bool             // Ctrl+V: a secrets file is painted as it is
+	Undo, Redo  []editorSnapshot // Ctrl+Z / Ctrl+Y
 }
 
 func (gui *GUI) openEditor(path string) bool {
@@ -55,7 +69,9 @@ func (gui *GUI) openEditor(path string) bool {
 		Scroll:     0,
 		Dirty:      false,
 		PrevScreen: gui.screen,
+		Secret:     secretsFile(path),
 	}
+	gui.editor.markSaved()
 	gui.screen = ScreenEditor
 	return true
 }
@@ -74,10 +90,48 @@ func (gui *GUI) closeEditor() {
 	}
 }
 
+// yamlSaveCheck parses data when path is a YAML file, as editorSave does
+// before writing. It returns the parse error, e.g. "YAML error: line 3:
+// mapping values are not allowed in this context", and whether it should
+// stop the save. Files with ERB (<% %>) only get a warning: kamal renders
+// the ERB before parsing, so the raw file need not be valid YAML.
+func yamlSaveCheck(path string, data []byte) (problem string, hard bool) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+	default:
+		return "", false
+	}
+	var doc map[string]interface{}
+	err := yaml.Unmarshal(data, &doc)
+	if err == nil {
+		return "", false
+	}
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	msg = strings.Join(strings.Fields(strings.ReplaceAll(msg, "unmarshal errors:\n", "")), " ")
+	if strings.Contains(string(data), "<%") {
+		return "Warning: YAML does not parse before ERB is rendered: " + msg, false
+	}
+	return "YAML error: " + msg, true
+}
+
+// editorSave writes the file. A YAML file that does not parse is not
+// written; the editor asks whether to save it anyway instead.
 func (gui *GUI) editorSave() bool {
 	if gui.editor == nil {
 		return false
 	}
+	data := []byte(strings.Join(gui.editor.Lines, "\n"))
+	problem, hard := yamlSaveCheck(gui.editor.Path, data)
+	gui.editor.Notice = problem
+	if hard {
+		gui.editor.ConfirmSave = true
+		return false
+	}
+	return gui.editorWrite()
+}
+
+// editorWrite writes the editor's lines to its file without checks.
+func (gui *GUI) editorWrite() bool {
 	data := []byte(strings.Join(gui.editor.Lines, "\n"))
 	// Use 0600 for secrets files for better security
 	perm := os.FileMode(0644)
@@ -88,7 +142,7 @@ func (gui *GUI) editorSave() bool {
 		gui.appendLog([]string{"Could not save: " + err.Error()})
 		return false
 	}
-	gui.editor.Dirty = false
+	gui.editor.markSaved()
 	return true
 }
 
@@ -96,6 +150,10 @@ func (gui *GUI) editorQuit() {
 	if gui.editor == nil {
 		return
 	}
+	if gui.editor.ConfirmSave {
+		gui.editor.ConfirmSave = false
+		return
+	}
 	if gui.editor.ConfirmQuit {
 		gui.editor.ConfirmQuit = false
 		gui.closeEditor()
@@ -121,6 +179,21 @@ func (gui *GUI) editorConfirmQuitNo() {
 	}
 }
 
+// editorConfirmSave answers "Save anyway?" after a YAML error.
+func (gui *GUI) editorConfirmSave(yes bool) {
+	if gui.editor == nil || !gui.editor.ConfirmSave {
+		return
+	}
+	gui.editor.ConfirmSave = false
+	if !yes {
+		return
+	}
+	if gui.editorWrite() {
+		gui.editor.Notice = ""
+		gui.appendLog([]string{"Saved " + gui.editor.Path + " with a YAML error"})
+	}
+}
+
 func (gui *GUI) editorMoveUp() {
 	if gui.editor == nil {
 		return
@@ -173,6 +246,7 @@ func (gui *GUI) editorInsertRune(r rune) {
 	if gui.editor == nil {
 		return
 	}
+	gui.editor.record()
 	line := gui.editor.Lines[gui.editor.Row]
 	byteOff := runeIndexToByteOffset(line, gui.editor.Col)
 	left := line[:byteOff]
@@ -187,6 +261,7 @@ func (gui *GUI) editorBackspace() {
 		return
 	}
 	if gui.editor.Col > 0 {
+		gui.editor.record()
 		line := gui.editor.Lines[gui.editor.Row]
 		byteOffCur := runeIndexToByteOffset(line, gui.editor.Col)
 		byteOffPrev := runeIndexToByteOffset(line, gui.editor.Col-1)
@@ -195,6 +270,7 @@ func (gui *GUI) editorBackspace() {
 		gui.editor.Dirty = true
 	} else if gui.editor.Row > 0 {
 		// Merge with previous line
+		gui.editor.record()
 		prevLen := utf8.RuneCountInString(gui.editor.Lines[gui.editor.Row-1])
 		gui.editor.Lines[gui.editor.Row-1] += gui.editor.Lines[gui.editor.Row]
 		gui.editor.Lines = append(gui.editor.Lines[:gui.editor.Row], gui.editor.Lines[gui.editor.Row+1:]...)
@@ -211,6 +287,7 @@ func (gui *GUI) editorEnter() {
 	if gui.editor == nil {
 		return
 	}
+	gui.editor.record()
 	line := gui.editor.Lines[gui.editor.Row]
 	byteOff := runeIndexToByteOffset(line, gui.editor.Col)
 	left := line[:byteOff]
@@ -228,16 +305,14 @@ func (gui *GUI) renderEditorView(g *gocui.Gui) error {
 		return nil
 	}
 	maxX, maxY := g.Size()
-	// Ensure minimum dimensions to avoid gocui errors
-	if maxX < 10 || maxY < 5 {
-		return fmt.Errorf("terminal too small (need at least 10x5, got %dx%d)", maxX, maxY)
-	}
-	// Editor area: full screen minus two lines for status
+	// Editor area: full screen minus two lines for status. A terminal too
+	// small for both keeps the previous frame until it grows again.
 	editorH := maxY - 2
-	if editorH < 1 {
-		editorH = 1
+	area, status := rect{0, 0, maxX - 1, editorH}, rect{0, editorH + 1, maxX - 1, maxY - 1}
+	if !area.valid() || !status.valid() {
+		return nil
 	}
-	if v, err := g.SetView(viewEditor, 0, 0, maxX-1, editorH); err != nil {
+	if v, err := setView(g, viewEditor, area); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -253,31 +328,28 @@ func (gui *GUI) renderEditorView(g *gocui.Gui) error {
 	v.Clear()
 	// Scroll: ensure cursor is visible
 	_, vy := v.Size()
-	if gui.editor.Row >= gui.editor.Scroll+vy {
-		gui.editor.Scroll = gui.editor.Row - vy + 1
-	}
-	if gui.editor.Row < gui.editor.Scroll {
-		gui.editor.Scroll = gui.editor.Row
-	}
+	gui.editor.Height = vy
+	gui.editor.keepVisible()
 	start := gui.editor.Scroll
 	end := start + vy
 	if end > len(gui.editor.Lines) {
 		end = len(gui.editor.Lines)
 	}
+	gutter := editorGutter(len(gui.editor.Lines))
 	for i := start; i < end; i++ {
 		line := gui.editor.Lines[i]
-		if i == gui.editor.Row {
-			fmt.Fprintf(v, "%s\n", line)
-		} else {
-			fmt.Fprintln(v, line)
+		// Only the painting is masked; the cursor follows the real text.
+		if gui.editor.masked() {
+			line = maskSecretLine(line)
 		}
+		fmt.Fprintln(v, dim(gutterLabel(i, gutter))+line)
 	}
 	cursorByteOff := runeIndexToByteOffset(gui.editor.Lines[gui.editor.Row], gui.editor.Col)
-	v.SetCursor(cursorByteOff, gui.editor.Row-gui.editor.Scroll)
+	v.SetCursor(gutter+cursorByteOff, gui.editor.Row-gui.editor.Scroll)
 	g.SetCurrentView(viewEditor)
 
 	// Status line at bottom
-	if _, err := g.SetView(viewEditorStatus, 0, editorH+1, maxX-1, maxY-1); err != nil {
+	if _, err := setView(g, viewEditorStatus, status); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -289,12 +361,95 @@ func (gui *GUI) renderEditorView(g *gocui.Gui) error {
 		if gui.editor.Dirty {
 			status += " [Modified]"
 		}
-		if gui.editor.ConfirmQuit {
+		switch {
+		case gui.editor.Searching:
+			status = " Search: " + gui.editor.SearchInput + "_  (Enter find, Esc cancel)"
+		case gui.editor.GoingTo:
+			status = fmt.Sprintf(" Go to line (1-%d): %s_  (Enter go, Esc cancel)", len(gui.editor.Lines), gui.editor.GotoInput)
+		case gui.editor.ConfirmQuit:
 			status = " Quit without saving? (y/n) "
-		} else {
-			status += "  ^S Save  ^Q Esc Quit  Arrows move"
+		case gui.editor.ConfirmSave:
+			status = " " + gui.editor.Notice + " — Save anyway? (y/n) "
+		case gui.editor.Notice != "":
+			status += "  " + gui.editor.Notice
+		default:
+			status += "  ^S Save  ^Q Esc Quit  ^W Search  ^G Go to line  ^Z/^Y Undo/Redo  ^E $EDITOR"
+			if gui.editor.masked() {
+				status += "  ^V Reveal"
+			} else if gui.editor.Secret {
+				status += "  ^V Hide"
+			}
 		}
 		fmt.Fprint(s, status)
 	}
 	return nil
 }
+
+// externalEditor returns the command line of the user's editor from
+// $VISUAL or $EDITOR, split into words (e.g. "code --wait").
+func externalEditor() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if f := strings.Fields(os.Getenv(env)); len(f) > 0 {
+			return f
+		}
+	}
+	return nil
+}
+
+// editorExternal (Ctrl+E) hands the open file to $EDITOR and reloads it
+// into the editor afterwards.
+func (gui *GUI) editorExternal() {
+	if gui.editor == nil {
+		return
+	}
+	editor := externalEditor()
+	switch {
+	case editor == nil:
+		gui.appendLog([]string{"Set $EDITOR (or $VISUAL) to edit in an external editor"})
+		return
+	case gui.editor.Dirty:
+		gui.appendLog([]string{"Save (^S) before opening " + editor[0]})
+		return
+	}
+	gui.cmdMu.Lock()
+	busy := gui.running
+	gui.cmdMu.Unlock()
+	if busy {
+		gui.appendLog([]string{"Wait for the running command before opening " + editor[0]})
+		return
+	}
+	path := gui.editor.Path
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	gui.handoff.request(gui.g, cmd, func(err error) {
+		if err != nil {
+			gui.logError(editor[0] + ": " + err.Error())
+		}
+		gui.reloadEditor(path)
+	})
+}
+
+// reloadEditor re-reads path into the open editor, keeping the cursor
+// where it still fits.
+func (gui *GUI) reloadEditor(path string) {
+	if gui.editor == nil || gui.editor.Path != path {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		gui.appendLog([]string{"Could not read file: " + err.Error()})
+		return
+	}
+	gui.editor.Lines = strings.Split(string(data), "\n")
+	gui.editor.markSaved()
+	gui.editor.Undo, gui.editor.Redo = nil, nil
+	if gui.editor.Row >= len(gui.editor.Lines) {
+		gui.editor.Row = len(gui.editor.Lines) - 1
+	}
+	if n := utf8.RuneCountInString(gui.editor.Lines[gui.editor.Row]); gui.editor.Col > n {
+		gui.editor.Col = n
+	}
+	if gui.editor.Scroll > gui.editor.Row {
+		gui.editor.Scroll = gui.editor.Row
+	}
+	gui.appendLog([]string{"Reloaded " + path})
+}
@@ -0,0 +1,131 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// editorGutter is the width of the line-number gutter for a file of n
+// lines: the widest number and a space.
+func editorGutter(n int) int {
+	return len(strconv.Itoa(max(n, 1))) + 1
+}
+
+// gutterLabel is line i's number, right-aligned in a gutter of width.
+func gutterLabel(i, width int) string {
+	return fmt.Sprintf("%*d ", width-1, i+1)
+}
+
+// keepVisible scrolls as little as possible to show Row in a view of
+// Height lines.
+func (e *editorState) keepVisible() {
+	if e.Height > 0 && e.Row >= e.Scroll+e.Height {
+		e.Scroll = e.Row - e.Height + 1
+	}
+	if e.Row < e.Scroll {
+		e.Scroll = e.Row
+	}
+}
+
+// centerOn puts row in the middle of the view when it is out of sight.
+func (e *editorState) centerOn(row int) {
+	if e.Height > 0 && (row < e.Scroll || row >= e.Scroll+e.Height) {
+		e.Scroll = max(0, row-e.Height/2)
+	}
+	e.keepVisible()
+}
+
+// clampCol keeps Col within the current line.
+func (e *editorState) clampCol() {
+	e.Col = min(e.Col, utf8.RuneCountInString(e.Lines[e.Row]))
+}
+
+// editorHome (Home) moves to the start of the line.
+func (gui *GUI) editorHome() {
+	if gui.editor == nil {
+		return
+	}
+	gui.editor.Col = 0
+}
+
+// editorEnd (End) moves to the end of the line.
+func (gui *GUI) editorEnd() {
+	if gui.editor == nil {
+		return
+	}
+	gui.editor.Col = utf8.RuneCountInString(gui.editor.Lines[gui.editor.Row])
+}
+
+// editorPage (PgUp dir -1, PgDn dir 1) moves the cursor and the view by a
+// view height, stopping at the first and last lines.
+func (gui *GUI) editorPage(dir int) {
+	e := gui.editor
+	if e == nil {
+		return
+	}
+	step := max(e.Height, 1)
+	e.Row = clampIndex(e.Row+dir*step, len(e.Lines)-1)
+	e.Scroll = clampIndex(e.Scroll+dir*step, max(len(e.Lines)-step, 0))
+	e.clampCol()
+	e.keepVisible()
+}
+
+// editorGotoStart (Ctrl+G) opens the go-to-line prompt in the status line.
+// Pressed while the prompt is open it jumps, like Enter.
+func (gui *GUI) editorGotoStart() {
+	if gui.editor == nil {
+		return
+	}
+	if gui.editor.GoingTo {
+		gui.editorGotoSubmit()
+		return
+	}
+	gui.editor.Searching = false
+	gui.editor.GoingTo = true
+	gui.editor.GotoInput = ""
+}
+
+// editorGoingTo reports whether the go-to-line prompt has the keyboard.
+func (gui *GUI) editorGoingTo() bool {
+	return gui.editor != nil && gui.editor.GoingTo
+}
+
+// editorGotoType adds a digit to the go-to-line prompt; other runes are
+// ignored.
+func (gui *GUI) editorGotoType(r rune) {
+	if r >= '0' && r <= '9' && len(gui.editor.GotoInput) < 9 {
+		gui.editor.GotoInput += string(r)
+	}
+}
+
+// editorGotoBackspace deletes the last digit of the go-to-line prompt.
+func (gui *GUI) editorGotoBackspace() {
+	if in := gui.editor.GotoInput; in != "" {
+		gui.editor.GotoInput = in[:len(in)-1]
+	}
+}
+
+// editorGotoCancel (Esc) closes the prompt without moving the cursor.
+func (gui *GUI) editorGotoCancel() {
+	gui.editor.GoingTo = false
+	gui.editor.GotoInput = ""
+}
+
+// editorGotoSubmit (Enter) closes the prompt and jumps to the start of the
+// line it names. A number past the end goes to the last line.
+func (gui *GUI) editorGotoSubmit() {
+	e := gui.editor
+	in := e.GotoInput
+	e.GoingTo, e.GotoInput = false, ""
+	n, err := strconv.Atoi(in)
+	if err != nil {
+		return
+	}
+	e.Row, e.Col = clampIndex(n-1, len(e.Lines)-1), 0
+	e.centerOn(e.Row)
+	e.Notice = ""
+	if n > len(e.Lines) {
+		e.Notice = fmt.Sprintf("The file has %d lines", len(e.Lines))
+	}
+}
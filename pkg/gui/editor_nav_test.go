@@ -0,0 +1,122 @@
+package gui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEditorGutter(t *testing.T) {
+	tests := []struct {
+		n, width int
+	}{
+		{0, 2},
+		{9, 2},
+		{10, 3},
+		{1234, 5},
+	}
+	for _, tt := range tests {
+		if got := editorGutter(tt.n); got != tt.width {
+			t.Errorf("editorGutter(%d) = %d, want %d", tt.n, got, tt.width)
+		}
+	}
+	if got := gutterLabel(6, 3); got != " 7 " {
+		t.Errorf("gutterLabel(6, 3) = %q", got)
+	}
+}
+
+// numbered is n lines "line 1" ... "line n".
+func numbered(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+	return lines
+}
+
+func TestEditorHomeEnd(t *testing.T) {
+	gui := &GUI{editor: &editorState{Lines: []string{"servers: 日本"}, Col: 3}}
+	gui.editorEnd()
+	if gui.editor.Col != 11 {
+		t.Errorf("End: Col = %d, want 11", gui.editor.Col)
+	}
+	gui.editorHome()
+	if gui.editor.Col != 0 {
+		t.Errorf("Home: Col = %d, want 0", gui.editor.Col)
+	}
+}
+
+// visible reports whether the cursor's row is on screen.
+func visible(e *editorState) bool {
+	return e.Row >= e.Scroll && e.Row < e.Scroll+e.Height
+}
+
+func TestEditorPage(t *testing.T) {
+	lines := numbered(25)
+	lines[20] = "x"
+	gui := &GUI{editor: &editorState{Lines: lines, Height: 10, Col: 5}}
+	steps := []struct {
+		dir, row, scroll, col int
+	}{
+		{1, 10, 10, 5},
+		{1, 20, 15, 1}, // the view stops at the last page; Col fits "x"
+		{1, 24, 15, 1},
+		{-1, 14, 5, 1},
+		{-1, 4, 0, 1},
+		{-1, 0, 0, 1},
+	}
+	for i, s := range steps {
+		gui.editorPage(s.dir)
+		e := gui.editor
+		if e.Row != s.row || e.Scroll != s.scroll || e.Col != s.col || !visible(e) {
+			t.Errorf("step %d: Row %d Scroll %d Col %d, want %d %d %d", i, e.Row, e.Scroll, e.Col, s.row, s.scroll, s.col)
+		}
+	}
+}
+
+func TestEditorGotoLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		row, scroll int
+		notice      string
+	}{
+		{"near the top", "3", 2, 0, ""},
+		{"out of view", "40", 39, 34, ""},
+		{"past the end", "999", 49, 44, "The file has 50 lines"},
+		{"zero", "0", 0, 0, ""},
+		{"empty", "", 7, 5, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gui := &GUI{editor: &editorState{Lines: numbered(50), Height: 10, Row: 7, Col: 4, Scroll: 5}}
+			gui.editorGotoStart()
+			if !gui.editorGoingTo() {
+				t.Fatal("Ctrl+G did not open the prompt")
+			}
+			for _, r := range tt.input + "x" {
+				gui.editorGotoType(r)
+			}
+			if gui.editor.GotoInput != tt.input {
+				t.Fatalf("prompt = %q, want %q", gui.editor.GotoInput, tt.input)
+			}
+			gui.editorGotoSubmit()
+			e := gui.editor
+			if e.GoingTo || e.Row != tt.row || e.Scroll != tt.scroll || e.Notice != tt.notice || !visible(e) {
+				t.Errorf("after %q: GoingTo %v Row %d Scroll %d Notice %q, want Row %d Scroll %d Notice %q",
+					tt.input, e.GoingTo, e.Row, e.Scroll, e.Notice, tt.row, tt.scroll, tt.notice)
+			}
+		})
+	}
+}
+
+func TestEditorGotoCancel(t *testing.T) {
+	gui := &GUI{editor: &editorState{Lines: numbered(5), Row: 2, Col: 1}}
+	gui.editorGotoStart()
+	gui.editorGotoType('4')
+	gui.editorGotoBackspace()
+	gui.editorGotoType('5')
+	gui.editorGotoCancel()
+	if gui.editor.GoingTo || gui.editor.Row != 2 || gui.editor.Col != 1 {
+		t.Errorf("Esc moved the cursor or left the prompt open: %+v", gui.editor)
+	}
+}
@@ -0,0 +1,108 @@
+package gui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// editorSearchStart (Ctrl+W) opens the search prompt in the editor's status
+// line, filled with the last term. Pressed while the prompt is open it
+// searches, like Enter.
+func (gui *GUI) editorSearchStart() {
+	if gui.editor == nil {
+		return
+	}
+	if gui.editor.Searching {
+		gui.editorSearchSubmit()
+		return
+	}
+	gui.editor.GoingTo = false
+	gui.editor.Searching = true
+	gui.editor.SearchInput = gui.editor.LastSearch
+}
+
+// editorSearching reports whether the search prompt has the keyboard.
+func (gui *GUI) editorSearching() bool {
+	return gui.editor != nil && gui.editor.Searching
+}
+
+// editorSearchType adds r to the search prompt.
+func (gui *GUI) editorSearchType(r rune) {
+	gui.editor.SearchInput += string(r)
+}
+
+// editorSearchBackspace deletes the last rune of the search prompt.
+func (gui *GUI) editorSearchBackspace() {
+	in := gui.editor.SearchInput
+	if _, size := utf8.DecodeLastRuneInString(in); size > 0 {
+		gui.editor.SearchInput = in[:len(in)-size]
+	}
+}
+
+// editorSearchCancel (Esc) closes the prompt without moving the cursor.
+func (gui *GUI) editorSearchCancel() {
+	gui.editor.Searching = false
+	gui.editor.SearchInput = ""
+}
+
+// editorSearchSubmit (Enter) closes the prompt and jumps to the next match
+// of its term.
+func (gui *GUI) editorSearchSubmit() {
+	gui.editor.Searching = false
+	if term := gui.editor.SearchInput; term != "" {
+		gui.editor.LastSearch = term
+	}
+	gui.editor.SearchInput = ""
+	gui.editorFindNext()
+}
+
+// editorFindNext (F3) jumps to the next match of the last search term after
+// the cursor, wrapping around the file.
+func (gui *GUI) editorFindNext() {
+	e := gui.editor
+	if e == nil || e.LastSearch == "" {
+		return
+	}
+	row, col, wrapped, ok := findInLines(e.Lines, e.LastSearch, e.Row, e.Col+1)
+	if !ok {
+		e.Notice = "\"" + e.LastSearch + "\" not found"
+		return
+	}
+	e.Row, e.Col = row, col
+	e.centerOn(row)
+	e.Notice = ""
+	if wrapped {
+		e.Notice = "Search wrapped"
+	}
+}
+
+// findInLines finds term case-insensitively in lines, starting at rune col
+// of row and wrapping around to the start. It returns the match as a row and
+// rune column, and whether the search wrapped.
+func findInLines(lines []string, term string, row, col int) (int, int, bool, bool) {
+	if len(lines) == 0 || term == "" {
+		return row, col, false, false
+	}
+	term = strings.Map(unicode.ToLower, term)
+	for i := 0; i <= len(lines); i++ {
+		r := (row + i) % len(lines)
+		// Lowering maps rune for rune, so rune columns carry over.
+		line := strings.Map(unicode.ToLower, lines[r])
+		from := 0
+		if i == 0 {
+			from = runeIndexToByteOffset(line, col)
+		}
+		idx := strings.Index(line[from:], term)
+		if idx < 0 {
+			continue
+		}
+		at := utf8.RuneCountInString(line[:from+idx])
+		// Back on the cursor's row, only matches before where it started count.
+		if i == len(lines) && at >= col {
+			break
+		}
+		return r, at, row+i >= len(lines), true
+	}
+	return row, col, false, false
+}
@@ -0,0 +1,77 @@
+package gui
+
+import "testing"
+
+func TestFindInLines(t *testing.T) {
+	lines := []string{
+		"service: app",
+		"image: user/App",
+		"servers:",
+		"  - 日本 app.example.com",
+	}
+	tests := []struct {
+		name             string
+		term             string
+		row, col         int
+		wantRow, wantCol int
+		wrapped, ok      bool
+	}{
+		{"same line", "app", 0, 0, 0, 9, false, true},
+		{"next line, any case", "app", 0, 10, 1, 12, false, true},
+		{"multi-byte columns", "APP", 1, 13, 3, 7, false, true},
+		{"multi-byte term", "日本", 0, 0, 3, 4, false, true},
+		{"wraps", "service", 1, 0, 0, 0, true, true},
+		{"only match under the cursor", "image", 1, 1, 1, 0, true, true},
+		{"not found", "redis", 2, 0, 2, 0, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row, col, wrapped, ok := findInLines(lines, tt.term, tt.row, tt.col)
+			if row != tt.wantRow || col != tt.wantCol || wrapped != tt.wrapped || ok != tt.ok {
+				t.Errorf("findInLines(%q, %d, %d) = %d, %d, %v, %v; want %d, %d, %v, %v",
+					tt.term, tt.row, tt.col, row, col, wrapped, ok, tt.wantRow, tt.wantCol, tt.wrapped, tt.ok)
+			}
+		})
+	}
+}
+
+func TestEditorSearchPrompt(t *testing.T) {
+	gui := &GUI{editor: &editorState{Lines: []string{"a: 1", "b: 2", "c: 3", "b: 4"}, Height: 2}}
+	gui.editorSearchStart()
+	for _, r := range "bx" {
+		gui.editorSearchType(r)
+	}
+	gui.editorSearchBackspace()
+	gui.editorSearchSubmit()
+	if gui.editor.Searching || gui.editor.Row != 1 || gui.editor.Col != 0 || gui.editor.LastSearch != "b" {
+		t.Fatalf("after searching b: %+v", gui.editor)
+	}
+
+	// F3 repeats from the cursor; the match below the view scrolls to it.
+	gui.editorFindNext()
+	if gui.editor.Row != 3 || gui.editor.Scroll != 2 {
+		t.Errorf("find next: row %d scroll %d, want 3 and 2", gui.editor.Row, gui.editor.Scroll)
+	}
+	// Ctrl+W opens the prompt with the last term; Ctrl+W again searches.
+	gui.editorSearchStart()
+	if gui.editor.SearchInput != "b" {
+		t.Errorf("prompt = %q, want the last term", gui.editor.SearchInput)
+	}
+	gui.editorSearchStart()
+	if gui.editor.Row != 1 || gui.editor.Notice != "Search wrapped" {
+		t.Errorf("Ctrl+W twice: row %d notice %q", gui.editor.Row, gui.editor.Notice)
+	}
+
+	// Esc leaves the cursor where it was.
+	gui.editorSearchStart()
+	gui.editor.SearchInput = "c"
+	gui.editorSearchCancel()
+	if gui.editor.Searching || gui.editor.Row != 1 || gui.editor.LastSearch != "b" {
+		t.Errorf("after Esc: %+v", gui.editor)
+	}
+	gui.editor.LastSearch = "zzz"
+	gui.editorFindNext()
+	if gui.editor.Row != 1 || gui.editor.Notice != `"zzz" not found` {
+		t.Errorf("not found: row %d notice %q", gui.editor.Row, gui.editor.Notice)
+	}
+}
@@ -0,0 +1,52 @@
+package gui
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// secretMask stands in for every secret value, whatever its length.
+const secretMask = "********"
+
+// secretsFile reports whether path is a kamal secrets file:
+// .kamal/secrets, or .kamal/secrets-<destination> and .kamal/secrets-common.
+func secretsFile(path string) bool {
+	name := filepath.Base(path)
+	return filepath.Base(filepath.Dir(path)) == ".kamal" && (name == "secrets" || strings.HasPrefix(name, "secrets-"))
+}
+
+// maskSecretLine is line as painted while a secrets file is masked:
+// KEY=******** for an assignment with a value, the whole line masked when
+// it has no =, such as a line of a multi-line value. Blank lines, comments
+// without =, and assignments with no value are shown as they are.
+func maskSecretLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return line
+	}
+	key, value, ok := strings.Cut(line, "=")
+	if !ok {
+		if strings.HasPrefix(trimmed, "#") {
+			return line
+		}
+		return line[:len(line)-len(strings.TrimLeft(line, " \t"))] + secretMask
+	}
+	if value == "" {
+		return line
+	}
+	return key + "=" + secretMask
+}
+
+// masked reports whether the editor paints its lines masked.
+func (e *editorState) masked() bool {
+	return e.Secret && !e.Revealed
+}
+
+// editorToggleReveal (Ctrl+V) shows or hides the values of a secrets file.
+// Only the painting changes; Lines always hold the real text.
+func (gui *GUI) editorToggleReveal() {
+	if gui.editor == nil || !gui.editor.Secret {
+		return
+	}
+	gui.editor.Revealed = !gui.editor.Revealed
+}
@@ -0,0 +1,97 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMaskSecretLine(t *testing.T) {
+	tests := []struct {
+		name, line, want string
+	}{
+		{"assignment", "KAMAL_REGISTRY_PASSWORD=s3cret", "KAMAL_REGISTRY_PASSWORD=********"},
+		{"length hidden", "RAILS_MASTER_KEY=0123456789abcdef0123456789abcdef", "RAILS_MASTER_KEY=********"},
+		{"quoted", `DB_PASSWORD="p=ss word"`, "DB_PASSWORD=********"},
+		{"multi-byte value", "GREETING=日本語", "GREETING=********"},
+		{"command substitution", "TOKEN=$(kamal secrets fetch --from op TOKEN)", "TOKEN=********"},
+		{"export", "export API_KEY=abc", "export API_KEY=********"},
+		{"empty value", "EMPTY=", "EMPTY="},
+		{"no =", "MIIEvQIBADANBgkqhkiG9w0BAQEFAASC", "********"},
+		{"no =, indented", "  MIIEvQIBADANBgkq", "  ********"},
+		{"comment", "# Secrets for staging", "# Secrets for staging"},
+		{"commented assignment", "# OLD_TOKEN=abc", "# OLD_TOKEN=********"},
+		{"blank", "   ", "   "},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := maskSecretLine(tt.line); got != tt.want {
+				t.Errorf("maskSecretLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretsFile(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/app/.kamal/secrets":            true,
+		"/app/.kamal/secrets-staging":    true,
+		"/app/.kamal/secrets-common":     true,
+		"/app/config/deploy.yml":         false,
+		"/app/.kamal/hooks/pre-deploy":   false,
+		"/app/config/secrets":            false,
+		"/app/.kamal/secrets.sample.txt": false,
+	} {
+		if got := secretsFile(path); got != want {
+			t.Errorf("secretsFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestEditorSecretsMaskedEditing(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".kamal", "secrets")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("TOKEN=abc\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	gui := &GUI{cwd: dir}
+	if !gui.openEditor(path) || !gui.editor.masked() {
+		t.Fatalf("a secrets file did not open masked: %+v", gui.editor)
+	}
+
+	// The cursor works on the real text while only stars are painted.
+	gui.editorEnd()
+	gui.editorInsertRune('d')
+	if gui.editor.Lines[0] != "TOKEN=abcd" || gui.editor.Col != 10 || !gui.editor.Dirty {
+		t.Errorf("after typing at the end: %q, Col %d, Dirty %v", gui.editor.Lines[0], gui.editor.Col, gui.editor.Dirty)
+	}
+	if !gui.editorSave() {
+		t.Fatal("save failed")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "TOKEN=abcd\n" {
+		t.Errorf("saved %q, want the real text", data)
+	}
+
+	gui.editorToggleReveal()
+	if gui.editor.masked() {
+		t.Error("Ctrl+V did not reveal")
+	}
+	gui.editorToggleReveal()
+	if !gui.editor.masked() {
+		t.Error("Ctrl+V again did not mask")
+	}
+
+	other := filepath.Join(dir, "deploy.yml")
+	if err := os.WriteFile(other, []byte("service: app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gui.openEditor(other)
+	gui.editorToggleReveal()
+	if gui.editor.masked() || gui.editor.Revealed {
+		t.Errorf("a config file is masked or toggled: %+v", gui.editor)
+	}
+}
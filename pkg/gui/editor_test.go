@@ -1,8 +1,13 @@
 package gui
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"unicode/utf8"
+
+	"github.com/jroimartin/gocui"
 )
 
 func TestRuneIndexToByteOffset(t *testing.T) {
@@ -132,3 +137,65 @@ func TestEditorUTF8Enter(t *testing.T) {
 		t.Errorf("Right after enter = %q, want %q", right, "wörld")
 	}
 }
+
+func TestYAMLSaveCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		data    string
+		problem string // prefix
+		hard    bool
+	}{
+		{"valid", "config/deploy.yml", "service: app\nservers:\n  - 10.0.0.1\n", "", false},
+		{"invalid", "config/deploy.yml", "service: app\nservers:\n  - 10.0.0.1\n  web: x\n", "YAML error: line 2:", true},
+		{"bad indent", "config/deploy.staging.yaml", "service: app\n  image: x\n", "YAML error: line 2:", true},
+		{"not a mapping", "config/deploy.yml", "- a\n- b\n", "YAML error: line 1: cannot unmarshal", true},
+		{"ERB", "config/deploy.yml", "service: app\nservers:\n  - <%= ENV[\"HOST\"] %>\n  web: x\n", "Warning: YAML does not parse before ERB is rendered: line 2:", false},
+		{"valid ERB", "config/deploy.yml", "service: app\nimage: \"<%= ENV['IMAGE'] %>\"\n", "", false},
+		{"not YAML", ".kamal/secrets", "KEY=: value: x\n  y", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem, hard := yamlSaveCheck(tt.path, []byte(tt.data))
+			if !strings.HasPrefix(problem, tt.problem) || (tt.problem == "") != (problem == "") || hard != tt.hard {
+				t.Errorf("yamlSaveCheck = %q, %v; want %q…, %v", problem, hard, tt.problem, tt.hard)
+			}
+		})
+	}
+}
+
+func TestEditorSaveInvalidYAMLAsks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy.yml")
+	if err := os.WriteFile(path, []byte("service: app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gui := &GUI{g: &gocui.Gui{}, logPause: newLogPause(pauseBufLimit)}
+	if !gui.openEditor(path) {
+		t.Fatal("openEditor failed")
+	}
+	gui.editor.Lines = []string{"service: app", "  image: x"}
+	gui.editor.Dirty = true
+
+	if gui.editorSave() || !gui.editor.ConfirmSave || !strings.HasPrefix(gui.editor.Notice, "YAML error: line 2") {
+		t.Fatalf("save of invalid YAML: ConfirmSave %v, Notice %q", gui.editor.ConfirmSave, gui.editor.Notice)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "service: app\n" {
+		t.Fatalf("invalid YAML written: %q", data)
+	}
+	gui.editorConfirmSave(false)
+	if data, _ := os.ReadFile(path); gui.editor.ConfirmSave || string(data) != "service: app\n" || !gui.editor.Dirty {
+		t.Fatalf("n saved anyway: %q", data)
+	}
+
+	gui.editorSave()
+	gui.editorConfirmSave(true)
+	if data, _ := os.ReadFile(path); string(data) != "service: app\n  image: x" || gui.editor.Dirty {
+		t.Errorf("y did not save: %q, dirty %v", data, gui.editor.Dirty)
+	}
+
+	// ERB only warns.
+	gui.editor.Lines = []string{"service: <%= ENV['S'] %>", "  image: x"}
+	if !gui.editorSave() || gui.editor.ConfirmSave || !strings.HasPrefix(gui.editor.Notice, "Warning:") {
+		t.Errorf("ERB save: ConfirmSave %v, Notice %q", gui.editor.ConfirmSave, gui.editor.Notice)
+	}
+}
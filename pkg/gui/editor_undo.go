@@ -0,0 +1,83 @@
+package gui
+
+// editorUndoLimit is how many edits Ctrl+Z can take back.
+const editorUndoLimit = 200
+
+// editorSnapshot is the buffer and cursor before or after one edit.
+type editorSnapshot struct {
+	Lines    []string
+	Row, Col int
+}
+
+func (e *editorState) snapshot() editorSnapshot {
+	return editorSnapshot{Lines: append([]string(nil), e.Lines...), Row: e.Row, Col: e.Col}
+}
+
+func (e *editorState) restore(s editorSnapshot) {
+	e.Lines, e.Row, e.Col = s.Lines, s.Row, s.Col
+	if e.Scroll > e.Row {
+		e.Scroll = e.Row
+	}
+	e.Dirty = !equalLines(e.Lines, e.Saved)
+}
+
+// record keeps the buffer as it is before an edit, for undo. A new edit
+// drops what could be redone.
+func (e *editorState) record() {
+	e.Undo = append(e.Undo, e.snapshot())
+	if len(e.Undo) > editorUndoLimit {
+		e.Undo = e.Undo[len(e.Undo)-editorUndoLimit:]
+	}
+	e.Redo = nil
+}
+
+// markSaved notes the buffer as what is on disk, so undoing back to it
+// leaves the editor clean.
+func (e *editorState) markSaved() {
+	e.Saved = append([]string(nil), e.Lines...)
+	e.Dirty = false
+}
+
+// editorUndo (Ctrl+Z) takes back the last edit.
+func (gui *GUI) editorUndo() {
+	e := gui.editor
+	if e == nil {
+		return
+	}
+	if len(e.Undo) == 0 {
+		e.Notice = "Nothing to undo"
+		return
+	}
+	e.Redo = append(e.Redo, e.snapshot())
+	e.restore(e.Undo[len(e.Undo)-1])
+	e.Undo = e.Undo[:len(e.Undo)-1]
+	e.Notice = ""
+}
+
+// editorRedo (Ctrl+Y) applies the last undone edit again.
+func (gui *GUI) editorRedo() {
+	e := gui.editor
+	if e == nil {
+		return
+	}
+	if len(e.Redo) == 0 {
+		e.Notice = "Nothing to redo"
+		return
+	}
+	e.Undo = append(e.Undo, e.snapshot())
+	e.restore(e.Redo[len(e.Redo)-1])
+	e.Redo = e.Redo[:len(e.Redo)-1]
+	e.Notice = ""
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jroimartin/gocui"
+)
+
+func TestEditorUndoRedo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets")
+	original := "KEY=1\nTOKEN=日本"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	gui := &GUI{g: &gocui.Gui{}}
+	if !gui.openEditor(path) {
+		t.Fatal("openEditor failed")
+	}
+	want := append([]string(nil), gui.editor.Lines...)
+
+	// Type, split a line, delete across a multi-byte rune and join lines.
+	gui.editor.Row, gui.editor.Col = 1, 8
+	for _, r := range "語x" {
+		gui.editorInsertRune(r)
+	}
+	gui.editorBackspace()
+	gui.editorBackspace()
+	gui.editorEnter()
+	gui.editorInsertRune('!')
+	gui.editor.Row, gui.editor.Col = 1, 0
+	gui.editorBackspace()
+	edited := append([]string(nil), gui.editor.Lines...)
+	if reflect.DeepEqual(edited, want) || !gui.editor.Dirty {
+		t.Fatalf("edits did not change the buffer: %q", edited)
+	}
+
+	n := len(gui.editor.Undo)
+	for i := 0; i < n; i++ {
+		gui.editorUndo()
+	}
+	if !reflect.DeepEqual(gui.editor.Lines, want) || gui.editor.Dirty {
+		t.Fatalf("after undoing everything: %q dirty %v, want %q clean", gui.editor.Lines, gui.editor.Dirty, want)
+	}
+	if gui.editor.Row != 1 || gui.editor.Col != 8 {
+		t.Errorf("cursor = %d:%d, want back where typing started (1:8)", gui.editor.Row, gui.editor.Col)
+	}
+	gui.editorUndo()
+	if gui.editor.Notice != "Nothing to undo" {
+		t.Errorf("notice = %q", gui.editor.Notice)
+	}
+
+	for i := 0; i < n; i++ {
+		gui.editorRedo()
+	}
+	if !reflect.DeepEqual(gui.editor.Lines, edited) || !gui.editor.Dirty {
+		t.Fatalf("after redoing everything: %q, want %q", gui.editor.Lines, edited)
+	}
+
+	// Saving makes the saved buffer the clean one; a new edit drops redo.
+	if !gui.editorWrite() {
+		t.Fatal("save failed")
+	}
+	gui.editorUndo()
+	if !gui.editor.Dirty {
+		t.Error("undo past the save is not dirty")
+	}
+	gui.editorInsertRune('z')
+	if len(gui.editor.Redo) != 0 {
+		t.Error("an edit kept the redo stack")
+	}
+}
+
+func TestEditorUndoLimit(t *testing.T) {
+	gui := &GUI{editor: &editorState{Lines: []string{""}}}
+	gui.editor.markSaved()
+	for i := 0; i < editorUndoLimit+50; i++ {
+		gui.editorInsertRune('a')
+	}
+	if len(gui.editor.Undo) != editorUndoLimit {
+		t.Fatalf("undo stack = %d, want capped at %d", len(gui.editor.Undo), editorUndoLimit)
+	}
+	for range gui.editor.Undo {
+		gui.editorUndo()
+	}
+	if got := len(gui.editor.Lines[0]); got != 50 || !gui.editor.Dirty {
+		t.Errorf("after undoing all kept edits: %d runes, dirty %v; want the 50 oldest edits kept", got, gui.editor.Dirty)
+	}
+}
@@ -0,0 +1,81 @@
+package gui
+
+// emptyState identifies a panel with nothing to show yet. All empty-state copy
+// lives in emptyStateText so it can be tested and translated in one place.
+type emptyState int
+
+const (
+	emptyNoDestinations emptyState = iota
+	emptyNoSelection
+	emptyNeverPolled
+	emptyKamalError
+	emptyProjectLog
+	emptyServerNoApps
+	emptyServerDetails
+	emptyServerLog
+	emptyNoAccessories
+	emptyConfigError
+)
+
+var emptyStateText = map[emptyState][]string{
+	emptyNoDestinations: {
+		"No config/deploy*.yml found in this directory.",
+		"",
+		"Run lazykamal from a Kamal app root, or pass the path:",
+		"  lazykamal /path/to/app",
+		"New project? Open the menu with m and choose Other > Init.",
+	},
+	emptyNoSelection: {
+		"No app selected.",
+		"Use ↑/↓ in the Apps panel, then press Enter for commands.",
+	},
+	emptyNeverPolled: {
+		"Waiting for the first status poll...",
+		"Press r to refresh now.",
+	},
+	emptyKamalError: {
+		"Kamal could not report status for this app.",
+		"Check that config/deploy*.yml is valid and the hosts are reachable.",
+		"Try Other > Config (kamal config) to see the parsed config, or r to retry.",
+	},
+	emptyProjectLog: {
+		"Command output will appear here.",
+		"Press Enter on an app to pick a command, or ? for all shortcuts.",
+	},
+	emptyServerNoApps: {
+		"No Kamal apps found on this server.",
+		"",
+		"Make sure apps are deployed with Kamal",
+		"and Docker is running. Press r to scan again.",
+	},
+	emptyServerDetails: {
+		"No app selected.",
+		"",
+		"Common first actions:",
+		"  Enter  open the app menu",
+		"  r      rescan the server for apps",
+		"  ?      show all shortcuts",
+	},
+	emptyServerLog: {
+		"Output will appear here.",
+		"",
+		"Try: Enter > Logs (live) to tail an app,",
+		"or Enter > Containers to act on one container.",
+	},
+	emptyNoAccessories: {
+		"No accessories configured — add one to config/deploy.yml",
+		"(press e to edit, t to insert a template).",
+		"",
+		"Press b to go back, or ? for all shortcuts.",
+	},
+	emptyConfigError: {
+		"This app's deploy config could not be read or parsed.",
+		"Commands are disabled until it is fixed.",
+		"Press Enter to edit it.",
+	},
+}
+
+// emptyStateLines returns the guidance lines for s.
+func emptyStateLines(s emptyState) []string {
+	return emptyStateText[s]
+}
@@ -0,0 +1,57 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestEmptyStateTextComplete(t *testing.T) {
+	states := []emptyState{
+		emptyNoDestinations,
+		emptyNoSelection,
+		emptyNeverPolled,
+		emptyKamalError,
+		emptyProjectLog,
+		emptyServerNoApps,
+		emptyServerDetails,
+		emptyServerLog,
+		emptyNoAccessories,
+		emptyConfigError,
+	}
+	for _, s := range states {
+		lines := emptyStateLines(s)
+		if len(lines) == 0 {
+			t.Errorf("empty state %d has no text", s)
+			continue
+		}
+		if lines[0] == "" {
+			t.Errorf("empty state %d starts with a blank line", s)
+		}
+	}
+	if len(emptyStateText) != len(states) {
+		t.Errorf("emptyStateText has %d entries, test covers %d", len(emptyStateText), len(states))
+	}
+}
+
+func TestEmptyStatesNameNextKey(t *testing.T) {
+	// Guidance should tell the user what to press next.
+	for s, lines := range emptyStateText {
+		text := strings.Join(lines, " ")
+		if !strings.Contains(text, "Enter") && !strings.Contains(text, " r ") &&
+			!strings.Contains(text, " m ") && !strings.Contains(text, "?") {
+			t.Errorf("empty state %d does not mention a key: %q", s, text)
+		}
+	}
+}
+
+func TestFirstErrorLine(t *testing.T) {
+	r := kamal.Result{Stderr: "\n  ERROR (SSHKit::Runner::ExecuteError): boom\nmore\n", ExitCode: 1}
+	if got := firstErrorLine(r, nil); got != "ERROR (SSHKit::Runner::ExecuteError): boom" {
+		t.Errorf("firstErrorLine() = %q", got)
+	}
+	if got := firstErrorLine(kamal.Result{ExitCode: 3}, nil); got != "exit code 3" {
+		t.Errorf("firstErrorLine() = %q, want exit code 3", got)
+	}
+}
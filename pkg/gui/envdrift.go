@@ -0,0 +1,104 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// containerEnv is the env of the running app containers, per host, as last
+// fetched for Env drift.
+type containerEnv struct {
+	byHost  map[string]map[string]string
+	fetched time.Time
+}
+
+// showEnvDrift compares the configured env with the running containers and
+// logs the drift. The container env is fetched once per destination and
+// reused until refresh is set (R on the Config menu).
+func (gui *GUI) showEnvDrift(refresh bool) {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		gui.logError("No app selected")
+		return
+	}
+	key := hostsKey(dest)
+	cfg := dest.Env()
+	if cached, ok := gui.envCache[key]; ok && !refresh {
+		gui.appendLog(envDriftLines(cfg, cached.byHost, time.Since(cached.fetched)))
+		return
+	}
+
+	opts := gui.runOpts()
+	var byHost map[string]map[string]string
+	gui.runQueryThen("Env Drift", func(stopCh <-chan struct{}) (kamal.Result, error) {
+		res, err := kamal.RunKamalWithStop([]string{"app", "exec", "--reuse", "env"}, opts, stopCh)
+		if err != nil || res.ExitCode != 0 {
+			return kamal.Result{Stderr: res.Stderr, ExitCode: res.ExitCode}, err
+		}
+		byHost = kamal.ParseContainerEnv(res.Stdout)
+		// The output holds secret values; only the drift is logged.
+		return kamal.Result{}, nil
+	}, func(stopCh <-chan struct{}, took time.Duration) {
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.envCache[key] = containerEnv{byHost: byHost, fetched: time.Now()}
+			return nil
+		})
+		gui.appendLog(envDriftLines(cfg, byHost, 0))
+	})
+}
+
+func (gui *GUI) keyEnvDriftRefresh(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ScreenConfig {
+		return nil
+	}
+	gui.showEnvDrift(true)
+	return nil
+}
+
+// envDriftLines renders the drift panel: per host, the variable names that
+// are missing from the container, no longer configured, or changed. Values
+// are never shown. age is how old a cached container env is (0 when fresh).
+func envDriftLines(cfg kamal.ConfiguredEnv, byHost map[string]map[string]string, age time.Duration) []string {
+	lines := []string{cyan("── Env drift (running vs config) ──")}
+	if len(byHost) == 0 {
+		lines = append(lines, yellow("  No running app containers found to compare against."))
+		return lines
+	}
+	hosts := make([]string, 0, len(byHost))
+	for h := range byHost {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+
+	drifted := false
+	for _, h := range hosts {
+		drift := kamal.CompareEnv(cfg, byHost[h])
+		if drift.Empty() {
+			lines = append(lines, green("  "+iconSuccess+" "+h+": in sync"))
+			continue
+		}
+		drifted = true
+		lines = append(lines, yellow("  "+iconWarning+" "+h+":"))
+		if len(drift.Missing) > 0 {
+			lines = append(lines, green("    + "+strings.Join(drift.Missing, ", "))+dim("  (configured, not in container)"))
+		}
+		if len(drift.Extra) > 0 {
+			lines = append(lines, red("    - "+strings.Join(drift.Extra, ", "))+dim("  (in container, no longer configured)"))
+		}
+		if len(drift.Changed) > 0 {
+			lines = append(lines, yellow("    ~ "+strings.Join(drift.Changed, ", "))+dim("  (value differs)"))
+		}
+	}
+	if drifted {
+		lines = append(lines, yellow("  Redeploy will reconcile the running containers with the config."))
+	}
+	if age > 0 {
+		lines = append(lines, dim(fmt.Sprintf("  Container env fetched %s ago — R to refresh", formatDuration(age))))
+	}
+	return lines
+}
@@ -0,0 +1,39 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestEnvDriftLines(t *testing.T) {
+	cfg := kamal.ConfiguredEnv{
+		Clear:  map[string]string{"RAILS_ENV": "production", "LOG_LEVEL": "debug"},
+		Secret: []string{"STRIPE_KEY"},
+	}
+	byHost := map[string]map[string]string{
+		"10.0.0.1": {"RAILS_ENV": "production", "LOG_LEVEL": "debug", "STRIPE_KEY": "sk_live_secret"},
+		"10.0.0.2": {"RAILS_ENV": "production", "LOG_LEVEL": "info", "OLD_TOKEN": "tok_secret"},
+	}
+	out := strings.Join(envDriftLines(cfg, byHost, 2*time.Minute), "\n")
+	for _, want := range []string{"10.0.0.1: in sync", "+ STRIPE_KEY", "- OLD_TOKEN", "~ LOG_LEVEL", "Redeploy will reconcile", "R to refresh"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("drift panel missing %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"sk_live_secret", "tok_secret", "info"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("drift panel shows value %q:\n%s", secret, out)
+		}
+	}
+
+	inSync := strings.Join(envDriftLines(cfg, map[string]map[string]string{"10.0.0.1": byHost["10.0.0.1"]}, 0), "\n")
+	if strings.Contains(inSync, "Redeploy") || strings.Contains(inSync, "refresh") {
+		t.Errorf("in-sync fresh panel has drift hints:\n%s", inSync)
+	}
+	if none := strings.Join(envDriftLines(cfg, nil, 0), "\n"); !strings.Contains(none, "No running app containers") {
+		t.Errorf("empty panel = %q", none)
+	}
+}
@@ -0,0 +1,130 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	etaWindow  = 20 // most recent samples the estimate is based on
+	etaOutlier = 3  // samples more than this factor off the median are ignored
+)
+
+// etaEstimate is the expected remaining time of a running deploy.
+type etaEstimate struct {
+	Typical time.Duration // median of the recorded durations
+	Low     time.Duration // remaining time, optimistic end
+	High    time.Duration // remaining time, pessimistic end
+	Samples int           // durations the estimate is based on
+	Overdue bool          // elapsed is past every plausible duration
+}
+
+// estimateETA estimates how much of a deploy is left after elapsed from
+// earlier durations of the same command, oldest first. There is no
+// estimate without history. Outliers (a first deploy that built every
+// layer, a run left waiting on a lock) are dropped, and the range is
+// widened when few samples remain so a single run does not pose as a
+// prediction.
+func estimateETA(samples []time.Duration, elapsed time.Duration) (etaEstimate, bool) {
+	if len(samples) > etaWindow {
+		samples = samples[len(samples)-etaWindow:]
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	if len(sorted) == 0 || sorted[len(sorted)-1] <= 0 {
+		return etaEstimate{}, false
+	}
+	median := quantile(sorted, 0.5)
+	var kept []time.Duration
+	for _, s := range sorted {
+		if s > 0 && s <= median*etaOutlier && s*etaOutlier >= median {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == 0 {
+		kept = sorted
+	}
+	median = quantile(kept, 0.5)
+
+	spread := time.Duration(float64(median) * sparseWidening(len(kept)))
+	low, high := quantile(kept, 0.25), quantile(kept, 0.75)
+	if median-spread < low {
+		low = median - spread
+	}
+	if median+spread > high {
+		high = median + spread
+	}
+	e := etaEstimate{Typical: median, Samples: len(kept)}
+	e.Low, e.High = clampRemaining(low-elapsed), clampRemaining(high-elapsed)
+	e.Overdue = e.High == 0
+	return e, true
+}
+
+// sparseWidening is the fraction of the median the range spans on either
+// side at least, by sample count.
+func sparseWidening(n int) float64 {
+	switch {
+	case n <= 1:
+		return 0.5
+	case n == 2:
+		return 0.35
+	case n < 5:
+		return 0.2
+	}
+	return 0.05
+}
+
+// quantile interpolates the q-quantile of sorted, which must not be empty.
+func quantile(sorted []time.Duration, q float64) time.Duration {
+	pos := q * float64(len(sorted)-1)
+	i := int(pos)
+	if i+1 >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + time.Duration(frac*float64(sorted[i+1]-sorted[i]))
+}
+
+func clampRemaining(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// etaText renders an estimate for the header, e.g.
+// "typically 7m (p50), ETA ~3m" or "typically 7m (p50, 1 deploy), ETA 1m–5m".
+func etaText(e etaEstimate) string {
+	typical := "typically " + approxDuration(e.Typical) + " (p50"
+	if e.Samples < 5 {
+		noun := "deploys"
+		if e.Samples == 1 {
+			noun = "deploy"
+		}
+		typical += fmt.Sprintf(", %d %s", e.Samples, noun)
+	}
+	typical += ")"
+	switch {
+	case e.Overdue:
+		return typical + ", taking longer than usual"
+	case approxDuration(e.Low) == approxDuration(e.High):
+		return typical + ", ETA ~" + approxDuration(e.High)
+	case e.Low == 0:
+		return typical + ", ETA <" + approxDuration(e.High)
+	}
+	return typical + ", ETA " + approxDuration(e.Low) + "–" + approxDuration(e.High)
+}
+
+// approxDuration rounds d for estimates: to 10 seconds under a minute,
+// to whole minutes above.
+func approxDuration(d time.Duration) string {
+	if r := d.Round(10 * time.Second); r < time.Minute {
+		return fmt.Sprintf("%ds", int(r.Seconds()))
+	}
+	d = d.Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+}
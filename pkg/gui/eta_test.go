@@ -0,0 +1,114 @@
+package gui
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mins(ms ...float64) []time.Duration {
+	out := make([]time.Duration, len(ms))
+	for i, m := range ms {
+		out[i] = time.Duration(m * float64(time.Minute))
+	}
+	return out
+}
+
+func TestEstimateETANoHistory(t *testing.T) {
+	if _, ok := estimateETA(nil, time.Minute); ok {
+		t.Error("estimateETA(no samples) = ok; the first deploy must not show an ETA")
+	}
+	if _, ok := estimateETA(mins(0), time.Minute); ok {
+		t.Error("estimateETA(zero samples) = ok")
+	}
+}
+
+func TestEstimateETASingleSample(t *testing.T) {
+	e, ok := estimateETA(mins(7), 4*time.Minute)
+	if !ok {
+		t.Fatal("estimateETA(one sample) not ok")
+	}
+	// One run says little: ±50% of 7m around the 3m left.
+	if e.Typical != 7*time.Minute || e.Low != 0 || e.High != 6*time.Minute+30*time.Second {
+		t.Errorf("estimate = %+v", e)
+	}
+	if got := etaText(e); got != "typically 7m (p50, 1 deploy), ETA <7m" {
+		t.Errorf("etaText() = %q", got)
+	}
+}
+
+func TestEstimateETADenseHistory(t *testing.T) {
+	e, ok := estimateETA(mins(7, 7, 7, 7, 7, 7), 4*time.Minute)
+	if !ok {
+		t.Fatal("not ok")
+	}
+	if got := etaText(e); got != "typically 7m (p50), ETA ~3m" {
+		t.Errorf("etaText() = %q (estimate %+v)", got, e)
+	}
+}
+
+func TestEstimateETAOutliers(t *testing.T) {
+	// A 40m first deploy that built every layer, and a 1m no-op.
+	e, _ := estimateETA(mins(40, 6, 7, 7, 8, 7, 0.1), 0)
+	if e.Typical != 7*time.Minute || e.Samples != 5 {
+		t.Errorf("estimate = %+v, want median 7m from 5 samples", e)
+	}
+}
+
+func TestEstimateETAOverdue(t *testing.T) {
+	e, _ := estimateETA(mins(7, 7, 7, 7, 7), 12*time.Minute)
+	if !e.Overdue || e.Low != 0 || e.High != 0 {
+		t.Errorf("estimate = %+v, want overdue", e)
+	}
+	if got := etaText(e); got != "typically 7m (p50), taking longer than usual" {
+		t.Errorf("etaText() = %q", got)
+	}
+}
+
+func TestEstimateETAWindow(t *testing.T) {
+	old := mins(20, 20, 20, 20, 20, 20, 20, 20, 20, 20)
+	recent := mins(5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5)
+	e, _ := estimateETA(append(old, recent...), 0)
+	if e.Typical != 5*time.Minute {
+		t.Errorf("Typical = %v, want only the last %d samples used", e.Typical, etaWindow)
+	}
+}
+
+func TestApproxDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                              "0s",
+		42 * time.Second:               "40s",
+		56 * time.Second:               "1m",
+		3*time.Minute + 29*time.Second: "3m",
+		time.Hour + 4*time.Minute + 40*time.Second: "1h5m",
+	}
+	for d, want := range tests {
+		if got := approxDuration(d); got != want {
+			t.Errorf("approxDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestDeployHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lazykamal", "deploy-durations.json")
+	h := loadDeployHistory(path)
+	if got := h.samples("config/deploy.staging.yml", "Deploy"); len(got) != 0 {
+		t.Fatalf("samples() on a new history = %v", got)
+	}
+	for i := 0; i < historyKeep+2; i++ {
+		if err := h.record("config/deploy.staging.yml", "Deploy", time.Duration(i+1)*time.Second); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.record("config/deploy.staging.yml", "Redeploy", time.Minute)
+
+	reloaded := loadDeployHistory(path)
+	got := reloaded.samples("config/deploy.staging.yml", "Deploy")
+	if len(got) != historyKeep || got[0] != 3*time.Second {
+		t.Errorf("reloaded Deploy samples: len %d, first %v", len(got), got[0])
+	}
+	if got := reloaded.samples("config/deploy.staging.yml", "Redeploy"); !reflect.DeepEqual(got, []time.Duration{time.Minute}) {
+		t.Errorf("Redeploy samples = %v", got)
+	}
+}
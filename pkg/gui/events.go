@@ -0,0 +1,50 @@
+package gui
+
+import (
+	"path/filepath"
+
+	"github.com/shuvro/lazykamal/pkg/events"
+)
+
+// startEvents opens the event socket when .lazykamal.yml sets event_socket.
+// A failure only logs a warning; the TUI works the same without it.
+func (gui *GUI) startEvents() {
+	if gui.project == nil || gui.project.EventSocket == "" {
+		return
+	}
+	path := gui.project.EventSocket
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(gui.cwd, path)
+	}
+	srv, err := events.Listen(path, gui.eventStatus)
+	if err != nil {
+		gui.appendLog([]string{statusLine("warning", "Event socket disabled: "+err.Error())})
+		return
+	}
+	gui.events = srv
+	gui.logInfo("Publishing events on " + path)
+}
+
+// eventStatus answers "status" requests on the event socket.
+func (gui *GUI) eventStatus() events.Event {
+	gui.cmdMu.Lock()
+	defer gui.cmdMu.Unlock()
+	e := events.Event{Running: gui.running, Command: gui.runningCmd, Destination: gui.eventDestination(), Pending: gui.pendingConfirm}
+	if gui.running {
+		e.DurationMs = gui.cmdClock.elapsed().Milliseconds()
+	}
+	return e
+}
+
+// eventDestination names the selected destination in events: the
+// destination name, or the service when deploy.yml is the only config.
+func (gui *GUI) eventDestination() string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return ""
+	}
+	if dest.Name != "" {
+		return dest.Name
+	}
+	return dest.Service
+}
@@ -0,0 +1,113 @@
+package gui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// newFakeGUI is a GUI on the demo project, staging selected, whose kamal
+// commands go to runner. It has no terminal: redraws are queued and dropped.
+func newFakeGUI(t *testing.T, runner *kamal.FakeRunner) *GUI {
+	t.Helper()
+	dir := t.TempDir()
+	if err := kamal.WriteDemoProject(dir); err != nil {
+		t.Fatal(err)
+	}
+	gui := &GUI{
+		g:        &gocui.Gui{},
+		cwd:      dir,
+		screen:   ScreenApps,
+		tags:     newLogTags(),
+		long:     newLongLines("x"),
+		runner:   runner,
+		logPause: newLogPause(pauseBufLimit),
+	}
+	gui.loadDestinations()
+	for i, d := range gui.destinations {
+		if d.Name == "staging" {
+			gui.selectedApp = i
+		}
+	}
+	return gui
+}
+
+// waitIdle waits for the running command, if any, to finish.
+func waitIdle(t *testing.T, gui *GUI) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		gui.cmdMu.Lock()
+		running := gui.running
+		gui.cmdMu.Unlock()
+		if !running {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("command still running")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestExecMenusRunSubcommands(t *testing.T) {
+	flags := []string{"--destination", "staging"}
+	tests := []struct {
+		screen  Screen
+		idx     int
+		confirm bool
+		want    [][]string // kamal argv, in order, global flags left out
+	}{
+		{ScreenDeploy, 0, false, [][]string{{"app", "version"}, {"deploy"}, {"app", "version"}}},
+		{ScreenDeploy, 1, false, [][]string{{"app", "version"}, {"deploy", "--skip-push"}, {"app", "version"}}},
+		{ScreenDeploy, 2, false, [][]string{{"app", "version"}, {"redeploy"}, {"app", "version"}}},
+		{ScreenDeploy, 4, false, [][]string{{"setup"}}},
+		{ScreenDeploy, 5, false, [][]string{{"app", "version"}, {"deploy", "--no-cache"}, {"app", "version"}}},
+		{ScreenDeploy, 6, false, [][]string{{"app", "version"}, {"redeploy", "--no-cache"}, {"app", "version"}}},
+		{ScreenDeploy, 7, false, [][]string{{"setup", "--no-cache"}}},
+		{ScreenApp, 1, false, [][]string{{"app", "start"}}},
+		{ScreenApp, 2, true, [][]string{{"app", "stop"}}},
+		{ScreenApp, 3, false, [][]string{{"app", "restart"}}},
+		{ScreenApp, 4, false, [][]string{{"app", "logs"}}},
+		{ScreenApp, 5, false, [][]string{{"app", "containers"}}},
+		{ScreenApp, 6, false, [][]string{{"app", "details"}}},
+		{ScreenApp, 7, false, [][]string{{"app", "images"}}},
+		{ScreenApp, 8, false, [][]string{{"app", "version"}}},
+		{ScreenApp, 11, false, [][]string{{"app", "maintenance"}}},
+		{ScreenApp, 12, false, [][]string{{"app", "live"}}},
+		{ScreenApp, 13, true, [][]string{{"app", "remove"}}},
+		{ScreenApp, 16, false, [][]string{{"app", "exec", "--detach", "whoami"}}},
+	}
+	for _, tt := range tests {
+		runner := &kamal.FakeRunner{}
+		gui := newFakeGUI(t, runner)
+		gui.screen, gui.submenuIdx = tt.screen, tt.idx
+		label := menus[tt.screen][tt.idx].Label
+		if tt.screen == ScreenDeploy {
+			gui.execDeploy()
+		} else {
+			gui.execApp()
+		}
+		if tt.confirm {
+			if gui.confirm == nil {
+				t.Errorf("%s: ran without asking", label)
+				continue
+			}
+			if calls := runner.Calls(); len(calls) != 0 {
+				t.Errorf("%s: ran %q before the answer", label, calls)
+			}
+			gui.answerConfirm(confirmYes, "test")
+		}
+		waitIdle(t, gui)
+		var want [][]string
+		for _, w := range tt.want {
+			want = append(want, append(append([]string{}, w...), flags...))
+		}
+		if got := runner.Calls(); !reflect.DeepEqual(got[:min(len(got), len(want))], want) {
+			t.Errorf("%s: ran %q, want %q", label, got, want)
+		}
+	}
+}
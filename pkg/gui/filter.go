@@ -0,0 +1,96 @@
+package gui
+
+import (
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// SetOnly restricts the Apps list to destinations whose label matches one of
+// the globs (from --only) for this session.
+func (gui *GUI) SetOnly(patterns []string) {
+	gui.only = patterns
+	gui.applyDestinationFilters()
+}
+
+// applyDestinationFilters rebuilds the Apps list from the discovered
+// destinations: hidden_destinations first, then --only, then the interactive
+// filter. The selection follows the destination, not the index.
+func (gui *GUI) applyDestinationFilters() {
+	selected := ""
+	if dest := gui.selectedDestination(); dest != nil {
+		selected = dest.ConfigPath
+	}
+	visible, hidden := gui.projectConfig().ApplyDestinations(gui.discovered, gui.showAllDests)
+	visible = kamal.FilterDestinations(visible, gui.only)
+	if gui.appFilter != "" {
+		visible = kamal.FilterDestinations(visible, []string{gui.appFilter})
+	}
+	gui.destinations, gui.hiddenDests = sortDestinations(visible), hidden
+	gui.selectedApp = 0
+	gui.selectDestination(selected)
+}
+
+// selectDestination selects the listed destination with configPath, if any.
+func (gui *GUI) selectDestination(configPath string) {
+	for i, d := range gui.destinations {
+		if d.ConfigPath == configPath {
+			gui.selectedApp = i
+			return
+		}
+	}
+}
+
+// projectConfig is the loaded .lazykamal.yml, or an empty one before the
+// first scan.
+func (gui *GUI) projectConfig() *kamal.ProjectConfig {
+	if gui.project == nil {
+		return &kamal.ProjectConfig{}
+	}
+	return gui.project
+}
+
+// filterPickerItems lists the destinations the interactive filter would keep.
+func (gui *GUI) filterPickerItems(pattern string) []pickerItem {
+	visible, _ := gui.projectConfig().ApplyDestinations(gui.discovered, gui.showAllDests)
+	visible = kamal.FilterDestinations(kamal.FilterDestinations(visible, gui.only), []string{pattern})
+	items := make([]pickerItem, 0, len(visible))
+	for _, d := range visible {
+		items = append(items, pickerItem{Label: d.Label(), Value: d.ConfigPath})
+	}
+	return items
+}
+
+// keyFilterApps opens the live filter on the Apps screen, unless the Output
+// has focus and / searches it instead. Enter keeps the filter (an empty one
+// clears it) and selects the highlighted app.
+func (gui *GUI) keyFilterApps(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ScreenApps || gui.logFocus {
+		return nil
+	}
+	p := &listPicker{
+		Title:  "Filter apps (glob or text)",
+		Input:  gui.appFilter,
+		Adding: true,
+	}
+	p.Filter = func(input string) []pickerItem { return gui.filterPickerItems(input) }
+	p.Items = p.Filter(p.Input)
+	if dest := gui.selectedDestination(); dest != nil {
+		for i, it := range p.Items {
+			if it.Value == dest.ConfigPath {
+				p.Cursor = i
+			}
+		}
+	}
+	p.OnDone = func(values []string) {
+		gui.appFilter = strings.TrimSpace(values[0])
+		gui.applyDestinationFilters()
+		if len(values) > 1 {
+			gui.selectDestination(values[1])
+		}
+		gui.resetStatus()
+	}
+	gui.showPicker(p)
+	return nil
+}
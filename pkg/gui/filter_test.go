@@ -0,0 +1,84 @@
+package gui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func filterTestGUI() *GUI {
+	return &GUI{
+		project: &kamal.ProjectConfig{HiddenDestinations: []string{"scratch"}},
+		discovered: []kamal.DeployDestination{
+			{Service: "myapp", Name: "production", ConfigPath: "config/deploy.production.yml"},
+			{Service: "myapp", Name: "staging", ConfigPath: "config/deploy.staging.yml"},
+			{Service: "myapp", Name: "scratch", ConfigPath: "config/deploy.scratch.yml"},
+			{Service: "other", Name: "staging", ConfigPath: "other/config/deploy.staging.yml"},
+		},
+	}
+}
+
+func listedPaths(gui *GUI) []string {
+	var out []string
+	for _, d := range gui.destinations {
+		out = append(out, d.ConfigPath)
+	}
+	return out
+}
+
+func TestApplyDestinationFilters(t *testing.T) {
+	gui := filterTestGUI()
+	gui.SetOnly([]string{"myapp*"})
+	want := []string{"config/deploy.staging.yml", "config/deploy.production.yml"}
+	if got := listedPaths(gui); !reflect.DeepEqual(got, want) {
+		t.Fatalf("--only myapp* listed %q, want %q", got, want)
+	}
+	if gui.hiddenDests != 1 {
+		t.Errorf("hiddenDests = %d, want 1", gui.hiddenDests)
+	}
+
+	// Selection follows the destination as the list narrows and widens.
+	gui.selectedApp = 1
+	gui.appFilter = "prod"
+	gui.applyDestinationFilters()
+	if got := listedPaths(gui); !reflect.DeepEqual(got, []string{"config/deploy.production.yml"}) {
+		t.Fatalf("filter prod listed %q", got)
+	}
+	if gui.selectedApp != 0 {
+		t.Errorf("selectedApp = %d after narrowing, want 0", gui.selectedApp)
+	}
+	gui.appFilter = ""
+	gui.applyDestinationFilters()
+	if gui.selectedApp != 1 {
+		t.Errorf("selectedApp = %d after clearing the filter, want 1", gui.selectedApp)
+	}
+
+	// Hidden destinations stay hidden unless shown, filter or not.
+	gui.showAllDests = true
+	gui.appFilter = "scr"
+	gui.applyDestinationFilters()
+	if got := listedPaths(gui); !reflect.DeepEqual(got, []string{"config/deploy.scratch.yml"}) {
+		t.Errorf("show all + filter scr listed %q", got)
+	}
+	gui.showAllDests = false
+	gui.applyDestinationFilters()
+	if len(gui.destinations) != 0 {
+		t.Errorf("hidden destination listed: %q", listedPaths(gui))
+	}
+}
+
+func TestFilterPickerItems(t *testing.T) {
+	gui := filterTestGUI()
+	p := &listPicker{Adding: true, Filter: gui.filterPickerItems}
+	p.refilter()
+	if len(p.Items) != 3 {
+		t.Fatalf("empty filter gave %d items, want 3", len(p.Items))
+	}
+	p.Cursor = 2
+	p.Input = "other"
+	p.refilter()
+	if len(p.Items) != 1 || p.Items[0].Value != "other/config/deploy.staging.yml" || p.Cursor != 0 {
+		t.Errorf("filter other gave %+v cursor %d", p.Items, p.Cursor)
+	}
+}
@@ -0,0 +1,51 @@
+package gui
+
+import (
+	"github.com/shuvro/lazykamal/pkg/git"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// refreshGit reads the branch and commit of the project's working tree for
+// the header. Outside a git repository the header shows none.
+func (gui *GUI) refreshGit() {
+	var head *git.Head
+	if h, ok := git.Current(gui.cwd); ok {
+		head = &h
+	}
+	gui.statusMu.Lock()
+	gui.gitHead = head
+	gui.statusMu.Unlock()
+}
+
+// currentGitHead is the last head refreshGit read, or nil.
+func (gui *GUI) currentGitHead() *git.Head {
+	gui.statusMu.Lock()
+	defer gui.statusMu.Unlock()
+	return gui.gitHead
+}
+
+// gitLabel is the header's "main@a1b2c3d (dirty)", or "" outside a git
+// repository.
+func gitLabel(head *git.Head) string {
+	if head == nil {
+		return ""
+	}
+	label := dim(head.Branch + "@" + head.Commit)
+	if head.Dirty {
+		label += " " + yellow("(dirty)")
+	}
+	return label
+}
+
+// dirtyNote warns in a deploy's confirmation that the working tree has
+// uncommitted changes, so the image it builds does not match the commit.
+func (gui *GUI) dirtyNote(dest *kamal.DeployDestination, name string) string {
+	if dest == nil || !deployLockCommands[name] {
+		return ""
+	}
+	head := gui.currentGitHead()
+	if head == nil || !head.Dirty {
+		return ""
+	}
+	return "The working tree has uncommitted changes; the image will not match " + head.Branch + "@" + head.Commit + "."
+}
@@ -0,0 +1,61 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/git"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestGitLabel(t *testing.T) {
+	tests := []struct {
+		head *git.Head
+		want string
+	}{
+		{nil, ""},
+		{&git.Head{Branch: "main", Commit: "a1b2c3d"}, "main@a1b2c3d"},
+		{&git.Head{Branch: "main", Commit: "a1b2c3d", Dirty: true}, "main@a1b2c3d (dirty)"},
+	}
+	for _, tt := range tests {
+		if got := stripANSI(gitLabel(tt.head)); got != tt.want {
+			t.Errorf("gitLabel(%+v) = %q, want %q", tt.head, got, tt.want)
+		}
+	}
+}
+
+func TestDeployConfirmMentionsDirtyTree(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	gui.refreshGit()
+	if gui.currentGitHead() != nil {
+		t.Fatalf("head = %+v outside a git repository", gui.currentGitHead())
+	}
+
+	gui.gitHead = &git.Head{Branch: "main", Commit: "a1b2c3d", Dirty: true}
+	gui.screen, gui.submenuIdx = ScreenDeploy, 0
+	gui.execDeploy()
+	if gui.confirm == nil {
+		t.Fatal("Deploy ran from a dirty tree without asking")
+	}
+	if want := "Deploy to demo (staging)?\nThe working tree has uncommitted changes; the image will not match main@a1b2c3d."; gui.confirm.Message != want {
+		t.Errorf("confirm message = %q, want %q", gui.confirm.Message, want)
+	}
+	gui.answerConfirm(confirmNo, "test")
+
+	// A clean tree deploys staging without asking.
+	gui.gitHead.Dirty = false
+	gui.screen, gui.submenuIdx = ScreenDeploy, 0
+	gui.execDeploy()
+	if gui.confirm != nil {
+		t.Errorf("Deploy from a clean tree asked: %q", gui.confirm.Message)
+	}
+	waitIdle(t, gui)
+	deployed := false
+	for _, c := range runner.Calls() {
+		deployed = deployed || strings.Join(c, " ") == "deploy --destination staging"
+	}
+	if !deployed {
+		t.Errorf("ran %q, want a deploy", runner.Calls())
+	}
+}
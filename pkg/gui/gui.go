@@ -1,6 +1,7 @@
 package gui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -9,6 +10,10 @@ import (
 	"time"
 
 	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+	"github.com/shuvro/lazykamal/pkg/events"
+	"github.com/shuvro/lazykamal/pkg/git"
+	"github.com/shuvro/lazykamal/pkg/hooks"
 	"github.com/shuvro/lazykamal/pkg/kamal"
 )
 
@@ -19,6 +24,7 @@ const (
 	viewHeader  = "header"
 	statusPoll  = 4 * time.Second
 	logBufLive  = 3000
+	liveRedraw  = 80 * time.Millisecond // at most one redraw per interval while output streams
 	logBufCmd   = 500
 	statusLines = 12
 )
@@ -43,6 +49,9 @@ const (
 	ScreenPrune
 	ScreenSecrets
 	ScreenRegistry
+	ScreenPicker
+	ScreenPager
+	ScreenInput
 )
 
 func (s Screen) String() string {
@@ -79,6 +88,12 @@ func (s Screen) String() string {
 		return "secrets"
 	case ScreenRegistry:
 		return "registry"
+	case ScreenPicker:
+		return "picker"
+	case ScreenPager:
+		return "pager"
+	case ScreenInput:
+		return "input"
 	default:
 		return "unknown"
 	}
@@ -86,35 +101,101 @@ func (s Screen) String() string {
 
 // GUI holds TUI state.
 type GUI struct {
-	g              *gocui.Gui
-	cwd            string
-	version        string
-	destinations   []kamal.DeployDestination
-	selectedApp    int
-	screen         Screen
-	prevScreen     Screen
-	submenuIdx     int
-	logLines       []string
-	logMu          sync.Mutex
-	statusText     string
-	statusMu       sync.Mutex
-	running        bool
-	runningCmd     string
-	cmdStartTime   time.Time
-	maxX           int
-	maxY           int
-	statusStopCh   chan struct{}
-	statusTicker   *time.Ticker
-	liveLogsStop   chan struct{}
-	liveLogsActive bool
-	liveLogsMu     sync.Mutex
-	cmdMu          sync.Mutex
-	cmdStopCh      chan struct{}
-	editor         *editorState
-	spinner        *Spinner
-	confirm        *confirmState
-	logScroll      int // scroll offset for log view
-	statusScroll   int // scroll offset for status view
+	g               *gocui.Gui
+	cwd             string
+	version         string
+	destinations    []kamal.DeployDestination // after .lazykamal.yml filtering
+	project         *kamal.ProjectConfig
+	showAllDests    bool // list destinations hidden by .lazykamal.yml
+	hiddenDests     int
+	discovered      []kamal.DeployDestination // every destination found on disk
+	only            []string                  // --only globs
+	appFilter       string                    // interactive Apps filter (/)
+	selectedApp     int
+	collapsedApps   map[string]bool // services whose Apps group is collapsed (←)
+	screen          Screen
+	prevScreen      Screen
+	submenuIdx      int
+	logLines        []logEntry
+	tags            *logTags   // destinations Output lines are tagged with
+	cmdTag          string     // tag of the running command's lines (guarded by logMu)
+	logDestOnly     bool       // Output shows only the selected destination's lines, D (guarded by logMu)
+	long            *longLines // untruncated text of capped Output lines (guarded by logMu)
+	pager           *pager
+	pagerPrev       Screen
+	logMu           sync.Mutex
+	zone            displayZone // zone for Output timestamps (Z cycles)
+	ansi            ansiMode    // escape codes in command output (LAZYKAMAL_ANSI)
+	resize          resizeDebounce
+	statuses        map[string]polledStatus // last poll per destination config (guarded by statusMu)
+	statusRefresh   chan struct{}           // asks the poller to refresh now
+	statusPaused    string                  // why polling waits, e.g. "paused while Deploy runs"; "" while polling
+	gitHead         *git.Head               // the project's branch and commit; nil outside git (guarded by statusMu)
+	kamalVersion    string                  // warning when kamal on PATH differs from Gemfile.lock
+	skew            skewProbe
+	proxy           probe[[]kamal.ProxyHost]       // last proxy details per destination config, for the status panel
+	accessoryHealth probe[[]kamal.AccessoryHealth] // last accessory details per destination config, for the status panel
+	lock            probe[kamal.LockInfo]          // last lock status per destination config, for the status panel and deploy confirms
+	statusExtras    probe[string]                  // last status_extra plugin output per destination config, for the status panel
+	statusExtraBusy map[string]bool                // destination configs whose status_extra plugins are running (guarded by statusMu)
+	statusMu        sync.Mutex
+	running         bool
+	runningCmd      string
+	cmdSamples      []time.Duration // earlier durations of the running command, for its ETA
+	session         sessionLog      // commands run since startup, for the summary on quit
+	bootChecking    bool            // App Boot is checking accessories (guarded by cmdMu)
+	streaming       bool            // the running command logs output as it arrives; Esc cancels it (guarded by cmdMu)
+	handoff         handoff
+	cmdStartTime    time.Time
+	cmdClock        *cmdClock // times the running command; see cmdclock.go
+	maxX            int
+	maxY            int
+	statusStopCh    chan struct{}
+	statusTicker    *time.Ticker
+	liveLogsStop    chan struct{}
+	liveLogsActive  bool
+	liveStats       *streamStats // what the live stream received; guarded by liveLogsMu
+	liveLogsTag     string       // tag of the streaming destination's lines; guarded by liveLogsMu
+	liveLogsMu      sync.Mutex
+	observe         *kamal.Rollout // deploy being observed; guarded by liveLogsMu
+	logPause        *logPause
+	cmdMu           sync.Mutex
+	cmdStopCh       chan struct{}
+	editor          *editorState
+	spinner         *Spinner
+	confirm         *confirmState
+	pendingConfirm  string // title of the open confirm dialog, for event status (guarded by cmdMu)
+	debug           bool   // LAZYKAMAL_DEBUG: extra diagnostics in the log
+	picker          *listPicker
+	input           *inputState
+	history         *deployHistory          // durations of past deploys, for the ETA
+	journal         *journal                // mutating actions, for post-incident review
+	commands        *commandHistory         // every command run, for the History screen
+	confirmNote     string                  // how the action starting now was confirmed, for the journal
+	last            *lastCommand            // command . runs again
+	rerunPending    string                  // name of the command whose re-run is being confirmed
+	cmdArgv         []string                // kamal command lines of the running command (guarded by cmdMu)
+	cmdRetry        *deployRetry            // offer to run the failed command again (guarded by cmdMu)
+	lastExec        string                  // last App Exec command, offered again next time
+	hostSelections  map[string][]string     // --hosts per destination config, for this session
+	roleSelections  map[string][]string     // --roles per destination config, for this session
+	rollbackTo      map[string]string       // version suggested for Rollback after a failed post_deploy, per destination config
+	versionTarget   map[string]string       // --version for the next app command, per destination config
+	secret          *secretEnv              // variable for the next command only (guarded by cmdMu)
+	accessory       string                  // accessory the Accessory menu acts on; "" for all
+	envCache        map[string]containerEnv // running container env per destination config, for Env drift
+	stale           map[string]staleCheck   // last stale_containers result per destination config
+	events          *events.Server          // nil unless event_socket is configured
+	logScroll       int                     // first Output line shown, when logPinned
+	logPinned       bool                    // scrolled back: the Output stays put instead of following new lines
+	logFocus        bool                    // navigation keys scroll the log, not the menu (Tab)
+	logDropped      int                     // lines dropped from the front of logLines (buffer trim, clear)
+	hostFailures    []int                   // log positions (counting logDropped) of failed hosts' sections
+	hostFailureNext int                     // next hostFailures entry for F
+	search          logSearch               // Output search (/, n/N); guarded by logMu
+	statusScroll    int                     // scroll offset for status view
+	runner          kamal.Runner            // runs kamal commands; nil runs the kamal on PATH
+	demo            bool                    // --demo: canned output, nothing saved
 }
 
 // New creates a new GUI. Call FindDeployConfigs after to set destinations.
@@ -128,50 +209,92 @@ func New(version ...string) (*GUI, error) {
 	if err != nil {
 		cwd = "."
 	}
+	return newGUI(cwd, ver, nil)
+}
+
+// demoLineDelay paces the demo's canned output like a real server's.
+const demoLineDelay = 120 * time.Millisecond
+
+// NewDemo creates a GUI on the demo project in dir (see
+// kamal.WriteDemoProject) whose kamal commands are answered by a
+// kamal.FakeRunner, so every menu works offline. Nothing is saved to the
+// command, deploy or journal history.
+func NewDemo(version, dir string) (*GUI, error) {
+	return newGUI(dir, version, &kamal.FakeRunner{Delay: demoLineDelay})
+}
+
+// newGUI creates a GUI on cwd whose kamal commands go to runner; a
+// FakeRunner marks a demo.
+func newGUI(cwd, ver string, runner kamal.Runner) (*GUI, error) {
+	_, demo := runner.(*kamal.FakeRunner)
+	historyPath, journalPath, commandsPath := defaultHistoryPath(), defaultJournalPath(), defaultCommandHistoryPath()
+	if demo {
+		historyPath, journalPath, commandsPath = "", "", ""
+	}
 	g, err := gocui.NewGui(gocui.OutputNormal)
 	if err != nil {
-		return nil, err
+		return nil, &StartupError{Stage: StageTerminal, Err: err}
 	}
 	gui := &GUI{
-		g:            g,
-		cwd:          cwd,
-		version:      ver,
-		selectedApp:  0,
-		screen:       ScreenApps,
-		submenuIdx:   0,
-		logLines:     make([]string, 0, logBufLive),
-		statusStopCh: make(chan struct{}),
-		liveLogsStop: make(chan struct{}),
-		maxX:         80,
-		maxY:         24,
-	}
-	gui.destinations, _ = kamal.FindDeployConfigs(gui.cwd)
-	if len(gui.destinations) == 0 {
-		gui.destinations = []kamal.DeployDestination{}
+		g:              g,
+		cwd:            cwd,
+		version:        ver,
+		debug:          debugEnabled(),
+		selectedApp:    0,
+		screen:         ScreenApps,
+		submenuIdx:     0,
+		logLines:       make([]logEntry, 0, logBufLive),
+		tags:           newLogTags(),
+		long:           newLongLines("x"),
+		ansi:           ansiModeFromEnv(),
+		statusStopCh:   make(chan struct{}),
+		statusRefresh:  make(chan struct{}, 1),
+		statuses:       map[string]polledStatus{},
+		liveLogsStop:   make(chan struct{}),
+		logPause:       newLogPause(pauseBufLimit),
+		hostSelections: map[string][]string{},
+		roleSelections: map[string][]string{},
+		rollbackTo:     map[string]string{},
+		versionTarget:  map[string]string{},
+		envCache:       map[string]containerEnv{},
+		stale:          map[string]staleCheck{},
+		history:        loadDeployHistory(historyPath),
+		journal:        newJournal(journalPath),
+		commands:       loadCommandHistory(commandsPath, cwd, historyMaxFromEnv()),
+		runner:         runner,
+		demo:           demo,
+		session:        sessionLog{started: time.Now()},
+		maxX:           80,
+		maxY:           24,
+	}
+	gui.loadDestinations()
+	for _, note := range termNotes {
+		gui.logInfo(note)
+	}
+
+	if err := gui.attach(g); err != nil {
+		g.Close()
+		return nil, &StartupError{Stage: StageTerminal, Err: err}
 	}
-
-	g.SetManagerFunc(gui.layout)
-	if err := gui.keybindings(g); err != nil {
-		return nil, err
-	}
-	g.SelFgColor = gocui.ColorCyan
 	gui.startStatusPolling()
+	go gui.checkKamalVersion(gui.cwd, false)
+	go gui.checkStaleLocks(gui.cwd, append([]kamal.DeployDestination(nil), gui.destinations...))
 	return gui, nil
 }
 
 func (gui *GUI) layout(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
-	if maxX < 20 {
-		maxX = 20
+	if gui.resize.skipFrame(g, maxX, maxY) {
+		return nil
 	}
-	if maxY < 10 {
-		maxY = 10
+	panels := projectLayout(maxX, maxY)
+	if !panels.valid() {
+		return nil
 	}
-	gui.maxX = maxX
-	gui.maxY = maxY
+	gui.maxX, gui.maxY = clampLayoutSize(maxX, maxY)
 
 	// Header
-	if v, err := g.SetView(viewHeader, 0, 0, maxX-1, 2); err != nil {
+	if v, err := setView(g, viewHeader, panels.header); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -189,39 +312,70 @@ func (gui *GUI) layout(g *gocui.Gui) error {
 	gui.cmdMu.Lock()
 	isRunning := gui.running
 	cmdName := gui.runningCmd
-	cmdStart := gui.cmdStartTime
+	clock := gui.cmdClock
+	samples := gui.cmdSamples
 	sp := gui.spinner
+	cancelHint := "Ctrl+X cancel"
+	if gui.streaming {
+		cancelHint = "Ctrl+X/Esc cancel"
+	}
 	gui.cmdMu.Unlock()
 
 	// Build status indicator
 	var statusIndicator string
 	if isRunning {
-		elapsed := time.Since(cmdStart)
+		elapsed := clock.elapsed()
+		took := "(" + formatDuration(elapsed) + ")"
+		if e, ok := estimateETA(samples, elapsed); ok {
+			took = "— " + formatDuration(elapsed) + " elapsed, " + etaText(e)
+		}
 		if sp != nil {
-			statusIndicator = fmt.Sprintf(" %s %s (%s) %s", sp.Frame(), cmdName, formatDuration(elapsed), dim("Ctrl+X cancel"))
+			statusIndicator = fmt.Sprintf(" %s %s %s %s", sp.Frame(), cmdName, took, dim(cancelHint))
 		} else {
-			statusIndicator = fmt.Sprintf(" %s %s (%s) %s", yellow(iconRunning), cmdName, formatDuration(elapsed), dim("Ctrl+X cancel"))
+			statusIndicator = fmt.Sprintf(" %s %s %s %s", yellow(iconRunning), cmdName, took, dim(cancelHint))
 		}
+	} else if summary := gui.observeStatus(); live && summary != "" {
+		statusIndicator = " " + cyan(iconPlay) + " Observing deploy: " + summary + " " + dim("Esc stop")
+	} else if live && gui.logPause.IsPaused() {
+		statusIndicator = " " + yellow(iconPause) + " Paused (Space to resume)"
 	} else if live {
-		statusIndicator = " " + green(iconPlay) + " Live logs (Esc to stop)"
+		statusIndicator = " " + green(iconPlay) + " Live logs (Space pause, Esc stop)"
+	} else if gui.rerunPending != "" {
+		statusIndicator = " " + yellow(iconRefresh) + " Re-run " + gui.rerunPending + "?"
 	} else {
 		statusIndicator = " " + green(iconCheck) + " Ready"
 	}
 
 	// Mode indicator and breadcrumb
 	modeLabel := green("[PROJECT MODE]")
+	if gui.demo {
+		modeLabel = yellow("[DEMO]")
+	}
 	breadcrumb := gui.getBreadcrumb()
+	if label := gitLabel(gui.currentGitHead()); label != "" {
+		breadcrumb += " " + label
+	}
+	gui.statusMu.Lock()
+	if gui.kamalVersion != "" {
+		breadcrumb += " " + yellow(iconWarning+" kamal version mismatch")
+	}
+	gui.statusMu.Unlock()
+	if warn := gui.skewWarningFor(); warn != "" {
+		breadcrumb += " " + yellow(iconWarning+" "+warn)
+	}
+	if version := gui.targetVersion(); version != "" {
+		breadcrumb += " " + yellow("targeting version "+version+" — press V to clear")
+	}
+	if key := gui.pendingSecretKey(); key != "" {
+		breadcrumb += " " + yellow("secret "+key+" for the next command")
+	}
 
-	fmt.Fprintf(header, " %s %s %s | %s %s |%s | %s\n",
+	fmt.Fprint(header, glyphs(fmt.Sprintf(" %s %s %s | %s %s |%s | %s\n",
 		cyan(iconRocket), bold("Lazykamal"), dim(gui.version),
-		modeLabel, breadcrumb, statusIndicator, dim("?: help"))
+		modeLabel, breadcrumb, statusIndicator, dim("?: help"))))
 
 	// Left panel: apps / menu (about 40% width)
-	leftW := maxX * 4 / 10
-	if leftW < 25 {
-		leftW = 25
-	}
-	if v, err := g.SetView(viewMain, 0, 3, leftW-1, maxY-1); err != nil {
+	if v, err := setView(g, viewMain, panels.main); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -232,12 +386,7 @@ func (gui *GUI) layout(g *gocui.Gui) error {
 	}
 
 	// Right: status (top) + log (bottom)
-	statusH := statusLines + 2
-	if statusH > maxY-6 {
-		statusH = maxY - 6
-	}
-	statusY := 3 + statusH
-	if v, err := g.SetView(viewStatus, leftW, 3, maxX-1, statusY-1); err != nil {
+	if v, err := setView(g, viewStatus, panels.status); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -245,13 +394,12 @@ func (gui *GUI) layout(g *gocui.Gui) error {
 		v.Title = " Live status "
 		v.Wrap = true
 	}
-	if v, err := g.SetView(viewLog, leftW, statusY, maxX-1, maxY-1); err != nil {
+	if v, err := setView(g, viewLog, panels.log); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
 		v.Frame = true
 		v.Title = " Output / Live logs "
-		v.Autoscroll = true
 		v.Wrap = true
 	}
 
@@ -267,6 +415,24 @@ func (gui *GUI) layout(g *gocui.Gui) error {
 		gui.renderLog(g)
 		return gui.renderConfirmDialog(g)
 	}
+	if gui.screen == ScreenPicker {
+		gui.renderLeftPanel(g)
+		gui.renderStatus(g)
+		gui.renderLog(g)
+		return gui.renderPicker(g)
+	}
+	if gui.screen == ScreenInput {
+		gui.renderLeftPanel(g)
+		gui.renderStatus(g)
+		gui.renderLog(g)
+		return gui.renderInputDialog(g)
+	}
+	if gui.screen == ScreenPager {
+		gui.renderLeftPanel(g)
+		gui.renderStatus(g)
+		gui.renderLog(g)
+		return renderPager(g, gui.pager)
+	}
 	gui.renderLeftPanel(g)
 	gui.renderStatus(g)
 	gui.renderLog(g)
@@ -279,20 +445,11 @@ func (gui *GUI) renderHelpOverlay(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
 
 	// Center the help overlay
-	width := 60
-	height := 28
-	if width > maxX-4 {
-		width = maxX - 4
-	}
-	if height > maxY-4 {
-		height = maxY - 4
+	r := centeredRect(maxX, maxY, 60, 33, 4, 4)
+	if !r.valid() {
+		return nil
 	}
-	x0 := (maxX - width) / 2
-	y0 := (maxY - height) / 2
-	x1 := x0 + width
-	y1 := y0 + height
-
-	if v, err := g.SetView(viewHelp, x0, y0, x1, y1); err != nil {
+	if v, err := setView(g, viewHelp, r); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -319,6 +476,22 @@ func (gui *GUI) renderHelpOverlay(g *gocui.Gui) error {
    Esc / b     Go back          m    Main menu
    r           Refresh          c    Clear log
    j/k         Scroll log       J/K  Scroll status
+   Tab         Focus menu / log for ↑/↓ and paging
+   PgUp/PgDn   Page (10 items)  Home/End, g/G  First/last
+   Space       Pause/resume live logs
+   H           Target roles/hosts (--roles, --hosts)
+   V           Target a version (--version) / clear
+   a           Show/hide hidden apps (.lazykamal.yml)
+   /           Filter apps by glob or text (live)
+   /  n/N      Search the Output, next/previous match
+   ←/→         Collapse/expand a service's apps
+   R           Refresh env drift (Config menu)
+   Z           Timestamps: local / UTC / server
+   D           Output: selected destination only / all
+   F           Jump to a failed host's output
+   x           Expand a truncated line (pager)
+   .           Re-run the last command
+   Ctrl+S      Save the Output log to a file
    Ctrl+X      Cancel command   q    Quit
    ?           This help
 
@@ -326,6 +499,7 @@ func (gui *GUI) renderHelpOverlay(g *gocui.Gui) error {
  ──────────────────────────────────────────────
    ↑/↓/←/→     Move cursor
    Ctrl+S      Save file
+   Ctrl+E      Open in $EDITOR (reloads after)
    Ctrl+Q/Esc  Quit editor
 
  %s
@@ -340,7 +514,7 @@ func (gui *GUI) renderHelpOverlay(g *gocui.Gui) error {
 		bold("KEYBOARD SHORTCUTS"),
 		bold("EDITOR (when editing files)"),
 		bold("SERVER MODE (different mode)"))
-	fmt.Fprint(v, help)
+	fmt.Fprint(v, glyphs(help))
 	g.SetCurrentView(viewHelp)
 	return nil
 }
@@ -352,14 +526,53 @@ func (gui *GUI) renderStatus(g *gocui.Gui) {
 	}
 	v.Clear()
 	gui.statusMu.Lock()
-	text := gui.statusText
+	paused := gui.statusPaused
 	gui.statusMu.Unlock()
-	if text == "" {
-		fmt.Fprintln(v, " Polling app version & containers...")
+	dest := gui.selectedDestination()
+	if len(gui.destinations) == 0 {
+		writeEmptyState(v, emptyNoDestinations)
+		return
+	}
+	if dest == nil {
+		writeEmptyState(v, emptyNoSelection)
+		return
+	}
+	if dest.ConfigErr != nil {
+		writeEmptyState(v, emptyConfigError)
+		fmt.Fprintln(v, "")
+		fmt.Fprintln(v, " "+red(configErrorText(gui.cwd, dest)))
+		return
+	}
+	st, polled := gui.statusFor(dest)
+	statusErr := st.err
+	switch {
+	case paused != "" && (!polled || statusErr != ""):
+		// A failed or missing poll says nothing about the servers now.
+		fmt.Fprintln(v, " "+dim("Status "+paused))
+		return
+	case !polled:
+		writeEmptyState(v, emptyNeverPolled)
+		return
+	case statusErr != "":
+		writeEmptyState(v, emptyKamalError)
+		fmt.Fprintln(v, "")
+		fmt.Fprintln(v, " "+red(statusErr))
 		return
 	}
 
-	lines := strings.Split(text, "\n")
+	text := st.text
+	if extra := gui.statusExtraFor(dest); extra != "" {
+		text += "\n" + extra
+	}
+	lines := append(strings.Split(text, "\n"), gui.staleStatusLines()...)
+	if paused != "" {
+		lines = append([]string{" " + dim("Status "+paused)}, lines...)
+	} else if note := staleStatusNote(st, time.Now()); note != "" {
+		lines = append([]string{note}, lines...)
+	}
+	if line := gui.lockStatusFor(dest); line != "" {
+		lines = append([]string{line}, lines...)
+	}
 	_, viewHeight := v.Size()
 	if viewHeight < 1 {
 		viewHeight = 1
@@ -384,7 +597,7 @@ func (gui *GUI) renderStatus(g *gocui.Gui) {
 	}
 
 	for _, l := range lines[start:end] {
-		fmt.Fprintln(v, l)
+		fmt.Fprintln(v, glyphs(l))
 	}
 
 	// Show scroll indicator
@@ -430,272 +643,232 @@ func (gui *GUI) renderLeftPanel(g *gocui.Gui) {
 	case ScreenRegistry:
 		gui.renderRegistryMenu(v)
 	}
+	gui.writeMenuHint(v)
 }
 
 func (gui *GUI) renderApps(v *gocui.View) {
 	v.Title = " Apps (destinations) "
+	if len(gui.destinations) == 0 && len(gui.discovered) > 0 && (gui.appFilter != "" || len(gui.only) > 0) {
+		fmt.Fprintln(v, "")
+		fmt.Fprintln(v, " No apps match the filter.")
+		if gui.appFilter != "" {
+			fmt.Fprintln(v, dim(" filter: "+gui.appFilter+" (/ to change)"))
+		}
+		return
+	}
 	if len(gui.destinations) == 0 {
 		fmt.Fprintln(v, "")
-		fmt.Fprintln(v, " No config/deploy*.yml found.")
-		fmt.Fprintln(v, " Run from a Kamal app root.")
+		writeEmptyState(v, emptyNoDestinations)
 		return
 	}
-	for i, d := range gui.destinations {
+	width, _ := v.Size()
+	rows := gui.currentAppRows()
+	grouped := appsGrouped(rows)
+	current := gui.selectedDestination()
+	for _, r := range rows {
+		if r.dest < 0 {
+			selected := r.collapsed && current != nil && current.Service == r.service
+			fmt.Fprintln(v, truncate(appHeader(r, selected), width))
+			continue
+		}
+		d := gui.destinations[r.dest]
 		prefix := "  "
-		if i == gui.selectedApp {
-			prefix = "› "
+		if r.dest == gui.selectedApp {
+			prefix = iconArrow + " "
 		}
-		fmt.Fprintf(v, "%s%s\n", prefix, d.Label())
+		if grouped {
+			prefix += "  "
+		}
+		label := d.Label()
+		if d.Protected {
+			label += " " + red("[protected]")
+		}
+		if d.ConfigErr != nil {
+			label += " " + red("[config error]")
+		}
+		if d.Hidden {
+			label = dim(label + " (hidden)")
+		}
+		fmt.Fprintf(v, "%s%s\n", prefix, truncate(label, width-visibleWidth(prefix)))
 	}
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " ↑/↓ select  Enter: commands")
+	if current != nil && current.ConfigErr != nil {
+		fmt.Fprintln(v, glyphs(" ↑/↓ select  Enter: edit config  /: filter"))
+	} else {
+		fmt.Fprintln(v, glyphs(" ↑/↓ select  Enter: commands  /: filter"))
+	}
+	if grouped {
+		fmt.Fprintln(v, dim(glyphs(" ←/→ collapse/expand group")))
+	}
+	if gui.appFilter != "" {
+		fmt.Fprintln(v, dim(" filter: "+gui.appFilter+" (/ to change)"))
+	}
+	if gui.hiddenDests > 0 {
+		if gui.showAllDests {
+			fmt.Fprintln(v, dim(" a: hide hidden apps"))
+		} else {
+			fmt.Fprintln(v, dim(fmt.Sprintf(" a: show %d hidden app(s)", gui.hiddenDests)))
+		}
+	}
 }
 
 func (gui *GUI) renderMainMenu(v *gocui.View) {
 	v.Title = " Commands "
-	items := []string{
-		"Deploy / Redeploy / Rollback",
-		"App (boot, start, stop, logs…)",
-		"Server (bootstrap, exec)",
-		"Accessory (boot, logs, reboot)",
-		"Proxy (boot, logs, reboot)",
-		"Other (prune, config, lock…)",
-		"Config (edit deploy.yml, secrets, restart)",
-	}
-	for i, s := range items {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, s)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: open  Esc: back")
+	width, _ := v.Size()
+	writeMenu(v, width, menuLabels(ScreenMainMenu, menuLabelWidth(width)), gui.submenuIdx, " Enter: open  Esc: back")
 }
 
 func (gui *GUI) renderDeployMenu(v *gocui.View) {
 	v.Title = " Deploy "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Deploy", "Deploy (skip push)", "Redeploy", "Rollback", "Setup (first-time)", "Deploy (no cache)", "Redeploy (no cache)", "Setup (no cache)"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenDeploy, menuLabelWidth(width))
+	if version := gui.rollbackVersion(); version != "" {
+		actions[3] = "Rollback " + yellow("(to "+version+")")
 	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderAppMenu(v *gocui.View) {
 	v.Title = " App "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Boot", "Start", "Stop", "Restart", "Logs", "Containers", "Details", "Images", "Version", "Stale containers", "Exec: whoami", "Maintenance", "Live", "Remove", "Live: App logs (stream)", "Stale containers (stop)", "Exec: whoami (detach)"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenApp, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderServerMenu(v *gocui.View) {
 	v.Title = " Server "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Bootstrap", "Exec: date", "Exec: uptime"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenServer, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderAccessoryMenu(v *gocui.View) {
 	v.Title = " Accessory "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Boot all", "Start all", "Stop all", "Restart all", "Reboot all", "Remove all", "Details all", "Logs all", "Exec: sh (all)", "Upgrade"}
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	if !gui.hasAccessories() {
+		writeEmptyState(v, emptyNoAccessories)
+		return
+	}
+	actions := menuLabels(ScreenAccessory, menuLabelWidth(width))
 	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
+		actions[i] = forAccessory(a, gui.accessory)
 	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderProxyMenu(v *gocui.View) {
 	v.Title = " Proxy "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Boot", "Start", "Stop", "Restart", "Reboot", "Reboot (rolling)", "Logs", "Details", "Remove", "Boot config get (deprecated)", "Boot config set (deprecated)", "Boot config reset (deprecated)", "Live: Proxy logs (stream)"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenProxy, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderOtherMenu(v *gocui.View) {
 	v.Title = " Other "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Prune >", "Build >", "Config", "Details", "Audit", "Lock status", "Lock acquire", "Lock release", "Lock release --force", "Registry >", "Secrets >", "Env push", "Env pull", "Env delete", "Docs", "Help", "Init", "Upgrade", "Version"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back  ?: help")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenOther, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back  ?: help")
 }
 
 func (gui *GUI) renderBuildMenu(v *gocui.View) {
 	v.Title = " Build "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Push", "Pull", "Deliver", "Dev", "Create", "Remove", "Details"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenBuild, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderPruneMenu(v *gocui.View) {
 	v.Title = " Prune "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"All", "Images", "Containers"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenPrune, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderSecretsMenu(v *gocui.View) {
 	v.Title = " Secrets "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Fetch", "Extract", "Print"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenSecrets, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderRegistryMenu(v *gocui.View) {
 	v.Title = " Registry "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{"Setup", "Login", "Logout", "Remove"}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " Enter: run  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenRegistry, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " Enter: run  b/Esc: back")
 }
 
 func (gui *GUI) renderConfigMenu(v *gocui.View) {
 	v.Title = " Config "
 	dest := gui.selectedDestination()
-	label := "—"
+	label := glyphs("—")
 	if dest != nil {
 		label = dest.Label()
 	}
-	fmt.Fprintf(v, " App: %s\n\n", label)
-	actions := []string{
-		"Edit deploy config (current dest)",
-		"Edit secrets (current dest)",
-		"Redeploy (after edit)",
-		"App restart (after edit)",
-	}
-	for i, a := range actions {
-		prefix := "  "
-		if i == gui.submenuIdx {
-			prefix = "› "
-		}
-		fmt.Fprintf(v, "%s%s\n", prefix, a)
-	}
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " In-TUI edit (nano/vi style)  b/Esc: back")
+	width, _ := v.Size()
+	fmt.Fprintf(v, " App: %s\n\n", truncate(label, width-6))
+	actions := menuLabels(ScreenConfig, menuLabelWidth(width))
+	writeMenu(v, width, actions, gui.submenuIdx, " In-TUI edit (nano/vi style)  R: refresh env drift  b/Esc: back")
 }
 
 func (gui *GUI) renderLog(g *gocui.Gui) {
@@ -704,47 +877,64 @@ func (gui *GUI) renderLog(g *gocui.Gui) {
 		return
 	}
 	v.Clear()
-	gui.logMu.Lock()
-	lines := append([]string(nil), gui.logLines...)
-	gui.logMu.Unlock()
-	if len(lines) == 0 {
-		fmt.Fprintln(v, " Command output will appear here.")
-		return
-	}
 	_, viewHeight := v.Size()
 	if viewHeight < 1 {
 		viewHeight = 1
 	}
-
-	// Calculate scroll bounds
-	maxScroll := len(lines) - viewHeight
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
-	if gui.logScroll > maxScroll {
-		gui.logScroll = maxScroll
-	}
-	if gui.logScroll < 0 {
-		gui.logScroll = 0
+	skew := gui.liveSkew()
+	gui.logMu.Lock()
+	visible := gui.visibleLog()
+	entries := make([]logEntry, len(visible))
+	for i, e := range visible {
+		entries[i] = gui.tags.label(e)
+	}
+	lines := renderSearched(entries, gui.zone, skew, gui.search.term)
+	if !gui.logPinned {
+		gui.logScroll = len(lines)
+	}
+	start, end := clampLogWindow(&gui.logScroll, len(lines), viewHeight)
+	pinned := gui.logPinned
+	filtered := gui.logDestOnly
+	searching := ""
+	if gui.search.active() {
+		searching = searchTitle(&gui.search, gui.logMatches(), gui.logDropped)
 	}
-
-	start := gui.logScroll
-	end := start + viewHeight
-	if end > len(lines) {
-		end = len(lines)
+	gui.logMu.Unlock()
+	// Following, wrapped lines may overflow the panel; keep the newest in view.
+	v.Autoscroll = !pinned
+	if len(lines) == 0 {
+		writeEmptyState(v, emptyProjectLog)
+		return
 	}
 
 	for _, l := range lines[start:end] {
 		fmt.Fprintln(v, l)
 	}
 
+	gui.liveLogsMu.Lock()
+	live, stats := gui.liveLogsActive, gui.liveStats
+	gui.liveLogsMu.Unlock()
+
 	// Show scroll indicator if scrolled
-	if gui.logScroll > 0 || end < len(lines) {
+	title := " Output / Live logs "
+	if gui.logPause.IsPaused() {
+		title = " Output / " + gui.logPause.Label() + " "
+	} else if live && stats != nil {
+		title = " Output / " + stats.title(time.Now()) + " "
+	}
+	title += "[" + zoneLabel(gui.zone, skew) + "] "
+	if dest := gui.selectedDestination(); filtered && dest != nil {
+		title += "[only " + logTagName(dest) + "] "
+	}
+	title += searching
+	if pinned || end < len(lines) {
 		scrollInfo := fmt.Sprintf(" [%d-%d of %d] ", start+1, end, len(lines))
-		v.Title = fmt.Sprintf(" Output / Live logs %s", scrollInfo)
-	} else {
-		v.Title = " Output / Live logs "
+		title += scrollInfo
 	}
+	if pinned {
+		title += followHint(gui.logFocus)
+	}
+	v.Title = focusTitle(title, gui.logFocus)
 }
 
 func (gui *GUI) selectedDestination() *kamal.DeployDestination {
@@ -758,51 +948,205 @@ func (gui *GUI) selectedDestination() *kamal.DeployDestination {
 }
 
 func (gui *GUI) runOpts() kamal.RunOptions {
-	return kamal.RunOpts(gui.cwd, gui.selectedDestination())
+	opts := kamal.RunOpts(gui.cwd, gui.selectedDestination())
+	opts.Runner = gui.runner
+	opts.Hosts = strings.Join(gui.selectedHosts(), ",")
+	opts.Roles = strings.Join(gui.selectedRoles(), ",")
+	opts.Defaults = gui.projectConfig().Commands
+	opts.OnRun = gui.logArgv
+	opts.Env = gui.secretEnvOnce
+	return opts
+}
+
+// logArgv shows the exact kamal command line before it runs, quoted so it
+// can be pasted into a terminal, and which flags .lazykamal.yml added, so no
+// flag is added behind the user's back.
+func (gui *GUI) logArgv(args, fromDefaults []string) {
+	gui.cmdMu.Lock()
+	gui.cmdArgv = append(gui.cmdArgv, kamal.CommandLine(args))
+	gui.cmdMu.Unlock()
+	lines := []string{dim("  $ " + kamal.CommandLine(args))}
+	if len(fromDefaults) > 0 {
+		lines = append(lines, dim("    from "+kamal.ProjectConfigFile+": "+strings.Join(fromDefaults, " ")))
+	}
+	gui.appendLog(lines)
 }
 
 func (gui *GUI) startStatusPolling() {
 	gui.statusTicker = time.NewTicker(statusPoll)
+	stopCh, tick := gui.statusStopCh, gui.statusTicker.C
 	go func() {
 		for {
 			select {
-			case <-gui.statusStopCh:
+			case <-stopCh:
 				return
-			case <-gui.statusTicker.C:
+			case <-tick:
+				gui.refreshStatus()
+			case <-gui.statusRefresh:
 				gui.refreshStatus()
 			}
 		}
 	}()
 }
 
+func (gui *GUI) stopStatusPolling() {
+	if gui.statusStopCh != nil {
+		close(gui.statusStopCh)
+		gui.statusStopCh = nil
+	}
+	if gui.statusTicker != nil {
+		gui.statusTicker.Stop()
+	}
+}
+
+// statusPauseReason is why the status poll should wait: a command is
+// running or logs are streaming. Polling then would load the servers and can
+// trip over kamal's deploy lock, so the panel pauses until they finish.
+func (gui *GUI) statusPauseReason() string {
+	gui.cmdMu.Lock()
+	running, name := gui.running, gui.runningCmd
+	gui.cmdMu.Unlock()
+	if running {
+		return "paused while " + name + " runs"
+	}
+	gui.liveLogsMu.Lock()
+	live := gui.liveLogsActive
+	gui.liveLogsMu.Unlock()
+	if live {
+		return "paused while logs stream"
+	}
+	return ""
+}
+
+// refreshStatus polls the selected destination and stores the result under
+// it; the panel shows only the entry of whichever destination is selected.
 func (gui *GUI) refreshStatus() {
-	dest := gui.selectedDestination()
-	if dest == nil {
+	gui.refreshGit()
+	selected := gui.selectedDestination()
+	if selected == nil {
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+		return
+	}
+	// A destination whose config is broken has nothing to poll; the panel
+	// shows the error instead.
+	if selected.ConfigErr != nil {
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+		return
+	}
+	d := *selected
+	dest := &d
+	if reason := gui.statusPauseReason(); reason != "" {
 		gui.statusMu.Lock()
-		gui.statusText = " No app selected.\n Select an app (destination) for live status."
+		gui.statusPaused = reason
 		gui.statusMu.Unlock()
 		gui.g.Update(func(*gocui.Gui) error { return nil })
 		return
 	}
-	opts := gui.runOpts()
+	gui.pollStatus(dest)
+}
+
+// pollStatus polls dest now, whatever runs, and stores the result under it.
+// A mutating command calls it for its destination before it reports
+// success, so the panel and the message agree.
+func (gui *GUI) pollStatus(dest *kamal.DeployDestination) {
+	// Built from dest, not the selection, which may have moved on since the
+	// command started. Polls are not echoed, nor given a command's secret.
+	opts := kamal.RunOpts(gui.cwd, dest)
+	opts.Runner = gui.runner
+	opts.Hosts = strings.Join(gui.hostSelections[hostsKey(dest)], ",")
+	opts.Roles = strings.Join(gui.roleSelections[hostsKey(dest)], ",")
+	opts.Defaults = gui.projectConfig().Commands
 	var buf string
-	buf = " App: " + dest.Label() + "\n\n"
-	if r, err := kamal.AppVersion(opts); err == nil {
+	var errLine string
+	buf = " App: " + dest.Label() + "\n"
+	buf += " Config: " + dest.ConfigSource(gui.cwd) + "\n\n"
+	var versions map[string]string
+	if r, err := kamal.AppVersion(opts); err == nil && r.ExitCode == 0 {
 		buf += " Version:\n " + stringsTrim(r.Combined(), 2) + "\n\n"
+		versions = kamal.ParseAppVersions(r.Combined())
 	} else {
 		buf += " Version: (error)\n\n"
+		errLine = firstErrorLine(r, err)
 	}
-	if r, err := kamal.AppContainers(opts); err == nil {
-		buf += " Containers:\n " + stringsTrim(r.Combined(), 8) + "\n"
+	if r, err := kamal.AppContainers(opts); err == nil && r.ExitCode == 0 {
+		buf += containersStatus(r.Combined())
+		// Containers are listed, so kamal itself works; a version error alone is not fatal.
+		errLine = ""
 	} else {
 		buf += " Containers: (error)\n"
+		if errLine == "" {
+			errLine = firstErrorLine(r, err)
+		}
+	}
+	if errLine == "" {
+		gui.checkLock(dest, opts)
+		gui.checkProxy(dest, opts)
+		gui.checkAccessories(dest, opts)
 	}
+	if line := gui.proxyStatusFor(dest); line != "" {
+		buf += "\n" + line
+	}
+	if section := gui.accessoryStatusFor(dest); section != "" {
+		buf += "\n" + section
+	}
+	gui.checkStatusExtra(dest, versions)
+	gui.storeStatus(dest, polledStatus{text: buf, err: errLine, at: time.Now()})
 	gui.statusMu.Lock()
-	gui.statusText = buf
+	gui.statusPaused = ""
 	gui.statusMu.Unlock()
+	if errLine == "" {
+		gui.checkClockSkew(dest, opts)
+	}
 	gui.g.Update(func(*gocui.Gui) error { return nil })
 }
 
+// checkKamalVersion compares the kamal on PATH with the version pinned in
+// Gemfile.lock and records a warning for the header. It never blocks usage.
+// The warning is logged when it first appears, or every time if onDemand.
+func (gui *GUI) checkKamalVersion(cwd string, onDemand bool) {
+	warn := kamal.VersionMismatch(cwd)
+	gui.statusMu.Lock()
+	changed := warn != gui.kamalVersion
+	gui.kamalVersion = warn
+	gui.statusMu.Unlock()
+	if warn != "" && (changed || onDemand) {
+		gui.appendLog([]string{
+			statusLine("warning", warn),
+			dim("  Commands may behave differently; consider running kamal via `bundle exec kamal`."),
+		})
+	}
+	gui.g.Update(func(*gocui.Gui) error { return nil })
+}
+
+// resetStatus refreshes the status after the selected app changes. Until
+// the new poll lands, the app's cached status is shown with its age.
+func (gui *GUI) resetStatus() {
+	gui.statusMu.Lock()
+	gui.statusPaused = ""
+	gui.statusMu.Unlock()
+	gui.requestStatus()
+}
+
+// firstErrorLine returns a one-line summary of why a kamal call failed.
+func firstErrorLine(r kamal.Result, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	for _, l := range strings.Split(r.Stderr+"\n"+r.Stdout, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return fmt.Sprintf("exit code %d", r.ExitCode)
+}
+
+// writeEmptyState renders the guidance text for an empty panel.
+func writeEmptyState(v *gocui.View, s emptyState) {
+	for _, l := range emptyStateLines(s) {
+		fmt.Fprintln(v, " "+glyphs(l))
+	}
+}
+
 func stringsTrim(s string, maxLines int) string {
 	lines := strings.Split(strings.TrimSpace(s), "\n")
 	if len(lines) > maxLines {
@@ -814,15 +1158,40 @@ func stringsTrim(s string, maxLines int) string {
 	return strings.Join(lines, "\n")
 }
 
+// appendLog adds lines to the Output panel. While a command runs they are
+// tagged with its destination.
 func (gui *GUI) appendLog(lines []string) {
 	gui.logMu.Lock()
 	defer gui.logMu.Unlock()
+	gui.appendEntries(gui.cmdTag, lines)
+}
+
+// appendLogTagged adds lines tagged with the destination key tag.
+func (gui *GUI) appendLogTagged(tag string, lines []string) {
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	gui.appendEntries(tag, lines)
+}
+
+// appendEntries adds timestamped entries and trims the buffer. Callers hold
+// logMu.
+func (gui *GUI) appendEntries(tag string, lines []string) {
 	for _, line := range lines {
-		// Add timestamp to each line
-		gui.logLines = append(gui.logLines, timestampedLine(sanitizeLogLine(line)))
+		e := gui.long.entry(glyphs(sanitizeLogLine(line)))
+		e.dest = tag
+		gui.logLines = append(gui.logLines, e)
 	}
 	if len(gui.logLines) > logBufLive {
-		gui.logLines = gui.logLines[len(gui.logLines)-logBufLive:]
+		drop := len(gui.logLines) - logBufLive
+		if gui.logPinned {
+			// Keep the same lines in view as the front is trimmed.
+			gui.logScroll -= len(gui.visibleEntries(gui.logLines[:drop]))
+			if gui.logScroll < 0 {
+				gui.logScroll = 0
+			}
+		}
+		gui.logDropped += drop
+		gui.logLines = gui.logLines[drop:]
 	}
 }
 
@@ -841,22 +1210,19 @@ func (gui *GUI) logInfo(msg string) {
 	gui.appendLog([]string{statusLine("info", msg)})
 }
 
-func (gui *GUI) appendLogFromResult(r kamal.Result) {
-	gui.appendLog(r.Lines())
-}
-
 func (gui *GUI) startLiveLogs(kind string) {
 	gui.liveLogsMu.Lock()
 	if gui.liveLogsActive {
 		gui.liveLogsMu.Unlock()
 		return
 	}
+	tag := gui.tags.tag(gui.selectedDestination())
 	gui.liveLogsActive = true
 	gui.liveLogsStop = make(chan struct{})
+	gui.liveLogsTag = tag
 	stopCh := gui.liveLogsStop
 	gui.liveLogsMu.Unlock()
 
-	opts := gui.runOpts()
 	var subcommand []string
 	switch kind {
 	case "app":
@@ -864,31 +1230,93 @@ func (gui *GUI) startLiveLogs(kind string) {
 	case "proxy":
 		subcommand = []string{"proxy", "logs"}
 	case "accessory":
-		subcommand = []string{"accessory", "logs", "all"}
+		if !gui.hasAccessories() {
+			gui.liveLogsMu.Lock()
+			gui.liveLogsActive = false
+			gui.liveLogsMu.Unlock()
+			gui.logInfo("No accessories configured; nothing to stream")
+			return
+		}
+		subcommand = []string{"accessory", "logs", gui.accessoryArg()}
 	default:
 		gui.liveLogsMu.Lock()
 		gui.liveLogsActive = false
 		gui.liveLogsMu.Unlock()
 		return
 	}
+	opts := gui.commandOpts(subcommand)
+	go func() {
+		gui.pipeLive(tag, subcommand, opts, stopCh)
+		gui.liveLogsMu.Lock()
+		if gui.liveLogsStop == stopCh {
+			gui.liveLogsActive = false
+		}
+		gui.liveLogsMu.Unlock()
+		gui.logPause.Reset()
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+		go gui.refreshStatus()
+	}()
+}
 
+// pipeLive streams subcommand output into the log, tagged tag and honoring
+// pause, until the command exits or stopCh is closed.
+func (gui *GUI) pipeLive(tag string, subcommand []string, opts kamal.RunOptions, stopCh <-chan struct{}) {
 	lastUpdate := time.Now()
-	throttle := 80 * time.Millisecond
+	skew := gui.liveSkew()
+	stats := newStreamStats(streamName(subcommand), lastUpdate)
+	opts.Env = nil // a secret is for a command, not a stream
+	gui.liveLogsMu.Lock()
+	gui.liveStats = stats
+	gui.liveLogsMu.Unlock()
 	onLine := func(line string) {
-		gui.appendLog([]string{line})
-		if time.Since(lastUpdate) < throttle {
+		stats.add(line)
+		line = annotateSkew(cleanOutput(line, gui.ansi), skew)
+		if !gui.logPause.Offer(line) {
+			gui.appendLogTagged(tag, []string{line})
+		}
+		if time.Since(lastUpdate) < liveRedraw {
 			return
 		}
 		lastUpdate = time.Now()
 		gui.g.Update(func(*gocui.Gui) error { return nil })
 	}
-	go func() {
-		_ = kamal.RunKamalStream(subcommand, opts, onLine, stopCh)
-		gui.liveLogsMu.Lock()
-		gui.liveLogsActive = false
-		gui.liveLogsMu.Unlock()
-		gui.g.Update(func(*gocui.Gui) error { return nil })
-	}()
+	_ = kamal.RunKamalStream(subcommand, opts, onLine, stopCh)
+	gui.liveLogsMu.Lock()
+	if gui.liveStats == stats {
+		gui.liveStats = nil
+	}
+	gui.liveLogsMu.Unlock()
+	gui.logInfo("Live logs stopped: " + stats.totals(time.Now()))
+}
+
+// streamCommand is the command fn for long-running kamal commands (deploy,
+// setup, build): output is logged as it arrives rather than when the
+// command exits, and Esc cancels like Ctrl+X.
+func (gui *GUI) streamCommand(subcommand []string, opts kamal.RunOptions) func(stopCh <-chan struct{}) (kamal.Result, error) {
+	return func(stopCh <-chan struct{}) (kamal.Result, error) {
+		gui.cmdMu.Lock()
+		gui.streaming = true
+		gui.cmdMu.Unlock()
+		defer func() {
+			gui.cmdMu.Lock()
+			gui.streaming = false
+			gui.cmdMu.Unlock()
+		}()
+
+		gui.logMu.Lock()
+		start := gui.logDropped + len(gui.logLines)
+		gui.logMu.Unlock()
+		lastUpdate := time.Now()
+		res, err := kamal.RunKamalStreamWithStop(subcommand, opts, func(line string) {
+			gui.appendLog([]string{cleanOutput(line, gui.ansi)})
+			if time.Since(lastUpdate) >= liveRedraw {
+				lastUpdate = time.Now()
+				gui.g.Update(func(*gocui.Gui) error { return nil })
+			}
+		}, stopCh)
+		gui.appendHostVerdict(res.Lines(), start)
+		return res, err
+	}
 }
 
 func (gui *GUI) stopLiveLogs() {
@@ -899,6 +1327,45 @@ func (gui *GUI) stopLiveLogs() {
 	}
 	close(gui.liveLogsStop)
 	gui.liveLogsActive = false
+	gui.logPause.Reset()
+}
+
+// togglePauseLogs freezes or unfreezes the log view while a live stream runs.
+// On resume with buffered lines, the user chooses between appending them and
+// skipping straight to live output.
+func (gui *GUI) togglePauseLogs() {
+	gui.liveLogsMu.Lock()
+	live := gui.liveLogsActive
+	gui.liveLogsMu.Unlock()
+	if !live {
+		return
+	}
+	if !gui.logPause.IsPaused() {
+		gui.logPause.Pause()
+		return
+	}
+	held, _ := gui.logPause.Buffered()
+	if held == 0 {
+		gui.logPause.Resume()
+		return
+	}
+	gui.liveLogsMu.Lock()
+	tag := gui.liveLogsTag
+	gui.liveLogsMu.Unlock()
+	gui.prevScreen = gui.screen
+	gui.showConfirm("Resume live logs",
+		fmt.Sprintf("Append %s buffered lines? (No: skip to live)", formatCount(held)),
+		func() {
+			lines, dropped := gui.logPause.Resume()
+			if dropped > 0 {
+				gui.logInfo(fmt.Sprintf("%s older buffered lines were dropped", formatCount(dropped)))
+			}
+			gui.appendLogTagged(tag, lines)
+		},
+		func() {
+			lines, dropped := gui.logPause.Resume()
+			gui.logInfo(fmt.Sprintf("Skipped %s buffered lines", formatCount(len(lines)+dropped)))
+		})
 }
 
 func (gui *GUI) execConfig() {
@@ -944,43 +1411,109 @@ func (gui *GUI) execConfig() {
 			gui.appendLog([]string{"Editing " + path + " (^S save, ^Q/Esc quit)"})
 		}
 	case 2: // Redeploy
-		opts := gui.runOpts()
-		gui.runCommand("Redeploy", func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"redeploy"}, opts, stopCh)
-		})
+		opts := gui.commandOpts([]string{"redeploy"})
+		gui.runCommand("Redeploy", gui.streamCommand([]string{"redeploy"}, opts))
 	case 3: // App restart
 		opts := gui.runOpts()
 		gui.runCommand("App Restart", func(stopCh <-chan struct{}) (kamal.Result, error) {
 			return kamal.RunKamalWithStop([]string{"app", "restart"}, opts, stopCh)
 		})
+	case 4: // Env drift
+		gui.showEnvDrift(false)
+	case 5: // Bulk edit
+		gui.bulkEdit()
+	case 6: // Create project config
+		gui.createProjectConfig()
 	}
 }
 
 func (gui *GUI) refreshDestinations() {
-	dests, err := kamal.FindDeployConfigs(gui.cwd)
-	if err == nil {
-		gui.destinations = dests
-		if gui.selectedApp >= len(gui.destinations) {
-			gui.selectedApp = len(gui.destinations) - 1
-			if gui.selectedApp < 0 {
-				gui.selectedApp = 0
+	gui.loadDestinations()
+}
+
+// runeKey is a global binding on a printable key.
+type runeKey struct {
+	key rune
+	fn  func(*gocui.Gui, *gocui.View) error
+}
+
+// runeKeys are the global single-key bindings, in the order they are bound.
+// gocui runs every binding that matches, so a key typed into the editor, a
+// dialog, a picker or an input reaches these as well; each stands aside
+// unless navigating (q, ? and b also close the help).
+func (gui *GUI) runeKeys() []runeKey {
+	return []runeKey{
+		// q = quit
+		{'q', func(g *gocui.Gui, v *gocui.View) error {
+			if !gui.navigating() && gui.screen != ScreenHelp {
+				return nil
 			}
-		}
+			return gui.requestQuit()
+		}},
+		// m = main menu from anywhere (except apps)
+		{'m', gui.keyMain},
+		// ? = show help overlay
+		{'?', gui.keyHelp},
+		// H = choose target roles and hosts (--roles/--hosts) for the selected app
+		{'H', gui.keyHosts},
+		// / = filter the Apps list, or search the Output when it has focus or
+		// off the Apps list; n/N = next/previous match
+		{'/', gui.keyFilterApps},
+		{'/', gui.keySearchLog},
+		{'n', gui.keyLogMatch(1)},
+		{'N', gui.keyLogMatch(-1)},
+		// F = jump to the next failed host of the last multi-host command
+		{'F', gui.keyJumpHostFailure},
+		// V = target a version for the next app command, or clear it
+		{'V', gui.keyTargetVersion},
+		// . = run the last command again
+		{'.', gui.keyRerun},
+		// x = show truncated Output lines in full
+		{'x', gui.keyExpandLine},
+		// Z = cycle the Output timestamp zone (local, UTC, server)
+		{'Z', gui.keyCycleZone},
+		// D = show only the selected destination's Output lines, or all
+		{'D', gui.keyDestFilter},
+		// R = re-fetch the running env for Env drift (Config menu)
+		{'R', gui.keyEnvDriftRefresh},
+		// a = show/hide destinations hidden by .lazykamal.yml
+		{'a', gui.keyShowAll},
+		// Accessory screen without accessories: e = edit config, t = insert template
+		{'e', gui.keyAccessoryConfig(false)},
+		{'t', gui.keyAccessoryConfig(true)},
+		// r = refresh destinations
+		{'r', gui.keyRefresh},
+		// c = clear log
+		{'c', gui.keyClearLog},
+		// j/k = scroll the log view, J/K the status view
+		{'k', gui.keyScrollLogUp},
+		{'j', gui.keyScrollLogDown},
+		{'K', gui.keyScrollStatusUp},
+		{'J', gui.keyScrollStatusDown},
+		// g/G = jump like Home/End
+		{'g', gui.keyJump(-1)},
+		{'G', gui.keyJump(1)},
+		// b = back, like Esc; in a dialog b may be typed text
+		{'b', func(g *gocui.Gui, v *gocui.View) error {
+			if gui.screen == ScreenConfirm {
+				return nil
+			}
+			return gui.keyBack(g, v)
+		}},
 	}
 }
 
 func (gui *GUI) keybindings(g *gocui.Gui) error {
-	quit := func(g *gocui.Gui, v *gocui.View) error {
-		return gocui.ErrQuit
-	}
-	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, quit); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding("", 'q', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		return gocui.ErrQuit
+	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		return gui.requestQuit()
 	}); err != nil {
 		return err
 	}
+	for _, k := range gui.runeKeys() {
+		if err := g.SetKeybinding("", k.key, gocui.ModNone, k.fn); err != nil {
+			return err
+		}
+	}
 
 	// Global: Ctrl+X = cancel running command
 	if err := g.SetKeybinding("", gocui.KeyCtrlX, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
@@ -989,100 +1522,59 @@ func (gui *GUI) keybindings(g *gocui.Gui) error {
 	}); err != nil {
 		return err
 	}
-	// Global: m = main menu from anywhere (except apps)
-	if err := g.SetKeybinding("", 'm', gocui.ModNone, gui.keyMain); err != nil {
-		return err
-	}
-	// Global: ? = show help overlay
-	if err := g.SetKeybinding("", '?', gocui.ModNone, gui.keyHelp); err != nil {
-		return err
-	}
-	// Global: r = refresh destinations
-	if err := g.SetKeybinding("", 'r', gocui.ModNone, gui.keyRefresh); err != nil {
-		return err
-	}
-	// Global: c = clear log
-	if err := g.SetKeybinding("", 'c', gocui.ModNone, gui.keyClearLog); err != nil {
-		return err
-	}
-	// Scroll log view: j/k or Page Up/Down
-	if err := g.SetKeybinding("", 'k', gocui.ModNone, gui.keyScrollLogUp); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding("", 'j', gocui.ModNone, gui.keyScrollLogDown); err != nil {
+	// Global: Ctrl+S = save the Output log to a file (the editor saves its own)
+	if err := g.SetKeybinding("", gocui.KeyCtrlS, gocui.ModNone, gui.keyExportLog); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyPgup, gocui.ModNone, gui.keyScrollLogUp); err != nil {
+	// Tab moves focus between the menu and the log; PgUp/PgDn, Home/End and
+	// g/G page or jump in whichever has it.
+	if err := g.SetKeybinding("", gocui.KeyTab, gocui.ModNone, gui.keyToggleFocus); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyPgdn, gocui.ModNone, gui.keyScrollLogDown); err != nil {
-		return err
-	}
-	// Scroll status view: K/J (shift)
-	if err := g.SetKeybinding("", 'K', gocui.ModNone, gui.keyScrollStatusUp); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding("", 'J', gocui.ModNone, gui.keyScrollStatusDown); err != nil {
-		return err
-	}
-	// Confirm dialog: left/right arrows and enter
-	if err := g.SetKeybinding(viewConfirm, gocui.KeyArrowLeft, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirmLeft()
-		return nil
-	}); err != nil {
-		return err
-	}
-	if err := g.SetKeybinding(viewConfirm, gocui.KeyArrowRight, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirmRight()
-		return nil
-	}); err != nil {
-		return err
+	for key, h := range map[gocui.Key]func(*gocui.Gui, *gocui.View) error{
+		gocui.KeyPgup: gui.keyPage(-1),
+		gocui.KeyPgdn: gui.keyPage(1),
+		gocui.KeyHome: gui.keyJump(-1),
+		gocui.KeyEnd:  gui.keyJump(1),
+	} {
+		if err := g.SetKeybinding("", key, gocui.ModNone, h); err != nil {
+			return err
+		}
 	}
-	if err := g.SetKeybinding(viewConfirm, gocui.KeyEnter, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirmEnter()
-		return nil
-	}); err != nil {
+	// Space = pause/resume live log stream
+	if err := g.SetKeybinding("", gocui.KeySpace, gocui.ModNone, gui.keyPauseLogs); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewConfirm, gocui.KeyEsc, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.closeConfirm()
-		return nil
-	}); err != nil {
+	// Confirm dialog
+	if err := bindConfirmKeys(g, viewConfirm, func() *confirmState { return gui.confirm }, gui.answerConfirm); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewConfirm, 'n', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.closeConfirm()
-		return nil
-	}); err != nil {
+	// Up/Down
+	if err := g.SetKeybinding("", gocui.KeyArrowDown, gocui.ModNone, gui.keyDown); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewConfirm, 'y', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirm.Selected = 0
-		gui.confirmEnter()
-		return nil
-	}); err != nil {
+	if err := g.SetKeybinding("", gocui.KeyArrowUp, gocui.ModNone, gui.keyUp); err != nil {
 		return err
 	}
-	// Up/Down
-	if err := g.SetKeybinding("", gocui.KeyArrowDown, gocui.ModNone, gui.keyDown); err != nil {
+	// Left/Right collapse and expand the Apps list groups
+	if err := g.SetKeybinding("", gocui.KeyArrowLeft, gocui.ModNone, gui.keyAppGroup(false)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", gocui.KeyArrowUp, gocui.ModNone, gui.keyUp); err != nil {
+	if err := g.SetKeybinding("", gocui.KeyArrowRight, gocui.ModNone, gui.keyAppGroup(true)); err != nil {
 		return err
 	}
 	// Enter
 	if err := g.SetKeybinding("", gocui.KeyEnter, gocui.ModNone, gui.keyEnter); err != nil {
 		return err
 	}
-	// Escape / b = back
+	// Escape = back
 	if err := g.SetKeybinding("", gocui.KeyEsc, gocui.ModNone, gui.keyBack); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 'b', gocui.ModNone, gui.keyBack); err != nil {
-		return err
-	}
 	gui.setEditorKeybindings(g)
-	return nil
+	gui.setPickerKeybindings(g)
+	gui.setInputKeybindings(g)
+	return bindPagerKeys(g, func() *pager { return gui.pager })
 }
 
 func (gui *GUI) setEditorKeybindings(g *gocui.Gui) {
@@ -1095,10 +1587,47 @@ func (gui *GUI) setEditorKeybindings(g *gocui.Gui) {
 	bind(gocui.KeyArrowDown, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorMoveDown(); return nil })
 	bind(gocui.KeyArrowLeft, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorMoveLeft(); return nil })
 	bind(gocui.KeyArrowRight, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorMoveRight(); return nil })
-	bind(gocui.KeyEnter, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorEnter(); return nil })
-	bind(gocui.KeyBackspace, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorBackspace(); return nil })
-	bind(gocui.KeyBackspace2, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorBackspace(); return nil })
-	bind(gocui.KeyEsc, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorQuit(); return nil })
+	bind(gocui.KeyHome, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorHome(); return nil })
+	bind(gocui.KeyEnd, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorEnd(); return nil })
+	bind(gocui.KeyPgup, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorPage(-1); return nil })
+	bind(gocui.KeyPgdn, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorPage(1); return nil })
+	bind(gocui.KeyEnter, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+		if gui.editorSearching() {
+			gui.editorSearchSubmit()
+		} else if gui.editorGoingTo() {
+			gui.editorGotoSubmit()
+		} else {
+			gui.editorEnter()
+		}
+		return nil
+	})
+	backspace := func(*gocui.Gui, *gocui.View) error {
+		if gui.editorSearching() {
+			gui.editorSearchBackspace()
+		} else if gui.editorGoingTo() {
+			gui.editorGotoBackspace()
+		} else {
+			gui.editorBackspace()
+		}
+		return nil
+	}
+	bind(gocui.KeyBackspace, gocui.ModNone, backspace)
+	bind(gocui.KeyBackspace2, gocui.ModNone, backspace)
+	bind(gocui.KeyEsc, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+		if gui.editorSearching() {
+			gui.editorSearchCancel()
+		} else if gui.editorGoingTo() {
+			gui.editorGotoCancel()
+		} else {
+			gui.editorQuit()
+		}
+		return nil
+	})
+	bind(gocui.KeyCtrlW, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorSearchStart(); return nil })
+	bind(gocui.KeyF3, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorFindNext(); return nil })
+	bind(gocui.KeyCtrlG, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorGotoStart(); return nil })
+	bind(gocui.KeyCtrlZ, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorUndo(); return nil })
+	bind(gocui.KeyCtrlY, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorRedo(); return nil })
 	bind(gocui.KeyCtrlS, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
 		if gui.editorSave() {
 			gui.appendLog([]string{"Saved " + gui.editor.Path})
@@ -1106,11 +1635,19 @@ func (gui *GUI) setEditorKeybindings(g *gocui.Gui) {
 		return nil
 	})
 	bind(gocui.KeyCtrlQ, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorQuit(); return nil })
-	// Printable runes for insert; y/n when ConfirmQuit trigger confirm
+	bind(gocui.KeyCtrlE, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorExternal(); return nil })
+	bind(gocui.KeyCtrlV, gocui.ModNone, func(*gocui.Gui, *gocui.View) error { gui.editorToggleReveal(); return nil })
+	// Printable runes for insert; y/n when ConfirmQuit or ConfirmSave trigger confirm
 	for r := rune(32); r < 127; r++ {
 		r := r
 		bind(gocui.Key(r), gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
-			if gui.editor != nil && gui.editor.ConfirmQuit {
+			if gui.editor != nil && gui.editor.ConfirmSave {
+				gui.editorConfirmSave(r == 'y')
+			} else if gui.editorSearching() {
+				gui.editorSearchType(r)
+			} else if gui.editorGoingTo() {
+				gui.editorGotoType(r)
+			} else if gui.editor != nil && gui.editor.ConfirmQuit {
 				if r == 'y' {
 					gui.editorConfirmQuitYes()
 				} else {
@@ -1125,19 +1662,27 @@ func (gui *GUI) setEditorKeybindings(g *gocui.Gui) {
 }
 
 func (gui *GUI) keyMain(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenApps {
+	if gui.screen == ScreenApps && !gui.refuseBrokenConfig(gui.selectedDestination()) {
 		gui.screen = ScreenMainMenu
 		gui.submenuIdx = 0
 	}
 	return nil
 }
 
+func (gui *GUI) keyHosts(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.selectHosts()
+	return nil
+}
+
 func (gui *GUI) keyHelp(g *gocui.Gui, v *gocui.View) error {
 	if gui.screen == ScreenHelp {
 		gui.closeHelp(g)
 		return nil
 	}
-	if gui.screen != ScreenEditor {
+	if gui.navigating() {
 		gui.screen = ScreenHelp
 	}
 	return nil
@@ -1155,6 +1700,13 @@ func (gui *GUI) getBreadcrumb() string {
 	destLabel := dim("(no app)")
 	if dest != nil {
 		destLabel = cyan(dest.Label())
+		if isProduction(dest) {
+			destLabel = bold(red(dest.Label()))
+		}
+		destLabel += dim(" [" + filepath.Base(dest.ConfigPath) + "]")
+		if label := targetLabel(gui.selectedRoles(), gui.selectedHosts()); label != "" {
+			destLabel += yellow(" [" + label + "]")
+		}
 	}
 
 	path := destLabel
@@ -1171,6 +1723,9 @@ func (gui *GUI) getBreadcrumb() string {
 		path = destLabel + dim(" > ") + blue("Server")
 	case ScreenAccessory:
 		path = destLabel + dim(" > ") + cyan("Accessory")
+		if gui.accessory != "" {
+			path += dim(" > ") + gui.accessory
+		}
 	case ScreenProxy:
 		path = destLabel + dim(" > ") + cyan("Proxy")
 	case ScreenOther:
@@ -1190,7 +1745,7 @@ func (gui *GUI) getBreadcrumb() string {
 }
 
 func (gui *GUI) keyRefresh(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor || gui.screen == ScreenHelp {
+	if !gui.navigating() {
 		return nil
 	}
 	gui.refreshDestinations()
@@ -1200,39 +1755,60 @@ func (gui *GUI) keyRefresh(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *GUI) keyClearLog(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor || gui.screen == ScreenHelp {
+	if !gui.navigating() {
 		return nil
 	}
+	gui.liveLogsMu.Lock()
+	live := gui.liveLogsActive
+	gui.liveLogsMu.Unlock()
 	gui.logMu.Lock()
-	gui.logLines = make([]string, 0, logBufLive)
+	gui.logDropped += len(gui.logLines)
+	gui.logLines = make([]logEntry, 0, logBufLive)
+	gui.long.reset()
+	gui.search = logSearch{}
+	if live {
+		// The stream keeps appending; leave a marker so the jump is visible.
+		gui.logLines = append(gui.logLines, logEntry{at: time.Now(), cleared: true})
+	}
+	gui.logScroll, gui.logPinned = 0, false
 	gui.logMu.Unlock()
-	gui.logScroll = 0
 	return nil
 }
 
 func (gui *GUI) keyScrollLogUp(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor || gui.screen == ScreenHelp {
+	if !gui.navigating() {
 		return nil
 	}
-	if gui.logScroll > 0 {
-		gui.logScroll -= 5
-		if gui.logScroll < 0 {
-			gui.logScroll = 0
-		}
-	}
+	gui.scrollLog(g, -5)
 	return nil
 }
 
 func (gui *GUI) keyScrollLogDown(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor || gui.screen == ScreenHelp {
+	if !gui.navigating() {
 		return nil
 	}
-	gui.logScroll += 5
+	gui.scrollLog(g, 5)
+	return nil
+}
+
+func (gui *GUI) keyCycleZone(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.zone = gui.zone.next()
+	return nil
+}
+
+func (gui *GUI) keyPauseLogs(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.togglePauseLogs()
 	return nil
 }
 
 func (gui *GUI) keyScrollStatusUp(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor || gui.screen == ScreenHelp {
+	if !gui.navigating() {
 		return nil
 	}
 	if gui.statusScroll > 0 {
@@ -1245,7 +1821,7 @@ func (gui *GUI) keyScrollStatusUp(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *GUI) keyScrollStatusDown(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor || gui.screen == ScreenHelp {
+	if !gui.navigating() {
 		return nil
 	}
 	gui.statusScroll += 3
@@ -1253,6 +1829,13 @@ func (gui *GUI) keyScrollStatusDown(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *GUI) keyBack(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen == ScreenPicker || gui.screen == ScreenInput {
+		return nil // handled by the picker and input view bindings
+	}
+	if gui.screen == ScreenPager {
+		gui.closePager()
+		return nil
+	}
 	if gui.screen == ScreenConfirm {
 		gui.closeConfirm()
 		return nil
@@ -1272,6 +1855,13 @@ func (gui *GUI) keyBack(g *gocui.Gui, v *gocui.View) error {
 		gui.stopLiveLogs()
 		return nil
 	}
+	gui.cmdMu.Lock()
+	streaming := gui.streaming
+	gui.cmdMu.Unlock()
+	if streaming {
+		gui.cancelCommand()
+		return nil
+	}
 	switch gui.screen {
 	case ScreenMainMenu:
 		gui.screen = ScreenApps
@@ -1285,36 +1875,19 @@ func (gui *GUI) keyBack(g *gocui.Gui, v *gocui.View) error {
 	case ScreenEditor:
 		gui.editorQuit()
 	}
-	return nil
-}
-
-func (gui *GUI) keyUp(g *gocui.Gui, v *gocui.View) error {
-	if gui.screen == ScreenEditor {
-		gui.editorMoveUp()
-		return nil
-	}
-	switch gui.screen {
-	case ScreenApps:
-		if gui.selectedApp > 0 {
-			gui.selectedApp--
-		}
-	case ScreenMainMenu:
-		if gui.submenuIdx > 0 {
-			gui.submenuIdx--
-		}
-	case ScreenDeploy:
-		if gui.submenuIdx > 0 {
-			gui.submenuIdx--
-		}
-	case ScreenApp:
-		if gui.submenuIdx > 0 {
-			gui.submenuIdx--
-		}
-	case ScreenServer, ScreenAccessory, ScreenProxy, ScreenOther, ScreenConfig, ScreenBuild, ScreenPrune, ScreenSecrets, ScreenRegistry:
-		if gui.submenuIdx > 0 {
-			gui.submenuIdx--
-		}
+	return nil
+}
+
+func (gui *GUI) keyUp(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen == ScreenEditor {
+		gui.editorMoveUp()
+		return nil
+	}
+	if gui.logFocus && gui.navigating() {
+		gui.scrollLog(g, -1)
+		return nil
 	}
+	gui.setSelection(gui.selection() - 1)
 	return nil
 }
 
@@ -1323,60 +1896,11 @@ func (gui *GUI) keyDown(g *gocui.Gui, v *gocui.View) error {
 		gui.editorMoveDown()
 		return nil
 	}
-	switch gui.screen {
-	case ScreenApps:
-		if gui.selectedApp < len(gui.destinations)-1 {
-			gui.selectedApp++
-		}
-	case ScreenMainMenu:
-		if gui.submenuIdx < 6 {
-			gui.submenuIdx++
-		}
-	case ScreenDeploy:
-		if gui.submenuIdx < 7 {
-			gui.submenuIdx++
-		}
-	case ScreenApp:
-		if gui.submenuIdx < 16 {
-			gui.submenuIdx++
-		}
-	case ScreenServer:
-		if gui.submenuIdx < 2 {
-			gui.submenuIdx++
-		}
-	case ScreenAccessory:
-		if gui.submenuIdx < 9 {
-			gui.submenuIdx++
-		}
-	case ScreenProxy:
-		if gui.submenuIdx < 12 {
-			gui.submenuIdx++
-		}
-	case ScreenOther:
-		if gui.submenuIdx < 18 {
-			gui.submenuIdx++
-		}
-	case ScreenConfig:
-		if gui.submenuIdx < 3 {
-			gui.submenuIdx++
-		}
-	case ScreenBuild:
-		if gui.submenuIdx < 6 {
-			gui.submenuIdx++
-		}
-	case ScreenPrune:
-		if gui.submenuIdx < 2 {
-			gui.submenuIdx++
-		}
-	case ScreenSecrets:
-		if gui.submenuIdx < 2 {
-			gui.submenuIdx++
-		}
-	case ScreenRegistry:
-		if gui.submenuIdx < 3 {
-			gui.submenuIdx++
-		}
+	if gui.logFocus && gui.navigating() {
+		gui.scrollLog(g, 1)
+		return nil
 	}
+	gui.setSelection(gui.selection() + 1)
 	return nil
 }
 
@@ -1389,9 +1913,28 @@ func (gui *GUI) keyEnter(g *gocui.Gui, v *gocui.View) error {
 	}
 	switch gui.screen {
 	case ScreenApps:
+		if service, ok := gui.cursorOnCollapsedGroup(); ok {
+			delete(gui.collapsedApps, service)
+			return nil
+		}
+		if dest := gui.selectedDestination(); dest != nil && dest.ConfigErr != nil {
+			gui.editBrokenConfig(dest)
+			return nil
+		}
 		gui.screen = ScreenMainMenu
 		gui.submenuIdx = 0
 	case ScreenMainMenu:
+		if gui.submenuIdx == mainMenuConnect {
+			gui.connectServer()
+			return nil
+		}
+		if gui.submenuIdx == mainMenuHistory {
+			gui.showHistory()
+			return nil
+		}
+		if ScreenDeploy+Screen(gui.submenuIdx) == ScreenAccessory && gui.pickAccessory() {
+			return nil
+		}
 		gui.screen = ScreenDeploy + Screen(gui.submenuIdx)
 		gui.submenuIdx = 0
 	case ScreenConfig:
@@ -1403,7 +1946,9 @@ func (gui *GUI) keyEnter(g *gocui.Gui, v *gocui.View) error {
 	case ScreenServer:
 		gui.execServer()
 	case ScreenAccessory:
-		gui.execAccessory()
+		if gui.hasAccessories() {
+			gui.execAccessory()
+		}
 	case ScreenProxy:
 		gui.execProxy()
 	case ScreenOther:
@@ -1423,24 +1968,140 @@ func (gui *GUI) keyEnter(g *gocui.Gui, v *gocui.View) error {
 // runCommand executes a kamal command with spinner, timing, and proper logging.
 // It creates a stop channel that can be closed via Ctrl+X to cancel the command.
 // The fn receives a stopCh that will be closed on cancel/timeout.
+// The command counts as mutating; see runQueryThen and runItem.
 func (gui *GUI) runCommand(name string, fn func(stopCh <-chan struct{}) (kamal.Result, error)) {
+	gui.runCommandThen(name, fn, nil)
+}
+
+// runCommandThen is runCommand with an onSuccess hook that runs after the
+// completion line is logged, while the command still counts as running.
+func (gui *GUI) runCommandThen(name string, fn func(stopCh <-chan struct{}) (kamal.Result, error), onSuccess func(stopCh <-chan struct{}, took time.Duration)) {
+	gui.runAs(name, false, fn, onSuccess)
+}
+
+// runQueryThen is runCommandThen for a command that only inspects state.
+func (gui *GUI) runQueryThen(name string, fn func(stopCh <-chan struct{}) (kamal.Result, error), onSuccess func(stopCh <-chan struct{}, took time.Duration)) {
+	gui.runAs(name, true, fn, onSuccess)
+}
+
+// runItem runs the highlighted menu item's command as name, read-only when
+// the item is declared so.
+func (gui *GUI) runItem(name string, fn func(stopCh <-chan struct{}) (kamal.Result, error)) {
+	item, _ := gui.selectedMenuItem()
+	gui.runAs(name, item.ReadOnly, fn, nil)
+}
+
+// runAs runs name, mutating or read-only. Mutating commands on a protected
+// destination are confirmed by typing the destination name first; deploys
+// to production are confirmed either way.
+func (gui *GUI) runAs(name string, readOnly bool, fn func(stopCh <-chan struct{}) (kamal.Result, error), onSuccess func(stopCh <-chan struct{}, took time.Duration)) {
+	dest := gui.selectedDestination()
+	note := gui.lockNote(dest, name)
+	if dirty := gui.dirtyNote(dest, name); dirty != "" {
+		note = strings.TrimPrefix(withNote(note, dirty), "\n")
+	}
+	if needsTypedConfirm(dest, readOnly) {
+		message := withNote(productionMessage(dest, name, ""), note)
+		gui.confirmProtected(dest, name, message, func() {
+			gui.startRemembered(gui.newLastCommand(name, readOnly, fn, onSuccess, message))
+		})
+		return
+	}
+	// Deploys to production are confirmed, however routine, and so is any
+	// deploy while the lock is held or from a dirty working tree.
+	if (isProduction(dest) && deployCommands[name]) || note != "" {
+		message := withNote(productionMessage(dest, name, ""), note)
+		gui.prevScreen = gui.screen
+		gui.showConfirm("Confirm "+name, message, func() {
+			gui.startRemembered(gui.newLastCommand(name, readOnly, fn, onSuccess, message))
+		}, nil)
+		return
+	}
+	gui.startRemembered(gui.newLastCommand(name, readOnly, fn, onSuccess, ""))
+}
+
+func (gui *GUI) startCommand(name string, readOnly bool, fn func(stopCh <-chan struct{}) (kamal.Result, error), onSuccess func(stopCh <-chan struct{}, took time.Duration)) {
+	if gui.refuseBrokenConfig(gui.selectedDestination()) {
+		return
+	}
 	gui.cmdMu.Lock()
 	gui.running = true
 	gui.runningCmd = name
 	gui.cmdStartTime = time.Now()
+	gui.cmdClock = newCmdClock(time.Now)
+	clock := gui.cmdClock
 	gui.cmdStopCh = make(chan struct{})
+	destination := gui.eventDestination()
+	start := gui.cmdStartTime
+	sessionDest := ""
+	if dest := gui.selectedDestination(); dest != nil {
+		sessionDest = dest.Label()
+	}
+	errorPayload := gui.pluginPayload(hooks.OnCommandError, gui.selectedDestination())
+	errorPayload.Command = name
+	gui.cmdArgv = nil
+	gui.cmdRetry = nil
+	secretKey := gui.claimSecret()
+	var journaled *journalEntry
+	if !readOnly {
+		journaled = &journalEntry{Action: name, Destination: sessionDest, Roles: gui.selectedRoles(), Hosts: gui.selectedHosts(), Confirm: gui.confirmNote}
+		if journaled.Confirm == "" {
+			journaled.Confirm = confirmNotAsked
+		}
+	}
+	// A mutating command polls the status of its destination before it
+	// reports success; queries leave it to the regular poll.
+	var statusDest *kamal.DeployDestination
+	if dest := gui.selectedDestination(); dest != nil && !readOnly {
+		d := *dest
+		statusDest = &d
+	}
+	var lockDest *kamal.DeployDestination
+	if dest := gui.selectedDestination(); dest != nil && deployLockCommands[name] {
+		d := *dest
+		lockDest = &d
+	}
+	// Deploys to a subset of roles or hosts are neither estimated nor recorded.
+	historyDest := ""
+	gui.cmdSamples = nil
+	if dest := gui.selectedDestination(); dest != nil && etaCommands[name] && gui.history != nil && targetFlags(gui.selectedRoles(), gui.selectedHosts()) == "" {
+		historyDest = dest.ConfigPath
+		gui.cmdSamples = gui.history.samples(historyDest, name)
+	}
 
 	// Start spinner
 	gui.spinner = NewSpinner(name, func() {
+		clock.tick()
 		gui.g.Update(func(*gocui.Gui) error { return nil })
 	})
 	gui.spinner.Start()
 	stopCh := gui.cmdStopCh
 	gui.cmdMu.Unlock()
+	tag := gui.tags.tag(gui.selectedDestination())
+	gui.logMu.Lock()
+	gui.cmdTag = tag
+	gui.logMu.Unlock()
+	// Summaries name the destination; its lines may interleave with others'.
+	title := name
+	if sessionDest != "" {
+		title += " on " + sessionDest
+	}
 
-	gui.logInfo("Running: " + name + " " + dim("(Ctrl+X cancel)"))
+	gui.logInfo("Running: " + title + " " + dim("(Ctrl+X cancel)"))
+	gui.events.Publish(events.Event{Type: events.CommandStarted, Command: name, Destination: destination})
+	if dest := gui.selectedDestination(); dest != nil {
+		gui.appendLog([]string{dim("  config: " + dest.ConfigSource(gui.cwd))})
+	}
+	if flags := targetFlags(gui.selectedRoles(), gui.selectedHosts()); flags != "" {
+		gui.appendLog([]string{yellow("  " + flags)})
+	}
+	if secretKey != "" {
+		gui.appendLog([]string{yellow("  env: " + secretKey + " (secret, this command only)")})
+	}
 
 	go func() {
+		var retry *deployRetry
+		polled := false
 		defer func() {
 			gui.cmdMu.Lock()
 			gui.spinner.Stop()
@@ -1448,209 +2109,313 @@ func (gui *GUI) runCommand(name string, fn func(stopCh <-chan struct{}) (kamal.R
 			gui.running = false
 			gui.runningCmd = ""
 			gui.cmdStopCh = nil
+			unused := gui.dropClaimedSecret()
 			gui.cmdMu.Unlock()
+			if unused != "" {
+				gui.appendLog([]string{statusLine("warning", unused+" was not used: "+name+" ran no kamal command; it is forgotten")})
+			}
+			gui.logMu.Lock()
+			if gui.cmdTag == tag {
+				gui.cmdTag = ""
+			}
+			gui.logMu.Unlock()
 			gui.g.Update(func(*gocui.Gui) error { return nil })
+			if retry != nil {
+				gui.offerRetry(*retry)
+			}
+			if !polled {
+				go gui.refreshStatus()
+			}
 		}()
 
 		res, err := fn(stopCh)
-		duration := time.Since(gui.cmdStartTime)
+		duration, unreliable := clock.elapsed(), clock.unreliable()
+		if touchesProxy(name) {
+			gui.proxy.forget()
+		}
+		if touchesAccessories(name) {
+			gui.accessoryHealth.forget()
+		}
+		if touchesLock(name) {
+			gui.lock.forget()
+		}
+		outcome := sessionOutcome(res, err, stopCh)
+		gui.session.record(sessionCommand{Name: name, Destination: sessionDest, Took: duration, Unreliable: unreliable, Outcome: outcome})
+		entry := newHistoryEntry(name, sessionDest, start, duration, res, err, outcome, cleanOutputLines(res.Lines(), gui.ansi))
+		entry.Unreliable = unreliable
+		if err := gui.commands.add(entry); err != nil && gui.debug {
+			gui.logInfo("History not saved: " + err.Error())
+		}
+		if journaled != nil {
+			gui.cmdMu.Lock()
+			journaled.Commands = gui.cmdArgv
+			gui.cmdMu.Unlock()
+			journaled.Time, journaled.Outcome, journaled.DurationMs = start, outcome, duration.Milliseconds()
+			gui.journalAction(*journaled)
+		}
+		if outcome != "ok" && outcome != "cancelled" {
+			errorPayload.DurationMs = duration.Milliseconds()
+			errorPayload.Error = firstErrorLine(res, err)
+			if err == nil {
+				errorPayload.ExitCode = &res.ExitCode
+			}
+			gui.firePlugins(gui.projectConfig().Plugins.OnCommandError, errorPayload)
+		}
 
+		finished := events.Event{Type: events.CommandFinished, Command: name, Destination: destination, DurationMs: duration.Milliseconds()}
 		if err != nil {
-			gui.logError(fmt.Sprintf("%s failed: %s", name, err.Error()))
+			var timeout *kamal.TimeoutError
+			if errors.As(err, &timeout) {
+				gui.logError(fmt.Sprintf("%s %s", title, err.Error()))
+			} else {
+				gui.logError(fmt.Sprintf("%s failed: %s", title, err.Error()))
+			}
+			finished.Success, finished.Error = events.Result(false), err.Error()
+			gui.events.Publish(finished)
+			if lockDest != nil {
+				go gui.offerLockRelease(name, "cancelled", *lockDest, start)
+			}
 			return
 		}
+		finished.Success = events.Result(res.ExitCode == 0)
+		gui.events.Publish(finished)
+		if name == "Lock Acquire" && res.ExitCode == 0 {
+			gui.events.Publish(events.Event{Type: events.LockAcquired, Destination: destination})
+		}
 
-		// Log output
-		gui.appendLogFromResult(res)
+		// Log output, unless it was logged as it arrived
+		if !res.Streamed {
+			gui.appendLogFromResult(res)
+		}
 
 		// Log completion with duration
 		if res.ExitCode == 0 {
-			gui.logSuccess(fmt.Sprintf("%s completed in %s", name, formatDuration(duration)))
+			if statusDest != nil {
+				gui.pollStatus(statusDest)
+				polled = true
+			}
+			gui.logSuccess(fmt.Sprintf("%s completed in %s", title, tookText(duration, unreliable)))
+			// A duration across a suspend would skew the ETA.
+			if historyDest != "" && !unreliable {
+				if err := gui.history.record(historyDest, name, duration); err != nil && gui.debug {
+					gui.logInfo("Deploy history not saved: " + err.Error())
+				}
+			}
+			if onSuccess != nil {
+				onSuccess(stopCh, duration)
+			}
 		} else {
-			gui.logError(fmt.Sprintf("%s failed (exit %d) in %s", name, res.ExitCode, formatDuration(duration)))
+			gui.logError(fmt.Sprintf("%s failed (exit %d) in %s", title, res.ExitCode, tookText(duration, unreliable)))
+			gui.cmdMu.Lock()
+			retry = gui.cmdRetry
+			gui.cmdMu.Unlock()
+			if retry != nil {
+				gui.appendLog([]string{yellow("  Likely transient: " + retry.reason())})
+			}
+			if lockDest != nil {
+				go gui.offerLockRelease(name, "failed", *lockDest, start)
+			}
 		}
 	}()
 }
 
-// cancelCommand cancels the currently running command if any.
+// cancelCommand cancels the currently running command if any. Closing the
+// stop channel kills the kamal process; startCommand's cleanup then clears
+// the running state as for any other exit.
 func (gui *GUI) cancelCommand() {
 	gui.cmdMu.Lock()
 	var name string
+	var elapsed time.Duration
 	if gui.running && gui.cmdStopCh != nil {
 		name = gui.runningCmd
+		elapsed = gui.cmdClock.elapsed()
 		close(gui.cmdStopCh)
 		gui.cmdStopCh = nil
 	}
 	gui.cmdMu.Unlock()
 	if name != "" {
-		gui.logInfo("Cancelled: " + name)
+		gui.logInfo(fmt.Sprintf("Cancelled: %s after %s", name, formatDuration(elapsed)))
 	}
 }
 
 // runWithConfirm shows a confirmation dialog before running a destructive command
 func (gui *GUI) runWithConfirm(name string, message string, fn func(stopCh <-chan struct{}) (kamal.Result, error)) {
+	gui.runWithConfirmThen(name, message, fn, nil)
+}
+
+// runWithConfirmThen is runWithConfirm with an onSuccess hook, as for
+// runCommandThen.
+func (gui *GUI) runWithConfirmThen(name string, message string, fn func(stopCh <-chan struct{}) (kamal.Result, error), onSuccess func(stopCh <-chan struct{}, took time.Duration)) {
+	message = productionMessage(gui.selectedDestination(), name, message)
+	if flags := targetFlags(gui.selectedRoles(), gui.selectedHosts()); flags != "" {
+		message += " (" + flags + ")"
+	}
+	if dest := gui.selectedDestination(); needsTypedConfirm(dest, false) {
+		gui.confirmProtected(dest, name, message, func() {
+			gui.startRemembered(gui.newLastCommand(name, false, fn, onSuccess, message))
+		})
+		return
+	}
 	gui.prevScreen = gui.screen
 	gui.showConfirm("Confirm "+name, message, func() {
-		gui.runCommand(name, fn)
+		gui.startRemembered(gui.newLastCommand(name, false, fn, onSuccess, message))
 	}, nil)
+	gui.confirm.RequireText = productionConfirmText(gui.selectedDestination(), name)
 }
 
 func (gui *GUI) execDeploy() {
-	opts := gui.runOpts()
-	var fn func(stopCh <-chan struct{}) (kamal.Result, error)
 	var name string
+	var args []string
 
 	switch gui.submenuIdx {
 	case 0:
 		name = "Deploy"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"deploy"}, opts, stopCh)
-		}
+		args = []string{"deploy"}
 	case 1:
 		name = "Deploy (skip push)"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"deploy", "--skip-push"}, opts, stopCh)
-		}
+		args = []string{"deploy", "--skip-push"}
 	case 2:
 		name = "Redeploy"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"redeploy"}, opts, stopCh)
-		}
+		args = []string{"redeploy"}
 	case 3:
-		name = "Rollback"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"rollback"}, opts, stopCh)
-		}
-		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
+		gui.pickRollback()
 		return
 	case 4:
 		name = "Setup"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"setup"}, opts, stopCh)
-		}
+		args = []string{"setup"}
 	case 5:
 		name = "Deploy (no cache)"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"deploy", "--no-cache"}, opts, stopCh)
-		}
+		args = []string{"deploy", "--no-cache"}
 	case 6:
 		name = "Redeploy (no cache)"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"redeploy", "--no-cache"}, opts, stopCh)
-		}
+		args = []string{"redeploy", "--no-cache"}
 	case 7:
 		name = "Setup (no cache)"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"setup", "--no-cache"}, opts, stopCh)
-		}
+		args = []string{"setup", "--no-cache"}
+	case 8:
+		gui.observeDeploy()
+		return
 	default:
 		return
 	}
 
-	gui.runCommand(name, fn)
+	switch gui.submenuIdx {
+	case 0, 1, 2, 5, 6:
+		gui.runDeploy(name, args)
+		return
+	}
+	gui.runCommand(name, gui.streamCommand(args, gui.commandOpts(args)))
+}
+
+// runDeploy runs a deploy or redeploy variant with a post-deploy summary
+// comparing the version running before and after. When it fails for a
+// transient reason, a retry is offered once it has finished.
+func (gui *GUI) runDeploy(name string, args []string) {
+	opts := gui.commandOpts(args)
+	deploy := gui.streamCommand(args, opts)
+	var dest kamal.DeployDestination
+	if d := gui.selectedDestination(); d != nil {
+		dest = *d
+	}
+	var before map[string]string
+	fn := func(stopCh <-chan struct{}) (kamal.Result, error) {
+		if r, err := kamal.RunKamalWithStop([]string{"app", "version"}, opts, stopCh); err == nil {
+			before = kamal.ParseAppVersions(r.Combined())
+		}
+		res, err := deploy(stopCh)
+		if err == nil && res.ExitCode != 0 {
+			if retry := retryFor(name, args, res.Lines()); retry != nil {
+				retry.dest = dest
+				gui.cmdMu.Lock()
+				gui.cmdRetry = retry
+				gui.cmdMu.Unlock()
+			}
+		}
+		return res, err
+	}
+	gui.runCommandThen(name, fn, func(stopCh <-chan struct{}, took time.Duration) {
+		version := gui.deploySummary(opts, before, took, stopCh)
+		gui.session.setVersion(version)
+		success := gui.pluginPayload(hooks.OnDeploySuccess, gui.selectedDestination())
+		success.Command, success.Version, success.PreviousVersion = name, version, versionLabel(before)
+		success.DurationMs = took.Milliseconds()
+		gui.firePlugins(gui.projectConfig().Plugins.OnDeploySuccess, success)
+		gui.runPostDeploy(opts, versionLabel(before), version, stopCh)
+	})
 }
 
 func (gui *GUI) execApp() {
-	opts := gui.runOpts()
-	var fn func(stopCh <-chan struct{}) (kamal.Result, error)
 	var name string
+	var args []string
 	needsConfirm := false
 
 	switch gui.submenuIdx {
 	case 0:
-		name = "App Boot"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "boot"}, opts, stopCh)
-		}
+		gui.bootApp()
+		return
 	case 1:
 		name = "App Start"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "start"}, opts, stopCh)
-		}
+		args = []string{"app", "start"}
 	case 2:
 		name = "App Stop"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "stop"}, opts, stopCh)
-		}
+		args = []string{"app", "stop"}
 		needsConfirm = true
 	case 3:
 		name = "App Restart"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "restart"}, opts, stopCh)
-		}
+		args = []string{"app", "restart"}
 	case 4:
 		name = "App Logs"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "logs"}, opts, stopCh)
-		}
+		args = []string{"app", "logs"}
 	case 5:
 		name = "App Containers"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "containers"}, opts, stopCh)
-		}
+		args = []string{"app", "containers"}
 	case 6:
 		name = "App Details"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "details"}, opts, stopCh)
-		}
+		args = []string{"app", "details"}
 	case 7:
 		name = "App Images"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "images"}, opts, stopCh)
-		}
+		args = []string{"app", "images"}
 	case 8:
 		name = "App Version"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "version"}, opts, stopCh)
-		}
+		args = []string{"app", "version"}
 	case 9:
-		name = "App Stale Containers"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "stale_containers"}, opts, stopCh)
-		}
+		gui.detectStale(nil)
+		return
 	case 10:
-		name = "App Exec: whoami"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "exec", "whoami"}, opts, stopCh)
-		}
+		gui.promptAppExec()
+		return
 	case 11:
 		name = "App Maintenance"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "maintenance"}, opts, stopCh)
-		}
+		args = []string{"app", "maintenance"}
 	case 12:
 		name = "App Live"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "live"}, opts, stopCh)
-		}
+		args = []string{"app", "live"}
 	case 13:
 		name = "App Remove"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "remove"}, opts, stopCh)
-		}
+		args = []string{"app", "remove"}
 		needsConfirm = true
 	case 14:
 		gui.startLiveLogs("app")
 		return
 	case 15:
-		name = "App Stale Containers (stop)"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "stale_containers", "--stop"}, opts, stopCh)
-		}
-		needsConfirm = true
+		gui.stopRemoveStale()
+		return
 	case 16:
 		name = "App Exec: whoami (detach)"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"app", "exec", "--detach", "whoami"}, opts, stopCh)
-		}
+		args = []string{"app", "exec", "--detach", "whoami"}
 	default:
 		return
 	}
 
+	opts := gui.commandOpts(args)
+	fn := func(stopCh <-chan struct{}) (kamal.Result, error) {
+		return kamal.RunKamalWithStop(args, opts, stopCh)
+	}
+
 	if needsConfirm {
 		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
 	} else {
-		gui.runCommand(name, fn)
+		gui.runItem(name, fn)
 	}
 }
 
@@ -1679,76 +2444,63 @@ func (gui *GUI) execServer() {
 		return
 	}
 
-	gui.runCommand(name, fn)
+	gui.runItem(name, fn)
 }
 
 func (gui *GUI) execAccessory() {
 	opts := gui.runOpts()
-	var fn func(stopCh <-chan struct{}) (kamal.Result, error)
+	target, label := gui.accessoryArg(), gui.accessoryLabel()
+	var args []string
 	var name string
 	needsConfirm := false
 
 	switch gui.submenuIdx {
 	case 0:
-		name = "Accessory Boot All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "boot", "all"}, opts, stopCh)
-		}
+		name = "Accessory Boot " + label
+		args = []string{"accessory", "boot", target}
 	case 1:
-		name = "Accessory Start All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "start", "all"}, opts, stopCh)
-		}
+		name = "Accessory Start " + label
+		args = []string{"accessory", "start", target}
 	case 2:
-		name = "Accessory Stop All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "stop", "all"}, opts, stopCh)
-		}
+		name = "Accessory Stop " + label
+		args = []string{"accessory", "stop", target}
 		needsConfirm = true
 	case 3:
-		name = "Accessory Restart All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "restart", "all"}, opts, stopCh)
-		}
+		name = "Accessory Restart " + label
+		args = []string{"accessory", "restart", target}
 	case 4:
-		name = "Accessory Reboot All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "reboot", "all"}, opts, stopCh)
-		}
+		name = "Accessory Reboot " + label
+		args = []string{"accessory", "reboot", target}
 	case 5:
-		name = "Accessory Remove All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "remove", "all"}, opts, stopCh)
-		}
+		name = "Accessory Remove " + label
+		args = []string{"accessory", "remove", target}
 		needsConfirm = true
 	case 6:
-		name = "Accessory Details All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "details", "all"}, opts, stopCh)
-		}
+		name = "Accessory Details " + label
+		args = []string{"accessory", "details", target}
 	case 7:
-		name = "Accessory Logs All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "logs", "all"}, opts, stopCh)
-		}
+		name = "Accessory Logs " + label
+		args = []string{"accessory", "logs", target}
 	case 8:
-		name = "Accessory Exec All"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "exec", "all", "sh"}, opts, stopCh)
-		}
+		name = "Accessory Exec " + label
+		args = []string{"accessory", "exec", target, "sh"}
 	case 9:
 		name = "Accessory Upgrade"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"accessory", "upgrade"}, opts, stopCh)
-		}
+		args = []string{"accessory", "upgrade"}
 	default:
 		return
 	}
+	fn := func(stopCh <-chan struct{}) (kamal.Result, error) {
+		return kamal.RunKamalWithStop(args, opts, stopCh)
+	}
+	if target == "all" && (args[1] == "details" || args[1] == "logs") {
+		fn = gui.accessoryEach(args[1], opts)
+	}
 
 	if needsConfirm {
-		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
+		gui.runWithConfirm(name, gui.accessoryMessage(getDestructiveMessage(gui.screen, gui.submenuIdx)), fn)
 	} else {
-		gui.runCommand(name, fn)
+		gui.runItem(name, fn)
 	}
 }
 
@@ -1824,6 +2576,9 @@ func (gui *GUI) execProxy() {
 	case 12:
 		gui.startLiveLogs("proxy")
 		return
+	case 13:
+		gui.checkProxyUpgrade()
+		return
 	default:
 		return
 	}
@@ -1831,8 +2586,51 @@ func (gui *GUI) execProxy() {
 	if needsConfirm {
 		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
 	} else {
-		gui.runCommand(name, fn)
+		gui.runItem(name, fn)
+	}
+}
+
+// checkProxyUpgrade compares the running kamal-proxy image with the newest
+// published tag, then offers a rolling proxy reboot to pick up the new image.
+func (gui *GUI) checkProxyUpgrade() {
+	opts := gui.runOpts()
+	gui.logInfo("Checking kamal-proxy versions...")
+	go func() {
+		current := "unknown"
+		if r, err := kamal.RunKamal([]string{"proxy", "details"}, opts); err == nil {
+			if tag := proxyTagFromDetails(r.Combined()); tag != "" {
+				current = tag
+			}
+		}
+		available, err := docker.LatestProxyVersion()
+		if err != nil {
+			gui.logError("Registry check failed: " + err.Error())
+			available = "unknown"
+		}
+		gui.logInfo(fmt.Sprintf("kamal-proxy running: %s, available: %s", current, available))
+
+		msg := fmt.Sprintf("Proxy %s -> %s. Reboot proxy (rolling) now?", current, available)
+		if c, ok := docker.CompareVersions(current, available); ok && c >= 0 {
+			msg = fmt.Sprintf("Proxy %s is up to date. Reboot (rolling) anyway?", current)
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.runWithConfirm("Proxy Upgrade", msg, func(stopCh <-chan struct{}) (kamal.Result, error) {
+				return kamal.RunKamalWithStop([]string{"proxy", "reboot", "--rolling"}, opts, stopCh)
+			})
+			return nil
+		})
+	}()
+}
+
+// proxyTagFromDetails extracts the kamal-proxy image tag from `kamal proxy details` output.
+func proxyTagFromDetails(output string) string {
+	prefix := docker.ProxyImageRepo + ":"
+	for _, field := range strings.Fields(output) {
+		if strings.HasPrefix(field, prefix) {
+			return docker.ImageTag(field)
+		}
 	}
+	return ""
 }
 
 func (gui *GUI) execOther() {
@@ -1935,6 +2733,17 @@ func (gui *GUI) execOther() {
 		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
 			return kamal.RunKamalWithStop([]string{"version"}, opts, stopCh)
 		}
+		gui.runQueryThen(name, fn, func(<-chan struct{}, time.Duration) { gui.checkKamalVersion(opts.Cwd, true) })
+		return
+	case 19: // Journal >
+		gui.showJournal()
+		return
+	case 20:
+		gui.promptSecretEnv()
+		return
+	case 21:
+		gui.removeEverything()
+		return
 	default:
 		return
 	}
@@ -1942,7 +2751,7 @@ func (gui *GUI) execOther() {
 	if needsConfirm {
 		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
 	} else {
-		gui.runCommand(name, fn)
+		gui.runItem(name, fn)
 	}
 }
 
@@ -1955,24 +2764,16 @@ func (gui *GUI) execBuild() {
 	switch gui.submenuIdx {
 	case 0:
 		name = "Build Push"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"build", "push"}, opts, stopCh)
-		}
+		fn = gui.streamCommand([]string{"build", "push"}, opts)
 	case 1:
 		name = "Build Pull"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"build", "pull"}, opts, stopCh)
-		}
+		fn = gui.streamCommand([]string{"build", "pull"}, opts)
 	case 2:
 		name = "Build Deliver"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"build", "deliver"}, opts, stopCh)
-		}
+		fn = gui.streamCommand([]string{"build", "deliver"}, opts)
 	case 3:
 		name = "Build Dev"
-		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
-			return kamal.RunKamalWithStop([]string{"build", "dev"}, opts, stopCh)
-		}
+		fn = gui.streamCommand([]string{"build", "dev"}, opts)
 	case 4:
 		name = "Build Create"
 		fn = func(stopCh <-chan struct{}) (kamal.Result, error) {
@@ -1996,7 +2797,7 @@ func (gui *GUI) execBuild() {
 	if needsConfirm {
 		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
 	} else {
-		gui.runCommand(name, fn)
+		gui.runItem(name, fn)
 	}
 }
 
@@ -2054,7 +2855,7 @@ func (gui *GUI) execSecrets() {
 		return
 	}
 
-	gui.runCommand(name, fn)
+	gui.runItem(name, fn)
 }
 
 func (gui *GUI) execRegistry() {
@@ -2092,20 +2893,61 @@ func (gui *GUI) execRegistry() {
 	if needsConfirm {
 		gui.runWithConfirm(name, getDestructiveMessage(gui.screen, gui.submenuIdx), fn)
 	} else {
-		gui.runCommand(name, fn)
+		gui.runItem(name, fn)
+	}
+}
+
+// attach sets up layout and keybindings on a new gocui instance.
+func (gui *GUI) attach(g *gocui.Gui) error {
+	g.SetManagerFunc(gui.layout)
+	if err := gui.keybindings(g); err != nil {
+		return err
 	}
+	g.SelFgColor = gocui.ColorCyan
+	g.ASCII = asciiOutput
+	gui.g = g
+	return nil
 }
 
 // Run starts the TUI main loop.
-func (gui *GUI) Run() error {
-	defer gui.g.Close()
-	defer func() {
-		close(gui.statusStopCh)
-		if gui.statusTicker != nil {
-			gui.statusTicker.Stop()
-		}
-	}()
-	return gui.g.MainLoop()
+func (gui *GUI) Run() (err error) {
+	defer recoverCrash(gui.version, &err)
+	defer func() { gui.g.Close() }()
+	gui.startEvents()
+	defer gui.events.Close()
+	defer gui.stopStatusPolling()
+	return runMainLoop(gui, &gui.handoff)
+}
+
+func (gui *GUI) ui() *gocui.Gui { return gui.g }
+
+// pause stops live logs and status polling before the terminal is handed
+// to another program.
+func (gui *GUI) pause() {
+	gui.stopLiveLogs()
+	gui.stopStatusPolling()
+}
+
+// resume recreates the gocui instance after an external program and
+// restarts status polling; screen and selection are unchanged.
+func (gui *GUI) resume() error {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		return err
+	}
+	if err := gui.attach(g); err != nil {
+		g.Close()
+		return err
+	}
+	gui.statusStopCh = make(chan struct{})
+	gui.startStatusPolling()
+	return nil
+}
+
+// Stop ends the main loop from another goroutine, e.g. on SIGTERM; Run
+// then restores the terminal and returns.
+func (gui *GUI) Stop() {
+	gui.g.Update(quit)
 }
 
 // Close tears down the gocui instance, restoring terminal state.
@@ -2118,16 +2960,20 @@ func (gui *GUI) Close() {
 func (gui *GUI) SetCwd(cwd string) error {
 	absPath, err := filepath.Abs(cwd)
 	if err != nil {
-		return fmt.Errorf("invalid path: %w", err)
+		return &StartupError{Stage: StageProject, Err: fmt.Errorf("invalid path: %w", err)}
 	}
 
 	// Validate the path is safe
 	if err := validateCwd(absPath); err != nil {
-		return err
+		return &StartupError{Stage: StageProject, Err: err}
 	}
 
 	gui.cwd = absPath
-	gui.destinations, _ = kamal.FindDeployConfigs(gui.cwd)
-	gui.selectedApp = 0
+	gui.commands = loadCommandHistory(defaultCommandHistoryPath(), gui.cwd, historyMaxFromEnv())
+	gui.destinations = nil
+	gui.loadDestinations()
+	gui.resetStatus()
+	go gui.checkKamalVersion(gui.cwd, false)
+	go gui.checkStaleLocks(gui.cwd, append([]kamal.DeployDestination(nil), gui.destinations...))
 	return nil
 }
@@ -1,6 +1,10 @@
 package gui
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestScreenString(t *testing.T) {
 	tests := []struct {
@@ -23,6 +27,7 @@ func TestScreenString(t *testing.T) {
 		{ScreenPrune, "prune"},
 		{ScreenSecrets, "secrets"},
 		{ScreenRegistry, "registry"},
+		{ScreenPicker, "picker"},
 		{Screen(999), "unknown"},
 	}
 
@@ -38,14 +43,14 @@ func TestScreenString(t *testing.T) {
 // menuItemCounts maps each screen to its expected number of menu items.
 // This must stay in sync with the render functions and keyDown max bounds.
 var menuItemCounts = map[Screen]int{
-	ScreenMainMenu:  7,  // Deploy, App, Server, Accessory, Proxy, Other, Config
-	ScreenDeploy:    8,  // Deploy, Deploy (skip push), Redeploy, Rollback, Setup, Deploy (no cache), Redeploy (no cache), Setup (no cache)
+	ScreenMainMenu:  9,  // Deploy, App, Server, Accessory, Proxy, Other, Config, Connect to server, History
+	ScreenDeploy:    9,  // Deploy, Deploy (skip push), Redeploy, Rollback, Setup, Deploy (no cache), Redeploy (no cache), Setup (no cache), Observe
 	ScreenApp:       17, // Boot..Live:App logs + Stale containers (stop) + Exec: whoami (detach)
 	ScreenServer:    3,  // Bootstrap, Exec: date, Exec: uptime
 	ScreenAccessory: 10, // Boot..Upgrade
-	ScreenProxy:     13, // Boot..Live: Proxy logs
-	ScreenOther:     19, // Prune>, Build>, Config..Version
-	ScreenConfig:    4,  // Edit deploy, Edit secrets, Redeploy, App restart
+	ScreenProxy:     14, // Boot..Live: Proxy logs, Upgrade
+	ScreenOther:     22, // Prune>, Build>, Config..Version, Journal>, Run with secret env, Remove EVERYTHING
+	ScreenConfig:    7,  // Edit deploy, Edit secrets, Redeploy, App restart, Env drift, Bulk edit, Create project config
 	ScreenBuild:     7,  // Push, Pull, Deliver, Dev, Create, Remove, Details
 	ScreenPrune:     3,  // All, Images, Containers
 	ScreenSecrets:   3,  // Fetch, Extract, Print
@@ -56,14 +61,14 @@ func TestKeyDownMaxBounds(t *testing.T) {
 	// The keyDown max bound for each screen should be itemCount - 1.
 	// This test verifies the bounds match the menu item counts.
 	expectedMax := map[Screen]int{
-		ScreenMainMenu:  6,
-		ScreenDeploy:    7,
+		ScreenMainMenu:  8,
+		ScreenDeploy:    8,
 		ScreenApp:       16,
 		ScreenServer:    2,
 		ScreenAccessory: 9,
-		ScreenProxy:     12,
-		ScreenOther:     18,
-		ScreenConfig:    3,
+		ScreenProxy:     13,
+		ScreenOther:     21,
+		ScreenConfig:    6,
 		ScreenBuild:     6,
 		ScreenPrune:     2,
 		ScreenSecrets:   2,
@@ -80,6 +85,10 @@ func TestKeyDownMaxBounds(t *testing.T) {
 			t.Errorf("Screen %q: menuItemCounts=%d implies max=%d, but expected max=%d",
 				screen, count, count-1, wantMax)
 		}
+		gui := &GUI{screen: screen}
+		if got, ok := gui.menuLast(); !ok || got != wantMax {
+			t.Errorf("Screen %q: menuLast()=%d,%v, expected max=%d", screen, got, ok, wantMax)
+		}
 	}
 }
 
@@ -127,3 +136,54 @@ func TestGetDestructiveMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestProxyTagFromDetails(t *testing.T) {
+	output := `  INFO [a1b2] Running docker ps --filter name=^kamal-proxy$ on 10.0.0.1
+CONTAINER ID   IMAGE                         COMMAND                  STATUS
+4f1c2d3e5a6b   basecamp/kamal-proxy:v0.8.0   "kamal-proxy run"        Up 3 days`
+	if got := proxyTagFromDetails(output); got != "v0.8.0" {
+		t.Errorf("proxyTagFromDetails() = %q, want v0.8.0", got)
+	}
+	if got := proxyTagFromDetails("no proxy here"); got != "" {
+		t.Errorf("proxyTagFromDetails() = %q, want empty", got)
+	}
+}
+
+func TestCancelCommand(t *testing.T) {
+	stopCh := make(chan struct{})
+	started := time.Now().Add(-3 * time.Second)
+	gui := &GUI{running: true, runningCmd: "Deploy", cmdStartTime: started, cmdClock: &cmdClock{now: time.Now, start: started}, cmdStopCh: stopCh}
+	gui.cancelCommand()
+	select {
+	case <-stopCh:
+	default:
+		t.Fatal("stop channel not closed")
+	}
+	if gui.cmdStopCh != nil {
+		t.Error("cmdStopCh kept after cancel")
+	}
+	last := stripANSI(gui.logLines[len(gui.logLines)-1].text)
+	if !strings.Contains(last, "Cancelled: Deploy after 3") {
+		t.Errorf("log = %q, want the name and elapsed time", last)
+	}
+	// A second Ctrl+X has nothing left to cancel.
+	gui.cancelCommand()
+	if n := len(gui.logLines); n != 1 {
+		t.Errorf("second cancel logged again (%d lines)", n)
+	}
+}
+
+func TestStatusPauseReason(t *testing.T) {
+	gui := &GUI{}
+	if got := gui.statusPauseReason(); got != "" {
+		t.Errorf("idle: statusPauseReason() = %q, want polling", got)
+	}
+	gui.liveLogsActive = true
+	if got := gui.statusPauseReason(); got != "paused while logs stream" {
+		t.Errorf("live logs: statusPauseReason() = %q", got)
+	}
+	gui.running, gui.runningCmd = true, "Deploy"
+	if got := gui.statusPauseReason(); got != "paused while Deploy runs" {
+		t.Errorf("running: statusPauseReason() = %q", got)
+	}
+}
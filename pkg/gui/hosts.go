@@ -0,0 +1,179 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// hostsKey identifies a destination for the per-session host selection.
+func hostsKey(dest *kamal.DeployDestination) string {
+	return dest.ConfigPath
+}
+
+// selectedHosts returns the --hosts selection for the current destination.
+func (gui *GUI) selectedHosts() []string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return nil
+	}
+	return gui.hostSelections[hostsKey(dest)]
+}
+
+// hostPickerItems lists the configured servers with their roles, checking
+// those in current and appending manually added hosts not in the config.
+func hostPickerItems(servers []kamal.ServerHost, current []string) []pickerItem {
+	checked := map[string]bool{}
+	for _, h := range current {
+		checked[h] = true
+	}
+	var items []pickerItem
+	listed := map[string]bool{}
+	for _, s := range servers {
+		listed[s.Host] = true
+		items = append(items, pickerItem{
+			Label:   s.Host + dim(" ("+strings.Join(s.Roles, ", ")+")"),
+			Value:   s.Host,
+			Checked: checked[s.Host],
+		})
+	}
+	for _, h := range current {
+		if !listed[h] {
+			items = append(items, pickerItem{Label: h + dim(" (manual)"), Value: h, Checked: true})
+		}
+	}
+	return items
+}
+
+// rolePrefix marks role items in the target picker; host items are bare.
+const rolePrefix = "role:"
+
+// selectedRoles returns the --roles selection for the current destination.
+func (gui *GUI) selectedRoles() []string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return nil
+	}
+	return gui.roleSelections[hostsKey(dest)]
+}
+
+// rolePickerItems lists the configured roles with their host counts,
+// checking those in current.
+func rolePickerItems(servers []kamal.ServerHost, current []string) []pickerItem {
+	checked := map[string]bool{}
+	for _, r := range current {
+		checked[r] = true
+	}
+	counts := map[string]int{}
+	for _, s := range servers {
+		for _, r := range s.Roles {
+			counts[r]++
+		}
+	}
+	var items []pickerItem
+	for _, r := range kamal.ServerRoles(servers) {
+		items = append(items, pickerItem{
+			Label:   "role " + r + dim(fmt.Sprintf(" (%d host(s))", counts[r])),
+			Value:   rolePrefix + r,
+			Checked: checked[r],
+		})
+	}
+	return items
+}
+
+// splitTarget separates the picker's values into roles and hosts. clear is
+// set when the "clear filter" item was checked.
+func splitTarget(values []string) (roles, hosts []string, clear bool) {
+	for _, v := range values {
+		switch {
+		case v == "":
+			clear = true
+		case strings.HasPrefix(v, rolePrefix):
+			roles = append(roles, strings.TrimPrefix(v, rolePrefix))
+		default:
+			hosts = append(hosts, v)
+		}
+	}
+	return roles, hosts, clear
+}
+
+// targetLabel describes a role/host filter, e.g. "web @ 10.0.0.2", or ""
+// when there is none.
+func targetLabel(roles, hosts []string) string {
+	switch {
+	case len(roles) > 0 && len(hosts) > 0:
+		return strings.Join(roles, ",") + " @ " + strings.Join(hosts, ",")
+	case len(roles) > 0:
+		return strings.Join(roles, ",")
+	case len(hosts) > 0:
+		return "@ " + strings.Join(hosts, ",")
+	}
+	return ""
+}
+
+// targetFlags is the --roles/--hosts arguments of a filter, for logs and
+// confirm messages.
+func targetFlags(roles, hosts []string) string {
+	var flags []string
+	if len(roles) > 0 {
+		flags = append(flags, "--roles "+strings.Join(roles, ","))
+	}
+	if len(hosts) > 0 {
+		flags = append(flags, "--hosts "+strings.Join(hosts, ","))
+	}
+	return strings.Join(flags, " ")
+}
+
+// selectHosts opens the multi-select target picker for the current
+// destination: roles first, then hosts, with a "clear filter" item while a
+// filter is set. The confirmed selection is passed as --roles/--hosts to
+// every command until changed.
+func (gui *GUI) selectHosts() {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return
+	}
+	key := hostsKey(dest)
+	servers := dest.Servers()
+	var items []pickerItem
+	if targetLabel(gui.roleSelections[key], gui.hostSelections[key]) != "" {
+		items = append(items, pickerItem{Label: yellow("Clear filter") + dim(" (all roles and hosts)"), Value: ""})
+	}
+	items = append(items, rolePickerItems(servers, gui.roleSelections[key])...)
+	items = append(items, hostPickerItems(servers, gui.hostSelections[key])...)
+	gui.showPicker(&listPicker{
+		Title:    "Target: " + dest.Label(),
+		Items:    items,
+		Multi:    true,
+		Validate: kamal.ValidateHost,
+		OnDone: func(values []string) {
+			roles, hosts, clear := splitTarget(values)
+			if clear {
+				roles, hosts = nil, nil
+			}
+			msg := "Run commands on all roles and hosts?"
+			if flags := targetFlags(roles, hosts); flags != "" {
+				msg = fmt.Sprintf("Run commands with %s?", flags)
+			}
+			gui.prevScreen = gui.screen
+			gui.showConfirm("Target", msg, func() {
+				if len(roles) == 0 {
+					delete(gui.roleSelections, key)
+				} else {
+					gui.roleSelections[key] = roles
+				}
+				if len(hosts) == 0 {
+					delete(gui.hostSelections, key)
+				} else {
+					gui.hostSelections[key] = hosts
+				}
+				if flags := targetFlags(roles, hosts); flags != "" {
+					gui.logInfo("Targeting " + flags)
+				} else {
+					gui.logInfo("Targeting all roles and hosts")
+				}
+			}, nil)
+		},
+	})
+}
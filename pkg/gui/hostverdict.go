@@ -0,0 +1,120 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// hostVerdict renders per-host results as one compact line, e.g.
+// "10.0.1.5 ✓ 42.0s · 10.0.1.7 ✗ see line 214". base is the log position
+// of the first parsed line.
+func hostVerdict(results []kamal.HostResult, base int) string {
+	parts := make([]string, len(results))
+	for i, r := range results {
+		if r.Failed {
+			parts[i] = red(fmt.Sprintf("%s %s see line %d", r.Host, iconError, base+r.Line+1))
+		} else {
+			parts[i] = green(fmt.Sprintf("%s %s %s", r.Host, iconSuccess, formatDuration(r.Duration)))
+		}
+	}
+	return strings.Join(parts, dim(" · "))
+}
+
+// appendLogFromResult logs a command's output. The hosts SSHKit reported
+// errors for follow, and when the output spans several hosts, a per-host
+// verdict, with F jumping to the sections of the hosts that failed.
+func (gui *GUI) appendLogFromResult(r kamal.Result) {
+	raw := r.Lines()
+	gui.logMu.Lock()
+	start := gui.logDropped + len(gui.logLines)
+	gui.logMu.Unlock()
+	gui.appendLog(cleanOutputLines(raw, gui.ansi))
+	gui.appendHostVerdict(raw, start)
+}
+
+// appendHostVerdict adds the host errors and per-host verdict for output
+// lines that were logged from the absolute log position start on.
+func (gui *GUI) appendHostVerdict(raw []string, start int) {
+	plain := make([]string, len(raw))
+	for i, l := range raw {
+		plain[i] = stripANSI(l)
+	}
+	gui.annotateHostErrors(kamal.ParseHostErrors(plain), start)
+	results := kamal.ParseHostResults(plain)
+	if len(results) < 2 {
+		return
+	}
+	var failures []int
+	for _, h := range results {
+		if h.Failed {
+			failures = append(failures, start+h.Line)
+		}
+	}
+	gui.logMu.Lock()
+	line := start - gui.logDropped
+	gui.logMu.Unlock()
+	verdict := "  Hosts: " + hostVerdict(results, line)
+	if len(failures) > 0 {
+		verdict += dim("  (F: jump)")
+	}
+	gui.appendLog([]string{verdict})
+	gui.logMu.Lock()
+	gui.hostFailures, gui.hostFailureNext = failures, 0
+	gui.logMu.Unlock()
+}
+
+// hostErrorLine is the annotation for one host's SSHKit error, e.g.
+// "host 10.0.1.6: Net::SSH::AuthenticationFailed".
+func hostErrorLine(e kamal.HostError) string {
+	return "host " + e.Host + ": " + truncate(e.Message, 120)
+}
+
+// annotateHostErrors colors the error blocks of output logged from the
+// absolute log position start on, those still in the buffer, and appends
+// one line per failing host.
+func (gui *GUI) annotateHostErrors(errs []kamal.HostError, start int) {
+	if len(errs) == 0 {
+		return
+	}
+	lines := make([]string, len(errs))
+	gui.logMu.Lock()
+	for i, e := range errs {
+		for n := start + e.Line; n < start+e.End; n++ {
+			idx := n - gui.logDropped
+			if idx < 0 || idx >= len(gui.logLines) {
+				continue
+			}
+			// Lines already colored (ERROR, redacted) keep their colors.
+			if !strings.Contains(gui.logLines[idx].text, "\x1b") {
+				gui.logLines[idx].text = red(gui.logLines[idx].text)
+			}
+		}
+		lines[i] = red("  " + iconError + " " + hostErrorLine(e))
+	}
+	gui.logMu.Unlock()
+	gui.appendLog(lines)
+}
+
+// keyJumpHostFailure scrolls the Output panel to the next failing host's
+// section from the last multi-host command.
+func (gui *GUI) keyJumpHostFailure(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.logMu.Lock()
+	target := -1
+	if len(gui.hostFailures) > 0 {
+		target, gui.hostFailureNext = nextJump(gui.hostFailures, gui.hostFailureNext, gui.logDropped)
+	}
+	if target >= 0 {
+		gui.logScroll, gui.logPinned = gui.visibleLogIndex(target), true
+	}
+	gui.logMu.Unlock()
+	if target < 0 {
+		gui.logInfo("No failed host in the last multi-host command")
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestHostVerdict(t *testing.T) {
+	results := []kamal.HostResult{
+		{Host: "10.0.1.5", Duration: 42 * time.Second},
+		{Host: "10.0.1.7", Failed: true, Line: 8},
+	}
+	got := hostVerdict(results, 205)
+	for _, want := range []string{"10.0.1.5 " + iconSuccess + " 42.0s", " · ", "10.0.1.7 " + iconError + " see line 214"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("hostVerdict() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestAppendLogFromResultJump(t *testing.T) {
+	gui := &GUI{}
+	gui.appendLog([]string{"Running: App Restart"})
+	gui.appendLogFromResult(kamal.Result{Stdout: "  INFO [aa11] Running docker start on 10.0.1.5\n" +
+		"  INFO [bb22] Running docker start on 10.0.1.6\n" +
+		"  INFO [aa11] Finished in 1.0 seconds with exit status 0 (successful).\n" +
+		" ERROR [bb22] Finished in 1.0 seconds with exit status 1 (failed).\n"})
+	last := gui.logLines[len(gui.logLines)-1].text
+	if !strings.Contains(last, "10.0.1.6 "+iconError+" see line 3") {
+		t.Fatalf("verdict line = %q, want failure at line 3", last)
+	}
+	gui.screen = ScreenApps
+	_ = gui.keyJumpHostFailure(nil, nil)
+	if gui.logScroll != 2 {
+		t.Errorf("logScroll after F = %d, want 2", gui.logScroll)
+	}
+}
+
+func TestAppendLogFromResultHostErrors(t *testing.T) {
+	defer func(c bool) { colorOutput = c }(colorOutput)
+	colorOutput = true
+	gui := &GUI{}
+	gui.appendLogFromResult(kamal.Result{Stdout: "  INFO [aa11] Running docker start on 10.0.1.5\n" +
+		"  INFO [aa11] Finished in 1.0 seconds with exit status 0 (successful).\n" +
+		"  ERROR (SSHKit::Runner::ExecuteError): Exception while executing as root@10.0.1.6: docker exit status: 1\n" +
+		"docker stdout: Nothing written\n" +
+		"docker stderr: Error response from daemon: No such container\n"})
+	var plain []string
+	for _, e := range gui.logLines {
+		plain = append(plain, stripANSI(e.text))
+	}
+	last := plain[len(plain)-1]
+	if !strings.Contains(last, "host 10.0.1.6: Error response from daemon: No such container") {
+		t.Fatalf("annotation = %q", last)
+	}
+	if stderr := gui.logLines[4].text; stderr != red("docker stderr: Error response from daemon: No such container") {
+		t.Errorf("stderr line = %q, want it red", stderr)
+	}
+	if info := gui.logLines[0].text; strings.Contains(info, "\x1b") {
+		t.Errorf("INFO line colored: %q", info)
+	}
+}
@@ -0,0 +1,216 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+)
+
+const viewInput = "input"
+
+// inputMaxLen is how many characters an input dialog accepts unless the
+// caller sets MaxLen.
+const inputMaxLen = 256
+
+// inputState is the one-line text dialog: printable characters are inserted
+// at the cursor, ←/→ move it, Backspace deletes before it. Enter submits the
+// trimmed text (an empty submit does nothing), Esc cancels.
+type inputState struct {
+	Title    string
+	Message  string // shown above the field
+	Value    []rune
+	Cursor   int // rune index into Value
+	MaxLen   int
+	Masked   bool               // each character is shown as *
+	Validate func(string) error // checked on Enter; the error is shown
+	OnSubmit func(string)
+	Err      string
+	prev     Screen
+}
+
+// newInput returns a dialog pre-filled with initial, cursor at the end.
+func newInput(title, message, initial string, onSubmit func(string)) *inputState {
+	in := &inputState{Title: title, Message: message, MaxLen: inputMaxLen, OnSubmit: onSubmit}
+	in.Value = []rune(initial)
+	if len(in.Value) > in.MaxLen {
+		in.Value = in.Value[:in.MaxLen]
+	}
+	in.Cursor = len(in.Value)
+	return in
+}
+
+func (in *inputState) text() string { return string(in.Value) }
+
+// insert adds r at the cursor unless the field is full.
+func (in *inputState) insert(r rune) {
+	if in.MaxLen > 0 && len(in.Value) >= in.MaxLen {
+		in.Err = fmt.Sprintf("at most %d characters", in.MaxLen)
+		return
+	}
+	in.Value = append(in.Value[:in.Cursor], append([]rune{r}, in.Value[in.Cursor:]...)...)
+	in.Cursor++
+	in.Err = ""
+}
+
+// backspace deletes the character before the cursor.
+func (in *inputState) backspace() {
+	if in.Cursor == 0 {
+		return
+	}
+	in.Value = append(in.Value[:in.Cursor-1], in.Value[in.Cursor:]...)
+	in.Cursor--
+	in.Err = ""
+}
+
+func (in *inputState) move(delta int) {
+	in.Cursor += delta
+	if in.Cursor < 0 {
+		in.Cursor = 0
+	}
+	if in.Cursor > len(in.Value) {
+		in.Cursor = len(in.Value)
+	}
+}
+
+// submission returns the trimmed text and whether Enter should close the
+// dialog with it: not when it is empty or fails Validate.
+func (in *inputState) submission() (string, bool) {
+	s := strings.TrimSpace(in.text())
+	if s == "" {
+		return "", false
+	}
+	if in.Validate != nil {
+		if err := in.Validate(s); err != nil {
+			in.Err = err.Error()
+			return "", false
+		}
+	}
+	return s, true
+}
+
+// field renders the value with the cursor, scrolled so the cursor stays
+// within width columns.
+func (in *inputState) field(width int) string {
+	value := in.Value
+	if in.Masked {
+		value = []rune(strings.Repeat("*", len(in.Value)))
+	}
+	start := 0
+	if width > 1 && in.Cursor >= width {
+		start = in.Cursor - width + 1
+	}
+	end := len(value)
+	if width > 0 && end > start+width {
+		end = start + width
+	}
+	before := string(value[start:in.Cursor])
+	at, after := " ", ""
+	if in.Cursor < end {
+		at, after = string(value[in.Cursor]), string(value[in.Cursor+1:end])
+	}
+	if !colorOutput {
+		return before + "_" + strings.TrimSuffix(at, " ") + after
+	}
+	return before + colorReverse + at + colorReset + after
+}
+
+func (gui *GUI) showInput(in *inputState) {
+	in.prev = gui.screen
+	gui.input = in
+	gui.screen = ScreenInput
+}
+
+func (gui *GUI) closeInput() {
+	if gui.input != nil {
+		gui.screen = gui.input.prev
+		if gui.input.Masked {
+			for i := range gui.input.Value {
+				gui.input.Value[i] = 0
+			}
+		}
+	}
+	gui.input = nil
+	gui.g.DeleteView(viewInput)
+	gui.g.SetCurrentView(viewMain)
+}
+
+func (gui *GUI) renderInputDialog(g *gocui.Gui) error {
+	in := gui.input
+	if in == nil {
+		return nil
+	}
+	maxX, maxY := g.Size()
+	width := 64
+	if width > maxX-4 {
+		width = maxX - 4
+	}
+	msgLines := wrapText(glyphs(in.Message), width-3)
+	r := centeredRect(maxX, maxY, width, len(msgLines)+6, 4, 2)
+	if !r.valid() {
+		return nil
+	}
+	if v, err := setView(g, viewInput, r); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = true
+		v.FgColor = gocui.ColorWhite
+	}
+	v, _ := g.View(viewInput)
+	if v == nil {
+		return nil
+	}
+	v.Title = " " + in.Title + " "
+	v.Clear()
+	for _, l := range msgLines {
+		fmt.Fprintln(v, " "+l)
+	}
+	if len(msgLines) > 0 {
+		fmt.Fprintln(v)
+	}
+	fmt.Fprintf(v, " %s %s\n", cyan(iconArrow), in.field(width-6))
+	fmt.Fprintln(v)
+	if in.Err != "" {
+		fmt.Fprintln(v, " "+red(in.Err))
+	} else {
+		fmt.Fprintln(v, dim(glyphs(" Enter: OK  Esc: cancel  ←/→: move")))
+	}
+	g.SetCurrentView(viewInput)
+	return nil
+}
+
+// setInputKeybindings binds the input dialog keys. Like the picker's, they
+// must come after the global bindings, which ignore ScreenInput.
+func (gui *GUI) setInputKeybindings(g *gocui.Gui) {
+	bind := func(key interface{}, fn func(in *inputState)) {
+		_ = g.SetKeybinding(viewInput, key, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+			if gui.input != nil {
+				fn(gui.input)
+			}
+			return nil
+		})
+	}
+	bind(gocui.KeyEsc, func(*inputState) { gui.closeInput() })
+	bind(gocui.KeyEnter, func(in *inputState) {
+		s, ok := in.submission()
+		if !ok {
+			return
+		}
+		gui.closeInput()
+		if in.OnSubmit != nil {
+			in.OnSubmit(s)
+		}
+	})
+	bind(gocui.KeyArrowLeft, func(in *inputState) { in.move(-1) })
+	bind(gocui.KeyArrowRight, func(in *inputState) { in.move(1) })
+	bind(gocui.KeyHome, func(in *inputState) { in.Cursor = 0 })
+	bind(gocui.KeyEnd, func(in *inputState) { in.Cursor = len(in.Value) })
+	bind(gocui.KeyBackspace, func(in *inputState) { in.backspace() })
+	bind(gocui.KeyBackspace2, func(in *inputState) { in.backspace() })
+	bind(gocui.KeySpace, func(in *inputState) { in.insert(' ') })
+	for r := rune(33); r < 127; r++ {
+		r := r
+		bind(r, func(in *inputState) { in.insert(r) })
+	}
+}
@@ -0,0 +1,94 @@
+package gui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestInputEditing(t *testing.T) {
+	in := newInput("Exec", "", "bin/rails", nil)
+	if in.Cursor != len("bin/rails") {
+		t.Fatalf("cursor = %d, want end", in.Cursor)
+	}
+	for _, r := range " c" {
+		in.insert(r)
+	}
+	in.move(-2)
+	in.backspace() // drops the "s" before the space
+	in.insert('S')
+	if got := in.text(); got != "bin/railS c" {
+		t.Errorf("text() = %q", got)
+	}
+	in.move(-100)
+	in.backspace()
+	if in.Cursor != 0 || in.text() != "bin/railS c" {
+		t.Errorf("backspace at start changed the value: %q, cursor %d", in.text(), in.Cursor)
+	}
+	in.move(100)
+	if in.Cursor != len(in.Value) {
+		t.Errorf("move past the end: cursor %d", in.Cursor)
+	}
+}
+
+func TestInputMaxLen(t *testing.T) {
+	in := newInput("Exec", "", "", nil)
+	in.MaxLen = 3
+	for _, r := range "abcd" {
+		in.insert(r)
+	}
+	if in.text() != "abc" || in.Err == "" {
+		t.Errorf("text() = %q, Err = %q; want abc and an error", in.text(), in.Err)
+	}
+	if long := newInput("Exec", "", strings.Repeat("x", inputMaxLen+10), nil); len(long.Value) != inputMaxLen {
+		t.Errorf("initial value not capped: %d", len(long.Value))
+	}
+}
+
+func TestInputSubmission(t *testing.T) {
+	in := newInput("Exec", "", "   ", nil)
+	if _, ok := in.submission(); ok {
+		t.Error("blank submission accepted")
+	}
+	in = newInput("Exec", "", "  whoami ", nil)
+	if s, ok := in.submission(); !ok || s != "whoami" {
+		t.Errorf("submission() = %q, %v", s, ok)
+	}
+	in.Validate = func(string) error { return errors.New("nope") }
+	if _, ok := in.submission(); ok || in.Err != "nope" {
+		t.Errorf("invalid submission: Err = %q", in.Err)
+	}
+}
+
+func TestInputField(t *testing.T) {
+	defer func(c bool) { colorOutput = c }(colorOutput)
+	colorOutput = false
+	in := newInput("Exec", "", "abcdef", nil)
+	if got := in.field(10); got != "abcdef_" {
+		t.Errorf("field() at end = %q", got)
+	}
+	if got := in.field(4); got != "def_" {
+		t.Errorf("field() scrolled = %q", got)
+	}
+	in.Cursor = 1
+	if got := in.field(10); got != "a_bcdef" {
+		t.Errorf("field() mid = %q", got)
+	}
+}
+
+func TestInputFieldMasked(t *testing.T) {
+	defer func(c bool) { colorOutput = c }(colorOutput)
+	colorOutput = false
+	in := newInput("Secret", "", "s3cr3t", nil)
+	in.Masked = true
+	if got := in.field(10); got != "******_" {
+		t.Errorf("field() = %q, want only *", got)
+	}
+	in.Cursor = 2
+	if got := in.field(10); got != "**_****" {
+		t.Errorf("field() mid = %q", got)
+	}
+	if in.text() != "s3cr3t" {
+		t.Errorf("text() = %q, want the typed value", in.text())
+	}
+}
@@ -0,0 +1,235 @@
+package gui
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journalEntry is one mutating action: what ran where, how it was
+// confirmed and how it ended. It is a JSON line in the journal file.
+type journalEntry struct {
+	Time        time.Time `json:"time"`
+	User        string    `json:"user,omitempty"`
+	Project     string    `json:"project"`
+	Action      string    `json:"action"`
+	Destination string    `json:"destination,omitempty"`
+	Roles       []string  `json:"roles,omitempty"`
+	Hosts       []string  `json:"hosts,omitempty"`
+	Commands    []string  `json:"commands,omitempty"` // kamal command lines run
+	Confirm     string    `json:"confirm"`            // e.g. "yes (via y)", "typed staging", "not asked"
+	Outcome     string    `json:"outcome"`            // as in the session summary, or "declined"
+	DurationMs  int64     `json:"duration_ms,omitempty"`
+}
+
+// confirmNotAsked is the Confirm of an action that ran without a dialog.
+const confirmNotAsked = "not asked"
+
+// summary is the entry as a row of the Journal screen.
+func (e journalEntry) summary() string {
+	line := e.Time.Local().Format("15:04:05") + "  " + e.Action
+	if e.Destination != "" {
+		line += "  " + e.Destination
+	}
+	outcome := e.Outcome
+	if e.DurationMs > 0 {
+		outcome += " in " + formatDuration(time.Duration(e.DurationMs)*time.Millisecond)
+	}
+	switch e.Outcome {
+	case "ok":
+		outcome = green(outcome)
+	case "declined", "cancelled":
+		outcome = yellow(outcome)
+	default:
+		outcome = red(outcome)
+	}
+	return line + "  " + outcome + dim("  ["+e.Confirm+"]")
+}
+
+// journal is the append-only record of the session's mutating actions. It
+// keeps this session's entries for the Journal screen and appends every
+// entry to a JSON lines file in the user's cache directory, best effort.
+type journal struct {
+	mu      sync.Mutex
+	path    string
+	entries []journalEntry
+}
+
+// defaultJournalPath is journal.jsonl next to the deploy history, or ""
+// when there is no cache directory.
+func defaultJournalPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "lazykamal", "journal.jsonl")
+}
+
+func newJournal(path string) *journal {
+	return &journal{path: path}
+}
+
+// add records e and appends it to the file. A nil journal records nothing.
+func (j *journal) add(e journalEntry) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, e)
+	if j.path == "" {
+		return nil
+	}
+	data, err := marshalJournal([]journalEntry{e})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (j *journal) snapshot() []journalEntry {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]journalEntry(nil), j.entries...)
+}
+
+// marshalJournal encodes entries as JSON lines.
+func marshalJournal(entries []journalEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// parseJournal decodes JSON lines, skipping blank ones. A malformed line is
+// an error naming its line number.
+func parseJournal(data []byte) ([]journalEntry, error) {
+	var out []journalEntry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		var e journalEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("journal line %d: %w", n, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// journalAction records a mutating action in the current project.
+func (gui *GUI) journalAction(e journalEntry) {
+	e.Project = gui.cwd
+	e.User = os.Getenv("USER")
+	if err := gui.journal.add(e); err != nil {
+		gui.appendLog([]string{statusLine("warning", "Journal: "+err.Error())})
+	}
+}
+
+// journalDeclined records a confirm dialog for a command answered no. Only
+// mutating commands are confirmed in a "Confirm <name>" dialog.
+func (gui *GUI) journalDeclined(d confirmDecision) {
+	name := strings.TrimPrefix(d.Title, "Confirm ")
+	if d.Answer == confirmYes || name == d.Title {
+		return
+	}
+	dest := ""
+	if sel := gui.selectedDestination(); sel != nil {
+		dest = sel.Label()
+	}
+	gui.journalAction(journalEntry{
+		Time:        time.Now(),
+		Action:      name,
+		Destination: dest,
+		Roles:       gui.selectedRoles(),
+		Hosts:       gui.selectedHosts(),
+		Confirm:     d.Answer.String() + " (via " + d.Via + ")",
+		Outcome:     "declined",
+	})
+}
+
+// showJournal lists this session's journal, newest first. Enter shows an
+// entry in full; the first item exports the session as JSON lines.
+func (gui *GUI) showJournal() {
+	entries := gui.journal.snapshot()
+	if len(entries) == 0 {
+		gui.logInfo("Journal: no mutating action yet this session")
+		return
+	}
+	items := []pickerItem{{Label: cyan("Export to file…"), Value: "export"}}
+	texts := make([]string, len(entries))
+	for i, e := range entries {
+		data, _ := json.Marshal(e)
+		texts[i] = string(data)
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		items = append(items, pickerItem{Label: entries[i].summary(), Value: fmt.Sprint(i)})
+	}
+	gui.showPicker(&listPicker{
+		Title:   fmt.Sprintf("Journal (%d)", len(entries)),
+		Message: "Mutating actions this session. Enter shows one in full.",
+		Items:   items,
+		OnDone: func(values []string) {
+			if len(values) != 1 {
+				return
+			}
+			if values[0] == "export" {
+				gui.exportJournal(entries)
+				return
+			}
+			p := newPager(texts)
+			p.pos, _ = strconv.Atoi(values[0])
+			p.title = "Journal entry"
+			gui.pager, gui.pagerPrev, gui.screen = p, gui.screen, ScreenPager
+		},
+	})
+}
+
+// exportJournal asks for a path and writes entries there as JSON lines.
+func (gui *GUI) exportJournal(entries []journalEntry) {
+	name := "lazykamal-journal-" + time.Now().Format("20060102-150405") + ".jsonl"
+	gui.showInput(newInput("Export journal", "Write this session's journal as JSON lines to:", name, func(path string) {
+		data, err := marshalJournal(entries)
+		if err == nil {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(gui.cwd, path)
+			}
+			err = os.WriteFile(path, data, 0o600)
+		}
+		if err != nil {
+			gui.logError("Journal export failed: " + err.Error())
+			return
+		}
+		gui.logSuccess(fmt.Sprintf("Exported %d journal entries to %s", len(entries), path))
+	}))
+}
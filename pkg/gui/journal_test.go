@@ -0,0 +1,86 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalRoundTrip(t *testing.T) {
+	at := time.Date(2026, 3, 4, 10, 11, 12, 0, time.UTC)
+	entries := []journalEntry{
+		{
+			Time: at, User: "ana", Project: "/srv/app", Action: "Deploy", Destination: "myapp (staging)",
+			Roles: []string{"web"}, Hosts: []string{"10.0.0.1"}, Commands: []string{"kamal deploy -d staging --hosts 10.0.0.1"},
+			Confirm: "typed staging", Outcome: "ok", DurationMs: 61000,
+		},
+		{Time: at.Add(time.Minute), Project: "/srv/app", Action: "App Stop", Confirm: "no (dismissed) (via esc)", Outcome: "declined"},
+	}
+	data, err := marshalJournal(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Fatalf("got %d lines, want one per entry:\n%s", n, data)
+	}
+	if !strings.Contains(string(data), `"confirm":"typed staging"`) || strings.Contains(strings.Split(string(data), "\n")[1], "duration_ms") {
+		t.Errorf("unexpected encoding:\n%s", data)
+	}
+	got, err := parseJournal(append(data, '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries) {
+		t.Errorf("round trip = %+v\nwant %+v", got, entries)
+	}
+}
+
+func TestParseJournalBadLine(t *testing.T) {
+	_, err := parseJournal([]byte(`{"action":"Deploy","outcome":"ok"}` + "\nnot json\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("err = %v, want one naming line 2", err)
+	}
+}
+
+func TestJournalAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lazykamal", "journal.jsonl")
+	first := newJournal(path)
+	if err := first.add(journalEntry{Action: "Deploy", Outcome: "ok"}); err != nil {
+		t.Fatal(err)
+	}
+	second := newJournal(path) // a later session
+	if err := second.add(journalEntry{Action: "App Stop", Outcome: "exit 1"}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseJournal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Action != "Deploy" || got[1].Action != "App Stop" {
+		t.Errorf("file holds %+v, want both sessions' entries in order", got)
+	}
+	if s := second.snapshot(); len(s) != 1 {
+		t.Errorf("second session shows %d entries, want only its own", len(s))
+	}
+}
+
+func TestJournalDeclined(t *testing.T) {
+	gui := &GUI{journal: newJournal("")}
+	gui.journalDeclined(confirmDecision{Title: "Confirm App Stop", Answer: confirmDismissed, Via: "esc"})
+	gui.journalDeclined(confirmDecision{Title: "Confirm App Stop", Answer: confirmYes, Via: "y"}) // recorded when it runs
+	gui.journalDeclined(confirmDecision{Title: "Target", Answer: confirmNo, Via: "enter"})        // not a command
+	got := gui.journal.snapshot()
+	if len(got) != 1 {
+		t.Fatalf("journal = %+v, want only the declined App Stop", got)
+	}
+	if got[0].Action != "App Stop" || got[0].Outcome != "declined" || got[0].Confirm != "no (dismissed) (via esc)" {
+		t.Errorf("entry = %+v", got[0])
+	}
+}
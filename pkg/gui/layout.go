@@ -0,0 +1,174 @@
+package gui
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jroimartin/gocui"
+)
+
+const (
+	// maxTermSize bounds believable terminal dimensions. Zero, negative or
+	// larger sizes are transient reports during a resize; the frame is
+	// skipped and the previous one stays on screen.
+	maxTermSize = 10000
+	// minLayoutW and minLayoutH are the smallest size the panels are laid
+	// out for; smaller terminals get the panels clipped, not squashed.
+	minLayoutW = 20
+	minLayoutH = 10
+	// relayoutInterval coalesces re-layout during rapid resizes (~30 Hz).
+	relayoutInterval = 33 * time.Millisecond
+)
+
+// rect is a view's corners as passed to gocui's SetView.
+type rect struct {
+	x0, y0, x1, y1 int
+}
+
+// valid reports whether gocui accepts r: SetView fails unless x1 > x0 and
+// y1 > y0.
+func (r rect) valid() bool {
+	return r.x0 >= 0 && r.y0 >= 0 && r.x1 > r.x0 && r.y1 > r.y0
+}
+
+// setView is g.SetView for r.
+func setView(g *gocui.Gui, name string, r rect) (*gocui.View, error) {
+	return g.SetView(name, r.x0, r.y0, r.x1, r.y1)
+}
+
+// saneSize reports whether a size from g.Size() is worth laying out.
+func saneSize(w, h int) bool {
+	return w > 0 && h > 0 && w <= maxTermSize && h <= maxTermSize
+}
+
+// panelLayout is the four main panels: header, left menu, status and log.
+type panelLayout struct {
+	header, main, status, log rect
+}
+
+func (l panelLayout) valid() bool {
+	return l.header.valid() && l.main.valid() && l.status.valid() && l.log.valid()
+}
+
+// clampLayoutSize raises a terminal size to the minimum the panels need.
+func clampLayoutSize(maxX, maxY int) (int, int) {
+	if maxX < minLayoutW {
+		maxX = minLayoutW
+	}
+	if maxY < minLayoutH {
+		maxY = minLayoutH
+	}
+	return maxX, maxY
+}
+
+// leftWidth is the left panel's width: share of maxX, at least min, and
+// always leaving room for the right panels.
+func leftWidth(maxX, share, min int) int {
+	w := share
+	if w < min {
+		w = min
+	}
+	if w > maxX-2 {
+		w = maxX - 2
+	}
+	return w
+}
+
+// projectLayout places the project-mode panels: a fixed-height status panel
+// above the log on the right, the apps/menu panel (about 40%) on the left.
+func projectLayout(maxX, maxY int) panelLayout {
+	maxX, maxY = clampLayoutSize(maxX, maxY)
+	leftW := leftWidth(maxX, maxX*4/10, 25)
+	statusH := statusLines + 2
+	if statusH > maxY-6 {
+		statusH = maxY - 6
+	}
+	statusY := 3 + statusH
+	return panelLayout{
+		header: rect{0, 0, maxX - 1, 2},
+		main:   rect{0, 3, leftW - 1, maxY - 1},
+		status: rect{leftW, 3, maxX - 1, statusY - 1},
+		log:    rect{leftW, statusY, maxX - 1, maxY - 1},
+	}
+}
+
+// serverLayout places the server-mode panels: the list on the left third,
+// details and logs splitting the right side.
+func serverLayout(maxX, maxY int) panelLayout {
+	maxX, maxY = clampLayoutSize(maxX, maxY)
+	leftW := leftWidth(maxX, maxX/3, 30)
+	statusH := (maxY - 3) / 2
+	return panelLayout{
+		header: rect{0, 0, maxX - 1, 2},
+		main:   rect{0, 3, leftW - 1, maxY - 1},
+		status: rect{leftW, 3, maxX - 1, 3 + statusH},
+		log:    rect{leftW, 4 + statusH, maxX - 1, maxY - 1},
+	}
+}
+
+// centeredRect centers a width x height overlay, shrunk to leave marginX
+// columns and marginY rows free.
+func centeredRect(maxX, maxY, width, height, marginX, marginY int) rect {
+	if width > maxX-marginX {
+		width = maxX - marginX
+	}
+	if height > maxY-marginY {
+		height = maxY - marginY
+	}
+	x0 := (maxX - width) / 2
+	y0 := (maxY - height) / 2
+	return rect{x0, y0, x0 + width, y0 + height}
+}
+
+// resizeDebounce rate-limits re-layout while the terminal size keeps
+// changing, so a drag produces at most ~30 layouts a second.
+type resizeDebounce struct {
+	mu      sync.Mutex
+	w, h    int
+	last    time.Time
+	pending bool
+}
+
+// settle records a layout at size w x h and returns 0, or, when the size
+// changed less than relayoutInterval after the previous layout, how long to
+// wait before laying out again.
+func (d *resizeDebounce) settle(w, h int, now time.Time) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.w != 0 && (w != d.w || h != d.h) {
+		if wait := relayoutInterval - now.Sub(d.last); wait > 0 {
+			return wait
+		}
+	}
+	d.w, d.h, d.last = w, h, now
+	return 0
+}
+
+// retry schedules one redraw after wait, unless one is already scheduled.
+func (d *resizeDebounce) retry(g *gocui.Gui, wait time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.pending {
+		return
+	}
+	d.pending = true
+	time.AfterFunc(wait, func() {
+		d.mu.Lock()
+		d.pending = false
+		d.mu.Unlock()
+		g.Update(func(*gocui.Gui) error { return nil })
+	})
+}
+
+// skipFrame reports whether layout should leave the screen as it is: on a
+// nonsensical size, or while a resize is being coalesced.
+func (d *resizeDebounce) skipFrame(g *gocui.Gui, maxX, maxY int) bool {
+	if !saneSize(maxX, maxY) {
+		return true
+	}
+	if wait := d.settle(maxX, maxY, time.Now()); wait > 0 {
+		d.retry(g, wait)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package gui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLayoutRectsAcrossSizes(t *testing.T) {
+	for w := 1; w <= 320; w++ {
+		for h := 1; h <= 120; h++ {
+			for name, l := range map[string]panelLayout{"project": projectLayout(w, h), "server": serverLayout(w, h)} {
+				if !l.valid() {
+					t.Fatalf("%s layout at %dx%d has an invalid rect: %+v", name, w, h, l)
+				}
+			}
+			for _, r := range []rect{
+				centeredRect(w, h, 60, 28, 4, 4),
+				centeredRect(w, h, 50, 9, 4, 2),
+				centeredRect(w, h, 60, 29, 0, 0),
+			} {
+				if w > 5 && h > 5 && !r.valid() {
+					t.Fatalf("overlay at %dx%d has an invalid rect: %+v", w, h, r)
+				}
+			}
+		}
+	}
+}
+
+func TestSaneSize(t *testing.T) {
+	for _, tt := range []struct {
+		w, h int
+		want bool
+	}{
+		{80, 24, true},
+		{1, 1, true},
+		{0, 24, false},
+		{80, 0, false},
+		{-1, -1, false},
+		{80, 1 << 20, false},
+	} {
+		if got := saneSize(tt.w, tt.h); got != tt.want {
+			t.Errorf("saneSize(%d, %d) = %v, want %v", tt.w, tt.h, got, tt.want)
+		}
+	}
+}
+
+func TestResizeDebounce(t *testing.T) {
+	var d resizeDebounce
+	now := time.Now()
+	if wait := d.settle(80, 24, now); wait != 0 {
+		t.Fatalf("first layout waited %s", wait)
+	}
+	if wait := d.settle(80, 24, now.Add(time.Millisecond)); wait != 0 {
+		t.Errorf("same size waited %s", wait)
+	}
+	if wait := d.settle(81, 24, now.Add(10*time.Millisecond)); wait <= 0 || wait > relayoutInterval {
+		t.Errorf("rapid resize wait = %s, want (0, %s]", wait, relayoutInterval)
+	}
+	if wait := d.settle(82, 25, now.Add(relayoutInterval+time.Millisecond)); wait != 0 {
+		t.Errorf("resize after the interval waited %s", wait)
+	}
+}
@@ -0,0 +1,308 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+)
+
+const viewPicker = "picker"
+
+// pickerItem is one row of a listPicker.
+type pickerItem struct {
+	Label   string // shown to the user
+	Value   string // returned on confirm
+	Checked bool
+}
+
+// listPicker is a generic overlay list. With Multi set, Space toggles items
+// and Enter returns every checked value; otherwise Enter returns the item
+// under the cursor. When Validate is set, 'a' adds a typed value. With Expect
+// set the picker is a typed confirmation: OnDone runs only once the input
+// matches Expect exactly. With Filter set the picker is a live filter: Items
+// are recomputed from the input on every keystroke and Enter returns the
+// input followed by the highlighted value.
+type listPicker struct {
+	Title    string
+	Message  string // shown above the items
+	Expect   string
+	Items    []pickerItem
+	Cursor   int
+	Multi    bool
+	Validate func(string) error
+	Filter   func(input string) []pickerItem
+	OnDone   func(values []string)
+
+	Adding bool   // typing a new value
+	Input  string // value being typed
+	Err    string // last validation error
+	prev   Screen
+}
+
+func (p *listPicker) move(delta int) {
+	p.Cursor += delta
+	if p.Cursor >= len(p.Items) {
+		p.Cursor = len(p.Items) - 1
+	}
+	if p.Cursor < 0 {
+		p.Cursor = 0
+	}
+}
+
+func (p *listPicker) toggle() {
+	if p.Multi && p.Cursor < len(p.Items) {
+		p.Items[p.Cursor].Checked = !p.Items[p.Cursor].Checked
+	}
+}
+
+// selected returns the values the picker confirms with.
+func (p *listPicker) selected() []string {
+	var out []string
+	if !p.Multi {
+		if p.Cursor < len(p.Items) {
+			out = append(out, p.Items[p.Cursor].Value)
+		}
+		return out
+	}
+	for _, it := range p.Items {
+		if it.Checked {
+			out = append(out, it.Value)
+		}
+	}
+	return out
+}
+
+// add validates value and appends it checked, or checks it if already listed.
+func (p *listPicker) add(value string) error {
+	if p.Validate != nil {
+		if err := p.Validate(value); err != nil {
+			return err
+		}
+	}
+	for i, it := range p.Items {
+		if it.Value == value {
+			p.Items[i].Checked = true
+			p.Cursor = i
+			return nil
+		}
+	}
+	p.Items = append(p.Items, pickerItem{Label: value + dim(" (manual)"), Value: value, Checked: true})
+	p.Cursor = len(p.Items) - 1
+	return nil
+}
+
+// refilter recomputes Items from the input in filter mode.
+func (p *listPicker) refilter() {
+	if p.Filter == nil {
+		return
+	}
+	p.Items = p.Filter(p.Input)
+	p.move(0)
+}
+
+// confirmTyped reports whether the typed input matches Expect, setting Err
+// when it does not.
+func (p *listPicker) confirmTyped() bool {
+	if p.Input != p.Expect {
+		p.Err = fmt.Sprintf("type %q to confirm", p.Expect)
+		return false
+	}
+	return true
+}
+
+func (gui *GUI) showPicker(p *listPicker) {
+	p.prev = gui.screen
+	gui.picker = p
+	gui.screen = ScreenPicker
+}
+
+func (gui *GUI) closePicker() {
+	if gui.picker != nil {
+		gui.screen = gui.picker.prev
+	}
+	gui.picker = nil
+	gui.g.DeleteView(viewPicker)
+	gui.g.SetCurrentView(viewMain)
+}
+
+func (gui *GUI) renderPicker(g *gocui.Gui) error {
+	p := gui.picker
+	if p == nil {
+		return nil
+	}
+	maxX, maxY := g.Size()
+	width := 56
+	if width > maxX-4 {
+		width = maxX - 4
+	}
+	msgLines := wrapText(glyphs(p.Message), width-3)
+	r := centeredRect(maxX, maxY, width, len(msgLines)+len(p.Items)+6, 4, 2)
+	if !r.valid() {
+		return nil
+	}
+
+	if v, err := setView(g, viewPicker, r); err != nil {
+		if err != gocui.ErrUnknownView {
+			return err
+		}
+		v.Frame = true
+		v.FgColor = gocui.ColorWhite
+	}
+	v, _ := g.View(viewPicker)
+	if v == nil {
+		return nil
+	}
+	v.Title = " " + p.Title + " "
+	v.Clear()
+
+	for _, l := range msgLines {
+		fmt.Fprintln(v, " "+l)
+	}
+	if len(msgLines) > 0 {
+		fmt.Fprintln(v)
+	}
+	if len(p.Items) == 0 && p.Filter != nil {
+		fmt.Fprintln(v, dim(" No matches."))
+	} else if len(p.Items) == 0 && p.Expect == "" {
+		fmt.Fprintln(v, dim(" Nothing to pick from."))
+	}
+	for i, it := range p.Items {
+		cursor := "  "
+		if i == p.Cursor {
+			cursor = cyan(iconArrow) + " "
+		}
+		box := ""
+		if p.Multi {
+			box = "[ ] "
+			if it.Checked {
+				box = "[" + green("x") + "] "
+			}
+		}
+		fmt.Fprintf(v, "%s%s%s\n", cursor, box, glyphs(it.Label))
+	}
+	fmt.Fprintln(v)
+	switch {
+	case p.Expect != "":
+		fmt.Fprintf(v, " Type %s to confirm: %s_\n", yellow(p.Expect), p.Input)
+		if p.Err != "" {
+			fmt.Fprintln(v, " "+red(p.Err))
+		} else {
+			fmt.Fprintln(v, dim(" Enter: confirm  Esc: cancel"))
+		}
+	case p.Filter != nil:
+		fmt.Fprintf(v, " Filter: %s_\n", p.Input)
+		fmt.Fprintln(v, dim(glyphs(" ↑/↓: move  Enter: apply  Esc: cancel")))
+	case p.Adding:
+		fmt.Fprintf(v, " Add: %s_\n", p.Input)
+		if p.Err != "" {
+			fmt.Fprintln(v, " "+red(p.Err))
+		} else {
+			fmt.Fprintln(v, dim(" Enter: add  Esc: cancel"))
+		}
+	default:
+		if p.Err != "" {
+			fmt.Fprintln(v, " "+red(p.Err))
+		}
+		hint := " Enter: confirm  Esc: cancel"
+		if p.Multi {
+			hint = " Space: toggle" + hint
+		}
+		if p.Validate != nil {
+			hint += "  a: add"
+		}
+		fmt.Fprintln(v, dim(hint))
+	}
+	g.SetCurrentView(viewPicker)
+	return nil
+}
+
+// setPickerKeybindings binds picker keys. It must run after the global
+// bindings so global handlers (which ignore ScreenPicker) fire first.
+func (gui *GUI) setPickerKeybindings(g *gocui.Gui) {
+	bind := func(key interface{}, fn func()) {
+		_ = g.SetKeybinding(viewPicker, key, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+			if gui.picker != nil {
+				fn()
+			}
+			return nil
+		})
+	}
+	bind(gocui.KeyArrowUp, func() { gui.picker.move(-1) })
+	bind(gocui.KeyArrowDown, func() { gui.picker.move(1) })
+	bind(gocui.KeyEsc, func() {
+		if gui.picker.Adding && gui.picker.Expect == "" && gui.picker.Filter == nil {
+			gui.picker.Adding, gui.picker.Input, gui.picker.Err = false, "", ""
+			return
+		}
+		gui.closePicker()
+	})
+	bind(gocui.KeyEnter, func() {
+		p := gui.picker
+		if p.Expect != "" {
+			if !p.confirmTyped() {
+				return
+			}
+			gui.closePicker()
+			if p.OnDone != nil {
+				p.OnDone([]string{p.Input})
+			}
+			return
+		}
+		if p.Filter != nil {
+			values := append([]string{p.Input}, p.selected()...)
+			gui.closePicker()
+			if p.OnDone != nil {
+				p.OnDone(values)
+			}
+			return
+		}
+		if p.Adding {
+			if err := p.add(p.Input); err != nil {
+				p.Err = err.Error()
+				return
+			}
+			p.Adding, p.Input, p.Err = false, "", ""
+			return
+		}
+		values := p.selected()
+		gui.closePicker()
+		if p.OnDone != nil {
+			p.OnDone(values)
+		}
+	})
+	backspace := func() {
+		if p := gui.picker; p.Adding && len(p.Input) > 0 {
+			p.Input = p.Input[:len(p.Input)-1]
+			p.Err = ""
+			p.refilter()
+		}
+	}
+	bind(gocui.KeyBackspace, backspace)
+	bind(gocui.KeyBackspace2, backspace)
+	bind(gocui.KeySpace, func() {
+		if p := gui.picker; p.Adding && p.Filter != nil {
+			p.Input += " "
+			p.refilter()
+		} else if !p.Adding {
+			gui.picker.toggle()
+		}
+	})
+	for r := rune(33); r < 127; r++ {
+		r := r
+		bind(r, func() {
+			p := gui.picker
+			switch {
+			case p.Adding:
+				p.Input += string(r)
+				p.Err = ""
+				p.refilter()
+			case r == 'a' && p.Validate != nil:
+				p.Adding = true
+			case r == 'k':
+				p.move(-1)
+			case r == 'j':
+				p.move(1)
+			}
+		})
+	}
+}
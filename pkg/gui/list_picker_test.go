@@ -0,0 +1,135 @@
+package gui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestListPickerMultiSelect(t *testing.T) {
+	p := &listPicker{
+		Multi: true,
+		Items: []pickerItem{{Value: "a"}, {Value: "b"}, {Value: "c"}},
+	}
+	p.toggle()
+	p.move(2)
+	p.toggle()
+	p.move(5) // clamps at the last item
+	if p.Cursor != 2 {
+		t.Errorf("Cursor = %d, want 2", p.Cursor)
+	}
+	if got := p.selected(); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("selected() = %v, want [a c]", got)
+	}
+	p.move(-10)
+	if p.Cursor != 0 {
+		t.Errorf("Cursor = %d, want 0", p.Cursor)
+	}
+}
+
+func TestListPickerSingleSelect(t *testing.T) {
+	p := &listPicker{Items: []pickerItem{{Value: "a"}, {Value: "b"}}}
+	p.toggle() // no-op without Multi
+	p.move(1)
+	if got := p.selected(); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("selected() = %v, want [b]", got)
+	}
+}
+
+func TestListPickerAdd(t *testing.T) {
+	p := &listPicker{
+		Multi:    true,
+		Items:    []pickerItem{{Value: "10.0.0.1"}},
+		Validate: kamal.ValidateHost,
+	}
+	if err := p.add("10.0.0.1,10.0.0.2"); err == nil {
+		t.Error("add() should reject a comma-separated list")
+	}
+	if err := p.add("10.0.0.1"); err != nil || len(p.Items) != 1 || !p.Items[0].Checked {
+		t.Errorf("add(existing) should check it in place, items = %+v, err = %v", p.Items, err)
+	}
+	if err := p.add("web-2.example.com"); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+	if got := p.selected(); !reflect.DeepEqual(got, []string{"10.0.0.1", "web-2.example.com"}) {
+		t.Errorf("selected() = %v", got)
+	}
+}
+
+func TestHostPickerItems(t *testing.T) {
+	servers := []kamal.ServerHost{
+		{Host: "10.0.0.1", Roles: []string{"job", "web"}},
+		{Host: "10.0.0.2", Roles: []string{"web"}},
+	}
+	items := hostPickerItems(servers, []string{"10.0.0.2", "extra.example.com"})
+	if len(items) != 3 {
+		t.Fatalf("len(items) = %d, want 3", len(items))
+	}
+	if items[0].Checked || !items[1].Checked || !items[2].Checked {
+		t.Errorf("checked = %v/%v/%v, want false/true/true", items[0].Checked, items[1].Checked, items[2].Checked)
+	}
+	if !strings.Contains(items[0].Label, "job, web") {
+		t.Errorf("label %q should show roles", items[0].Label)
+	}
+	if items[2].Value != "extra.example.com" || !strings.Contains(items[2].Label, "manual") {
+		t.Errorf("manual host item = %+v", items[2])
+	}
+}
+
+func TestListPickerTypedConfirm(t *testing.T) {
+	p := &listPicker{Expect: "production", Adding: true}
+	p.Input = "prod"
+	if p.confirmTyped() || !strings.Contains(p.Err, `"production"`) {
+		t.Errorf("confirmTyped(%q) = true or Err %q, want false with hint", p.Input, p.Err)
+	}
+	p.Input = "production"
+	if !p.confirmTyped() {
+		t.Errorf("confirmTyped(%q) = false, want true", p.Input)
+	}
+}
+
+func TestRolePickerItems(t *testing.T) {
+	servers := []kamal.ServerHost{
+		{Host: "10.0.0.1", Roles: []string{"job", "web"}},
+		{Host: "10.0.0.2", Roles: []string{"web"}},
+	}
+	items := rolePickerItems(servers, []string{"web"})
+	if len(items) != 2 || items[0].Value != "role:job" || items[0].Checked || !items[1].Checked {
+		t.Errorf("rolePickerItems() = %+v", items)
+	}
+	if !strings.Contains(stripANSI(items[1].Label), "(2 host(s))") {
+		t.Errorf("web label = %q", items[1].Label)
+	}
+}
+
+func TestSplitTarget(t *testing.T) {
+	roles, hosts, clear := splitTarget([]string{"role:web", "10.0.0.2", "role:job"})
+	if !reflect.DeepEqual(roles, []string{"web", "job"}) || !reflect.DeepEqual(hosts, []string{"10.0.0.2"}) || clear {
+		t.Errorf("splitTarget() = %v, %v, %v", roles, hosts, clear)
+	}
+	if _, _, clear := splitTarget([]string{"", "10.0.0.2"}); !clear {
+		t.Error("splitTarget() with the clear item: clear = false")
+	}
+}
+
+func TestTargetLabel(t *testing.T) {
+	tests := []struct {
+		roles, hosts []string
+		label, flags string
+	}{
+		{[]string{"web"}, []string{"10.0.0.2"}, "web @ 10.0.0.2", "--roles web --hosts 10.0.0.2"},
+		{[]string{"web", "job"}, nil, "web,job", "--roles web,job"},
+		{nil, []string{"a", "b"}, "@ a,b", "--hosts a,b"},
+		{nil, nil, "", ""},
+	}
+	for _, tt := range tests {
+		if got := targetLabel(tt.roles, tt.hosts); got != tt.label {
+			t.Errorf("targetLabel(%v, %v) = %q, want %q", tt.roles, tt.hosts, got, tt.label)
+		}
+		if got := targetFlags(tt.roles, tt.hosts); got != tt.flags {
+			t.Errorf("targetFlags(%v, %v) = %q, want %q", tt.roles, tt.hosts, got, tt.flags)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+const lockReleaseHint = "  Release it from Other > Lock Release once no deploy is running."
+
+// deployLockCommands take Kamal's deploy lock for their whole run, so a
+// cancelled or crashed run can leave it held.
+var deployLockCommands = map[string]bool{
+	"Deploy":              true,
+	"Deploy (skip push)":  true,
+	"Deploy (no cache)":   true,
+	"Redeploy":            true,
+	"Redeploy (no cache)": true,
+	"Setup":               true,
+	"Setup (no cache)":    true,
+}
+
+// offerLockRelease runs after a deploy command was cancelled or failed. When
+// the deploy lock is still held by a lock that deploy took, it offers to
+// release it. It runs in the background like the other post-command checks.
+func (gui *GUI) offerLockRelease(name, why string, dest kamal.DeployDestination, start time.Time) {
+	opts := kamal.RunOpts(gui.cwd, &dest)
+	opts.Runner = gui.runner
+	res, err := kamal.RunKamal([]string{"lock", "status"}, opts)
+	if err != nil || res.ExitCode != 0 {
+		return
+	}
+	lock := kamal.ParseLockStatus(res.Combined())
+	if !lock.LeftBy(kamal.LockUser(gui.cwd), start) {
+		return
+	}
+	gui.g.Update(func(*gocui.Gui) error {
+		gui.cmdMu.Lock()
+		busy := gui.running
+		gui.cmdMu.Unlock()
+		current := gui.selectedDestination()
+		if busy || current == nil || hostsKey(current) != hostsKey(&dest) || gui.screen == ScreenEditor || gui.screen == ScreenConfirm || gui.screen == ScreenPicker || gui.screen == ScreenInput || gui.screen == ScreenPager {
+			gui.appendLog([]string{statusLine("warning", dest.Label()+": "+name+" left the deploy lock held"), dim(lockReleaseHint)})
+			return nil
+		}
+		gui.confirmLockRelease(name, why, lock, opts)
+		return nil
+	})
+}
+
+// confirmLockRelease asks whether to release lock. However the dialog is
+// declined, the lock is reported as left held with how to release it.
+func (gui *GUI) confirmLockRelease(name, why string, lock kamal.LockInfo, opts kamal.RunOptions) {
+	gui.prevScreen = gui.screen
+	gui.showConfirm("Release deploy lock?", lockReleaseMessage(name, why, lock), func() {
+		gui.runCommand("Lock Release", func(stopCh <-chan struct{}) (kamal.Result, error) {
+			opts.OnRun = gui.logArgv
+			return kamal.RunKamalWithStop([]string{"lock", "release"}, opts, stopCh)
+		})
+	}, nil)
+	gui.confirm.OnClose = func(a confirmAnswer) {
+		if a != confirmYes {
+			gui.appendLog([]string{statusLine("warning", "Deploy lock left held"), dim(lockReleaseHint)})
+		}
+	}
+}
+
+func lockReleaseMessage(name, why string, lock kamal.LockInfo) string {
+	msg := fmt.Sprintf("Release the deploy lock left by the %s %s? [y/N]\nLocked by: %s", why, name, lock.Holder)
+	if lock.Version != "" {
+		msg += "\nVersion: " + lock.Version
+	}
+	return msg
+}
+
+// checkStaleLocks looks for deploy locks taken by this user's deploys on
+// every destination, e.g. after lazykamal was killed mid-deploy, and logs a
+// one-line hint for each.
+func (gui *GUI) checkStaleLocks(cwd string, dests []kamal.DeployDestination) {
+	user := kamal.LockUser(cwd)
+	if user == "" {
+		return
+	}
+	for i := range dests {
+		dest := &dests[i]
+		if dest.ConfigErr != nil {
+			continue
+		}
+		opts := kamal.RunOpts(cwd, dest)
+		opts.Runner = gui.runner
+		res, err := kamal.RunKamal([]string{"lock", "status"}, opts)
+		if err != nil || res.ExitCode != 0 {
+			continue
+		}
+		lock := kamal.ParseLockStatus(res.Combined())
+		if !lock.HeldByDeployOf(user) {
+			continue
+		}
+		gui.appendLog([]string{statusLine("warning", staleLockHint(dest.Label(), lock, time.Now()))})
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+	}
+}
+
+func staleLockHint(label string, lock kamal.LockInfo, now time.Time) string {
+	who, at := lock.LockedBy()
+	since := ""
+	if !at.IsZero() {
+		since = " for " + formatDuration(now.Sub(at).Round(time.Second))
+	}
+	return fmt.Sprintf("%s: deploy lock held by %s (a deploy of yours)%s; release it from Other > Lock Release if no deploy is running", label, who, since)
+}
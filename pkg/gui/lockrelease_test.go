@@ -0,0 +1,42 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestLockReleaseMessage(t *testing.T) {
+	lock := kamal.LockInfo{Held: true, Holder: "Jane Doe at 2024-05-01T10:00:05Z", Version: "9f8e7d6c", Message: kamal.DeployLockMessage}
+	got := lockReleaseMessage("Deploy", "cancelled", lock)
+	for _, want := range []string{"left by the cancelled Deploy? [y/N]", "Jane Doe", "Version: 9f8e7d6c"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("lockReleaseMessage() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestStaleLockHint(t *testing.T) {
+	lock := kamal.LockInfo{Held: true, Holder: "Jane Doe at 2024-05-01T10:00:00Z", Message: kamal.DeployLockMessage}
+	got := staleLockHint("staging", lock, time.Date(2024, 5, 1, 13, 30, 0, 0, time.UTC))
+	for _, want := range []string{"staging: deploy lock held by Jane Doe", "for 3h30m", "Lock Release"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("staleLockHint() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestDecliningLockReleaseLeavesAHint(t *testing.T) {
+	lock := kamal.LockInfo{Held: true, Holder: "Jane Doe", Message: kamal.DeployLockMessage}
+	for _, key := range []interface{}{'n', gocui.KeyEsc, gocui.KeyEnter} {
+		gui := newFakeGUI(t, &kamal.FakeRunner{})
+		gui.confirmLockRelease("Deploy", "cancelled", lock, kamal.RunOptions{})
+		pressConfirmKey(t, gui, key) // Enter answers the preselected No
+		if log := plainLog(gui); !strings.Contains(log, "Deploy lock left held") || !strings.Contains(log, "Other > Lock Release") {
+			t.Errorf("%v: log = %q, want the lock reported held with the release hint", key, log)
+		}
+	}
+}
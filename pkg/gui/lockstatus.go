@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// lockRecheck is how often the status poll re-runs `kamal lock status`:
+// about every third poll, with slack for a tick that comes a little early.
+const lockRecheck = 3*statusPoll - statusPoll/2
+
+// touchesLock reports whether command name can take or release the deploy
+// lock.
+func touchesLock(name string) bool {
+	return deployLockCommands[name] || strings.HasPrefix(name, "Lock ") || strings.HasPrefix(name, "Rollback") || name == removeEverythingName
+}
+
+// checkLock runs `kamal lock status` for dest from the status poll when
+// the last check is older than lockRecheck.
+func (gui *GUI) checkLock(dest *kamal.DeployDestination, opts kamal.RunOptions) {
+	key := hostsKey(dest)
+	if !gui.lock.claim(key, time.Now(), lockRecheck) {
+		return
+	}
+	opts.Defaults = nil
+	r, err := kamal.LockStatus(opts)
+	if err != nil || r.ExitCode != 0 {
+		return
+	}
+	gui.lock.set(key, kamal.ParseLockStatus(r.Combined()), time.Now())
+}
+
+// lockHolder is who holds lock and since when, e.g. "alice since 14:02",
+// with the time in zone and the date when it is not today.
+func lockHolder(lock kamal.LockInfo, zone displayZone, now time.Time) string {
+	who, at := lock.LockedBy()
+	if at.IsZero() {
+		return who
+	}
+	suffix := ""
+	if zone == zoneLocal {
+		at, now = at.Local(), now.Local()
+	} else {
+		at, now = at.UTC(), now.UTC()
+		suffix = " UTC"
+	}
+	layout := "15:04"
+	if at.Format("2006-01-02") != now.Format("2006-01-02") {
+		layout = "Jan 2 15:04"
+	}
+	return who + " since " + at.Format(layout) + suffix
+}
+
+// lockStatusLine is the Live status panel's line for a held lock, with its
+// message unless the lock is a deploy's own; "" when the lock is free.
+func lockStatusLine(lock kamal.LockInfo, zone displayZone, now time.Time) string {
+	if !lock.Held {
+		return ""
+	}
+	line := " " + yellow(iconLock+" locked by "+lockHolder(lock, zone, now))
+	if lock.Message != "" && lock.Message != kamal.DeployLockMessage {
+		line += dim(" — " + lock.Message)
+	}
+	return line
+}
+
+// lockStatusFor is the lock line for dest from the last check.
+func (gui *GUI) lockStatusFor(dest *kamal.DeployDestination) string {
+	lock, ok := gui.lock.current(hostsKey(dest))
+	if !ok {
+		return ""
+	}
+	return lockStatusLine(lock, gui.zone, time.Now())
+}
+
+// lockNote warns in name's confirmation that dest's deploy lock is held, so
+// the command will fail until it is released. It is "" for commands that
+// do not take the lock, and when the last check found it free.
+func (gui *GUI) lockNote(dest *kamal.DeployDestination, name string) string {
+	if dest == nil || !deployLockCommands[name] {
+		return ""
+	}
+	lock, ok := gui.lock.current(hostsKey(dest))
+	if !ok || !lock.Held {
+		return ""
+	}
+	note := "The deploy lock is held by " + lockHolder(lock, gui.zone, time.Now())
+	if lock.Message != "" {
+		note += " (" + lock.Message + ")"
+	}
+	return note + "; kamal refuses to " + strings.ToLower(strings.Fields(name)[0]) + " until it is released."
+}
+
+// withNote adds note to a confirmation message on a line of its own.
+func withNote(message, note string) string {
+	if note == "" {
+		return message
+	}
+	return message + "\n" + note
+}
@@ -0,0 +1,84 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestLockCheckedEveryThirdPoll(t *testing.T) {
+	var p probe[kamal.LockInfo]
+	start := time.Now()
+	var got []bool
+	for i := 0; i < 7; i++ {
+		// Ticks drift; one that comes a little early still checks.
+		at := start.Add(time.Duration(i)*statusPoll - 100*time.Millisecond*time.Duration(i%2))
+		got = append(got, p.claim("a", at, lockRecheck))
+	}
+	want := []bool{true, false, false, true, false, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("claims = %v, want %v", got, want)
+		}
+	}
+	if !p.claim("b", start, lockRecheck) {
+		t.Error("first poll of another destination does not check")
+	}
+	p.set("a", kamal.LockInfo{Held: true}, start)
+	p.forget()
+	if _, ok := p.current("a"); ok || !p.claim("a", start, lockRecheck) {
+		t.Error("forget() kept the result or the last check")
+	}
+}
+
+func TestLockStatusLine(t *testing.T) {
+	plainStyle(t)
+	now := time.Date(2024, 5, 1, 16, 0, 0, 0, time.UTC)
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"There is no deploy lock", ""},
+		{"Locked by: alice at 2024-05-01T14:02:00Z\nVersion: 9f8e7d6c\nMessage: Automatic deploy lock", " L locked by alice since 14:02 UTC"},
+		{"Locked by: alice at 2024-04-30T14:02:00Z\nMessage: DB migration", " L locked by alice since Apr 30 14:02 UTC - DB migration"},
+		{"Locked by: CI\nMessage: Manual", " L locked by CI - Manual"},
+	}
+	for _, tt := range tests {
+		got := glyphs(lockStatusLine(kamal.ParseLockStatus(tt.output), zoneUTC, now))
+		if got != tt.want {
+			t.Errorf("lockStatusLine(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestDeployConfirmMentionsLock(t *testing.T) {
+	runner := &kamal.FakeRunner{Respond: func(args []string) ([]string, int) {
+		if args[0] == "lock" {
+			return []string{"Locked by: alice at 2024-05-01T14:02:00Z", "Message: DB migration"}, 0
+		}
+		return kamal.DemoResponse(args)
+	}}
+	gui := newFakeGUI(t, runner)
+	gui.zone = zoneUTC
+	gui.checkLock(gui.selectedDestination(), gui.runOpts())
+
+	gui.screen, gui.submenuIdx = ScreenDeploy, 0
+	gui.execDeploy()
+	if gui.confirm == nil {
+		t.Fatal("Deploy ran without asking while the lock is held")
+	}
+	if !strings.Contains(gui.confirm.Message, "The deploy lock is held by alice since May 1 14:02 UTC (DB migration); kamal refuses to deploy") {
+		t.Errorf("confirm message = %q", gui.confirm.Message)
+	}
+	gui.answerConfirm(confirmNo, "test")
+
+	// Commands that do not take the lock are not held up.
+	gui.screen, gui.submenuIdx = ScreenApp, 8
+	gui.execApp()
+	if gui.confirm != nil {
+		t.Errorf("App Version asked: %q", gui.confirm.Message)
+	}
+	waitIdle(t, gui)
+}
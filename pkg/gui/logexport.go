@@ -0,0 +1,79 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+)
+
+// logExportName is the file Ctrl+S writes the Output log to at now.
+func logExportName(now time.Time) string {
+	return "lazykamal-output-" + now.Format("20060102-150405") + ".log"
+}
+
+// formatLogExport is entries as plain text for a file: timestamps as shown
+// in zone, colors stripped, and secrets masked again in case a line got
+// past the mask on its way in.
+func formatLogExport(entries []logEntry, zone displayZone, skew time.Duration) []byte {
+	var b strings.Builder
+	for _, line := range renderEntries(entries, zone, skew) {
+		b.WriteString(sanitizeLogLine(stripANSI(line)))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+// exportLog writes entries to a new file in dir and returns its path.
+func exportLog(dir string, entries []logEntry, zone displayZone, skew time.Duration) (string, error) {
+	path := filepath.Join(dir, logExportName(time.Now()))
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if err := os.WriteFile(path, formatLogExport(entries, zone, skew), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// keyExportLog writes the whole Output log, destination prefixes included,
+// to a file in the project directory.
+func (gui *GUI) keyExportLog(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	skew := gui.liveSkew()
+	gui.logMu.Lock()
+	entries := make([]logEntry, len(gui.logLines))
+	for i, e := range gui.logLines {
+		entries[i] = gui.tags.label(e)
+	}
+	zone := gui.zone
+	gui.logMu.Unlock()
+	path, err := exportLog(gui.cwd, entries, zone, skew)
+	if err != nil {
+		gui.logError("Output export failed: " + err.Error())
+		return nil
+	}
+	gui.logSuccess(fmt.Sprintf("Wrote %d Output lines to %s", len(entries), path))
+	return nil
+}
+
+// keyExportLog writes the Output log to a file in the current directory.
+func (gui *ServerGUI) keyExportLog(g *gocui.Gui, v *gocui.View) error {
+	skew := gui.skew.current(gui.host)
+	gui.logMu.Lock()
+	entries := append([]logEntry(nil), gui.logLines...)
+	zone := gui.zone
+	gui.logMu.Unlock()
+	path, err := exportLog(".", entries, zone, skew)
+	if err != nil {
+		gui.logError("Output export failed: " + err.Error())
+		return nil
+	}
+	gui.logSuccess(fmt.Sprintf("Wrote %d Output lines to %s", len(entries), path))
+	return nil
+}
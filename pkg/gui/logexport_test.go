@@ -0,0 +1,43 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatLogExport(t *testing.T) {
+	at := time.Date(2026, 10, 1, 9, 12, 3, 0, time.UTC)
+	entries := []logEntry{
+		{at: at, text: green("✓ Deploy completed in 1m2s")},
+		// A line that reached the buffer without going through appendLog.
+		{at: at, text: red("password=hunter2")},
+		{at: at, cleared: true},
+	}
+	got := string(formatLogExport(entries, zoneUTC, 0))
+	want := "09:12:03 ✓ Deploy completed in 1m2s\n09:12:03 password=[REDACTED]\n── cleared at 09:12:03 ──\n"
+	if got != want {
+		t.Errorf("formatLogExport =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestExportLog(t *testing.T) {
+	dir := t.TempDir()
+	path, err := exportLog(dir, []logEntry{newLogEntry("hello")}, zoneUTC, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(filepath.Base(path), "lazykamal-output-") || filepath.Ext(path) != ".log" {
+		t.Errorf("path = %q", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasSuffix(string(data), " hello\n") {
+		t.Errorf("file = %q, %v", data, err)
+	}
+
+	if _, err := exportLog(filepath.Join(dir, "missing"), nil, zoneUTC, 0); err == nil {
+		t.Error("exportLog into a missing directory succeeded")
+	}
+}
@@ -0,0 +1,307 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jroimartin/gocui"
+)
+
+// logSearch is the Output search (/): the term, and which match n/N step
+// from. Only the text of an entry is searched, never its timestamp, so
+// "12:" does not hit every line.
+type logSearch struct {
+	term string
+	at   int // log position (counting logDropped) of the current match
+}
+
+// active reports whether there is a search to highlight and step through.
+func (s *logSearch) active() bool { return s.term != "" }
+
+// entryMatches reports whether e's text contains term, ignoring case and
+// escape codes.
+func entryMatches(e logEntry, term string) bool {
+	if e.cleared || term == "" {
+		return false
+	}
+	return len(matchSpans(stripANSI(e.text), term)) > 0
+}
+
+// matchSpans returns the byte ranges of s that equal term ignoring case,
+// left to right and not overlapping.
+func matchSpans(s, term string) [][2]int {
+	if term == "" {
+		return nil
+	}
+	var spans [][2]int
+	for i := 0; i+len(term) <= len(s); {
+		if strings.EqualFold(s[i:i+len(term)], term) {
+			spans = append(spans, [2]int{i, i + len(term)})
+			i += len(term)
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return spans
+}
+
+// highlightMatches returns text with each match of term shown reversed. A
+// line with a match loses its own colors so the highlight stays readable.
+func highlightMatches(text, term string) string {
+	plain := stripANSI(text)
+	spans := matchSpans(plain, term)
+	if len(spans) == 0 || !colorOutput {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		b.WriteString(plain[last:sp[0]])
+		b.WriteString(colorReverse + plain[sp[0]:sp[1]] + colorReset)
+		last = sp[1]
+	}
+	b.WriteString(plain[last:])
+	return b.String()
+}
+
+// renderSearched renders entries like renderEntries, with the matches of
+// term highlighted.
+func renderSearched(entries []logEntry, zone displayZone, skew time.Duration, term string) []string {
+	lines := renderEntries(entries, zone, skew)
+	if term == "" {
+		return lines
+	}
+	for i, e := range entries {
+		if entryMatches(e, term) {
+			lines[i] = dim(formatStamp(e.at, zone, skew)) + " " + highlightMatches(e.text, term)
+		}
+	}
+	return lines
+}
+
+// stepMatch returns the index into matches of the match after (dir 1) or
+// before (dir -1) log position at, wrapping around the ends. matches are
+// indexes into the log; dropped turns them into positions.
+func stepMatch(matches []int, at, dropped, dir int) int {
+	if dir > 0 {
+		for i, m := range matches {
+			if m+dropped > at {
+				return i
+			}
+		}
+		return 0
+	}
+	for i := len(matches) - 1; i >= 0; i-- {
+		if matches[i]+dropped < at {
+			return i
+		}
+	}
+	return len(matches) - 1
+}
+
+// searchTitle is the Output title tag for a search: the term and which
+// match of how many is current.
+func searchTitle(s *logSearch, matches []int, dropped int) string {
+	if len(matches) == 0 {
+		return fmt.Sprintf("[/%s: no match] ", s.term)
+	}
+	for i, m := range matches {
+		if m+dropped == s.at {
+			return fmt.Sprintf("[/%s %d/%d] ", s.term, i+1, len(matches))
+		}
+	}
+	return fmt.Sprintf("[/%s %d matches] ", s.term, len(matches))
+}
+
+// keySearchLog opens the Output search. On the Apps list / filters the
+// list instead, unless the Output has focus.
+func (gui *GUI) keySearchLog(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() || (gui.screen == ScreenApps && !gui.logFocus) {
+		return nil
+	}
+	gui.logMu.Lock()
+	gui.search = logSearch{}
+	gui.logMu.Unlock()
+	gui.showInput(newInput("Search Output", "Case-insensitive; n/N: next/previous match", "", gui.startLogSearch))
+	return nil
+}
+
+// startLogSearch searches the Output for term and scrolls to the first
+// match.
+func (gui *GUI) startLogSearch(term string) {
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	gui.search = logSearch{term: term, at: -1}
+	if matches := gui.logMatches(); len(matches) > 0 {
+		gui.jumpToMatch(matches, 0)
+	}
+}
+
+// keyLogMatch scrolls the Output to the next (dir 1) or previous (dir -1)
+// match of the search.
+func (gui *GUI) keyLogMatch(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if !gui.navigating() {
+			return nil
+		}
+		gui.logMu.Lock()
+		defer gui.logMu.Unlock()
+		if !gui.search.active() {
+			return nil
+		}
+		if matches := gui.logMatches(); len(matches) > 0 {
+			gui.jumpToMatch(matches, stepMatch(matches, gui.search.at, gui.logDropped, dir))
+		}
+		return nil
+	}
+}
+
+// logMatches lists the indexes into logLines of the shown lines that match
+// the search, tags included. Callers hold logMu.
+func (gui *GUI) logMatches() []int {
+	key, filtered := gui.logFilterKey()
+	var matches []int
+	for i, e := range gui.logLines {
+		if filtered && e.dest != "" && e.dest != key {
+			continue
+		}
+		if entryMatches(gui.tags.label(e), gui.search.term) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// jumpToMatch makes matches[i] the current match and scrolls to it.
+// Callers hold logMu.
+func (gui *GUI) jumpToMatch(matches []int, i int) {
+	gui.search.at = matches[i] + gui.logDropped
+	gui.logScroll, gui.logPinned = gui.visibleLogIndex(matches[i]), true
+}
+
+// typing reports whether keystrokes go to a text field, the export path or
+// the Output search, so global single-letter keys must stand aside.
+func (gui *ServerGUI) typing() bool {
+	return gui.typingExportPath() || gui.searchInput != nil
+}
+
+// keySearchLog opens the search field in the Output title.
+func (gui *ServerGUI) keySearchLog(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.logMu.Lock()
+	gui.search = logSearch{}
+	gui.logMu.Unlock()
+	gui.searchInput = newInput("", "", "", nil)
+	return nil
+}
+
+// startLogSearch searches the Output for term and scrolls to the first
+// match.
+func (gui *ServerGUI) startLogSearch(term string) {
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	gui.search = logSearch{term: term, at: -1}
+	if matches := gui.logMatches(); len(matches) > 0 {
+		gui.search.at = matches[0] + gui.logDropped
+		gui.logScroll = matches[0]
+	}
+}
+
+// keyLogMatch scrolls the Output to the next (dir 1) or previous (dir -1)
+// match of the search.
+func (gui *ServerGUI) keyLogMatch(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(*gocui.Gui, *gocui.View) error {
+		if !gui.navigating() {
+			return nil
+		}
+		gui.logMu.Lock()
+		defer gui.logMu.Unlock()
+		if !gui.search.active() {
+			return nil
+		}
+		if matches := gui.logMatches(); len(matches) > 0 {
+			i := stepMatch(matches, gui.search.at, gui.logDropped, dir)
+			gui.search.at = matches[i] + gui.logDropped
+			gui.logScroll = matches[i]
+		}
+		return nil
+	}
+}
+
+// logMatches lists the indexes into logLines that match the search.
+// Callers hold logMu.
+func (gui *ServerGUI) logMatches() []int {
+	var matches []int
+	for i, e := range gui.logLines {
+		if entryMatches(e, gui.search.term) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// bindLogSearchKeys binds / and n/N, and routes typed characters to the
+// search field while it is open: Enter searches, Esc cancels. The typed
+// characters are bound first so the / that opens the field is not typed
+// into it.
+func (gui *ServerGUI) bindLogSearchKeys(g *gocui.Gui) error {
+	typed := func(r rune) func(*gocui.Gui, *gocui.View) error {
+		return func(*gocui.Gui, *gocui.View) error {
+			if gui.searchInput != nil {
+				gui.searchInput.insert(r)
+			}
+			return nil
+		}
+	}
+	for r := rune(33); r < 127; r++ {
+		if err := g.SetKeybinding(viewMain, r, gocui.ModNone, typed(r)); err != nil {
+			return err
+		}
+	}
+	if err := g.SetKeybinding(viewMain, gocui.KeySpace, gocui.ModNone, typed(' ')); err != nil {
+		return err
+	}
+	backspace := func(*gocui.Gui, *gocui.View) error {
+		if gui.searchInput != nil {
+			gui.searchInput.backspace()
+		}
+		return nil
+	}
+	for _, k := range []gocui.Key{gocui.KeyBackspace, gocui.KeyBackspace2} {
+		if err := g.SetKeybinding(viewMain, k, gocui.ModNone, backspace); err != nil {
+			return err
+		}
+	}
+	if err := g.SetKeybinding(viewMain, gocui.KeyEnter, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+		if in := gui.searchInput; in != nil {
+			gui.searchInput = nil
+			if term := strings.TrimSpace(in.text()); term != "" {
+				gui.startLogSearch(term)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding(viewMain, gocui.KeyEsc, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+		gui.searchInput = nil
+		return nil
+	}); err != nil {
+		return err
+	}
+	for key, h := range map[rune]func(*gocui.Gui, *gocui.View) error{
+		'/': gui.keySearchLog,
+		'n': gui.keyLogMatch(1),
+		'N': gui.keyLogMatch(-1),
+	} {
+		if err := g.SetKeybinding("", key, gocui.ModNone, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package gui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchSpans(t *testing.T) {
+	tests := []struct {
+		s, term string
+		want    [][2]int
+	}{
+		{"Deploy failed", "FAIL", [][2]int{{7, 11}}},
+		{"aaaa", "aa", [][2]int{{0, 2}, {2, 4}}},
+		{"héllo HÉLLO", "héllo", [][2]int{{0, 6}, {7, 13}}},
+		{"nothing here", "deploy", nil},
+		{"anything", "", nil},
+	}
+	for _, tt := range tests {
+		if got := matchSpans(tt.s, tt.term); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("matchSpans(%q, %q) = %v, want %v", tt.s, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestEntryMatchesSkipsTimestamp(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	e := logEntry{at: at, text: red("Deploy failed")}
+	if entryMatches(e, "12:") {
+		t.Error("the timestamp prefix matched")
+	}
+	if !entryMatches(e, "deploy F") {
+		t.Error("a case-insensitive match across colors was missed")
+	}
+	if entryMatches(logEntry{at: at, cleared: true}, "cleared") {
+		t.Error("the cleared marker matched")
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	got := highlightMatches(green("Deploy ok, deploy done"), "DEPLOY")
+	want := colorReverse + "Deploy" + colorReset + " ok, " + colorReverse + "deploy" + colorReset + " done"
+	if got != want {
+		t.Errorf("highlightMatches = %q, want %q", got, want)
+	}
+	if got := highlightMatches(green("no match"), "x"); got != green("no match") {
+		t.Errorf("a line without a match changed: %q", got)
+	}
+
+	defer func(c bool) { colorOutput = c }(colorOutput)
+	colorOutput = false
+	if got := highlightMatches("Deploy ok", "deploy"); got != "Deploy ok" {
+		t.Errorf("without colors = %q", got)
+	}
+}
+
+func TestStepMatch(t *testing.T) {
+	matches := []int{3, 8, 20}
+	tests := []struct {
+		name             string
+		at, dropped, dir int
+		want             int
+	}{
+		{"first from the start", -1, 0, 1, 0},
+		{"next", 3, 0, 1, 1},
+		{"next wraps", 20, 0, 1, 0},
+		{"previous", 8, 0, -1, 0},
+		{"previous wraps", 3, 0, -1, 2},
+		{"positions count dropped lines", 13, 5, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stepMatch(matches, tt.at, tt.dropped, tt.dir); got != tt.want {
+				t.Errorf("stepMatch(%v, %d, %d, %d) = %d, want %d", matches, tt.at, tt.dropped, tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogSearchProject(t *testing.T) {
+	gui := &GUI{}
+	for i := 0; i < 30; i++ {
+		line := fmt.Sprintf("line %d", i)
+		if i%10 == 5 {
+			line = fmt.Sprintf("ERROR at step %d", i)
+		}
+		gui.appendLog([]string{line})
+	}
+	gui.startLogSearch("error")
+	if gui.logScroll != 5 {
+		t.Fatalf("logScroll = %d after the search, want the first match (5)", gui.logScroll)
+	}
+	steps := []struct {
+		dir  int
+		want int
+	}{{1, 15}, {1, 25}, {1, 5}, {-1, 25}, {-1, 15}}
+	for _, s := range steps {
+		_ = gui.keyLogMatch(s.dir)(nil, nil)
+		if gui.logScroll != s.want {
+			t.Errorf("after %+d logScroll = %d, want %d", s.dir, gui.logScroll, s.want)
+		}
+	}
+	if got := searchTitle(&gui.search, gui.logMatches(), gui.logDropped); got != "[/error 2/3] " {
+		t.Errorf("searchTitle = %q", got)
+	}
+
+	lines := renderSearched(gui.logLines, zoneLocal, 0, gui.search.term)
+	if !strings.Contains(lines[15], colorReverse+"ERROR"+colorReset) || strings.Contains(lines[14], colorReverse) {
+		t.Errorf("highlighting: %q, %q", lines[14], lines[15])
+	}
+
+	_ = gui.keyClearLog(nil, nil)
+	if gui.search.active() {
+		t.Error("clearing the log kept the search")
+	}
+	_ = gui.keyLogMatch(1)(nil, nil)
+	if gui.logScroll != 0 {
+		t.Errorf("n without a search scrolled to %d", gui.logScroll)
+	}
+}
+
+func TestLogSearchProjectSlash(t *testing.T) {
+	gui := &GUI{screen: ScreenApps}
+	gui.search = logSearch{term: "old"}
+	_ = gui.keySearchLog(nil, nil)
+	if gui.input != nil || !gui.search.active() {
+		t.Fatal("/ on the Apps list searched the Output instead of filtering")
+	}
+	gui.logFocus = true
+	_ = gui.keySearchLog(nil, nil)
+	if gui.input == nil || gui.screen != ScreenInput {
+		t.Fatal("/ with the Output focused did not open the search")
+	}
+	if gui.search.active() {
+		t.Error("a new search kept the previous term")
+	}
+}
+
+func TestLogSearchServer(t *testing.T) {
+	gui := &ServerGUI{}
+	for i := 0; i < 12; i++ {
+		gui.appendLog([]string{fmt.Sprintf("web-%d restarted", i%3)})
+	}
+	_ = gui.keySearchLog(nil, nil)
+	if !gui.typing() || gui.navigating() {
+		t.Fatal("the search field did not take the keys")
+	}
+	for _, r := range "WEB-2" {
+		gui.searchInput.insert(r)
+	}
+	term := strings.TrimSpace(gui.searchInput.text())
+	gui.searchInput = nil
+	gui.startLogSearch(term)
+	if gui.logScroll != 2 {
+		t.Fatalf("logScroll = %d, want the first match (2)", gui.logScroll)
+	}
+	_ = gui.keyLogMatch(1)(nil, nil)
+	_ = gui.keyLogMatch(1)(nil, nil)
+	if gui.logScroll != 8 {
+		t.Errorf("after n n logScroll = %d, want 8", gui.logScroll)
+	}
+	_ = gui.keyLogMatch(-1)(nil, nil)
+	if gui.logScroll != 5 {
+		t.Errorf("after N logScroll = %d, want 5", gui.logScroll)
+	}
+	gui.clearLog()
+	if gui.search.active() {
+		t.Error("clearing the log kept the search")
+	}
+}
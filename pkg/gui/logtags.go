@@ -0,0 +1,184 @@
+package gui
+
+import (
+	"sync"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// logTagColors color the destination prefixes of Output lines. Red, green
+// and yellow are left to status lines. Destinations get them in order of
+// first use, so a destination keeps its color for the session.
+var logTagColors = []string{
+	colorCyan, colorMagenta, colorBlue, colorWhite,
+	colorBold + colorCyan, colorBold + colorMagenta, colorBold + colorBlue, colorBold + colorWhite,
+}
+
+// logTagAbbrev shortens common destination names for the prefix.
+var logTagAbbrev = map[string]string{
+	"production":  "prod",
+	"staging":     "stg",
+	"development": "dev",
+}
+
+// logTagMax caps the length of a prefix name.
+const logTagMax = 8
+
+// logTagName is dest's short name in Output prefixes: the destination
+// name, abbreviated when common, or the service for the base config.
+func logTagName(dest *kamal.DeployDestination) string {
+	name := dest.Name
+	if name == "" {
+		name = dest.Service
+	}
+	if short, ok := logTagAbbrev[name]; ok {
+		return short
+	}
+	if r := []rune(name); len(r) > logTagMax {
+		return string(r[:logTagMax])
+	}
+	return name
+}
+
+// logTags knows the destinations Output lines were tagged with, by
+// hostsKey, in order of first use.
+type logTags struct {
+	mu    sync.Mutex
+	order []string
+	names map[string]string
+}
+
+func newLogTags() *logTags {
+	return &logTags{names: map[string]string{}}
+}
+
+// tag registers dest and returns the key its lines are tagged with, or ""
+// for nil (general lines).
+func (t *logTags) tag(dest *kamal.DeployDestination) string {
+	if t == nil || dest == nil {
+		return ""
+	}
+	key := hostsKey(dest)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.names[key]; !ok {
+		t.order = append(t.order, key)
+	}
+	t.names[key] = logTagName(dest)
+	return key
+}
+
+// prefix is the colored "[stg]" prefix of lines tagged key. It is empty
+// for general lines and while only one destination has tagged lines, when
+// there is nothing to tell apart.
+func (t *logTags) prefix(key string) string {
+	if t == nil || key == "" {
+		return ""
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.order) < 2 {
+		return ""
+	}
+	for i, k := range t.order {
+		if k == key {
+			return colorize("["+t.names[key]+"]", logTagColors[i%len(logTagColors)])
+		}
+	}
+	return ""
+}
+
+// label returns e with its destination prefix in front of the text.
+func (t *logTags) label(e logEntry) logEntry {
+	if p := t.prefix(e.dest); p != "" && !e.cleared {
+		e.text = p + " " + e.text
+	}
+	return e
+}
+
+// filterLogEntries keeps the general lines and those tagged key.
+func filterLogEntries(entries []logEntry, key string) []logEntry {
+	out := make([]logEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.dest == "" || e.dest == key {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// logFilterKey is the tag of the selected destination when D shows only
+// its lines. Callers hold logMu.
+func (gui *GUI) logFilterKey() (key string, on bool) {
+	if !gui.logDestOnly {
+		return "", false
+	}
+	if dest := gui.selectedDestination(); dest != nil {
+		key = hostsKey(dest)
+	}
+	return key, true
+}
+
+// visibleLog is the Output buffer as shown, filtered when D is on. Callers
+// hold logMu.
+func (gui *GUI) visibleLog() []logEntry {
+	return gui.visibleEntries(gui.logLines)
+}
+
+// visibleEntries keeps those of entries visibleLog would show. Callers
+// hold logMu.
+func (gui *GUI) visibleEntries(entries []logEntry) []logEntry {
+	if key, on := gui.logFilterKey(); on {
+		return filterLogEntries(entries, key)
+	}
+	return entries
+}
+
+// visibleLogIndex maps an index into logLines to the row visibleLog shows
+// it at, or the row before when it is filtered out. Callers hold logMu.
+func (gui *GUI) visibleLogIndex(i int) int {
+	key, on := gui.logFilterKey()
+	if !on {
+		return i
+	}
+	row := -1
+	for j := 0; j <= i && j < len(gui.logLines); j++ {
+		if e := gui.logLines[j]; e.dest == "" || e.dest == key {
+			row++
+		}
+	}
+	if row < 0 {
+		return 0
+	}
+	return row
+}
+
+// appendLogFor is appendLog for lines that came from dest, such as its
+// live logs or status poll, whichever command runs meanwhile.
+func (gui *GUI) appendLogFor(dest *kamal.DeployDestination, lines []string) {
+	gui.appendLogTagged(gui.tags.tag(dest), lines)
+}
+
+// keyDestFilter toggles showing only the selected destination's lines (and
+// general ones) in the Output panel.
+func (gui *GUI) keyDestFilter(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	dest := gui.selectedDestination()
+	gui.logMu.Lock()
+	on := !gui.logDestOnly && dest != nil
+	gui.logDestOnly = on
+	gui.logScroll, gui.logPinned = 0, false
+	gui.logMu.Unlock()
+	switch {
+	case on:
+		gui.logInfo("Output: only " + dest.Label() + " (D: all destinations)")
+	case dest == nil:
+		gui.logInfo("Output: no app selected to filter by")
+	default:
+		gui.logInfo("Output: all destinations")
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestLogTagName(t *testing.T) {
+	tests := []struct {
+		dest kamal.DeployDestination
+		want string
+	}{
+		{kamal.DeployDestination{Name: "staging", Service: "myapp"}, "stg"},
+		{kamal.DeployDestination{Name: "production", Service: "myapp"}, "prod"},
+		{kamal.DeployDestination{Name: "eu-west", Service: "myapp"}, "eu-west"},
+		{kamal.DeployDestination{Name: "customer-acme", Service: "myapp"}, "customer"},
+		{kamal.DeployDestination{Service: "myapp"}, "myapp"},
+	}
+	for _, tt := range tests {
+		if got := logTagName(&tt.dest); got != tt.want {
+			t.Errorf("logTagName(%q) = %q, want %q", tt.dest.Name, got, tt.want)
+		}
+	}
+}
+
+func TestLogTagsPrefix(t *testing.T) {
+	staging := &kamal.DeployDestination{Name: "staging", ConfigPath: "config/deploy.staging.yml"}
+	production := &kamal.DeployDestination{Name: "production", ConfigPath: "config/deploy.production.yml"}
+	tags := newLogTags()
+
+	stg := tags.tag(staging)
+	if p := tags.prefix(stg); p != "" {
+		t.Errorf("prefix with one destination = %q, want none", p)
+	}
+	prod := tags.tag(production)
+	first := tags.prefix(stg)
+	if first != colorize("[stg]", logTagColors[0]) {
+		t.Errorf("staging prefix = %q", first)
+	}
+	if got := tags.prefix(prod); got != colorize("[prod]", logTagColors[1]) {
+		t.Errorf("production prefix = %q", got)
+	}
+	tags.tag(staging)
+	if got := tags.prefix(stg); got != first {
+		t.Errorf("staging prefix changed to %q after tagging again", got)
+	}
+	if got := tags.prefix(""); got != "" {
+		t.Errorf("general line prefix = %q, want none", got)
+	}
+	if got := tags.tag(nil); got != "" {
+		t.Errorf("tag(nil) = %q, want \"\"", got)
+	}
+}
+
+func TestDestinationFilter(t *testing.T) {
+	gui := &GUI{
+		tags: newLogTags(),
+		destinations: []kamal.DeployDestination{
+			{Name: "staging", Service: "myapp", ConfigPath: "config/deploy.staging.yml"},
+			{Name: "production", Service: "myapp", ConfigPath: "config/deploy.production.yml"},
+		},
+		screen: ScreenApps,
+	}
+	gui.appendLog([]string{"general"})
+	gui.appendLogFor(&gui.destinations[0], []string{"staging 1"})
+	gui.appendLogFor(&gui.destinations[1], []string{"production 1"})
+	gui.cmdTag = gui.tags.tag(&gui.destinations[1])
+	gui.appendLog([]string{"production command"})
+	gui.cmdTag = ""
+	gui.appendLogFor(&gui.destinations[0], []string{"staging 2"})
+
+	_ = gui.keyDestFilter(nil, nil)
+	if !gui.logDestOnly {
+		t.Fatal("D did not turn the filter on")
+	}
+	var texts []string
+	for _, e := range gui.visibleLog() {
+		texts = append(texts, e.text)
+	}
+	// The filter's own status line is general, so it stays visible.
+	if got := strings.Join(texts[:3], "|"); got != "general|staging 1|staging 2" {
+		t.Errorf("filtered lines = %q", texts)
+	}
+	if got := gui.visibleLogIndex(3); got != 1 {
+		t.Errorf("visibleLogIndex of a filtered-out line = %d, want 1 (the line before)", got)
+	}
+	if got := gui.tags.label(gui.logLines[1]).text; !strings.Contains(got, "[stg]") {
+		t.Errorf("labelled staging line = %q, want [stg] prefix", got)
+	}
+
+	gui.selectedApp = 1
+	texts = nil
+	for _, e := range gui.visibleLog() {
+		texts = append(texts, e.text)
+	}
+	if got := strings.Join(texts[:3], "|"); got != "general|production 1|production command" {
+		t.Errorf("filtered lines after selecting production = %q", texts)
+	}
+
+	_ = gui.keyDestFilter(nil, nil)
+	if gui.logDestOnly || len(gui.visibleLog()) != len(gui.logLines) {
+		t.Error("second D did not show all destinations")
+	}
+}
@@ -0,0 +1,98 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultMaxLine = 8 << 10 // bytes kept per Output line
+	longLinesKeep  = 32      // untruncated lines kept for the pager
+	longLinesBytes = 8 << 20 // and their total size
+)
+
+// maxLineFromEnv reads LAZYKAMAL_MAX_LINE, the number of bytes of an
+// Output line to keep, e.g. "8192" or "16K"; 0 keeps lines whole.
+func maxLineFromEnv() int {
+	v := strings.TrimSpace(os.Getenv("LAZYKAMAL_MAX_LINE"))
+	if v == "" {
+		return defaultMaxLine
+	}
+	mult := 1
+	if u := strings.ToUpper(v); strings.HasSuffix(u, "K") || strings.HasSuffix(u, "KB") {
+		v, mult = strings.TrimRight(u, "KB"), 1<<10
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return defaultMaxLine
+	}
+	return n * mult
+}
+
+// truncateLine cuts s to at most max bytes, backing off so the cut does not
+// split a UTF-8 rune or an escape sequence. It returns the kept prefix and
+// the number of bytes cut.
+func truncateLine(s string, max int) (string, int) {
+	if max <= 0 || len(s) <= max {
+		return s, 0
+	}
+	cut := max
+	if esc := strings.LastIndexByte(s[:cut], '\x1b'); esc >= 0 {
+		loc := ansiSequence.FindStringIndex(s[esc:])
+		if loc == nil || loc[0] != 0 || esc+loc[1] > cut {
+			cut = esc
+		}
+	}
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut], len(s) - cut
+}
+
+// longLines keeps Output lines within the line cap and the untruncated text
+// of the lines it cut, newest last, for the pager. Callers hold the log lock.
+type longLines struct {
+	max   int    // bytes kept per line; 0 keeps lines whole
+	key   string // key that opens the pager, named in the suffix
+	texts []string
+	size  int
+}
+
+func newLongLines(key string) *longLines {
+	return &longLines{max: maxLineFromEnv(), key: key}
+}
+
+// entry returns the log entry for line, truncated when it is over the cap.
+func (l *longLines) entry(line string) logEntry {
+	if l == nil {
+		return newLogEntry(line)
+	}
+	short, cut := truncateLine(line, l.max)
+	if cut == 0 {
+		return newLogEntry(line)
+	}
+	l.add(line)
+	if strings.Contains(short, "\x1b") {
+		short += "\x1b[0m"
+	}
+	return newLogEntry(short + dim(glyphs(fmt.Sprintf(" (+%s truncated — press %s to expand into pager)", formatBytes(int64(cut)), l.key))))
+}
+
+func (l *longLines) add(text string) {
+	l.texts = append(l.texts, text)
+	l.size += len(text)
+	for len(l.texts) > 1 && (len(l.texts) > longLinesKeep || l.size > longLinesBytes) {
+		l.size -= len(l.texts[0])
+		l.texts = l.texts[1:]
+	}
+}
+
+func (l *longLines) reset() {
+	if l == nil {
+		return
+	}
+	l.texts, l.size = nil, 0
+}
@@ -0,0 +1,108 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateLineRunes(t *testing.T) {
+	line := strings.Repeat("é", 10) // 2 bytes each
+	got, cut := truncateLine(line, 5)
+	if got != "éé" || cut != 16 {
+		t.Errorf("truncateLine() = %q, %d; want two runes and 16 bytes cut", got, cut)
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("truncateLine() split a rune: %q", got)
+	}
+
+	if got, cut := truncateLine("short", 8); got != "short" || cut != 0 {
+		t.Errorf("truncateLine(short) = %q, %d", got, cut)
+	}
+	if got, cut := truncateLine(strings.Repeat("x", 20), 0); cut != 0 || len(got) != 20 {
+		t.Errorf("truncateLine with max 0 cut %d bytes", cut)
+	}
+}
+
+func TestTruncateLineANSI(t *testing.T) {
+	line := "abc\x1b[31mred\x1b[0m" + strings.Repeat("z", 20)
+	tests := []struct {
+		max  int
+		want string
+	}{
+		{5, "abc"},                    // inside "\x1b[31m"
+		{8, "abc\x1b[31m"},            // right after it
+		{12, "abc\x1b[31mred"},        // inside "\x1b[0m"
+		{15, "abc\x1b[31mred\x1b[0m"}, // after the reset
+	}
+	for _, tt := range tests {
+		if got, _ := truncateLine(line, tt.max); got != tt.want {
+			t.Errorf("truncateLine(max %d) = %q, want %q", tt.max, got, tt.want)
+		}
+	}
+
+	// An OSC hyperlink longer than the cap is dropped whole.
+	link := "see \x1b]8;;https://example.com/" + strings.Repeat("p", 50) + "\x1b\\here\x1b]8;;\x1b\\"
+	if got, _ := truncateLine(link, 20); got != "see " {
+		t.Errorf("truncateLine(OSC) = %q, want %q", got, "see ")
+	}
+}
+
+func TestLongLinesEntry(t *testing.T) {
+	l := &longLines{max: 8, key: "x"}
+	short := l.entry("12345678")
+	if short.text != "12345678" || len(l.texts) != 0 {
+		t.Fatalf("line at the cap was truncated: %q", short.text)
+	}
+	long := l.entry("0123456789abcdef" + strings.Repeat("y", 2048))
+	if !strings.HasPrefix(long.text, "01234567") || !strings.Contains(long.text, "(+2.0 KB truncated — press x to expand into pager)") {
+		t.Errorf("entry() = %q", long.text)
+	}
+	if len(l.texts) != 1 || len(l.texts[0]) != 2064 {
+		t.Errorf("full text not kept: %d texts", len(l.texts))
+	}
+	for i := 0; i < longLinesKeep+5; i++ {
+		l.entry(strings.Repeat("q", 16))
+	}
+	if len(l.texts) != longLinesKeep {
+		t.Errorf("kept %d long lines, want %d", len(l.texts), longLinesKeep)
+	}
+}
+
+func TestMaxLineFromEnv(t *testing.T) {
+	for v, want := range map[string]int{"": defaultMaxLine, "4096": 4096, "16K": 16 << 10, "16kb": 16 << 10, "0": 0, "lots": defaultMaxLine} {
+		t.Setenv("LAZYKAMAL_MAX_LINE", v)
+		if got := maxLineFromEnv(); got != want {
+			t.Errorf("LAZYKAMAL_MAX_LINE=%q: maxLineFromEnv() = %d, want %d", v, got, want)
+		}
+	}
+}
+
+func TestPager(t *testing.T) {
+	if newPager(nil) != nil {
+		t.Fatal("newPager(nil) should be nil")
+	}
+	p := newPager([]string{"first", `{"a":1,"b":[1,2]}`})
+	p.layout(80)
+	if p.pos != 1 || len(p.lines) < 4 || p.lines[1] != `  "a": 1,` {
+		t.Errorf("JSON not indented: %q", p.lines)
+	}
+	p.show(-1)
+	p.layout(3)
+	if strings.Join(p.lines, "|") != "fir|st" {
+		t.Errorf("wrapped = %q", p.lines)
+	}
+	p.page = 1
+	p.scroll(10)
+	if p.top != 1 {
+		t.Errorf("scroll past the end: top = %d, want 1", p.top)
+	}
+}
+
+func TestWrapRunes(t *testing.T) {
+	got := wrapRunes("äöüß\nab", 3)
+	want := []string{"äöü", "ß", "ab"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapRunes() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,252 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jroimartin/gocui"
+)
+
+// menuItem is one row of a project-mode menu. Desc and Cmd are shown under
+// the menu as the selection moves, so users know what runs before it runs.
+// Cmd is empty for items that open a submenu. Short replaces Label when the
+// panel is too narrow for the menu; it is empty when Label is short enough.
+// ReadOnly marks items that only inspect state: they skip the typed
+// confirmation of protected destinations, are not journaled, and no status
+// poll follows them as it follows a mutating command.
+type menuItem struct {
+	Label    string
+	Desc     string
+	Cmd      string
+	Short    string
+	ReadOnly bool
+}
+
+// menus is the single source of truth for menu labels and explanations. The
+// exec* functions dispatch on the same indexes.
+var menus = map[Screen][]menuItem{
+	ScreenMainMenu: {
+		{"Deploy / Redeploy / Rollback", "Ship a new version, redeploy the current one, or roll back.", "", "Deploy", false},
+		{"App (boot, start, stop, logs…)", "Manage the app containers on every host.", "", "App", false},
+		{"Server (bootstrap, exec)", "Prepare hosts and run one-off commands on them.", "", "Server", false},
+		{"Accessory (boot, logs, reboot)", "Manage databases, caches and other accessories.", "", "Accessory", false},
+		{"Proxy (boot, logs, reboot)", "Manage kamal-proxy, which routes traffic to the app.", "", "Proxy", false},
+		{"Other (prune, config, lock…)", "Pruning, builds, locks, registry, secrets and more.", "", "Other", false},
+		{"Config (edit deploy.yml, secrets, restart)", "Edit the deploy config and secrets in the TUI.", "", "Config", false},
+		{"Connect to server →", "Open server mode on one of this destination's hosts; quitting it returns here.", "", "Connect →", false},
+		{"History", "Commands run in this project, newest first, with how they ended.", "", "", false},
+	},
+	ScreenDeploy: {
+		{"Deploy", "Build and push the image, then boot it on every host with zero downtime.", "kamal deploy", "", false},
+		{"Deploy (skip push)", "Deploy an image that is already in the registry.", "kamal deploy --skip-push", "", false},
+		{"Redeploy", "Deploy without bootstrapping servers or booting the proxy and accessories.", "kamal redeploy", "", false},
+		{"Rollback", "Boot the containers of a previous version again.", "kamal rollback [VERSION]", "", false},
+		{"Setup (first-time)", "Install Docker, boot accessories and proxy, then deploy.", "kamal setup", "", false},
+		{"Deploy (no cache)", "Deploy with a clean image build.", "kamal deploy --no-cache", "", false},
+		{"Redeploy (no cache)", "Redeploy with a clean image build.", "kamal redeploy --no-cache", "", false},
+		{"Setup (no cache)", "First-time setup with a clean image build.", "kamal setup --no-cache", "", false},
+		{"Observe deploy (read-only)", "Follow a deploy started elsewhere (e.g. CI) without touching the lock.", "kamal lock status / audit / app version / app logs", "Observe deploy", true},
+	},
+	ScreenApp: {
+		{"Boot", "Start a container for the current version, replacing the running one.", "kamal app boot", "", false},
+		{"Start", "Start the existing app containers.", "kamal app start", "", false},
+		{"Stop", "Stop the app containers; the app goes down.", "kamal app stop", "", false},
+		{"Restart", "Restart the app containers in place.", "kamal app restart", "", false},
+		{"Logs", "Show recent app container logs.", "kamal app logs", "", true},
+		{"Containers", "List app containers on every host.", "kamal app containers", "", true},
+		{"Details", "Show the running app containers.", "kamal app details", "", true},
+		{"Images", "List app images on every host.", "kamal app images", "", true},
+		{"Version", "Show the version running on each host.", "kamal app version", "", true},
+		{"Stale containers", "List app containers left over from older versions.", "kamal app stale_containers", "", true},
+		{"Exec (command)", "Prompt for a command and run it in a new container of the current version.", "kamal app exec <command>", "", false},
+		{"Maintenance", "Have the proxy serve a maintenance page instead of the app.", "kamal app maintenance", "", false},
+		{"Live", "Take the app out of maintenance mode.", "kamal app live", "", false},
+		{"Remove", "Remove app containers and images from every host.", "kamal app remove", "", false},
+		{"Live: App logs (stream)", "Stream app logs into the Output panel until Esc.", "kamal app logs (streamed)", "Stream logs", true},
+		{"Stop & remove stale", "List stale containers, confirm, then stop and remove them.", "kamal app stale_containers --stop, then kamal app remove_container VERSION", "", false},
+		{"Exec: whoami (detach)", "Run whoami in a detached container.", "kamal app exec --detach whoami", "Exec whoami", false},
+	},
+	ScreenServer: {
+		{"Bootstrap", "Install Docker and create the Kamal directories on each host.", "kamal server bootstrap", "", false},
+		{"Exec: date", "Print the date on every host.", "kamal server exec date", "", true},
+		{"Exec: uptime", "Print uptime and load on every host.", "kamal server exec uptime", "", true},
+	},
+	ScreenAccessory: {
+		{"Boot all", "Create and start every accessory container.", "kamal accessory boot all", "", false},
+		{"Start all", "Start the existing accessory containers.", "kamal accessory start all", "", false},
+		{"Stop all", "Stop every accessory container.", "kamal accessory stop all", "", false},
+		{"Restart all", "Restart every accessory container.", "kamal accessory restart all", "", false},
+		{"Reboot all", "Remove and boot every accessory again, picking up config changes.", "kamal accessory reboot all", "", false},
+		{"Remove all", "Remove accessory containers, images and data directories.", "kamal accessory remove all", "", false},
+		{"Details all", "Show the accessory containers.", "kamal accessory details all", "", true},
+		{"Logs all", "Show recent accessory logs.", "kamal accessory logs all", "", true},
+		{"Exec: sh (all)", "Run sh in every accessory.", "kamal accessory exec all sh", "", false},
+		{"Upgrade", "Upgrade accessories from Kamal 1 to Kamal 2.", "kamal accessory upgrade", "", false},
+	},
+	ScreenProxy: {
+		{"Boot", "Start kamal-proxy on every host.", "kamal proxy boot", "", false},
+		{"Start", "Start the existing proxy container.", "kamal proxy start", "", false},
+		{"Stop", "Stop kamal-proxy; the app stops receiving traffic.", "kamal proxy stop", "", false},
+		{"Restart", "Restart the proxy container.", "kamal proxy restart", "", false},
+		{"Reboot", "Remove and boot the proxy again on every host at once.", "kamal proxy reboot", "", false},
+		{"Reboot (rolling)", "Reboot the proxy one host at a time.", "kamal proxy reboot --rolling", "", false},
+		{"Logs", "Show recent proxy logs.", "kamal proxy logs", "", true},
+		{"Details", "Show the proxy container.", "kamal proxy details", "", true},
+		{"Remove", "Remove the proxy container and image.", "kamal proxy remove", "", false},
+		{"Boot config get (deprecated)", "Show the saved proxy boot options.", "kamal proxy boot_config get", "Boot config get", true},
+		{"Boot config set (deprecated)", "Save proxy boot options used on the next reboot.", "kamal proxy boot_config set", "Boot config set", false},
+		{"Boot config reset (deprecated)", "Forget saved proxy boot options.", "kamal proxy boot_config reset", "Boot config reset", false},
+		{"Live: Proxy logs (stream)", "Stream proxy logs into the Output panel until Esc.", "kamal proxy logs (streamed)", "Stream logs", true},
+		{"Upgrade (check + rolling reboot)", "Compare the running proxy with the latest release, then offer a rolling reboot.", "kamal proxy details, then kamal proxy reboot --rolling", "Upgrade", false},
+	},
+	ScreenOther: {
+		{"Prune >", "Remove old images and containers.", "", "", false},
+		{"Build >", "Build, push and manage the image builder.", "", "", false},
+		{"Config", "Print the merged configuration.", "kamal config", "", true},
+		{"Details", "Show app, proxy and accessory containers.", "kamal details", "", true},
+		{"Audit", "Show the audit log from each host.", "kamal audit", "", true},
+		{"Lock status", "Show who holds the deploy lock.", "kamal lock status", "", true},
+		{"Lock acquire", "Take the deploy lock so nobody else can deploy.", "kamal lock acquire", "", false},
+		{"Lock release", "Release the deploy lock.", "kamal lock release", "", false},
+		{"Lock release --force", "Release the deploy lock even if someone else holds it.", "kamal lock release --force", "", false},
+		{"Registry >", "Log in to or out of the registry.", "", "", false},
+		{"Secrets >", "Fetch, extract and print secrets.", "", "", false},
+		{"Env push", "Push env files to the hosts (Kamal 1).", "kamal env push", "", false},
+		{"Env pull", "Pull env files from the hosts (Kamal 1).", "kamal env pull", "", false},
+		{"Env delete", "Delete env files from the hosts (Kamal 1).", "kamal env delete", "", false},
+		{"Docs", "Show Kamal documentation.", "kamal docs", "", true},
+		{"Help", "Show Kamal's command help.", "kamal help", "", true},
+		{"Init", "Create config/deploy.yml and .kamal/secrets stubs.", "kamal init", "", false},
+		{"Upgrade", "Upgrade hosts from Kamal 1 to Kamal 2.", "kamal upgrade", "", false},
+		{"Version", "Show the kamal version on PATH.", "kamal version", "", true},
+		{"Journal >", "Review this session's mutating actions and export them as JSON lines.", "", "", false},
+		{"Run with secret env…", "Type a variable and its masked value, passed to the next kamal command only and never saved.", "KEY=… kamal <next command>", "Secret env", false},
+		{"Remove EVERYTHING (app+proxy+accessories)", "Tear down the app, kamal-proxy and every accessory with its data on all hosts. Asks for the destination name, then lists what goes; what runs is saved to a file first.", "kamal remove --confirmed", "Remove everything", false},
+	},
+	ScreenConfig: {
+		{"Edit deploy config (current dest)", "Open the destination's deploy config in the in-TUI editor.", "", "Edit deploy config", false},
+		{"Edit secrets (current dest)", "Open the destination's .kamal/secrets in the in-TUI editor.", "", "Edit secrets", false},
+		{"Redeploy (after edit)", "Redeploy so config changes take effect.", "kamal redeploy", "Redeploy", false},
+		{"App restart (after edit)", "Restart the app containers.", "kamal app restart", "App restart", false},
+		{"Env drift (running vs config)", "Compare configured env keys with the running containers.", "kamal app exec --reuse env", "Env drift", true},
+		{"Edit key in all destinations (bulk edit)", "Set one YAML key in deploy.yml and every destination config, after previewing each diff.", "", "Bulk edit key", false},
+		{"Create project config (.lazykamal.yml)", "Write a commented .lazykamal.yml documenting every option, then open it in the editor.", "lazykamal init-config", "Create project config", false},
+	},
+	ScreenBuild: {
+		{"Push", "Build the image and push it to the registry.", "kamal build push", "", false},
+		{"Pull", "Pull the image from the registry onto the hosts.", "kamal build pull", "", false},
+		{"Deliver", "Build, push and pull the image.", "kamal build deliver", "", false},
+		{"Dev", "Build a local image tagged dirty, without pushing.", "kamal build dev", "", false},
+		{"Create", "Create the build setup (buildx builder).", "kamal build create", "", false},
+		{"Remove", "Remove the build setup.", "kamal build remove", "", false},
+		{"Details", "Show the builder setup.", "kamal build details", "", true},
+	},
+	ScreenPrune: {
+		{"All", "Remove unused images and stopped containers.", "kamal prune all", "", false},
+		{"Images", "Remove unused images.", "kamal prune images", "", false},
+		{"Containers", "Remove old stopped app containers.", "kamal prune containers", "", false},
+	},
+	ScreenSecrets: {
+		{"Fetch", "Fetch secrets from a password manager adapter.", "kamal secrets fetch", "", true},
+		{"Extract", "Extract one secret from fetched secrets.", "kamal secrets extract", "", true},
+		{"Print", "Print the resolved secrets; values are shown.", "kamal secrets print", "", true},
+	},
+	ScreenRegistry: {
+		{"Setup", "Log in to the registry locally and on the hosts.", "kamal registry setup", "", false},
+		{"Login", "Log in to the registry locally and on the hosts.", "kamal registry login", "", false},
+		{"Logout", "Log out of the registry on the hosts.", "kamal registry logout", "", false},
+		{"Remove", "Remove the registry setup (e.g. a local registry).", "kamal registry remove", "", false},
+	},
+}
+
+// menuLabels returns the labels of the menu for screen. When one of them is
+// wider than width cells the whole menu switches to its short labels, so
+// rows stay alike; width <= 0 means no limit.
+func menuLabels(screen Screen, width int) []string {
+	items := menus[screen]
+	short := false
+	for _, it := range items {
+		if width > 0 && visibleWidth(glyphs(it.Label)) > width {
+			short = true
+			break
+		}
+	}
+	labels := make([]string, len(items))
+	for i, it := range items {
+		label := it.Label
+		if short && it.Short != "" {
+			label = it.Short
+		}
+		labels[i] = glyphs(label)
+	}
+	return labels
+}
+
+// menuLabelWidth is the room for labels in a menu view width cells wide,
+// after the selection arrow.
+func menuLabelWidth(width int) int {
+	return width - 2
+}
+
+// writeMenu writes labels with an arrow at selected, then the key hint.
+// Labels are cut to the width and the hint is wrapped, so neither runs past
+// the panel edge and the arrow and hint always show.
+func writeMenu(w io.Writer, width int, labels []string, selected int, hint string) {
+	for i, l := range labels {
+		prefix := "  "
+		if i == selected {
+			prefix = iconArrow + " "
+		}
+		fmt.Fprintf(w, "%s%s\n", prefix, truncate(l, menuLabelWidth(width)))
+	}
+	fmt.Fprintln(w, "")
+	writeKeyHint(w, width, hint)
+}
+
+// writeKeyHint writes a " Enter: run  b/Esc: back" style hint, wrapped when
+// it does not fit in width cells.
+func writeKeyHint(w io.Writer, width int, hint string) {
+	if visibleWidth(hint) <= width {
+		fmt.Fprintln(w, hint)
+		return
+	}
+	for _, l := range wrapText(hint, width-1) {
+		fmt.Fprintln(w, " "+l)
+	}
+}
+
+// selectedMenuItem is the highlighted menu item, if the screen is a menu.
+func (gui *GUI) selectedMenuItem() (menuItem, bool) {
+	items := menus[gui.screen]
+	if gui.submenuIdx < 0 || gui.submenuIdx >= len(items) {
+		return menuItem{}, false
+	}
+	if gui.screen == ScreenAccessory {
+		if !gui.hasAccessories() {
+			return menuItem{}, false
+		}
+		item := items[gui.submenuIdx]
+		item.Label, item.Cmd = forAccessory(item.Label, gui.accessory), forAccessory(item.Cmd, gui.accessory)
+		return item, true
+	}
+	return items[gui.submenuIdx], true
+}
+
+// writeMenuHint explains the highlighted item under the menu: what it does
+// and the command it runs.
+func (gui *GUI) writeMenuHint(v *gocui.View) {
+	item, ok := gui.selectedMenuItem()
+	if !ok {
+		return
+	}
+	width, _ := v.Size()
+	fmt.Fprintln(v, "")
+	for _, l := range wrapText(glyphs(item.Desc), width-2) {
+		fmt.Fprintln(v, " "+dim(l))
+	}
+	if item.Cmd != "" {
+		for _, l := range wrapText("$ "+item.Cmd, width-2) {
+			fmt.Fprintln(v, " "+cyan(l))
+		}
+	}
+}
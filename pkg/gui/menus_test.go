@@ -0,0 +1,87 @@
+package gui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMenusDescribeEveryItem(t *testing.T) {
+	for screen, want := range menuItemCounts {
+		items := menus[screen]
+		if len(items) != want {
+			t.Errorf("%s menu has %d items, menuItemCounts says %d", screen, len(items), want)
+		}
+		for i, it := range items {
+			if it.Label == "" || it.Desc == "" {
+				t.Errorf("%s item %d (%q) has no label or description", screen, i, it.Label)
+			}
+			opensScreen := screen == ScreenMainMenu || strings.HasSuffix(it.Label, " >") || strings.HasPrefix(it.Label, "Edit ")
+			if opensScreen != (it.Cmd == "") {
+				t.Errorf("%s item %q: Cmd = %q", screen, it.Label, it.Cmd)
+			}
+		}
+	}
+	for screen := range menus {
+		if _, ok := menuItemCounts[screen]; !ok {
+			t.Errorf("%s menu missing from menuItemCounts", screen)
+		}
+	}
+}
+
+func TestSelectedMenuItem(t *testing.T) {
+	gui := &GUI{screen: ScreenApp, submenuIdx: 9}
+	it, ok := gui.selectedMenuItem()
+	if !ok || it.Cmd != "kamal app stale_containers" {
+		t.Errorf("selectedMenuItem() = %+v, %v", it, ok)
+	}
+	gui.screen = ScreenApps
+	if _, ok := gui.selectedMenuItem(); ok {
+		t.Error("selectedMenuItem() on the Apps list = ok")
+	}
+}
+
+func TestMenuShortLabels(t *testing.T) {
+	for screen, items := range menus {
+		for _, it := range items {
+			if it.Short != "" && visibleWidth(it.Short) >= visibleWidth(it.Label) {
+				t.Errorf("%s item %q: short label %q is not shorter", screen, it.Label, it.Short)
+			}
+		}
+	}
+	if got := menuLabels(ScreenMainMenu, 0)[0]; got != "Deploy / Redeploy / Rollback" {
+		t.Errorf("wide main menu label = %q", got)
+	}
+	narrow := menuLabels(ScreenMainMenu, 24)
+	if narrow[0] != "Deploy" || narrow[6] != "Config" {
+		t.Errorf("narrow main menu labels = %q", narrow)
+	}
+	// Short labels switch the whole menu, including items without one.
+	if got := menuLabels(ScreenConfig, 30); got[0] != "Edit deploy config" || got[2] != "Redeploy" {
+		t.Errorf("narrow config menu labels = %q", got)
+	}
+}
+
+func TestWriteMenuFitsPanel(t *testing.T) {
+	hint := " Enter: run  b/Esc: back"
+	for screen := range menus {
+		for width := 12; width <= 60; width++ {
+			labels := menuLabels(screen, menuLabelWidth(width))
+			selected := len(labels) - 1
+			var buf bytes.Buffer
+			writeMenu(&buf, width, labels, selected, hint)
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			for _, l := range lines {
+				if visibleWidth(l) > width {
+					t.Errorf("%s at width %d: %q is %d cells wide", screen, width, l, visibleWidth(l))
+				}
+			}
+			if !strings.HasPrefix(lines[selected], iconArrow+" ") {
+				t.Errorf("%s at width %d: selected row %q has no arrow", screen, width, lines[selected])
+			}
+			if got := strings.Join(lines[len(labels)+1:], " "); !strings.Contains(got, "Enter: run") || !strings.Contains(got, "back") {
+				t.Errorf("%s at width %d: hint = %q", screen, width, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package gui
+
+import (
+	"github.com/jroimartin/gocui"
+)
+
+// menuPage is how far PgUp/PgDn move a menu selection.
+const menuPage = 10
+
+// clampIndex keeps a selection within [0, last]. An empty list (last < 0)
+// only has 0.
+func clampIndex(i, last int) int {
+	if i > last {
+		i = last
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// maxLogScroll is the offset that shows the last full page of n lines in a
+// panel height lines tall.
+func maxLogScroll(n, height int) int {
+	if height < 1 {
+		height = 1
+	}
+	if n <= height {
+		return 0
+	}
+	return n - height
+}
+
+// scrollBy moves a log offset by delta lines. The offset is clamped before
+// moving, so one left past the end (follow mode) pages up from the last
+// page rather than from nowhere.
+func scrollBy(scroll, delta, n, height int) int {
+	last := maxLogScroll(n, height)
+	return clampIndex(clampIndex(scroll, last)+delta, last)
+}
+
+// focusTitle marks the Output panel title while the navigation keys scroll
+// the log instead of moving the menu selection.
+func focusTitle(title string, focused bool) string {
+	if focused {
+		return "»" + title
+	}
+	return title
+}
+
+// panelHeight is the inner height of view, at least 1.
+func panelHeight(g *gocui.Gui, view string) int {
+	v, err := g.View(view)
+	if err != nil || v == nil {
+		return 1
+	}
+	_, h := v.Size()
+	if h < 1 {
+		h = 1
+	}
+	return h
+}
+
+// navigating reports whether the list and log navigation keys apply: not
+// while the editor, a dialog, the help or the pager has the keyboard.
+func (gui *GUI) navigating() bool {
+	switch gui.screen {
+	case ScreenEditor, ScreenHelp, ScreenConfirm, ScreenPicker, ScreenInput, ScreenPager:
+		return false
+	}
+	return true
+}
+
+// menuLast is the last selectable index on the current list screen, and
+// false on screens without a list.
+func (gui *GUI) menuLast() (int, bool) {
+	switch gui.screen {
+	case ScreenApps:
+		return appSelectableCount(gui.currentAppRows()) - 1, true
+	case ScreenAccessory:
+		if !gui.hasAccessories() {
+			return 0, true
+		}
+	}
+	items, ok := menus[gui.screen]
+	return len(items) - 1, ok
+}
+
+func (gui *GUI) selection() int {
+	if gui.screen == ScreenApps {
+		return appCursor(gui.currentAppRows(), gui.destinations, gui.selectedApp)
+	}
+	return gui.submenuIdx
+}
+
+// setSelection moves the selection on the current list screen to i,
+// clamped. On the Apps screen i counts selectable rows, which differ from
+// destinations while groups are collapsed. Changing app resets the live
+// status.
+func (gui *GUI) setSelection(i int) {
+	last, ok := gui.menuLast()
+	if !ok {
+		return
+	}
+	i = clampIndex(i, last)
+	if gui.screen != ScreenApps {
+		gui.submenuIdx = i
+		return
+	}
+	if app := appAt(gui.currentAppRows(), gui.destinations, i, gui.selectedApp); app != gui.selectedApp {
+		gui.selectedApp = app
+		gui.resetStatus()
+	}
+}
+
+// scrollLog moves the Output offset by delta lines. Scrolling back pins
+// the Output; reaching the bottom follows new lines again.
+func (gui *GUI) scrollLog(g *gocui.Gui, delta int) {
+	gui.scrollLogBy(delta, panelHeight(g, viewLog))
+}
+
+// scrollLogBy is scrollLog for a panel height lines tall.
+func (gui *GUI) scrollLogBy(delta, height int) {
+	gui.logMu.Lock()
+	n := len(gui.visibleLog())
+	if !gui.logPinned {
+		gui.logScroll = maxLogScroll(n, height)
+	}
+	gui.logScroll = scrollBy(gui.logScroll, delta, n, height)
+	gui.logPinned = gui.logScroll < maxLogScroll(n, height)
+	gui.logMu.Unlock()
+}
+
+// followHint is the Output title note while it is pinned: how to follow
+// new lines again.
+func followHint(focused bool) string {
+	if focused {
+		return "(scrolled, G to follow) "
+	}
+	return "(scrolled, Tab G to follow) "
+}
+
+func (gui *GUI) keyToggleFocus(g *gocui.Gui, v *gocui.View) error {
+	if gui.navigating() {
+		gui.logFocus = !gui.logFocus
+	}
+	return nil
+}
+
+// keyPage pages the Output by its panel height when it has focus, or moves
+// the menu selection by menuPage.
+func (gui *GUI) keyPage(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if !gui.navigating() {
+			return nil
+		}
+		if gui.logFocus {
+			gui.scrollLog(g, dir*panelHeight(g, viewLog))
+			return nil
+		}
+		gui.setSelection(gui.selection() + dir*menuPage)
+		return nil
+	}
+}
+
+// keyJump goes to the start (dir < 0) or end of the Output when it has
+// focus, or to the first or last menu item.
+func (gui *GUI) keyJump(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if !gui.navigating() {
+			return nil
+		}
+		if gui.logFocus {
+			height := panelHeight(g, viewLog)
+			gui.logMu.Lock()
+			gui.logScroll = 0
+			gui.logPinned = dir < 0 && len(gui.visibleLog()) > height
+			gui.logMu.Unlock()
+			return nil
+		}
+		if dir < 0 {
+			gui.setSelection(0)
+		} else if last, ok := gui.menuLast(); ok {
+			gui.setSelection(last)
+		}
+		return nil
+	}
+}
+
+// navigating reports whether the list and log navigation keys apply: not
+// while a dialog, the help or the pager is open or a path is being typed.
+func (gui *ServerGUI) navigating() bool {
+	switch gui.screen {
+	case ServerScreenConfirm, ServerScreenHelp, ServerScreenPager:
+		return false
+	}
+	return !gui.typing()
+}
+
+// menuLast is the last selectable index on the current list screen, and
+// false on screens without a list.
+func (gui *ServerGUI) menuLast() (int, bool) {
+	switch gui.screen {
+	case ServerScreenApps:
+		return len(gui.apps) - 1, true
+	case ServerScreenAppMenu:
+		return 6, true // Containers, Logs, Details, Actions, Proxy, Exec, Back
+	case ServerScreenActionsMenu:
+		return 9, true // Boot, Start, Stop, Restart, Remove, Images, Version, Health, Verify, Back
+	case ServerScreenProxyMenu:
+		return 7, true // Logs, Details, Restart, Reboot, Stop, Start, Upgrade, Back
+	case ServerScreenContainerSelect:
+		return len(gui.allContainers) - 1, true
+	case ServerScreenSaveLogs:
+		return len(saveLogsOptions) - 1, true
+	case ServerScreenPortForward:
+		return len(gui.forward.ports) - 1, true
+	}
+	return 0, false
+}
+
+func (gui *ServerGUI) selection() int {
+	switch gui.screen {
+	case ServerScreenApps:
+		return gui.selectedApp
+	case ServerScreenContainerSelect:
+		return gui.selectedContainer
+	}
+	return gui.selectedItem
+}
+
+// setSelection moves the selection on the current list screen to i,
+// clamped.
+func (gui *ServerGUI) setSelection(i int) {
+	last, ok := gui.menuLast()
+	if !ok {
+		return
+	}
+	i = clampIndex(i, last)
+	switch gui.screen {
+	case ServerScreenApps:
+		gui.selectedApp = i
+	case ServerScreenContainerSelect:
+		gui.selectedContainer = i
+	default:
+		gui.selectedItem = i
+	}
+}
+
+// scrollLog moves the Output offset by delta lines.
+func (gui *ServerGUI) scrollLog(g *gocui.Gui, delta int) {
+	height := panelHeight(g, viewLog)
+	gui.logMu.Lock()
+	gui.logScroll = scrollBy(gui.logScroll, delta, len(gui.logLines), height)
+	gui.logMu.Unlock()
+}
+
+func (gui *ServerGUI) keyToggleFocus(g *gocui.Gui, v *gocui.View) error {
+	if gui.navigating() {
+		gui.logFocus = !gui.logFocus
+	}
+	return nil
+}
+
+// keyPage pages the Output by its panel height when it has focus, or moves
+// the menu selection by menuPage.
+func (gui *ServerGUI) keyPage(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if !gui.navigating() {
+			return nil
+		}
+		if gui.logFocus {
+			gui.scrollLog(g, dir*panelHeight(g, viewLog))
+			return nil
+		}
+		gui.setSelection(gui.selection() + dir*menuPage)
+		return nil
+	}
+}
+
+// keyJump goes to the start (dir < 0) or end of the Output when it has
+// focus, or to the first or last list item.
+func (gui *ServerGUI) keyJump(dir int) func(*gocui.Gui, *gocui.View) error {
+	return func(g *gocui.Gui, v *gocui.View) error {
+		if !gui.navigating() {
+			return nil
+		}
+		if gui.logFocus {
+			height := panelHeight(g, viewLog)
+			gui.logMu.Lock()
+			gui.logScroll = 0
+			if dir > 0 {
+				gui.logScroll = maxLogScroll(len(gui.logLines), height)
+			}
+			gui.logMu.Unlock()
+			return nil
+		}
+		if dir < 0 {
+			gui.setSelection(0)
+		} else if last, ok := gui.menuLast(); ok {
+			gui.setSelection(last)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,213 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/docker"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestClampIndex(t *testing.T) {
+	tests := []struct {
+		i, last, want int
+	}{
+		{0, 5, 0},
+		{3, 5, 3},
+		{5, 5, 5},
+		{15, 5, 5},
+		{-10, 5, 0},
+		{0, -1, 0}, // empty list
+		{3, -1, 0},
+	}
+	for _, tt := range tests {
+		if got := clampIndex(tt.i, tt.last); got != tt.want {
+			t.Errorf("clampIndex(%d, %d) = %d, want %d", tt.i, tt.last, got, tt.want)
+		}
+	}
+}
+
+func TestScrollBy(t *testing.T) {
+	tests := []struct {
+		name                           string
+		scroll, delta, n, height, want int
+	}{
+		{"page down", 0, 20, 3000, 20, 20},
+		{"page up", 40, -20, 3000, 20, 20},
+		{"page up at top", 0, -20, 3000, 20, 0},
+		{"page down stops at last page", 2975, 20, 3000, 20, 2980},
+		{"page up from follow mode", 3000, -20, 3000, 20, 2960},
+		{"line down", 10, 1, 3000, 20, 11},
+		{"buffer shorter than panel", 0, 20, 5, 20, 0},
+		{"empty buffer", 0, 20, 0, 20, 0},
+		{"zero height panel", 0, 1, 10, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scrollBy(tt.scroll, tt.delta, tt.n, tt.height); got != tt.want {
+				t.Errorf("scrollBy(%d, %d, %d, %d) = %d, want %d", tt.scroll, tt.delta, tt.n, tt.height, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxLogScroll(t *testing.T) {
+	tests := []struct {
+		n, height, want int
+	}{
+		{3000, 20, 2980},
+		{20, 20, 0},
+		{5, 20, 0},
+		{0, 20, 0},
+		{10, 0, 9},
+	}
+	for _, tt := range tests {
+		if got := maxLogScroll(tt.n, tt.height); got != tt.want {
+			t.Errorf("maxLogScroll(%d, %d) = %d, want %d", tt.n, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestSetSelectionMenu(t *testing.T) {
+	gui := &GUI{screen: ScreenOther}
+	steps := []struct {
+		name string
+		to   func() int
+		want int
+	}{
+		{"page down", func() int { return gui.selection() + menuPage }, 10},
+		{"page down again", func() int { return gui.selection() + menuPage }, 20},
+		{"page down past end", func() int { return gui.selection() + menuPage }, 21},
+		{"page up", func() int { return gui.selection() - menuPage }, 11},
+		{"page up past start", func() int { return gui.selection() - 2*menuPage }, 0},
+		{"end", func() int { last, _ := gui.menuLast(); return last }, 21},
+	}
+	for _, s := range steps {
+		gui.setSelection(s.to())
+		if gui.submenuIdx != s.want {
+			t.Errorf("%s: submenuIdx = %d, want %d", s.name, gui.submenuIdx, s.want)
+		}
+	}
+}
+
+func TestSetSelectionApps(t *testing.T) {
+	gui := &GUI{screen: ScreenApps, destinations: make([]kamal.DeployDestination, 3), statusRefresh: make(chan struct{}, 1)}
+	gui.setSelection(menuPage)
+	if gui.selectedApp != 2 {
+		t.Errorf("selectedApp = %d, want 2", gui.selectedApp)
+	}
+	select {
+	case <-gui.statusRefresh:
+	default:
+		t.Error("changing app should refresh the status at once")
+	}
+	gui.setSelection(gui.selection() + 1)
+	select {
+	case <-gui.statusRefresh:
+		t.Error("staying on the same app should not refresh the status")
+	default:
+	}
+
+	empty := &GUI{screen: ScreenApps}
+	empty.setSelection(menuPage)
+	if empty.selectedApp != 0 {
+		t.Errorf("empty list: selectedApp = %d, want 0", empty.selectedApp)
+	}
+
+	dialog := &GUI{screen: ScreenConfirm, submenuIdx: 4}
+	dialog.setSelection(0)
+	if dialog.submenuIdx != 4 {
+		t.Error("a screen without a list should ignore selection moves")
+	}
+}
+
+func TestServerSetSelection(t *testing.T) {
+	tests := []struct {
+		name   string
+		screen ServerScreen
+		to     int
+		want   int
+	}{
+		{"apps end", ServerScreenApps, 100, 11},
+		{"actions page down", ServerScreenActionsMenu, menuPage, 9},
+		{"proxy start", ServerScreenProxyMenu, -menuPage, 0},
+		{"containers page down", ServerScreenContainerSelect, menuPage, 4},
+		{"save logs end", ServerScreenSaveLogs, 100, len(saveLogsOptions) - 1},
+		{"port forward end", ServerScreenPortForward, 100, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gui := &ServerGUI{
+				screen:        tt.screen,
+				apps:          make([]docker.App, 12),
+				allContainers: make([]ContainerInfo, 5),
+				forward:       &forwardPrompt{ports: make([]docker.ContainerPort, 3)},
+			}
+			gui.setSelection(tt.to)
+			if got := gui.selection(); got != tt.want {
+				t.Errorf("selection() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFocusTitle(t *testing.T) {
+	if got := focusTitle(" Output ", false); got != " Output " {
+		t.Errorf("unfocused title = %q", got)
+	}
+	if got := focusTitle(" Output ", true); got != "» Output " {
+		t.Errorf("focused title = %q", got)
+	}
+}
+
+func TestLogPinAndFollow(t *testing.T) {
+	gui := &GUI{}
+	for i := 0; i < 30; i++ {
+		gui.appendLog([]string{fmt.Sprintf("line %d", i)})
+	}
+	gui.scrollLogBy(5, 10)
+	if gui.logPinned {
+		t.Fatal("scrolling down at the bottom pinned the Output")
+	}
+	gui.scrollLogBy(-5, 10)
+	if !gui.logPinned || gui.logScroll != 15 {
+		t.Fatalf("after scrolling up: pinned = %v, scroll = %d, want pinned at 15", gui.logPinned, gui.logScroll)
+	}
+	gui.appendLog([]string{"new"})
+	if gui.logScroll != 15 {
+		t.Errorf("new output moved a pinned Output to %d", gui.logScroll)
+	}
+	gui.scrollLogBy(menuPage, 10)
+	if gui.logPinned {
+		t.Error("scrolling back to the bottom did not follow again")
+	}
+}
+
+func TestPinnedLogTrim(t *testing.T) {
+	gui := &GUI{}
+	lines := make([]string, logBufLive)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	gui.appendLog(lines)
+	gui.logScroll, gui.logPinned = 100, true
+	gui.appendLog([]string{"a", "b", "c"})
+	if gui.logScroll != 97 || gui.logLines[gui.logScroll].text != "line 100" {
+		t.Errorf("after trimming 3 lines scroll = %d (%q), want 97 on line 100", gui.logScroll, gui.logLines[gui.logScroll].text)
+	}
+	gui.logScroll = 1
+	gui.appendLog([]string{"d", "e"})
+	if gui.logScroll != 0 {
+		t.Errorf("scroll trimmed past the front = %d, want 0", gui.logScroll)
+	}
+}
+
+func TestFollowHint(t *testing.T) {
+	if got := followHint(true); !strings.Contains(got, "G to follow") || strings.Contains(got, "Tab") {
+		t.Errorf("focused hint = %q", got)
+	}
+	if got := followHint(false); !strings.Contains(got, "Tab G to follow") {
+		t.Errorf("unfocused hint = %q", got)
+	}
+}
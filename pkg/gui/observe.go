@@ -0,0 +1,144 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// observePollInterval is how often observe mode re-checks the lock and the
+// versions running on each host.
+const observePollInterval = 5 * time.Second
+
+// observeDeploy follows a deploy started elsewhere (e.g. CI). It only runs
+// read-only commands: lock status, audit, app version and app logs. It never
+// acquires or releases the lock. Esc stops observing; the deploy continues.
+func (gui *GUI) observeDeploy() {
+	gui.liveLogsMu.Lock()
+	if gui.liveLogsActive {
+		gui.liveLogsMu.Unlock()
+		gui.logInfo("A live stream is already running (Esc to stop it first)")
+		return
+	}
+	tag := gui.tags.tag(gui.selectedDestination())
+	gui.liveLogsActive = true
+	gui.liveLogsStop = make(chan struct{})
+	gui.liveLogsTag = tag
+	stopCh := gui.liveLogsStop
+	gui.liveLogsMu.Unlock()
+
+	opts := gui.runOpts()
+	opts.OnRun = nil // observing polls every few seconds; its commands are not echoed
+	opts.Env = nil
+	gui.logInfo("Observe deploy: looking for a deploy in progress " + dim("(read-only, Esc stop)"))
+
+	go func() {
+		var rollout *kamal.Rollout
+		defer func() {
+			gui.liveLogsMu.Lock()
+			if gui.liveLogsStop == stopCh && gui.liveLogsActive {
+				close(stopCh)
+				gui.liveLogsActive = false
+			}
+			if gui.observe == rollout {
+				gui.observe = nil
+			}
+			gui.liveLogsMu.Unlock()
+			gui.logPause.Reset()
+			gui.g.Update(func(*gocui.Gui) error { return nil })
+			go gui.refreshStatus()
+		}()
+
+		lockRes, err := kamal.RunKamalWithStop([]string{"lock", "status"}, opts, stopCh)
+		if err != nil {
+			select {
+			case <-stopCh:
+			default:
+				gui.logError("Observe deploy: lock status failed: " + err.Error())
+			}
+			return
+		}
+		lock := kamal.ParseLockStatus(lockRes.Combined())
+		if auditRes, err := kamal.RunKamalWithStop([]string{"audit"}, opts, stopCh); err == nil {
+			if last := kamal.LastAuditEntry(auditRes.Combined()); last != "" {
+				gui.appendLog([]string{dim("  last audit: " + last)})
+			}
+		}
+		if !lock.Held {
+			gui.logInfo("No deploy in progress (deploy lock is not held)")
+			return
+		}
+
+		rollout = kamal.NewRollout(lock.Version)
+		gui.liveLogsMu.Lock()
+		gui.observe = rollout
+		gui.liveLogsMu.Unlock()
+		target := lock.Version
+		if target == "" {
+			target = "unknown version"
+		}
+		gui.logInfo(fmt.Sprintf("Deploy in progress: %s by %s", target, lock.Holder))
+
+		logOpts := opts
+		logOpts.Version = lock.Version
+		go gui.pipeLive(tag, []string{"app", "logs", "--follow"}, logOpts, stopCh)
+
+		ticker := time.NewTicker(observePollInterval)
+		defer ticker.Stop()
+		for {
+			obs, ok := gui.pollRollout(opts, stopCh)
+			if ok {
+				gui.liveLogsMu.Lock()
+				changed := rollout.Observe(obs)
+				summary := rollout.Summary()
+				gui.liveLogsMu.Unlock()
+				if changed {
+					gui.logInfo("Observe deploy: " + summary)
+				}
+				switch rollout.Phase {
+				case kamal.RolloutSucceeded:
+					gui.logSuccess("Deploy finished: " + summary)
+					return
+				case kamal.RolloutFailed:
+					gui.logError("Deploy did not converge: " + summary)
+					return
+				}
+			}
+			gui.g.Update(func(*gocui.Gui) error { return nil })
+			select {
+			case <-stopCh:
+				gui.logInfo("Stopped observing (the remote deploy continues)")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// pollRollout takes one read-only snapshot of the lock and running versions.
+func (gui *GUI) pollRollout(opts kamal.RunOptions, stopCh <-chan struct{}) (kamal.RolloutObservation, bool) {
+	lockRes, err := kamal.RunKamalWithStop([]string{"lock", "status"}, opts, stopCh)
+	if err != nil {
+		return kamal.RolloutObservation{}, false
+	}
+	verRes, err := kamal.RunKamalWithStop([]string{"app", "version"}, opts, stopCh)
+	if err != nil {
+		return kamal.RolloutObservation{}, false
+	}
+	return kamal.RolloutObservation{
+		LockHeld: kamal.ParseLockStatus(lockRes.Combined()).Held,
+		Versions: kamal.ParseAppVersions(verRes.Combined()),
+	}, true
+}
+
+// observeStatus returns the header text while observing a deploy, or "".
+func (gui *GUI) observeStatus() string {
+	gui.liveLogsMu.Lock()
+	defer gui.liveLogsMu.Unlock()
+	if gui.observe == nil {
+		return ""
+	}
+	return gui.observe.Summary()
+}
@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jroimartin/gocui"
+)
+
+// opTally counts the per-container outcomes of one multi-container action
+// and where its errors were logged, so a failure that scrolled past is not
+// hidden behind the completion line.
+type opTally struct {
+	total  int
+	failed int
+	errors []int // log positions (counting logDropped) of the error entries
+}
+
+func (t *opTally) ok() { t.total++ }
+
+func (t *opTally) fail(pos int) {
+	t.total++
+	t.failed++
+	t.errors = append(t.errors, pos)
+}
+
+// completion is the line that ends the action: green when everything
+// succeeded, yellow when some items failed and red when all did.
+func (t *opTally) completion(name string, took time.Duration) string {
+	switch {
+	case t.failed == 0:
+		return statusLine("success", fmt.Sprintf("%s completed in %s", name, formatDuration(took)))
+	case t.failed == t.total:
+		return red(fmt.Sprintf("%s %s failed: %d of %d failed in %s — press e to jump to first error",
+			iconError, name, t.failed, t.total, formatDuration(took)))
+	}
+	return yellow(fmt.Sprintf("%s %s completed in %s: %d of %d failed — press e to jump to first error",
+		iconWarning, name, formatDuration(took), t.failed, t.total))
+}
+
+// nextJump picks the next of positions (absolute, counting dropped) still
+// in the buffer, starting at next and wrapping. It returns the buffer
+// index to scroll to, or -1 when every position has been dropped, and the
+// next value to start from.
+func nextJump(positions []int, next, dropped int) (target, after int) {
+	for range positions {
+		abs := positions[next%len(positions)]
+		next = (next + 1) % len(positions)
+		if abs >= dropped {
+			return abs - dropped, next
+		}
+	}
+	return -1, next
+}
+
+// logItemError logs msg as an error and counts it as a failed item of t.
+func (gui *ServerGUI) logItemError(t *opTally, msg string) {
+	t.fail(gui.appendLogAt([]string{statusLine("error", msg)}))
+}
+
+// finishTally logs the completion line of a multi-container action and
+// makes e jump through its errors.
+func (gui *ServerGUI) finishTally(name string, t *opTally) {
+	gui.cmdMu.Lock()
+	start := gui.cmdStartTime
+	gui.cmdMu.Unlock()
+	gui.appendLog([]string{t.completion(name, time.Since(start))})
+	gui.logMu.Lock()
+	gui.errorJumps, gui.errorJumpNext = t.errors, 0
+	gui.logMu.Unlock()
+}
+
+// keyJumpError scrolls the Output panel to the next error of the last
+// multi-container action.
+func (gui *ServerGUI) keyJumpError(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.logMu.Lock()
+	target := -1
+	if len(gui.errorJumps) > 0 {
+		target, gui.errorJumpNext = nextJump(gui.errorJumps, gui.errorJumpNext, gui.logDropped)
+	}
+	if target >= 0 {
+		gui.logScroll = target
+	}
+	gui.logMu.Unlock()
+	if target < 0 {
+		gui.logInfo("No error in the last action")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package gui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestOpTallyCompletion(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []bool // true = item succeeded
+		want    string
+	}{
+		{"all ok", []bool{true, true, true}, iconSuccess + " Restart completed in 3.0s"},
+		{"some failed", []bool{true, false, true, true, false, true}, iconWarning + " Restart completed in 3.0s: 2 of 6 failed — press e to jump to first error"},
+		{"all failed", []bool{false, false}, iconError + " Restart failed: 2 of 2 failed in 3.0s — press e to jump to first error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tally opTally
+			for i, ok := range tt.results {
+				if ok {
+					tally.ok()
+				} else {
+					tally.fail(i)
+				}
+			}
+			if tally.total != len(tt.results) {
+				t.Errorf("total = %d, want %d", tally.total, len(tt.results))
+			}
+			if len(tally.errors) != tally.failed {
+				t.Errorf("errors = %v, want one position per failure (%d)", tally.errors, tally.failed)
+			}
+			if got := stripANSI(tally.completion("Restart", 3*time.Second)); got != tt.want {
+				t.Errorf("completion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextJump(t *testing.T) {
+	positions := []int{5, 12, 40}
+	steps := []struct {
+		dropped    int
+		wantTarget int
+	}{
+		{0, 5},
+		{0, 12},
+		{0, 40},
+		{0, 5},   // wraps
+		{10, 2},  // 5 dropped: next is 12, now at index 2
+		{10, 30}, // 40
+		{10, 2},  // wraps, skipping 5
+		{50, -1}, // all dropped
+	}
+	next := 0
+	for i, s := range steps {
+		var target int
+		target, next = nextJump(positions, next, s.dropped)
+		if target != s.wantTarget {
+			t.Errorf("step %d: target = %d, want %d", i, target, s.wantTarget)
+		}
+	}
+}
+
+func TestServerAppendLogAtCountsDropped(t *testing.T) {
+	gui := &ServerGUI{}
+	for i := 0; i < 1000; i++ {
+		gui.appendLog([]string{fmt.Sprintf("line %d", i)})
+	}
+	var tally opTally
+	gui.logItemError(&tally, "Failed to restart web-2: boom")
+	if gui.logDropped != 1 {
+		t.Fatalf("logDropped = %d, want 1", gui.logDropped)
+	}
+	if len(tally.errors) != 1 || tally.errors[0] != 1000 {
+		t.Fatalf("errors = %v, want [1000]", tally.errors)
+	}
+	for i := 0; i < 20; i++ {
+		gui.appendLog([]string{"later"})
+	}
+	gui.finishTally("Restart", &tally)
+	if err := gui.keyJumpError(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := 1000 - gui.logDropped; gui.logScroll != want {
+		t.Errorf("logScroll = %d, want %d (the error line)", gui.logScroll, want)
+	}
+	if got := stripANSI(gui.logLines[gui.logScroll].text); got != iconError+" Failed to restart web-2: boom" {
+		t.Errorf("jumped to %q", got)
+	}
+}
@@ -0,0 +1,208 @@
+package gui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jroimartin/gocui"
+)
+
+const viewPager = "pager"
+
+// pager is the read-only overlay showing truncated Output lines in full,
+// shared by project and server mode. Valid JSON is indented; escape codes
+// are stripped so wrapping cannot split them.
+type pager struct {
+	texts []string // untruncated lines, oldest first
+	pos   int      // line shown
+	lines []string // texts[pos] wrapped for width
+	width int
+	top   int
+	page  int    // body height at the last render
+	title string // "" for the truncated Output lines
+}
+
+// newPager opens on the newest line; nil when there is nothing to show.
+func newPager(texts []string) *pager {
+	if len(texts) == 0 {
+		return nil
+	}
+	return &pager{texts: append([]string(nil), texts...), pos: len(texts) - 1}
+}
+
+// pagerText is the text shown for one stored line.
+func pagerText(line string) string {
+	line = stripANSI(line)
+	trimmed := strings.TrimSpace(line)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		var buf bytes.Buffer
+		if json.Indent(&buf, []byte(trimmed), "", "  ") == nil {
+			return buf.String()
+		}
+	}
+	return line
+}
+
+// wrapRunes hard-wraps text at width runes per line.
+func wrapRunes(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		for utf8.RuneCountInString(line) > width {
+			i, n := 0, 0
+			for n < width {
+				_, size := utf8.DecodeRuneInString(line[i:])
+				i += size
+				n++
+			}
+			out = append(out, line[:i])
+			line = line[i:]
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// layout wraps the current line for width, keeping the scroll position.
+func (p *pager) layout(width int) {
+	if p.lines != nil && p.width == width {
+		return
+	}
+	p.lines, p.width = wrapRunes(pagerText(p.texts[p.pos]), width), width
+}
+
+func (p *pager) scroll(delta int) {
+	p.top += delta
+	if max := len(p.lines) - p.page; p.top > max {
+		p.top = max
+	}
+	if p.top < 0 {
+		p.top = 0
+	}
+}
+
+// show switches to the stored line delta away from the current one.
+func (p *pager) show(delta int) {
+	pos := p.pos + delta
+	if pos < 0 || pos >= len(p.texts) {
+		return
+	}
+	p.pos, p.lines, p.top = pos, nil, 0
+}
+
+func renderPager(g *gocui.Gui, p *pager) error {
+	maxX, maxY := g.Size()
+	r := rect{x0: 1, y0: 1, x1: maxX - 2, y1: maxY - 2}
+	if !r.valid() {
+		return nil
+	}
+	v, err := setView(g, viewPager, r)
+	if err != nil && err != gocui.ErrUnknownView {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	v.Frame = true
+	v.Wrap = false
+	width, height := v.Size()
+	p.layout(width - 1)
+	p.page = height - 2
+	if p.page < 1 {
+		p.page = 1
+	}
+	p.scroll(0)
+	title := p.title
+	if title == "" {
+		title = "Long line"
+	}
+	v.Title = glyphs(fmt.Sprintf(" %s %d/%d · %s ", title, p.pos+1, len(p.texts), formatBytes(int64(len(p.texts[p.pos])))))
+	v.Clear()
+	end := p.top + p.page
+	if end > len(p.lines) {
+		end = len(p.lines)
+	}
+	for _, l := range p.lines[p.top:end] {
+		fmt.Fprintln(v, " "+l)
+	}
+	for i := end - p.top; i < p.page; i++ {
+		fmt.Fprintln(v)
+	}
+	fmt.Fprintln(v)
+	fmt.Fprint(v, dim(fmt.Sprintf(" %d-%d of %d  j/k scroll  PgUp/PgDn page  g/G, Home/End top/end  n/p next/prev  Esc close", p.top+1, end, len(p.lines))))
+	g.SetCurrentView(viewPager)
+	return nil
+}
+
+// bindPagerKeys binds scrolling on the pager view. Closing is left to the
+// modes' own Esc and expand handlers so a single key press is handled once.
+func bindPagerKeys(g *gocui.Gui, get func() *pager) error {
+	bind := func(key interface{}, fn func(p *pager)) error {
+		return g.SetKeybinding(viewPager, key, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+			if p := get(); p != nil {
+				fn(p)
+			}
+			return nil
+		})
+	}
+	keys := []struct {
+		key interface{}
+		fn  func(p *pager)
+	}{
+		{'j', func(p *pager) { p.scroll(1) }},
+		{'k', func(p *pager) { p.scroll(-1) }},
+		{gocui.KeyArrowDown, func(p *pager) { p.scroll(1) }},
+		{gocui.KeyArrowUp, func(p *pager) { p.scroll(-1) }},
+		{gocui.KeyPgdn, func(p *pager) { p.scroll(p.page) }},
+		{gocui.KeyPgup, func(p *pager) { p.scroll(-p.page) }},
+		{gocui.KeySpace, func(p *pager) { p.scroll(p.page) }},
+		{'g', func(p *pager) { p.top = 0 }},
+		{'G', func(p *pager) { p.top = len(p.lines) }},
+		{gocui.KeyHome, func(p *pager) { p.top = 0 }},
+		{gocui.KeyEnd, func(p *pager) { p.top = len(p.lines) }},
+		{'n', func(p *pager) { p.show(1) }},
+		{'p', func(p *pager) { p.show(-1) }},
+	}
+	for _, k := range keys {
+		if err := bind(k.key, k.fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keyExpandLine opens the pager on the newest truncated Output line, or
+// closes it.
+func (gui *GUI) keyExpandLine(g *gocui.Gui, v *gocui.View) error {
+	switch gui.screen {
+	case ScreenPager:
+		gui.closePager()
+		return nil
+	case ScreenEditor, ScreenHelp, ScreenConfirm, ScreenPicker, ScreenInput:
+		return nil
+	}
+	gui.logMu.Lock()
+	var p *pager
+	if gui.long != nil {
+		p = newPager(gui.long.texts)
+	}
+	gui.logMu.Unlock()
+	if p == nil {
+		gui.logInfo("No truncated line to expand")
+		return nil
+	}
+	gui.pager, gui.pagerPrev, gui.screen = p, gui.screen, ScreenPager
+	return nil
+}
+
+func (gui *GUI) closePager() {
+	gui.pager = nil
+	gui.screen = gui.pagerPrev
+	gui.g.DeleteView(viewPager)
+	gui.g.SetCurrentView(viewMain)
+}
@@ -0,0 +1,132 @@
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// pauseBufLimit bounds how many streamed lines are held while a stream is paused.
+const pauseBufLimit = 5000
+
+// logPause freezes the visible log during live streaming while the stream keeps
+// being consumed into a bounded side buffer.
+type logPause struct {
+	mu      sync.Mutex
+	paused  bool
+	pending []string
+	dropped int
+	limit   int
+}
+
+func newLogPause(limit int) *logPause {
+	return &logPause{limit: limit}
+}
+
+// Pause starts buffering incoming lines instead of showing them.
+func (p *logPause) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+// IsPaused reports whether incoming lines are currently being held back.
+func (p *logPause) IsPaused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Offer buffers line if the view is paused. It returns true when the line was
+// held back, in which case the caller must not append it to the visible log.
+func (p *logPause) Offer(line string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.paused {
+		return false
+	}
+	p.pending = append(p.pending, line)
+	if len(p.pending) > p.limit {
+		over := len(p.pending) - p.limit
+		p.pending = p.pending[over:]
+		p.dropped += over
+	}
+	return true
+}
+
+// Buffered returns the number of lines held back and the number dropped
+// because the side buffer overflowed.
+func (p *logPause) Buffered() (held, dropped int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pending), p.dropped
+}
+
+// Resume stops buffering and returns the held-back lines and the overflow count.
+func (p *logPause) Resume() (lines []string, dropped int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lines, dropped = p.pending, p.dropped
+	p.paused = false
+	p.pending = nil
+	p.dropped = 0
+	return lines, dropped
+}
+
+// Reset discards any paused state, e.g. when a stream ends.
+func (p *logPause) Reset() {
+	_, _ = p.Resume()
+}
+
+// Label returns the title fragment shown while paused, e.g. "PAUSED (1,204 lines buffered)".
+func (p *logPause) Label() string {
+	held, _ := p.Buffered()
+	return fmt.Sprintf("PAUSED (%s lines buffered)", formatCount(held))
+}
+
+// clearedMarker is inserted when the log is cleared while a stream is running.
+func clearedMarker(stamp string) string {
+	return dim("── cleared at " + stamp + " ──")
+}
+
+// clampLogWindow clamps *scroll to the valid range for n lines shown in a view
+// of the given height and returns the visible [start, end) range. Callers must
+// hold the log mutex that guards *scroll.
+func clampLogWindow(scroll *int, n, height int) (start, end int) {
+	if height < 1 {
+		height = 1
+	}
+	maxScroll := n - height
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if *scroll > maxScroll {
+		*scroll = maxScroll
+	}
+	if *scroll < 0 {
+		*scroll = 0
+	}
+	start = *scroll
+	end = start + height
+	if end > n {
+		end = n
+	}
+	return start, end
+}
+
+// formatCount formats n with thousands separators (1204 -> "1,204").
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	neg := false
+	if n < 0 {
+		neg = true
+		s = s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
@@ -0,0 +1,144 @@
+package gui
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogPauseOffer(t *testing.T) {
+	p := newLogPause(3)
+	if p.Offer("a") {
+		t.Fatal("Offer() should not buffer while not paused")
+	}
+
+	p.Pause()
+	for _, l := range []string{"1", "2", "3", "4", "5"} {
+		if !p.Offer(l) {
+			t.Fatalf("Offer(%q) should buffer while paused", l)
+		}
+	}
+	held, dropped := p.Buffered()
+	if held != 3 || dropped != 2 {
+		t.Errorf("Buffered() = (%d, %d), want (3, 2)", held, dropped)
+	}
+
+	lines, dropped := p.Resume()
+	if len(lines) != 3 || lines[0] != "3" || lines[2] != "5" {
+		t.Errorf("Resume() lines = %v, want [3 4 5]", lines)
+	}
+	if dropped != 2 {
+		t.Errorf("Resume() dropped = %d, want 2", dropped)
+	}
+	if p.IsPaused() {
+		t.Error("IsPaused() should be false after Resume()")
+	}
+	if held, _ := p.Buffered(); held != 0 {
+		t.Errorf("Buffered() after Resume() = %d, want 0", held)
+	}
+}
+
+func TestLogPauseLabel(t *testing.T) {
+	p := newLogPause(pauseBufLimit)
+	p.Pause()
+	for i := 0; i < 1204; i++ {
+		p.Offer("line")
+	}
+	if got, want := p.Label(), "PAUSED (1,204 lines buffered)"; got != want {
+		t.Errorf("Label() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1,000"},
+		{1204, "1,204"},
+		{1234567, "1,234,567"},
+		{-4500, "-4,500"},
+	}
+	for _, tt := range tests {
+		if got := formatCount(tt.n); got != tt.want {
+			t.Errorf("formatCount(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestClampLogWindow(t *testing.T) {
+	tests := []struct {
+		name                 string
+		scroll, n, height    int
+		wantStart, wantEnd   int
+		wantScrollAfterClamp int
+	}{
+		{"fits", 0, 5, 10, 0, 5, 0},
+		{"follow past end", 100, 50, 10, 40, 50, 40},
+		{"negative", -3, 50, 10, 0, 10, 0},
+		{"middle", 20, 50, 10, 20, 30, 20},
+		{"empty", 7, 0, 10, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scroll := tt.scroll
+			start, end := clampLogWindow(&scroll, tt.n, tt.height)
+			if start != tt.wantStart || end != tt.wantEnd || scroll != tt.wantScrollAfterClamp {
+				t.Errorf("clampLogWindow(%d, %d, %d) = [%d,%d) scroll=%d, want [%d,%d) scroll=%d",
+					tt.scroll, tt.n, tt.height, start, end, scroll, tt.wantStart, tt.wantEnd, tt.wantScrollAfterClamp)
+			}
+		})
+	}
+}
+
+func TestServerLogAppendClearScrollConcurrent(t *testing.T) {
+	gui := &ServerGUI{logPause: newLogPause(pauseBufLimit), streamingLogs: true}
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				gui.appendLog([]string{"line"})
+			}
+		}()
+	}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			gui.clearLog()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = gui.keyScrollUp(nil, nil)
+			_ = gui.keyScrollDown(nil, nil)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			gui.logMu.Lock()
+			start, end := clampLogWindow(&gui.logScroll, len(gui.logLines), 10)
+			if start < 0 || end > len(gui.logLines) || start > end {
+				t.Errorf("invalid window [%d,%d) for %d lines", start, end, len(gui.logLines))
+			}
+			gui.logMu.Unlock()
+		}
+	}()
+	wg.Wait()
+
+	gui.clearLog()
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	if len(gui.logLines) != 1 || !strings.Contains(gui.logLines[0].render(zoneLocal, 0), "cleared at") {
+		t.Errorf("clear during stream should leave only the marker, got %v", gui.logLines)
+	}
+	if gui.logScroll != len(gui.logLines) {
+		t.Errorf("clear should reset to follow mode, logScroll = %d", gui.logScroll)
+	}
+}
@@ -0,0 +1,101 @@
+package gui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+
+	"github.com/shuvro/lazykamal/pkg/hooks"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// statusExtraLines caps how many stdout lines each status_extra plugin adds
+// to the Live status panel.
+const statusExtraLines = 5
+
+// pluginPayload starts a payload for hook about dest, which may be nil.
+func (gui *GUI) pluginPayload(hook string, dest *kamal.DeployDestination) hooks.Payload {
+	p := hooks.NewPayload(hook, gui.cwd)
+	if dest != nil {
+		p.App = dest.Label()
+		p.Destination = dest.Name
+	}
+	return p
+}
+
+// firePlugins runs the commands registered for an event hook point in the
+// background, one after another. Their output is not shown. A failure is
+// logged as a warning and never affects the command that triggered it.
+func (gui *GUI) firePlugins(cmds []kamal.HookCommand, p hooks.Payload) {
+	if len(cmds) == 0 {
+		return
+	}
+	go func() {
+		for _, c := range cmds {
+			if _, res := hooks.RunPlugin(gui.cwd, c.Run, c.Timeout, p, 0); !res.Passed() {
+				gui.appendLog([]string{statusLine("warning", "Plugin "+p.Hook+": "+c.Run+": "+res.Reason())})
+			}
+		}
+	}()
+}
+
+// checkStatusExtra runs the status_extra plugins for dest in the background
+// from the status poll, at most one run per destination config at a time,
+// and keeps their output for statusExtraFor. Plugins may take up to their
+// timeout each, which must not hold up the poll.
+func (gui *GUI) checkStatusExtra(dest *kamal.DeployDestination, versions map[string]string) {
+	cmds := gui.projectConfig().Plugins.StatusExtra
+	if len(cmds) == 0 {
+		return
+	}
+	key := hostsKey(dest)
+	gui.statusMu.Lock()
+	if gui.statusExtraBusy[key] || !gui.statusExtras.claim(key, time.Now(), statusPoll) {
+		gui.statusMu.Unlock()
+		return
+	}
+	if gui.statusExtraBusy == nil {
+		gui.statusExtraBusy = map[string]bool{}
+	}
+	gui.statusExtraBusy[key] = true
+	gui.statusMu.Unlock()
+	p := gui.pluginPayload(hooks.StatusExtra, dest)
+	p.Versions = versions
+	go func() {
+		outputs := make([][]string, len(cmds))
+		results := make([]hooks.Result, len(cmds))
+		for i, c := range cmds {
+			outputs[i], results[i] = hooks.RunPlugin(gui.cwd, c.Run, c.Timeout, p, statusExtraLines)
+		}
+		gui.statusExtras.set(key, statusExtraText(outputs, results), time.Now())
+		gui.statusMu.Lock()
+		delete(gui.statusExtraBusy, key)
+		gui.statusMu.Unlock()
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+	}()
+}
+
+// statusExtraFor is what the status_extra plugins last added to the Live
+// status panel for dest, or "" before their first run.
+func (gui *GUI) statusExtraFor(dest *kamal.DeployDestination) string {
+	extra, _ := gui.statusExtras.current(hostsKey(dest))
+	return extra
+}
+
+// statusExtraText renders status_extra output: each plugin's lines without
+// color codes and with secrets redacted, or a dim note in their place when
+// it failed.
+func statusExtraText(outputs [][]string, results []hooks.Result) string {
+	var b strings.Builder
+	for i, res := range results {
+		if !res.Passed() {
+			b.WriteString(" " + dim(res.Command+": "+res.Reason()) + "\n")
+			continue
+		}
+		for _, l := range outputs[i] {
+			b.WriteString(" " + cleanOutput(l, ansiStrip) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/hooks"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestStatusExtraText(t *testing.T) {
+	outputs := [][]string{
+		{"Sidekiq: 12 enqueued", "\x1b[31mRetries: 3\x1b[0m"},
+		nil,
+		{"DATABASE_URL=postgres://app:hunter2@db/app"},
+	}
+	results := []hooks.Result{
+		{Command: "scripts/sidekiq.sh"},
+		{Command: "scripts/broken.sh", ExitCode: 2},
+		{Command: "scripts/env.sh"},
+	}
+	want := " Sidekiq: 12 enqueued\n" +
+		" Retries: 3\n" +
+		" scripts/broken.sh: exit 2\n" +
+		" " + sanitizeLogLine("DATABASE_URL=postgres://app:hunter2@db/app") + "\n"
+	if got := stripANSI(statusExtraText(outputs, results)); got != want {
+		t.Errorf("statusExtraText() =\n%q\nwant\n%q", got, want)
+	}
+	if got := statusExtraText(nil, nil); got != "" {
+		t.Errorf("no plugins: %q, want empty", got)
+	}
+}
+
+func TestPluginPayload(t *testing.T) {
+	gui := &GUI{cwd: "/src/myapp"}
+	p := gui.pluginPayload(hooks.OnCommandError, nil)
+	if p.Schema != hooks.PayloadSchema || p.Hook != hooks.OnCommandError || p.Project != "/src/myapp" || p.App != "" {
+		t.Errorf("payload without destination = %+v", p)
+	}
+}
+
+func TestSlowStatusExtraDoesNotHoldUpThePoll(t *testing.T) {
+	gui := newFakeGUI(t, &kamal.FakeRunner{})
+	gui.project = &kamal.ProjectConfig{Plugins: kamal.Plugins{StatusExtra: []kamal.HookCommand{{Run: "sleep 1; echo 'Sidekiq: 12 enqueued'"}}}}
+	dest := gui.selectedDestination()
+
+	start := time.Now()
+	gui.pollStatus(dest)
+	if took := time.Since(start); took > 500*time.Millisecond {
+		t.Errorf("pollStatus took %v, waiting on the plugin", took)
+	}
+	if extra := gui.statusExtraFor(dest); extra != "" {
+		t.Errorf("plugin output %q before it finished", extra)
+	}
+	gui.pollStatus(dest) // the first run is still going; no second one starts
+
+	deadline := time.Now().Add(5 * time.Second)
+	for gui.statusExtraFor(dest) == "" && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := gui.statusExtraFor(dest); !strings.Contains(got, "Sidekiq: 12 enqueued") {
+		t.Errorf("statusExtraFor() = %q, want the plugin's output", got)
+	}
+}
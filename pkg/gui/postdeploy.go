@@ -0,0 +1,128 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/events"
+	"github.com/shuvro/lazykamal/pkg/hooks"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// runPostDeploy runs the post_deploy commands from .lazykamal.yml after a
+// successful deploy and logs a pass/fail verdict. previous and version are
+// the version labels before and after the deploy. On failure Rollback is
+// pre-filled with the previous version, or run right away when
+// post_deploy_rollback is set.
+func (gui *GUI) runPostDeploy(opts kamal.RunOptions, previous, version string, stopCh <-chan struct{}) {
+	if gui.project == nil || len(gui.project.PostDeploy) == 0 {
+		return
+	}
+	verdict := gui.postDeployChecks(opts, previous, version, stopCh)
+	gui.events.Publish(events.Event{
+		Type:        events.PostDeploy,
+		Destination: gui.eventDestination(),
+		Version:     version,
+		Success:     events.Result(verdict.Passed()),
+		Error:       failureSummary(verdict),
+	})
+	key := ""
+	if dest := gui.selectedDestination(); dest != nil {
+		key = hostsKey(dest)
+	}
+	if verdict.Passed() {
+		gui.appendLog([]string{green("  " + iconSuccess + " Post-deploy: " + verdict.Summary())})
+		gui.setRollbackVersion(key, "")
+		return
+	}
+	gui.appendLog([]string{red("  " + iconError + " Post-deploy FAILED: " + verdict.Summary())})
+
+	target := rollbackCandidate(previous)
+	if target == "" || target == version {
+		gui.appendLog([]string{yellow("  Previous version unknown; check the app before rolling back.")})
+		return
+	}
+	if gui.project.PostDeployRollback {
+		gui.logInfo("post_deploy_rollback: rolling back to " + target)
+		res, err := kamal.RunKamalWithStop([]string{"rollback", target}, opts, stopCh)
+		if err != nil {
+			gui.logError("Rollback failed: " + err.Error())
+			return
+		}
+		gui.appendLogFromResult(res)
+		if res.ExitCode == 0 {
+			gui.logSuccess("Rolled back to " + target)
+			gui.verifyRollback(opts, target, stopCh)
+		} else {
+			gui.logError(fmt.Sprintf("Rollback failed (exit %d)", res.ExitCode))
+		}
+		return
+	}
+	gui.setRollbackVersion(key, target)
+	gui.appendLog([]string{yellow(bold("  " + iconArrow + " Consider Deploy > Rollback (pre-filled: " + target + ")"))})
+}
+
+// postDeployChecks runs the post_deploy commands in order, echoing their
+// output, until one is cancelled.
+func (gui *GUI) postDeployChecks(opts kamal.RunOptions, previous, version string, stopCh <-chan struct{}) hooks.Verdict {
+	env := []string{
+		"LAZYKAMAL_DESTINATION=" + opts.Destination,
+		"LAZYKAMAL_VERSION=" + version,
+		"LAZYKAMAL_PREVIOUS_VERSION=" + previous,
+	}
+	var verdict hooks.Verdict
+	for _, h := range gui.project.PostDeploy {
+		gui.logInfo("Post-deploy: " + h.Run)
+		res := hooks.Run(gui.cwd, h.Run, h.Timeout, env, func(line string) {
+			gui.appendLog([]string{"  " + cleanOutput(line, gui.ansi)})
+		}, stopCh)
+		verdict.Results = append(verdict.Results, res)
+		if res.Cancelled {
+			break
+		}
+	}
+	return verdict
+}
+
+// failureSummary is the verdict summary when it failed, "" otherwise.
+func failureSummary(v hooks.Verdict) string {
+	if v.Passed() {
+		return ""
+	}
+	return v.Summary()
+}
+
+// rollbackCandidate returns label when it names a single version.
+func rollbackCandidate(label string) string {
+	if label == "" || label == "unknown" || strings.Contains(label, ", ") {
+		return ""
+	}
+	return label
+}
+
+// setRollbackVersion pre-fills (or with "" clears) Rollback for the
+// destination config key. It is safe to call from command goroutines.
+func (gui *GUI) setRollbackVersion(key, version string) {
+	if key == "" {
+		return
+	}
+	gui.g.Update(func(*gocui.Gui) error {
+		if version == "" {
+			delete(gui.rollbackTo, key)
+		} else {
+			gui.rollbackTo[key] = version
+		}
+		return nil
+	})
+}
+
+// rollbackVersion is the version Rollback is pre-filled with for the
+// selected destination, if any.
+func (gui *GUI) rollbackVersion() string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return ""
+	}
+	return gui.rollbackTo[hostsKey(dest)]
+}
@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"sync"
+	"time"
+)
+
+// probe holds the last result of a status-panel check per destination
+// config, e.g. `kamal proxy details`, and spaces the checks out: the status
+// poll claims a key, runs the check and sets the result when it succeeds.
+// The zero value is ready to use.
+type probe[T any] struct {
+	mu     sync.Mutex
+	checks map[string]probeCheck[T]
+}
+
+type probeCheck[T any] struct {
+	value   T
+	ok      bool // a check succeeded; value is meaningful
+	checked time.Time
+}
+
+// claim reports whether key is due for a check, its last one being every
+// or more ago, and if so marks it checked so a failed or concurrent check
+// waits for the next recheck.
+func (p *probe[T]) claim(key string, now time.Time, every time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.checks == nil {
+		p.checks = map[string]probeCheck[T]{}
+	}
+	c, seen := p.checks[key]
+	if seen && now.Sub(c.checked) < every {
+		return false
+	}
+	c.checked = now
+	p.checks[key] = c
+	return true
+}
+
+func (p *probe[T]) set(key string, value T, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.checks == nil {
+		p.checks = map[string]probeCheck[T]{}
+	}
+	p.checks[key] = probeCheck[T]{value: value, ok: true, checked: now}
+}
+
+// current returns the last successful result for key.
+func (p *probe[T]) current(key string) (T, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := p.checks[key]
+	return c.value, c.ok
+}
+
+// forget drops every result so the next poll checks again, after a command
+// that may have changed what the probe reports.
+func (p *probe[T]) forget() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks = nil
+}
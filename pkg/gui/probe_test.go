@@ -0,0 +1,33 @@
+package gui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestProbe(t *testing.T) {
+	var p probe[[]kamal.ProxyHost]
+	now := time.Now()
+	if !p.claim("a", now, proxyRecheck) {
+		t.Fatal("first claim = false")
+	}
+	if p.claim("a", now.Add(proxyRecheck/2), proxyRecheck) {
+		t.Error("claim within proxyRecheck = true")
+	}
+	if _, ok := p.current("a"); ok {
+		t.Error("current() before set reports a result")
+	}
+	p.set("a", []kamal.ProxyHost{{Running: true}}, now)
+	if hosts, ok := p.current("a"); !ok || len(hosts) != 1 {
+		t.Errorf("current() = %v, %v", hosts, ok)
+	}
+	if !p.claim("b", now, proxyRecheck) {
+		t.Error("claim for another destination = false")
+	}
+	p.forget()
+	if !p.claim("a", now.Add(time.Second), proxyRecheck) {
+		t.Error("claim after forget = false")
+	}
+}
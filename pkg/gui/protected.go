@@ -0,0 +1,154 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// needsTypedConfirm reports whether the command must be confirmed by typing
+// the destination name: a mutating one on a protected destination.
+func needsTypedConfirm(dest *kamal.DeployDestination, readOnly bool) bool {
+	return dest != nil && dest.Protected && !readOnly
+}
+
+// removalCommands remove containers, images or data for good. On a
+// destination named production their confirm dialog needs a typed name.
+var removalCommands = map[string]bool{
+	"App Remove":           true,
+	"Proxy Remove":         true,
+	"Accessory Remove All": true,
+	"Prune All":            true,
+	"Prune Images":         true,
+	"Prune Containers":     true,
+}
+
+// deployCommands ship or roll back a version. On a production destination
+// they are confirmed even when nothing else asks.
+var deployCommands = map[string]bool{
+	"Deploy":              true,
+	"Deploy (skip push)":  true,
+	"Deploy (no cache)":   true,
+	"Redeploy":            true,
+	"Redeploy (no cache)": true,
+	"Setup":               true,
+	"Setup (no cache)":    true,
+	"Rollback":            true,
+}
+
+// isProduction reports whether dest is named production or listed in
+// protected_destinations.
+func isProduction(dest *kamal.DeployDestination) bool {
+	return dest != nil && (dest.Protected || strings.EqualFold(dest.Name, "production"))
+}
+
+// productionMessage is the confirm message of name on dest: "PRODUCTION: "
+// first when it deploys or rolls back production. An empty message asks
+// whether to run name on dest.
+func productionMessage(dest *kamal.DeployDestination, name, message string) string {
+	if message == "" {
+		message = name + "?"
+		if dest != nil {
+			message = name + " to " + dest.Label() + "?"
+		}
+	}
+	if isProduction(dest) && deployCommands[name] {
+		return "PRODUCTION: " + message
+	}
+	return message
+}
+
+// productionConfirmText is what must be typed to confirm name on dest:
+// the service name, or "production" without one. It is "" when a single
+// key confirms it.
+func productionConfirmText(dest *kamal.DeployDestination, name string) string {
+	if !isProduction(dest) || !removalCommands[name] {
+		return ""
+	}
+	if dest.Service != "" {
+		return dest.Service
+	}
+	return "production"
+}
+
+// confirmProtected asks for the destination name to be typed before run.
+func (gui *GUI) confirmProtected(dest *kamal.DeployDestination, name, message string, run func()) {
+	if message == "" {
+		message = name + "?"
+	}
+	gui.showPicker(&listPicker{
+		Title:   "Protected: " + dest.Label(),
+		Message: fmt.Sprintf("%s is a protected destination. %s", dest.Name, message),
+		Expect:  dest.Name,
+		Adding:  true,
+		OnDone: func([]string) {
+			gui.confirmNote = "typed " + dest.Name
+			run()
+			gui.confirmNote = ""
+		},
+	})
+}
+
+// loadDestinations discovers destinations, applies .lazykamal.yml and the
+// session filters, keeping the selected destination when it is still listed.
+func (gui *GUI) loadDestinations() {
+	dests, err := kamal.FindDeployConfigs(gui.cwd)
+	if err != nil {
+		return
+	}
+	cfg, err := kamal.LoadProjectConfig(gui.cwd)
+	if err != nil {
+		gui.appendLog([]string{statusLine("warning", err.Error())})
+	}
+	for _, w := range cfg.CommandWarnings() {
+		gui.appendLog([]string{statusLine("warning", w)})
+	}
+	gui.project = cfg
+	gui.discovered = dests
+	gui.applyDestinationFilters()
+}
+
+// createProjectConfig writes the .lazykamal.yml template, reloads it and
+// reports anything that would be ignored, then opens it in the editor. An
+// existing file is left alone.
+func (gui *GUI) createProjectConfig() {
+	path, err := kamal.WriteProjectConfigTemplate(gui.cwd, false)
+	if errors.Is(err, kamal.ErrProjectConfigExists) {
+		gui.logInfo(path + " already exists; edit it, or run lazykamal init-config --force to replace it")
+		return
+	}
+	if err != nil {
+		gui.logError("Could not create " + kamal.ProjectConfigFile + ": " + err.Error())
+		return
+	}
+	gui.logSuccess("Created " + path)
+	if warnings, err := kamal.CheckProjectConfig(gui.cwd); err != nil {
+		gui.appendLog([]string{statusLine("warning", err.Error())})
+	} else {
+		for _, w := range warnings {
+			gui.appendLog([]string{statusLine("warning", w)})
+		}
+	}
+	gui.loadDestinations()
+	if gui.openEditor(path) {
+		gui.appendLog([]string{"Editing " + path + " (^S save, ^Q/Esc quit)"})
+	}
+}
+
+func (gui *GUI) keyShowAll(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ScreenApps {
+		return nil
+	}
+	gui.showAllDests = !gui.showAllDests
+	gui.loadDestinations()
+	if gui.showAllDests {
+		gui.logInfo(fmt.Sprintf("Showing all destinations (%d hidden by %s)", gui.hiddenDests, kamal.ProjectConfigFile))
+	} else {
+		gui.logInfo("Hiding destinations listed in " + kamal.ProjectConfigFile)
+	}
+	gui.resetStatus()
+	return nil
+}
@@ -0,0 +1,143 @@
+package gui
+
+import (
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestNeedsTypedConfirm(t *testing.T) {
+	protected := &kamal.DeployDestination{Name: "production", Protected: true}
+	plain := &kamal.DeployDestination{Name: "staging"}
+	tests := []struct {
+		dest     *kamal.DeployDestination
+		readOnly bool
+		want     bool
+	}{
+		{protected, false, true},
+		{protected, true, false},
+		{plain, false, false},
+		{nil, false, false},
+	}
+	for _, tt := range tests {
+		if got := needsTypedConfirm(tt.dest, tt.readOnly); got != tt.want {
+			t.Errorf("needsTypedConfirm(%+v, %v) = %v, want %v", tt.dest, tt.readOnly, got, tt.want)
+		}
+	}
+}
+
+func TestReadOnlyMenuItemsOnAProtectedDestination(t *testing.T) {
+	tests := []struct {
+		name     string
+		screen   Screen
+		idx      int
+		readOnly bool
+	}{
+		{"Secrets Fetch", ScreenSecrets, 0, true},
+		{"Secrets Extract", ScreenSecrets, 1, true},
+		{"Secrets Print", ScreenSecrets, 2, true},
+		{"App Logs", ScreenApp, 4, true},
+		{"Accessory Details All", ScreenAccessory, 6, true},
+		{"App Restart", ScreenApp, 3, false},
+		{"Server Bootstrap", ScreenServer, 0, false},
+	}
+	for _, tt := range tests {
+		runner := &kamal.FakeRunner{}
+		gui := newFakeGUI(t, runner)
+		gui.journal = newJournal("")
+		gui.selectedDestination().Protected = true
+		gui.screen, gui.submenuIdx = tt.screen, tt.idx
+		if item, _ := gui.selectedMenuItem(); item.ReadOnly != tt.readOnly {
+			t.Errorf("%s: menu item ReadOnly = %v, want %v", tt.name, item.ReadOnly, tt.readOnly)
+		}
+		if err := gui.keyEnter(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if asked := gui.screen == ScreenPicker; asked == tt.readOnly {
+			t.Errorf("%s: typed confirmation asked = %v, want %v", tt.name, asked, !tt.readOnly)
+			continue
+		}
+		if !tt.readOnly {
+			continue
+		}
+		waitIdle(t, gui)
+		if len(runner.Calls()) == 0 {
+			t.Errorf("%s did not run", tt.name)
+		}
+		if got := gui.journal.snapshot(); len(got) != 0 {
+			t.Errorf("%s journaled as mutating: %+v", tt.name, got)
+		}
+	}
+}
+
+func TestProductionConfirmText(t *testing.T) {
+	production := &kamal.DeployDestination{Service: "myapp", Name: "production"}
+	tests := []struct {
+		dest *kamal.DeployDestination
+		name string
+		want string
+	}{
+		{production, "App Remove", "myapp"},
+		{production, "Accessory Remove All", "myapp"},
+		{production, "Prune All", "myapp"},
+		{&kamal.DeployDestination{Name: "Production"}, "Proxy Remove", "production"},
+		{production, "App Stop", ""},
+		{production, "Accessory Remove redis", ""},
+		{&kamal.DeployDestination{Service: "myapp", Name: "staging"}, "App Remove", ""},
+		{nil, "App Remove", ""},
+	}
+	for _, tt := range tests {
+		if got := productionConfirmText(tt.dest, tt.name); got != tt.want {
+			t.Errorf("productionConfirmText(%+v, %q) = %q, want %q", tt.dest, tt.name, got, tt.want)
+		}
+	}
+
+	gui := &GUI{screen: ScreenApp, destinations: []kamal.DeployDestination{*production}}
+	gui.runWithConfirm("App Remove", "Remove the application?", nil)
+	if gui.confirm == nil || gui.confirm.RequireText != "myapp" {
+		t.Fatalf("App Remove on production: confirm = %+v", gui.confirm)
+	}
+	gui.confirm.typeRune('m')
+	gui.confirm.resolve(confirmDismissed, "esc")
+	gui.confirm = nil
+	gui.runWithConfirm("App Stop", "Stop the application?", nil)
+	if gui.confirm.RequireText != "" || len(gui.confirm.typed) != 0 {
+		t.Errorf("App Stop after a dismissed removal: %+v", gui.confirm)
+	}
+}
+
+func TestProductionMessage(t *testing.T) {
+	production := &kamal.DeployDestination{Service: "myapp", Name: "production"}
+	live := &kamal.DeployDestination{Service: "myapp", Name: "live", Protected: true}
+	staging := &kamal.DeployDestination{Service: "myapp", Name: "staging"}
+	tests := []struct {
+		dest    *kamal.DeployDestination
+		name    string
+		message string
+		want    string
+	}{
+		{production, "Deploy", "", "PRODUCTION: Deploy to myapp (production)?"},
+		{live, "Redeploy", "", "PRODUCTION: Redeploy to myapp (live)?"},
+		{production, "Rollback", "Rollback to abc123?", "PRODUCTION: Rollback to abc123?"},
+		{production, "App Stop", "Stop the application?", "Stop the application?"},
+		{staging, "Rollback", "Rollback to abc123?", "Rollback to abc123?"},
+		{staging, "Deploy", "", "Deploy to myapp (staging)?"},
+		{nil, "Deploy", "", "Deploy?"},
+	}
+	for _, tt := range tests {
+		if got := productionMessage(tt.dest, tt.name, tt.message); got != tt.want {
+			t.Errorf("productionMessage(%+v, %q) = %q, want %q", tt.dest, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRunCommandThenConfirmsProductionDeploys(t *testing.T) {
+	gui := &GUI{screen: ScreenDeploy, destinations: []kamal.DeployDestination{{Service: "myapp", Name: "production"}}}
+	gui.runCommandThen("Deploy", nil, nil)
+	if gui.screen != ScreenConfirm || gui.confirm == nil || gui.confirm.Message != "PRODUCTION: Deploy to myapp (production)?" {
+		t.Fatalf("Deploy on production: screen = %v, confirm = %+v", gui.screen, gui.confirm)
+	}
+	if gui.prevScreen != ScreenDeploy {
+		t.Errorf("prevScreen = %v, want the Deploy menu", gui.prevScreen)
+	}
+}
@@ -0,0 +1,80 @@
+package gui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// proxyRecheck is how often the status poll re-runs `kamal proxy details`.
+// The proxy changes far less often than the app, and proxy commands run from
+// the TUI invalidate the result anyway.
+const proxyRecheck = time.Minute
+
+// touchesProxy reports whether command name can start, stop or replace
+// kamal-proxy.
+func touchesProxy(name string) bool {
+	return strings.HasPrefix(name, "Proxy ") || strings.HasPrefix(name, "Setup") || strings.HasPrefix(name, "Deploy") || name == removeEverythingName
+}
+
+// checkProxy runs `kamal proxy details` for dest from the status poll when
+// the last check is older than proxyRecheck.
+func (gui *GUI) checkProxy(dest *kamal.DeployDestination, opts kamal.RunOptions) {
+	key := hostsKey(dest)
+	if !gui.proxy.claim(key, time.Now(), proxyRecheck) {
+		return
+	}
+	opts.Defaults = nil // a --verbose default would bury the table in log lines
+	r, err := kamal.ProxyDetails(opts)
+	if err != nil || r.ExitCode != 0 {
+		return
+	}
+	gui.proxy.set(key, kamal.ParseProxyDetails(r.Stdout), time.Now())
+}
+
+// proxyStatusLine renders a proxy details result for the status panel:
+// the running version(s) when the proxy is up on every host, otherwise
+// which hosts lack it and how to start it.
+func proxyStatusLine(hosts []kamal.ProxyHost) string {
+	var down []string
+	seen := map[string]bool{}
+	var versions []string
+	for _, h := range hosts {
+		if !h.Running {
+			name := h.Host
+			if name == "" {
+				name = "host"
+			}
+			down = append(down, name)
+			continue
+		}
+		if h.Version != "" && !seen[h.Version] {
+			seen[h.Version] = true
+			versions = append(versions, h.Version)
+		}
+	}
+	if len(hosts) == 0 || len(down) == len(hosts) {
+		return red(iconError+" not running") + dim(" — Proxy → Boot starts it")
+	}
+	if len(down) > 0 {
+		return red(iconError+" not running on "+strings.Join(down, ", ")) + dim(" — Proxy → Boot starts it")
+	}
+	line := green(iconSuccess + " running")
+	if len(versions) > 0 {
+		sort.Strings(versions)
+		line += " (" + strings.Join(versions, ", ") + ")"
+	}
+	return line
+}
+
+// proxyStatusFor is the status panel's proxy line for dest, or "" before
+// the first successful check.
+func (gui *GUI) proxyStatusFor(dest *kamal.DeployDestination) string {
+	hosts, ok := gui.proxy.current(hostsKey(dest))
+	if !ok {
+		return ""
+	}
+	return " Proxy: " + proxyStatusLine(hosts) + "\n"
+}
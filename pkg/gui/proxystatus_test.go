@@ -0,0 +1,46 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestProxyStatusLine(t *testing.T) {
+	running := []kamal.ProxyHost{
+		{Host: "10.0.0.1", Running: true, Version: "v0.8.2"},
+		{Host: "10.0.0.2", Running: true, Version: "v0.8.2"},
+	}
+	if got := stripANSI(proxyStatusLine(running)); got != iconSuccess+" running (v0.8.2)" {
+		t.Errorf("proxyStatusLine(running) = %q", got)
+	}
+
+	mixed := []kamal.ProxyHost{
+		{Host: "10.0.0.1", Running: true, Version: "v0.8.2"},
+		{Host: "10.0.0.2", Running: true, Version: "v0.7.0"},
+	}
+	if got := stripANSI(proxyStatusLine(mixed)); !strings.Contains(got, "(v0.7.0, v0.8.2)") {
+		t.Errorf("proxyStatusLine(mixed versions) = %q", got)
+	}
+
+	partial := []kamal.ProxyHost{{Host: "10.0.0.1", Running: true}, {Host: "10.0.0.2"}}
+	got := stripANSI(proxyStatusLine(partial))
+	if !strings.Contains(got, "not running on 10.0.0.2") || !strings.Contains(got, "Boot") {
+		t.Errorf("proxyStatusLine(partial) = %q", got)
+	}
+
+	if got := stripANSI(proxyStatusLine(nil)); !strings.HasPrefix(got, iconError+" not running —") {
+		t.Errorf("proxyStatusLine(none) = %q", got)
+	}
+}
+
+func TestTouchesProxy(t *testing.T) {
+	for name, want := range map[string]bool{
+		"Proxy Boot": true, "Setup (no cache)": true, "Deploy": true, "Redeploy": false, "App Boot": false,
+	} {
+		if got := touchesProxy(name); got != want {
+			t.Errorf("touchesProxy(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
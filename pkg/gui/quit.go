@@ -0,0 +1,50 @@
+package gui
+
+import (
+	"github.com/jroimartin/gocui"
+)
+
+// quitBlocker says what quitting now would lose: a command or a live stream
+// that is still running, or "" when nothing is.
+func (gui *GUI) quitBlocker() string {
+	gui.cmdMu.Lock()
+	running, name := gui.running, gui.runningCmd
+	gui.cmdMu.Unlock()
+	if running {
+		return name + " is still running; quitting leaves it unfinished here."
+	}
+	gui.liveLogsMu.Lock()
+	live := gui.liveLogsActive
+	gui.liveLogsMu.Unlock()
+	if live {
+		return "Live logs are still streaming."
+	}
+	return ""
+}
+
+// requestQuit is what q and Ctrl+C do. A dirty editor goes through its own
+// "Quit without saving?" prompt first, and a running command or stream asks
+// before quitting. Ctrl+C pressed again while either prompt (or any other
+// dialog) is open quits; q stands aside there, as it may be typed text.
+func (gui *GUI) requestQuit() error {
+	if gui.screen == ScreenEditor && gui.editor != nil && gui.editor.Dirty && !gui.editor.ConfirmQuit {
+		gui.editor.ConfirmQuit = true
+		gui.editor.ConfirmSave = false
+		return nil
+	}
+	if gui.screen == ScreenConfirm || gui.screen == ScreenEditor {
+		return gocui.ErrQuit
+	}
+	msg := gui.quitBlocker()
+	if msg == "" {
+		return gocui.ErrQuit
+	}
+	gui.prevScreen = gui.screen
+	gui.showConfirm("Quit lazykamal?", msg+"\nQuit anyway? [y/N]", func() {
+		gui.g.Update(quit)
+	}, nil)
+	return nil
+}
+
+// quit is posted to a main loop to end it as if q was pressed.
+func quit(*gocui.Gui) error { return gocui.ErrQuit }
@@ -0,0 +1,42 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jroimartin/gocui"
+)
+
+func TestRequestQuit(t *testing.T) {
+	gui := &GUI{screen: ScreenApps}
+	if err := gui.requestQuit(); err != gocui.ErrQuit {
+		t.Errorf("idle requestQuit = %v, want ErrQuit", err)
+	}
+
+	gui = &GUI{screen: ScreenEditor, editor: &editorState{Dirty: true, PrevScreen: ScreenConfig}}
+	if err := gui.requestQuit(); err != nil || !gui.editor.ConfirmQuit {
+		t.Fatalf("dirty editor: err = %v, ConfirmQuit = %v; want the editor's prompt", err, gui.editor.ConfirmQuit)
+	}
+	if err := gui.requestQuit(); err != gocui.ErrQuit {
+		t.Errorf("second request with the editor prompt open = %v, want ErrQuit", err)
+	}
+
+	gui = &GUI{screen: ScreenDeploy, running: true, runningCmd: "Deploy"}
+	if err := gui.requestQuit(); err != nil {
+		t.Fatalf("requestQuit while running = %v, want a dialog", err)
+	}
+	if gui.screen != ScreenConfirm || gui.confirm == nil || !strings.Contains(gui.confirm.Message, "Deploy is still running") {
+		t.Fatalf("screen = %v, confirm = %+v", gui.screen, gui.confirm)
+	}
+	if gui.prevScreen != ScreenDeploy {
+		t.Errorf("prevScreen = %v, want the Deploy menu", gui.prevScreen)
+	}
+	if err := gui.requestQuit(); err != gocui.ErrQuit {
+		t.Errorf("second request with the dialog open = %v, want ErrQuit", err)
+	}
+
+	gui = &GUI{screen: ScreenApps, liveLogsActive: true}
+	if err := gui.requestQuit(); err != nil || gui.screen != ScreenConfirm {
+		t.Errorf("requestQuit with live logs: err = %v, screen = %v", err, gui.screen)
+	}
+}
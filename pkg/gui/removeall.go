@@ -0,0 +1,154 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// removeEverythingName is the command name of Other > Remove EVERYTHING.
+const removeEverythingName = "Remove Everything"
+
+// removalRecord is what was running on a destination just before Remove
+// EVERYTHING, written next to the project so there is a record of what was
+// torn down.
+type removalRecord struct {
+	Time        time.Time       `json:"time"`
+	App         string          `json:"app"`
+	Hosts       []string        `json:"hosts"`
+	Accessories []string        `json:"accessories"`
+	Status      kamal.AppStatus `json:"status"`
+	Details     string          `json:"details"` // `kamal details` output
+	Errors      []string        `json:"errors,omitempty"`
+}
+
+// removalRecordName is the file the record of dest is written to at now.
+func removalRecordName(dest *kamal.DeployDestination, now time.Time) string {
+	name := dest.Service
+	if dest.Name != "" {
+		name += "-" + dest.Name
+	}
+	return "lazykamal-removed-" + name + "-" + now.Format("20060102-150405") + ".json"
+}
+
+// removeConfirmText is what must be typed before Remove EVERYTHING: the
+// destination name, or the service without one.
+func removeConfirmText(dest *kamal.DeployDestination) string {
+	if dest.Name != "" {
+		return dest.Name
+	}
+	if dest.Service != "" {
+		return dest.Service
+	}
+	return "remove"
+}
+
+// hostNames are the hosts of dest's servers, in config order.
+func hostNames(dest *kamal.DeployDestination) []string {
+	var hosts []string
+	for _, s := range dest.Servers() {
+		hosts = append(hosts, s.Host)
+	}
+	return hosts
+}
+
+// removeEverythingMessage is the second confirmation of Remove EVERYTHING:
+// exactly what `kamal remove` does on dest, and where the record goes.
+func removeEverythingMessage(dest *kamal.DeployDestination, record string) string {
+	hosts := "every host"
+	if h := hostNames(dest); len(h) > 0 {
+		hosts = strings.Join(h, ", ")
+	}
+	lines := []string{
+		"kamal remove will, on " + hosts + ":",
+		"  • stop and remove the " + dest.Service + " app containers and images",
+		"  • stop and remove kamal-proxy, taking down every app it serves there",
+	}
+	if acc := dest.Accessories(); len(acc) > 0 {
+		lines = append(lines, "  • remove the accessories "+strings.Join(acc, ", ")+" with their images and data directories")
+	}
+	lines = append(lines,
+		"  • log out of the registry",
+		"What runs now is saved to "+record+" first.",
+		"This cannot be undone. Remove EVERYTHING on "+dest.Label()+"? [y/N]",
+	)
+	return strings.Join(lines, "\n")
+}
+
+// removeEverything runs `kamal remove` on the selected destination after
+// two confirmations: the destination name typed, then a y/N listing what
+// goes. It records the current status to a file before anything is
+// removed, and is never remembered for . (rerun).
+func (gui *GUI) removeEverything() {
+	selected := gui.selectedDestination()
+	if selected == nil {
+		gui.logError("No app selected")
+		return
+	}
+	d := *selected
+	dest := &d
+	expect := removeConfirmText(dest)
+	gui.showPicker(&listPicker{
+		Title:   "Remove EVERYTHING: " + dest.Label(),
+		Message: fmt.Sprintf("This tears down the app, kamal-proxy and every accessory of %s. Type %s to continue.", dest.Label(), expect),
+		Expect:  expect,
+		Adding:  true,
+		OnDone: func([]string) {
+			record := filepath.Join(gui.cwd, removalRecordName(dest, time.Now()))
+			gui.prevScreen = gui.screen
+			gui.showConfirm("Remove EVERYTHING", removeEverythingMessage(dest, record), func() {
+				if current := gui.selectedDestination(); current == nil || current.ConfigPath != dest.ConfigPath {
+					gui.logError("Remove EVERYTHING cancelled: the selected app changed")
+					return
+				}
+				gui.confirmNote = "typed " + expect + ", then confirmed"
+				gui.startCommand(removeEverythingName, false, gui.removeEverythingRun(dest, record), nil)
+				gui.confirmNote = ""
+			}, nil)
+		},
+	})
+}
+
+// removeEverythingRun records dest's status to record and then runs
+// `kamal remove`. Nothing is removed when the record cannot be written.
+func (gui *GUI) removeEverythingRun(dest *kamal.DeployDestination, record string) func(stopCh <-chan struct{}) (kamal.Result, error) {
+	args := []string{"remove", "--confirmed"}
+	opts := gui.commandOpts(args)
+	remove := gui.streamCommand(args, opts)
+	return func(stopCh <-chan struct{}) (kamal.Result, error) {
+		gui.logInfo("Recording what runs on " + dest.Label() + " before removing it…")
+		snapshot := opts
+		snapshot.OnRun, snapshot.Env, snapshot.Defaults = nil, nil, nil
+		rec := removalRecord{
+			Time:        time.Now().UTC(),
+			App:         dest.Label(),
+			Hosts:       hostNames(dest),
+			Accessories: dest.Accessories(),
+			Status:      kamal.FetchStatus(snapshot, dest),
+		}
+		if r, err := kamal.RunKamalWithStop([]string{"details"}, snapshot, stopCh); err == nil && r.ExitCode == 0 {
+			rec.Details = cleanOutput(r.Combined(), ansiStrip)
+		} else {
+			rec.Errors = append(rec.Errors, "details: "+firstErrorLine(r, err))
+		}
+		select {
+		case <-stopCh:
+			return kamal.Result{ExitCode: -1}, fmt.Errorf("cancelled before anything was removed")
+		default:
+		}
+		data, err := json.MarshalIndent(rec, "", "  ")
+		if err == nil {
+			err = os.WriteFile(record, append(data, '\n'), 0o600)
+		}
+		if err != nil {
+			return kamal.Result{ExitCode: 1}, fmt.Errorf("nothing removed: could not save the record: %w", err)
+		}
+		gui.logSuccess("Saved the record to " + record)
+		return remove(stopCh)
+	}
+}
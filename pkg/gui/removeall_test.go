@@ -0,0 +1,112 @@
+package gui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// typeConfirm types input into the open typed confirmation and presses
+// Enter, reporting whether it was accepted.
+func typeConfirm(gui *GUI, input string) bool {
+	p := gui.picker
+	p.Input = input
+	if !p.confirmTyped() {
+		return false
+	}
+	gui.closePicker()
+	p.OnDone([]string{p.Input})
+	return true
+}
+
+func TestRemoveEverythingNeedsBothConfirmations(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	gui.screen, gui.submenuIdx = ScreenOther, 21
+
+	// A wrong name keeps the dialog open; Esc then closes it.
+	gui.execOther()
+	if gui.picker == nil || gui.picker.Expect != "staging" {
+		t.Fatalf("picker = %+v, want a typed confirmation of staging", gui.picker)
+	}
+	if typeConfirm(gui, "stagin") {
+		t.Fatal("a wrong name was accepted")
+	}
+	gui.closePicker()
+
+	// The right name, then No.
+	gui.execOther()
+	if !typeConfirm(gui, "staging") {
+		t.Fatal("the destination name was refused")
+	}
+	if gui.confirm == nil || !strings.Contains(gui.confirm.Message, "kamal remove will, on 10.0.1.5:") {
+		t.Fatalf("second confirmation = %+v", gui.confirm)
+	}
+	for _, want := range []string{"kamal-proxy", "db, redis", "registry", "lazykamal-removed-demo-staging-"} {
+		if !strings.Contains(gui.confirm.Message, want) {
+			t.Errorf("second confirmation does not mention %q:\n%s", want, gui.confirm.Message)
+		}
+	}
+	gui.answerConfirm(confirmNo, "test")
+	waitIdle(t, gui)
+	if calls := runner.Calls(); len(calls) != 0 {
+		t.Fatalf("ran %q without both confirmations", calls)
+	}
+	if files, _ := filepath.Glob(filepath.Join(gui.cwd, "lazykamal-removed-*")); len(files) != 0 {
+		t.Errorf("record written without both confirmations: %v", files)
+	}
+
+	// Both: the status is recorded first, then kamal remove runs.
+	gui.execOther()
+	typeConfirm(gui, "staging")
+	gui.answerConfirm(confirmYes, "test")
+	waitIdle(t, gui)
+	var got []string
+	for _, c := range runner.Calls() {
+		got = append(got, strings.Join(c, " "))
+	}
+	dest := " --destination staging"
+	want := []string{"app version" + dest, "app containers" + dest, "details" + dest, "remove --confirmed" + dest}
+	// The status poll that follows every command may come after.
+	if !reflect.DeepEqual(got[:min(len(got), len(want))], want) {
+		t.Errorf("ran %q, want %q first", got, want)
+	}
+	files, _ := filepath.Glob(filepath.Join(gui.cwd, "lazykamal-removed-demo-staging-*.json"))
+	if len(files) != 1 {
+		t.Fatalf("records = %v, want one", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rec removalRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.App != "demo (staging)" || len(rec.Status.Containers) == 0 || !reflect.DeepEqual(rec.Accessories, []string{"db", "redis"}) {
+		t.Errorf("record = %+v", rec)
+	}
+	if gui.last != nil {
+		t.Errorf("Remove EVERYTHING is remembered for rerun: %q", gui.last.name)
+	}
+}
+
+func TestRemoveEverythingKeepsEverythingWhenTheRecordFails(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	dest := gui.selectedDestination()
+	res, err := gui.removeEverythingRun(dest, filepath.Join(gui.cwd, "missing", "record.json"))(make(chan struct{}))
+	if err == nil || !strings.Contains(err.Error(), "nothing removed") || res.ExitCode == 0 {
+		t.Errorf("result = %+v, %v; want a failure", res, err)
+	}
+	for _, c := range runner.Calls() {
+		if c[0] == "remove" {
+			t.Fatalf("ran kamal remove without a record: %q", runner.Calls())
+		}
+	}
+}
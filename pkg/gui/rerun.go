@@ -0,0 +1,118 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// lastCommand is the command . runs again: the same name and fn, the
+// confirmation it needed and the destination it ran against.
+type lastCommand struct {
+	name      string
+	readOnly  bool // the command only inspects state
+	fn        func(stopCh <-chan struct{}) (kamal.Result, error)
+	onSuccess func(stopCh <-chan struct{}, took time.Duration)
+	confirm   string                   // message of its confirm dialog; "" when it ran without one
+	dest      *kamal.DeployDestination // copy of the destination; nil without one
+}
+
+// newLastCommand is name and fn as they start now on the selected
+// destination.
+func (gui *GUI) newLastCommand(name string, readOnly bool, fn func(stopCh <-chan struct{}) (kamal.Result, error), onSuccess func(stopCh <-chan struct{}, took time.Duration), confirm string) lastCommand {
+	c := lastCommand{name: name, readOnly: readOnly, fn: fn, onSuccess: onSuccess, confirm: confirm}
+	if dest := gui.selectedDestination(); dest != nil {
+		d := *dest
+		c.dest = &d
+	}
+	return c
+}
+
+// startRemembered starts c and keeps it for the next rerun.
+func (gui *GUI) startRemembered(c lastCommand) {
+	gui.last = &c
+	gui.startCommand(c.name, c.readOnly, c.fn, c.onSuccess)
+}
+
+// rerunMessage is the confirm dialog of a rerun of c with current selected,
+// or "" when it runs without asking: on the same destination and with no
+// confirmation the first time.
+func rerunMessage(c lastCommand, current *kamal.DeployDestination) string {
+	switched := c.dest != nil && (current == nil || current.ConfigPath != c.dest.ConfigPath)
+	if !switched {
+		return c.confirm
+	}
+	selected := "no app"
+	if current != nil {
+		selected = current.Label()
+	}
+	msg := fmt.Sprintf("Last command ran against %s; you now have %s selected.", c.dest.Label(), selected)
+	if c.confirm != "" {
+		msg += "\n" + c.confirm
+	}
+	return msg + fmt.Sprintf("\nSwitch back to %s and run %s again? [y/N]", c.dest.Label(), c.name)
+}
+
+// keyRerun runs the last command again, asking first when it was confirmed
+// the first time or the selected destination changed since.
+func (gui *GUI) keyRerun(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	gui.cmdMu.Lock()
+	running, name := gui.running, gui.runningCmd
+	gui.cmdMu.Unlock()
+	if running {
+		gui.logInfo(name + " is still running")
+		return nil
+	}
+	if gui.last == nil {
+		gui.logInfo("No command to re-run yet")
+		return nil
+	}
+	c := *gui.last
+	msg := rerunMessage(c, gui.selectedDestination())
+	// On the same destination, a protected one's typed confirmation is
+	// the only one, as the first time.
+	if msg == "" || (msg == c.confirm && needsTypedConfirm(gui.selectedDestination(), c.readOnly)) {
+		gui.rerunOn(c)
+		return nil
+	}
+	gui.rerunPending = c.name
+	gui.prevScreen = gui.screen
+	gui.showConfirm("Re-run "+c.name, msg, func() {
+		if c.dest != nil {
+			gui.selectDestination(c.dest.ConfigPath)
+			if d := gui.selectedDestination(); d == nil || d.ConfigPath != c.dest.ConfigPath {
+				gui.logError(c.dest.Label() + " is no longer listed; not re-run")
+				return
+			}
+		}
+		if needsTypedConfirm(gui.selectedDestination(), c.readOnly) {
+			// The typed confirmation opens once this dialog has closed.
+			gui.g.Update(func(*gocui.Gui) error {
+				gui.rerunOn(c)
+				return nil
+			})
+			return
+		}
+		gui.startRemembered(c)
+	}, nil)
+	gui.confirm.RequireText = productionConfirmText(c.dest, c.name)
+	gui.confirm.OnClose = func(confirmAnswer) { gui.rerunPending = "" }
+	return nil
+}
+
+// rerunOn starts c on the selected destination, typing its name first when
+// it is protected.
+func (gui *GUI) rerunOn(c lastCommand) {
+	if dest := gui.selectedDestination(); needsTypedConfirm(dest, c.readOnly) {
+		gui.confirmProtected(dest, c.name, c.confirm, func() {
+			gui.startRemembered(c)
+		})
+		return
+	}
+	gui.startRemembered(c)
+}
@@ -0,0 +1,92 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestRerunMessage(t *testing.T) {
+	staging := &kamal.DeployDestination{Service: "myapp", Name: "staging", ConfigPath: "config/deploy.staging.yml"}
+	production := &kamal.DeployDestination{Service: "myapp", Name: "production", ConfigPath: "config/deploy.production.yml"}
+
+	if msg := rerunMessage(lastCommand{name: "App Restart", dest: staging}, staging); msg != "" {
+		t.Errorf("same destination, no confirm: %q, want none", msg)
+	}
+	if msg := rerunMessage(lastCommand{name: "App Stop", dest: staging, confirm: "Stop the app?"}, staging); msg != "Stop the app?" {
+		t.Errorf("same destination, destructive: %q, want the original confirm", msg)
+	}
+	if msg := rerunMessage(lastCommand{name: "Version"}, production); msg != "" {
+		t.Errorf("no destination recorded: %q, want none", msg)
+	}
+
+	msg := rerunMessage(lastCommand{name: "App Stop", dest: staging, confirm: "Stop the app?"}, production)
+	for _, want := range []string{
+		"Last command ran against myapp (staging); you now have myapp (production) selected.",
+		"Stop the app?",
+		"Switch back to myapp (staging) and run App Stop again? [y/N]",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("switched destination: %q lacks %q", msg, want)
+		}
+	}
+	if msg := rerunMessage(lastCommand{name: "App Restart", dest: staging}, nil); !strings.Contains(msg, "you now have no app selected") {
+		t.Errorf("nothing selected: %q", msg)
+	}
+}
+
+func TestKeyRerun(t *testing.T) {
+	gui := &GUI{screen: ScreenApp}
+	if err := gui.keyRerun(nil, nil); err != nil || !strings.Contains(plainLog(gui), "No command to re-run yet") {
+		t.Fatalf("nothing run yet: err = %v, log = %q", err, plainLog(gui))
+	}
+
+	staging := kamal.DeployDestination{Service: "myapp", Name: "staging", ConfigPath: "config/deploy.staging.yml"}
+	production := kamal.DeployDestination{Service: "myapp", Name: "production", ConfigPath: "config/deploy.production.yml"}
+	gui = &GUI{g: &gocui.Gui{}, screen: ScreenApp, destinations: []kamal.DeployDestination{production}}
+	gui.last = &lastCommand{name: "App Restart", dest: &staging}
+
+	for _, key := range []interface{}{'n', gocui.KeyEsc} {
+		if err := gui.keyRerun(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if gui.screen != ScreenConfirm || gui.confirm == nil || gui.rerunPending != "App Restart" {
+			t.Fatalf("switched destination: screen = %v, pending = %q; want a confirm", gui.screen, gui.rerunPending)
+		}
+		if gui.prevScreen != ScreenApp {
+			t.Errorf("prevScreen = %v, want the App menu", gui.prevScreen)
+		}
+		pressConfirmKey(t, gui, key)
+		if gui.confirm != nil || gui.rerunPending != "" {
+			t.Errorf("%v: dialog %v, pending rerun %q left", key, gui.confirm != nil, gui.rerunPending)
+		}
+	}
+
+	// Staging has gone from the list meanwhile: yes does not run it on
+	// production instead.
+	gui.screen = ScreenApp
+	if err := gui.keyRerun(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	gui.confirm.resolve(confirmYes, "y")
+	if gui.running || !strings.Contains(plainLog(gui), "myapp (staging) is no longer listed; not re-run") {
+		t.Errorf("running = %v, log = %q", gui.running, plainLog(gui))
+	}
+
+	gui = &GUI{screen: ScreenApp, running: true, runningCmd: "Deploy"}
+	gui.last = &lastCommand{name: "App Restart"}
+	if err := gui.keyRerun(nil, nil); err != nil || gui.screen != ScreenApp || !strings.Contains(plainLog(gui), "Deploy is still running") {
+		t.Errorf("while running: screen = %v, log = %q", gui.screen, plainLog(gui))
+	}
+}
+
+// plainLog is the Output text without colors.
+func plainLog(gui *GUI) string {
+	var lines []string
+	for _, e := range gui.logLines {
+		lines = append(lines, stripANSI(e.text))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,87 @@
+package gui
+
+import (
+	"fmt"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// deployRetry is the deploy to offer again after it failed for a
+// transient reason.
+type deployRetry struct {
+	name  string
+	args  []string
+	cause kamal.DeployFailure
+	dest  kamal.DeployDestination
+	// skipPush is set when the failed deploy pushed its image, so the
+	// retry passes --skip-push.
+	skipPush bool
+}
+
+// retryFor returns the retry of the deploy command name (kamal args)
+// whose output is lines, or nil when the failure is not a known transient
+// one. A deploy that already pushed its image is retried with --skip-push.
+func retryFor(name string, args []string, lines []string) *deployRetry {
+	cause, ok := kamal.ClassifyDeployFailure(lines)
+	if !ok || !cause.Transient() {
+		return nil
+	}
+	r := &deployRetry{name: name, args: args, cause: cause}
+	if args[0] == "deploy" && name != "Deploy (skip push)" && kamal.PushSucceeded(lines) {
+		r.name, r.args, r.skipPush = "Deploy (skip push)", []string{"deploy", "--skip-push"}, true
+	}
+	return r
+}
+
+// reason is the cause as shown in the failure summary.
+func (r deployRetry) reason() string {
+	if r.cause.Host != "" {
+		return r.cause.Reason + " on " + r.cause.Host
+	}
+	return r.cause.Reason
+}
+
+func retryMessage(r deployRetry) string {
+	msg := fmt.Sprintf("The deploy failed: %s.\nThis is usually transient. Run %s again? [y/N]", r.reason(), kamal.CommandLine(r.args))
+	if r.skipPush {
+		msg += "\nThe image was already pushed, so the retry skips the push."
+	}
+	if r.cause.Kind == kamal.FailureLock {
+		msg += "\nWait until the other deploy has finished first."
+	}
+	return msg
+}
+
+// offerRetry asks whether to run a failed deploy again. It runs once the
+// failed deploy no longer counts as running; when another dialog is open,
+// the destination changed or a command started meanwhile, the cause is only
+// logged.
+func (gui *GUI) offerRetry(r deployRetry) {
+	gui.g.Update(func(*gocui.Gui) error {
+		gui.cmdMu.Lock()
+		busy := gui.running
+		gui.cmdMu.Unlock()
+		current := gui.selectedDestination()
+		if busy || current == nil || hostsKey(current) != hostsKey(&r.dest) || gui.screen == ScreenEditor || gui.screen == ScreenConfirm || gui.screen == ScreenPicker || gui.screen == ScreenInput || gui.screen == ScreenPager {
+			gui.appendLog([]string{statusLine("warning", "Likely transient: "+r.reason()+"; retry from the Deploy menu")})
+			return nil
+		}
+		gui.confirmRetry(r)
+		return nil
+	})
+}
+
+// confirmRetry asks whether to run r again; however it is declined, the
+// log says it was not retried and why the deploy failed.
+func (gui *GUI) confirmRetry(r deployRetry) {
+	gui.prevScreen = gui.screen
+	gui.showConfirm("Retry deploy?", retryMessage(r), func() {
+		gui.runDeploy(r.name, r.args)
+	}, nil)
+	gui.confirm.OnClose = func(a confirmAnswer) {
+		if a != confirmYes {
+			gui.appendLog([]string{dim("  Not retried: " + r.reason())})
+		}
+	}
+}
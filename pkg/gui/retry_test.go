@@ -0,0 +1,88 @@
+package gui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestRetryFor(t *testing.T) {
+	pushed := []string{
+		"  INFO [7f3d5a21] Running docker buildx build --push -t registry.example.com/myapp:4f2a9d1 . as deploy@localhost",
+		"  INFO [7f3d5a21] Finished in 88.019 seconds with exit status 0 (successful).",
+		"  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.1.6: Net::SSH::Disconnect",
+	}
+	pushFailed := []string{
+		"  INFO [7f3d5a21] Running docker buildx build --push -t registry.example.com/myapp:4f2a9d1 . as deploy@localhost",
+		"docker stderr: ERROR: failed to solve: failed to push registry.example.com/myapp:4f2a9d1: unexpected status from PUT request to https://registry.example.com/v2/: 503 Service Unavailable",
+	}
+	healthcheck := []string{"docker stderr: Error: target failed to become healthy within configured timeout (30s)"}
+
+	tests := []struct {
+		name     string
+		cmd      string
+		args     []string
+		lines    []string
+		wantName string
+		wantArgs []string
+		skipPush bool
+	}{
+		{"pushed deploy skips push", "Deploy (no cache)", []string{"deploy", "--no-cache"}, pushed, "Deploy (skip push)", []string{"deploy", "--skip-push"}, true},
+		{"push failed retries as is", "Deploy", []string{"deploy"}, pushFailed, "Deploy", []string{"deploy"}, false},
+		{"skip push stays", "Deploy (skip push)", []string{"deploy", "--skip-push"}, pushed, "Deploy (skip push)", []string{"deploy", "--skip-push"}, false},
+		{"redeploy retries as is", "Redeploy", []string{"redeploy"}, pushed, "Redeploy", []string{"redeploy"}, false},
+		{"healthcheck is not retried", "Deploy", []string{"deploy"}, healthcheck, "", nil, false},
+		{"unknown failure is not retried", "Deploy", []string{"deploy"}, []string{"boom"}, "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := retryFor(tt.cmd, tt.args, tt.lines)
+			if tt.wantName == "" {
+				if r != nil {
+					t.Fatalf("retryFor = %+v, want nil", r)
+				}
+				return
+			}
+			if r == nil {
+				t.Fatal("retryFor = nil")
+			}
+			if r.name != tt.wantName || !reflect.DeepEqual(r.args, tt.wantArgs) || r.skipPush != tt.skipPush {
+				t.Errorf("retryFor = %q %v skipPush=%v, want %q %v %v", r.name, r.args, r.skipPush, tt.wantName, tt.wantArgs, tt.skipPush)
+			}
+		})
+	}
+}
+
+func TestRetryMessage(t *testing.T) {
+	r := retryFor("Deploy", []string{"deploy"}, []string{
+		"  INFO [7f3d5a21] Running docker buildx build --push -t r/app:1 . as deploy@localhost",
+		"  INFO [7f3d5a21] Finished in 8.0 seconds with exit status 0 (successful).",
+		"  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.1.6: connection closed by remote host",
+	})
+	msg := retryMessage(*r)
+	for _, want := range []string{"SSH connection dropped on 10.0.1.6", "kamal deploy --skip-push", "[y/N]", "already pushed"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message %q lacks %q", msg, want)
+		}
+	}
+
+	lock := retryFor("Deploy", []string{"deploy"}, []string{"  ERROR (Kamal::Cli::LockError): Deploy lock found. Run 'kamal lock help' for more information"})
+	if msg := retryMessage(*lock); !strings.Contains(msg, "other deploy") || strings.Contains(msg, "already pushed") {
+		t.Errorf("lock message = %q", msg)
+	}
+}
+
+func TestDecliningRetryLogsWhy(t *testing.T) {
+	r := deployRetry{name: "Deploy", args: []string{"deploy"}, cause: kamal.DeployFailure{Reason: "SSH connection dropped", Host: "10.0.1.6"}}
+	for _, key := range []interface{}{'n', gocui.KeyEsc, gocui.KeyEnter} {
+		gui := newFakeGUI(t, &kamal.FakeRunner{})
+		gui.confirmRetry(r)
+		pressConfirmKey(t, gui, key) // Enter answers the preselected No
+		if log := plainLog(gui); !strings.Contains(log, "Not retried: SSH connection dropped on 10.0.1.6") {
+			t.Errorf("%v: log = %q, want why it was not retried", key, log)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package gui
+
+import (
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// pickRollback rolls back to the version pre-filled after a failed
+// post_deploy check, or lets the user pick one from the app images on the
+// hosts first. Esc in the picker cancels without running anything.
+func (gui *GUI) pickRollback() {
+	if version := gui.rollbackVersion(); version != "" {
+		gui.rollback(version)
+		return
+	}
+	gui.withAppImages(func(images []kamal.AppImage) {
+		if len(images) == 0 {
+			gui.logError("No app images found on the hosts; nothing to roll back to")
+			return
+		}
+		gui.showPicker(rollbackPicker(images, gui.rollback))
+	})
+}
+
+// rollbackPicker is versionPicker worded for Rollback.
+func rollbackPicker(images []kamal.AppImage, onPick func(version string)) *listPicker {
+	p := versionPicker(images, onPick)
+	p.Title = "Rollback to"
+	p.Message = "Boot the containers of this version again, newest first."
+	return p
+}
+
+// rollback confirms and runs `kamal rollback version`. A successful
+// rollback clears the pre-filled version and is then verified on the hosts.
+func (gui *GUI) rollback(version string) {
+	opts := gui.runOpts()
+	args := []string{"rollback", version}
+	key := ""
+	if dest := gui.selectedDestination(); dest != nil {
+		key = hostsKey(dest)
+	}
+	gui.runWithConfirmThen("Rollback", "Rollback to "+version+"?", func(stopCh <-chan struct{}) (kamal.Result, error) {
+		res, err := kamal.RunKamalWithStop(args, opts, stopCh)
+		if err == nil && res.ExitCode == 0 {
+			gui.setRollbackVersion(key, "")
+		}
+		return res, err
+	}, func(stopCh <-chan struct{}, _ time.Duration) {
+		gui.verifyRollback(opts, version, stopCh)
+	})
+}
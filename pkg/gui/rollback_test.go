@@ -0,0 +1,102 @@
+package gui
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestRollbackPicker(t *testing.T) {
+	var picked string
+	p := rollbackPicker([]kamal.AppImage{
+		{Tag: "9f8e7d6c", Created: "2 hours ago", Hosts: []string{"a"}},
+		{Tag: "1a2b3c4d", Created: "3 days ago", Hosts: []string{"a"}},
+	}, func(v string) { picked = v })
+	if p.Title != "Rollback to" || len(p.Items) != 2 {
+		t.Fatalf("picker = %q with %d items", p.Title, len(p.Items))
+	}
+	p.move(1)
+	p.OnDone(p.selected())
+	if picked != "1a2b3c4d" {
+		t.Errorf("picked %q, want 1a2b3c4d", picked)
+	}
+}
+
+// appVersionsAfter answers `kamal app version` with from on every host for
+// the first polls and with to afterwards; anything else like the demo.
+func appVersionsAfter(polls int, from, to string) func(args []string) ([]string, int) {
+	var mu sync.Mutex
+	return func(args []string) ([]string, int) {
+		if strings.Join(args[:2], " ") != "app version" {
+			return kamal.DemoResponse(args)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		v := to
+		if polls > 0 {
+			polls--
+			v = from
+		}
+		return []string{"App Host: 10.0.2.10", v, "App Host: 10.0.2.11", v}, 0
+	}
+}
+
+func TestVerifyRollback(t *testing.T) {
+	defer func(timeout, interval time.Duration) {
+		rollbackVerifyTimeout, rollbackVerifyInterval = timeout, interval
+	}(rollbackVerifyTimeout, rollbackVerifyInterval)
+	rollbackVerifyInterval = time.Millisecond
+
+	tests := []struct {
+		name    string
+		polls   int
+		timeout time.Duration
+		check   string // a post_deploy command, if any
+		want    string
+	}{
+		{"converges", 2, time.Minute, "", "rollback verified: a1b2c3 serving on all hosts"},
+		{"health check passes", 0, time.Minute, "true", "rollback verified: a1b2c3 serving on all hosts"},
+		{"health check fails", 0, time.Minute, "false", "rollback NOT verified: a1b2c3 runs on all hosts but post_deploy failed"},
+		{"never converges", 1 << 30, 20 * time.Millisecond, "", "rollback NOT verified: after 20ms still on the wrong version: 10.0.2.10 (9f8e7d), 10.0.2.11 (9f8e7d)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollbackVerifyTimeout = tt.timeout
+			runner := &kamal.FakeRunner{Respond: appVersionsAfter(tt.polls, "9f8e7d", "a1b2c3")}
+			gui := newFakeGUI(t, runner)
+			if tt.check != "" {
+				gui.project = &kamal.ProjectConfig{PostDeploy: []kamal.HookCommand{{Run: tt.check}}}
+			}
+			gui.verifyRollback(gui.runOpts(), "a1b2c3", make(chan struct{}))
+			if log := plainLog(gui); !strings.Contains(log, tt.want) {
+				t.Errorf("Output does not say %q:\n%s", tt.want, log)
+			}
+		})
+	}
+}
+
+func TestVerifyRollbackStops(t *testing.T) {
+	runner := &kamal.FakeRunner{Respond: appVersionsAfter(1<<30, "9f8e7d", "a1b2c3")}
+	gui := newFakeGUI(t, runner)
+	stopCh := make(chan struct{})
+	close(stopCh)
+	gui.verifyRollback(gui.runOpts(), "a1b2c3", stopCh)
+	log := plainLog(gui)
+	if !strings.Contains(log, "Stopped verifying the rollback") || strings.Contains(log, "rollback verified") {
+		t.Errorf("Output after Esc:\n%s", log)
+	}
+}
+
+func TestWrongVersionEscalation(t *testing.T) {
+	got := wrongVersionEscalation("a1b2c3", map[string]string{}, 2*time.Minute)
+	if !strings.Contains(got, "no host reported its version within 2m0s") {
+		t.Errorf("escalation without versions = %q", got)
+	}
+	got = wrongVersionEscalation("a1b2c3", map[string]string{"b": "a1b2c3", "a": "9f8e7d"}, 2*time.Minute)
+	if want := "rollback NOT verified: after 2m0s still on the wrong version: a (9f8e7d)"; got != want {
+		t.Errorf("escalation = %q, want %q", got, want)
+	}
+}
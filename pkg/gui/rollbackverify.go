@@ -0,0 +1,77 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// rollbackVerifyTimeout is how long a rollback has to show its version on
+// every host; rollbackVerifyInterval is how often the hosts are asked.
+var (
+	rollbackVerifyTimeout  = 2 * time.Minute
+	rollbackVerifyInterval = observePollInterval
+)
+
+// verifyRollback follows a finished rollback until every host runs
+// version, as observe mode follows a deploy, then runs the post_deploy
+// checks when there are any. It ends with "rollback verified" or with the
+// hosts still on the wrong version. Esc stops it; the rollback stands.
+func (gui *GUI) verifyRollback(opts kamal.RunOptions, version string, stopCh <-chan struct{}) {
+	opts.OnRun = nil // polled every few seconds; not echoed
+	gui.logInfo("Verifying rollback: waiting for every host to run " + version + " " + dim("(Esc stop)"))
+	rollout := kamal.NewRollout(version)
+	deadline := time.Now().Add(rollbackVerifyTimeout)
+	var versions map[string]string
+	summary := ""
+	for {
+		if res, err := kamal.RunKamalWithStop([]string{"app", "version"}, opts, stopCh); err == nil && res.ExitCode == 0 {
+			versions = kamal.ParseAppVersions(res.Combined())
+			// The rollback has released its lock; only convergence is watched.
+			rollout.Observe(kamal.RolloutObservation{LockHeld: true, Versions: versions})
+			if s := rollout.Summary(); s != summary {
+				summary = s
+				gui.appendLog([]string{dim("  " + s)})
+			}
+			if converged, _ := kamal.CheckConvergence(version, versions); converged {
+				break
+			}
+		}
+		if !time.Now().Before(deadline) {
+			gui.logError(wrongVersionEscalation(version, versions, rollbackVerifyTimeout))
+			return
+		}
+		select {
+		case <-stopCh:
+			gui.logInfo("Stopped verifying the rollback")
+			return
+		case <-time.After(rollbackVerifyInterval):
+		}
+	}
+
+	if gui.project != nil && len(gui.project.PostDeploy) > 0 {
+		verdict := gui.postDeployChecks(opts, "", version, stopCh)
+		if !verdict.Passed() {
+			gui.logError(fmt.Sprintf("rollback NOT verified: %s runs on all hosts but post_deploy failed: %s", version, verdict.Summary()))
+			return
+		}
+	}
+	gui.logSuccess("rollback verified: " + version + " serving on all hosts")
+}
+
+// wrongVersionEscalation is the error logged when a rollback to version
+// has not converged within timeout, naming the hosts still on another
+// version and what they run.
+func wrongVersionEscalation(version string, versions map[string]string, timeout time.Duration) string {
+	_, off := kamal.CheckConvergence(version, versions)
+	if len(off) == 0 {
+		return fmt.Sprintf("rollback NOT verified: no host reported its version within %s; check the app before trusting %s", formatDuration(timeout), version)
+	}
+	hosts := make([]string, len(off))
+	for i, h := range off {
+		hosts[i] = fmt.Sprintf("%s (%s)", h, versions[h])
+	}
+	return fmt.Sprintf("rollback NOT verified: after %s still on the wrong version: %s", formatDuration(timeout), strings.Join(hosts, ", "))
+}
@@ -0,0 +1,115 @@
+package gui
+
+import (
+	"errors"
+	"regexp"
+)
+
+// secretValueMaxLen is how long a secret value may be; tokens run longer
+// than the default input limit.
+const secretValueMaxLen = 4096
+
+var envKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// secretEnv is a variable typed into "Run with secret env" for the next
+// command only. It is never written to disk, and its value is masked in
+// the log for the rest of the session.
+type secretEnv struct {
+	key, value string
+	claimed    bool // the command it is for has started
+}
+
+// validateEnvKey accepts shell variable names.
+func validateEnvKey(s string) error {
+	if !envKey.MatchString(s) {
+		return errors.New("letters, digits and _ only, not starting with a digit")
+	}
+	return nil
+}
+
+// validateSecretValue rejects values too short to be masked in the output.
+func validateSecretValue(s string) error {
+	if len(s) < minSecretLen {
+		return errors.New("at least 4 characters, so it can be masked in the output")
+	}
+	return nil
+}
+
+// promptSecretEnv asks for a KEY and a masked value passed to the next
+// command only. With one pending, it is forgotten instead.
+func (gui *GUI) promptSecretEnv() {
+	gui.cmdMu.Lock()
+	pending, running := gui.secret, gui.running
+	if pending != nil && !pending.claimed {
+		gui.secret = nil
+	}
+	gui.cmdMu.Unlock()
+	if running {
+		gui.logInfo("A command is running; set the secret when it finishes")
+		return
+	}
+	if pending != nil {
+		gui.logInfo("Forgot secret " + pending.key + "; no command received it")
+		return
+	}
+	key := newInput("Secret env: key", "Name of the variable passed to the next kamal command only, e.g. MIGRATION_TOKEN.", "", func(key string) {
+		value := newInput("Secret env: "+key, "Value of "+key+". It is not saved, and is masked wherever the output repeats it.", "", func(value string) {
+			sessionSecrets.register(value)
+			gui.cmdMu.Lock()
+			gui.secret = &secretEnv{key: key, value: value}
+			gui.cmdMu.Unlock()
+			gui.logInfo(key + " will be passed to the next command only (select Run with secret env again to forget it)")
+		})
+		value.Masked = true
+		value.MaxLen = secretValueMaxLen
+		value.Validate = validateSecretValue
+		gui.showInput(value)
+	})
+	key.Validate = validateEnvKey
+	gui.showInput(key)
+}
+
+// pendingSecretKey is the key of the secret waiting for the next command,
+// or "".
+func (gui *GUI) pendingSecretKey() string {
+	gui.cmdMu.Lock()
+	defer gui.cmdMu.Unlock()
+	if gui.secret == nil {
+		return ""
+	}
+	return gui.secret.key
+}
+
+// claimSecret reserves the pending secret for the command starting now and
+// returns its key, or "" when there is none. Callers hold cmdMu.
+func (gui *GUI) claimSecret() string {
+	if gui.secret == nil {
+		return ""
+	}
+	gui.secret.claimed = true
+	return gui.secret.key
+}
+
+// secretEnvOnce is RunOptions.Env: the claimed secret goes to the first
+// kamal invocation of its command, then is forgotten.
+func (gui *GUI) secretEnvOnce() []string {
+	gui.cmdMu.Lock()
+	defer gui.cmdMu.Unlock()
+	s := gui.secret
+	if s == nil || !s.claimed {
+		return nil
+	}
+	gui.secret = nil
+	return []string{s.key + "=" + s.value}
+}
+
+// dropClaimedSecret forgets a secret its command ended without using, and
+// returns its key, or "". Callers hold cmdMu.
+func (gui *GUI) dropClaimedSecret() string {
+	if gui.secret == nil || !gui.secret.claimed {
+		return ""
+	}
+	key := gui.secret.key
+	gui.secret = nil
+	return key
+}
@@ -0,0 +1,57 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLiteralSecrets(t *testing.T) {
+	var s literalSecrets
+	s.register("abc")
+	s.register("t0k3n-42")
+	s.register("t0k3n-42")
+	if len(s.values) != 1 {
+		t.Fatalf("registered %q, want the long value once", s.values)
+	}
+	if got := s.redact("migrate --token-file /tmp/x; echo t0k3n-42 and abc"); got != "migrate --token-file /tmp/x; echo [REDACTED] and abc" {
+		t.Errorf("redact() = %q", got)
+	}
+
+	sessionSecrets.register("zz-session-only-value")
+	if got := sanitizeLogLine("  value: zz-session-only-value"); strings.Contains(got, "zz-session") {
+		t.Errorf("sanitizeLogLine() = %q, want the session secret masked", got)
+	}
+}
+
+func TestValidateSecretEnv(t *testing.T) {
+	for key, ok := range map[string]bool{"MIGRATION_TOKEN": true, "_x1": true, "1TOKEN": false, "MY-TOKEN": false, "A B": false} {
+		if err := validateEnvKey(key); (err == nil) != ok {
+			t.Errorf("validateEnvKey(%q) = %v", key, err)
+		}
+	}
+	if validateSecretValue("abc") == nil || validateSecretValue("abcd") != nil {
+		t.Error("values must be at least 4 characters")
+	}
+}
+
+func TestSecretEnvOnce(t *testing.T) {
+	gui := &GUI{secret: &secretEnv{key: "MIGRATION_TOKEN", value: "t0k3n"}}
+	if env := gui.secretEnvOnce(); env != nil {
+		t.Errorf("before its command started: %q", env)
+	}
+	if key := gui.claimSecret(); key != "MIGRATION_TOKEN" {
+		t.Fatalf("claimSecret() = %q", key)
+	}
+	if env := gui.secretEnvOnce(); len(env) != 1 || env[0] != "MIGRATION_TOKEN=t0k3n" {
+		t.Errorf("first invocation: %q", env)
+	}
+	if env := gui.secretEnvOnce(); env != nil || gui.secret != nil {
+		t.Errorf("second invocation: %q, secret %+v; want it forgotten", env, gui.secret)
+	}
+
+	gui.secret = &secretEnv{key: "MIGRATION_TOKEN", value: "t0k3n"}
+	gui.claimSecret()
+	if key := gui.dropClaimedSecret(); key != "MIGRATION_TOKEN" || gui.secret != nil {
+		t.Errorf("unused secret: dropped %q, left %+v", key, gui.secret)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Security-related utilities for the GUI
@@ -70,6 +71,8 @@ func isSymlink(path string) bool {
 // sanitizeLogLine removes potentially sensitive information from log output
 // This is a basic implementation - extend based on your needs
 func sanitizeLogLine(line string) string {
+	line = sessionSecrets.redact(line)
+
 	// List of patterns that might indicate sensitive data
 	sensitivePatterns := []string{
 		"password=",
@@ -85,23 +88,77 @@ func sanitizeLogLine(line string) string {
 	}
 
 	for _, pattern := range sensitivePatterns {
-		if strings.Contains(line, pattern) {
-			// Find the pattern and mask the value
-			idx := strings.Index(line, pattern)
-			if idx != -1 {
-				// Find end of value (space, newline, or end of string)
-				endIdx := len(line)
-				for i := idx + len(pattern); i < len(line); i++ {
-					if line[i] == ' ' || line[i] == '\n' || line[i] == '\t' || line[i] == '"' || line[i] == '\'' {
-						endIdx = i
-						break
-					}
-				}
-				line = line[:idx+len(pattern)] + "[REDACTED]" + line[endIdx:]
-			}
+		line = maskValue(line, pattern)
+	}
+	// Flags whose value is the next argument, e.g. a command line with
+	// --password s3cret.
+	for _, flag := range []string{"--password ", "--token ", "--secret "} {
+		line = maskValue(line, flag)
+	}
+
+	return line
+}
+
+// maskValue replaces the value after the first pattern in line with
+// [REDACTED]. The value ends at whitespace, a quote or an escape code; a
+// quoted value is masked up to its closing quote.
+func maskValue(line, pattern string) string {
+	idx := strings.Index(line, pattern)
+	if idx == -1 {
+		return line
+	}
+	start := idx + len(pattern)
+	end := start
+	if end < len(line) && (line[end] == '"' || line[end] == '\'') {
+		if close := strings.IndexByte(line[end+1:], line[end]); close >= 0 {
+			end += close + 2
+		} else {
+			end = len(line)
+		}
+	} else {
+		for end < len(line) && !strings.ContainsRune(" \n\t\"'\033", rune(line[end])) {
+			end++
+		}
+	}
+	return line[:start] + "[REDACTED]" + line[end:]
+}
+
+// minSecretLen is the shortest value masked literally; shorter ones would
+// mask ordinary words.
+const minSecretLen = 4
+
+// sessionSecrets are the values typed into lazykamal this session. They
+// are masked wherever they appear, whatever their key.
+var sessionSecrets literalSecrets
+
+// literalSecrets masks registered values literally.
+type literalSecrets struct {
+	mu     sync.RWMutex
+	values []string
+}
+
+// register masks value from now until the session ends.
+func (s *literalSecrets) register(value string) {
+	if len(value) < minSecretLen {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range s.values {
+		if v == value {
+			return
 		}
 	}
+	s.values = append(s.values, value)
+}
 
+// redact replaces every registered value in line with [REDACTED].
+func (s *literalSecrets) redact(line string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, v := range s.values {
+		line = strings.ReplaceAll(line, v, "[REDACTED]")
+	}
 	return line
 }
 
@@ -103,6 +103,21 @@ func TestSanitizeLogLine(t *testing.T) {
 			input:    "password=secret1 token=secret2",
 			expected: "password=[REDACTED] token=[REDACTED]",
 		},
+		{
+			name:     "password flag in a command line",
+			input:    "$ kamal registry login --password s3cret --verbose",
+			expected: "$ kamal registry login --password [REDACTED] --verbose",
+		},
+		{
+			name:     "quoted flag value",
+			input:    "$ kamal x --token 'a b' --verbose",
+			expected: "$ kamal x --token [REDACTED] --verbose",
+		},
+		{
+			name:     "value before an escape code",
+			input:    "\033[2m$ kamal x --secret abc\033[0m",
+			expected: "\033[2m$ kamal x --secret [REDACTED]\033[0m",
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,129 @@
+package gui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+// dockerCreatedLayout is how `docker ps` prints {{.CreatedAt}}.
+const dockerCreatedLayout = "2006-01-02 15:04:05 -0700 MST"
+
+// Column limits of the container select screen. The name column takes what
+// the panel has left, but never less than containerNameMin.
+const (
+	containerNameMin = 12
+	containerRoleMax = 12
+	containerTagMax  = 12
+	containerAgeMax  = 12
+)
+
+// containerCreated is when docker created c; ok is false when Created is
+// missing or not in docker's format.
+func containerCreated(c docker.Container) (created time.Time, ok bool) {
+	t, err := time.Parse(dockerCreatedLayout, c.Created)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// sortContainers orders the container select screen: running containers
+// first, then the newest created. Containers without a readable creation
+// time keep their discovery order after the others of their state.
+func sortContainers(list []ContainerInfo) {
+	sort.SliceStable(list, func(i, j int) bool {
+		ri, rj := list[i].Container.State == "running", list[j].Container.State == "running"
+		if ri != rj {
+			return ri
+		}
+		ci, oki := containerCreated(list[i].Container)
+		cj, okj := containerCreated(list[j].Container)
+		if oki != okj {
+			return oki
+		}
+		return oki && ci.After(cj)
+	})
+}
+
+// dockerUptime matches the uptime in a running container's status,
+// "Up 3 hours (healthy)" -> "3 hours".
+var dockerUptime = regexp.MustCompile(`^Up (.+?)(?: \(.*\))?$`)
+
+// containerAge is c's age for its row: "up 3 hours" while it runs, or how
+// long ago it was created, e.g. "2d ago". It is "-" when neither is known.
+func containerAge(c docker.Container, now time.Time) string {
+	if c.State == "running" {
+		if m := dockerUptime.FindStringSubmatch(c.Status); m != nil {
+			return "up " + m[1]
+		}
+	}
+	created, ok := containerCreated(c)
+	if !ok {
+		return "-"
+	}
+	return humanAge(now.Sub(created)) + " ago"
+}
+
+// humanAge is d in its largest whole unit: "45s", "12m", "3h", "2d".
+func humanAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// containerRows are the lines of the container select screen for a panel
+// width cells wide: a state dot, name, role, image tag and age, aligned in
+// columns. Names that do not fit lose their middle, so the version suffix
+// that tells containers apart stays visible.
+func containerRows(list []ContainerInfo, selected, width int, now time.Time) []string {
+	cells := make([][]string, len(list))
+	widths := make([]int, 4)
+	for i, ci := range list {
+		cells[i] = []string{
+			ci.Container.Name,
+			truncate(ci.Role, containerRoleMax),
+			truncate(docker.ImageTag(ci.Container.Image), containerTagMax),
+			truncate(containerAge(ci.Container, now), containerAgeMax),
+		}
+		for j, cell := range cells[i] {
+			if w := visibleWidth(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+	// Prefix, dot and the gaps between the columns.
+	fixed := 4 + 2*3 + widths[1] + widths[2] + widths[3]
+	if room := width - fixed; room < widths[0] {
+		widths[0] = room
+		if widths[0] < containerNameMin {
+			widths[0] = containerNameMin
+		}
+	}
+	rows := make([]string, len(list))
+	for i, ci := range list {
+		prefix := "  "
+		if i == selected {
+			prefix = cyan(iconArrow) + " "
+		}
+		c := cells[i]
+		row := []string{
+			padRight(truncateMiddle(c[0], widths[0]), widths[0]),
+			dim(padRight(c[1], widths[1])),
+			padRight(c[2], widths[2]),
+			dim(c[3]),
+		}
+		rows[i] = prefix + stateBullet(ci.Container.State) + " " + strings.Join(row, "  ")
+	}
+	return rows
+}
@@ -0,0 +1,136 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+// loadRunningImages fetches the images the containers in apps run and
+// merges them into the cache shown next to versions. Failures only mean no
+// digest is shown, so they are logged in debug mode alone.
+func (gui *ServerGUI) loadRunningImages(apps []docker.App) {
+	images, err := docker.RunningImages(gui.client, apps)
+	if err != nil {
+		if gui.debug {
+			gui.logInfo("Image digests unavailable: " + strings.SplitN(err.Error(), "\n", 2)[0])
+		}
+		return
+	}
+	gui.storeRunningImages(images)
+	gui.g.Update(func(*gocui.Gui) error { return nil })
+}
+
+func (gui *ServerGUI) storeRunningImages(images map[string]docker.RunningImage) {
+	gui.imagesMu.Lock()
+	defer gui.imagesMu.Unlock()
+	if gui.images == nil {
+		gui.images = map[string]docker.RunningImage{}
+	}
+	for id, img := range images {
+		gui.images[id] = img
+	}
+}
+
+// runningDigest is the short registry digest of container id's image, or ""
+// when it is not known (not fetched yet, or an image built on the host).
+func (gui *ServerGUI) runningDigest(id string) string {
+	gui.imagesMu.Lock()
+	defer gui.imagesMu.Unlock()
+	if d := gui.images[id].RepoDigest; d != "" {
+		return docker.ShortDigest(d)
+	}
+	return ""
+}
+
+// versionWithDigest renders a version followed by the digest of the
+// container it was read from.
+func (gui *ServerGUI) versionWithDigest(containers []docker.Container) string {
+	version := docker.GetAppVersion(containers)
+	if len(containers) == 0 {
+		return version
+	}
+	if d := gui.runningDigest(containers[0].ID); d != "" {
+		version += " " + dim("@"+d)
+	}
+	return version
+}
+
+// verifyDigests compares the image each app container runs with what the
+// registry now serves for the same tag. A mismatch means the tag was moved
+// after the container was started, usually by a force-push. Accessories are
+// left out: their public tags ("postgres:16") move by design.
+func (gui *ServerGUI) verifyDigests(app docker.App) {
+	if len(app.Containers) == 0 {
+		gui.logError("No containers to verify")
+		return
+	}
+	gui.logInfo(fmt.Sprintf("=== %s Verify ===", app.Service))
+
+	go func() {
+		images, err := docker.RunningImages(gui.client, []docker.App{app})
+		if err != nil {
+			gui.logError("Failed to inspect containers: " + strings.SplitN(err.Error(), "\n", 2)[0])
+			return
+		}
+		gui.storeRunningImages(images)
+
+		type manifest struct {
+			m   docker.RegistryManifest
+			err error
+		}
+		manifests := map[string]manifest{}
+		mismatches := 0
+		for _, c := range app.Containers {
+			mf, ok := manifests[c.Image]
+			if !ok {
+				mf.m, mf.err = docker.InspectManifest(gui.client, c.Image)
+				manifests[c.Image] = mf
+			}
+			verdict := docker.DigestUnknown
+			if mf.err == nil {
+				verdict = docker.CompareDigest(images[c.ID], mf.m)
+			}
+			if verdict == docker.DigestMismatch {
+				mismatches++
+			}
+			gui.appendLog([]string{verifyLine(c, images[c.ID], mf.m, verdict, mf.err)})
+		}
+		if mismatches > 0 {
+			gui.logError(fmt.Sprintf("%d container(s) run an image the registry no longer serves for its tag — the tag was probably force-pushed", mismatches))
+			return
+		}
+		gui.logSuccess("Verify completed")
+	}()
+}
+
+// verifyLine is the log line for one container's digest check.
+func verifyLine(c docker.Container, running docker.RunningImage, m docker.RegistryManifest, verdict docker.DigestVerdict, err error) string {
+	tag := docker.ImageTag(c.Image)
+	switch verdict {
+	case docker.DigestMatch:
+		return "  " + statusLine("success", fmt.Sprintf("%s: %s matches the registry %s", c.Name, tag, dim("@"+docker.ShortDigest(shownDigest(running)))))
+	case docker.DigestMismatch:
+		registry := ""
+		if len(m.Digests) > 0 {
+			registry = " " + dim("@"+docker.ShortDigest(m.Digests[0]))
+		}
+		return "  " + statusLine("error", fmt.Sprintf("%s: runs %s %s but the registry's %s is now%s", c.Name, tag, dim("@"+docker.ShortDigest(shownDigest(running))), tag, registry))
+	}
+	why := "no digest recorded for the running image"
+	if err != nil {
+		why = "registry lookup failed: " + strings.SplitN(strings.TrimSpace(err.Error()), "\n", 2)[0]
+	}
+	return "  " + statusLine("warning", fmt.Sprintf("%s: cannot verify %s (%s)", c.Name, tag, why))
+}
+
+// shownDigest is the digest shown for a running image: the registry digest
+// when known, else the image ID.
+func shownDigest(running docker.RunningImage) string {
+	if running.RepoDigest != "" {
+		return running.RepoDigest
+	}
+	return running.ID
+}
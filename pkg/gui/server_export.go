@@ -0,0 +1,168 @@
+package gui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+// exportFormats are the inventory formats offered by "Export inventory";
+// each is also the file extension.
+var exportFormats = []string{"json", "csv"}
+
+// exportPrompt is the state of the "Export inventory" screen: the chosen
+// format and the local path being typed.
+type exportPrompt struct {
+	format int
+	path   string
+}
+
+// setFormat switches the format, and the path's extension with it when the
+// path still ends in the old one.
+func (p *exportPrompt) setFormat(i int) {
+	if i < 0 || i >= len(exportFormats) || i == p.format {
+		return
+	}
+	if old := "." + exportFormats[p.format]; strings.HasSuffix(p.path, old) {
+		p.path = strings.TrimSuffix(p.path, old) + "." + exportFormats[i]
+	}
+	p.format = i
+}
+
+// typingExportPath reports whether keystrokes go to the export path, so
+// global single-letter keys must stand aside.
+func (gui *ServerGUI) typingExportPath() bool {
+	return gui.screen == ServerScreenExport
+}
+
+func (gui *ServerGUI) keyExportInventory(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ServerScreenApps || !gui.startup.ready() {
+		return nil
+	}
+	gui.export = &exportPrompt{path: docker.InventoryPath(gui.client.Host, exportFormats[0], time.Now())}
+	gui.screen = ServerScreenExport
+	return nil
+}
+
+// bindExportKeys routes typed characters and Backspace to the export path
+// while the export screen is open.
+func (gui *ServerGUI) bindExportKeys(g *gocui.Gui) error {
+	typed := func(s string) func(*gocui.Gui, *gocui.View) error {
+		return func(*gocui.Gui, *gocui.View) error {
+			if gui.typingExportPath() {
+				gui.export.path += s
+			}
+			return nil
+		}
+	}
+	for r := rune(33); r < 127; r++ {
+		if err := g.SetKeybinding(viewMain, r, gocui.ModNone, typed(string(r))); err != nil {
+			return err
+		}
+	}
+	if err := g.SetKeybinding(viewMain, gocui.KeySpace, gocui.ModNone, typed(" ")); err != nil {
+		return err
+	}
+	backspace := func(*gocui.Gui, *gocui.View) error {
+		if gui.typingExportPath() && gui.export.path != "" {
+			r := []rune(gui.export.path)
+			gui.export.path = string(r[:len(r)-1])
+		}
+		return nil
+	}
+	for _, k := range []gocui.Key{gocui.KeyBackspace, gocui.KeyBackspace2} {
+		if err := g.SetKeybinding(viewMain, k, gocui.ModNone, backspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gui *ServerGUI) renderExport(v *gocui.View) {
+	v.Title = " Export inventory "
+	fmt.Fprintf(v, " %d app(s) on %s\n\n", len(gui.apps), gui.client.HostDisplay())
+	for i, f := range exportFormats {
+		prefix := "  "
+		if i == gui.export.format {
+			prefix = cyan(iconArrow) + " "
+		}
+		fmt.Fprintln(v, prefix+strings.ToUpper(f))
+	}
+	fmt.Fprintln(v, "")
+	fmt.Fprintln(v, " Path:")
+	fmt.Fprintln(v, " "+gui.export.path+"_")
+	fmt.Fprintln(v, "")
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓ format  type to edit path")))
+	fmt.Fprintln(v, dim(" Enter: export  Esc: back"))
+}
+
+// exportInventory writes the discovered apps to path. Only the image
+// digests are fetched, in one docker inspect; everything else is what the
+// apps list already shows.
+func (gui *ServerGUI) exportInventory(format, path string) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		gui.logError("Export path is empty")
+		return
+	}
+	gui.cmdMu.Lock()
+	if gui.running {
+		gui.cmdMu.Unlock()
+		gui.logError("Another command is running")
+		return
+	}
+	gui.running = true
+	gui.runningCmd = "Exporting inventory"
+	gui.cmdStartTime = time.Now()
+	gui.cmdMu.Unlock()
+
+	apps := gui.apps
+	go func() {
+		defer func() {
+			gui.cmdMu.Lock()
+			gui.running = false
+			gui.runningCmd = ""
+			gui.cmdMu.Unlock()
+			gui.g.Update(func(*gocui.Gui) error { return nil })
+		}()
+		digests, err := docker.ImageDigests(gui.client, apps)
+		if err != nil {
+			gui.appendLog([]string{statusLine("warning", "Image digests unavailable: "+strings.SplitN(err.Error(), "\n", 2)[0])})
+		}
+		inv := docker.NewInventory(apps, gui.client, gui.version, time.Now(), digests)
+		if err := writeInventory(inv, format, path); err != nil {
+			gui.logError("Failed to export inventory: " + err.Error())
+			return
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		gui.logSuccess(fmt.Sprintf("Exported %d app(s) as %s to %s", len(inv.Apps), strings.ToUpper(format), path))
+	}()
+}
+
+func writeInventory(inv docker.Inventory, format, path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if format == "csv" {
+		err = inv.WriteCSV(f)
+	} else {
+		err = inv.WriteJSON(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,20 @@
+package gui
+
+import "testing"
+
+func TestExportPromptSetFormat(t *testing.T) {
+	p := &exportPrompt{path: "lazykamal-inventory-host.json"}
+	p.setFormat(1)
+	if p.format != 1 || p.path != "lazykamal-inventory-host.csv" {
+		t.Errorf("setFormat(csv) = %d %q", p.format, p.path)
+	}
+	p.path = "inventory.txt"
+	p.setFormat(0)
+	if p.format != 0 || p.path != "inventory.txt" {
+		t.Errorf("setFormat kept a custom extension? got %d %q", p.format, p.path)
+	}
+	p.setFormat(5)
+	if p.format != 0 {
+		t.Errorf("setFormat(out of range) changed format to %d", p.format)
+	}
+}
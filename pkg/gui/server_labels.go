@@ -0,0 +1,48 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jroimartin/gocui"
+)
+
+// groupingLabels are the labels discovery groups containers by.
+var groupingLabels = map[string]bool{"service": true, "role": true, "destination": true}
+
+func (gui *ServerGUI) keyContainerLabels(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ServerScreenContainerSelect || gui.selectedContainer >= len(gui.allContainers) {
+		return nil
+	}
+	gui.appendLog(containerLabelLines(gui.allContainers[gui.selectedContainer]))
+	return nil
+}
+
+// containerLabelLines lists a container's labels sorted by key, with the
+// grouping labels highlighted, followed by how discovery classified it.
+func containerLabelLines(ci ContainerInfo) []string {
+	c := ci.Container
+	lines := []string{cyan("── Labels: " + c.Name + " ──")}
+	keys := make([]string, 0, len(c.Labels))
+	for k := range c.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if groupingLabels[k] {
+			lines = append(lines, fmt.Sprintf("  %s %s=%s", yellow("*"), yellow(k), bold(c.Labels[k])))
+			continue
+		}
+		lines = append(lines, dim("    "+k+"=")+c.Labels[k])
+	}
+	if len(keys) == 0 {
+		lines = append(lines, dim("    no labels"))
+	}
+	if c.Labels["destination"] == "" {
+		lines = append(lines, dim("    no destination label, grouped as production"))
+	}
+	if c.Grouping != "" {
+		lines = append(lines, "  Grouped as "+c.Grouping)
+	}
+	return lines
+}
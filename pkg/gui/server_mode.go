@@ -2,6 +2,7 @@ package gui
 
 import (
 	"fmt"
+	"os/exec"
 	"strings"
 	"sync"
 	"time"
@@ -29,9 +30,20 @@ type ServerGUI struct {
 	selectedContainer int             // For container selection
 	allContainers     []ContainerInfo // Flattened list of all containers for current app
 	screen            ServerScreen
-	logLines          []string
+	logLines          []logEntry
+	long              *longLines // untruncated text of capped Output lines (guarded by logMu)
+	pager             *pager
 	logMu             sync.Mutex
+	zone              displayZone // zone for Output timestamps (Z cycles)
+	ansi              ansiMode    // escape codes in container output (LAZYKAMAL_ANSI)
+	resize            resizeDebounce
 	logScroll         int
+	logDropped        int         // lines dropped from the front of logLines (buffer trim, clear)
+	errorJumps        []int       // log positions (counting logDropped) of the last action's errors
+	errorJumpNext     int         // next errorJumps entry for e
+	logFocus          bool        // navigation keys scroll the log, not the list (Tab)
+	search            logSearch   // Output search (/, n/N); guarded by logMu
+	searchInput       *inputState // the search term being typed, nil otherwise
 	running           bool
 	runningCmd        string
 	cmdStartTime      time.Time
@@ -41,11 +53,29 @@ type ServerGUI struct {
 	// Confirmation dialog
 	confirm    *confirmState
 	prevScreen ServerScreen
+	pagerPrev  ServerScreen
+	debug      bool // LAZYKAMAL_DEBUG: extra diagnostics in the log
 	// Live log streaming
 	streamMu           sync.Mutex
 	streamingLogs      bool
 	liveLogsStop       chan struct{}
 	streamingContainer string
+	stream             *streamStats // what the stream received
+	logPause           *logPause
+	// Container chosen for "Save logs…", and the line count being typed
+	// there ("" takes the selected preset)
+	saveLogsTarget ContainerInfo
+	saveLogsLines  string
+	images         map[string]docker.RunningImage // by container ID, for digests (guarded by imagesMu)
+	imagesMu       sync.Mutex
+	export         *exportPrompt  // "Export inventory" screen state
+	forward        *forwardPrompt // "Port forward…" screen state
+	tunnels        []*tunnel      // running port forwards (guarded by tunnelsMu)
+	tunnelsMu      sync.Mutex
+	skew           skewProbe
+	startup        serverStartup // connecting and discovering apps after the TUI is up
+	handoff        handoff       // external program waiting for the terminal
+	done           chan struct{} // closed when the TUI exits
 }
 
 // ServerScreen represents the current screen in server mode
@@ -59,30 +89,20 @@ const (
 	ServerScreenProxyMenu   // Submenu: Proxy operations
 	ServerScreenHelp
 	ServerScreenConfirm
+	ServerScreenSaveLogs    // Choose how much of a container's log to save
+	ServerScreenExport      // Export inventory: format and local path
+	ServerScreenPager       // Truncated Output lines in full
+	ServerScreenPortForward // Choose a container port and local port to forward
 )
 
-// NewServerMode creates a new server mode GUI
+// NewServerMode creates a new server mode GUI. It does not touch the
+// network: Run brings the TUI up and connects to host in the background.
 func NewServerMode(version, host string) (*ServerGUI, error) {
 	client := ssh.NewClient(host)
 
-	// Test connection
-	fmt.Printf("Testing SSH connection to %s...\n", client.HostDisplay())
-	if err := client.TestConnection(); err != nil {
-		return nil, fmt.Errorf("SSH connection failed: %w\nMake sure you can run: ssh %s", err, client.HostDisplay())
-	}
-	fmt.Println("Connected!")
-
-	// Discover apps
-	fmt.Println("Discovering Kamal apps...")
-	apps, err := docker.DiscoverApps(client)
-	if err != nil {
-		return nil, fmt.Errorf("failed to discover apps: %w", err)
-	}
-	fmt.Printf("Found %d app(s)\n", len(apps))
-
 	g, err := gocui.NewGui(gocui.OutputNormal)
 	if err != nil {
-		return nil, err
+		return nil, &StartupError{Stage: StageTerminal, Err: err}
 	}
 
 	gui := &ServerGUI{
@@ -90,32 +110,85 @@ func NewServerMode(version, host string) (*ServerGUI, error) {
 		version:  version,
 		host:     host,
 		client:   client,
-		apps:     apps,
 		screen:   ServerScreenApps,
-		logLines: make([]string, 0, 1000),
+		logLines: make([]logEntry, 0, 1000),
+		long:     newLongLines("X"),
+		ansi:     ansiModeFromEnv(),
+		logPause: newLogPause(pauseBufLimit),
+		debug:    debugEnabled(),
+		done:     make(chan struct{}),
+	}
+	for _, note := range termNotes {
+		gui.logInfo(note)
 	}
+	client.OnRun = gui.echoCommand
 
 	// Initialize spinner with update function
 	gui.spinner = NewSpinner("", func() {
-		g.Update(func(g *gocui.Gui) error { return nil })
+		gui.g.Update(func(g *gocui.Gui) error { return nil })
 	})
 	gui.spinner.Start()
 
+	if err := gui.attach(g); err != nil {
+		gui.spinner.Stop()
+		g.Close()
+		return nil, &StartupError{Stage: StageTerminal, Err: err}
+	}
+
+	return gui, nil
+}
+
+// attach sets up layout and keybindings on a new gocui instance.
+func (gui *ServerGUI) attach(g *gocui.Gui) error {
 	g.SetManagerFunc(gui.layout)
 	g.Cursor = false
 	g.Mouse = false
-
+	g.ASCII = asciiOutput
 	if err := gui.keybindings(g); err != nil {
-		return nil, err
+		return err
 	}
+	gui.g = g
+	return nil
+}
 
-	return gui, nil
+func (gui *ServerGUI) ui() *gocui.Gui { return gui.g }
+
+// pause stops the log stream and the spinner before the terminal is
+// handed to another program.
+func (gui *ServerGUI) pause() {
+	gui.stopLogStream()
+	gui.spinner.Stop()
+}
+
+// resume recreates the gocui instance after an external program.
+func (gui *ServerGUI) resume() error {
+	g, err := gocui.NewGui(gocui.OutputNormal)
+	if err != nil {
+		return err
+	}
+	if err := gui.attach(g); err != nil {
+		g.Close()
+		return err
+	}
+	gui.spinner.Start()
+	return nil
 }
 
 // Run starts the server mode GUI
-func (gui *ServerGUI) Run() error {
-	defer gui.g.Close()
-	return gui.g.MainLoop()
+func (gui *ServerGUI) Run() (err error) {
+	defer recoverCrash(gui.version, &err)
+	defer func() { gui.g.Close() }()
+	defer close(gui.done)
+	defer gui.stopTunnels()
+	gui.connect()
+	gui.watchClockSkew()
+	return runMainLoop(gui, &gui.handoff)
+}
+
+// Stop ends the main loop from another goroutine, e.g. on SIGTERM; Run
+// then restores the terminal and returns.
+func (gui *ServerGUI) Stop() {
+	gui.g.Update(quit)
 }
 
 // Close tears down the gocui instance, restoring terminal state.
@@ -126,9 +199,16 @@ func (gui *ServerGUI) Close() {
 // layout manages the server mode layout
 func (gui *ServerGUI) layout(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
+	if gui.resize.skipFrame(g, maxX, maxY) {
+		return nil
+	}
+	panels := serverLayout(maxX, maxY)
+	if !panels.valid() {
+		return nil
+	}
 
 	// Header
-	if v, err := g.SetView(viewHeader, 0, 0, maxX-1, 2); err != nil {
+	if v, err := setView(g, viewHeader, panels.header); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -138,11 +218,7 @@ func (gui *ServerGUI) layout(g *gocui.Gui) error {
 	gui.renderHeader(g)
 
 	// Left panel (apps list or menu)
-	leftW := maxX / 3
-	if leftW < 30 {
-		leftW = 30
-	}
-	if v, err := g.SetView(viewMain, 0, 3, leftW-1, maxY-1); err != nil {
+	if v, err := setView(g, viewMain, panels.main); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -152,8 +228,7 @@ func (gui *ServerGUI) layout(g *gocui.Gui) error {
 	gui.renderLeftPanel(g)
 
 	// Right panel - Status
-	statusH := (maxY - 3) / 2
-	if v, err := g.SetView(viewStatus, leftW, 3, maxX-1, 3+statusH); err != nil {
+	if v, err := setView(g, viewStatus, panels.status); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -164,7 +239,7 @@ func (gui *ServerGUI) layout(g *gocui.Gui) error {
 	gui.renderStatus(g)
 
 	// Right panel - Logs
-	if v, err := g.SetView(viewLog, leftW, 4+statusH, maxX-1, maxY-1); err != nil {
+	if v, err := setView(g, viewLog, panels.log); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -185,6 +260,10 @@ func (gui *ServerGUI) layout(g *gocui.Gui) error {
 		return gui.renderHelpOverlay(g)
 	}
 
+	if gui.screen == ServerScreenPager {
+		return renderPager(g, gui.pager)
+	}
+
 	g.SetCurrentView(viewMain)
 	return nil
 }
@@ -206,8 +285,18 @@ func (gui *ServerGUI) renderHeader(g *gocui.Gui) {
 	cmdStart := gui.cmdStartTime
 	gui.cmdMu.Unlock()
 
-	status := green("✓ Connected")
-	if isStreaming {
+	status := green(iconSuccess + " Connected")
+	switch phase, _, _ := gui.startup.state(); phase {
+	case phaseConnecting:
+		status = yellow(gui.spinner.Frame()) + " Connecting " + dim("Ctrl+X cancel")
+	case phaseDiscovering:
+		status = yellow(gui.spinner.Frame()) + " Discovering apps " + dim("Ctrl+X cancel")
+	case phaseFailed:
+		status = red(iconError+" Not connected") + " " + dim("(r to retry)")
+	}
+	if isStreaming && gui.logPause.IsPaused() {
+		status = yellow(iconPause) + " Paused " + dim("(Space to resume)")
+	} else if isStreaming {
 		status = cyan(gui.spinner.Frame()) + " Streaming logs " + dim("(Esc to stop)")
 	} else if isRunning {
 		elapsed := time.Since(cmdStart)
@@ -216,12 +305,15 @@ func (gui *ServerGUI) renderHeader(g *gocui.Gui) {
 
 	// Show mode indicator prominently
 	modeLabel := yellow("[SERVER MODE]") + " " + cyan(gui.client.HostDisplay())
+	if warn := skewWarning(gui.skew.current(gui.host)); warn != "" {
+		modeLabel += " " + yellow(iconWarning+" "+warn)
+	}
 
-	fmt.Fprintf(v, " %s%s %s | %s | %s | %s",
+	fmt.Fprint(v, glyphs(fmt.Sprintf(" %s%s %s | %s | %s | %s",
 		iconRocket, bold("Lazykamal"), dim(gui.version),
 		modeLabel,
 		status,
-		dim("?: help"))
+		dim("?: help"))))
 }
 
 func (gui *ServerGUI) renderLeftPanel(g *gocui.Gui) {
@@ -242,17 +334,25 @@ func (gui *ServerGUI) renderLeftPanel(g *gocui.Gui) {
 		gui.renderActionsMenu(v)
 	case ServerScreenProxyMenu:
 		gui.renderProxyMenu(v)
+	case ServerScreenSaveLogs:
+		gui.renderSaveLogs(v)
+	case ServerScreenExport:
+		gui.renderExport(v)
+	case ServerScreenPortForward:
+		gui.renderPortForward(v)
 	}
 }
 
 func (gui *ServerGUI) renderAppsList(v *gocui.View) {
 	v.Title = fmt.Sprintf(" Apps on %s ", gui.client.Host)
+	width, _ := v.Size()
 
+	if phase, err, since := gui.startup.state(); phase != phaseReady {
+		writeStartupState(v, phase, err, gui.client.HostDisplay(), time.Since(since), gui.spinner.Frame(), width)
+		return
+	}
 	if len(gui.apps) == 0 {
-		fmt.Fprintln(v, " No Kamal apps found on this server.")
-		fmt.Fprintln(v, "")
-		fmt.Fprintln(v, " Make sure apps are deployed with Kamal")
-		fmt.Fprintln(v, " and Docker is running.")
+		writeEmptyState(v, emptyServerNoApps)
 		return
 	}
 
@@ -265,36 +365,35 @@ func (gui *ServerGUI) renderAppsList(v *gocui.View) {
 		running := docker.CountRunning(app.Containers)
 		total := len(app.Containers)
 		version := docker.GetAppVersion(app.Containers)
+		health := docker.ComputeHealth(app)
 
-		status := green("●")
-		if running == 0 {
-			status = red("●")
-		} else if running < total {
-			status = yellow("●")
-		}
-
-		line := fmt.Sprintf("%s%s %s (%s)", prefix, status, app.Service, app.Destination)
+		line := fmt.Sprintf("%s%s %s (%s)", prefix, healthDot(health.Worst()), app.Service, app.Destination)
 		if version != "" && version != "unknown" {
 			line += dim(fmt.Sprintf(" [%s]", truncate(version, 12)))
 		}
-		fmt.Fprintln(v, line)
+		line += " " + healthSummary(health, len(app.Accessories) > 0)
+		fmt.Fprintln(v, truncate(line, width))
 
 		// Show container count and accessories
 		if i == gui.selectedApp {
-			fmt.Fprintf(v, "    %s Web: %d/%d containers\n", dim("├─"), running, total)
+			fmt.Fprintf(v, "    %s Web: %d/%d containers\n", dim(iconBranch), running, total)
 			for j, acc := range app.Accessories {
 				accRunning := docker.CountRunning(acc.Containers)
-				prefix := "├─"
+				prefix := iconBranch
 				if j == len(app.Accessories)-1 {
-					prefix = "└─"
+					prefix = iconBranchLast
+				}
+				entry := fmt.Sprintf("%s: %d/%d running", acc.Name, accRunning, len(acc.Containers))
+				if accRunning < len(acc.Containers) || len(acc.Containers) == 0 {
+					entry = red(iconError + " " + entry)
 				}
-				fmt.Fprintf(v, "    %s %s: %d container(s)\n", dim(prefix), acc.Name, accRunning)
+				fmt.Fprintf(v, "    %s %s\n", dim(prefix), entry)
 			}
 		}
 	}
 
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim(" ↑/↓ select  Enter: menu  r: refresh"))
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓ select  Enter: menu  r: refresh  E: export")))
 }
 
 func (gui *ServerGUI) renderAppMenu(v *gocui.View) {
@@ -328,14 +427,14 @@ func (gui *ServerGUI) renderAppMenu(v *gocui.View) {
 
 		label := item.label
 		if item.submenu {
-			label += " →"
+			label += glyphs(" →")
 		}
 
 		fmt.Fprintln(v, prefix+label)
 	}
 
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim(" ↑/↓: navigate  Enter: select  b: back"))
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓: navigate  Enter: select  b: back")))
 }
 
 func (gui *ServerGUI) renderActionsMenu(v *gocui.View) {
@@ -343,7 +442,7 @@ func (gui *ServerGUI) renderActionsMenu(v *gocui.View) {
 		return
 	}
 	app := gui.apps[gui.selectedApp]
-	v.Title = fmt.Sprintf(" %s › Actions ", app.Service)
+	v.Title = fmt.Sprintf(" %s %s Actions ", app.Service, iconArrow)
 
 	// Actions submenu: 0-7 items
 	menuItems := []struct {
@@ -358,7 +457,8 @@ func (gui *ServerGUI) renderActionsMenu(v *gocui.View) {
 		{"Images", false},        // 5
 		{"Version", false},       // 6
 		{"Health", false},        // 7
-		{"Back", false},          // 8
+		{"Verify digest", false}, // 8
+		{"Back", false},          // 9
 	}
 
 	for i, item := range menuItems {
@@ -376,13 +476,13 @@ func (gui *ServerGUI) renderActionsMenu(v *gocui.View) {
 	}
 
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim(" ↑/↓: navigate  Enter: select  b: back"))
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓: navigate  Enter: select  b: back")))
 }
 
 func (gui *ServerGUI) renderProxyMenu(v *gocui.View) {
 	v.Title = " Proxy "
 
-	// Proxy submenu: 0-6 items
+	// Proxy submenu: 0-7 items
 	menuItems := []struct {
 		label  string
 		danger bool
@@ -393,7 +493,8 @@ func (gui *ServerGUI) renderProxyMenu(v *gocui.View) {
 		{"Reboot", false},      // 3
 		{"Stop", true},         // 4 - destructive
 		{"Start", false},       // 5
-		{"Back", false},        // 6
+		{"Upgrade", true},      // 6 - recreates the container
+		{"Back", false},        // 7
 	}
 
 	for i, item := range menuItems {
@@ -411,7 +512,7 @@ func (gui *ServerGUI) renderProxyMenu(v *gocui.View) {
 	}
 
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim(" ↑/↓: navigate  Enter: select  b: back"))
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓: navigate  Enter: select  b: back")))
 }
 
 func (gui *ServerGUI) renderContainerSelect(v *gocui.View) {
@@ -426,27 +527,9 @@ func (gui *ServerGUI) renderContainerSelect(v *gocui.View) {
 		gui.buildContainerList()
 	}
 
-	for i, ci := range gui.allContainers {
-		prefix := "  "
-		if i == gui.selectedContainer {
-			prefix = cyan(iconArrow) + " "
-		}
-
-		status := green("●")
-		if ci.Container.State != "running" {
-			status = red("●")
-		}
-
-		name := ci.Container.Name
-		if len(name) > 25 {
-			name = name[:22] + "..."
-		}
-
-		line := fmt.Sprintf("%s%s %s", prefix, status, name)
-		if ci.Role != "" {
-			line += dim(fmt.Sprintf(" [%s]", ci.Role))
-		}
-		fmt.Fprintln(v, line)
+	width, _ := v.Size()
+	for _, row := range containerRows(gui.allContainers, gui.selectedContainer, width, time.Now()) {
+		fmt.Fprintln(v, row)
 	}
 
 	fmt.Fprintln(v, "")
@@ -458,6 +541,9 @@ func (gui *ServerGUI) renderContainerSelect(v *gocui.View) {
 		fmt.Fprintln(v, "")
 		fmt.Fprintln(v, dim(" Actions:"))
 		fmt.Fprintln(v, "   l - View Logs")
+		fmt.Fprintln(v, glyphs("   L - Save logs…"))
+		fmt.Fprintln(v, "   i - Labels")
+		fmt.Fprintln(v, glyphs("   P - Port forward…"))
 		fmt.Fprintln(v, "   r - Restart")
 		fmt.Fprintln(v, "   s - Stop")
 		fmt.Fprintln(v, "   S - Start")
@@ -467,7 +553,7 @@ func (gui *ServerGUI) renderContainerSelect(v *gocui.View) {
 	}
 
 	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim(" ↑/↓ select  b/Esc: back"))
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓ select  b/Esc: back")))
 }
 
 func (gui *ServerGUI) buildContainerList() {
@@ -494,6 +580,7 @@ func (gui *ServerGUI) buildContainerList() {
 			})
 		}
 	}
+	sortContainers(gui.allContainers)
 }
 
 func (gui *ServerGUI) renderStatus(g *gocui.Gui) {
@@ -502,9 +589,10 @@ func (gui *ServerGUI) renderStatus(g *gocui.Gui) {
 		return
 	}
 	v.Clear()
+	gui.writeTunnels(v)
 
 	if gui.selectedApp >= len(gui.apps) {
-		fmt.Fprintln(v, " Select an app to view details")
+		writeEmptyState(v, emptyServerDetails)
 		return
 	}
 
@@ -512,7 +600,7 @@ func (gui *ServerGUI) renderStatus(g *gocui.Gui) {
 
 	fmt.Fprintf(v, " Service: %s\n", bold(app.Service))
 	fmt.Fprintf(v, " Destination: %s\n", app.Destination)
-	fmt.Fprintf(v, " Version: %s\n", docker.GetAppVersion(app.Containers))
+	fmt.Fprintf(v, " Version: %s\n", gui.versionWithDigest(app.Containers))
 	fmt.Fprintf(v, " Proxy: %s\n", formatProxyStatus(app.ProxyStatus))
 	fmt.Fprintln(v, "")
 
@@ -522,9 +610,9 @@ func (gui *ServerGUI) renderStatus(g *gocui.Gui) {
 	fmt.Fprintf(v, " Containers: %d/%d running\n", running, total)
 
 	for _, c := range app.Containers {
-		status := green("●")
+		status := green(iconRunning)
 		if c.State != "running" {
-			status = red("●")
+			status = red(iconRunning)
 		}
 		fmt.Fprintf(v, "   %s %s (%s)\n", status, truncate(c.Name, 30), c.State)
 	}
@@ -535,21 +623,54 @@ func (gui *ServerGUI) renderStatus(g *gocui.Gui) {
 		fmt.Fprintln(v, " Accessories:")
 		for _, acc := range app.Accessories {
 			accRunning := docker.CountRunning(acc.Containers)
-			status := green("●")
+			status := green(iconRunning)
 			if accRunning == 0 {
-				status = red("●")
+				status = red(iconRunning)
 			}
 			fmt.Fprintf(v, "   %s %s (%d container(s))\n", status, acc.Name, len(acc.Containers))
 		}
 	}
 }
 
+// healthDot renders a colored status dot for a health state.
+func healthDot(h docker.HealthState) string {
+	switch h {
+	case docker.HealthOK:
+		return green(iconRunning)
+	case docker.HealthDegraded:
+		return yellow(iconRunning)
+	default:
+		return red(iconRunning)
+	}
+}
+
+// healthMark renders a compact ✓/~/✗ for a health state.
+func healthMark(h docker.HealthState) string {
+	switch h {
+	case docker.HealthOK:
+		return green(iconSuccess)
+	case docker.HealthDegraded:
+		return yellow("~")
+	default:
+		return red(iconError)
+	}
+}
+
+// healthSummary renders the compound "web ✓ acc ✗" indicator.
+func healthSummary(h docker.AppHealth, hasAccessories bool) string {
+	s := dim("web ") + healthMark(h.Web)
+	if hasAccessories {
+		s += dim(" acc ") + healthMark(h.Accessories)
+	}
+	return s
+}
+
 func formatProxyStatus(status string) string {
 	switch status {
 	case "running":
-		return green("✓ running")
+		return green(iconSuccess + " running")
 	case "not running":
-		return red("✗ not running")
+		return red(iconError + " not running")
 	default:
 		return yellow(status)
 	}
@@ -566,44 +687,37 @@ func (gui *ServerGUI) renderLog(g *gocui.Gui) {
 	gui.streamMu.Lock()
 	isStreaming := gui.streamingLogs
 	streamContainer := gui.streamingContainer
+	stats := gui.stream
 	gui.streamMu.Unlock()
-	if isStreaming {
-		v.Title = fmt.Sprintf(" LIVE: %s (Esc to stop) ", truncate(streamContainer, 20))
+	if isStreaming && gui.logPause.IsPaused() {
+		v.Title = fmt.Sprintf(" %s: %s ", gui.logPause.Label(), truncate(streamContainer, 20))
+	} else if isStreaming {
+		v.Title = " " + stats.title(time.Now()) + " (Esc to stop) "
 	} else {
 		v.Title = " Output / Logs "
 	}
-
-	gui.logMu.Lock()
-	lines := append([]string(nil), gui.logLines...)
-	gui.logMu.Unlock()
-
-	if len(lines) == 0 {
-		fmt.Fprintln(v, " Output will appear here.")
-		fmt.Fprintln(v, "")
-		fmt.Fprintln(v, dim(" Select an app and run a command"))
-		return
-	}
+	skew := gui.skew.current(gui.host)
+	v.Title += "[" + zoneLabel(gui.zone, skew) + "] "
 
 	_, viewHeight := v.Size()
 	if viewHeight < 1 {
 		viewHeight = 1
 	}
-
-	maxScroll := len(lines) - viewHeight
-	if maxScroll < 0 {
-		maxScroll = 0
-	}
-	if gui.logScroll > maxScroll {
-		gui.logScroll = maxScroll
+	gui.logMu.Lock()
+	lines := renderSearched(gui.logLines, gui.zone, skew, gui.search.term)
+	start, end := clampLogWindow(&gui.logScroll, len(lines), viewHeight)
+	if gui.search.active() {
+		v.Title += searchTitle(&gui.search, gui.logMatches(), gui.logDropped)
 	}
-	if gui.logScroll < 0 {
-		gui.logScroll = 0
+	gui.logMu.Unlock()
+	if gui.searchInput != nil {
+		v.Title += "/" + gui.searchInput.text() + "_ "
 	}
+	v.Title = focusTitle(v.Title, gui.logFocus)
 
-	start := gui.logScroll
-	end := start + viewHeight
-	if end > len(lines) {
-		end = len(lines)
+	if len(lines) == 0 {
+		writeEmptyState(v, emptyServerLog)
+		return
 	}
 
 	for _, l := range lines[start:end] {
@@ -613,12 +727,11 @@ func (gui *ServerGUI) renderLog(g *gocui.Gui) {
 
 func (gui *ServerGUI) renderHelpOverlay(g *gocui.Gui) error {
 	maxX, maxY := g.Size()
-	width := 60
-	height := 28
-	x0 := (maxX - width) / 2
-	y0 := (maxY - height) / 2
-
-	if v, err := g.SetView(viewHelp, x0, y0, x0+width, y0+height); err != nil {
+	r := centeredRect(maxX, maxY, 60, 30, 0, 0)
+	if !r.valid() {
+		return nil
+	}
+	if v, err := setView(g, viewHelp, r); err != nil {
 		if err != gocui.ErrUnknownView {
 			return err
 		}
@@ -631,46 +744,68 @@ func (gui *ServerGUI) renderHelpOverlay(g *gocui.Gui) error {
 		return nil
 	}
 	v.Clear()
-
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, yellow("  ╔══════════════════════════════════════════════════╗"))
-	fmt.Fprintln(v, yellow("  ║")+bold("          YOU ARE IN SERVER MODE                ")+yellow("║"))
-	fmt.Fprintln(v, yellow("  ╚══════════════════════════════════════════════════╝"))
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, "  Server Mode connects via SSH to manage containers")
-	fmt.Fprintln(v, "  using Docker commands directly on the server.")
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim("  Available: logs, start, stop, restart, health, etc."))
-	fmt.Fprintln(v, red("  NOT available: deploy, redeploy, rollback, build"))
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, cyan("  For deploy commands, use Project Mode:"))
-	fmt.Fprintln(v, "    $ lazykamal /path/to/kamal/project")
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, " ──────────────────────────────────────────────────────")
-	fmt.Fprintln(v, "  KEYBOARD SHORTCUTS")
-	fmt.Fprintln(v, " ──────────────────────────────────────────────────────")
-	fmt.Fprintln(v, "   ↑/↓       Navigate       j/k       Scroll logs")
-	fmt.Fprintln(v, "   Enter     Select         c         Clear log")
-	fmt.Fprintln(v, "   b/Esc     Go back        r         Refresh apps")
-	fmt.Fprintln(v, "   Ctrl+X    Cancel cmd     ?         Help")
-	fmt.Fprintln(v, "   q         Quit")
-	fmt.Fprintln(v, "")
-	fmt.Fprintln(v, dim("  Press ? or Esc to close"))
+	out := glyphWriter{v}
+
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, yellow("  ╔══════════════════════════════════════════════════╗"))
+	fmt.Fprintln(out, yellow("  ║")+bold("          YOU ARE IN SERVER MODE                ")+yellow("║"))
+	fmt.Fprintln(out, yellow("  ╚══════════════════════════════════════════════════╝"))
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "  Server Mode connects via SSH to manage containers")
+	fmt.Fprintln(out, "  using Docker commands directly on the server.")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, dim("  Available: logs, start, stop, restart, health, etc."))
+	fmt.Fprintln(out, red("  NOT available: deploy, redeploy, rollback, build"))
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, cyan("  For deploy commands, use Project Mode:"))
+	fmt.Fprintln(out, "    $ lazykamal /path/to/kamal/project")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, " ──────────────────────────────────────────────────────")
+	fmt.Fprintln(out, "  KEYBOARD SHORTCUTS")
+	fmt.Fprintln(out, " ──────────────────────────────────────────────────────")
+	fmt.Fprintln(out, "   ↑/↓       Navigate       j/k       Scroll logs")
+	fmt.Fprintln(out, "   Tab       Focus list / log for ↑/↓ and paging")
+	fmt.Fprintln(out, "   PgUp/PgDn Page (10 items) Home/End, g/G  First/last")
+	fmt.Fprintln(out, "   Enter     Select         c         Clear log")
+	fmt.Fprintln(out, "   b/Esc     Go back        r         Refresh apps")
+	fmt.Fprintln(out, "   Ctrl+X    Cancel cmd     ?         Help")
+	fmt.Fprintln(out, "   Space     Pause/resume live logs")
+	fmt.Fprintln(out, "   Z         Timestamps: local / UTC / server")
+	fmt.Fprintln(out, "   E         Export inventory (JSON/CSV)")
+	fmt.Fprintln(out, "   P         Port forward a container port over SSH")
+	fmt.Fprintln(out, "   T         Stop all tunnels")
+	fmt.Fprintln(out, "   X         Expand a truncated log line")
+	fmt.Fprintln(out, "   Ctrl+S    Save the Output log to a file")
+	fmt.Fprintln(out, "   e         Jump to the last action's next error")
+	fmt.Fprintln(out, "   /  n/N    Search the log, next/previous match")
+	fmt.Fprintln(out, "   q         Quit")
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, dim("  Press ? or Esc to close"))
 
 	return nil
 }
 
 func (gui *ServerGUI) appendLog(lines []string) {
+	gui.appendLogAt(lines)
+}
+
+// appendLogAt appends lines and returns the log position (counting
+// logDropped) of the first, taken under the same lock so concurrent
+// writers cannot shift it.
+func (gui *ServerGUI) appendLogAt(lines []string) int {
 	gui.logMu.Lock()
 	defer gui.logMu.Unlock()
+	pos := gui.logDropped + len(gui.logLines)
 	for _, line := range lines {
-		gui.logLines = append(gui.logLines, timestampedLine(sanitizeLogLine(line)))
+		gui.logLines = append(gui.logLines, gui.long.entry(glyphs(sanitizeLogLine(line))))
 	}
 	if len(gui.logLines) > 1000 {
+		gui.logDropped += len(gui.logLines) - 1000
 		gui.logLines = gui.logLines[len(gui.logLines)-1000:]
 	}
 	// Auto-scroll to bottom
 	gui.logScroll = len(gui.logLines)
+	return pos
 }
 
 func (gui *ServerGUI) logSuccess(msg string) {
@@ -702,20 +837,34 @@ func (gui *ServerGUI) cancelCommand() {
 
 // keybindings sets up server mode keybindings
 func (gui *ServerGUI) keybindings(g *gocui.Gui) error {
+	// notTyping wraps a single-key global so it stands aside while a path
+	// or an Output search is being typed.
+	notTyping := func(h func(*gocui.Gui, *gocui.View) error) func(*gocui.Gui, *gocui.View) error {
+		return func(g *gocui.Gui, v *gocui.View) error {
+			if gui.typing() || gui.screen == ServerScreenPager {
+				return nil
+			}
+			return h(g, v)
+		}
+	}
+
 	// Quit
 	if err := g.SetKeybinding("", gocui.KeyCtrlC, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
 		return gocui.ErrQuit
 	}); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 'q', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+	if err := g.SetKeybinding("", 'q', gocui.ModNone, notTyping(func(g *gocui.Gui, v *gocui.View) error {
 		return gocui.ErrQuit
-	}); err != nil {
+	})); err != nil {
 		return err
 	}
 
 	// Cancel running command
 	if err := g.SetKeybinding("", gocui.KeyCtrlX, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		if gui.cancelStartup() {
+			return nil
+		}
 		gui.cancelCommand()
 		return nil
 	}); err != nil {
@@ -735,86 +884,136 @@ func (gui *ServerGUI) keybindings(g *gocui.Gui) error {
 	if err := g.SetKeybinding("", gocui.KeyEsc, gocui.ModNone, gui.keyBack); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 'b', gocui.ModNone, gui.keyBack); err != nil {
+	if err := g.SetKeybinding("", 'b', gocui.ModNone, notTyping(gui.keyBack)); err != nil {
 		return err
 	}
 
 	// Refresh
-	if err := g.SetKeybinding("", 'r', gocui.ModNone, gui.keyRefresh); err != nil {
+	if err := g.SetKeybinding("", 'r', gocui.ModNone, notTyping(gui.keyRefresh)); err != nil {
 		return err
 	}
 
 	// Help
-	if err := g.SetKeybinding("", '?', gocui.ModNone, gui.keyHelp); err != nil {
+	if err := g.SetKeybinding("", '?', gocui.ModNone, notTyping(gui.keyHelp)); err != nil {
 		return err
 	}
 
 	// Clear log
-	if err := g.SetKeybinding("", 'c', gocui.ModNone, gui.keyClearLog); err != nil {
+	if err := g.SetKeybinding("", 'c', gocui.ModNone, notTyping(gui.keyClearLog)); err != nil {
 		return err
 	}
 
 	// Scroll
-	if err := g.SetKeybinding("", 'j', gocui.ModNone, gui.keyScrollDown); err != nil {
+	if err := g.SetKeybinding("", 'j', gocui.ModNone, notTyping(gui.keyScrollDown)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("", 'k', gocui.ModNone, notTyping(gui.keyScrollUp)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 'k', gocui.ModNone, gui.keyScrollUp); err != nil {
+	// Tab moves focus between the list and the log; PgUp/PgDn, Home/End and
+	// g/G page or jump in whichever has it.
+	if err := g.SetKeybinding("", gocui.KeyTab, gocui.ModNone, gui.keyToggleFocus); err != nil {
 		return err
 	}
+	for key, h := range map[interface{}]func(*gocui.Gui, *gocui.View) error{
+		gocui.KeyPgup: gui.keyPage(-1),
+		gocui.KeyPgdn: gui.keyPage(1),
+		gocui.KeyHome: gui.keyJump(-1),
+		gocui.KeyEnd:  gui.keyJump(1),
+		'g':           gui.keyJump(-1),
+		'G':           gui.keyJump(1),
+	} {
+		if err := g.SetKeybinding("", key, gocui.ModNone, h); err != nil {
+			return err
+		}
+	}
 
-	// Confirm dialog keybindings
-	if err := g.SetKeybinding(viewServerConfirm, gocui.KeyArrowLeft, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirmLeft()
+	// Pause/resume live log stream
+	if err := g.SetKeybinding("", gocui.KeySpace, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		if gui.screen == ServerScreenConfirm || gui.screen == ServerScreenHelp || gui.screen == ServerScreenPager || gui.typing() {
+			return nil
+		}
+		gui.togglePauseLogs()
 		return nil
 	}); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewServerConfirm, gocui.KeyArrowRight, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirmRight()
-		return nil
-	}); err != nil {
+
+	// Confirm dialog keybindings
+	if err := bindConfirmKeys(g, viewServerConfirm, func() *confirmState { return gui.confirm }, gui.answerConfirm); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewServerConfirm, gocui.KeyEnter, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirmEnter()
-		return nil
-	}); err != nil {
+
+	// Container actions (in container select screen)
+	if err := g.SetKeybinding("", 'l', gocui.ModNone, notTyping(gui.keyContainerLogs)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewServerConfirm, gocui.KeyEsc, gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.closeConfirm()
-		return nil
-	}); err != nil {
+	if err := g.SetKeybinding("", 's', gocui.ModNone, notTyping(gui.keyContainerStop)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewServerConfirm, 'n', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.closeConfirm()
-		return nil
-	}); err != nil {
+	if err := g.SetKeybinding("", 'S', gocui.ModNone, notTyping(gui.keyContainerStart)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding(viewServerConfirm, 'y', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
-		gui.confirm.Selected = 0
-		gui.confirmEnter()
-		return nil
-	}); err != nil {
+	if err := g.SetKeybinding("", 'x', gocui.ModNone, notTyping(gui.keyContainerRemove)); err != nil {
 		return err
 	}
-
-	// Container actions (in container select screen)
-	if err := g.SetKeybinding("", 'l', gocui.ModNone, gui.keyContainerLogs); err != nil {
+	if err := g.SetKeybinding("", 'L', gocui.ModNone, notTyping(gui.keyContainerSaveLogs)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("", 'i', gocui.ModNone, notTyping(gui.keyContainerLabels)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("", 'P', gocui.ModNone, notTyping(gui.keyContainerPortForward)); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("", 'T', gocui.ModNone, notTyping(gui.keyStopTunnels)); err != nil {
+		return err
+	}
+	if err := gui.bindForwardKeys(g); err != nil {
+		return err
+	}
+	if err := gui.bindSaveLogsKeys(g); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("", 'e', gocui.ModNone, gui.keyJumpError); err != nil {
+		return err
+	}
+	if err := g.SetKeybinding("", 'Z', gocui.ModNone, notTyping(gui.keyCycleZone)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 's', gocui.ModNone, gui.keyContainerStop); err != nil {
+	if err := g.SetKeybinding("", gocui.KeyCtrlS, gocui.ModNone, gui.keyExportLog); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 'S', gocui.ModNone, gui.keyContainerStart); err != nil {
+	if err := g.SetKeybinding("", 'E', gocui.ModNone, notTyping(gui.keyExportInventory)); err != nil {
 		return err
 	}
-	if err := g.SetKeybinding("", 'x', gocui.ModNone, gui.keyContainerRemove); err != nil {
+	if err := gui.bindExportKeys(g); err != nil {
 		return err
 	}
+	// X = show truncated Output lines in full (x removes containers)
+	if err := g.SetKeybinding("", 'X', gocui.ModNone, func(g *gocui.Gui, v *gocui.View) error {
+		if gui.typing() {
+			return nil
+		}
+		return gui.keyExpandLine(g, v)
+	}); err != nil {
+		return err
+	}
+	if err := bindPagerKeys(g, func() *pager { return gui.pager }); err != nil {
+		return err
+	}
+	// After the globals: Enter and Esc must find the search still open.
+	if err := gui.bindLogSearchKeys(g); err != nil {
+		return err
+	}
+
+	return nil
+}
 
+func (gui *ServerGUI) keyCycleZone(g *gocui.Gui, v *gocui.View) error {
+	if gui.confirm == nil {
+		gui.zone = gui.zone.next()
+	}
 	return nil
 }
 
@@ -867,34 +1066,112 @@ func (gui *ServerGUI) keyContainerRemove(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *ServerGUI) removeContainer(ci ContainerInfo) {
+	app, _ := gui.currentApp()
 	gui.showConfirm("Confirm Remove", fmt.Sprintf("Remove container %s?", ci.Container.Name), func() {
 		gui.logInfo(fmt.Sprintf("Removing %s...", ci.Container.Name))
-		gui.cmdMu.Lock()
-		gui.running = true
-		gui.runningCmd = "Remove"
-		gui.cmdStartTime = time.Now()
-		gui.cmdMu.Unlock()
-
-		go func() {
-			defer func() {
-				gui.cmdMu.Lock()
-				gui.running = false
-				gui.cmdMu.Unlock()
-			}()
-			cmd := fmt.Sprintf("docker rm %s", ci.Container.ID)
-			if _, err := gui.client.Run(cmd); err != nil {
+		gui.runMutation("Remove", app, func() (bool, func()) {
+			if err := docker.RemoveContainer(gui.client, ci.Container.ID); err != nil {
 				gui.logError(fmt.Sprintf("Failed to remove %s: %s", ci.Container.Name, err.Error()))
-			} else {
-				gui.cmdMu.Lock()
-				start := gui.cmdStartTime
-				gui.cmdMu.Unlock()
-				gui.logSuccess(fmt.Sprintf("Removed %s in %s", ci.Container.Name, formatDuration(time.Since(start))))
-				gui.refreshAppsAndContainers()
+				return false, nil
 			}
-		}()
+			gui.cmdMu.Lock()
+			took := time.Since(gui.cmdStartTime)
+			gui.cmdMu.Unlock()
+			return true, func() { gui.logSuccess(fmt.Sprintf("Removed %s in %s", ci.Container.Name, formatDuration(took))) }
+		})
 	}, nil)
 }
 
+// currentApp returns the app selected in the Apps list, if any.
+func (gui *ServerGUI) currentApp() (docker.App, bool) {
+	if gui.selectedApp < 0 || gui.selectedApp >= len(gui.apps) {
+		return docker.App{}, false
+	}
+	return gui.apps[gui.selectedApp], true
+}
+
+// echoCommand logs the remote command line of a running action so it can be
+// rerun by hand on the server. Discovery and other reads are not echoed.
+func (gui *ServerGUI) echoCommand(command string) {
+	gui.cmdMu.Lock()
+	running := gui.running
+	gui.cmdMu.Unlock()
+	if running {
+		gui.appendLog([]string{dim("  $ " + command)})
+	}
+}
+
+// runMutation marks name as the running command and runs fn in the
+// background. When fn reports a change, only app's containers are re-listed
+// so status dots reflect it without a full rediscovery, and then fn's
+// summary line is logged, so the list and the message agree.
+func (gui *ServerGUI) runMutation(name string, app docker.App, fn func() (changed bool, summary func())) {
+	gui.cmdMu.Lock()
+	gui.running = true
+	gui.runningCmd = name
+	gui.cmdStartTime = time.Now()
+	gui.cmdMu.Unlock()
+
+	go func() {
+		defer func() {
+			gui.cmdMu.Lock()
+			gui.running = false
+			gui.cmdMu.Unlock()
+		}()
+		changed, summary := fn()
+		if changed && app.Service != "" {
+			gui.refreshApp(app)
+		}
+		if summary != nil {
+			summary()
+		}
+	}()
+}
+
+// refreshApp soft-refreshes one app's containers and updates the Apps list and
+// container list in place, keeping the selected container by ID.
+func (gui *ServerGUI) refreshApp(app docker.App) {
+	updated, err := docker.RefreshApp(gui.client, app)
+	if err != nil {
+		gui.logError("Failed to refresh: " + err.Error())
+		return
+	}
+	gui.loadRunningImages([]docker.App{updated})
+	gui.g.Update(func(*gocui.Gui) error {
+		for i := range gui.apps {
+			if gui.apps[i].Service == app.Service && gui.apps[i].Destination == app.Destination {
+				gui.apps[i] = updated
+			}
+		}
+		if gui.screen == ServerScreenContainerSelect {
+			selectedID := ""
+			if gui.selectedContainer < len(gui.allContainers) {
+				selectedID = gui.allContainers[gui.selectedContainer].Container.ID
+			}
+			gui.buildContainerList()
+			gui.selectedContainer = containerIndexByID(gui.allContainers, selectedID, gui.selectedContainer)
+		}
+		return nil
+	})
+}
+
+// containerIndexByID returns the index of id in list, or fallback clamped to
+// the list bounds when the container is gone.
+func containerIndexByID(list []ContainerInfo, id string, fallback int) int {
+	for i, ci := range list {
+		if id != "" && ci.Container.ID == id {
+			return i
+		}
+	}
+	if fallback >= len(list) {
+		fallback = len(list) - 1
+	}
+	if fallback < 0 {
+		fallback = 0
+	}
+	return fallback
+}
+
 // refreshAppsAndContainers refreshes apps from server and rebuilds container list
 func (gui *ServerGUI) refreshAppsAndContainers() {
 	apps, err := docker.DiscoverApps(gui.client)
@@ -903,6 +1180,7 @@ func (gui *ServerGUI) refreshAppsAndContainers() {
 		return
 	}
 	gui.apps = apps
+	go gui.loadRunningImages(apps)
 	// Rebuild container list for current app
 	if gui.screen == ServerScreenContainerSelect {
 		gui.buildContainerList()
@@ -917,53 +1195,41 @@ func (gui *ServerGUI) refreshAppsAndContainers() {
 }
 
 func (gui *ServerGUI) keyDown(g *gocui.Gui, v *gocui.View) error {
-	switch gui.screen {
-	case ServerScreenApps:
-		if gui.selectedApp < len(gui.apps)-1 {
-			gui.selectedApp++
-		}
-	case ServerScreenAppMenu:
-		// 7 items: Containers, Logs, Details, Actions, Proxy, Exec, Back
-		if gui.selectedItem < 6 {
-			gui.selectedItem++
-		}
-	case ServerScreenActionsMenu:
-		// 9 items: Boot, Start, Stop, Restart, Remove, Images, Version, Health, Back
-		if gui.selectedItem < 8 {
-			gui.selectedItem++
-		}
-	case ServerScreenProxyMenu:
-		// 7 items: Logs, Details, Restart, Reboot, Stop, Start, Back
-		if gui.selectedItem < 6 {
-			gui.selectedItem++
-		}
-	case ServerScreenContainerSelect:
-		if gui.selectedContainer < len(gui.allContainers)-1 {
-			gui.selectedContainer++
-		}
+	if gui.searchInput != nil {
+		return nil
 	}
+	if gui.screen == ServerScreenExport {
+		gui.export.setFormat(gui.export.format + 1)
+		return nil
+	}
+	if gui.logFocus && gui.navigating() {
+		gui.scrollLog(g, 1)
+		return nil
+	}
+	gui.setSelection(gui.selection() + 1)
 	return nil
 }
 
 func (gui *ServerGUI) keyUp(g *gocui.Gui, v *gocui.View) error {
-	switch gui.screen {
-	case ServerScreenApps:
-		if gui.selectedApp > 0 {
-			gui.selectedApp--
-		}
-	case ServerScreenAppMenu, ServerScreenActionsMenu, ServerScreenProxyMenu:
-		if gui.selectedItem > 0 {
-			gui.selectedItem--
-		}
-	case ServerScreenContainerSelect:
-		if gui.selectedContainer > 0 {
-			gui.selectedContainer--
-		}
+	if gui.searchInput != nil {
+		return nil
 	}
+	if gui.screen == ServerScreenExport {
+		gui.export.setFormat(gui.export.format - 1)
+		return nil
+	}
+	if gui.logFocus && gui.navigating() {
+		gui.scrollLog(g, -1)
+		return nil
+	}
+	gui.setSelection(gui.selection() - 1)
 	return nil
 }
 
 func (gui *ServerGUI) keyEnter(g *gocui.Gui, v *gocui.View) error {
+	if gui.searchInput != nil {
+		return nil
+	}
 	switch gui.screen {
 	case ServerScreenApps:
 		if len(gui.apps) > 0 {
@@ -981,6 +1247,14 @@ func (gui *ServerGUI) keyEnter(g *gocui.Gui, v *gocui.View) error {
 		if gui.selectedContainer < len(gui.allContainers) {
 			gui.viewContainerLogs(gui.allContainers[gui.selectedContainer])
 		}
+	case ServerScreenSaveLogs:
+		gui.saveLogs()
+	case ServerScreenExport:
+		gui.exportInventory(exportFormats[gui.export.format], gui.export.path)
+		gui.screen = ServerScreenApps
+		gui.export = nil
+	case ServerScreenPortForward:
+		gui.forwardPort()
 	case ServerScreenHelp:
 		gui.screen = ServerScreenApps
 		g.DeleteView(viewHelp)
@@ -989,10 +1263,17 @@ func (gui *ServerGUI) keyEnter(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *ServerGUI) keyBack(g *gocui.Gui, v *gocui.View) error {
+	if gui.searchInput != nil {
+		return nil
+	}
 	if gui.screen == ServerScreenConfirm {
 		gui.closeConfirm()
 		return nil
 	}
+	if gui.screen == ServerScreenPager {
+		gui.closePager()
+		return nil
+	}
 
 	// Stop log streaming if active
 	gui.streamMu.Lock()
@@ -1004,6 +1285,17 @@ func (gui *ServerGUI) keyBack(g *gocui.Gui, v *gocui.View) error {
 	}
 
 	switch gui.screen {
+	case ServerScreenExport:
+		gui.screen = ServerScreenApps
+		gui.export = nil
+	case ServerScreenSaveLogs:
+		gui.screen = ServerScreenContainerSelect
+		gui.selectedItem = 0
+		gui.saveLogsLines = ""
+	case ServerScreenPortForward:
+		gui.screen = ServerScreenContainerSelect
+		gui.selectedItem = 0
+		gui.forward = nil
 	case ServerScreenContainerSelect:
 		gui.screen = ServerScreenAppMenu
 		gui.selectedContainer = 0
@@ -1031,6 +1323,15 @@ func (gui *ServerGUI) keyRefresh(g *gocui.Gui, v *gocui.View) error {
 		return nil
 	}
 
+	// Before the apps are listed, r retries a failed or cancelled connect
+	switch phase, _, _ := gui.startup.state(); phase {
+	case phaseFailed:
+		gui.connect()
+		return nil
+	case phaseConnecting, phaseDiscovering:
+		return nil
+	}
+
 	// Otherwise, refresh apps
 	gui.logInfo("Refreshing apps...")
 	go func() {
@@ -1041,30 +1342,21 @@ func (gui *ServerGUI) keyRefresh(g *gocui.Gui, v *gocui.View) error {
 		}
 		gui.apps = apps
 		gui.logSuccess(fmt.Sprintf("Found %d app(s)", len(apps)))
+		gui.loadRunningImages(apps)
 	}()
 	return nil
 }
 
 func (gui *ServerGUI) restartContainer(ci ContainerInfo) {
+	app, _ := gui.currentApp()
 	gui.logInfo(fmt.Sprintf("Restarting %s...", ci.Container.Name))
-	gui.cmdMu.Lock()
-	gui.running = true
-	gui.runningCmd = "Restart"
-	gui.cmdStartTime = time.Now()
-	gui.cmdMu.Unlock()
-
-	go func() {
-		defer func() {
-			gui.cmdMu.Lock()
-			gui.running = false
-			gui.cmdMu.Unlock()
-		}()
+	gui.runMutation("Restart", app, func() (bool, func()) {
 		if err := docker.RestartContainer(gui.client, ci.Container.ID); err != nil {
 			gui.logError(fmt.Sprintf("Failed to restart %s: %s", ci.Container.Name, err.Error()))
-		} else {
-			gui.logSuccess(fmt.Sprintf("Restarted %s", ci.Container.Name))
+			return false, nil
 		}
-	}()
+		return true, func() { gui.logSuccess(fmt.Sprintf("Restarted %s", ci.Container.Name)) }
+	})
 }
 
 func (gui *ServerGUI) keyHelp(g *gocui.Gui, v *gocui.View) error {
@@ -1078,19 +1370,43 @@ func (gui *ServerGUI) keyHelp(g *gocui.Gui, v *gocui.View) error {
 }
 
 func (gui *ServerGUI) keyClearLog(g *gocui.Gui, v *gocui.View) error {
-	gui.logMu.Lock()
-	gui.logLines = make([]string, 0, 1000)
-	gui.logMu.Unlock()
-	gui.logScroll = 0
+	gui.clearLog()
 	return nil
 }
 
+// clearLog empties the log. While a stream is active a marker line is kept
+// and the view stays in follow mode so the refill doesn't jump around.
+func (gui *ServerGUI) clearLog() {
+	gui.streamMu.Lock()
+	isStreaming := gui.streamingLogs
+	gui.streamMu.Unlock()
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	gui.logDropped += len(gui.logLines)
+	gui.logLines = make([]logEntry, 0, 1000)
+	gui.long.reset()
+	gui.search = logSearch{}
+	if isStreaming {
+		gui.logLines = append(gui.logLines, logEntry{at: time.Now(), cleared: true})
+	}
+	// Follow mode: appendLog also keeps the offset at the end, render clamps it.
+	gui.logScroll = len(gui.logLines)
+}
+
 func (gui *ServerGUI) keyScrollDown(g *gocui.Gui, v *gocui.View) error {
+	gui.logMu.Lock()
 	gui.logScroll += 5
+	gui.logMu.Unlock()
 	return nil
 }
 
 func (gui *ServerGUI) keyScrollUp(g *gocui.Gui, v *gocui.View) error {
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	// The stored offset may be past the end (follow mode); clamp before moving.
+	if maxScroll := len(gui.logLines) - 1; gui.logScroll > maxScroll {
+		gui.logScroll = maxScroll
+	}
 	if gui.logScroll > 0 {
 		gui.logScroll -= 5
 		if gui.logScroll < 0 {
@@ -1138,7 +1454,7 @@ func (gui *ServerGUI) executeActionsMenuAction() {
 	}
 	app := gui.apps[gui.selectedApp]
 
-	// Actions menu: 0: Boot, 1: Start, 2: Stop, 3: Restart, 4: Remove, 5: Images, 6: Version, 7: Health, 8: Back
+	// Actions menu: 0: Boot, 1: Start, 2: Stop, 3: Restart, 4: Remove, 5: Images, 6: Version, 7: Health, 8: Verify, 9: Back
 	switch gui.selectedItem {
 	case 0: // Boot / Reboot
 		gui.rebootApp(app)
@@ -1156,7 +1472,9 @@ func (gui *ServerGUI) executeActionsMenuAction() {
 		gui.showAppVersion(app)
 	case 7: // Health
 		gui.showAppHealth(app)
-	case 8: // Back
+	case 8: // Verify digest
+		gui.verifyDigests(app)
+	case 9: // Back
 		gui.screen = ServerScreenAppMenu
 		gui.selectedItem = 0
 	}
@@ -1164,7 +1482,7 @@ func (gui *ServerGUI) executeActionsMenuAction() {
 
 // executeProxyMenuAction handles proxy submenu selections
 func (gui *ServerGUI) executeProxyMenuAction() {
-	// Proxy menu: 0: Logs, 1: Details, 2: Restart, 3: Reboot, 4: Stop, 5: Start, 6: Back
+	// Proxy menu: 0: Logs, 1: Details, 2: Restart, 3: Reboot, 4: Stop, 5: Start, 6: Upgrade, 7: Back
 	switch gui.selectedItem {
 	case 0: // Logs (live)
 		gui.viewProxyLogs()
@@ -1178,7 +1496,9 @@ func (gui *ServerGUI) executeProxyMenuAction() {
 		gui.proxyStop()
 	case 5: // Start
 		gui.proxyStart()
-	case 6: // Back
+	case 6: // Upgrade
+		gui.proxyUpgrade()
+	case 7: // Back
 		gui.screen = ServerScreenAppMenu
 		gui.selectedItem = 0
 	}
@@ -1207,8 +1527,7 @@ func (gui *ServerGUI) viewAppLogs(app docker.App) {
 			}
 
 			gui.appendLog([]string{fmt.Sprintf("─── %s ───", container.Name)})
-			lines := splitLines(output)
-			gui.appendLog(lines)
+			gui.appendLog(cleanOutputLines(splitLines(output), gui.ansi))
 		}
 		gui.logSuccess("Fetched logs from all containers")
 	}()
@@ -1225,13 +1544,19 @@ func (gui *ServerGUI) viewContainerLogs(ci ContainerInfo) {
 	gui.streamingContainer = ci.Container.Name
 	gui.liveLogsStop = make(chan struct{})
 	stopCh := gui.liveLogsStop
+	stats := newStreamStats(ci.Container.Name, time.Now())
+	gui.stream = stats
 	gui.streamMu.Unlock()
 
 	go func() {
 		lastUpdate := time.Now()
 		throttle := 80 * time.Millisecond
 		err := docker.StreamContainerLogs(gui.client, ci.Container.ID, func(line string) {
-			gui.appendLog([]string{line})
+			stats.add(line)
+			line = annotateSkew(cleanOutput(line, gui.ansi), gui.skew.current(gui.host))
+			if !gui.logPause.Offer(line) {
+				gui.appendLog([]string{line})
+			}
 			if time.Since(lastUpdate) < throttle {
 				return
 			}
@@ -1242,10 +1567,12 @@ func (gui *ServerGUI) viewContainerLogs(ci ContainerInfo) {
 		gui.streamMu.Lock()
 		gui.streamingLogs = false
 		gui.streamMu.Unlock()
+		gui.logPause.Reset()
+		totals := stats.totals(time.Now())
 		if err != nil {
-			gui.logError("Log stream ended: " + err.Error())
+			gui.logError("Log stream ended after " + totals + ": " + err.Error())
 		} else {
-			gui.logInfo("Log stream stopped")
+			gui.logInfo("Log stream stopped: " + totals)
 		}
 	}()
 }
@@ -1257,53 +1584,67 @@ func (gui *ServerGUI) stopLogStream() {
 		close(gui.liveLogsStop)
 		gui.liveLogsStop = nil
 		gui.streamingLogs = false
+		gui.logPause.Reset()
 	}
 }
 
+// togglePauseLogs freezes or unfreezes the log view while a container log
+// stream runs. Buffered lines can be appended or skipped on resume.
+func (gui *ServerGUI) togglePauseLogs() {
+	gui.streamMu.Lock()
+	isStreaming := gui.streamingLogs
+	gui.streamMu.Unlock()
+	if !isStreaming {
+		return
+	}
+	if !gui.logPause.IsPaused() {
+		gui.logPause.Pause()
+		return
+	}
+	held, _ := gui.logPause.Buffered()
+	if held == 0 {
+		gui.logPause.Resume()
+		return
+	}
+	gui.showConfirm("Resume live logs",
+		fmt.Sprintf("Append %s buffered lines? (No: skip to live)", formatCount(held)),
+		func() {
+			lines, dropped := gui.logPause.Resume()
+			if dropped > 0 {
+				gui.logInfo(fmt.Sprintf("%s older buffered lines were dropped", formatCount(dropped)))
+			}
+			gui.appendLog(lines)
+		},
+		func() {
+			lines, dropped := gui.logPause.Resume()
+			gui.logInfo(fmt.Sprintf("Skipped %s buffered lines", formatCount(len(lines)+dropped)))
+		})
+}
+
 func (gui *ServerGUI) stopContainer(ci ContainerInfo) {
+	app, _ := gui.currentApp()
 	gui.showConfirm("Confirm Stop", fmt.Sprintf("Stop container %s?", ci.Container.Name), func() {
 		gui.logInfo(fmt.Sprintf("Stopping %s...", ci.Container.Name))
-		gui.cmdMu.Lock()
-		gui.running = true
-		gui.runningCmd = "Stop"
-		gui.cmdStartTime = time.Now()
-		gui.cmdMu.Unlock()
-
-		go func() {
-			defer func() {
-				gui.cmdMu.Lock()
-				gui.running = false
-				gui.cmdMu.Unlock()
-			}()
+		gui.runMutation("Stop", app, func() (bool, func()) {
 			if err := docker.StopContainer(gui.client, ci.Container.ID); err != nil {
 				gui.logError(fmt.Sprintf("Failed to stop %s: %s", ci.Container.Name, err.Error()))
-			} else {
-				gui.logSuccess(fmt.Sprintf("Stopped %s", ci.Container.Name))
+				return false, nil
 			}
-		}()
+			return true, func() { gui.logSuccess(fmt.Sprintf("Stopped %s", ci.Container.Name)) }
+		})
 	}, nil)
 }
 
 func (gui *ServerGUI) startContainer(ci ContainerInfo) {
+	app, _ := gui.currentApp()
 	gui.logInfo(fmt.Sprintf("Starting %s...", ci.Container.Name))
-	gui.cmdMu.Lock()
-	gui.running = true
-	gui.runningCmd = "Start"
-	gui.cmdStartTime = time.Now()
-	gui.cmdMu.Unlock()
-
-	go func() {
-		defer func() {
-			gui.cmdMu.Lock()
-			gui.running = false
-			gui.cmdMu.Unlock()
-		}()
+	gui.runMutation("Start", app, func() (bool, func()) {
 		if err := docker.StartContainer(gui.client, ci.Container.ID); err != nil {
 			gui.logError(fmt.Sprintf("Failed to start %s: %s", ci.Container.Name, err.Error()))
-		} else {
-			gui.logSuccess(fmt.Sprintf("Started %s", ci.Container.Name))
+			return false, nil
 		}
-	}()
+		return true, func() { gui.logSuccess(fmt.Sprintf("Started %s", ci.Container.Name)) }
+	})
 }
 
 func (gui *ServerGUI) restartApp(app docker.App) {
@@ -1313,30 +1654,18 @@ func (gui *ServerGUI) restartApp(app docker.App) {
 	}
 
 	gui.logInfo(fmt.Sprintf("Restarting %s...", app.Service))
-	gui.cmdMu.Lock()
-	gui.running = true
-	gui.runningCmd = "Restart"
-	gui.cmdStartTime = time.Now()
-	gui.cmdMu.Unlock()
-
-	go func() {
-		defer func() {
-			gui.cmdMu.Lock()
-			gui.running = false
-			gui.cmdMu.Unlock()
-		}()
+	gui.runMutation("Restart", app, func() (bool, func()) {
+		var t opTally
 		for _, c := range app.Containers {
 			if err := docker.RestartContainer(gui.client, c.ID); err != nil {
-				gui.logError(fmt.Sprintf("Failed to restart %s: %s", c.Name, err.Error()))
+				gui.logItemError(&t, fmt.Sprintf("Failed to restart %s: %s", c.Name, err.Error()))
 			} else {
 				gui.logSuccess(fmt.Sprintf("Restarted %s", c.Name))
+				t.ok()
 			}
 		}
-		gui.cmdMu.Lock()
-		start := gui.cmdStartTime
-		gui.cmdMu.Unlock()
-		gui.logSuccess(fmt.Sprintf("Restart completed in %s", formatDuration(time.Since(start))))
-	}()
+		return t.failed < t.total, func() { gui.finishTally("Restart", &t) }
+	})
 }
 
 func (gui *ServerGUI) stopApp(app docker.App) {
@@ -1347,30 +1676,18 @@ func (gui *ServerGUI) stopApp(app docker.App) {
 
 	gui.showConfirm("Confirm Stop", fmt.Sprintf("Stop all containers for %s?", app.Service), func() {
 		gui.logInfo(fmt.Sprintf("Stopping %s...", app.Service))
-		gui.cmdMu.Lock()
-		gui.running = true
-		gui.runningCmd = "Stop"
-		gui.cmdStartTime = time.Now()
-		gui.cmdMu.Unlock()
-
-		go func() {
-			defer func() {
-				gui.cmdMu.Lock()
-				gui.running = false
-				gui.cmdMu.Unlock()
-			}()
+		gui.runMutation("Stop", app, func() (bool, func()) {
+			var t opTally
 			for _, c := range app.Containers {
 				if err := docker.StopContainer(gui.client, c.ID); err != nil {
-					gui.logError(fmt.Sprintf("Failed to stop %s: %s", c.Name, err.Error()))
+					gui.logItemError(&t, fmt.Sprintf("Failed to stop %s: %s", c.Name, err.Error()))
 				} else {
 					gui.logSuccess(fmt.Sprintf("Stopped %s", c.Name))
+					t.ok()
 				}
 			}
-			gui.cmdMu.Lock()
-			start := gui.cmdStartTime
-			gui.cmdMu.Unlock()
-			gui.logSuccess(fmt.Sprintf("Stop completed in %s", formatDuration(time.Since(start))))
-		}()
+			return t.failed < t.total, func() { gui.finishTally("Stop", &t) }
+		})
 	}, nil)
 }
 
@@ -1381,30 +1698,18 @@ func (gui *ServerGUI) startApp(app docker.App) {
 	}
 
 	gui.logInfo(fmt.Sprintf("Starting %s...", app.Service))
-	gui.cmdMu.Lock()
-	gui.running = true
-	gui.runningCmd = "Start"
-	gui.cmdStartTime = time.Now()
-	gui.cmdMu.Unlock()
-
-	go func() {
-		defer func() {
-			gui.cmdMu.Lock()
-			gui.running = false
-			gui.cmdMu.Unlock()
-		}()
+	gui.runMutation("Start", app, func() (bool, func()) {
+		var t opTally
 		for _, c := range app.Containers {
 			if err := docker.StartContainer(gui.client, c.ID); err != nil {
-				gui.logError(fmt.Sprintf("Failed to start %s: %s", c.Name, err.Error()))
+				gui.logItemError(&t, fmt.Sprintf("Failed to start %s: %s", c.Name, err.Error()))
 			} else {
 				gui.logSuccess(fmt.Sprintf("Started %s", c.Name))
+				t.ok()
 			}
 		}
-		gui.cmdMu.Lock()
-		start := gui.cmdStartTime
-		gui.cmdMu.Unlock()
-		gui.logSuccess(fmt.Sprintf("Start completed in %s", formatDuration(time.Since(start))))
-	}()
+		return t.failed < t.total, func() { gui.finishTally("Start", &t) }
+	})
 }
 
 func (gui *ServerGUI) showAppDetails(app docker.App) {
@@ -1416,16 +1721,21 @@ func (gui *ServerGUI) showAppDetails(app docker.App) {
 		for _, acc := range app.Accessories {
 			allContainers = append(allContainers, acc.Containers...)
 		}
+		gui.loadRunningImages([]docker.App{app})
 
 		for _, c := range allContainers {
 			// Get container inspect details
-			cmd := fmt.Sprintf("docker inspect --format '{{.State.Status}} | Started: {{.State.StartedAt}} | Image: {{.Config.Image}}' %s", c.ID)
+			cmd := docker.Bin(gui.client) + " inspect --format '{{.State.Status}} | Started: {{.State.StartedAt}} | Image: {{.Config.Image}}' " + ssh.Quote(c.ID)
 			output, err := gui.client.Run(cmd)
 			if err != nil {
 				gui.appendLog([]string{fmt.Sprintf("  %s: error - %s", c.Name, err.Error())})
 				continue
 			}
-			gui.appendLog([]string{fmt.Sprintf("  %s: %s", c.Name, strings.TrimSpace(output))})
+			line := fmt.Sprintf("  %s: %s", c.Name, strings.TrimSpace(output))
+			if d := gui.runningDigest(c.ID); d != "" {
+				line += " " + dim("@"+d)
+			}
+			gui.appendLog([]string{line})
 		}
 		gui.logSuccess("Details fetched")
 	}()
@@ -1433,18 +1743,7 @@ func (gui *ServerGUI) showAppDetails(app docker.App) {
 
 func (gui *ServerGUI) rebootApp(app docker.App) {
 	gui.logInfo(fmt.Sprintf("Rebooting %s (stop + start)...", app.Service))
-	gui.cmdMu.Lock()
-	gui.running = true
-	gui.runningCmd = "Reboot"
-	gui.cmdStartTime = time.Now()
-	gui.cmdMu.Unlock()
-
-	go func() {
-		defer func() {
-			gui.cmdMu.Lock()
-			gui.running = false
-			gui.cmdMu.Unlock()
-		}()
+	gui.runMutation("Reboot", app, func() (bool, func()) {
 		// Stop all containers
 		for _, c := range app.Containers {
 			if err := docker.StopContainer(gui.client, c.ID); err != nil {
@@ -1460,10 +1759,11 @@ func (gui *ServerGUI) rebootApp(app docker.App) {
 			}
 		}
 		gui.cmdMu.Lock()
-		start := gui.cmdStartTime
+		took := time.Since(gui.cmdStartTime)
 		gui.cmdMu.Unlock()
-		gui.logSuccess(fmt.Sprintf("Reboot completed in %s", formatDuration(time.Since(start))))
-	}()
+		// Even partial failures leave states changed, so always refresh.
+		return true, func() { gui.logSuccess(fmt.Sprintf("Reboot completed in %s", formatDuration(took))) }
+	})
 }
 
 func (gui *ServerGUI) execShell(app docker.App) {
@@ -1474,17 +1774,26 @@ func (gui *ServerGUI) execShell(app docker.App) {
 
 	container := app.Containers[0]
 	gui.logInfo(fmt.Sprintf("Opening shell in %s...", container.Name))
-	gui.logInfo("Running: docker exec -it ... /bin/sh")
 
 	go func() {
 		// Try common shells
 		shells := []string{"/bin/bash", "/bin/sh"}
 		for _, shell := range shells {
-			cmd := fmt.Sprintf("docker exec %s which %s 2>/dev/null", container.ID, shell)
+			cmd := fmt.Sprintf("%s exec %s which %s 2>/dev/null", docker.Bin(gui.client), ssh.Quote(container.ID), ssh.Quote(shell))
 			if output, err := gui.client.Run(cmd); err == nil && strings.TrimSpace(output) != "" {
-				gui.logInfo(fmt.Sprintf("Shell available: %s", shell))
-				gui.logInfo("To connect manually run:")
-				gui.logInfo(fmt.Sprintf("  ssh %s docker exec -it %s %s", gui.client.HostDisplay(), container.Name, shell))
+				remote := fmt.Sprintf("%s exec -it %s %s", docker.Bin(gui.client), ssh.Quote(container.Name), shell)
+				if _, err := exec.LookPath("ssh"); err != nil {
+					gui.logInfo("To connect manually run:")
+					gui.logInfo(fmt.Sprintf("  ssh -t %s %s", gui.client.HostDisplay(), remote))
+					return
+				}
+				gui.handoff.request(gui.g, gui.client.InteractiveCommand(remote), func(err error) {
+					if err != nil {
+						gui.logError(fmt.Sprintf("Shell in %s ended: %s", container.Name, err.Error()))
+						return
+					}
+					gui.logInfo(fmt.Sprintf("Shell in %s closed", container.Name))
+				})
 				return
 			}
 		}
@@ -1501,11 +1810,13 @@ func (gui *ServerGUI) viewProxyLogs() {
 	gui.streamingContainer = "kamal-proxy"
 	gui.liveLogsStop = make(chan struct{})
 	stopCh := gui.liveLogsStop
+	stats := newStreamStats("kamal-proxy", time.Now())
+	gui.stream = stats
 	gui.streamMu.Unlock()
 
 	go func() {
 		// Find kamal-proxy container
-		cmd := `docker ps --filter "name=kamal-proxy" --format "{{.ID}}" | head -1`
+		cmd := docker.Bin(gui.client) + ` ps --filter "name=kamal-proxy" --format "{{.ID}}" | head -1`
 		proxyID, err := gui.client.Run(cmd)
 		if err != nil || strings.TrimSpace(proxyID) == "" {
 			gui.logError("kamal-proxy container not found")
@@ -1519,7 +1830,11 @@ func (gui *ServerGUI) viewProxyLogs() {
 		lastUpdate := time.Now()
 		throttle := 80 * time.Millisecond
 		err = docker.StreamContainerLogs(gui.client, proxyID, func(line string) {
-			gui.appendLog([]string{line})
+			stats.add(line)
+			line = annotateSkew(line, gui.skew.current(gui.host))
+			if !gui.logPause.Offer(line) {
+				gui.appendLog([]string{line})
+			}
 			if time.Since(lastUpdate) < throttle {
 				return
 			}
@@ -1530,10 +1845,12 @@ func (gui *ServerGUI) viewProxyLogs() {
 		gui.streamMu.Lock()
 		gui.streamingLogs = false
 		gui.streamMu.Unlock()
+		gui.logPause.Reset()
+		totals := stats.totals(time.Now())
 		if err != nil {
-			gui.logError("Proxy log stream ended: " + err.Error())
+			gui.logError("Proxy log stream ended after " + totals + ": " + err.Error())
 		} else {
-			gui.logInfo("Proxy log stream stopped")
+			gui.logInfo("Proxy log stream stopped: " + totals)
 		}
 	}()
 }
@@ -1542,7 +1859,7 @@ func (gui *ServerGUI) showProxyDetails() {
 	gui.logInfo("=== kamal-proxy Details ===")
 
 	go func() {
-		cmd := `docker ps --filter "name=kamal-proxy" --format "Name: {{.Names}}\nImage: {{.Image}}\nStatus: {{.Status}}\nPorts: {{.Ports}}"`
+		cmd := docker.Bin(gui.client) + ` ps --filter "name=kamal-proxy" --format "Name: {{.Names}}\nImage: {{.Image}}\nStatus: {{.Status}}\nPorts: {{.Ports}}"`
 		output, err := gui.client.Run(cmd)
 		if err != nil {
 			gui.logError("Failed to get proxy details: " + err.Error())
@@ -1582,7 +1899,7 @@ func (gui *ServerGUI) showAppImages(app docker.App) {
 
 		for image := range images {
 			// Get image details
-			cmd := fmt.Sprintf("docker images --format 'ID: {{.ID}} | Size: {{.Size}} | Created: {{.CreatedSince}}' %s", image)
+			cmd := docker.Bin(gui.client) + " images --format 'ID: {{.ID}} | Size: {{.Size}} | Created: {{.CreatedSince}}' " + ssh.Quote(image)
 			output, err := gui.client.Run(cmd)
 			if err != nil {
 				gui.appendLog([]string{fmt.Sprintf("  %s: error - %s", image, err.Error())})
@@ -1602,6 +1919,11 @@ func (gui *ServerGUI) showAppVersion(app docker.App) {
 	gui.appendLog([]string{fmt.Sprintf("  Service: %s", app.Service)})
 	gui.appendLog([]string{fmt.Sprintf("  Destination: %s", app.Destination)})
 	gui.appendLog([]string{fmt.Sprintf("  Version: %s", version)})
+	if len(app.Containers) > 0 {
+		if d := gui.runningDigest(app.Containers[0].ID); d != "" {
+			gui.appendLog([]string{fmt.Sprintf("  Digest: %s", d)})
+		}
+	}
 
 	// Show version from labels if available
 	if len(app.Containers) > 0 {
@@ -1624,16 +1946,16 @@ func (gui *ServerGUI) showAppHealth(app docker.App) {
 
 		for _, c := range allContainers {
 			// Check container health status
-			cmd := fmt.Sprintf("docker inspect --format '{{.State.Status}} | Health: {{if .State.Health}}{{.State.Health.Status}}{{else}}no healthcheck{{end}} | Restarts: {{.RestartCount}}' %s", c.ID)
+			cmd := docker.Bin(gui.client) + " inspect --format '{{.State.Status}} | Health: {{if .State.Health}}{{.State.Health.Status}}{{else}}no healthcheck{{end}} | Restarts: {{.RestartCount}}' " + ssh.Quote(c.ID)
 			output, err := gui.client.Run(cmd)
 			if err != nil {
 				gui.appendLog([]string{fmt.Sprintf("  %s: error - %s", c.Name, err.Error())})
 				continue
 			}
 
-			status := red("●")
+			status := red(iconRunning)
 			if c.State == "running" {
-				status = green("●")
+				status = green(iconRunning)
 			}
 			gui.appendLog([]string{fmt.Sprintf("  %s %s: %s", status, c.Name, strings.TrimSpace(output))})
 		}
@@ -1643,19 +1965,8 @@ func (gui *ServerGUI) showAppHealth(app docker.App) {
 
 func (gui *ServerGUI) removeStoppedContainers(app docker.App) {
 	gui.logInfo(fmt.Sprintf("Removing stopped containers for %s...", app.Service))
-	gui.cmdMu.Lock()
-	gui.running = true
-	gui.runningCmd = "Remove"
-	gui.cmdStartTime = time.Now()
-	gui.cmdMu.Unlock()
-
-	go func() {
-		defer func() {
-			gui.cmdMu.Lock()
-			gui.running = false
-			gui.cmdMu.Unlock()
-		}()
-		removed := 0
+	gui.runMutation("Remove", app, func() (bool, func()) {
+		var t opTally
 		allContainers := app.Containers
 		for _, acc := range app.Accessories {
 			allContainers = append(allContainers, acc.Containers...)
@@ -1663,32 +1974,27 @@ func (gui *ServerGUI) removeStoppedContainers(app docker.App) {
 
 		for _, c := range allContainers {
 			if c.State != "running" {
-				cmd := fmt.Sprintf("docker rm %s", c.ID)
-				if _, err := gui.client.Run(cmd); err != nil {
-					gui.logError(fmt.Sprintf("Failed to remove %s: %s", c.Name, err.Error()))
+				if err := docker.RemoveContainer(gui.client, c.ID); err != nil {
+					gui.logItemError(&t, fmt.Sprintf("Failed to remove %s: %s", c.Name, err.Error()))
 				} else {
 					gui.logSuccess(fmt.Sprintf("Removed %s", c.Name))
-					removed++
+					t.ok()
 				}
 			}
 		}
 
-		if removed == 0 {
+		if t.total == 0 {
 			gui.logInfo("No stopped containers to remove")
-		} else {
-			gui.cmdMu.Lock()
-			start := gui.cmdStartTime
-			gui.cmdMu.Unlock()
-			gui.logSuccess(fmt.Sprintf("Removed %d container(s) in %s", removed, formatDuration(time.Since(start))))
-			gui.refreshAppsAndContainers()
+			return false, nil
 		}
-	}()
+		return t.failed < t.total, func() { gui.finishTally(fmt.Sprintf("Remove of %d stopped container(s)", t.total), &t) }
+	})
 }
 
 // --- Proxy Management ---
 
 func (gui *ServerGUI) getProxyContainerID() (string, error) {
-	cmd := `docker ps -a --filter "name=kamal-proxy" --format "{{.ID}}" | head -1`
+	cmd := docker.Bin(gui.client) + ` ps -a --filter "name=kamal-proxy" --format "{{.ID}}" | head -1`
 	output, err := gui.client.Run(cmd)
 	if err != nil {
 		return "", err
@@ -1830,6 +2136,80 @@ func (gui *ServerGUI) proxyStart() {
 	}()
 }
 
+// proxyUpgrade recreates kamal-proxy from the newest published image, keeping
+// the existing container's ports, env, volumes and network. The exact recreate
+// command is shown in the confirm dialog before anything is changed.
+func (gui *ServerGUI) proxyUpgrade() {
+	gui.logInfo("Checking kamal-proxy versions...")
+	go func() {
+		pc, err := docker.InspectProxy(gui.client)
+		if err != nil {
+			gui.logError(err.Error())
+			return
+		}
+		current := docker.ImageTag(pc.Image)
+		available, err := docker.LatestProxyVersion()
+		if err != nil {
+			gui.logError("Registry check failed: " + err.Error())
+			return
+		}
+		gui.logInfo(fmt.Sprintf("kamal-proxy running: %s, available: %s", current, available))
+		if c, ok := docker.CompareVersions(current, available); ok && c >= 0 {
+			gui.logSuccess("kamal-proxy is already up to date")
+			return
+		}
+
+		image := docker.ProxyImageRepo + ":" + available
+		recreate := docker.ProxyRecreateCommand(gui.client, *pc, image)
+		gui.logInfo("Recreate command: " + recreate)
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.showConfirm("Confirm Proxy Upgrade", fmt.Sprintf("Upgrade kamal-proxy %s -> %s? Traffic is interrupted while it restarts. Runs: %s", current, available, recreate), func() {
+				gui.runProxyRecreate(pc, image, recreate)
+			}, nil)
+			return nil
+		})
+	}()
+}
+
+func (gui *ServerGUI) runProxyRecreate(pc *docker.ProxyContainer, image, recreate string) {
+	gui.cmdMu.Lock()
+	gui.running = true
+	gui.runningCmd = "Proxy Upgrade"
+	gui.cmdStartTime = time.Now()
+	gui.cmdMu.Unlock()
+
+	go func() {
+		defer func() {
+			gui.cmdMu.Lock()
+			gui.running = false
+			gui.cmdMu.Unlock()
+		}()
+		gui.logInfo("Pulling " + image + "...")
+		if err := docker.PullImage(gui.client, image); err != nil {
+			gui.logError(fmt.Sprintf("Failed to pull %s: %s", image, err.Error()))
+			return
+		}
+		if err := docker.StopContainer(gui.client, pc.ID); err != nil {
+			gui.logError(fmt.Sprintf("Failed to stop proxy: %s", err.Error()))
+			return
+		}
+		if err := docker.RemoveContainer(gui.client, pc.ID); err != nil {
+			gui.logError(fmt.Sprintf("Failed to remove old proxy: %s", err.Error()))
+			return
+		}
+		if _, err := gui.client.Run(recreate); err != nil {
+			gui.logError(fmt.Sprintf("Failed to start new proxy: %s", err.Error()))
+			gui.logError("Proxy is down. Recreate it manually with: " + recreate)
+			return
+		}
+		gui.cmdMu.Lock()
+		start := gui.cmdStartTime
+		gui.cmdMu.Unlock()
+		gui.logSuccess(fmt.Sprintf("Proxy upgraded to %s in %s", docker.ImageTag(image), formatDuration(time.Since(start))))
+		gui.refreshAppsAndContainers()
+	}()
+}
+
 func splitLines(s string) []string {
 	var lines []string
 	for _, line := range strings.Split(s, "\n") {
@@ -1839,3 +2219,31 @@ func splitLines(s string) []string {
 	}
 	return lines
 }
+
+// keyExpandLine opens the pager on the newest truncated Output line, or
+// closes it.
+func (gui *ServerGUI) keyExpandLine(g *gocui.Gui, v *gocui.View) error {
+	switch gui.screen {
+	case ServerScreenPager:
+		gui.closePager()
+		return nil
+	case ServerScreenConfirm, ServerScreenHelp:
+		return nil
+	}
+	gui.logMu.Lock()
+	p := newPager(gui.long.texts)
+	gui.logMu.Unlock()
+	if p == nil {
+		gui.logInfo("No truncated line to expand")
+		return nil
+	}
+	gui.pager, gui.pagerPrev, gui.screen = p, gui.screen, ServerScreenPager
+	return nil
+}
+
+func (gui *ServerGUI) closePager() {
+	gui.pager = nil
+	gui.screen = gui.pagerPrev
+	gui.g.DeleteView(viewPager)
+	gui.g.SetCurrentView(viewMain)
+}
@@ -0,0 +1,151 @@
+package gui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+func TestContainerIndexByID(t *testing.T) {
+	list := []ContainerInfo{
+		{Container: docker.Container{ID: "a"}},
+		{Container: docker.Container{ID: "b"}},
+		{Container: docker.Container{ID: "c"}},
+	}
+	tests := []struct {
+		name     string
+		list     []ContainerInfo
+		id       string
+		fallback int
+		want     int
+	}{
+		{"found moves with container", list, "c", 0, 2},
+		{"gone keeps position", list, "x", 1, 1},
+		{"gone clamps to end", list[:2], "c", 2, 1},
+		{"empty list", nil, "a", 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := containerIndexByID(tt.list, tt.id, tt.fallback); got != tt.want {
+				t.Errorf("containerIndexByID(%q, %d) = %d, want %d", tt.id, tt.fallback, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortContainers(t *testing.T) {
+	list := []ContainerInfo{
+		{Container: docker.Container{Name: "stale", State: "exited", Created: "2024-05-01 10:00:00 +0000 UTC"}},
+		{Container: docker.Container{Name: "old", State: "running", Created: "2024-05-01 10:00:00 +0000 UTC"}},
+		{Container: docker.Container{Name: "unknown", State: "running"}},
+		{Container: docker.Container{Name: "new", State: "running", Created: "2024-05-03 09:00:00 +0000 UTC"}},
+		{Container: docker.Container{Name: "failed", State: "exited", Created: "2024-05-02 10:00:00 +0000 UTC"}},
+	}
+	sortContainers(list)
+	var got []string
+	for _, ci := range list {
+		got = append(got, ci.Container.Name)
+	}
+	if want := "new old unknown failed stale"; strings.Join(got, " ") != want {
+		t.Errorf("sortContainers() = %v, want %s", got, want)
+	}
+}
+
+func TestContainerAge(t *testing.T) {
+	now := time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		c    docker.Container
+		want string
+	}{
+		{docker.Container{State: "running", Status: "Up 3 hours (healthy)"}, "up 3 hours"},
+		{docker.Container{State: "running", Status: "Up About a minute"}, "up About a minute"},
+		{docker.Container{State: "exited", Status: "Exited (0) 3 hours ago", Created: "2024-05-01 10:00:00 +0000 UTC"}, "2d ago"},
+		{docker.Container{State: "created", Created: "2024-05-03 09:48:00 +0000 UTC"}, "12m ago"},
+		{docker.Container{State: "exited"}, "-"},
+	}
+	for _, tt := range tests {
+		if got := containerAge(tt.c, now); got != tt.want {
+			t.Errorf("containerAge(%+v) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestContainerRows(t *testing.T) {
+	plainStyle(t)
+	now := time.Date(2024, 5, 3, 10, 0, 0, 0, time.UTC)
+	list := []ContainerInfo{
+		{Role: "web", Container: docker.Container{Name: "myapp-web-production-4f1c2d9e", Image: "registry.io/myapp:4f1c2d9e", State: "running", Status: "Up 3 hours"}},
+		{Role: "web", Container: docker.Container{Name: "myapp-web-production-88aa77bb", Image: "registry.io/myapp:88aa77bb", State: "exited", Created: "2024-05-01 10:00:00 +0000 UTC"}},
+		{Role: "postgres", Container: docker.Container{Name: "myapp-postgres", Image: "postgres:16", State: "running", Status: "Up 5 days"}},
+	}
+	wide := containerRows(list, 0, 80, now)
+	want := []string{
+		"> * myapp-web-production-4f1c2d9e  web       4f1c2d9e  up 3 hours",
+		"  * myapp-web-production-88aa77bb  web       88aa77bb  2d ago",
+		"  * myapp-postgres                 postgres  16        up 5 days",
+	}
+	if strings.Join(wide, "\n") != strings.Join(want, "\n") {
+		t.Errorf("containerRows(80) =\n%s\nwant\n%s", strings.Join(wide, "\n"), strings.Join(want, "\n"))
+	}
+	narrow := containerRows(list, 1, 56, now)
+	for _, row := range narrow {
+		if w := visibleWidth(row); w > 56 {
+			t.Errorf("row %q is %d cells wide, panel is 56", row, w)
+		}
+	}
+	if !strings.Contains(narrow[0], "...-4f1c2d9e") || !strings.Contains(narrow[1], "...-88aa77bb") {
+		t.Errorf("narrow rows lost the version suffix:\n%s", strings.Join(narrow, "\n"))
+	}
+}
+
+func TestVerifyLine(t *testing.T) {
+	c := docker.Container{Name: "app-web-v1", Image: "registry.io/app:v1"}
+	running := docker.RunningImage{ID: "sha256:cfg", RepoDigest: "sha256:aaaaaaaaaaaa1111"}
+	m := docker.RegistryManifest{Digests: []string{"sha256:bbbbbbbbbbbb2222"}}
+	line := stripANSI(verifyLine(c, running, m, docker.DigestMismatch, nil))
+	if !strings.Contains(line, "@aaaaaaaaaaaa") || !strings.Contains(line, "@bbbbbbbbbbbb") {
+		t.Errorf("mismatch line %q lacks both digests", line)
+	}
+	line = stripANSI(verifyLine(c, running, docker.RegistryManifest{}, docker.DigestUnknown, errors.New("unauthorized: authentication required\nmore")))
+	if !strings.Contains(line, "registry lookup failed: unauthorized") || strings.Contains(line, "more") {
+		t.Errorf("unknown line = %q", line)
+	}
+}
+
+func TestContainerLabelLines(t *testing.T) {
+	defer func(c bool) { colorOutput = c }(colorOutput)
+	colorOutput = false
+	ci := ContainerInfo{Container: docker.Container{
+		Name:     "myapp-postgres",
+		Labels:   map[string]string{"service": "myapp-postgres", "zeta": "1", "alpha": "2"},
+		Grouping: `accessory "postgres" of "myapp": no role label; service "myapp-postgres" extends deployed service "myapp"`,
+	}}
+	got := strings.Join(containerLabelLines(ci), "\n")
+	want := strings.Join([]string{
+		"── Labels: myapp-postgres ──",
+		"    alpha=2",
+		"  * service=myapp-postgres",
+		"    zeta=1",
+		"    no destination label, grouped as production",
+		`  Grouped as accessory "postgres" of "myapp": no role label; service "myapp-postgres" extends deployed service "myapp"`,
+	}, "\n")
+	if got != want {
+		t.Errorf("containerLabelLines() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEchoCommand(t *testing.T) {
+	gui := &ServerGUI{}
+	gui.echoCommand("docker ps")
+	if len(gui.logLines) != 0 {
+		t.Errorf("read echoed while idle: %v", gui.logLines)
+	}
+	gui.running = true
+	gui.echoCommand("docker stop abc123")
+	if len(gui.logLines) != 1 || !strings.Contains(stripANSI(gui.logLines[0].text), "$ docker stop abc123") {
+		t.Errorf("action not echoed: %v", gui.logLines)
+	}
+}
@@ -0,0 +1,164 @@
+package gui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+// saveLogsOptions are the presets on the "Save logs…" screen; lines <= 0
+// saves the full log. A typed line count takes their place.
+var saveLogsOptions = []struct {
+	label string
+	lines int
+}{
+	{"Last 1,000 lines", 1000},
+	{"Last 10,000 lines", 10000},
+	{"Last 100,000 lines", 100000},
+	{"Full log", 0},
+}
+
+func (gui *ServerGUI) keyContainerSaveLogs(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ServerScreenContainerSelect || gui.selectedContainer >= len(gui.allContainers) {
+		return nil
+	}
+	gui.saveLogsTarget = gui.allContainers[gui.selectedContainer]
+	gui.saveLogsLines = ""
+	gui.screen = ServerScreenSaveLogs
+	gui.selectedItem = 0
+	return nil
+}
+
+// parseSaveLogsLines reads a typed line count for "Save logs…".
+func parseSaveLogsLines(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("line count %q is not a positive number", s)
+	}
+	return n, nil
+}
+
+// bindSaveLogsKeys routes digits and Backspace to the line count while the
+// "Save logs…" screen is open.
+func (gui *ServerGUI) bindSaveLogsKeys(g *gocui.Gui) error {
+	for r := '0'; r <= '9'; r++ {
+		r := r
+		if err := g.SetKeybinding(viewMain, r, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+			if gui.screen == ServerScreenSaveLogs && len(gui.saveLogsLines) < 9 {
+				gui.saveLogsLines += string(r)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	backspace := func(*gocui.Gui, *gocui.View) error {
+		if gui.screen == ServerScreenSaveLogs && gui.saveLogsLines != "" {
+			gui.saveLogsLines = gui.saveLogsLines[:len(gui.saveLogsLines)-1]
+		}
+		return nil
+	}
+	for _, k := range []gocui.Key{gocui.KeyBackspace, gocui.KeyBackspace2} {
+		if err := g.SetKeybinding(viewMain, k, gocui.ModNone, backspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gui *ServerGUI) renderSaveLogs(v *gocui.View) {
+	v.Title = glyphs(" Save logs… ")
+	fmt.Fprintf(v, " %s\n\n", gui.saveLogsTarget.Container.Name)
+	for i, o := range saveLogsOptions {
+		prefix := "  "
+		if i == gui.selectedItem {
+			prefix = cyan(iconArrow) + " "
+		}
+		fmt.Fprintln(v, prefix+o.label)
+	}
+	fmt.Fprintln(v, "")
+	lines := gui.saveLogsLines
+	if lines == "" && gui.selectedItem < len(saveLogsOptions) {
+		lines = "all"
+		if n := saveLogsOptions[gui.selectedItem].lines; n > 0 {
+			lines = strconv.Itoa(n)
+		}
+		lines = dim(lines)
+	}
+	fmt.Fprintln(v, " Lines: "+lines+"_")
+	fmt.Fprintln(v, "")
+	fmt.Fprintln(v, dim(" Saved under ./"+docker.LogSaveDir+"/"))
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓ preset  0-9 line count")))
+	fmt.Fprintln(v, dim(" Enter: save  b/Esc: back"))
+}
+
+// saveLogs saves the typed line count of the chosen container's log, or the
+// selected preset when none is typed. An invalid count keeps the screen open.
+func (gui *ServerGUI) saveLogs() {
+	lines := saveLogsOptions[gui.selectedItem].lines
+	if gui.saveLogsLines != "" {
+		n, err := parseSaveLogsLines(gui.saveLogsLines)
+		if err != nil {
+			gui.logError(err.Error())
+			return
+		}
+		lines = n
+	}
+	gui.saveContainerLogs(gui.saveLogsTarget, lines)
+	gui.screen = ServerScreenContainerSelect
+	gui.selectedItem = 0
+	gui.saveLogsLines = ""
+}
+
+// saveContainerLogs streams the container's logs straight into a local file,
+// showing bytes received in the header. Ctrl+X cancels and keeps what was
+// received so far.
+func (gui *ServerGUI) saveContainerLogs(ci ContainerInfo, lines int) {
+	gui.cmdMu.Lock()
+	if gui.running {
+		gui.cmdMu.Unlock()
+		gui.logError("Another command is running")
+		return
+	}
+	gui.running = true
+	gui.runningCmd = "Saving logs"
+	gui.cmdStartTime = time.Now()
+	gui.cmdStopCh = make(chan struct{})
+	stopCh := gui.cmdStopCh
+	gui.cmdMu.Unlock()
+
+	gui.logInfo(fmt.Sprintf("Saving logs of %s... (Ctrl+X cancel)", ci.Container.Name))
+
+	go func() {
+		defer func() {
+			gui.cmdMu.Lock()
+			gui.running = false
+			gui.runningCmd = ""
+			gui.cmdStopCh = nil
+			gui.cmdMu.Unlock()
+			gui.g.Update(func(*gocui.Gui) error { return nil })
+		}()
+		path, n, err := docker.SaveContainerLogs(gui.client, ci.Container.ID, ci.Container.Name, lines, docker.LogSaveDir, func(total int64) {
+			gui.cmdMu.Lock()
+			gui.runningCmd = "Saving logs " + formatBytes(total)
+			gui.cmdMu.Unlock()
+			gui.g.Update(func(*gocui.Gui) error { return nil })
+		}, stopCh)
+		if abs, absErr := filepath.Abs(path); absErr == nil {
+			path = abs
+		}
+		if err != nil {
+			if n > 0 {
+				gui.logError(fmt.Sprintf("Saving logs stopped after %s (%s): %s", formatBytes(n), path, err.Error()))
+			} else {
+				gui.logError("Failed to save logs: " + err.Error())
+			}
+			return
+		}
+		gui.logSuccess(fmt.Sprintf("Saved %s of logs to %s", formatBytes(n), path))
+	}()
+}
@@ -0,0 +1,14 @@
+package gui
+
+import "testing"
+
+func TestParseSaveLogsLines(t *testing.T) {
+	if n, err := parseSaveLogsLines("2500"); err != nil || n != 2500 {
+		t.Errorf("parseSaveLogsLines(2500) = %d, %v", n, err)
+	}
+	for _, bad := range []string{"", "0", "000"} {
+		if _, err := parseSaveLogsLines(bad); err == nil {
+			t.Errorf("parseSaveLogsLines(%q) succeeded", bad)
+		}
+	}
+}
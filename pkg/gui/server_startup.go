@@ -0,0 +1,227 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// serverPhase is how far server mode got in reaching its host and finding
+// the apps on it. The TUI is up from the start; the Apps panel shows the
+// phase until it is ready.
+type serverPhase int
+
+const (
+	phaseConnecting  serverPhase = iota // testing the SSH connection
+	phaseDiscovering                    // listing Kamal apps over docker
+	phaseReady                          // apps listed
+	phaseFailed                         // gave up or cancelled; r tries again
+)
+
+// errStartupCancelled is the failure of an attempt cancelled with Ctrl+X.
+var errStartupCancelled = errors.New("cancelled")
+
+// serverStartup is the state of the background connect and discovery.
+// Every attempt has a generation, so the late result of one that was
+// cancelled or retried is dropped.
+type serverStartup struct {
+	mu    sync.Mutex
+	phase serverPhase
+	err   error // why it failed, a *StartupError or errStartupCancelled
+	since time.Time
+	gen   int
+}
+
+// begin starts a new attempt in the connecting phase and returns its
+// generation.
+func (s *serverStartup) begin(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gen++
+	s.phase, s.err, s.since = phaseConnecting, nil, now
+	return s.gen
+}
+
+// advance moves attempt gen to phase, with err when it failed. It reports
+// false, changing nothing, when gen is no longer the current attempt or
+// the attempt already ended.
+func (s *serverStartup) advance(gen int, phase serverPhase, err error, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen != s.gen || s.phase == phaseReady || s.phase == phaseFailed {
+		return false
+	}
+	s.phase, s.err, s.since = phase, err, now
+	return true
+}
+
+// cancel fails the current attempt if it is still running. Its ssh command
+// is left to finish or time out on its own; the result is ignored.
+func (s *serverStartup) cancel(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.phase != phaseConnecting && s.phase != phaseDiscovering {
+		return false
+	}
+	s.gen++
+	s.phase, s.err, s.since = phaseFailed, errStartupCancelled, now
+	return true
+}
+
+// state is the phase, why it failed, and when it was entered.
+func (s *serverStartup) state() (serverPhase, error, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.phase, s.err, s.since
+}
+
+// ready reports whether the apps have been listed.
+func (s *serverStartup) ready() bool {
+	phase, _, _ := s.state()
+	return phase == phaseReady
+}
+
+// connect tests the SSH connection and discovers the apps in the
+// background, moving the Apps panel through the phases as it goes.
+func (gui *ServerGUI) connect() {
+	gen := gui.startup.begin(time.Now())
+	go func() {
+		if err := gui.client.TestConnection(); err != nil {
+			gui.failStartup(gen, &StartupError{Stage: StageSSH, SSH: sshTarget(gui.client), Err: err})
+			return
+		}
+		gui.discover(gen)
+	}()
+}
+
+// discover lists the apps for attempt gen, once connected.
+func (gui *ServerGUI) discover(gen int) {
+	if !gui.startup.advance(gen, phaseDiscovering, nil, time.Now()) {
+		return
+	}
+	gui.g.Update(func(*gocui.Gui) error { return nil })
+	apps, err := docker.DiscoverApps(gui.client)
+	if err != nil {
+		gui.offerDockerAccess(gen, err)
+		return
+	}
+	gui.g.Update(func(*gocui.Gui) error {
+		if !gui.startup.advance(gen, phaseReady, nil, time.Now()) {
+			return nil
+		}
+		gui.apps = apps
+		gui.selectedApp = 0
+		gui.logSuccess(fmt.Sprintf("Connected to %s: found %d app(s)", gui.client.HostDisplay(), len(apps)))
+		if gui.client.Sudo {
+			gui.logInfo("Running docker through sudo for this session")
+		}
+		go gui.probeClockSkew()
+		go gui.loadRunningImages(apps)
+		return nil
+	})
+}
+
+// offerDockerAccess handles a discovery failure caused by docker itself:
+// it logs how to fix the access problem and, when the user may use
+// passwordless sudo, offers to retry with `sudo docker` for the session.
+// Other errors fail the attempt as they are.
+func (gui *ServerGUI) offerDockerAccess(gen int, err error) {
+	fail := func() {
+		gui.failStartup(gen, &StartupError{Stage: StageDiscovery, SSH: sshTarget(gui.client), Err: err})
+	}
+	problem := docker.ClassifyAccessError(err)
+	if problem == docker.AccessOK {
+		fail()
+		return
+	}
+	user := gui.client.User
+	if user == "" {
+		user = ssh.DetectUser(gui.client.Host)
+	}
+	gui.appendLog(docker.AccessGuidance(problem, user, gui.client.Host))
+	if problem != docker.AccessDenied || gui.client.Sudo || !docker.CanSudo(gui.client) {
+		fail()
+		return
+	}
+	gui.g.Update(func(*gocui.Gui) error {
+		gui.showConfirm("Use sudo?", "Retry with sudo docker for this session? [y/N]", func() {
+			gui.client.Sudo = true
+			go gui.discover(gen)
+		}, fail)
+		return nil
+	})
+}
+
+// failStartup ends attempt gen with err and logs it.
+func (gui *ServerGUI) failStartup(gen int, err error) {
+	if !gui.startup.advance(gen, phaseFailed, err, time.Now()) {
+		return
+	}
+	gui.appendLog(startupErrorLines(err))
+	gui.g.Update(func(*gocui.Gui) error { return nil })
+}
+
+// cancelStartup is Ctrl+X while connecting or discovering.
+func (gui *ServerGUI) cancelStartup() bool {
+	if !gui.startup.cancel(time.Now()) {
+		return false
+	}
+	gui.logInfo("Cancelled connecting to " + gui.client.HostDisplay() + " (r: try again)")
+	return true
+}
+
+// startupErrorLines are a startup failure as Output lines: the category and
+// message, with the next steps dimmed below.
+func startupErrorLines(err error) []string {
+	r := startupAdvice(err)
+	lines := []string{statusLine("error", r.category+": "+strings.ReplaceAll(r.message, "\n", " "))}
+	for _, step := range r.steps {
+		lines = append(lines, dim("  "+iconArrow+" "+step))
+	}
+	return lines
+}
+
+// writeStartupState is the Apps panel until the apps are listed: what is
+// being done and for how long, or why it failed and what to try next.
+func writeStartupState(w io.Writer, phase serverPhase, err error, host string, elapsed time.Duration, frame string, width int) {
+	switch phase {
+	case phaseConnecting, phaseDiscovering:
+		what := "Connecting to " + host + "…"
+		if phase == phaseDiscovering {
+			what = "Discovering Kamal apps…"
+		}
+		fmt.Fprintln(w, glyphs(fmt.Sprintf(" %s %s %s", yellow(frame), what, dim(formatDuration(elapsed)))))
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, dim(" Ctrl+X: cancel  q: quit"))
+	case phaseFailed:
+		if errors.Is(err, errStartupCancelled) {
+			fmt.Fprintln(w, " Cancelled before the apps were listed.")
+		} else {
+			r := startupAdvice(err)
+			fmt.Fprintln(w, glyphs(red(" "+iconError+" "+r.category)))
+			for _, line := range wrapText(strings.ReplaceAll(r.message, "\n", " "), width-2) {
+				fmt.Fprintln(w, "  "+line)
+			}
+			fmt.Fprintln(w, "")
+			fmt.Fprintln(w, bold(" Next steps:"))
+			for i, step := range r.steps {
+				for j, line := range wrapText(step, width-5) {
+					if j == 0 {
+						fmt.Fprintf(w, "  %d. %s\n", i+1, line)
+					} else {
+						fmt.Fprintln(w, "     "+line)
+					}
+				}
+			}
+		}
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, dim(" r: try again  q: quit"))
+	}
+}
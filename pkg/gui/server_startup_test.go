@@ -0,0 +1,122 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerStartupPhases(t *testing.T) {
+	now := time.Now()
+	var s serverStartup
+	gen := s.begin(now)
+	if phase, err, _ := s.state(); phase != phaseConnecting || err != nil {
+		t.Fatalf("after begin: %v, %v", phase, err)
+	}
+	if !s.advance(gen, phaseDiscovering, nil, now) {
+		t.Fatal("connecting -> discovering refused")
+	}
+	if !s.advance(gen, phaseReady, nil, now) || !s.ready() {
+		t.Fatal("discovering -> ready refused")
+	}
+	if s.advance(gen, phaseFailed, errors.New("late"), now) {
+		t.Error("a ready startup failed afterwards")
+	}
+	if s.cancel(now) {
+		t.Error("a ready startup was cancelled")
+	}
+}
+
+func TestServerStartupFailAndRetry(t *testing.T) {
+	now := time.Now()
+	var s serverStartup
+	gen := s.begin(now)
+	fail := &StartupError{Stage: StageSSH, Err: errors.New("connection refused")}
+	if !s.advance(gen, phaseFailed, fail, now) {
+		t.Fatal("connecting -> failed refused")
+	}
+	if phase, err, _ := s.state(); phase != phaseFailed || err != fail {
+		t.Errorf("after failing: %v, %v", phase, err)
+	}
+	if s.advance(gen, phaseDiscovering, nil, now) {
+		t.Error("a failed attempt moved on")
+	}
+
+	retry := s.begin(now)
+	if retry == gen {
+		t.Fatal("retry reused the generation")
+	}
+	if phase, err, _ := s.state(); phase != phaseConnecting || err != nil {
+		t.Errorf("after retry: %v, %v", phase, err)
+	}
+}
+
+func TestServerStartupCancel(t *testing.T) {
+	now := time.Now()
+	var s serverStartup
+	gen := s.begin(now)
+	if !s.advance(gen, phaseDiscovering, nil, now) {
+		t.Fatal("connecting -> discovering refused")
+	}
+	if !s.cancel(now) {
+		t.Fatal("discovering could not be cancelled")
+	}
+	if phase, err, _ := s.state(); phase != phaseFailed || !errors.Is(err, errStartupCancelled) {
+		t.Errorf("after cancel: %v, %v", phase, err)
+	}
+	// The cancelled attempt finishing later changes nothing, even after a
+	// retry started.
+	retry := s.begin(now)
+	if s.advance(gen, phaseReady, nil, now) {
+		t.Error("the cancelled attempt became ready")
+	}
+	if phase, _, _ := s.state(); phase != phaseConnecting {
+		t.Errorf("retry in %v, want connecting", phase)
+	}
+	if !s.advance(retry, phaseDiscovering, nil, now) {
+		t.Error("the retry could not move on")
+	}
+	if !s.cancel(now) || s.cancel(now) {
+		t.Error("cancel is not once per attempt")
+	}
+}
+
+func TestWriteStartupState(t *testing.T) {
+	var buf bytes.Buffer
+	writeStartupState(&buf, phaseConnecting, nil, "deploy@web1", 3*time.Second, "|", 40)
+	out := stripANSI(buf.String())
+	for _, want := range []string{"Connecting to deploy@web1", "3.0s", "Ctrl+X: cancel"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("connecting panel %q lacks %q", out, want)
+		}
+	}
+
+	buf.Reset()
+	writeStartupState(&buf, phaseDiscovering, nil, "deploy@web1", time.Second, "|", 40)
+	if out := stripANSI(buf.String()); !strings.Contains(out, "Discovering Kamal apps") {
+		t.Errorf("discovering panel: %q", out)
+	}
+
+	buf.Reset()
+	err := &StartupError{Stage: StageSSH, SSH: "deploy@web1", Err: errors.New("Permission denied (publickey).")}
+	writeStartupState(&buf, phaseFailed, err, "deploy@web1", 0, "|", 40)
+	out = stripANSI(buf.String())
+	for _, want := range []string{"SSH authentication failed", "Permission denied", "Next steps:", "  1. ", "r: try again"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("failed panel %q lacks %q", out, want)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if visibleWidth(line) > 40 {
+			t.Errorf("line wider than the panel: %q", line)
+		}
+	}
+
+	buf.Reset()
+	writeStartupState(&buf, phaseFailed, errStartupCancelled, "deploy@web1", 0, "|", 40)
+	if out := stripANSI(buf.String()); !strings.Contains(out, "Cancelled") || strings.Contains(out, "Next steps") {
+		t.Errorf("cancelled panel: %q", out)
+	}
+}
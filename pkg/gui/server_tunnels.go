@@ -0,0 +1,283 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+// forwardPrompt is the state of the "Port forward…" screen: the container,
+// its ports, and the local port being typed ("" takes the suggestion).
+type forwardPrompt struct {
+	target ContainerInfo
+	ports  []docker.ContainerPort
+	local  string
+}
+
+// tunnel is an SSH port forward running in the background until T or quit.
+type tunnel struct {
+	container string
+	local     int
+	target    string
+	cmd       *exec.Cmd
+	stopped   bool // stopped on purpose, so its exit is not an error (guarded by tunnelsMu)
+}
+
+// suggestLocalPort is the local port offered for p: the container's port,
+// moved up by 10000 when binding it would need root.
+func suggestLocalPort(p docker.ContainerPort) int {
+	if p.Port < 1024 {
+		return p.Port + 10000
+	}
+	return p.Port
+}
+
+// parseLocalPort reads a typed local port.
+func parseLocalPort(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > 65535 {
+		return 0, fmt.Errorf("local port %q is not between 1 and 65535", s)
+	}
+	return n, nil
+}
+
+// localPortFree reports whether nothing listens on port on loopback yet.
+func localPortFree(port int) bool {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
+
+// clipboardCommands copy their stdin to the clipboard; the first one on
+// PATH is used.
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+func copyToClipboard(text string) error {
+	for _, c := range clipboardCommands {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return fmt.Errorf("no clipboard tool found (pbcopy, wl-copy, xclip, xsel)")
+}
+
+// keyContainerPortForward reads the selected container's ports and opens
+// the "Port forward…" screen.
+func (gui *ServerGUI) keyContainerPortForward(g *gocui.Gui, v *gocui.View) error {
+	if gui.screen != ServerScreenContainerSelect || gui.selectedContainer >= len(gui.allContainers) {
+		return nil
+	}
+	ci := gui.allContainers[gui.selectedContainer]
+	if ci.Container.State != "running" {
+		gui.logError(ci.Container.Name + " is not running")
+		return nil
+	}
+	gui.logInfo("Reading ports of " + ci.Container.Name + "...")
+	go func() {
+		ports, err := docker.ContainerPorts(gui.client, ci.Container.ID)
+		if err != nil {
+			gui.logError("Failed to read ports: " + strings.SplitN(err.Error(), "\n", 2)[0])
+			return
+		}
+		if len(ports) == 0 {
+			gui.logError(ci.Container.Name + " has no TCP ports to forward")
+			return
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			if gui.screen != ServerScreenContainerSelect {
+				return nil
+			}
+			gui.forward = &forwardPrompt{target: ci, ports: ports}
+			gui.screen = ServerScreenPortForward
+			gui.selectedItem = 0
+			return nil
+		})
+	}()
+	return nil
+}
+
+// bindForwardKeys routes digits and Backspace to the local port while the
+// "Port forward…" screen is open.
+func (gui *ServerGUI) bindForwardKeys(g *gocui.Gui) error {
+	for r := '0'; r <= '9'; r++ {
+		r := r
+		if err := g.SetKeybinding(viewMain, r, gocui.ModNone, func(*gocui.Gui, *gocui.View) error {
+			if gui.screen == ServerScreenPortForward && len(gui.forward.local) < 5 {
+				gui.forward.local += string(r)
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	backspace := func(*gocui.Gui, *gocui.View) error {
+		if gui.screen == ServerScreenPortForward && gui.forward.local != "" {
+			gui.forward.local = gui.forward.local[:len(gui.forward.local)-1]
+		}
+		return nil
+	}
+	for _, k := range []gocui.Key{gocui.KeyBackspace, gocui.KeyBackspace2} {
+		if err := g.SetKeybinding(viewMain, k, gocui.ModNone, backspace); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (gui *ServerGUI) renderPortForward(v *gocui.View) {
+	f := gui.forward
+	v.Title = glyphs(" Port forward… ")
+	fmt.Fprintf(v, " %s on %s\n\n", f.target.Container.Name, gui.client.Host)
+	for i, p := range f.ports {
+		prefix := "  "
+		if i == gui.selectedItem {
+			prefix = cyan(iconArrow) + " "
+		}
+		where := "container " + p.Target()
+		if p.Published() {
+			where = "published " + p.Target()
+		}
+		fmt.Fprintf(v, "%s%d/tcp  %s\n", prefix, p.Port, dim(where))
+	}
+	fmt.Fprintln(v, "")
+	local := f.local
+	if local == "" && gui.selectedItem < len(f.ports) {
+		local = dim(strconv.Itoa(suggestLocalPort(f.ports[gui.selectedItem])))
+	}
+	fmt.Fprintln(v, " Local port: "+local+"_")
+	fmt.Fprintln(v, "")
+	fmt.Fprintln(v, dim(glyphs(" ↑/↓ port  0-9 local port")))
+	fmt.Fprintln(v, dim(" Enter: forward  Esc: back"))
+}
+
+// forwardPort prints and copies the ssh command for the chosen port, then
+// offers to run it as a managed tunnel.
+func (gui *ServerGUI) forwardPort() {
+	f := gui.forward
+	if f == nil || gui.selectedItem >= len(f.ports) {
+		return
+	}
+	p := f.ports[gui.selectedItem]
+	local := suggestLocalPort(p)
+	if f.local != "" {
+		n, err := parseLocalPort(f.local)
+		if err != nil {
+			gui.logError(err.Error())
+			return
+		}
+		local = n
+	}
+	name := f.target.Container.Name
+	command := gui.client.ForwardCommand(local, p.TargetHost, p.TargetPort)
+	lines := []string{cyan("── Port forward: " + name + ":" + strconv.Itoa(p.Port) + " ──"), "  " + command}
+	if err := copyToClipboard(command); err != nil {
+		lines = append(lines, dim("  Not copied: "+err.Error()))
+	} else {
+		lines = append(lines, dim("  Copied to the clipboard"))
+	}
+	gui.appendLog(lines)
+
+	gui.screen = ServerScreenContainerSelect
+	gui.forward = nil
+	gui.selectedItem = 0
+	if !localPortFree(local) {
+		gui.logError(fmt.Sprintf("localhost:%d is already in use; pick another local port", local))
+		return
+	}
+	msg := fmt.Sprintf("Forward localhost:%d to %s port %d in the background, until T or quit? [y/N]", local, name, p.Port)
+	gui.showConfirm("Start tunnel?", msg, func() {
+		gui.startTunnel(name, local, p)
+	}, nil)
+}
+
+// startTunnel runs the forward in the background and logs when it closes
+// by itself, e.g. because the connection dropped.
+func (gui *ServerGUI) startTunnel(name string, local int, p docker.ContainerPort) {
+	cmd := gui.client.ForwardCmd(local, p.TargetHost, p.TargetPort)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		gui.logError("Failed to start tunnel: " + err.Error())
+		return
+	}
+	t := &tunnel{container: name, local: local, target: p.Target(), cmd: cmd}
+	gui.tunnelsMu.Lock()
+	gui.tunnels = append(gui.tunnels, t)
+	gui.tunnelsMu.Unlock()
+	gui.logSuccess(fmt.Sprintf("Tunnel open: localhost:%d %s %s:%d (T stops tunnels)", local, glyphs("→"), name, p.Port))
+
+	go func() {
+		err := cmd.Wait()
+		gui.tunnelsMu.Lock()
+		stopped := t.stopped
+		for i, o := range gui.tunnels {
+			if o == t {
+				gui.tunnels = append(gui.tunnels[:i], gui.tunnels[i+1:]...)
+				break
+			}
+		}
+		gui.tunnelsMu.Unlock()
+		if !stopped {
+			reason := strings.TrimSpace(stderr.String())
+			if reason == "" && err != nil {
+				reason = err.Error()
+			}
+			gui.logError(fmt.Sprintf("Tunnel localhost:%d closed: %s", local, strings.SplitN(reason, "\n", 2)[0]))
+		}
+		gui.g.Update(func(*gocui.Gui) error { return nil })
+	}()
+}
+
+// stopTunnels kills every managed tunnel and returns how many there were.
+func (gui *ServerGUI) stopTunnels() int {
+	gui.tunnelsMu.Lock()
+	defer gui.tunnelsMu.Unlock()
+	for _, t := range gui.tunnels {
+		t.stopped = true
+		_ = t.cmd.Process.Kill()
+	}
+	n := len(gui.tunnels)
+	gui.tunnels = nil
+	return n
+}
+
+func (gui *ServerGUI) keyStopTunnels(g *gocui.Gui, v *gocui.View) error {
+	if n := gui.stopTunnels(); n > 0 {
+		gui.logSuccess(fmt.Sprintf("Stopped %d tunnel(s)", n))
+	} else {
+		gui.logInfo("No tunnels running")
+	}
+	return nil
+}
+
+// writeTunnels lists the running tunnels at the top of the status panel.
+func (gui *ServerGUI) writeTunnels(w io.Writer) {
+	gui.tunnelsMu.Lock()
+	defer gui.tunnelsMu.Unlock()
+	if len(gui.tunnels) == 0 {
+		return
+	}
+	fmt.Fprintln(w, " Tunnels "+dim("(T: stop all)"))
+	for _, t := range gui.tunnels {
+		fmt.Fprintf(w, "   %s localhost:%d %s %s %s\n", green(iconRunning), t.local, glyphs("→"), t.target, dim("("+t.container+")"))
+	}
+	fmt.Fprintln(w, "")
+}
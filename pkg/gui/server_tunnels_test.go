@@ -0,0 +1,82 @@
+package gui
+
+import (
+	"bytes"
+	"net"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/docker"
+)
+
+func TestSuggestLocalPort(t *testing.T) {
+	tests := map[int]int{5432: 5432, 6379: 6379, 80: 10080, 443: 10443, 1024: 1024}
+	for port, want := range tests {
+		if got := suggestLocalPort(docker.ContainerPort{Port: port}); got != want {
+			t.Errorf("suggestLocalPort(%d) = %d, want %d", port, got, want)
+		}
+	}
+}
+
+func TestParseLocalPort(t *testing.T) {
+	if n, err := parseLocalPort("15432"); err != nil || n != 15432 {
+		t.Errorf("parseLocalPort(15432) = %d, %v", n, err)
+	}
+	for _, bad := range []string{"", "0", "65536", "99999"} {
+		if _, err := parseLocalPort(bad); err == nil {
+			t.Errorf("parseLocalPort(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestLocalPortFree(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer ln.Close()
+	if localPortFree(ln.Addr().(*net.TCPAddr).Port) {
+		t.Error("a port with a listener counts as free")
+	}
+}
+
+func TestWriteTunnels(t *testing.T) {
+	gui := &ServerGUI{}
+	var buf bytes.Buffer
+	gui.writeTunnels(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("no tunnels wrote %q", buf.String())
+	}
+
+	gui.tunnels = []*tunnel{{container: "myapp-db", local: 15432, target: "127.0.0.1:5432"}}
+	gui.writeTunnels(&buf)
+	out := stripANSI(buf.String())
+	for _, want := range []string{"Tunnels", "T: stop all", "localhost:15432", "127.0.0.1:5432", "myapp-db"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("tunnels section %q lacks %q", out, want)
+		}
+	}
+}
+
+func TestStopTunnels(t *testing.T) {
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("no sleep binary")
+	}
+	cmd := exec.Command(sleep, "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	tun := &tunnel{local: 15432, cmd: cmd}
+	gui := &ServerGUI{tunnels: []*tunnel{tun}}
+	if n := gui.stopTunnels(); n != 1 {
+		t.Errorf("stopTunnels() = %d, want 1", n)
+	}
+	if err := cmd.Wait(); err == nil {
+		t.Error("tunnel process was not killed")
+	}
+	if !tun.stopped || len(gui.tunnels) != 0 {
+		t.Errorf("stopped = %v, tunnels left = %d", tun.stopped, len(gui.tunnels))
+	}
+}
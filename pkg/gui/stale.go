@@ -0,0 +1,174 @@
+package gui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// staleCheck is the last `kamal app stale_containers` result for a
+// destination, shown in the status panel.
+type staleCheck struct {
+	containers []kamal.StaleContainer
+	checked    time.Time
+}
+
+// detectStale runs `kamal app stale_containers`, records the result for the
+// status panel and logs it. then, if set, runs with the containers found.
+func (gui *GUI) detectStale(then func(dest *kamal.DeployDestination, stale []kamal.StaleContainer)) {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		gui.logError("No app selected")
+		return
+	}
+	key := hostsKey(dest)
+	opts := gui.runOpts()
+	var stale []kamal.StaleContainer
+	gui.runQueryThen("App Stale Containers", func(stopCh <-chan struct{}) (kamal.Result, error) {
+		res, err := kamal.RunKamalWithStop([]string{"app", "stale_containers"}, opts, stopCh)
+		if err == nil && res.ExitCode == 0 {
+			stale = kamal.ParseStaleContainers(res.Stdout)
+		}
+		return res, err
+	}, func(stopCh <-chan struct{}, took time.Duration) {
+		gui.g.Update(func(*gocui.Gui) error {
+			gui.stale[key] = staleCheck{containers: stale, checked: time.Now()}
+			return nil
+		})
+		gui.appendLog(staleLines(dest, stale, then == nil))
+		if then != nil {
+			then(dest, stale)
+		}
+	})
+}
+
+// stopRemoveStale detects the stale containers, confirms with the exact
+// list, then stops them (`stale_containers --stop`) and removes each stale
+// version's stopped containers (`app remove_container`).
+func (gui *GUI) stopRemoveStale() {
+	gui.detectStale(func(dest *kamal.DeployDestination, stale []kamal.StaleContainer) {
+		if len(stale) == 0 {
+			return
+		}
+		opts := gui.runOpts()
+		versions := kamal.StaleVersions(stale)
+		fn := func(stopCh <-chan struct{}) (kamal.Result, error) {
+			res, err := kamal.RunKamalWithStop([]string{"app", "stale_containers", "--stop"}, opts, stopCh)
+			if err != nil || res.ExitCode != 0 {
+				return res, err
+			}
+			for _, v := range versions {
+				r, err := kamal.RunKamalWithStop([]string{"app", "remove_container", v}, opts, stopCh)
+				res.Stdout += r.Stdout
+				res.Stderr += r.Stderr
+				if err != nil || r.ExitCode != 0 {
+					res.ExitCode = r.ExitCode
+					return res, err
+				}
+			}
+			return res, nil
+		}
+		key := hostsKey(dest)
+		onDone := func(stopCh <-chan struct{}, took time.Duration) {
+			gui.g.Update(func(*gocui.Gui) error {
+				gui.stale[key] = staleCheck{checked: time.Now()}
+				return nil
+			})
+		}
+		gui.g.Update(func(*gocui.Gui) error {
+			name := "App Stale Containers (stop)"
+			// The containers found stale are gone after this; . must not
+			// remove them again, nor re-run the command before it.
+			gui.last = nil
+			message := staleConfirmMessage(dest, stale)
+			if flags := targetFlags(gui.selectedRoles(), gui.selectedHosts()); flags != "" {
+				message += "\n(" + flags + ")"
+			}
+			if needsTypedConfirm(dest, false) {
+				gui.confirmProtected(dest, name, message, func() {
+					gui.startCommand(name, false, fn, onDone)
+				})
+				return nil
+			}
+			gui.prevScreen = gui.screen
+			gui.showConfirm("Confirm "+name, message, func() {
+				gui.startCommand(name, false, fn, onDone)
+			}, nil)
+			return nil
+		})
+	})
+}
+
+// staleConfirmMessage lists exactly which containers on which hosts will be
+// stopped and removed.
+func staleConfirmMessage(dest *kamal.DeployDestination, stale []kamal.StaleContainer) string {
+	lines := []string{getDestructiveMessage(ScreenApp, 15)}
+	for _, c := range stale {
+		lines = append(lines, "• "+c.Host+": "+c.ContainerName(dest.Service, dest.Name))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// staleCounts returns "N stale container(s) on host" per host, sorted by
+// host, or one green line when there are none.
+func staleCounts(stale []kamal.StaleContainer) []string {
+	if len(stale) == 0 {
+		return []string{green(iconSuccess + " No stale containers")}
+	}
+	counts := map[string]int{}
+	for _, c := range stale {
+		counts[c.Host]++
+	}
+	hosts := make([]string, 0, len(counts))
+	for h := range counts {
+		hosts = append(hosts, h)
+	}
+	sort.Strings(hosts)
+	lines := make([]string, len(hosts))
+	for i, h := range hosts {
+		noun := "containers"
+		if counts[h] == 1 {
+			noun = "container"
+		}
+		lines[i] = yellow(fmt.Sprintf("%s %d stale %s on %s", iconWarning, counts[h], noun, h))
+	}
+	return lines
+}
+
+// staleLines renders a stale_containers result for the log: counts per host,
+// then each container. hint points at the cleanup action.
+func staleLines(dest *kamal.DeployDestination, stale []kamal.StaleContainer, hint bool) []string {
+	lines := []string{cyan("── Stale containers ──")}
+	for _, l := range staleCounts(stale) {
+		lines = append(lines, "  "+l)
+	}
+	for _, c := range stale {
+		lines = append(lines, dim(fmt.Sprintf("    %s  %s (role %s, version %s)", c.Host, c.ContainerName(dest.Service, dest.Name), c.Role, c.Version)))
+	}
+	if hint && len(stale) > 0 {
+		lines = append(lines, dim("  App → Stop & remove stale cleans them up."))
+	}
+	return lines
+}
+
+// staleStatusLines is the status panel summary of the last stale check for
+// the selected destination.
+func (gui *GUI) staleStatusLines() []string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return nil
+	}
+	check, ok := gui.stale[hostsKey(dest)]
+	if !ok {
+		return nil
+	}
+	lines := []string{" Stale containers (" + formatDuration(time.Since(check.checked)) + " ago):"}
+	for _, l := range staleCounts(check.containers) {
+		lines = append(lines, "  "+l)
+	}
+	return lines
+}
@@ -0,0 +1,43 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestStaleCounts(t *testing.T) {
+	if got := staleCounts(nil); len(got) != 1 || got[0] != green(iconSuccess+" No stale containers") {
+		t.Errorf("staleCounts(nil) = %q, want one green line", got)
+	}
+	stale := []kamal.StaleContainer{
+		{Host: "10.0.1.6", Role: "web", Version: "aaa"},
+		{Host: "10.0.1.5", Role: "web", Version: "aaa"},
+		{Host: "10.0.1.5", Role: "web", Version: "bbb"},
+		{Host: "10.0.1.5", Role: "job", Version: "aaa"},
+	}
+	got := strings.Join(staleCounts(stale), "\n")
+	for _, want := range []string{"3 stale containers on 10.0.1.5", "1 stale container on 10.0.1.6"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("staleCounts() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "10.0.1.5") > strings.Index(got, "10.0.1.6") {
+		t.Errorf("staleCounts() not sorted by host:\n%s", got)
+	}
+}
+
+func TestStaleConfirmMessage(t *testing.T) {
+	dest := &kamal.DeployDestination{Service: "myapp", Name: "staging"}
+	stale := []kamal.StaleContainer{
+		{Host: "10.0.1.5", Role: "web", Version: "aaa"},
+		{Host: "10.0.1.6", Role: "job", Version: "bbb"},
+	}
+	got := staleConfirmMessage(dest, stale)
+	for _, want := range []string{"10.0.1.5: myapp-web-staging-aaa", "10.0.1.6: myapp-job-staging-bbb"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("staleConfirmMessage() missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package gui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/docker"
+	"github.com/shuvro/lazykamal/pkg/ssh"
+)
+
+// Startup stages, where New, SetCwd or NewServerMode gave up, or where
+// server mode stopped reaching its host.
+const (
+	StageTerminal  = "terminal"
+	StageProject   = "project"
+	StageSSH       = "ssh"
+	StageDiscovery = "discovery"
+)
+
+// StartupError is a failure before the TUI came up, or before server mode
+// listed the apps, with what was being done so ReportStartupError (or the
+// Apps panel) can suggest what to try next.
+type StartupError struct {
+	Stage string
+	// SSH is the ssh arguments that reach the server ("-p 2222 deploy@host")
+	// in server mode, for the commands suggested to the user.
+	SSH string
+	Err error
+}
+
+func (e *StartupError) Error() string { return e.Err.Error() }
+func (e *StartupError) Unwrap() error { return e.Err }
+
+// sshTarget is the ssh arguments that reach client's host.
+func sshTarget(client *ssh.Client) string {
+	if client.Port != "" && client.Port != "22" {
+		return "-p " + client.Port + " " + client.HostDisplay()
+	}
+	return client.HostDisplay()
+}
+
+// startupReport is what ReportStartupError prints for one failure.
+type startupReport struct {
+	category string
+	message  string
+	steps    []string
+}
+
+// startupAdvice maps a startup failure to its category and 2-3 next steps.
+// Errors that are not a StartupError get the generic treatment.
+func startupAdvice(err error) startupReport {
+	se, ok := err.(*StartupError)
+	if !ok {
+		se = &StartupError{Err: err}
+	}
+	r := startupReport{message: strings.TrimSpace(se.Err.Error())}
+	msg := strings.ToLower(r.message)
+	target := se.SSH
+	switch se.Stage {
+	case StageTerminal:
+		r.category = "Could not start the terminal interface"
+		r.steps = []string{
+			"Run lazykamal in an interactive terminal, not through a pipe or a CI job",
+			"Try a common terminal type: TERM=" + fallbackTerm + " lazykamal",
+			"For scripts, use the headless commands: lazykamal status, lazykamal deploy",
+		}
+	case StageProject:
+		r.category = "Cannot open the project directory"
+		r.steps = []string{
+			"Check that the path exists and you can read it: ls -la PATH",
+			"Run lazykamal from the project root, where config/deploy.yml lives",
+		}
+	case StageSSH:
+		switch {
+		case strings.Contains(msg, "permission denied"):
+			r.category = "SSH authentication failed"
+			r.steps = []string{
+				"Test the login without prompts: ssh -o BatchMode=yes " + target + " echo ok",
+				"Load your key into the agent (ssh-add) or set IdentityFile in ~/.ssh/config",
+				"Check the user: lazykamal --server USER@HOST",
+			}
+		case strings.Contains(msg, "host key verification failed"),
+			strings.Contains(msg, "remote host identification has changed"):
+			r.category = "SSH host key mismatch"
+			r.steps = []string{
+				"If the server was rebuilt, remove the old key: ssh-keygen -R " + sshHost(target),
+				"Then connect once by hand to accept the new key: ssh " + target,
+			}
+		case strings.Contains(msg, "could not resolve hostname"),
+			strings.Contains(msg, "name or service not known"):
+			r.category = "Unknown SSH host"
+			r.steps = []string{
+				"Check the host name for typos, or use the server's IP address",
+				"See how ssh resolves it: ssh -G " + sshHost(target) + " | grep hostname",
+			}
+		case strings.Contains(msg, "connection refused"),
+			strings.Contains(msg, "timed out"),
+			strings.Contains(msg, "no route to host"),
+			strings.Contains(msg, "network is unreachable"):
+			r.category = "Server unreachable over SSH"
+			r.steps = []string{
+				"Check that the server is up and SSH listens on that port (default 22)",
+				"See where the connection stops: ssh -v " + target + " echo ok",
+				"If it is behind a VPN or bastion, connect that first or set ProxyJump in ~/.ssh/config",
+			}
+		default:
+			r.category = "SSH connection failed"
+			r.steps = []string{
+				"Make sure this works without a password prompt: ssh " + target + " echo ok",
+				"See the full exchange: ssh -v " + target + " echo ok",
+			}
+		}
+	case StageDiscovery:
+		switch docker.ClassifyAccessError(se.Err) {
+		case docker.AccessDenied:
+			r.category = "No access to Docker on the server"
+			r.steps = []string{
+				"Add the SSH user to the docker group, then reconnect: ssh " + target + " sudo usermod -aG docker $USER",
+				"Or connect as root: lazykamal --server root@" + sshHost(target),
+			}
+		case docker.AccessNotFound:
+			r.category = "Docker is not installed on the server"
+			r.steps = []string{
+				"Check on the server: ssh " + target + " which docker",
+				"Kamal installs Docker on kamal setup; run it from the project first",
+			}
+		default:
+			r.category = "Could not discover apps on the server"
+			r.steps = []string{
+				"Run the discovery command yourself: ssh " + target + " docker ps -a",
+				"Retry with LAZYKAMAL_DEBUG=1 to log every command",
+			}
+		}
+	default:
+		r.category = "Lazykamal could not start"
+		r.steps = []string{
+			"Retry with LAZYKAMAL_DEBUG=1 to log every command",
+			"If it keeps failing, report it with this message: https://github.com/shuvro/lazykamal/issues",
+		}
+	}
+	return r
+}
+
+// sshHost is the host name in ssh arguments, without port flag or user.
+func sshHost(target string) string {
+	fields := strings.Fields(target)
+	if len(fields) == 0 {
+		return "HOST"
+	}
+	host := fields[len(fields)-1]
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	return host
+}
+
+// ReportStartupError prints err to w as a block with its category, the
+// underlying message and what to try next. Call it only after the terminal
+// has been restored (Close on a GUI that was created). Colors are used when
+// w is a terminal and the self-check left them on.
+func ReportStartupError(w io.Writer, err error) {
+	r := startupAdvice(err)
+	paint := func(text, color string) string { return text }
+	if colorOutput && isTerminal(w) {
+		paint = func(text, color string) string { return color + text + colorReset }
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, paint(iconError+" "+r.category, colorRed+colorBold))
+	for _, line := range strings.Split(r.message, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, paint("Next steps:", colorBold))
+	for i, step := range r.steps {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, step)
+	}
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,69 @@
+package gui
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStartupAdvice(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		category string
+		step     string // a substring of one of the steps
+	}{
+		{"terminal", &StartupError{Stage: StageTerminal, Err: errors.New("termbox: error while reading terminfo data")},
+			"Could not start the terminal interface", "TERM=" + fallbackTerm},
+		{"project", &StartupError{Stage: StageProject, Err: errors.New("path does not exist: /nope")},
+			"Cannot open the project directory", "config/deploy.yml"},
+		{"ssh auth", &StartupError{Stage: StageSSH, SSH: "-p 2222 deploy@web1", Err: errors.New("exit status 255: deploy@web1: Permission denied (publickey).")},
+			"SSH authentication failed", "ssh -o BatchMode=yes -p 2222 deploy@web1 echo ok"},
+		{"host key", &StartupError{Stage: StageSSH, SSH: "deploy@web1", Err: errors.New("exit status 255: Host key verification failed.")},
+			"SSH host key mismatch", "ssh-keygen -R web1"},
+		{"dns", &StartupError{Stage: StageSSH, SSH: "web1", Err: errors.New("ssh: Could not resolve hostname web1: Name or service not known")},
+			"Unknown SSH host", "ssh -G web1"},
+		{"refused", &StartupError{Stage: StageSSH, SSH: "web1", Err: errors.New("ssh: connect to host web1 port 22: Connection refused")},
+			"Server unreachable over SSH", "ssh -v web1 echo ok"},
+		{"timeout", &StartupError{Stage: StageSSH, SSH: "web1", Err: errors.New("command timed out after 30s")},
+			"Server unreachable over SSH", "ProxyJump"},
+		{"ssh other", &StartupError{Stage: StageSSH, SSH: "web1", Err: errors.New("exit status 255")},
+			"SSH connection failed", "ssh web1 echo ok"},
+		{"docker denied", &StartupError{Stage: StageDiscovery, SSH: "deploy@web1", Err: errors.New("failed to list containers: exit status 1: permission denied while trying to connect to the Docker daemon socket")},
+			"No access to Docker on the server", "lazykamal --server root@web1"},
+		{"docker missing", &StartupError{Stage: StageDiscovery, SSH: "web1", Err: errors.New("failed to list containers: exit status 127: bash: docker: command not found")},
+			"Docker is not installed on the server", "which docker"},
+		{"discovery other", &StartupError{Stage: StageDiscovery, SSH: "web1", Err: errors.New("failed to list containers: exit status 1")},
+			"Could not discover apps on the server", "ssh web1 docker ps -a"},
+		{"plain error", errors.New("boom"), "Lazykamal could not start", "LAZYKAMAL_DEBUG=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := startupAdvice(tt.err)
+			if r.category != tt.category {
+				t.Errorf("category = %q, want %q", r.category, tt.category)
+			}
+			if len(r.steps) < 2 || len(r.steps) > 3 {
+				t.Errorf("got %d steps, want 2-3: %q", len(r.steps), r.steps)
+			}
+			if !strings.Contains(strings.Join(r.steps, "\n"), tt.step) {
+				t.Errorf("steps %q do not mention %q", r.steps, tt.step)
+			}
+		})
+	}
+}
+
+func TestReportStartupErrorPlain(t *testing.T) {
+	var b bytes.Buffer
+	ReportStartupError(&b, &StartupError{Stage: StageSSH, SSH: "web1", Err: errors.New("exit status 255: Permission denied (publickey).\n")})
+	out := b.String()
+	if strings.Contains(out, "\033[") {
+		t.Errorf("color codes written to a non-terminal:\n%s", out)
+	}
+	for _, want := range []string{"SSH authentication failed", "  exit status 255: Permission denied (publickey).", "Next steps:", "  1. "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package gui
+
+import (
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// statusStaleAfter is how old the selected destination's status gets before
+// the panel says it is updating, e.g. right after switching to it.
+const statusStaleAfter = 3 * statusPoll
+
+// polledStatus is the last status poll of one destination.
+type polledStatus struct {
+	text string
+	err  string // first line of kamal's error output
+	at   time.Time
+}
+
+// storeStatus records a poll of dest. The poller passes the destination it
+// started with, so a slow poll lands in that destination's entry whatever
+// is selected by the time it finishes.
+func (gui *GUI) storeStatus(dest *kamal.DeployDestination, st polledStatus) {
+	gui.statusMu.Lock()
+	defer gui.statusMu.Unlock()
+	if gui.statuses == nil {
+		gui.statuses = map[string]polledStatus{}
+	}
+	gui.statuses[dest.ConfigPath] = st
+}
+
+// statusFor is the last poll of dest, and false when it was never polled.
+func (gui *GUI) statusFor(dest *kamal.DeployDestination) (polledStatus, bool) {
+	gui.statusMu.Lock()
+	defer gui.statusMu.Unlock()
+	st, ok := gui.statuses[dest.ConfigPath]
+	return st, ok
+}
+
+// requestStatus asks the poller to refresh now instead of at the next tick,
+// e.g. after the selected app changes. Requests made while one is pending
+// are merged.
+func (gui *GUI) requestStatus() {
+	select {
+	case gui.statusRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// staleStatusNote is the line above a cached status older than
+// statusStaleAfter, or "" for a recent one.
+func staleStatusNote(st polledStatus, now time.Time) string {
+	age := now.Sub(st.at)
+	if age < statusStaleAfter {
+		return ""
+	}
+	return " " + dim("updating… (last: "+formatElapsed(age)+" ago)")
+}
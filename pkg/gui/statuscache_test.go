@@ -0,0 +1,78 @@
+package gui
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestRefreshStatusSlowPollKeepsToItsDestination(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	runner := &kamal.FakeRunner{Respond: func(args []string) ([]string, int) {
+		cmd := strings.Join(args, " ")
+		if !strings.HasPrefix(cmd, "app version ") {
+			return kamal.DemoResponse(args)
+		}
+		// Staging answers slowly, as over a bad link; production at once.
+		if strings.Contains(cmd, "--destination staging") {
+			once.Do(func() { close(started) })
+			<-release
+			return []string{"staging-build"}, 0
+		}
+		return []string{"production-build"}, 0
+	}}
+	gui := newFakeGUI(t, runner)
+	staging := gui.selectedDestination()
+	productionIdx := -1
+	for i, d := range gui.destinations {
+		if d.Name == "production" {
+			productionIdx = i
+		}
+	}
+	production := &gui.destinations[productionIdx]
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		gui.refreshStatus()
+	}()
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("staging poll never started")
+	}
+	// The user moves to production while staging is still polling.
+	gui.selectedApp = productionIdx
+	gui.refreshStatus()
+	close(release)
+	wg.Wait()
+
+	st, ok := gui.statusFor(production)
+	if !ok || !strings.Contains(st.text, "production-build") || strings.Contains(st.text, "staging-build") {
+		t.Errorf("production status = %q, %v; the slow staging poll overwrote it", st.text, ok)
+	}
+	st, ok = gui.statusFor(staging)
+	if !ok || !strings.Contains(st.text, "staging-build") {
+		t.Errorf("staging status = %q, %v", st.text, ok)
+	}
+	if sel, _ := gui.statusFor(gui.selectedDestination()); sel.text != gui.statuses[production.ConfigPath].text {
+		t.Error("the panel should read the selected destination's entry")
+	}
+}
+
+func TestStaleStatusNote(t *testing.T) {
+	plainStyle(t)
+	now := time.Now()
+	if got := staleStatusNote(polledStatus{at: now.Add(-statusPoll)}, now); got != "" {
+		t.Errorf("recent status note = %q, want none", got)
+	}
+	if got, want := staleStatusNote(polledStatus{at: now.Add(-42 * time.Second)}, now), " updating… (last: 42s ago)"; got != want {
+		t.Errorf("stale status note = %q, want %q", got, want)
+	}
+}
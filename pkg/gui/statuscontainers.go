@@ -0,0 +1,87 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// statusRows is how many raw lines of `kamal app containers` the Live status
+// panel shows when the output cannot be parsed.
+const statusRows = 8
+
+// containersStatus is the Containers section of the Live status panel: an
+// aligned row per container with a state bullet, name, state and image tag,
+// hosts included when there are several. Output the parser does not
+// recognise is shown raw, so an unusual kamal version still shows something.
+func containersStatus(output string) string {
+	rows := kamal.ParseAppContainers(output)
+	if len(rows) == 0 {
+		return " Containers:\n" + stringsTrim(output, statusRows) + "\n"
+	}
+	hosts := map[string]bool{}
+	running := 0
+	for _, c := range rows {
+		hosts[c.Host] = true
+		if c.Running() {
+			running++
+		}
+	}
+	cells := make([][]string, len(rows))
+	widths := make([]int, 3)
+	for i, c := range rows {
+		name := c.Name
+		if name == "" {
+			name = c.ID
+		}
+		state := c.State()
+		if state == "" {
+			state = c.Status
+		}
+		cells[i] = []string{truncate(name, 32), state, truncate(c.Tag(), 12)}
+		if len(hosts) > 1 {
+			cells[i] = append([]string{c.Host}, cells[i]...)
+		}
+		for j, cell := range cells[i] {
+			if j >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := visibleWidth(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, " Containers: %d/%d running\n", running, len(rows))
+	for i, c := range rows {
+		b.WriteString("   " + stateBullet(c.State()))
+		last := len(cells[i]) - 1
+		for j, cell := range cells[i] {
+			if j == last {
+				// The tag is dimmed and not padded.
+				b.WriteString("  " + dim(cell))
+				continue
+			}
+			b.WriteString(" ")
+			if j > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(padRight(cell, widths[j]))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// stateBullet is a container state as a colored dot: green when running,
+// yellow while it restarts or is paused, red otherwise.
+func stateBullet(state string) string {
+	switch state {
+	case "running":
+		return green(iconRunning)
+	case "restarting", "paused", "created":
+		return yellow(iconRunning)
+	}
+	return red(iconRunning)
+}
@@ -0,0 +1,33 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContainersStatus(t *testing.T) {
+	plainStyle(t)
+	output := `App Host: 10.0.1.5
+CONTAINER ID   IMAGE                        COMMAND                  CREATED       STATUS                     PORTS    NAMES
+c0ffee123456   registry/myapp:4f1c2d9e      "/rails/bin/docker-e…"   3 hours ago   Up 3 hours (healthy)       80/tcp   myapp-web-4f1c2d9e
+App Host: 10.0.1.6
+CONTAINER ID   IMAGE                        COMMAND                  CREATED       STATUS                     PORTS    NAMES
+0badc0de7890   registry/myapp:88aa77bb      "/rails/bin/docker-e…"   2 days ago    Exited (137) 3 hours ago            myapp-web-88aa77bb
+`
+	want := " Containers: 1/2 running\n" +
+		"   * 10.0.1.5  myapp-web-4f1c2d9e  running  4f1c2d9e\n" +
+		"   * 10.0.1.6  myapp-web-88aa77bb  exited   88aa77bb\n"
+	if got := containersStatus(output); got != want {
+		t.Errorf("containersStatus() =\n%s\nwant\n%s", got, want)
+	}
+
+	oneHost := strings.SplitN(output, "App Host: 10.0.1.6", 2)[0]
+	if got := containersStatus(oneHost); !strings.Contains(got, "   * myapp-web-4f1c2d9e  running  4f1c2d9e\n") {
+		t.Errorf("one host: %q, want no host column", got)
+	}
+
+	raw := "Some future kamal format\nweb: running"
+	if got := containersStatus(raw); got != " Containers:\n Some future kamal format\n web: running\n" {
+		t.Errorf("unparsed output: %q, want it raw", got)
+	}
+}
@@ -0,0 +1,101 @@
+package gui
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// completedLogged reports whether the Output has the command's completion
+// line yet, successful or not.
+func completedLogged(gui *GUI) bool {
+	gui.logMu.Lock()
+	defer gui.logMu.Unlock()
+	for _, e := range gui.logLines {
+		if strings.Contains(e.text, " completed in ") || strings.Contains(e.text, " failed (exit ") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMutatingCommandPollsStatusBeforeSuccess(t *testing.T) {
+	tests := []struct {
+		name   string
+		screen Screen
+		idx    int
+		exit   int
+		polled bool
+	}{
+		{"App Restart", ScreenApp, 3, 0, true},
+		{"Proxy Reboot", ScreenProxy, 4, 0, true},
+		{"App Details", ScreenApp, 6, 0, false},       // a query
+		{"Secrets Fetch", ScreenSecrets, 0, 0, false}, // a query
+		{"App Restart", ScreenApp, 3, 1, false},       // failed
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var early []string // calls made before the success line, the command first
+			runner := &kamal.FakeRunner{}
+			gui := newFakeGUI(t, runner)
+			runner.Respond = func(args []string) ([]string, int) {
+				done := completedLogged(gui)
+				mu.Lock()
+				defer mu.Unlock()
+				if done {
+					return kamal.DemoResponse(args)
+				}
+				early = append(early, strings.Join(args, " "))
+				if len(early) == 1 {
+					return nil, tt.exit
+				}
+				return kamal.DemoResponse(args)
+			}
+			gui.screen, gui.submenuIdx = tt.screen, tt.idx
+			if err := gui.keyEnter(nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			waitIdle(t, gui)
+			mu.Lock()
+			defer mu.Unlock()
+			polled := len(early) > 1 && early[1] == "app version --destination staging"
+			if polled != tt.polled {
+				t.Errorf("%s (exit %d): calls before the success line %q, want polled %v", tt.name, tt.exit, early, tt.polled)
+			}
+			if _, ok := gui.statusFor(gui.selectedDestination()); tt.polled && !ok {
+				t.Error("no status stored for the destination")
+			}
+		})
+	}
+}
+
+func TestPollStatusUsesTheDestinationsOwnFlags(t *testing.T) {
+	runner := &kamal.FakeRunner{}
+	gui := newFakeGUI(t, runner)
+	staging := *gui.selectedDestination()
+	var production kamal.DeployDestination
+	for _, d := range gui.destinations {
+		if d.Name == "production" {
+			production = d
+		}
+	}
+	gui.hostSelections = map[string][]string{hostsKey(&staging): {"10.0.1.5"}, hostsKey(&production): {"10.0.2.7"}}
+	gui.roleSelections = map[string][]string{hostsKey(&staging): {"workers"}}
+
+	// A command on production finished after staging was selected.
+	gui.pollStatus(&production)
+	calls := runner.Calls()
+	if len(calls) == 0 {
+		t.Fatal("no status calls")
+	}
+	if got, want := strings.Join(calls[0], " "), "app version --destination production --hosts 10.0.2.7"; got != want {
+		t.Errorf("first status call = %q, want %q", got, want)
+	}
+	if _, ok := gui.statusFor(&production); !ok {
+		t.Error("no status stored for production")
+	}
+}
@@ -0,0 +1,76 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamStats counts what one live log stream received since it started,
+// paused lines included, for the Output title and the line logged when
+// it stops.
+type streamStats struct {
+	mu    sync.Mutex
+	name  string // container or kamal command streamed
+	start time.Time
+	lines int
+	bytes int64
+}
+
+func newStreamStats(name string, now time.Time) *streamStats {
+	return &streamStats{name: name, start: now}
+}
+
+// add counts one received line and its newline.
+func (s *streamStats) add(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lines++
+	s.bytes += int64(len(line)) + 1
+}
+
+// title is the Output title of the stream at now.
+func (s *streamStats) title(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return streamTitle(truncate(s.name, 20), now.Sub(s.start), s.lines)
+}
+
+// totals is what the stream received by now, for the line logged when it
+// stops.
+func (s *streamStats) totals(now time.Time) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return streamTotals(now.Sub(s.start), s.lines, s.bytes)
+}
+
+// streamTitle is "LIVE: web-abc · 12m · 8,431 lines".
+func streamTitle(name string, elapsed time.Duration, lines int) string {
+	return glyphs(fmt.Sprintf("LIVE: %s · %s · %s", name, formatElapsed(elapsed), countNoun(lines, "line")))
+}
+
+// streamTotals is "8,431 lines (1.2 MB) in 12m".
+func streamTotals(elapsed time.Duration, lines int, bytes int64) string {
+	return fmt.Sprintf("%s (%s) in %s", countNoun(lines, "line"), formatBytes(bytes), formatElapsed(elapsed))
+}
+
+// streamName is a kamal logs command as the stream's name: its words
+// without flags, "app logs".
+func streamName(subcommand []string) string {
+	var words []string
+	for _, w := range subcommand {
+		if !strings.HasPrefix(w, "-") {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// countNoun is n with thousands separators and noun, plural unless n is 1.
+func countNoun(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+	return formatCount(n) + " " + noun
+}
@@ -0,0 +1,42 @@
+package gui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatElapsed(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                                       "0s",
+		42 * time.Second:                        "42s",
+		12*time.Minute + 30*time.Second:         "12m",
+		time.Hour + 5*time.Minute + time.Second: "1h05m",
+	} {
+		if got := formatElapsed(d); got != want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestStreamStats(t *testing.T) {
+	plainStyle(t)
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	s := newStreamStats("web-abc", start)
+	s.add("hello")
+	s.add("")
+	if got, want := s.title(start.Add(12*time.Minute)), "LIVE: web-abc - 12m - 2 lines"; got != want {
+		t.Errorf("title = %q, want %q", got, want)
+	}
+	if got, want := s.totals(start.Add(42*time.Second)), "2 lines (7 B) in 42s"; got != want {
+		t.Errorf("totals = %q, want %q", got, want)
+	}
+	if got, want := streamTotals(12*time.Minute, 8431, 1200000), "8,431 lines (1.1 MB) in 12m"; got != want {
+		t.Errorf("streamTotals = %q, want %q", got, want)
+	}
+	if got := countNoun(1, "line"); got != "1 line" {
+		t.Errorf("countNoun(1) = %q", got)
+	}
+	if got := streamName([]string{"app", "logs", "-f", "--lines", "100"}); got != "app logs 100" {
+		t.Errorf("streamName = %q", got)
+	}
+}
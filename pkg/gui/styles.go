@@ -2,8 +2,12 @@ package gui
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
 )
 
 // ANSI color codes for terminal styling
@@ -11,6 +15,7 @@ const (
 	colorReset   = "\033[0m"
 	colorBold    = "\033[1m"
 	colorDim     = "\033[2m"
+	colorReverse = "\033[7m"
 	colorRed     = "\033[31m"
 	colorGreen   = "\033[32m"
 	colorYellow  = "\033[33m"
@@ -25,8 +30,9 @@ const (
 // Spinner frames for loading animation
 var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
 
-// Status icons
-const (
+// Status icons. useASCII swaps them for the ASCII set on terminals
+// without UTF-8.
+var (
 	iconSuccess  = "✓"
 	iconError    = "✗"
 	iconRunning  = "●"
@@ -54,8 +60,82 @@ const (
 	iconTerminal = "⌨"
 )
 
+// Tree branches for nested list rows, e.g. a web role's containers.
+var (
+	iconBranch     = "├─"
+	iconBranchLast = "└─"
+)
+
+// asciiIcons pairs each icon with its plain-ASCII stand-in.
+var asciiIcons = []struct {
+	icon  *string
+	ascii string
+}{
+	{&iconSuccess, "[OK]"}, {&iconError, "[ERR]"}, {&iconRunning, "*"}, {&iconPending, "o"},
+	{&iconWarning, "!"}, {&iconInfo, "i"}, {&iconArrow, ">"}, {&iconDot, "*"},
+	{&iconCheck, "[OK]"}, {&iconCross, "[ERR]"}, {&iconStar, "*"}, {&iconPlay, ">"},
+	{&iconStop, "#"}, {&iconPause, "||"}, {&iconRefresh, "~"}, {&iconFolder, "+"},
+	{&iconFile, "-"}, {&iconGear, "*"}, {&iconRocket, ">"}, {&iconServer, "#"},
+	{&iconLock, "L"}, {&iconUnlock, "U"}, {&iconKey, "K"}, {&iconPackage, "P"},
+	{&iconTerminal, ">"}, {&iconBranch, "|-"}, {&iconBranchLast, "`-"},
+}
+
+// asciiOutput is true once useASCII ran; gocui then draws ASCII frames.
+var asciiOutput bool
+
+// useASCII switches icons, spinner and frames to plain ASCII.
+func useASCII() {
+	asciiOutput = true
+	for _, i := range asciiIcons {
+		*i.icon = i.ascii
+	}
+	spinnerFrames = []string{"|", "/", "-", "\\"}
+}
+
+// asciiGlyphs replaces the box drawing and punctuation used in fixed text
+// (help, banners, menus, hints) with ASCII.
+var asciiGlyphs = strings.NewReplacer(
+	"─", "-", "═", "=", "║", "|", "╔", "+", "╗", "+", "╚", "+", "╝", "+",
+	"├", "|", "└", "`", "→", "->", "←", "<-", "↑", "^", "↓", "v",
+	"…", "...", "—", "-", "–", "-", "·", "-", "»", ">>", "›", ">", "•", "*",
+	"●", "*", "✓", "[OK]", "✗", "[ERR]", "█", "#", "░", ".", "▸", "+", "▾", "-",
+)
+
+// glyphs is s as drawn: unchanged, or with ASCII stand-ins after useASCII.
+func glyphs(s string) string {
+	if !asciiOutput {
+		return s
+	}
+	return asciiGlyphs.Replace(s)
+}
+
+// glyphWriter writes through glyphs, for views of fixed text.
+type glyphWriter struct{ w io.Writer }
+
+func (g glyphWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(g.w, glyphs(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// colorOutput is false when the terminal self-check, NO_COLOR or
+// --no-color turned colors off. The color helpers then return text as is.
+var colorOutput = true
+
+// Style is how the interface is drawn, from the command line: NoColor
+// (--no-color) turns colors off, ASCII (--ascii) uses plain-ASCII icons,
+// spinner and frames. The terminal self-check may downgrade further.
+type Style struct {
+	NoColor bool
+	ASCII   bool
+}
+
 // Styled text helpers
 func colorize(text, color string) string {
+	if !colorOutput {
+		return text
+	}
 	return color + text + colorReset
 }
 
@@ -103,14 +183,30 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%dm", h, m)
 }
 
-// FormatTimestamp formats a timestamp for display
-func formatTimestamp(t time.Time) string {
-	return t.Format("15:04:05")
+// formatElapsed is d to the coarsest useful unit, for a long-running
+// stream: "42s", "12m", "1h05m".
+func formatElapsed(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
 }
 
-// TimestampedLine creates a log line with timestamp
-func timestampedLine(line string) string {
-	return dim(formatTimestamp(time.Now())) + " " + line
+// formatBytes formats a byte count, e.g. "512 B", "3.4 MB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 4; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTP"[exp])
 }
 
 // ProgressBar creates a simple progress bar
@@ -125,47 +221,148 @@ func progressBar(percent, width int) string {
 	filled := width * percent / 100
 	empty := width - filled
 
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", empty)
+	bar := glyphs(strings.Repeat("█", filled) + strings.Repeat("░", empty))
 	return fmt.Sprintf("[%s] %d%%", bar, percent)
 }
 
 // Separator creates a horizontal separator line
 func separator(width int, char string) string {
-	return strings.Repeat(char, width)
+	return glyphs(strings.Repeat(char, width))
 }
 
-// Truncate truncates a string to max length with ellipsis
+// visibleWidth is the number of terminal cells s takes: escape codes take
+// none and wide runes (CJK, most emoji) take two.
+func visibleWidth(s string) int {
+	return runewidth.StringWidth(stripANSI(s))
+}
+
+// Truncate shortens s to at most maxLen cells, ending in "..." when cut;
+// maxLen <= 0 means no limit. Escape codes are kept whole, and a reset is
+// appended when the cut falls inside a colored region so the color does
+// not leak past the string.
 func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if maxLen <= 0 || visibleWidth(s) <= maxLen {
 		return s
 	}
-	if maxLen <= 3 {
-		return s[:maxLen]
+	ellipsis := "..."
+	if maxLen <= len(ellipsis) {
+		ellipsis = ""
+	}
+	budget := maxLen - len(ellipsis)
+	var b strings.Builder
+	width, colored := 0, false
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if loc := ansiSequence.FindStringIndex(s[i:]); loc != nil && loc[0] == 0 {
+				seq := s[i : i+loc[1]]
+				if strings.HasSuffix(seq, "m") {
+					colored = seq != colorReset && seq != "\x1b[m"
+				}
+				b.WriteString(seq)
+				i += loc[1]
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runewidth.RuneWidth(r)
+		if width+w > budget {
+			break
+		}
+		b.WriteString(s[i : i+size])
+		width += w
+		i += size
 	}
-	return s[:maxLen-3] + "..."
+	b.WriteString(ellipsis)
+	if colored {
+		b.WriteString(colorReset)
+	}
+	return b.String()
 }
 
-// PadRight pads a string to the right with spaces
+// truncateMiddle shortens plain s to at most maxLen cells by cutting its
+// middle, so both the prefix and a distinguishing suffix (a version hash in
+// a container name) stay visible: "myapp-web-...-4f1c2d9e".
+func truncateMiddle(s string, maxLen int) string {
+	const ellipsis = "..."
+	if maxLen <= 0 || visibleWidth(s) <= maxLen {
+		return s
+	}
+	if maxLen <= len(ellipsis)+2 {
+		return truncate(s, maxLen)
+	}
+	budget := maxLen - len(ellipsis)
+	runes := []rune(s)
+	tail, tailWidth := len(runes), 0
+	for tail > 0 && tailWidth+runewidth.RuneWidth(runes[tail-1]) <= budget-budget/2 {
+		tail--
+		tailWidth += runewidth.RuneWidth(runes[tail])
+	}
+	head, headWidth := 0, 0
+	for head < tail && headWidth+runewidth.RuneWidth(runes[head]) <= budget-tailWidth {
+		headWidth += runewidth.RuneWidth(runes[head])
+		head++
+	}
+	return string(runes[:head]) + ellipsis + string(runes[tail:])
+}
+
+// PadRight pads a string to the right with spaces up to width cells
 func padRight(s string, width int) string {
-	if len(s) >= width {
+	w := visibleWidth(s)
+	if w >= width {
 		return s
 	}
-	return s + strings.Repeat(" ", width-len(s))
+	return s + strings.Repeat(" ", width-w)
 }
 
-// PadLeft pads a string to the left with spaces
+// PadLeft pads a string to the left with spaces up to width cells
 func padLeft(s string, width int) string {
-	if len(s) >= width {
+	w := visibleWidth(s)
+	if w >= width {
 		return s
 	}
-	return strings.Repeat(" ", width-len(s)) + s
+	return strings.Repeat(" ", width-w) + s
 }
 
-// Center centers a string within a width
+// Center centers a string within width cells
 func center(s string, width int) string {
-	if len(s) >= width {
+	w := visibleWidth(s)
+	if w >= width {
 		return s
 	}
-	padding := (width - len(s)) / 2
-	return strings.Repeat(" ", padding) + s + strings.Repeat(" ", width-len(s)-padding)
+	padding := (width - w) / 2
+	return strings.Repeat(" ", padding) + s + strings.Repeat(" ", width-w-padding)
+}
+
+// wrapText splits s into lines of at most width runes, breaking on spaces where
+// possible. Explicit newlines in s are preserved.
+func wrapText(s string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					out = append(out, line)
+					line = ""
+				}
+				r := []rune(word)
+				out = append(out, string(r[:width]))
+				word = string(r[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				out = append(out, line)
+				line = word
+			}
+		}
+		out = append(out, line)
+	}
+	return out
 }
@@ -1,10 +1,32 @@
 package gui
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/shuvro/lazykamal/pkg/docker"
+	"github.com/shuvro/lazykamal/pkg/kamal"
 )
 
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KB"},
+		{8<<20 + 123, "8.0 MB"},
+		{3 << 30, "3.0 GB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
 func TestFormatDuration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -39,6 +61,18 @@ func TestTruncate(t *testing.T) {
 		{"hi", 2, "hi"},
 		{"hello", 5, "hello"},
 		{"hello", 4, "h..."},
+		{"hello", 0, "hello"},
+		{"hello", 2, "he"},
+		// Escape codes take no width and are never split; a cut inside a
+		// colored region ends with a reset.
+		{colorGreen + "hello" + colorReset, 5, colorGreen + "hello" + colorReset},
+		{colorGreen + "hello world" + colorReset, 8, colorGreen + "hello..." + colorReset},
+		{"ab" + colorRed + "cd" + colorReset + "efgh", 6, "ab" + colorRed + "c..." + colorReset},
+		{"ab" + colorRed + "cd" + colorReset + "efgh", 7, "ab" + colorRed + "cd" + colorReset + "..."},
+		// Wide runes take two cells and are never split.
+		{"日本語テキスト", 8, "日本..."},
+		{"日本語", 6, "日本語"},
+		{"🚀🚀🚀🚀", 7, "🚀🚀..."},
 	}
 
 	for _, tt := range tests {
@@ -51,6 +85,32 @@ func TestTruncate(t *testing.T) {
 	}
 }
 
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		input    string
+		maxLen   int
+		expected string
+	}{
+		{"myapp-web-4f1c2d9e", 30, "myapp-web-4f1c2d9e"},
+		{"myapp-web-production-4f1c2d9e", 20, "myapp-we...-4f1c2d9e"},
+		{"myapp-web-production-4f1c2d9e", 11, "myap...2d9e"},
+		{"hello", 0, "hello"},
+		{"hello world", 5, "he..."},
+		{"日本語テキスト", 9, "日本...ト"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := truncateMiddle(tt.input, tt.maxLen)
+			if result != tt.expected {
+				t.Errorf("truncateMiddle(%q, %d) = %q, want %q", tt.input, tt.maxLen, result, tt.expected)
+			}
+			if tt.maxLen > 0 && visibleWidth(result) > tt.maxLen {
+				t.Errorf("truncateMiddle(%q, %d) is %d cells wide", tt.input, tt.maxLen, visibleWidth(result))
+			}
+		})
+	}
+}
+
 func TestPadRight(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -60,6 +120,9 @@ func TestPadRight(t *testing.T) {
 		{"hello", 10, "hello     "},
 		{"hello", 5, "hello"},
 		{"hello", 3, "hello"},
+		{colorRed + "ok" + colorReset, 5, colorRed + "ok" + colorReset + "   "},
+		{"日本", 6, "日本  "},
+		{"🚀", 3, "🚀 "},
 	}
 
 	for _, tt := range tests {
@@ -81,6 +144,8 @@ func TestPadLeft(t *testing.T) {
 		{"hello", 10, "     hello"},
 		{"hello", 5, "hello"},
 		{"hello", 3, "hello"},
+		{colorRed + "ok" + colorReset, 4, "  " + colorRed + "ok" + colorReset},
+		{"日本", 5, " 日本"},
 	}
 
 	for _, tt := range tests {
@@ -103,6 +168,9 @@ func TestCenter(t *testing.T) {
 		{"hello", 5, "hello"},
 		{"hello", 3, "hello"},
 		{"a", 4, " a  "},
+		{colorBold + "hi" + colorReset, 6, "  " + colorBold + "hi" + colorReset + "  "},
+		{"日本", 6, " 日本 "},
+		{"🚀", 5, " 🚀  "},
 	}
 
 	for _, tt := range tests {
@@ -144,3 +212,90 @@ func TestSeparator(t *testing.T) {
 		t.Errorf("separator(5, \"-\") = %q, want \"-----\"", result)
 	}
 }
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  []string
+	}{
+		{"short", "Stop the proxy?", 40, []string{"Stop the proxy?"}},
+		{"wraps on spaces", "docker run --detach --name kamal-proxy", 20, []string{"docker run --detach", "--name kamal-proxy"}},
+		{"long word", "abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"newlines kept", "one\ntwo", 10, []string{"one", "two"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapText(tt.input, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapText(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+// plainStyle turns colors off and switches to ASCII until the test ends.
+func plainStyle(t *testing.T) {
+	t.Helper()
+	icons := make([]string, len(asciiIcons))
+	for i, ic := range asciiIcons {
+		icons[i] = *ic.icon
+	}
+	frames, color, ascii := spinnerFrames, colorOutput, asciiOutput
+	t.Cleanup(func() {
+		for i, ic := range asciiIcons {
+			*ic.icon = icons[i]
+		}
+		spinnerFrames, colorOutput, asciiOutput = frames, color, ascii
+	})
+	colorOutput = false
+	useASCII()
+}
+
+func TestPlainStyle(t *testing.T) {
+	plainStyle(t)
+
+	sp := NewSpinner("Deploy", nil)
+	rendered := []string{
+		statusLine("success", "Deploy completed in 3.0s"),
+		statusLine("error", "Deploy failed (exit 1) in 3.0s"),
+		statusLine("running", "Deploy"),
+		statusLine("warning", "kamal version mismatch"),
+		statusLine("info", "Running: Deploy"),
+		sp.Frame(),
+		healthDot(0), healthMark(1), healthSummary(docker.AppHealth{}, true),
+		formatProxyStatus("running"), formatProxyStatus("not running"),
+		summaryCommand(sessionCommand{Name: "Deploy", Destination: "myapp (staging)", Took: time.Minute, Outcome: "ok", Version: "abc123"}),
+		glyphs(hostVerdict([]kamal.HostResult{{Host: "10.0.1.5", Duration: time.Second}, {Host: "10.0.1.6", Failed: true}}, 0)),
+		newLogTags().label(logEntry{text: "x", dest: "a"}).render(zoneUTC, 0),
+		cleanOutput("\x1b[31m ERROR boom\x1b[0m", ansiStrip),
+		truncate(red("abcdefghij"), 5),
+		progressBar(50, 10),
+		separator(5, "─"),
+		glyphs("Connect to server → … — ↑/↓ ╔═╗ ║ ╚═╝"),
+	}
+	for screen := range menus {
+		rendered = append(rendered, menuLabels(screen, 0)...)
+	}
+	for _, s := range rendered {
+		if strings.Contains(s, "\033") {
+			t.Errorf("%q contains an escape sequence", s)
+		}
+		for _, r := range s {
+			if r > 0x7e {
+				t.Errorf("%q contains non-ASCII %q", s, r)
+				break
+			}
+		}
+	}
+	if got := statusLine("success", "ok"); got != "[OK] ok" {
+		t.Errorf("statusLine(success) = %q, want %q", got, "[OK] ok")
+	}
+	if got := statusLine("error", "boom"); got != "[ERR] boom" {
+		t.Errorf("statusLine(error) = %q, want %q", got, "[ERR] boom")
+	}
+	if strings.Join(spinnerFrames, "") != `|/-\` {
+		t.Errorf("spinner frames = %q", spinnerFrames)
+	}
+}
@@ -0,0 +1,170 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// summaryLines caps the session summary printed on quit.
+const summaryLines = 15
+
+// sessionCommand is one command run during the session.
+type sessionCommand struct {
+	Name        string
+	Destination string // label of the destination it ran against
+	Took        time.Duration
+	Unreliable  bool   // timed across a suspend
+	Outcome     string // "ok", "exit 1", "cancelled", ...
+	Version     string // version running after a deploy, if known
+}
+
+func (c sessionCommand) ok() bool { return c.Outcome == "ok" }
+
+// sessionLog keeps the commands run since startup, for the summary on quit.
+type sessionLog struct {
+	mu       sync.Mutex
+	started  time.Time
+	commands []sessionCommand
+}
+
+func (s *sessionLog) record(c sessionCommand) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commands = append(s.commands, c)
+}
+
+// setVersion notes the version a deploy left running on the last command.
+func (s *sessionLog) setVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n := len(s.commands); n > 0 {
+		s.commands[n-1].Version = version
+	}
+}
+
+func (s *sessionLog) snapshot() (time.Time, []sessionCommand) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.started, append([]sessionCommand(nil), s.commands...)
+}
+
+// sessionOutcome is how a command ended, for the summary.
+func sessionOutcome(res kamal.Result, err error, stopCh <-chan struct{}) string {
+	select {
+	case <-stopCh:
+		return "cancelled"
+	default:
+	}
+	var timeout *kamal.TimeoutError
+	switch {
+	case errors.As(err, &timeout):
+		return err.Error()
+	case err != nil:
+		return "error"
+	case res.ExitCode != 0:
+		return fmt.Sprintf("exit %d", res.ExitCode)
+	}
+	return "ok"
+}
+
+// SessionSummary is the recap printed after the terminal is restored, or ""
+// when no command ran or .lazykamal.yml sets no_session_summary.
+func (gui *GUI) SessionSummary() string {
+	if gui.projectConfig().NoSessionSummary {
+		return ""
+	}
+	started, commands := gui.session.snapshot()
+	lines := sessionSummaryLines(started, time.Now(), commands)
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// sessionSummaryLines renders the recap: totals, destinations touched,
+// deploys with the version they left running, then the commands, newest
+// kept when they do not all fit in summaryLines.
+func sessionSummaryLines(started, ended time.Time, commands []sessionCommand) []string {
+	if len(commands) == 0 {
+		return nil
+	}
+	failed := 0
+	touched := map[string]bool{}
+	var dests []string
+	var deploys []sessionCommand
+	for _, c := range commands {
+		if !c.ok() {
+			failed++
+		}
+		if c.Destination != "" && !touched[c.Destination] {
+			touched[c.Destination] = true
+			dests = append(dests, c.Destination)
+		}
+		if etaCommands[c.Name] {
+			deploys = append(deploys, c)
+		}
+	}
+	sort.Strings(dests)
+
+	outcome := fmt.Sprintf("%d ok", len(commands)-failed)
+	if failed > 0 {
+		outcome += fmt.Sprintf(", %d failed", failed)
+	}
+	lines := []string{
+		fmt.Sprintf("lazykamal session: %s, %d command(s) (%s)", formatDuration(ended.Sub(started).Truncate(time.Second)), len(commands), outcome),
+	}
+	if len(dests) > 0 {
+		lines = append(lines, "Destinations: "+strings.Join(dests, ", "))
+	}
+	if len(deploys) > 0 {
+		lines = append(lines, "Deploys:")
+		for _, d := range deploys {
+			lines = append(lines, "  "+summaryCommand(d))
+		}
+	}
+
+	room := summaryLines - len(lines) - 1
+	if room < 1 {
+		if len(lines) > summaryLines {
+			lines = lines[:summaryLines]
+		}
+		return lines
+	}
+	lines = append(lines, "Commands:")
+	shown := commands
+	if len(shown) > room {
+		shown = shown[len(shown)-room+1:]
+		lines = append(lines, fmt.Sprintf("  … %d earlier", len(commands)-len(shown)))
+	}
+	for _, c := range shown {
+		lines = append(lines, "  "+summaryCommand(c))
+	}
+	return lines
+}
+
+// summaryCommand is one command's line, e.g.
+// "✓ Deploy  myapp (staging)  4m12s → abc123".
+func summaryCommand(c sessionCommand) string {
+	mark := iconSuccess
+	if !c.ok() {
+		mark = iconError
+	}
+	line := mark + " " + c.Name
+	if c.Destination != "" {
+		line += "  " + c.Destination
+	}
+	line += "  " + tookText(c.Took, c.Unreliable)
+	if !c.ok() {
+		line += " (" + c.Outcome + ")"
+	}
+	if c.Version != "" {
+		line += " → " + c.Version
+	}
+	return glyphs(line)
+}
@@ -0,0 +1,79 @@
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+const sessionSummaryGolden = `lazykamal session: 1h12m, 5 command(s) (3 ok, 2 failed)
+Destinations: myapp (production), myapp (staging)
+Deploys:
+  ✓ Deploy  myapp (staging)  4m12s → abc123
+  ✗ Deploy  myapp (production)  1m30s (exit 1)
+Commands:
+  ✓ App Logs  myapp (staging)  2.1s
+  ✓ Deploy  myapp (staging)  4m12s → abc123
+  ✗ Deploy  myapp (production)  1m30s (exit 1)
+  ✗ Lock Release  myapp (production)  800ms (cancelled)
+  ✓ Config Validate  3.0s`
+
+func TestSessionSummaryLines(t *testing.T) {
+	started := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	commands := []sessionCommand{
+		{Name: "App Logs", Destination: "myapp (staging)", Took: 2100 * time.Millisecond, Outcome: "ok"},
+		{Name: "Deploy", Destination: "myapp (staging)", Took: 4*time.Minute + 12*time.Second, Outcome: "ok", Version: "abc123"},
+		{Name: "Deploy", Destination: "myapp (production)", Took: 90 * time.Second, Outcome: "exit 1"},
+		{Name: "Lock Release", Destination: "myapp (production)", Took: 800 * time.Millisecond, Outcome: "cancelled"},
+		{Name: "Config Validate", Took: 3 * time.Second, Outcome: "ok"},
+	}
+	got := strings.Join(sessionSummaryLines(started, started.Add(72*time.Minute+5*time.Second), commands), "\n")
+	if got != sessionSummaryGolden {
+		t.Errorf("sessionSummaryLines() =\n%s\nwant\n%s", got, sessionSummaryGolden)
+	}
+
+	if lines := sessionSummaryLines(started, started.Add(time.Hour), nil); lines != nil {
+		t.Errorf("no commands: got %q, want no summary", lines)
+	}
+}
+
+func TestSessionSummaryLinesLimit(t *testing.T) {
+	started := time.Now()
+	var commands []sessionCommand
+	for i := 0; i < 40; i++ {
+		commands = append(commands, sessionCommand{Name: fmt.Sprintf("App Version %d", i), Took: time.Second, Outcome: "ok"})
+	}
+	lines := sessionSummaryLines(started, started, commands)
+	if len(lines) != summaryLines {
+		t.Fatalf("%d lines, want %d:\n%s", len(lines), summaryLines, strings.Join(lines, "\n"))
+	}
+	if lines[2] != "  … 28 earlier" || !strings.Contains(lines[len(lines)-1], "App Version 39") {
+		t.Errorf("newest commands not kept:\n%s", strings.Join(lines, "\n"))
+	}
+}
+
+func TestSessionOutcome(t *testing.T) {
+	stopped := make(chan struct{})
+	close(stopped)
+	tests := []struct {
+		res    kamal.Result
+		err    error
+		stopCh chan struct{}
+		want   string
+	}{
+		{kamal.Result{}, nil, nil, "ok"},
+		{kamal.Result{ExitCode: 2}, nil, nil, "exit 2"},
+		{kamal.Result{}, errors.New("boom"), nil, "error"},
+		{kamal.Result{}, errors.New("signal: killed"), stopped, "cancelled"},
+		{kamal.Result{ExitCode: -1}, &kamal.TimeoutError{After: time.Minute}, nil, "timed out after 60s"},
+	}
+	for _, tt := range tests {
+		if got := sessionOutcome(tt.res, tt.err, tt.stopCh); got != tt.want {
+			t.Errorf("sessionOutcome(%v, %v) = %q, want %q", tt.res.ExitCode, tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package gui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/jroimartin/gocui"
+)
+
+// errSuspend ends a MainLoop so an external program can have the terminal.
+var errSuspend = errors.New("suspended for an external program")
+
+// handoff is something waiting for the terminal: an external program such
+// as $EDITOR or an interactive shell, or a nested TUI such as server mode.
+type handoff struct {
+	mu   sync.Mutex
+	run  func() error
+	done func(error) // runs on the new gocui instance once run returns
+}
+
+// request queues cmd and ends g's MainLoop so runMainLoop can run it. It
+// may be called from any goroutine.
+func (h *handoff) request(g *gocui.Gui, cmd *exec.Cmd, done func(error)) {
+	h.requestRun(g, func() error {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		return cmd.Run()
+	}, done)
+}
+
+// requestRun is request for Go code that takes over the terminal.
+func (h *handoff) requestRun(g *gocui.Gui, run func() error, done func(error)) {
+	h.mu.Lock()
+	h.run, h.done = run, done
+	h.mu.Unlock()
+	g.Update(func(*gocui.Gui) error { return errSuspend })
+}
+
+func (h *handoff) take() (func() error, func(error)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, done := h.run, h.done
+	h.run, h.done = nil, nil
+	return run, done
+}
+
+// suspendable is a TUI that can give the terminal away: pause stops the
+// work that writes to the screen (streams, tickers), resume creates a new
+// gocui instance with the same layout and keybindings and restarts it.
+type suspendable interface {
+	ui() *gocui.Gui
+	pause()
+	resume() error
+}
+
+// runMainLoop runs s's main loop, handing the terminal to queued programs
+// in between. gocui is fully closed while a program runs, so nothing draws
+// over it, and the screen, selection and log are kept on s.
+func runMainLoop(s suspendable, h *handoff) error {
+	for {
+		err := s.ui().MainLoop()
+		run, done := h.take()
+		if !errors.Is(err, errSuspend) || run == nil {
+			return err
+		}
+		s.pause()
+		s.ui().Close()
+		runErr := run()
+		if err := s.resume(); err != nil {
+			return err
+		}
+		if done != nil {
+			s.ui().Update(func(*gocui.Gui) error {
+				done(runErr)
+				return nil
+			})
+		}
+	}
+}
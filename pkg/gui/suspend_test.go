@@ -0,0 +1,60 @@
+package gui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExternalEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := externalEditor(); got != nil {
+		t.Errorf("externalEditor() with none set = %v, want nil", got)
+	}
+	t.Setenv("EDITOR", "vim")
+	if got := externalEditor(); !reflect.DeepEqual(got, []string{"vim"}) {
+		t.Errorf("externalEditor() = %v, want [vim]", got)
+	}
+	t.Setenv("VISUAL", "code --wait")
+	if got := externalEditor(); !reflect.DeepEqual(got, []string{"code", "--wait"}) {
+		t.Errorf("externalEditor() = %v, want VISUAL first", got)
+	}
+}
+
+func TestReloadEditorAfterExternalEdit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deploy.yml")
+	if err := os.WriteFile(path, []byte("service: app\nimage: app\nservers:\n  - 10.0.0.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gui := &GUI{}
+	if !gui.openEditor(path) {
+		t.Fatal("openEditor failed")
+	}
+	gui.editor.Row, gui.editor.Col, gui.editor.Scroll = 3, 12, 3
+
+	if err := os.WriteFile(path, []byte("service: renamed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gui.reloadEditor(path)
+	if got := gui.editor.Lines; !reflect.DeepEqual(got, []string{"service: renamed", ""}) {
+		t.Errorf("Lines after reload = %q", got)
+	}
+	if gui.editor.Row != 1 || gui.editor.Col != 0 || gui.editor.Scroll != 1 {
+		t.Errorf("cursor after reload = row %d col %d scroll %d, want 1 0 1", gui.editor.Row, gui.editor.Col, gui.editor.Scroll)
+	}
+	if gui.screen != ScreenEditor {
+		t.Errorf("screen after reload = %v, want the editor", gui.screen)
+	}
+}
+
+func TestStopStatusPollingTwice(t *testing.T) {
+	gui := &GUI{statusStopCh: make(chan struct{})}
+	gui.startStatusPolling()
+	gui.stopStatusPolling()
+	gui.stopStatusPolling() // Run's deferred stop after a handoff's pause
+	gui.statusStopCh = make(chan struct{})
+	gui.startStatusPolling()
+	gui.stopStatusPolling()
+}
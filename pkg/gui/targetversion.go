@@ -0,0 +1,116 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jroimartin/gocui"
+	"github.com/shuvro/lazykamal/pkg/docker"
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+// commandOpts is runOpts for a user-started subcommand. When a version is
+// targeted for the selected destination and subcommand honors --version,
+// it is passed along and the target is cleared: it applies to the next such
+// command only.
+func (gui *GUI) commandOpts(subcommand []string) kamal.RunOptions {
+	opts := gui.runOpts()
+	dest := gui.selectedDestination()
+	if dest == nil || !kamal.AcceptsVersion(subcommand) {
+		return opts
+	}
+	key := hostsKey(dest)
+	if version := gui.versionTarget[key]; version != "" {
+		opts.Version = version
+		delete(gui.versionTarget, key)
+		gui.appendLog([]string{yellow("  --version " + version)})
+	}
+	return opts
+}
+
+// targetVersion is the version targeted for the selected destination, if any.
+func (gui *GUI) targetVersion() string {
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return ""
+	}
+	return gui.versionTarget[hostsKey(dest)]
+}
+
+// keyTargetVersion clears the targeted version, or picks one from the app
+// images on the hosts.
+func (gui *GUI) keyTargetVersion(g *gocui.Gui, v *gocui.View) error {
+	if !gui.navigating() {
+		return nil
+	}
+	dest := gui.selectedDestination()
+	if dest == nil {
+		return nil
+	}
+	key := hostsKey(dest)
+	if version := gui.versionTarget[key]; version != "" {
+		delete(gui.versionTarget, key)
+		gui.logInfo("No longer targeting version " + version)
+		return nil
+	}
+	gui.cmdMu.Lock()
+	busy := gui.running
+	gui.cmdMu.Unlock()
+	if busy {
+		gui.logInfo("A command is running; pick a version when it finishes")
+		return nil
+	}
+
+	gui.withAppImages(func(images []kamal.AppImage) {
+		gui.showPicker(versionPicker(images, func(version string) {
+			gui.versionTarget[key] = version
+			gui.logInfo("Targeting version " + version + " for the next app command (V to clear)")
+		}))
+	})
+	return nil
+}
+
+// withAppImages lists the app images on the selected destination's hosts
+// and, when that succeeds, hands them to then on the UI thread.
+func (gui *GUI) withAppImages(then func(images []kamal.AppImage)) {
+	opts := gui.runOpts()
+	var images []kamal.AppImage
+	gui.runQueryThen("App Images", func(stopCh <-chan struct{}) (kamal.Result, error) {
+		res, err := kamal.RunKamalWithStop([]string{"app", "images"}, opts, stopCh)
+		if err == nil {
+			images = kamal.ParseAppImages(res.Combined())
+		}
+		return res, err
+	}, func(stopCh <-chan struct{}, took time.Duration) {
+		gui.g.Update(func(*gocui.Gui) error {
+			then(images)
+			return nil
+		})
+	})
+}
+
+// versionPicker lists images newest first; 'a' targets a version that is
+// not listed, e.g. one already removed from some hosts.
+func versionPicker(images []kamal.AppImage, onPick func(version string)) *listPicker {
+	p := &listPicker{
+		Title:    "Target version",
+		Message:  "Run the next app command (logs, exec, details, …) against this version only.",
+		Validate: kamal.ValidateVersion,
+		OnDone: func(values []string) {
+			if len(values) > 0 {
+				onPick(values[0])
+			}
+		},
+	}
+	for _, img := range images {
+		label := fmt.Sprintf("%s %s", img.Tag, dim(img.Created))
+		if img.Digest != "" {
+			label += dim(" @" + docker.ShortDigest(img.Digest))
+		}
+		if len(img.Hosts) > 1 {
+			label += dim(fmt.Sprintf(" · %d hosts", len(img.Hosts)))
+		}
+		p.Items = append(p.Items, pickerItem{Label: label, Value: img.Tag})
+	}
+	return p
+}
@@ -0,0 +1,54 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shuvro/lazykamal/pkg/kamal"
+)
+
+func TestCommandOptsConsumesTarget(t *testing.T) {
+	gui := &GUI{
+		destinations:  []kamal.DeployDestination{{Name: "staging", ConfigPath: "config/deploy.staging.yml"}},
+		versionTarget: map[string]string{"config/deploy.staging.yml": "a1b2c3"},
+	}
+	if opts := gui.commandOpts([]string{"app", "images"}); opts.Version != "" {
+		t.Errorf("app images got --version %q, want none", opts.Version)
+	}
+	if gui.targetVersion() != "a1b2c3" {
+		t.Fatalf("target cleared by a command that ignores --version")
+	}
+	if opts := gui.commandOpts([]string{"app", "logs"}); opts.Version != "a1b2c3" {
+		t.Errorf("app logs Version = %q, want a1b2c3", opts.Version)
+	}
+	if v := gui.targetVersion(); v != "" {
+		t.Errorf("target after use = %q, want cleared", v)
+	}
+	if opts := gui.commandOpts([]string{"app", "logs"}); opts.Version != "" {
+		t.Errorf("second app logs Version = %q, want none", opts.Version)
+	}
+}
+
+func TestVersionPicker(t *testing.T) {
+	var picked string
+	p := versionPicker([]kamal.AppImage{
+		{Tag: "9f8e7d6c", Created: "2 hours ago", Hosts: []string{"a", "b"}},
+		{Tag: "1a2b3c4d", Created: "3 days ago", Digest: "sha256:0123456789abcdef", Hosts: []string{"a"}},
+	}, func(v string) { picked = v })
+	if len(p.Items) != 2 || !strings.Contains(p.Items[0].Label, "2 hosts") {
+		t.Fatalf("items = %+v", p.Items)
+	}
+	if !strings.Contains(p.Items[1].Label, "@0123456789ab") {
+		t.Errorf("label %q lacks the short digest", p.Items[1].Label)
+	}
+	if err := p.add("-bad"); err == nil {
+		t.Errorf("add(-bad) accepted an invalid tag")
+	}
+	if err := p.add("0ldc0de"); err != nil {
+		t.Fatalf("add(0ldc0de) = %v", err)
+	}
+	p.OnDone(p.selected())
+	if picked != "0ldc0de" {
+		t.Errorf("picked %q, want the typed version", picked)
+	}
+}
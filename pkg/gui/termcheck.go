@@ -0,0 +1,180 @@
+package gui
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fallbackTerm is used when TERM cannot start the TUI: gocui's terminal
+// library has xterm built in, so it works without a terminfo database.
+const fallbackTerm = "xterm-256color"
+
+// builtinTerms are the TERM substrings the terminal library handles without
+// a terminfo entry.
+var builtinTerms = []string{"xterm", "rxvt", "linux", "Eterm", "screen", "cygwin", "st"}
+
+// termEnv is what the terminal self-check reads, so it can be run against
+// synthetic environments.
+type termEnv struct {
+	getenv   func(string) string
+	terminfo func(term string) bool // whether a terminfo entry exists
+}
+
+// termProblem is one finding, with the command that fixes it for good.
+type termProblem struct {
+	problem string
+	fix     string
+}
+
+// termReport is the outcome of checkTerminal: what to print before the TUI
+// starts and what to downgrade for this session.
+type termReport struct {
+	problems []termProblem
+	term     string // TERM to use instead, "" to keep it
+	ascii    bool   // no UTF-8: ASCII icons and frames
+	noColor  bool
+	basic    bool // fewer than 256 colors; noted but not a problem
+}
+
+// checkTerminal decides whether the terminal described by env can run the
+// TUI as is. It has no side effects; PrepareTerminal applies the result.
+func checkTerminal(env termEnv) termReport {
+	var r termReport
+	term := env.getenv("TERM")
+	switch {
+	case term == "":
+		r.term = fallbackTerm
+		r.problems = append(r.problems, termProblem{"TERM is not set", "export TERM=" + fallbackTerm})
+	case term == "dumb":
+		r.term, r.noColor = fallbackTerm, true
+		r.problems = append(r.problems, termProblem{"TERM=dumb cannot draw the interface", "export TERM=" + fallbackTerm})
+	case !env.terminfo(term) && !builtinTerm(term):
+		r.term = fallbackTerm
+		r.problems = append(r.problems, termProblem{
+			fmt.Sprintf("no terminfo entry for TERM=%s", term),
+			fmt.Sprintf("install the terminfo database (e.g. ncurses-base) or export TERM=%s", fallbackTerm),
+		})
+	}
+
+	if locale := effectiveLocale(env.getenv); !utf8Locale(locale) {
+		r.ascii = true
+		what := "no locale is set"
+		if locale != "" {
+			what = fmt.Sprintf("locale %s is not UTF-8", locale)
+		}
+		r.problems = append(r.problems, termProblem{what + ", so icons would garble the layout", "export LANG=C.UTF-8"})
+	}
+
+	if env.getenv("NO_COLOR") != "" {
+		r.noColor = true
+	}
+	if r.term != "" {
+		term = r.term
+	}
+	r.basic = !r.noColor && !colorTerm(term, env.getenv("COLORTERM"))
+	return r
+}
+
+func builtinTerm(term string) bool {
+	for _, name := range builtinTerms {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveLocale is the locale governing character encoding, in the order
+// the C library resolves it.
+func effectiveLocale(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func utf8Locale(locale string) bool {
+	l := strings.ToLower(locale)
+	return strings.Contains(l, "utf-8") || strings.Contains(l, "utf8")
+}
+
+func colorTerm(term, colorterm string) bool {
+	switch strings.ToLower(colorterm) {
+	case "truecolor", "24bit":
+		return true
+	}
+	return strings.Contains(term, "256color") || strings.Contains(term, "direct")
+}
+
+// hasTerminfo looks for term's entry where the terminal library does.
+func hasTerminfo(term string) bool {
+	var dirs []string
+	if d := os.Getenv("TERMINFO"); d != "" {
+		dirs = append(dirs, d)
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	for _, d := range strings.Split(os.Getenv("TERMINFO_DIRS"), ":") {
+		if d == "" {
+			d = "/usr/share/terminfo"
+		}
+		dirs = append(dirs, d)
+	}
+	dirs = append(dirs, "/lib/terminfo", "/usr/share/terminfo")
+	for _, d := range dirs {
+		for _, sub := range []string{term[:1], hex.EncodeToString([]byte(term[:1]))} {
+			if _, err := os.Stat(filepath.Join(d, sub, term)); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// termNotes are the downgrades PrepareTerminal applied, logged once the TUI
+// is up.
+var termNotes []string
+
+// PrepareTerminal checks the terminal before gocui starts, printing each
+// problem with its fix to w and downgrading what it must for this session:
+// a usable TERM, ASCII icons without UTF-8, no colors for TERM=dumb or
+// NO_COLOR. style adds the downgrades asked for on the command line. They
+// are logged again once the TUI is up, since the printed lines are hidden
+// behind it.
+func PrepareTerminal(w io.Writer, style Style) {
+	r := checkTerminal(termEnv{getenv: os.Getenv, terminfo: hasTerminfo})
+	for _, p := range r.problems {
+		fmt.Fprintf(w, "Terminal: %s\n  fix: %s\n", p.problem, p.fix)
+	}
+	termNotes = nil
+	if r.term != "" {
+		os.Setenv("TERM", r.term)
+		termNotes = append(termNotes, "Terminal: using TERM="+r.term+" for this session")
+	}
+	switch {
+	case r.ascii:
+		useASCII()
+		termNotes = append(termNotes, "Terminal: no UTF-8 locale, using ASCII icons (set LANG=C.UTF-8)")
+	case style.ASCII:
+		useASCII()
+		termNotes = append(termNotes, "Terminal: ASCII icons (--ascii)")
+	}
+	switch {
+	case r.noColor:
+		colorOutput = false
+		termNotes = append(termNotes, "Terminal: colors off")
+	case style.NoColor:
+		colorOutput = false
+		termNotes = append(termNotes, "Terminal: colors off (--no-color)")
+	}
+	if r.basic && colorOutput {
+		termNotes = append(termNotes, "Terminal: TERM="+os.Getenv("TERM")+" does not advertise 256 colors; if highlights look off, export TERM="+fallbackTerm)
+	}
+}
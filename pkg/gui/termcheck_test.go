@@ -0,0 +1,64 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+)
+
+func fakeTermEnv(vars map[string]string, terminfo ...string) termEnv {
+	return termEnv{
+		getenv: func(k string) string { return vars[k] },
+		terminfo: func(term string) bool {
+			for _, t := range terminfo {
+				if t == term {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+func TestCheckTerminal(t *testing.T) {
+	tests := []struct {
+		name     string
+		vars     map[string]string
+		terminfo []string
+		problems int
+		term     string
+		ascii    bool
+		noColor  bool
+		basic    bool
+	}{
+		{"healthy", map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"}, nil, 0, "", false, false, false},
+		{"terminfo entry", map[string]string{"TERM": "foot", "LC_ALL": "C.utf8", "COLORTERM": "truecolor"}, []string{"foot"}, 0, "", false, false, false},
+		{"no TERM", map[string]string{"LANG": "C.UTF-8"}, nil, 1, fallbackTerm, false, false, false},
+		{"dumb", map[string]string{"TERM": "dumb", "LANG": "C.UTF-8"}, nil, 1, fallbackTerm, false, true, false},
+		{"unknown TERM", map[string]string{"TERM": "foot", "LANG": "C.UTF-8"}, nil, 1, fallbackTerm, false, false, false},
+		{"no locale", map[string]string{"TERM": "xterm-256color"}, nil, 1, "", true, false, false},
+		{"LC_ALL wins", map[string]string{"TERM": "xterm-256color", "LC_ALL": "POSIX", "LANG": "en_US.UTF-8"}, nil, 1, "", true, false, false},
+		{"8 colors", map[string]string{"TERM": "screen", "LANG": "C.UTF-8"}, nil, 0, "", false, false, true},
+		{"NO_COLOR", map[string]string{"TERM": "screen", "LANG": "C.UTF-8", "NO_COLOR": "1"}, nil, 0, "", false, true, false},
+	}
+	for _, tt := range tests {
+		r := checkTerminal(fakeTermEnv(tt.vars, tt.terminfo...))
+		if len(r.problems) != tt.problems || r.term != tt.term || r.ascii != tt.ascii || r.noColor != tt.noColor || r.basic != tt.basic {
+			t.Errorf("%s: checkTerminal() = %+v", tt.name, r)
+		}
+	}
+}
+
+func TestCheckTerminalFixes(t *testing.T) {
+	r := checkTerminal(fakeTermEnv(map[string]string{"TERM": "foot", "LANG": "C"}))
+	var fixes []string
+	for _, p := range r.problems {
+		fixes = append(fixes, p.fix)
+	}
+	got := strings.Join(fixes, "\n")
+	if !strings.Contains(got, "export TERM="+fallbackTerm) || !strings.Contains(got, "export LANG=C.UTF-8") {
+		t.Errorf("fixes = %q", got)
+	}
+	if !strings.Contains(r.problems[1].problem, "locale C is not UTF-8") {
+		t.Errorf("locale problem = %q", r.problems[1].problem)
+	}
+}
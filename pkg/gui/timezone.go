@@ -0,0 +1,93 @@
+package gui
+
+import (
+	"fmt"
+	"time"
+)
+
+// displayZone is the zone log timestamps are shown in. It only changes how
+// lines are rendered; the log keeps the instant each line arrived.
+type displayZone int
+
+const (
+	zoneLocal  displayZone = iota // this machine's zone
+	zoneUTC                       // UTC, as servers usually log
+	zoneServer                    // the server's clock in UTC, corrected by the measured skew
+)
+
+func (z displayZone) String() string {
+	switch z {
+	case zoneUTC:
+		return "UTC"
+	case zoneServer:
+		return "server"
+	}
+	return "local"
+}
+
+// next cycles local → UTC → server → local.
+func (z displayZone) next() displayZone {
+	return (z + 1) % 3
+}
+
+// formatStamp formats t for the log in zone. skew is how far the server
+// clock is ahead of the local one; it only matters for zoneServer.
+func formatStamp(t time.Time, zone displayZone, skew time.Duration) string {
+	switch zone {
+	case zoneUTC:
+		return t.UTC().Format("15:04:05")
+	case zoneServer:
+		return t.Add(skew).UTC().Format("15:04:05")
+	}
+	return t.Local().Format("15:04:05")
+}
+
+// zoneLabel names zone for the Output title, e.g. "local CET", "UTC" or
+// "server UTC +2m5s".
+func zoneLabel(zone displayZone, skew time.Duration) string {
+	switch zone {
+	case zoneUTC:
+		return "UTC"
+	case zoneServer:
+		if skew == 0 {
+			return "server UTC"
+		}
+		sign := "+"
+		if skew < 0 {
+			sign, skew = "-", -skew
+		}
+		return fmt.Sprintf("server UTC %s%s", sign, formatDuration(skew))
+	}
+	name, _ := time.Now().Zone()
+	return "local " + name
+}
+
+// logEntry is one Output line and when it was logged. The timestamp is
+// formatted at render time so the display zone can change afterwards.
+type logEntry struct {
+	at      time.Time
+	text    string
+	cleared bool   // the "cleared at" marker
+	dest    string // hostsKey of the destination it came from, "" for general lines
+}
+
+func newLogEntry(text string) logEntry {
+	return logEntry{at: time.Now(), text: text}
+}
+
+func (e logEntry) render(zone displayZone, skew time.Duration) string {
+	stamp := formatStamp(e.at, zone, skew)
+	if e.cleared {
+		return clearedMarker(stamp)
+	}
+	return dim(stamp) + " " + e.text
+}
+
+// renderEntries renders entries for display in zone.
+func renderEntries(entries []logEntry, zone displayZone, skew time.Duration) []string {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = e.render(zone, skew)
+	}
+	return lines
+}
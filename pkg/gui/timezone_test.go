@@ -0,0 +1,59 @@
+package gui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatStamp(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	at := time.Date(2024, 5, 1, 10, 0, 0, 0, cet) // 09:00:00 UTC
+	tests := []struct {
+		zone displayZone
+		skew time.Duration
+		want string
+	}{
+		{zoneUTC, 0, "09:00:00"},
+		{zoneUTC, time.Minute, "09:00:00"},
+		{zoneServer, 0, "09:00:00"},
+		{zoneServer, 2*time.Minute + 5*time.Second, "09:02:05"},
+		{zoneServer, -90 * time.Second, "08:58:30"},
+		{zoneLocal, time.Minute, at.Local().Format("15:04:05")},
+	}
+	for _, tt := range tests {
+		if got := formatStamp(at, tt.zone, tt.skew); got != tt.want {
+			t.Errorf("formatStamp(%s, %s) = %q, want %q", tt.zone, tt.skew, got, tt.want)
+		}
+	}
+}
+
+func TestZoneCycleAndLabel(t *testing.T) {
+	z := zoneLocal
+	var seen []string
+	for i := 0; i < 4; i++ {
+		seen = append(seen, z.String())
+		z = z.next()
+	}
+	if got := strings.Join(seen, ","); got != "local,UTC,server,local" {
+		t.Errorf("zone cycle = %s", got)
+	}
+	if got := zoneLabel(zoneServer, -125*time.Second); got != "server UTC -2m5s" {
+		t.Errorf("zoneLabel(server) = %q", got)
+	}
+	if got := zoneLabel(zoneLocal, 0); !strings.HasPrefix(got, "local ") {
+		t.Errorf("zoneLabel(local) = %q", got)
+	}
+}
+
+func TestLogEntryRender(t *testing.T) {
+	at := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	e := logEntry{at: at, text: "hello"}
+	if got := e.render(zoneUTC, 0); got != dim("09:00:00")+" hello" {
+		t.Errorf("render() = %q", got)
+	}
+	marker := logEntry{at: at, cleared: true}
+	if got := marker.render(zoneServer, time.Minute); !strings.Contains(got, "cleared at 09:01:00") {
+		t.Errorf("render(cleared) = %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Package hooks runs project-defined shell commands around lazykamal
+// actions, such as smoke tests after a deploy.
+package hooks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout applies to a hook command that does not set its own.
+const DefaultTimeout = 60 * time.Second
+
+// Result is the outcome of one hook command.
+type Result struct {
+	Command   string
+	ExitCode  int
+	Duration  time.Duration
+	TimedOut  bool
+	Cancelled bool
+	Err       error // the command could not be started
+}
+
+// Passed reports whether the command exited 0 within its timeout.
+func (r Result) Passed() bool {
+	return r.Err == nil && !r.TimedOut && !r.Cancelled && r.ExitCode == 0
+}
+
+// Reason describes a failure, e.g. "exit 1" or "timed out after 30s".
+func (r Result) Reason() string {
+	switch {
+	case r.Err != nil:
+		return r.Err.Error()
+	case r.TimedOut:
+		return "timed out after " + r.Duration.Round(time.Second).String()
+	case r.Cancelled:
+		return "cancelled"
+	case r.ExitCode != 0:
+		return fmt.Sprintf("exit %d", r.ExitCode)
+	}
+	return "ok"
+}
+
+// Run executes command with the platform shell in dir, streaming stdout and
+// stderr line by line to onLine. env is added to the current environment.
+// The command is killed when timeout elapses or stopCh is closed.
+func Run(dir, command string, timeout time.Duration, env []string, onLine func(string), stopCh <-chan struct{}) Result {
+	return run(dir, command, timeout, env, nil, onLine, true, stopCh)
+}
+
+// run is Run with stdin. Without mergeStderr, stderr is discarded and only
+// stdout reaches onLine.
+func run(dir, command string, timeout time.Duration, env []string, stdin io.Reader, onLine func(string), mergeStderr bool, stopCh <-chan struct{}) Result {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cancelled := false
+	var mu sync.Mutex
+	if stopCh != nil {
+		go func() {
+			select {
+			case <-stopCh:
+				mu.Lock()
+				cancelled = true
+				mu.Unlock()
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), env...)
+	killGroup(cmd)
+	// Children such as curl may keep the pipes open after the shell is
+	// killed; don't wait for them forever.
+	cmd.WaitDelay = 2 * time.Second
+	pr, pw := io.Pipe()
+	cmd.Stdin = stdin
+	cmd.Stdout = pw
+	if mergeStderr {
+		cmd.Stderr = pw
+	}
+
+	res := Result{Command: command}
+	start := time.Now()
+	if err := cmd.Start(); err != nil {
+		res.Err = err
+		return res
+	}
+	done := make(chan struct{})
+	go func() {
+		sc := bufio.NewScanner(pr)
+		for sc.Scan() {
+			if onLine != nil {
+				onLine(sc.Text())
+			}
+		}
+		io.Copy(io.Discard, pr)
+		close(done)
+	}()
+	err := cmd.Wait()
+	pw.Close()
+	<-done
+	res.Duration = time.Since(start)
+
+	mu.Lock()
+	res.Cancelled = cancelled
+	mu.Unlock()
+	if ctx.Err() == context.DeadlineExceeded {
+		res.TimedOut = true
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		res.ExitCode = exitErr.ExitCode()
+	} else if err != nil && !res.TimedOut && !res.Cancelled {
+		res.Err = err
+	}
+	return res
+}
+
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// Verdict summarizes a run of several hook commands.
+type Verdict struct {
+	Results []Result
+}
+
+// Passed reports whether every command passed.
+func (v Verdict) Passed() bool {
+	for _, r := range v.Results {
+		if !r.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Summary returns e.g. "2/2 passed" or "1/2 passed; scripts/smoke.sh: exit 1".
+func (v Verdict) Summary() string {
+	passed := 0
+	var failures []string
+	for _, r := range v.Results {
+		if r.Passed() {
+			passed++
+		} else {
+			failures = append(failures, r.Command+": "+r.Reason())
+		}
+	}
+	s := fmt.Sprintf("%d/%d passed", passed, len(v.Results))
+	if len(failures) > 0 {
+		s += "; " + strings.Join(failures, "; ")
+	}
+	return s
+}
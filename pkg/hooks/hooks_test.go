@@ -0,0 +1,87 @@
+package hooks
+
+import (
+	"os/exec"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("hook tests use POSIX sh syntax")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestRunStreamsOutputAndEnv(t *testing.T) {
+	requireShell(t)
+	var mu sync.Mutex
+	var lines []string
+	onLine := func(l string) {
+		mu.Lock()
+		lines = append(lines, l)
+		mu.Unlock()
+	}
+	res := Run(t.TempDir(), `echo "dest=$LAZYKAMAL_DESTINATION"; echo err >&2`, time.Second, []string{"LAZYKAMAL_DESTINATION=staging"}, onLine, nil)
+	if !res.Passed() {
+		t.Fatalf("Run() = %+v, want passed", res)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	got := strings.Join(lines, "|")
+	if !strings.Contains(got, "dest=staging") || !strings.Contains(got, "err") {
+		t.Errorf("streamed lines = %q, want stdout and stderr", got)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	requireShell(t)
+	dir := t.TempDir()
+
+	res := Run(dir, "exit 3", time.Second, nil, nil, nil)
+	if res.Passed() || res.ExitCode != 3 || res.Reason() != "exit 3" {
+		t.Errorf("exit 3: %+v reason %q", res, res.Reason())
+	}
+
+	res = Run(dir, "sleep 5", 100*time.Millisecond, nil, nil, nil)
+	if res.Passed() || !res.TimedOut || !strings.HasPrefix(res.Reason(), "timed out") {
+		t.Errorf("timeout: %+v reason %q", res, res.Reason())
+	}
+	if res.Duration > 4*time.Second {
+		t.Errorf("timeout took %s, want the command killed", res.Duration)
+	}
+
+	stop := make(chan struct{})
+	time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+	res = Run(dir, "sleep 5", time.Minute, nil, nil, stop)
+	if res.Passed() || !res.Cancelled || res.TimedOut {
+		t.Errorf("cancel: %+v", res)
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	v := Verdict{Results: []Result{
+		{Command: "scripts/smoke.sh"},
+		{Command: "curl -f https://example.com/up", ExitCode: 22},
+	}}
+	if v.Passed() {
+		t.Error("Passed() = true, want false")
+	}
+	if got, want := v.Summary(), "1/2 passed; curl -f https://example.com/up: exit 22"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+	ok := Verdict{Results: []Result{{Command: "true"}}}
+	if !ok.Passed() || ok.Summary() != "1/1 passed" {
+		t.Errorf("all passed: %v %q", ok.Passed(), ok.Summary())
+	}
+	if !reflect.DeepEqual(Verdict{}.Passed(), true) {
+		t.Error("empty verdict should pass")
+	}
+}
@@ -0,0 +1,65 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// Plugin hook points: the keys under plugins: in .lazykamal.yml.
+const (
+	OnDeploySuccess = "on_deploy_success"
+	OnCommandError  = "on_command_error"
+	StatusExtra     = "status_extra"
+)
+
+// PayloadSchema is the version of Payload. It changes only when a field is
+// removed or changes meaning; new fields may be added at any time.
+const PayloadSchema = 1
+
+// PluginTimeout applies to a plugin command that does not set its own.
+// Plugins run beside the TUI, so they get less time than hooks.
+const PluginTimeout = 10 * time.Second
+
+// Payload is the JSON document a plugin command reads on stdin. Fields
+// that do not apply to the hook point are left out.
+type Payload struct {
+	Schema          int               `json:"schema"`
+	Hook            string            `json:"hook"`
+	Time            time.Time         `json:"time"`
+	Project         string            `json:"project"` // project root, also the working directory
+	App             string            `json:"app,omitempty"`
+	Destination     string            `json:"destination,omitempty"`
+	Command         string            `json:"command,omitempty"`
+	Version         string            `json:"version,omitempty"`
+	PreviousVersion string            `json:"previous_version,omitempty"`
+	ExitCode        *int              `json:"exit_code,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	DurationMs      int64             `json:"duration_ms,omitempty"`
+	Versions        map[string]string `json:"versions,omitempty"` // host -> running version
+}
+
+// NewPayload starts a payload for hook with the schema version and time set.
+func NewPayload(hook, project string) Payload {
+	return Payload{Schema: PayloadSchema, Hook: hook, Time: time.Now().UTC(), Project: project}
+}
+
+// RunPlugin runs a plugin command in dir with p as JSON on stdin and returns
+// up to maxLines lines of its stdout. Stderr is discarded, and stdout is
+// read to the end so a chatty plugin never blocks on a full pipe.
+func RunPlugin(dir, command string, timeout time.Duration, p Payload, maxLines int) ([]string, Result) {
+	if timeout <= 0 {
+		timeout = PluginTimeout
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, Result{Command: command, Err: err}
+	}
+	var lines []string
+	res := run(dir, command, timeout, nil, bytes.NewReader(append(data, '\n')), func(line string) {
+		if len(lines) < maxLines {
+			lines = append(lines, line)
+		}
+	}, false, nil)
+	return lines, res
+}
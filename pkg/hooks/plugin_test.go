@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPayloadSchema(t *testing.T) {
+	exit := 1
+	p := Payload{
+		Schema:      PayloadSchema,
+		Hook:        OnCommandError,
+		Time:        time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Project:     "/src/myapp",
+		App:         "myapp (staging)",
+		Destination: "staging",
+		Command:     "App Restart",
+		ExitCode:    &exit,
+		Error:       "ERROR (SSHKit::Runner::ExecuteError)",
+		DurationMs:  4200,
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schema":1,"hook":"on_command_error","time":"2026-03-01T12:00:00Z","project":"/src/myapp","app":"myapp (staging)","destination":"staging","command":"App Restart","exit_code":1,"error":"ERROR (SSHKit::Runner::ExecuteError)","duration_ms":4200}`
+	if string(data) != want {
+		t.Errorf("payload =\n%s\nwant\n%s", data, want)
+	}
+
+	status := NewPayload(StatusExtra, "/src/myapp")
+	status.Versions = map[string]string{"10.0.0.1": "abc123"}
+	data, _ = json.Marshal(status)
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"schema", "hook", "time", "project", "versions"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("status_extra payload missing %q: %s", key, data)
+		}
+	}
+	for _, key := range []string{"exit_code", "error", "command", "version"} {
+		if _, ok := decoded[key]; ok {
+			t.Errorf("status_extra payload has unset %q: %s", key, data)
+		}
+	}
+}
+
+func TestRunPlugin(t *testing.T) {
+	requireShell(t)
+	dir := t.TempDir()
+	p := NewPayload(StatusExtra, dir)
+	p.Destination = "staging"
+
+	lines, res := RunPlugin(dir, `cat; echo; echo second; echo third; echo hidden >&2`, time.Second, p, 2)
+	if !res.Passed() {
+		t.Fatalf("RunPlugin() = %+v, want passed", res)
+	}
+	if len(lines) != 2 || lines[1] != "" {
+		t.Fatalf("lines = %q, want the payload and an empty line", lines)
+	}
+	var got Payload
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil || got.Destination != "staging" || got.Schema != PayloadSchema {
+		t.Errorf("plugin read %q (%v), want the payload", lines[0], err)
+	}
+
+	lines, res = RunPlugin(dir, `seq 1 100000`, time.Second, p, 3)
+	if !res.Passed() || strings.Join(lines, ",") != "1,2,3" {
+		t.Errorf("long output: %+v lines %q", res, lines)
+	}
+
+	_, res = RunPlugin(dir, "exit 4", time.Second, p, 5)
+	if res.Passed() || res.Reason() != "exit 4" {
+		t.Errorf("failing plugin: %+v", res)
+	}
+
+	start := time.Now()
+	_, res = RunPlugin(dir, "sleep 5", 100*time.Millisecond, p, 5)
+	if !res.TimedOut || time.Since(start) > 4*time.Second {
+		t.Errorf("slow plugin: %+v after %s, want it killed", res, time.Since(start))
+	}
+
+	_, res = RunPlugin(dir, "true", time.Second, p, 5)
+	if !res.Passed() {
+		t.Errorf("plugin that ignores stdin: %+v", res)
+	}
+}
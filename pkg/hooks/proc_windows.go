@@ -0,0 +1,8 @@
+//go:build windows
+
+package hooks
+
+import "os/exec"
+
+// killGroup is a no-op on Windows; cmd.WaitDelay bounds orphaned children.
+func killGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,46 @@
+package kamal
+
+import "sort"
+
+// Accessories returns the sorted accessory names configured for the
+// destination, merging the overlay with the base config the way Kamal does.
+// An empty result means every `kamal accessory ... all` would fail.
+func (d *DeployDestination) Accessories() []string {
+	seen := map[string]bool{}
+	for _, cfg := range []map[string]interface{}{d.baseConfig(), d.Config} {
+		for _, name := range accessoryNames(cfg["accessories"]) {
+			seen[name] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// accessoryNames handles the usual name -> settings map (whether the
+// accessory is placed with host, hosts or roles) and a list of names or
+// single-key name maps.
+func accessoryNames(v interface{}) []string {
+	var names []string
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name := range v {
+			names = append(names, name)
+		}
+	case []interface{}:
+		for _, item := range v {
+			switch item := item.(type) {
+			case string:
+				names = append(names, item)
+			case map[string]interface{}:
+				for name := range item {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,70 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestAccessories(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{
+			name: "none",
+			yaml: "service: myapp\nservers: [10.0.0.1]\n",
+			want: []string{},
+		},
+		{
+			name: "host keyed",
+			yaml: "accessories:\n  redis:\n    image: redis:7\n    host: 10.0.0.1\n  db:\n    image: mysql:8\n    hosts: [10.0.0.2]\n",
+			want: []string{"db", "redis"},
+		},
+		{
+			name: "role keyed",
+			yaml: "accessories:\n  search:\n    image: opensearch:2\n    roles:\n      - web\n",
+			want: []string{"search"},
+		},
+		{
+			name: "list form",
+			yaml: "accessories:\n  - redis\n  - db:\n      image: postgres:16\n",
+			want: []string{"db", "redis"},
+		},
+		{
+			name: "empty section",
+			yaml: "accessories:\n",
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		var cfg map[string]interface{}
+		if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+			t.Fatalf("%s: yaml: %v", tt.name, err)
+		}
+		d := DeployDestination{Config: cfg}
+		if got := d.Accessories(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Accessories() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAccessoriesMergesBaseConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "deploy.yml")
+	if err := os.WriteFile(base, []byte("accessories:\n  redis:\n    image: redis:7\n    roles: [web]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := DeployDestination{
+		Name:     "staging",
+		BasePath: base,
+		Config:   map[string]interface{}{"accessories": map[string]interface{}{"db": map[string]interface{}{"image": "postgres:16"}}},
+	}
+	if got, want := d.Accessories(), []string{"db", "redis"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Accessories() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package kamal
+
+import (
+	"errors"
+	"sync"
+)
+
+// AccessoryJobs is how many per-accessory commands RunAccessoryEach runs at
+// once.
+const AccessoryJobs = 4
+
+// AccessoryResult is one accessory's part of RunAccessoryEach.
+type AccessoryResult struct {
+	Name   string
+	Result Result
+	Err    error
+}
+
+// Failed reports whether the accessory's command did not run or exited
+// non-zero.
+func (r AccessoryResult) Failed() bool {
+	return r.Err != nil || r.Result.ExitCode != 0
+}
+
+// RunAccessoryEach runs `kamal accessory ACTION NAME` for each name, at most
+// jobs at a time, instead of one `ACTION all` that walks them one after
+// another. Results are in the order of names. Once stopCh is closed the
+// running commands are stopped and the rest are not started.
+func RunAccessoryEach(action string, names []string, opts RunOptions, jobs int, stopCh <-chan struct{}) []AccessoryResult {
+	if jobs < 1 {
+		jobs = 1
+	}
+	results := make([]AccessoryResult, len(names))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		results[i].Name = name
+		if !acquire(sem, stopCh) {
+			results[i].Err = errors.New("command cancelled")
+			continue
+		}
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i].Result, results[i].Err = RunKamalWithStop([]string{"accessory", action, name}, opts, stopCh)
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
+
+// acquire takes a slot in sem, or reports false once stopCh is closed.
+func acquire(sem chan struct{}, stopCh <-chan struct{}) bool {
+	select {
+	case <-stopCh:
+		return false
+	default:
+	}
+	select {
+	case <-stopCh:
+		return false
+	case sem <- struct{}{}:
+		return true
+	}
+}
@@ -0,0 +1,64 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRunAccessoryEach(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("RUNNING_DIR", filepath.Join(dir, "running"))
+	t.Setenv("PEAK_LOG", filepath.Join(dir, "peak"))
+	fakeKamal(t, `mkdir -p "$RUNNING_DIR"
+touch "$RUNNING_DIR/$3"
+ls "$RUNNING_DIR" | wc -l >> "$PEAK_LOG"
+sleep 0.2
+rm "$RUNNING_DIR/$3"
+echo "$2 of $3"
+if [ "$3" = db ]; then echo "ERROR boom" >&2; exit 3; fi
+`)
+	names := []string{"cache", "db", "queue", "redis", "search"}
+	results := RunAccessoryEach("details", names, RunOptions{}, 2, nil)
+	if len(results) != len(names) {
+		t.Fatalf("got %d results", len(results))
+	}
+	for i, r := range results {
+		if r.Name != names[i] || !strings.Contains(r.Result.Stdout, "details of "+names[i]) {
+			t.Errorf("result %d = %+v", i, r)
+		}
+		if r.Failed() != (r.Name == "db") {
+			t.Errorf("%s: Failed() = %v", r.Name, r.Failed())
+		}
+	}
+	if results[1].Result.ExitCode != 3 || !strings.Contains(results[1].Result.Stderr, "boom") {
+		t.Errorf("db result = %+v", results[1])
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "peak"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	peak := 0
+	for _, f := range strings.Fields(string(data)) {
+		if n, _ := strconv.Atoi(f); n > peak {
+			peak = n
+		}
+	}
+	if peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+}
+
+func TestRunAccessoryEachStopped(t *testing.T) {
+	fakeKamal(t, "echo ran\n")
+	stopCh := make(chan struct{})
+	close(stopCh)
+	for _, r := range RunAccessoryEach("logs", []string{"db", "redis"}, RunOptions{}, 4, stopCh) {
+		if r.Err == nil || r.Result.Stdout != "" {
+			t.Errorf("%s ran after stop: %+v", r.Name, r)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package kamal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// AccessoryHealth is one accessory's state across its hosts, from `kamal
+// accessory details`.
+type AccessoryHealth struct {
+	Name string
+	// Up and Down are the hosts where the accessory's container is up, or
+	// is stopped or missing. A host is "" when kamal printed no headers.
+	Up   []string
+	Down []string
+	// Status is the docker status of a listed container, an unhealthy one
+	// when there is one, e.g. "Up 2 days (healthy)".
+	Status string
+}
+
+// Accessory health states, from AccessoryHealth.State.
+const (
+	AccessoryRunning  = "running"
+	AccessoryDegraded = "degraded" // up on some hosts only, or unhealthy
+	AccessoryDown     = "down"
+	AccessoryUnknown  = "unknown" // not in the output at all
+)
+
+// State sums h up as one of the Accessory* states.
+func (h AccessoryHealth) State() string {
+	switch {
+	case len(h.Up) > 0 && (len(h.Down) > 0 || strings.Contains(h.Status, "(unhealthy)")):
+		return AccessoryDegraded
+	case len(h.Up) > 0:
+		return AccessoryRunning
+	case len(h.Down) > 0:
+		return AccessoryDown
+	}
+	return AccessoryUnknown
+}
+
+// accessoryHostHeader is the line kamal prints before each host's table,
+// "Accessory db Host: 10.0.0.5".
+var accessoryHostHeader = regexp.MustCompile(`^Accessory (\S+) Host: (\S+)$`)
+
+// ParseAccessoryDetails parses `kamal accessory details` output (docker ps
+// tables whose NAMES column is <service>-<accessory>) into the health of
+// each of accessories, in their order. A host whose table lists no
+// container counts as down; containers are matched by name, so tables
+// without headers (--quiet) parse too.
+func ParseAccessoryDetails(output, service string, accessories []string) []AccessoryHealth {
+	byName := map[string]*AccessoryHealth{}
+	out := make([]AccessoryHealth, len(accessories))
+	for i, a := range accessories {
+		out[i].Name = a
+		byName[a] = &out[i]
+	}
+	// The header's accessory and host, until a container of it is seen.
+	var pending *AccessoryHealth
+	var host string
+	flush := func() {
+		if pending != nil {
+			pending.Down = append(pending.Down, host)
+			pending = nil
+		}
+	}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isSSHKitLogLine(line) || strings.HasPrefix(line, "CONTAINER ID") {
+			continue
+		}
+		if m := accessoryHostHeader.FindStringSubmatch(line); m != nil {
+			flush()
+			pending, host = byName[m[1]], m[2]
+			continue
+		}
+		cols := columnSep.Split(line, -1)
+		if len(cols) < 3 {
+			continue
+		}
+		h := byName[accessoryForContainer(cols[len(cols)-1], service, accessories)]
+		if h == nil {
+			continue
+		}
+		if h == pending {
+			pending = nil
+		}
+		status := ""
+		for _, col := range cols[2:] {
+			if strings.HasPrefix(col, "Up ") || strings.HasPrefix(col, "Exited") || strings.HasPrefix(col, "Created") || strings.HasPrefix(col, "Restarting") {
+				status = col
+				break
+			}
+		}
+		if h.Status == "" || strings.Contains(status, "(unhealthy)") {
+			h.Status = status
+		}
+		if strings.HasPrefix(status, "Up ") {
+			h.Up = append(h.Up, host)
+		} else {
+			h.Down = append(h.Down, host)
+		}
+	}
+	flush()
+	return out
+}
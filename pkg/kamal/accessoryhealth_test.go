@@ -0,0 +1,53 @@
+package kamal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAccessoryDetails(t *testing.T) {
+	got := ParseAccessoryDetails(accessoryDetailsFixture, "myapp", []string{"cache", "db", "redis-cache", "search"})
+	want := []AccessoryHealth{
+		{Name: "cache"},
+		{Name: "db", Up: []string{"10.0.0.5"}, Status: "Up 2 days"},
+		{Name: "redis-cache", Down: []string{"10.0.0.5"}, Status: "Exited (0) 3 hours ago"},
+		{Name: "search", Down: []string{"10.0.0.5"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAccessoryDetails() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+const accessoryDetailsMultiHost = `Accessory db Host: 10.0.0.5
+CONTAINER ID   IMAGE         COMMAND                  CREATED      STATUS                     PORTS      NAMES
+4f1e2d3c4b5a   postgres:16   "docker-entrypoint.s…"   2 days ago   Up 2 days (unhealthy)      5432/tcp   myapp-db
+
+Accessory redis Host: 10.0.0.5
+CONTAINER ID   IMAGE     COMMAND                  CREATED      STATUS      PORTS      NAMES
+9a8b7c6d5e4f   redis:7   "docker-entrypoint.s…"   2 days ago   Up 2 days   6379/tcp   myapp-redis
+
+Accessory redis Host: 10.0.0.6
+CONTAINER ID   IMAGE     COMMAND   CREATED   STATUS    PORTS     NAMES
+`
+
+func TestAccessoryHealthState(t *testing.T) {
+	health := ParseAccessoryDetails(accessoryDetailsMultiHost, "myapp", []string{"db", "redis", "search"})
+	got := map[string]string{}
+	for _, h := range health {
+		got[h.Name] = h.State()
+	}
+	want := map[string]string{"db": AccessoryDegraded, "redis": AccessoryDegraded, "search": AccessoryUnknown}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("states = %v, want %v", got, want)
+	}
+	if down := health[1].Down; !reflect.DeepEqual(down, []string{"10.0.0.6"}) {
+		t.Errorf("redis down on %v, want [10.0.0.6]", down)
+	}
+
+	// --quiet drops the headers; containers are still matched by name.
+	quiet := `CONTAINER ID   IMAGE     COMMAND                  CREATED      STATUS      PORTS      NAMES
+9a8b7c6d5e4f   redis:7   "docker-entrypoint.s…"   2 days ago   Up 2 days   6379/tcp   myapp-redis`
+	if s := ParseAccessoryDetails(quiet, "myapp", []string{"redis"})[0].State(); s != AccessoryRunning {
+		t.Errorf("quiet redis = %s, want %s", s, AccessoryRunning)
+	}
+}
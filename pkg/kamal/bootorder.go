@@ -0,0 +1,156 @@
+package kamal
+
+import (
+	"sort"
+	"strings"
+)
+
+// AppNode is the app itself in an accessory dependency graph.
+const AppNode = "app"
+
+// dataStoreWords mark an accessory as a data store by name (db,
+// postgres, redis-cache, …). Without depends_on, data stores boot first.
+var dataStoreWords = map[string]bool{
+	"db": true, "database": true, "postgres": true, "postgresql": true, "pg": true,
+	"mysql": true, "mariadb": true, "mongo": true, "mongodb": true,
+	"redis": true, "valkey": true, "memcached": true, "cache": true,
+}
+
+func isDataStore(name string) bool {
+	for _, w := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool { return r == '-' || r == '_' }) {
+		if dataStoreWords[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessoryDependencies builds the boot dependency graph for a destination's
+// accessories. declared is depends_on from .lazykamal.yml; when it is empty
+// the convention applies: other accessories wait for the data stores. The
+// app depends on every accessory unless declared lists it. Names in declared
+// that are not configured accessories are dropped and returned as unknown.
+func AccessoryDependencies(accessories []string, declared map[string][]string) (deps map[string][]string, unknown []string) {
+	configured := nameSet(accessories)
+	deps = map[string][]string{AppNode: append([]string{}, accessories...)}
+	if len(declared) == 0 {
+		var stores []string
+		for _, a := range accessories {
+			if isDataStore(a) {
+				stores = append(stores, a)
+			}
+		}
+		for _, a := range accessories {
+			if !isDataStore(a) && len(stores) > 0 {
+				deps[a] = stores
+			}
+		}
+		return deps, nil
+	}
+	seen := map[string]bool{}
+	addUnknown := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			unknown = append(unknown, name)
+		}
+	}
+	for node, on := range declared {
+		if node != AppNode && !configured[node] {
+			addUnknown(node)
+			continue
+		}
+		var known []string
+		for _, d := range on {
+			if configured[d] {
+				known = append(known, d)
+			} else {
+				addUnknown(d)
+			}
+		}
+		deps[node] = known
+	}
+	sort.Strings(unknown)
+	return deps, unknown
+}
+
+// CycleError reports a dependency cycle, e.g. db -> search -> db.
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+// BootOrder returns targets and everything they depend on, each after its
+// dependencies. Independent nodes are ordered by name so the result is
+// stable. A cycle yields a *CycleError.
+func BootOrder(deps map[string][]string, targets []string) ([]string, error) {
+	const (
+		visiting = iota + 1
+		done
+	)
+	state := map[string]int{}
+	var order, path []string
+	var visit func(n string) error
+	visit = func(n string) error {
+		switch state[n] {
+		case done:
+			return nil
+		case visiting:
+			for i, p := range path {
+				if p == n {
+					return &CycleError{Cycle: append(append([]string{}, path[i:]...), n)}
+				}
+			}
+		}
+		state[n] = visiting
+		path = append(path, n)
+		next := append([]string{}, deps[n]...)
+		sort.Strings(next)
+		for _, d := range next {
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[n] = done
+		order = append(order, n)
+		return nil
+	}
+	sorted := append([]string{}, targets...)
+	sort.Strings(sorted)
+	for _, t := range sorted {
+		if err := visit(t); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// RunningAccessories parses `kamal accessory details all` output into the
+// accessories that are up on every host listing them.
+func RunningAccessories(output, service string, accessories []string) map[string]bool {
+	running := map[string]bool{}
+	for _, h := range ParseAccessoryDetails(output, service, accessories) {
+		if len(h.Up) > 0 && len(h.Down) == 0 {
+			running[h.Name] = true
+		}
+	}
+	return running
+}
+
+// accessoryForContainer maps a container name to its accessory, preferring
+// the exact <service>-<accessory> name and else the longest matching suffix.
+func accessoryForContainer(container, service string, accessories []string) string {
+	best := ""
+	for _, a := range accessories {
+		if service != "" && container == service+"-"+a {
+			return a
+		}
+		if strings.HasSuffix(container, "-"+a) && len(a) > len(best) {
+			best = a
+		}
+	}
+	return best
+}
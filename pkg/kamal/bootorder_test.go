@@ -0,0 +1,129 @@
+package kamal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBootOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		deps    map[string][]string
+		targets []string
+		want    []string
+		cycle   []string
+	}{
+		{
+			name:    "no dependencies",
+			deps:    map[string][]string{},
+			targets: []string{AppNode},
+			want:    []string{AppNode},
+		},
+		{
+			name:    "dependencies before dependents",
+			deps:    map[string][]string{AppNode: {"search", "db", "redis"}, "search": {"db"}},
+			targets: []string{AppNode},
+			want:    []string{"db", "redis", "search", AppNode},
+		},
+		{
+			name:    "shared dependency booted once",
+			deps:    map[string][]string{AppNode: {"worker", "search"}, "worker": {"redis"}, "search": {"redis"}},
+			targets: []string{AppNode},
+			want:    []string{"redis", "search", "worker", AppNode},
+		},
+		{
+			name:    "several targets",
+			deps:    map[string][]string{"search": {"db"}},
+			targets: []string{"search", "db"},
+			want:    []string{"db", "search"},
+		},
+		{
+			name:    "self cycle",
+			deps:    map[string][]string{AppNode: {"db"}, "db": {"db"}},
+			targets: []string{AppNode},
+			cycle:   []string{"db", "db"},
+		},
+		{
+			name:    "longer cycle",
+			deps:    map[string][]string{AppNode: {"a"}, "a": {"b"}, "b": {"c"}, "c": {"a"}},
+			targets: []string{AppNode},
+			cycle:   []string{"a", "b", "c", "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BootOrder(tt.deps, tt.targets)
+			if tt.cycle != nil {
+				var ce *CycleError
+				if !errors.As(err, &ce) || !reflect.DeepEqual(ce.Cycle, tt.cycle) {
+					t.Fatalf("BootOrder() error = %v, want cycle %v", err, tt.cycle)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BootOrder() = %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCycleErrorMessage(t *testing.T) {
+	err := &CycleError{Cycle: []string{"db", "search", "db"}}
+	if got, want := err.Error(), "dependency cycle: db -> search -> db"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestAccessoryDependencies(t *testing.T) {
+	accessories := []string{"db", "redis-cache", "search"}
+
+	deps, unknown := AccessoryDependencies(accessories, nil)
+	want := map[string][]string{
+		AppNode:  {"db", "redis-cache", "search"},
+		"search": {"db", "redis-cache"},
+	}
+	if !reflect.DeepEqual(deps, want) || unknown != nil {
+		t.Errorf("AccessoryDependencies(convention) = %v, %v; want %v", deps, unknown, want)
+	}
+
+	declared := map[string][]string{"search": {"db", "queue"}, "mailer": {"db"}}
+	deps, unknown = AccessoryDependencies(accessories, declared)
+	want = map[string][]string{
+		AppNode:  {"db", "redis-cache", "search"},
+		"search": {"db"},
+	}
+	if !reflect.DeepEqual(deps, want) {
+		t.Errorf("AccessoryDependencies(declared) = %v, want %v", deps, want)
+	}
+	if !reflect.DeepEqual(unknown, []string{"mailer", "queue"}) {
+		t.Errorf("unknown = %v, want [mailer queue]", unknown)
+	}
+
+	deps, _ = AccessoryDependencies(accessories, map[string][]string{AppNode: {"db"}})
+	if !reflect.DeepEqual(deps[AppNode], []string{"db"}) {
+		t.Errorf("declared app deps = %v, want [db]", deps[AppNode])
+	}
+}
+
+const accessoryDetailsFixture = `  INFO [a1b2c3d4] Running docker ps --filter label=service=myapp-db on 10.0.0.5
+  INFO [a1b2c3d4] Finished in 0.412 seconds with exit status 0 (successful).
+Accessory db Host: 10.0.0.5
+CONTAINER ID   IMAGE         COMMAND                  CREATED      STATUS      PORTS      NAMES
+4f1e2d3c4b5a   postgres:16   "docker-entrypoint.s…"   2 days ago   Up 2 days   5432/tcp   myapp-db
+
+Accessory redis-cache Host: 10.0.0.5
+CONTAINER ID   IMAGE     COMMAND                  CREATED      STATUS                   PORTS      NAMES
+9a8b7c6d5e4f   redis:7   "docker-entrypoint.s…"   2 days ago   Exited (0) 3 hours ago              myapp-redis-cache
+
+Accessory search Host: 10.0.0.5
+CONTAINER ID   IMAGE     COMMAND   CREATED   STATUS    PORTS     NAMES
+`
+
+func TestRunningAccessories(t *testing.T) {
+	got := RunningAccessories(accessoryDetailsFixture, "myapp", []string{"cache", "db", "redis-cache", "search"})
+	want := map[string]bool{"db": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RunningAccessories() = %v, want %v", got, want)
+	}
+}
@@ -12,15 +12,57 @@ import (
 type DeployDestination struct {
 	Name       string
 	ConfigPath string
-	Service    string
-	Config     map[string]interface{}
+	// BasePath is the shared config/deploy.yml that Kamal merges under a
+	// destination overlay. Empty when ConfigPath is itself the base config.
+	BasePath string
+	Service  string
+	Config   map[string]interface{}
+	// Hidden and Protected are set from .lazykamal.yml by
+	// ProjectConfig.ApplyDestinations, not by discovery.
+	Hidden    bool
+	Protected bool
+	// ConfigErr is set when ConfigPath, or the base config under it, could
+	// not be read or parsed. The destination is still listed so it can be
+	// fixed, but commands are not run against it.
+	ConfigErr *ConfigError
+}
+
+// ConfigError is a deploy config file that could not be read or parsed.
+type ConfigError struct {
+	Path string
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// readDeployConfig reads and parses the deploy config at path. A file with
+// ERB (<% %>) that does not parse is not an error: kamal renders the ERB
+// first, so its settings are just unknown here.
+func readDeployConfig(path string) (map[string]interface{}, *ConfigError) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+	var cfg map[string]interface{}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		if strings.Contains(string(data), "<%") {
+			return nil, nil
+		}
+		return nil, &ConfigError{Path: path, Err: err}
+	}
+	return cfg, nil
 }
 
 // FindDeployConfigs discovers config/deploy*.yml and config/deploy*.yaml in the given directory.
 // In Kamal, deploy.yml is the base config and deploy.<destination>.yml files are destination
 // overlays. When destination files exist, only those are returned (deploy.yml is the shared
 // base, not a separate destination). When no destination files exist, deploy.yml is returned
-// as a single entry with an empty destination name.
+// as a single entry with an empty destination name. A file that cannot be read or
+// parsed is still returned, with ConfigErr set.
 func FindDeployConfigs(dir string) ([]DeployDestination, error) {
 	configDir := filepath.Join(dir, "config")
 	fi, err := os.Stat(configDir)
@@ -42,14 +84,7 @@ func FindDeployConfigs(dir string) ([]DeployDestination, error) {
 		if name == "deploy.yml" || name == "deploy.yaml" {
 			// This is the base config file. Only used as a destination entry
 			// when no destination-specific files exist.
-			data, err := os.ReadFile(configPath)
-			if err != nil {
-				continue
-			}
-			var cfg map[string]interface{}
-			if err := yaml.Unmarshal(data, &cfg); err != nil {
-				continue
-			}
+			cfg, cfgErr := readDeployConfig(configPath)
 			service := "default"
 			if s, ok := cfg["service"].(string); ok && s != "" {
 				service = s
@@ -59,18 +94,12 @@ func FindDeployConfigs(dir string) ([]DeployDestination, error) {
 				ConfigPath: configPath,
 				Service:    service,
 				Config:     cfg,
+				ConfigErr:  cfgErr,
 			}
 		} else if strings.HasPrefix(name, "deploy.") && (strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".yaml")) {
 			ext := name[strings.LastIndex(name, "."):]
 			destName := name[7 : len(name)-len(ext)]
-			data, err := os.ReadFile(configPath)
-			if err != nil {
-				continue
-			}
-			var cfg map[string]interface{}
-			if err := yaml.Unmarshal(data, &cfg); err != nil {
-				continue
-			}
+			cfg, cfgErr := readDeployConfig(configPath)
 			// For destination files, read service from the base config if not specified
 			// in the destination file, falling back to destination name.
 			service := destName
@@ -82,6 +111,7 @@ func FindDeployConfigs(dir string) ([]DeployDestination, error) {
 				ConfigPath: configPath,
 				Service:    service,
 				Config:     cfg,
+				ConfigErr:  cfgErr,
 			})
 		}
 	}
@@ -93,9 +123,15 @@ func FindDeployConfigs(dir string) ([]DeployDestination, error) {
 		// contain overrides).
 		if baseConfig != nil {
 			for i := range destinations {
+				destinations[i].BasePath = baseConfig.ConfigPath
 				if _, ok := destinations[i].Config["service"].(string); !ok {
 					destinations[i].Service = baseConfig.Service
 				}
+				// Kamal merges the overlay onto the base, so a broken base
+				// breaks every destination.
+				if destinations[i].ConfigErr == nil {
+					destinations[i].ConfigErr = baseConfig.ConfigErr
+				}
 			}
 		}
 		return destinations, nil
@@ -118,3 +154,19 @@ func SecretsPath(dir string, dest *DeployDestination) string {
 	}
 	return base
 }
+
+// ConfigSource describes which config files Kamal reads for the destination,
+// with paths relative to dir, e.g.
+// "base: config/deploy.yml + overlay: config/deploy.production.yml".
+func (d *DeployDestination) ConfigSource(dir string) string {
+	rel := func(p string) string {
+		if r, err := filepath.Rel(dir, p); err == nil && !strings.HasPrefix(r, "..") {
+			return filepath.ToSlash(r)
+		}
+		return p
+	}
+	if d.BasePath != "" {
+		return "base: " + rel(d.BasePath) + " + overlay: " + rel(d.ConfigPath)
+	}
+	return rel(d.ConfigPath)
+}
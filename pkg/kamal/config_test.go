@@ -275,3 +275,110 @@ func TestDeployDestination_Label(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployDestination_ConfigSource(t *testing.T) {
+	tmpDir := t.TempDir()
+	configDir := filepath.Join(tmpDir, "config")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "deploy.yml"), []byte("service: myapp\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "deploy.production.yml"), []byte("servers: [1.2.3.4]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dests, err := FindDeployConfigs(tmpDir)
+	if err != nil || len(dests) != 1 {
+		t.Fatalf("FindDeployConfigs() = %v, %v", dests, err)
+	}
+	want := "base: config/deploy.yml + overlay: config/deploy.production.yml"
+	if got := dests[0].ConfigSource(tmpDir); got != want {
+		t.Errorf("ConfigSource() = %q, want %q", got, want)
+	}
+
+	base := DeployDestination{ConfigPath: filepath.Join(configDir, "deploy.yml")}
+	if got := base.ConfigSource(tmpDir); got != "config/deploy.yml" {
+		t.Errorf("ConfigSource() = %q, want config/deploy.yml", got)
+	}
+}
+
+func TestFindDeployConfigs_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		files  map[string]string
+		broken map[string]string // destination -> file its ConfigErr names; absent when it loads
+	}{
+		{
+			name: "invalid overlay alongside valid ones",
+			files: map[string]string{
+				"deploy.yml":            "service: myapp\n",
+				"deploy.staging.yml":    "servers:\n  web: [10.0.0.1\n",
+				"deploy.production.yml": "servers:\n  web:\n    - 10.0.1.1\n",
+			},
+			broken: map[string]string{"staging": "deploy.staging.yml"},
+		},
+		{
+			name: "invalid base breaks every overlay",
+			files: map[string]string{
+				"deploy.yml":         "service: [myapp\n",
+				"deploy.staging.yml": "servers:\n  web:\n    - 10.0.0.1\n",
+			},
+			broken: map[string]string{"staging": "deploy.yml"},
+		},
+		{
+			name:   "invalid base alone",
+			files:  map[string]string{"deploy.yml": "service: [myapp\n"},
+			broken: map[string]string{"": "deploy.yml"},
+		},
+		{
+			name: "ERB is not an error",
+			files: map[string]string{
+				"deploy.yml":         "service: myapp\n",
+				"deploy.staging.yml": "servers:\n  web: [<%= ENV[\"HOSTS\"] %>\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configDir := filepath.Join(dir, "config")
+			if err := os.MkdirAll(configDir, 0755); err != nil {
+				t.Fatal(err)
+			}
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(configDir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configs, err := FindDeployConfigs(dir)
+			if err != nil {
+				t.Fatalf("FindDeployConfigs() error = %v", err)
+			}
+			want := 0
+			for name := range tt.files {
+				if name != "deploy.yml" {
+					want++
+				}
+			}
+			if want == 0 {
+				want = 1
+			}
+			if len(configs) != want {
+				t.Fatalf("got %d configs, want %d: every file is listed", len(configs), want)
+			}
+			for _, c := range configs {
+				file, broken := tt.broken[c.Name]
+				switch {
+				case broken && c.ConfigErr == nil:
+					t.Errorf("%q: no ConfigErr", c.Name)
+				case broken && filepath.Base(c.ConfigErr.Path) != file:
+					t.Errorf("%q: ConfigErr names %s, want %s", c.Name, c.ConfigErr.Path, file)
+				case !broken && c.ConfigErr != nil:
+					t.Errorf("%q: unexpected ConfigErr %v", c.Name, c.ConfigErr)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package kamal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CommandDefaults are per-command RunOptions set in .lazykamal.yml under
+// commands, keyed by the subcommand words joined with "_" (deploy,
+// app_logs, accessory_boot, …). Options chosen in the session win over
+// these; unset fields fall back to the global defaults.
+//
+//	commands:
+//	  deploy: {skip_hooks: true, verbose: true}
+//	  app_logs: {lines: 500}
+type CommandDefaults struct {
+	SkipHooks *bool  `yaml:"skip_hooks"`
+	Verbose   *bool  `yaml:"verbose"`
+	Quiet     *bool  `yaml:"quiet"`
+	Primary   *bool  `yaml:"primary"`
+	Roles     string `yaml:"roles"`
+	Lines     int    `yaml:"lines"`
+}
+
+// commandGroups are the kamal subcommands whose first word alone is not a
+// command; their keys take the second word too (app_logs, lock_status).
+var commandGroups = map[string][]string{
+	"accessory": {"boot", "details", "exec", "logs", "reboot", "remove", "restart", "start", "stop", "upgrade"},
+	"app":       {"boot", "containers", "details", "exec", "images", "live", "logs", "maintenance", "remove", "remove_container", "restart", "stale_containers", "start", "stop", "version"},
+	"build":     {"create", "deliver", "details", "dev", "pull", "push", "remove"},
+	"env":       {"delete", "pull", "push"},
+	"lock":      {"acquire", "release", "status"},
+	"proxy":     {"boot", "boot_config", "details", "logs", "reboot", "remove", "restart", "start", "stop"},
+	"prune":     {"all", "containers", "images"},
+	"registry":  {"login", "logout", "remove", "setup"},
+	"secrets":   {"extract", "fetch", "print"},
+	"server":    {"bootstrap", "exec"},
+}
+
+var singleCommands = []string{"audit", "config", "deploy", "details", "docs", "help", "init", "redeploy", "rollback", "setup", "upgrade", "version"}
+
+// CommandKey returns the .lazykamal.yml commands key for a subcommand, e.g.
+// ["app", "logs", "--follow"] -> "app_logs".
+func CommandKey(subcommand []string) string {
+	if len(subcommand) == 0 {
+		return ""
+	}
+	if _, ok := commandGroups[subcommand[0]]; ok && len(subcommand) > 1 {
+		return subcommand[0] + "_" + subcommand[1]
+	}
+	return subcommand[0]
+}
+
+// KnownCommandKeys lists every key accepted under commands, sorted.
+func KnownCommandKeys() []string {
+	keys := append([]string{}, singleCommands...)
+	for group, subs := range commandGroups {
+		for _, s := range subs {
+			keys = append(keys, group+"_"+s)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isKnownCommandKey(key string) bool {
+	for _, k := range KnownCommandKeys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandWarnings reports commands entries that would be ignored: unknown
+// command keys and lines on a command that does not print logs.
+func (c *ProjectConfig) CommandWarnings() []string {
+	var keys []string
+	for k := range c.Commands {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var warnings []string
+	for _, k := range keys {
+		if !isKnownCommandKey(k) {
+			warnings = append(warnings, fmt.Sprintf("%s: unknown command %q under commands (ignored); use e.g. deploy or app_logs", ProjectConfigFile, k))
+			continue
+		}
+		if d := c.Commands[k]; d.Lines != 0 && !strings.HasSuffix(k, "_logs") {
+			warnings = append(warnings, fmt.Sprintf("%s: commands.%s.lines only applies to logs commands (ignored)", ProjectConfigFile, k))
+		}
+	}
+	return warnings
+}
+
+// ForCommand returns opts with the project defaults for subcommand merged
+// in, and the flags that came from those defaults. Options already set in
+// opts (the session's choices) are kept.
+func (opts RunOptions) ForCommand(subcommand []string) (RunOptions, []string) {
+	key := CommandKey(subcommand)
+	if !isKnownCommandKey(key) {
+		return opts, nil
+	}
+	d, ok := opts.Defaults[key]
+	if !ok {
+		return opts, nil
+	}
+	var applied []string
+	setBool := func(field *bool, def *bool, flag string) {
+		if *field || def == nil || !*def {
+			return
+		}
+		*field = true
+		applied = append(applied, flag)
+	}
+	setBool(&opts.Primary, d.Primary, "--primary")
+	if opts.Roles == "" && d.Roles != "" {
+		opts.Roles = d.Roles
+		applied = append(applied, "--roles", d.Roles)
+	}
+	setBool(&opts.SkipHooks, d.SkipHooks, "--skip-hooks")
+	setBool(&opts.Verbose, d.Verbose, "--verbose")
+	setBool(&opts.Quiet, d.Quiet, "--quiet")
+	if opts.Lines == 0 && d.Lines > 0 && strings.HasSuffix(key, "_logs") {
+		opts.Lines = d.Lines
+		applied = append(applied, "--lines", strconv.Itoa(d.Lines))
+	}
+	return opts, applied
+}
+
+// commandArgs merges the project defaults for subcommand into opts and
+// returns the full kamal argv, reporting it through opts.OnRun.
+func commandArgs(subcommand []string, opts RunOptions) []string {
+	args, applied := resolveArgs(subcommand, opts)
+	if opts.OnRun != nil {
+		opts.OnRun(args, applied)
+	}
+	return args
+}
+
+// resolveArgs returns the kamal argv for subcommand and the flags of it
+// taken from the project defaults. --version is dropped for commands that
+// ignore it.
+func resolveArgs(subcommand []string, opts RunOptions) (args, applied []string) {
+	opts, applied = opts.ForCommand(subcommand)
+	if !AcceptsVersion(subcommand) {
+		opts.Version = ""
+	}
+	return append(append([]string{}, subcommand...), buildGlobalArgs(opts)...), applied
+}
@@ -0,0 +1,132 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandKey(t *testing.T) {
+	tests := []struct {
+		sub  []string
+		want string
+	}{
+		{[]string{"deploy"}, "deploy"},
+		{[]string{"deploy", "--skip-push"}, "deploy"},
+		{[]string{"app", "logs", "--follow"}, "app_logs"},
+		{[]string{"accessory", "boot", "db"}, "accessory_boot"},
+		{[]string{"lock", "status"}, "lock_status"},
+		{[]string{"app"}, "app"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := CommandKey(tt.sub); got != tt.want {
+			t.Errorf("CommandKey(%v) = %q, want %q", tt.sub, got, tt.want)
+		}
+	}
+}
+
+func TestForCommandPrecedence(t *testing.T) {
+	yes, no := true, false
+	defaults := map[string]CommandDefaults{
+		"deploy":   {SkipHooks: &yes, Verbose: &yes},
+		"app_logs": {Lines: 500, Roles: "web"},
+		"redeploy": {Verbose: &no},
+		"setup":    {Lines: 100},
+	}
+
+	tests := []struct {
+		name    string
+		sub     []string
+		session RunOptions
+		want    []string
+		applied []string
+	}{
+		{
+			name: "global defaults without project entry",
+			sub:  []string{"rollback"},
+			want: []string{"rollback"},
+		},
+		{
+			name:    "project default fills unset options",
+			sub:     []string{"deploy"},
+			want:    []string{"deploy", "--skip-hooks", "--verbose"},
+			applied: []string{"--skip-hooks", "--verbose"},
+		},
+		{
+			name:    "session choice wins over project default",
+			sub:     []string{"app", "logs"},
+			session: RunOptions{Lines: 50, Roles: "workers"},
+			want:    []string{"app", "logs", "--roles", "workers", "--lines", "50"},
+		},
+		{
+			name:    "session toggle already on is not reported",
+			sub:     []string{"deploy"},
+			session: RunOptions{Verbose: true},
+			want:    []string{"deploy", "--skip-hooks", "--verbose"},
+			applied: []string{"--skip-hooks"},
+		},
+		{
+			name:    "false default cannot turn a session toggle off",
+			sub:     []string{"redeploy"},
+			session: RunOptions{Verbose: true},
+			want:    []string{"redeploy", "--verbose"},
+		},
+		{
+			name: "lines ignored outside logs commands",
+			sub:  []string{"setup"},
+			want: []string{"setup"},
+		},
+		{
+			name:    "logs defaults apply to follow",
+			sub:     []string{"app", "logs", "--follow"},
+			want:    []string{"app", "logs", "--follow", "--roles", "web", "--lines", "500"},
+			applied: []string{"--roles", "web", "--lines", "500"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.session
+			opts.Defaults = defaults
+			var gotApplied []string
+			opts.OnRun = func(_, fromDefaults []string) { gotApplied = fromDefaults }
+			got := commandArgs(tt.sub, opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("args = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(gotApplied, tt.applied) {
+				t.Errorf("from defaults = %v, want %v", gotApplied, tt.applied)
+			}
+		})
+	}
+}
+
+func TestCommandWarnings(t *testing.T) {
+	dir := t.TempDir()
+	content := "commands:\n  deploy: {skip_hooks: true}\n  app_logs: {lines: 500}\n  deploi: {verbose: true}\n  redeploy: {lines: 10}\n"
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	if got := cfg.Commands["app_logs"].Lines; got != 500 {
+		t.Errorf("app_logs lines = %d, want 500", got)
+	}
+	warnings := cfg.CommandWarnings()
+	if len(warnings) != 2 {
+		t.Fatalf("CommandWarnings() = %v, want 2 warnings", warnings)
+	}
+	if !strings.Contains(warnings[0], `unknown command "deploi"`) {
+		t.Errorf("warnings[0] = %q, want unknown deploi", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "commands.redeploy.lines") {
+		t.Errorf("warnings[1] = %q, want redeploy lines", warnings[1])
+	}
+	if w := (&ProjectConfig{}).CommandWarnings(); len(w) != 0 {
+		t.Errorf("CommandWarnings(empty) = %v, want none", w)
+	}
+}
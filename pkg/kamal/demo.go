@@ -0,0 +1,259 @@
+package kamal
+
+import (
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// demoFiles is the project --demo opens: one app with two accessories and
+// a staging and a production destination.
+var demoFiles = map[string]string{
+	"config/deploy.yml": `service: demo
+image: example/demo
+registry:
+  server: registry.example.com
+  username: demo
+  password:
+    - KAMAL_REGISTRY_PASSWORD
+env:
+  clear:
+    RAILS_LOG_TO_STDOUT: "1"
+  secret:
+    - RAILS_MASTER_KEY
+accessories:
+  db:
+    image: postgres:16
+    host: 10.0.1.5
+  redis:
+    image: redis:7
+    host: 10.0.1.5
+`,
+	"config/deploy.staging.yml": `servers:
+  web:
+    - 10.0.1.5
+`,
+	"config/deploy.production.yml": `servers:
+  web:
+    - 10.0.2.10
+    - 10.0.2.11
+`,
+	".kamal/secrets":  "KAMAL_REGISTRY_PASSWORD=demo\nRAILS_MASTER_KEY=demo\n",
+	ProjectConfigFile: "protected_destinations: [production]\n",
+}
+
+// demoHosts are the servers of each demo destination.
+var demoHosts = map[string][]string{
+	"staging":    {"10.0.1.5"},
+	"production": {"10.0.2.10", "10.0.2.11"},
+}
+
+const (
+	demoVersion  = "4f1c2d9e"
+	demoPrevious = "88aa77bb"
+	demoImage    = "registry.example.com/example/demo"
+)
+
+// WriteDemoProject writes the --demo project into dir.
+func WriteDemoProject(dir string) error {
+	for name, content := range demoFiles {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DemoResponse is the output kamal would print for args against the demo
+// project, with SSHKit's log lines, and its exit code.
+func DemoResponse(args []string) ([]string, int) {
+	var words []string
+	for _, a := range args {
+		if strings.HasPrefix(a, "-") {
+			break
+		}
+		words = append(words, a)
+	}
+	dest := "staging"
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "--destination" {
+			dest = args[i+1]
+		}
+	}
+	hosts := demoHosts[dest]
+	if hosts == nil {
+		hosts = demoHosts["staging"]
+	}
+	cmd := strings.Join(words, " ")
+	switch {
+	case cmd == "version":
+		return []string{"2.5.3"}, 0
+	case cmd == "lock status":
+		return append(demoOnHosts(hosts, "cat .kamal/lock-demo/details", "", nil), "There is no deploy lock"), 0
+	case cmd == "app version":
+		return demoOnHosts(hosts, "docker ps --filter label=service=demo", "", func(string) []string {
+			return []string{demoVersion}
+		}), 0
+	case cmd == "app containers" || cmd == "app details":
+		return demoOnHosts(hosts, "docker container ls --all --filter label=service=demo", "", func(string) []string {
+			return demoContainers(dest)
+		}), 0
+	case cmd == "app images":
+		return demoOnHosts(hosts, "docker image ls "+demoImage, "", func(string) []string {
+			return []string{
+				"REPOSITORY                            TAG        IMAGE ID       CREATED        SIZE",
+				demoImage + "   " + demoVersion + "   3f2a9c1b7d4e   3 hours ago    412MB",
+				demoImage + "   " + demoPrevious + "   a1b2c3d4e5f6   2 days ago     409MB",
+			}
+		}), 0
+	case strings.HasPrefix(cmd, "app logs"):
+		return demoOnHosts(hosts, "docker logs demo-web-"+dest+"-"+demoVersion, "", func(h string) []string {
+			return demoAppLog(h)
+		}), 0
+	case cmd == "proxy details":
+		return demoOnHosts(hosts, "docker ps --filter name=^kamal-proxy$", "Proxy Host: ", func(string) []string {
+			return []string{
+				"CONTAINER ID   IMAGE                         COMMAND             CREATED      STATUS      PORTS                                      NAMES",
+				"0b1c2d3e4f5a   basecamp/kamal-proxy:v0.8.2   \"kamal-proxy run\"   3 days ago   Up 3 days   0.0.0.0:80->80/tcp, 0.0.0.0:443->443/tcp   kamal-proxy",
+			}
+		}), 0
+	case cmd == "proxy logs":
+		return demoOnHosts(hosts, "docker logs kamal-proxy", "", func(string) []string {
+			return []string{
+				`{"time":"` + time.Now().UTC().Format(time.RFC3339) + `","level":"INFO","msg":"Request","host":"demo.example.com","path":"/up","status":200,"dur":2}`,
+				`{"time":"` + time.Now().UTC().Format(time.RFC3339) + `","level":"INFO","msg":"Request","host":"demo.example.com","path":"/","status":200,"dur":41}`,
+			}
+		}), 0
+	case strings.HasPrefix(cmd, "accessory details"):
+		return demoAccessoryDetails(strings.TrimPrefix(cmd, "accessory details ")), 0
+	case strings.HasPrefix(cmd, "accessory logs"):
+		return demoOnHosts(demoHosts["staging"], "docker logs demo-db", "", func(string) []string {
+			return []string{
+				"LOG:  checkpoint starting: time",
+				"LOG:  checkpoint complete: wrote 42 buffers (0.3%)",
+			}
+		}), 0
+	case strings.HasPrefix(cmd, "server exec"):
+		return demoOnHosts(hosts, strings.TrimPrefix(cmd, "server exec "), "", func(string) []string {
+			if strings.Contains(cmd, "date +%s") {
+				return []string{strconv.FormatInt(time.Now().Unix(), 10)}
+			}
+			return []string{time.Now().UTC().Format(time.UnixDate)}
+		}), 0
+	case cmd == "deploy" || cmd == "redeploy" || cmd == "rollback" || cmd == "setup":
+		return demoDeploy(cmd, hosts), 0
+	}
+	return demoOnHosts(hosts, "kamal "+cmd, "", nil), 0
+}
+
+// demoOnHosts is SSHKit's log of command on each host, then what reply
+// prints for each host under its header ("App Host: " unless given).
+func demoOnHosts(hosts []string, command, header string, reply func(host string) []string) []string {
+	var lines []string
+	for _, h := range hosts {
+		id := demoID(command + h)
+		lines = append(lines,
+			fmt.Sprintf("  INFO [%s] Running %s on %s", id, command, h),
+			fmt.Sprintf("  INFO [%s] Finished in 0.%03d seconds with exit status 0 (successful).", id, 200+len(h)*7))
+	}
+	if reply == nil {
+		return lines
+	}
+	if header == "" {
+		header = "App Host: "
+	}
+	for _, h := range hosts {
+		lines = append(lines, header+h)
+		lines = append(lines, reply(h)...)
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func demoContainers(dest string) []string {
+	return []string{
+		"CONTAINER ID   IMAGE                                          COMMAND                  CREATED       STATUS                     PORTS     NAMES",
+		"c0ffee123456   " + demoImage + ":" + demoVersion + "   \"/rails/bin/docker-e…\"   3 hours ago   Up 3 hours (healthy)       80/tcp    demo-web-" + dest + "-" + demoVersion,
+		"0badc0de7890   " + demoImage + ":" + demoPrevious + "   \"/rails/bin/docker-e…\"   2 days ago    Exited (0) 3 hours ago               demo-web-" + dest + "-" + demoPrevious,
+	}
+}
+
+// demoAccessories are the demo accessories' docker ps rows.
+var demoAccessories = []struct{ name, row string }{
+	{"db", "1a2b3c4d5e6f   postgres:16   \"docker-entrypoint.s…\"   5 days ago   Up 5 days   5432/tcp   demo-db"},
+	{"redis", "6f5e4d3c2b1a   redis:7       \"docker-entrypoint.s…\"   5 days ago   Up 5 days   6379/tcp   demo-redis"},
+}
+
+// demoAccessoryDetails is `kamal accessory details NAME` for one accessory
+// or "all": a table under an "Accessory NAME Host:" header for each.
+func demoAccessoryDetails(name string) []string {
+	var lines []string
+	for _, a := range demoAccessories {
+		if name != "all" && name != a.name {
+			continue
+		}
+		lines = append(lines, demoOnHosts(demoHosts["staging"], "docker ps --filter label=service=demo-"+a.name, "Accessory "+a.name+" Host: ", func(string) []string {
+			return []string{
+				"CONTAINER ID   IMAGE         COMMAND                  CREATED      STATUS      PORTS      NAMES",
+				a.row,
+			}
+		})...)
+	}
+	return lines
+}
+
+func demoAppLog(host string) []string {
+	now := time.Now().UTC().Format("2006-01-02T15:04:05")
+	return []string{
+		host + " " + now + ` I, [` + now + `] INFO -- : Started GET "/up" for 10.0.0.1`,
+		host + " " + now + ` I, [` + now + `] INFO -- : Completed 200 OK in 2ms`,
+		host + " " + now + ` I, [` + now + `] INFO -- : Started GET "/" for 203.0.113.7`,
+		host + " " + now + ` I, [` + now + `] INFO -- : Completed 200 OK in 38ms (Views: 21.4ms | ActiveRecord: 6.1ms)`,
+	}
+}
+
+// demoDeploy is a deploy's log: build, pull and boot on every host.
+func demoDeploy(cmd string, hosts []string) []string {
+	lines := []string{
+		"  INFO [" + demoID(cmd) + "] Running /usr/bin/env mkdir -p .kamal on " + strings.Join(hosts, ", "),
+		"Acquiring the deploy lock...",
+	}
+	if cmd == "deploy" || cmd == "setup" {
+		lines = append(lines,
+			"Log into image registry...",
+			"Build and push app image...",
+			"#8 [build 4/6] RUN bundle install",
+			"#12 [build 6/6] RUN bin/rails assets:precompile",
+			"#15 pushing layers 2.1s done",
+		)
+	}
+	lines = append(lines, "Ensure kamal-proxy is running...")
+	for _, h := range hosts {
+		id := demoID(cmd + h)
+		lines = append(lines,
+			fmt.Sprintf("  INFO [%s] Running docker pull %s:%s on %s", id, demoImage, demoVersion, h),
+			fmt.Sprintf("  INFO [%s] Finished in 2.%03d seconds with exit status 0 (successful).", id, len(h)*31),
+			"  INFO [app-web] Booting demo-web-"+demoVersion+" on "+h,
+			"  INFO [app-web] Waiting for the container to be healthy...",
+		)
+	}
+	lines = append(lines,
+		"Prune old containers and images...",
+		"Releasing the deploy lock...",
+		fmt.Sprintf("  Finished all in %d.4 seconds", 18+4*len(hosts)),
+	)
+	return lines
+}
+
+// demoID is a stable SSHKit command id for s.
+func demoID(s string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(s)))
+}
@@ -0,0 +1,101 @@
+package kamal
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDemoProject(t *testing.T) {
+	dir := t.TempDir()
+	if err := WriteDemoProject(dir); err != nil {
+		t.Fatal(err)
+	}
+	dests, err := FindDeployConfigs(dir)
+	if err != nil || len(dests) != 2 {
+		t.Fatalf("FindDeployConfigs(demo) = %+v, %v; want staging and production", dests, err)
+	}
+	for _, d := range dests {
+		if got := d.Accessories(); !reflect.DeepEqual(got, []string{"db", "redis"}) {
+			t.Errorf("%s accessories = %v", d.Name, got)
+		}
+	}
+	if cfg, err := LoadProjectConfig(dir); err != nil || len(cfg.ProtectedDestinations) != 1 {
+		t.Errorf("demo %s = %+v, %v", ProjectConfigFile, cfg, err)
+	}
+}
+
+// The demo answers must parse like real kamal output, or the panels stay empty.
+func TestDemoResponseParses(t *testing.T) {
+	run := func(args ...string) string {
+		lines, code := DemoResponse(args)
+		if code != 0 {
+			t.Fatalf("%v exit %d", args, code)
+		}
+		return strings.Join(lines, "\n")
+	}
+	versions := ParseAppVersions(run("app", "version", "--destination", "production"))
+	if len(versions) != 2 || versions["10.0.2.11"] != demoVersion {
+		t.Errorf("demo versions = %v", versions)
+	}
+	containers := ParseAppContainers(run("app", "containers", "--destination", "staging"))
+	if len(containers) != 2 || !containers[0].Running() || containers[1].Running() || containers[0].Tag() != demoVersion {
+		t.Errorf("demo containers = %+v", containers)
+	}
+	if proxy := ParseProxyDetails(run("proxy", "details", "--destination", "staging")); len(proxy) != 1 || !proxy[0].Running {
+		t.Errorf("demo proxy = %+v", proxy)
+	}
+	if lock := ParseLockStatus(run("lock", "status")); lock.Held {
+		t.Errorf("demo lock = %+v", lock)
+	}
+	running := RunningAccessories(run("accessory", "details", "all"), "demo", []string{"db", "redis"})
+	if !running["db"] || !running["redis"] {
+		t.Errorf("demo running accessories = %v", running)
+	}
+	if hosts := ParseHostResults(strings.Split(strings.TrimSpace(run("deploy", "--destination", "production")), "\n")); len(hosts) != 2 {
+		t.Errorf("demo deploy host results = %+v", hosts)
+	}
+}
+
+func TestFakeRunner(t *testing.T) {
+	f := &FakeRunner{Respond: func(args []string) ([]string, int) {
+		return []string{"one", "two"}, 3
+	}}
+	opts := RunOptions{Destination: "staging", Runner: f}
+	res, err := RunKamal([]string{"app", "boot"}, opts)
+	if err != nil || res.Stdout != "one\ntwo\n" || res.ExitCode != 3 {
+		t.Errorf("RunKamal(fake) = %+v, %v", res, err)
+	}
+	var got []string
+	res, err = RunKamalStreamWithStop([]string{"deploy"}, opts, func(l string) { got = append(got, l) }, nil)
+	if err != nil || !res.Streamed || !reflect.DeepEqual(got, []string{"one", "two"}) {
+		t.Errorf("RunKamalStreamWithStop(fake) = %+v, %v, lines %v", res, err, got)
+	}
+	if err := RunKamalStream([]string{"app", "logs"}, opts, func(string) {}, nil); err == nil || err.Error() != "exit status 3" {
+		t.Errorf("RunKamalStream(fake) error = %v, want exit status 3", err)
+	}
+	want := [][]string{
+		{"app", "boot", "--destination", "staging"},
+		{"deploy", "--destination", "staging"},
+		{"app", "logs", "--destination", "staging"},
+	}
+	if calls := f.Calls(); !reflect.DeepEqual(calls, want) {
+		t.Errorf("Calls() = %q, want %q", calls, want)
+	}
+}
+
+func TestFakeRunnerFollowStops(t *testing.T) {
+	f := &FakeRunner{Delay: time.Millisecond}
+	ctx, cancel := context.WithCancel(context.Background())
+	n := 0
+	res, err := f.RunStream(ctx, []string{"app", "logs", "--follow"}, RunOptions{}, func(string) {
+		if n++; n == 50 {
+			cancel()
+		}
+	})
+	if err != nil || res.ExitCode != -1 || n != 50 {
+		t.Errorf("followed fake logs = exit %d, %v after %d lines", res.ExitCode, err, n)
+	}
+}
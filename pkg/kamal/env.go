@@ -0,0 +1,167 @@
+package kamal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ConfiguredEnv is the env section of a deploy config: clear variables with
+// their values and the names of secret variables.
+type ConfiguredEnv struct {
+	Clear  map[string]string
+	Secret []string
+}
+
+// Env returns the env configured for the destination, merged the way Kamal
+// merges a destination overlay: clear variables are merged key by key and a
+// secret list in the overlay replaces the base one.
+func (d *DeployDestination) Env() ConfiguredEnv {
+	env := ConfiguredEnv{Clear: map[string]string{}}
+	for _, cfg := range []map[string]interface{}{d.baseConfig(), d.Config} {
+		parseEnv(cfg["env"], &env)
+	}
+	sort.Strings(env.Secret)
+	return env
+}
+
+// parseEnv handles `env: {clear: {...}, secret: [...]}` and the flat
+// `env: {KEY: value}` form, where every key is clear. Secrets may be aliased
+// ("DB_PASSWORD:MAIN_DB_PASSWORD"); the variable name is the part before the
+// colon. Role-specific env (tags) is ignored.
+func parseEnv(v interface{}, env *ConfiguredEnv) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	_, hasClear := m["clear"]
+	_, hasSecret := m["secret"]
+	if !hasClear && !hasSecret {
+		for k, val := range m {
+			if k != "tags" {
+				env.Clear[k] = envValue(val)
+			}
+		}
+		return
+	}
+	if clear, ok := m["clear"].(map[string]interface{}); ok {
+		for k, val := range clear {
+			env.Clear[k] = envValue(val)
+		}
+	}
+	if secret, ok := m["secret"].([]interface{}); ok {
+		env.Secret = env.Secret[:0]
+		for _, s := range secret {
+			if name, ok := s.(string); ok {
+				name, _, _ = strings.Cut(name, ":")
+				env.Secret = append(env.Secret, name)
+			}
+		}
+	}
+}
+
+func envValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+var envLine = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)=(.*)$`)
+
+// ParseContainerEnv parses `kamal app exec --reuse env` output into
+// host -> variable -> value. Each host's output follows an "App Host:"
+// header; SSHKit log lines and anything that is not KEY=value are skipped.
+func ParseContainerEnv(output string) map[string]map[string]string {
+	out := map[string]map[string]string{}
+	host := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || isSSHKitLogLine(trimmed) {
+			continue
+		}
+		if v, ok := cutField(trimmed, "App Host:"); ok {
+			host = v
+			out[host] = map[string]string{}
+			continue
+		}
+		if host == "" {
+			continue
+		}
+		if m := envLine.FindStringSubmatch(line); m != nil {
+			out[host][m[1]] = m[2]
+		}
+	}
+	return out
+}
+
+// runtimeEnvKeys are set by Docker, the shell or Kamal itself rather than
+// the deploy config, so they never count as drift.
+var runtimeEnvKeys = map[string]bool{
+	"PATH": true, "HOSTNAME": true, "HOME": true, "TERM": true, "PWD": true,
+	"SHLVL": true, "_": true, "LANG": true, "LANGUAGE": true, "LC_ALL": true,
+}
+
+// runtimeEnvPrefixes cover variables that base images commonly bake in.
+var runtimeEnvPrefixes = []string{"KAMAL_", "RUBY_", "GEM_", "BUNDLE_", "NODE_", "YARN_", "NPM_", "PYTHON", "PIP_", "JAVA_", "GOLANG_", "GPG_KEY"}
+
+func isRuntimeEnvKey(key string) bool {
+	if runtimeEnvKeys[key] {
+		return true
+	}
+	for _, p := range runtimeEnvPrefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnvDrift lists the variable names that differ between the configured env
+// and a running container. Values are never included.
+type EnvDrift struct {
+	Missing []string // configured but not in the container (added since the last deploy)
+	Extra   []string // in the container but no longer configured
+	Changed []string // clear variables whose value differs
+}
+
+// Empty reports whether the container matches the config.
+func (d EnvDrift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Changed) == 0
+}
+
+// CompareEnv compares the configured env with a running container's env.
+// Secret values are not known locally, so secrets only count when missing.
+// Variables the image or runtime sets are not reported as extra, unless the
+// config sets them too.
+func CompareEnv(cfg ConfiguredEnv, running map[string]string) EnvDrift {
+	var drift EnvDrift
+	configured := map[string]bool{}
+	for k, want := range cfg.Clear {
+		configured[k] = true
+		got, ok := running[k]
+		switch {
+		case !ok:
+			drift.Missing = append(drift.Missing, k)
+		case got != want:
+			drift.Changed = append(drift.Changed, k)
+		}
+	}
+	for _, k := range cfg.Secret {
+		configured[k] = true
+		if _, ok := running[k]; !ok {
+			drift.Missing = append(drift.Missing, k)
+		}
+	}
+	for k := range running {
+		if !configured[k] && !isRuntimeEnvKey(k) {
+			drift.Extra = append(drift.Extra, k)
+		}
+	}
+	sort.Strings(drift.Missing)
+	sort.Strings(drift.Extra)
+	sort.Strings(drift.Changed)
+	return drift
+}
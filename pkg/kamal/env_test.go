@@ -0,0 +1,135 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want ConfiguredEnv
+	}{
+		{
+			name: "none",
+			yaml: "service: myapp\n",
+			want: ConfiguredEnv{Clear: map[string]string{}},
+		},
+		{
+			name: "clear and secret",
+			yaml: "env:\n  clear:\n    RAILS_ENV: production\n    WEB_CONCURRENCY: 2\n  secret:\n    - RAILS_MASTER_KEY\n    - DB_PASSWORD:MAIN_DB_PASSWORD\n",
+			want: ConfiguredEnv{
+				Clear:  map[string]string{"RAILS_ENV": "production", "WEB_CONCURRENCY": "2"},
+				Secret: []string{"DB_PASSWORD", "RAILS_MASTER_KEY"},
+			},
+		},
+		{
+			name: "flat form",
+			yaml: "env:\n  RAILS_ENV: production\n  DEBUG: false\n",
+			want: ConfiguredEnv{Clear: map[string]string{"RAILS_ENV": "production", "DEBUG": "false"}},
+		},
+	}
+	for _, tt := range tests {
+		var cfg map[string]interface{}
+		if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+			t.Fatalf("%s: yaml: %v", tt.name, err)
+		}
+		d := DeployDestination{Config: cfg}
+		if got := d.Env(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Env() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEnvMergesBaseConfig(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "deploy.yml")
+	if err := os.WriteFile(base, []byte("env:\n  clear:\n    RAILS_ENV: production\n    LOG_LEVEL: info\n  secret: [RAILS_MASTER_KEY, SMTP_PASSWORD]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var overlay map[string]interface{}
+	yaml.Unmarshal([]byte("env:\n  clear:\n    LOG_LEVEL: debug\n  secret: [RAILS_MASTER_KEY]\n"), &overlay)
+	d := DeployDestination{Config: overlay, BasePath: base}
+	want := ConfiguredEnv{
+		Clear:  map[string]string{"RAILS_ENV": "production", "LOG_LEVEL": "debug"},
+		Secret: []string{"RAILS_MASTER_KEY"},
+	}
+	if got := d.Env(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseContainerEnv(t *testing.T) {
+	output := `  INFO [1a2b3c4d] Running docker exec myapp-web-abc123 env on 10.0.1.5
+  INFO [1a2b3c4d] Finished in 0.412 seconds with exit status 0 (successful).
+App Host: 10.0.1.5
+PATH=/usr/local/bin:/usr/bin
+RAILS_ENV=production
+DATABASE_URL=postgres://u:p@db/app?sslmode=require
+Launching command with version abc123 from existing container...
+
+App Host: 10.0.1.6
+RAILS_ENV=staging
+EMPTY=
+`
+	want := map[string]map[string]string{
+		"10.0.1.5": {"PATH": "/usr/local/bin:/usr/bin", "RAILS_ENV": "production", "DATABASE_URL": "postgres://u:p@db/app?sslmode=require"},
+		"10.0.1.6": {"RAILS_ENV": "staging", "EMPTY": ""},
+	}
+	if got := ParseContainerEnv(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseContainerEnv() = %v, want %v", got, want)
+	}
+}
+
+func TestCompareEnv(t *testing.T) {
+	cfg := ConfiguredEnv{
+		Clear:  map[string]string{"RAILS_ENV": "production", "LOG_LEVEL": "debug", "NEW_FLAG": "1"},
+		Secret: []string{"RAILS_MASTER_KEY", "STRIPE_KEY"},
+	}
+	tests := []struct {
+		name    string
+		running map[string]string
+		want    EnvDrift
+	}{
+		{
+			name: "in sync",
+			running: map[string]string{
+				"RAILS_ENV": "production", "LOG_LEVEL": "debug", "NEW_FLAG": "1",
+				"RAILS_MASTER_KEY": "x", "STRIPE_KEY": "y",
+				"PATH": "/bin", "HOSTNAME": "abc", "RUBY_VERSION": "3.3", "KAMAL_VERSION": "abc",
+			},
+			want: EnvDrift{},
+		},
+		{
+			name: "drifted",
+			running: map[string]string{
+				"RAILS_ENV": "production", "LOG_LEVEL": "info",
+				"RAILS_MASTER_KEY": "x", "OLD_TOKEN": "z",
+			},
+			want: EnvDrift{
+				Missing: []string{"NEW_FLAG", "STRIPE_KEY"},
+				Extra:   []string{"OLD_TOKEN"},
+				Changed: []string{"LOG_LEVEL"},
+			},
+		},
+		{
+			name:    "empty container env",
+			running: map[string]string{},
+			want:    EnvDrift{Missing: []string{"LOG_LEVEL", "NEW_FLAG", "RAILS_ENV", "RAILS_MASTER_KEY", "STRIPE_KEY"}},
+		},
+	}
+	for _, tt := range tests {
+		got := CompareEnv(cfg, tt.running)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: CompareEnv() = %+v, want %+v", tt.name, got, tt.want)
+		}
+		if got.Empty() != (tt.name == "in sync") {
+			t.Errorf("%s: Empty() = %v", tt.name, got.Empty())
+		}
+	}
+}
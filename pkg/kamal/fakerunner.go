@@ -0,0 +1,100 @@
+package kamal
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeRunner is a Runner that runs nothing: it answers every command with
+// canned output, one line per Delay, and records the argv it was given.
+// Followed logs (--follow) repeat their lines until the command is stopped.
+type FakeRunner struct {
+	// Delay is the pause before each output line; zero answers at once.
+	Delay time.Duration
+	// Respond answers an argv with output lines and an exit code; nil
+	// answers like the demo project (DemoResponse).
+	Respond func(args []string) (lines []string, exitCode int)
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+// Calls returns the argv of every command run so far, in order.
+func (f *FakeRunner) Calls() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.calls...)
+}
+
+// Run implements Runner.
+func (f *FakeRunner) Run(ctx context.Context, args []string, opts RunOptions) (Result, error) {
+	lines, code := f.answer(args, opts)
+	var out strings.Builder
+	for _, l := range lines {
+		if !f.wait(ctx) {
+			return Result{Stdout: out.String(), ExitCode: -1}, nil
+		}
+		out.WriteString(l + "\n")
+	}
+	return Result{Stdout: out.String(), ExitCode: code}, nil
+}
+
+// RunStream implements Runner.
+func (f *FakeRunner) RunStream(ctx context.Context, args []string, opts RunOptions, onLine func(line string)) (Result, error) {
+	lines, code := f.answer(args, opts)
+	follow := hasArg(args, "--follow") || hasArg(args, "-f")
+	var out strings.Builder
+	for {
+		for _, l := range lines {
+			if !f.wait(ctx) {
+				return Result{Stdout: out.String(), ExitCode: -1, Streamed: true}, nil
+			}
+			out.WriteString(l + "\n")
+			onLine(l)
+		}
+		if !follow || len(lines) == 0 {
+			return Result{Stdout: out.String(), ExitCode: code, Streamed: true}, nil
+		}
+	}
+}
+
+// answer records args and looks up their output. Env is called as a real
+// command would, so a one-off variable is used up.
+func (f *FakeRunner) answer(args []string, opts RunOptions) ([]string, int) {
+	f.mu.Lock()
+	f.calls = append(f.calls, append([]string(nil), args...))
+	f.mu.Unlock()
+	if opts.Env != nil {
+		opts.Env()
+	}
+	if f.Respond != nil {
+		return f.Respond(args)
+	}
+	return DemoResponse(args)
+}
+
+// wait sleeps Delay, or reports false when ctx ends first.
+func (f *FakeRunner) wait(ctx context.Context) bool {
+	if f.Delay <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(f.Delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+func hasArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package kamal
+
+import (
+	"path"
+	"strings"
+)
+
+// ValidateGlob reports whether pattern is a well-formed destination glob.
+func ValidateGlob(pattern string) error {
+	_, err := path.Match(strings.ToLower(pattern), "")
+	return err
+}
+
+// MatchLabel reports whether a destination label such as "myapp (staging)"
+// matches pattern, case-insensitively. A pattern with glob characters
+// (* ? [) must match the whole label; plain text matches anywhere in it, so
+// typing narrows the list as expected. Malformed patterns match nothing.
+func MatchLabel(pattern, label string) bool {
+	pattern, label = strings.ToLower(pattern), strings.ToLower(label)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.Contains(label, pattern)
+	}
+	ok, err := path.Match(pattern, label)
+	return err == nil && ok
+}
+
+// FilterDestinations keeps destinations whose Label matches any pattern.
+// With no patterns every destination is kept.
+func FilterDestinations(dests []DeployDestination, patterns []string) []DeployDestination {
+	if len(patterns) == 0 {
+		return dests
+	}
+	out := []DeployDestination{}
+	for _, d := range dests {
+		for _, p := range patterns {
+			if MatchLabel(p, d.Label()) {
+				out = append(out, d)
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,64 @@
+package kamal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchLabel(t *testing.T) {
+	tests := []struct {
+		pattern, label string
+		want           bool
+	}{
+		{"myapp*", "myapp (production)", true},
+		{"myapp*", "myapp-worker (staging)", true},
+		{"myapp*", "billing (production)", false},
+		{"*(staging)", "myapp (staging)", true},
+		{"MYAPP*", "myapp (staging)", true},
+		{"stag", "myapp (staging)", true},
+		{"stag", "myapp (production)", false},
+		{"", "anything", true},
+		{"my?pp (prod*", "myapp (production)", true},
+		{"[", "myapp", false},
+	}
+	for _, tt := range tests {
+		if got := MatchLabel(tt.pattern, tt.label); got != tt.want {
+			t.Errorf("MatchLabel(%q, %q) = %v, want %v", tt.pattern, tt.label, got, tt.want)
+		}
+	}
+}
+
+func TestValidateGlob(t *testing.T) {
+	if err := ValidateGlob("myapp*"); err != nil {
+		t.Errorf("ValidateGlob(myapp*) = %v", err)
+	}
+	if err := ValidateGlob("myapp[*"); err == nil {
+		t.Error("ValidateGlob(myapp[*) = nil, want error")
+	}
+}
+
+func TestFilterDestinations(t *testing.T) {
+	dests := []DeployDestination{
+		{Name: "production", Service: "myapp"},
+		{Name: "staging", Service: "myapp"},
+		{Name: "production", Service: "billing"},
+	}
+	labels := func(ds []DeployDestination) []string {
+		var out []string
+		for _, d := range ds {
+			out = append(out, d.Label())
+		}
+		return out
+	}
+	if got := FilterDestinations(dests, nil); len(got) != 3 {
+		t.Errorf("FilterDestinations(no patterns) = %v, want all", labels(got))
+	}
+	got := labels(FilterDestinations(dests, []string{"myapp*", "billing (prod*"}))
+	want := []string{"myapp (production)", "myapp (staging)", "billing (production)"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterDestinations() = %v, want %v", got, want)
+	}
+	if got := FilterDestinations(dests, []string{"nope*"}); got == nil || len(got) != 0 {
+		t.Errorf("FilterDestinations(no match) = %#v, want empty non-nil", got)
+	}
+}
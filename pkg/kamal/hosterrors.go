@@ -0,0 +1,102 @@
+package kamal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// HostError is a host's failure as SSHKit reported it at the end of a
+// kamal command.
+type HostError struct {
+	Host string
+	// Message is the first meaningful line of the error: the command's
+	// stderr when it exited non-zero, else SSHKit's own message, e.g.
+	// "Net::SSH::AuthenticationFailed".
+	Message string
+	// Line and End are the indexes into the parsed lines of the ERROR
+	// line and of the first line after its block.
+	Line, End int
+}
+
+var (
+	sshkitError     = regexp.MustCompile(`^ERROR \(([\w:]+)\): (.*)$`)
+	sshkitException = regexp.MustCompile(`^Exception while executing (?:on host|as) (?:[^@\s]+@)?(\S+?): (.*)$`)
+	sshkitExit      = regexp.MustCompile(`^\S+ exit status: \d+$`)
+	sshkitOutput    = regexp.MustCompile(`^\S+ (stdout|stderr): (.*)$`)
+	logLevel        = regexp.MustCompile(`^(?:DEBUG|INFO|WARN|ERROR|FATAL) `)
+)
+
+// ParseHostErrors finds SSHKit's "ERROR (Class): …" blocks and the host
+// each names: "Exception while executing on host H" or "as user@H", or,
+// for errors that name none, the host of the last command that exited
+// non-zero. The block runs until a blank line or the next log line. Each
+// host is reported once, in order of its first error.
+func ParseHostErrors(lines []string) []HostError {
+	var errs []HostError
+	seen := map[string]bool{}
+	cmdHosts := map[string]string{} // command id -> host
+	lastFailed := ""
+	for n := 0; n < len(lines); n++ {
+		line := strings.TrimSpace(lines[n])
+		if m := sshkitRunning.FindStringSubmatch(line); m != nil {
+			cmdHosts[m[1]] = m[2]
+			continue
+		}
+		if m := sshkitFinished.FindStringSubmatch(line); m != nil {
+			if host, ok := cmdHosts[m[1]]; ok && m[3] != "0" {
+				lastFailed = host
+			}
+			continue
+		}
+		m := sshkitError.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		host, message := lastFailed, m[2]
+		if e := sshkitException.FindStringSubmatch(message); e != nil {
+			host, message = e[1], e[2]
+		}
+		end := n + 1
+		for end < len(lines) {
+			next := strings.TrimSpace(lines[end])
+			if next == "" || logLevel.MatchString(next) {
+				break
+			}
+			end++
+		}
+		if sshkitExit.MatchString(message) {
+			if out := commandOutput(lines[n+1 : end]); out != "" {
+				message = out
+			}
+		}
+		if host != "" && !seen[host] {
+			seen[host] = true
+			errs = append(errs, HostError{Host: host, Message: message, Line: n, End: end})
+		}
+		n = end - 1
+	}
+	return errs
+}
+
+// commandOutput is the first line a failed command wrote, as SSHKit
+// quotes it after the exit status: stderr first, then stdout.
+func commandOutput(block []string) string {
+	var stdout string
+	for _, l := range block {
+		m := sshkitOutput.FindStringSubmatch(strings.TrimSpace(l))
+		if m == nil {
+			continue
+		}
+		out := strings.TrimSpace(m[2])
+		if out == "" || out == "Nothing written" {
+			continue
+		}
+		if m[1] == "stderr" {
+			return out
+		}
+		if stdout == "" {
+			stdout = out
+		}
+	}
+	return stdout
+}
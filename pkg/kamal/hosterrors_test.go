@@ -0,0 +1,83 @@
+package kamal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// authFailureTranscript is `kamal app details` with a key the second host
+// does not accept; --verbose adds the backtrace.
+const authFailureTranscript = `  INFO [a1b2c3d4] Running docker ps --filter label=service=myapp on 10.0.1.5
+  INFO [a1b2c3d4] Finished in 0.412 seconds with exit status 0 (successful).
+  ERROR (SSHKit::Runner::ExecuteError): Exception while executing as root@10.0.1.6: Net::SSH::AuthenticationFailed
+/usr/local/bundle/gems/net-ssh-7.2.0/lib/net/ssh.rb:268:in 'start'
+/usr/local/bundle/gems/sshkit-1.22.0/lib/sshkit/backends/connection_pool.rb:63:in 'call'`
+
+// connectionTimeoutTranscript is `kamal deploy` with an unreachable host.
+const connectionTimeoutTranscript = `Acquiring the deploy lock...
+Ensure Docker is installed...
+  INFO [0f1e2d3c] Running docker -v on 10.0.3.1
+  INFO [0f1e2d3c] Finished in 0.208 seconds with exit status 0 (successful).
+Releasing the deploy lock...
+  Finished all in 31.4 seconds
+  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.3.2: Net::SSH::ConnectionTimeout
+
+  INFO [9a8b7c6d] Running /usr/bin/env rm -r .kamal/lock-myapp on 10.0.3.1`
+
+// commandFailedTranscript is `kamal app boot` where kamal reports the
+// failed command without its host.
+const commandFailedTranscript = `  INFO [11aa22bb] Running docker run --detach --name myapp-web-abc123 on 10.0.4.1
+  INFO [33cc44dd] Running docker run --detach --name myapp-web-abc123 on 10.0.4.2
+  INFO [11aa22bb] Finished in 1.902 seconds with exit status 0 (successful).
+ ERROR [33cc44dd] Finished in 0.311 seconds with exit status 125 (failed).
+  ERROR (SSHKit::Command::Failed): docker exit status: 125
+docker stdout: Nothing written
+docker stderr: docker: Error response from daemon: Conflict. The container name "/myapp-web-abc123" is already in use.
+See 'docker run --help'.`
+
+func TestParseHostErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		want       []HostError
+	}{
+		{
+			name:       "auth failure",
+			transcript: authFailureTranscript,
+			want:       []HostError{{Host: "10.0.1.6", Message: "Net::SSH::AuthenticationFailed", Line: 2, End: 5}},
+		},
+		{
+			name:       "connection timeout",
+			transcript: connectionTimeoutTranscript,
+			want:       []HostError{{Host: "10.0.3.2", Message: "Net::SSH::ConnectionTimeout", Line: 6, End: 7}},
+		},
+		{
+			name:       "non-zero exit",
+			transcript: appRestartTranscript,
+			want:       []HostError{{Host: "10.0.1.7", Message: "Error response from daemon: driver failed programming external connectivity", Line: 13, End: 16}},
+		},
+		{
+			name:       "non-zero exit without host",
+			transcript: commandFailedTranscript,
+			want:       []HostError{{Host: "10.0.4.2", Message: `docker: Error response from daemon: Conflict. The container name "/myapp-web-abc123" is already in use.`, Line: 4, End: 8}},
+		},
+		{
+			name:       "exception without exit status",
+			transcript: accessoryRebootTranscript,
+			want:       []HostError{{Host: "10.0.2.2", Message: "Net::SSH::ConnectionTimeout", Line: 3, End: 4}},
+		},
+		{
+			name:       "no sshkit errors",
+			transcript: "ERROR: config/deploy.yml not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseHostErrors(strings.Split(tt.transcript, "\n"))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHostErrors() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
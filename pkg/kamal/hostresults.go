@@ -0,0 +1,77 @@
+package kamal
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HostResult is one host's outcome in a multi-host kamal command.
+type HostResult struct {
+	Host     string
+	Duration time.Duration // sum of the host's SSHKit command durations
+	Failed   bool
+	// Line is the index into the parsed lines of the host's first command,
+	// or of the command that failed when Failed is set.
+	Line int
+}
+
+var (
+	sshkitRunning  = regexp.MustCompile(`^(?:INFO|DEBUG) \[([0-9a-f]+)\] Running .* on (\S+)$`)
+	sshkitFinished = regexp.MustCompile(`^(?:INFO|DEBUG|ERROR) \[([0-9a-f]+)\] Finished in ([0-9.]+) seconds with exit status (\d+)`)
+	sshkitFailed   = regexp.MustCompile(`Exception while executing on host (\S+?):`)
+)
+
+// ParseHostResults groups SSHKit's per-command log lines by host: each
+// "[id] Running … on host" line opens a command whose "[id] Finished in N
+// seconds with exit status S" line closes it. An "Exception while executing
+// on host H" line fails H too. Hosts are in order of first appearance.
+func ParseHostResults(lines []string) []HostResult {
+	var results []HostResult
+	index := map[string]int{}       // host -> results index
+	commands := map[string]int{}    // command id -> Running line
+	cmdHosts := map[string]string{} // command id -> host
+	result := func(host string, line int) *HostResult {
+		i, ok := index[host]
+		if !ok {
+			i = len(results)
+			index[host] = i
+			results = append(results, HostResult{Host: host, Line: line})
+		}
+		return &results[i]
+	}
+	fail := func(r *HostResult, line int) {
+		if !r.Failed {
+			r.Failed = true
+			r.Line = line
+		}
+	}
+	for n, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if m := sshkitRunning.FindStringSubmatch(line); m != nil {
+			commands[m[1]] = n
+			cmdHosts[m[1]] = m[2]
+			result(m[2], n)
+			continue
+		}
+		if m := sshkitFinished.FindStringSubmatch(line); m != nil {
+			host, ok := cmdHosts[m[1]]
+			if !ok {
+				continue
+			}
+			r := result(host, n)
+			if secs, err := strconv.ParseFloat(m[2], 64); err == nil {
+				r.Duration += time.Duration(secs * float64(time.Second))
+			}
+			if m[3] != "0" {
+				fail(r, commands[m[1]])
+			}
+			continue
+		}
+		if m := sshkitFailed.FindStringSubmatch(line); m != nil {
+			fail(result(m[1], n), n)
+		}
+	}
+	return results
+}
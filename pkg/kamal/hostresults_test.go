@@ -0,0 +1,74 @@
+package kamal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// appRestartTranscript is `kamal app restart` across three hosts where the
+// third host's container fails to start.
+const appRestartTranscript = `  INFO [e3f1a2b4] Running docker container ls --all --filter name=^myapp-web-abc123$ --quiet | xargs docker stop on 10.0.1.5
+  INFO [7c9d0e1f] Running docker container ls --all --filter name=^myapp-web-abc123$ --quiet | xargs docker stop on 10.0.1.6
+  INFO [5a6b7c8d] Running docker container ls --all --filter name=^myapp-web-abc123$ --quiet | xargs docker stop on 10.0.1.7
+  INFO [e3f1a2b4] Finished in 11.204 seconds with exit status 0 (successful).
+  INFO [7c9d0e1f] Finished in 12.031 seconds with exit status 0 (successful).
+  INFO [5a6b7c8d] Finished in 10.877 seconds with exit status 0 (successful).
+  INFO [0b1c2d3e] Running docker start myapp-web-abc123 on 10.0.1.5
+  INFO [4f5a6b7c] Running docker start myapp-web-abc123 on 10.0.1.6
+  INFO [8d9e0f1a] Running docker start myapp-web-abc123 on 10.0.1.7
+  INFO [0b1c2d3e] Finished in 30.512 seconds with exit status 0 (successful).
+  INFO [4f5a6b7c] Finished in 31.990 seconds with exit status 0 (successful).
+ ERROR [8d9e0f1a] Finished in 2.140 seconds with exit status 1 (failed).
+  Finished all in 44.2 seconds
+  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.1.7: docker exit status: 1
+docker stdout: Nothing written
+docker stderr: Error response from daemon: driver failed programming external connectivity`
+
+// accessoryRebootTranscript fails on one host without a Finished line.
+const accessoryRebootTranscript = `  INFO [1a2b3c4d] Running docker container stop myapp-redis on 10.0.2.1
+  INFO [5e6f7a8b] Running docker container stop myapp-redis on 10.0.2.2
+  INFO [1a2b3c4d] Finished in 0.811 seconds with exit status 0 (successful).
+  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.2.2: Net::SSH::ConnectionTimeout`
+
+func TestParseHostResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		want       []HostResult
+	}{
+		{
+			name:       "app restart with one failed host",
+			transcript: appRestartTranscript,
+			want: []HostResult{
+				{Host: "10.0.1.5", Duration: 41716 * time.Millisecond, Line: 0},
+				{Host: "10.0.1.6", Duration: 44021 * time.Millisecond, Line: 1},
+				{Host: "10.0.1.7", Duration: 13017 * time.Millisecond, Failed: true, Line: 8},
+			},
+		},
+		{
+			name:       "exception without exit status",
+			transcript: accessoryRebootTranscript,
+			want: []HostResult{
+				{Host: "10.0.2.1", Duration: 811 * time.Millisecond, Line: 0},
+				{Host: "10.0.2.2", Failed: true, Line: 3},
+			},
+		},
+		{
+			name:       "no sshkit lines",
+			transcript: "App Host: 10.0.1.5\nabc123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseHostResults(strings.Split(tt.transcript, "\n"))
+			for i := range got {
+				got[i].Duration = got[i].Duration.Round(time.Millisecond)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseHostResults() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package kamal
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DeployLockMessage is the message Kamal writes when deploy, redeploy or
+// setup take the lock themselves.
+const DeployLockMessage = "Automatic deploy lock"
+
+// lockClockSlack tolerates the lock timestamp being written slightly before
+// the command is seen to start.
+const lockClockSlack = time.Minute
+
+// LockedBy splits the "Locked by:" value, "<git user.name> at <UTC time>".
+// The time is zero when it is missing or unparsable.
+func (l LockInfo) LockedBy() (who string, at time.Time) {
+	i := strings.LastIndex(l.Holder, " at ")
+	if i < 0 {
+		return l.Holder, time.Time{}
+	}
+	stamp := strings.TrimSpace(l.Holder[i+len(" at "):])
+	for _, layout := range lockTimeLayouts {
+		if at, err := time.Parse(layout, stamp); err == nil {
+			return l.Holder[:i], at
+		}
+	}
+	return l.Holder, time.Time{}
+}
+
+// lockTimeLayouts are the lock timestamps kamal has written: ISO 8601, and
+// Ruby's Time#to_s for older locks.
+var lockTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"}
+
+// HeldByDeployOf reports whether the lock was taken automatically by a
+// deploy run as user. Kamal records the git user.name, not the machine, so
+// this is the closest match to "a deploy started here".
+func (l LockInfo) HeldByDeployOf(user string) bool {
+	if !l.Held || user == "" || l.Message != DeployLockMessage {
+		return false
+	}
+	who, _ := l.LockedBy()
+	return who == user
+}
+
+// LeftBy reports whether the lock is the one a deploy run as user and
+// started at start would have taken.
+func (l LockInfo) LeftBy(user string, start time.Time) bool {
+	if !l.HeldByDeployOf(user) {
+		return false
+	}
+	_, at := l.LockedBy()
+	return !at.IsZero() && !at.Before(start.Add(-lockClockSlack))
+}
+
+// LockUser is the name Kamal records as lock holder for commands run in
+// dir: git's user.name, or "Unknown" without git, as Kamal does.
+func LockUser(dir string) string {
+	cmd := exec.Command("git", "config", "user.name")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if _, lookErr := exec.LookPath("git"); lookErr != nil {
+			return "Unknown"
+		}
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
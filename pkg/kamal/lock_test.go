@@ -0,0 +1,52 @@
+package kamal
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockedBy(t *testing.T) {
+	lock := ParseLockStatus("Locked by: Jane Doe at 2024-05-01T10:00:00Z\nVersion: 9f8e7d6c\nMessage: Automatic deploy lock")
+	who, at := lock.LockedBy()
+	if who != "Jane Doe" || !at.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("LockedBy() = %q, %v", who, at)
+	}
+
+	who, at = LockInfo{Held: true, Holder: "alice at 2024-05-01 14:02:00 UTC"}.LockedBy()
+	if who != "alice" || !at.Equal(time.Date(2024, 5, 1, 14, 2, 0, 0, time.UTC)) {
+		t.Errorf("LockedBy() with Ruby time = %q, %v", who, at)
+	}
+
+	who, at = LockInfo{Held: true, Holder: "CI"}.LockedBy()
+	if who != "CI" || !at.IsZero() {
+		t.Errorf("LockedBy() without time = %q, %v", who, at)
+	}
+}
+
+func TestLockLeftBy(t *testing.T) {
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	deploy := ParseLockStatus("Locked by: Jane Doe at 2024-05-01T10:00:05Z\nVersion: 9f8e7d6c\nMessage: Automatic deploy lock")
+
+	tests := []struct {
+		name  string
+		lock  LockInfo
+		user  string
+		start time.Time
+		want  bool
+	}{
+		{"taken by this deploy", deploy, "Jane Doe", start, true},
+		{"within clock slack", deploy, "Jane Doe", start.Add(30 * time.Second), true},
+		{"older lock", deploy, "Jane Doe", start.Add(time.Hour), false},
+		{"someone else", deploy, "John Roe", start, false},
+		{"manual lock", ParseLockStatus("Locked by: Jane Doe at 2024-05-01T10:00:05Z\nMessage: DB migration"), "Jane Doe", start, false},
+		{"unlocked", ParseLockStatus("There is no deploy lock"), "Jane Doe", start, false},
+	}
+	for _, tt := range tests {
+		if got := tt.lock.LeftBy(tt.user, tt.start); got != tt.want {
+			t.Errorf("%s: LeftBy() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if !deploy.HeldByDeployOf("Jane Doe") {
+		t.Errorf("HeldByDeployOf() = false for an older deploy lock of the same user")
+	}
+}
@@ -0,0 +1,102 @@
+package kamal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// ExecArgs is a parsed `lazykamal exec [--destination NAME] -- ARGS...`.
+type ExecArgs struct {
+	Destination string
+	Args        []string // passed to kamal verbatim
+}
+
+// ParseExecArgs parses the arguments after `lazykamal exec`. Everything
+// after "--" goes to kamal untouched; without "--", kamal's arguments start
+// at the first argument that is not a lazykamal option.
+func ParseExecArgs(args []string) (ExecArgs, error) {
+	var e ExecArgs
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			e.Args = args[i+1:]
+			i = len(args)
+		case arg == "--destination" || arg == "-d":
+			if i+1 >= len(args) {
+				return ExecArgs{}, fmt.Errorf("%s requires a destination name", arg)
+			}
+			i++
+			e.Destination = args[i]
+		case strings.HasPrefix(arg, "--destination="):
+			e.Destination = strings.TrimPrefix(arg, "--destination=")
+		case strings.HasPrefix(arg, "-"):
+			return ExecArgs{}, fmt.Errorf("unknown option %q (pass kamal options after --)", arg)
+		default:
+			e.Args = args[i:]
+			i = len(args)
+		}
+	}
+	if len(e.Args) == 0 {
+		return ExecArgs{}, errors.New("no kamal command given, e.g. lazykamal exec -d staging -- app logs --lines 50")
+	}
+	return e, nil
+}
+
+// ResolveDestination finds destination name in dir the way the TUI lists
+// destinations: deploy.<name>.yml overlays on the shared deploy.yml, or
+// deploy.yml alone (name "") when there are no overlays.
+func ResolveDestination(dir, name string) (*DeployDestination, error) {
+	dests, err := FindDeployConfigs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(dests) == 0 {
+		return nil, fmt.Errorf("no config/deploy.yml found in %s", dir)
+	}
+	var names []string
+	for i := range dests {
+		if dests[i].Name == name {
+			if dests[i].ConfigErr != nil {
+				return nil, dests[i].ConfigErr
+			}
+			return &dests[i], nil
+		}
+		names = append(names, dests[i].Name)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("choose a destination with --destination: %s", strings.Join(names, ", "))
+	}
+	if len(names) == 1 && names[0] == "" {
+		return nil, fmt.Errorf("unknown destination %q: config/deploy.yml has no destination overlays", name)
+	}
+	return nil, fmt.Errorf("unknown destination %q (have: %s)", name, strings.Join(names, ", "))
+}
+
+// PassthroughArgs is the kamal argv for `lazykamal exec`: the user's
+// arguments followed by the global options the TUI passes for dest.
+// .lazykamal.yml command defaults are not applied; the command runs as
+// typed.
+func PassthroughArgs(cwd string, dest *DeployDestination, args []string) []string {
+	return append(append([]string{}, args...), buildGlobalArgs(RunOpts(cwd, dest))...)
+}
+
+// Passthrough runs kamal with argv in cwd, attached to the given streams,
+// and returns its exit code.
+func Passthrough(cwd string, argv []string, stdin io.Reader, stdout, stderr io.Writer) (int, error) {
+	cmd := exec.Command("kamal", argv...)
+	cmd.Dir = cwd
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
@@ -0,0 +1,119 @@
+package kamal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseExecArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want ExecArgs
+	}{
+		{"separator", []string{"--destination", "staging", "--", "app", "logs", "--lines", "50"},
+			ExecArgs{Destination: "staging", Args: []string{"app", "logs", "--lines", "50"}}},
+		{"short flag", []string{"-d", "staging", "--", "deploy"}, ExecArgs{Destination: "staging", Args: []string{"deploy"}}},
+		{"equals form", []string{"--destination=prod", "--", "app", "details"}, ExecArgs{Destination: "prod", Args: []string{"app", "details"}}},
+		{"no destination", []string{"--", "version"}, ExecArgs{Args: []string{"version"}}},
+		{"kamal options after separator stay verbatim", []string{"--", "-d", "other", "--", "x"},
+			ExecArgs{Args: []string{"-d", "other", "--", "x"}}},
+		{"without separator", []string{"-d", "staging", "app", "logs", "-d", "x"},
+			ExecArgs{Destination: "staging", Args: []string{"app", "logs", "-d", "x"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseExecArgs(tt.args)
+		if err != nil {
+			t.Errorf("%s: ParseExecArgs() error = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ParseExecArgs() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+
+	for _, args := range [][]string{
+		nil,
+		{"--"},
+		{"-d"},
+		{"--destination", "staging"},
+		{"--verbose", "--", "deploy"},
+	} {
+		if _, err := ParseExecArgs(args); err == nil {
+			t.Errorf("ParseExecArgs(%q) = nil error", args)
+		}
+	}
+}
+
+func writeDeployConfigs(t *testing.T, files ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, "config", f), []byte("service: myapp\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestResolveDestination(t *testing.T) {
+	dir := writeDeployConfigs(t, "deploy.yml", "deploy.staging.yml", "deploy.production.yml")
+	dest, err := ResolveDestination(dir, "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dest.Name != "staging" || dest.BasePath != filepath.Join(dir, "config", "deploy.yml") {
+		t.Errorf("dest = %+v, want the staging overlay on deploy.yml", dest)
+	}
+	if _, err := ResolveDestination(dir, ""); err == nil || !strings.Contains(err.Error(), "production") {
+		t.Errorf("no destination with overlays: err = %v, want the list of destinations", err)
+	}
+	if _, err := ResolveDestination(dir, "qa"); err == nil {
+		t.Error("unknown destination resolved")
+	}
+
+	base := writeDeployConfigs(t, "deploy.yml")
+	if dest, err := ResolveDestination(base, ""); err != nil || dest.Name != "" {
+		t.Errorf("base only: dest = %+v, err = %v", dest, err)
+	}
+	if _, err := ResolveDestination(base, "staging"); err == nil {
+		t.Error("base only: staging resolved")
+	}
+	if _, err := ResolveDestination(t.TempDir(), ""); err == nil {
+		t.Error("no config resolved")
+	}
+}
+
+func TestPassthroughArgs(t *testing.T) {
+	dest := &DeployDestination{Name: "staging"}
+	got := PassthroughArgs("/app", dest, []string{"app", "logs", "--lines", "50"})
+	want := []string{"app", "logs", "--lines", "50", "--destination", "staging"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PassthroughArgs() = %q, want %q", got, want)
+	}
+	if got := PassthroughArgs("/app", &DeployDestination{}, []string{"version"}); !reflect.DeepEqual(got, []string{"version"}) {
+		t.Errorf("PassthroughArgs(base) = %q", got)
+	}
+}
+
+func TestPassthroughExitCode(t *testing.T) {
+	fakeKamal(t, "echo \"args: $*\"\necho oops >&2\nexit 4\n")
+	var stdout, stderr bytes.Buffer
+	code, err := Passthrough(t.TempDir(), []string{"app", "logs", "--destination", "staging"}, nil, &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 4 {
+		t.Errorf("exit code = %d, want 4", code)
+	}
+	if stdout.String() != "args: app logs --destination staging\n" || stderr.String() != "oops\n" {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,144 @@
+package kamal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFile is the per-project lazykamal settings file, read from the
+// project root next to config/.
+const ProjectConfigFile = ".lazykamal.yml"
+
+// ProjectConfig holds lazykamal settings checked into a project.
+type ProjectConfig struct {
+	// HiddenDestinations are left out of the Apps list unless "show all" is on.
+	HiddenDestinations []string `yaml:"hidden_destinations"`
+	// ProtectedDestinations require typing the destination name before any
+	// mutating command.
+	ProtectedDestinations []string `yaml:"protected_destinations"`
+	// EventSocket, when set, is the Unix socket path (relative to the project
+	// root unless absolute) on which lazykamal publishes JSON events.
+	EventSocket string `yaml:"event_socket"`
+	// PostDeploy commands run from the project root after a successful
+	// deploy or redeploy, e.g. smoke tests.
+	PostDeploy []HookCommand `yaml:"post_deploy"`
+	// PostDeployRollback rolls back to the previous version automatically
+	// when a post_deploy command fails. Off by default.
+	PostDeployRollback bool `yaml:"post_deploy_rollback"`
+	// DependsOn orders accessory boots before App Boot: accessory (or
+	// "app") -> accessories that must be running first.
+	DependsOn map[string][]string `yaml:"depends_on"`
+	// Commands holds per-command default options, keyed like app_logs.
+	Commands map[string]CommandDefaults `yaml:"commands"`
+	// NoSessionSummary turns off the recap of the session's commands
+	// printed on quit.
+	NoSessionSummary bool `yaml:"no_session_summary"`
+	// Plugins are external commands run at named hook points with a JSON
+	// payload on stdin.
+	Plugins Plugins `yaml:"plugins"`
+}
+
+// Plugins lists the commands registered for each plugin hook point, e.g.
+//
+//	plugins:
+//	  on_deploy_success: [scripts/notify.sh]
+//	  status_extra:
+//	    - run: scripts/sidekiq-depth.sh
+//	      timeout: 3s
+type Plugins struct {
+	// OnDeploySuccess runs after a deploy or redeploy succeeds.
+	OnDeploySuccess []HookCommand `yaml:"on_deploy_success"`
+	// OnCommandError runs after a command fails or times out.
+	OnCommandError []HookCommand `yaml:"on_command_error"`
+	// StatusExtra runs on every status poll; the first lines of its
+	// stdout are shown in the Live status panel.
+	StatusExtra []HookCommand `yaml:"status_extra"`
+}
+
+// HookCommand is a shell command with an optional timeout. In YAML it is
+// either a plain string or a {run, timeout} map, e.g.
+//
+//	post_deploy:
+//	  - scripts/smoke.sh
+//	  - run: curl -fsS https://example.com/up
+//	    timeout: 10s
+type HookCommand struct {
+	Run     string
+	Timeout time.Duration // zero means the runner's default
+}
+
+// UnmarshalYAML accepts both HookCommand forms.
+func (h *HookCommand) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		return n.Decode(&h.Run)
+	}
+	var raw struct {
+		Run     string `yaml:"run"`
+		Timeout string `yaml:"timeout"`
+	}
+	if err := n.Decode(&raw); err != nil {
+		return err
+	}
+	if raw.Run == "" {
+		return fmt.Errorf("line %d: hook command needs run", n.Line)
+	}
+	h.Run = raw.Run
+	if raw.Timeout != "" {
+		d, err := time.ParseDuration(raw.Timeout)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid timeout %q", n.Line, raw.Timeout)
+		}
+		h.Timeout = d
+	}
+	return nil
+}
+
+// LoadProjectConfig reads dir/.lazykamal.yml. A missing file is not an error
+// and yields an empty config.
+func LoadProjectConfig(dir string) (*ProjectConfig, error) {
+	cfg := &ProjectConfig{}
+	data, err := os.ReadFile(filepath.Join(dir, ProjectConfigFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return &ProjectConfig{}, fmt.Errorf("%s: %w", ProjectConfigFile, err)
+	}
+	return cfg, nil
+}
+
+// ApplyDestinations marks hidden and protected destinations and returns the
+// ones to list. Hidden destinations are dropped unless showAll is set; hidden
+// is the number of destinations hidden by the config either way.
+func (c *ProjectConfig) ApplyDestinations(dests []DeployDestination, showAll bool) (visible []DeployDestination, hidden int) {
+	isHidden := nameSet(c.HiddenDestinations)
+	isProtected := nameSet(c.ProtectedDestinations)
+	visible = []DeployDestination{}
+	for _, d := range dests {
+		d.Hidden = d.Name != "" && isHidden[d.Name]
+		d.Protected = d.Name != "" && isProtected[d.Name]
+		if d.Hidden {
+			hidden++
+			if !showAll {
+				continue
+			}
+		}
+		visible = append(visible, d)
+	}
+	return visible, hidden
+}
+
+func nameSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
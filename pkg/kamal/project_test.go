@@ -0,0 +1,143 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil || len(cfg.HiddenDestinations) != 0 {
+		t.Fatalf("LoadProjectConfig(missing) = %+v, %v; want empty, nil", cfg, err)
+	}
+
+	content := "hidden_destinations: [demo, loadtest]\nprotected_destinations:\n  - production\n"
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = LoadProjectConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	want := &ProjectConfig{
+		HiddenDestinations:    []string{"demo", "loadtest"},
+		ProtectedDestinations: []string{"production"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("LoadProjectConfig() = %+v, want %+v", cfg, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte("hidden_destinations: {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProjectConfig(dir); err == nil {
+		t.Error("LoadProjectConfig(invalid yaml) = nil error, want error")
+	}
+}
+
+func TestApplyDestinations(t *testing.T) {
+	dests := []DeployDestination{{Name: "demo"}, {Name: "loadtest"}, {Name: "production"}, {Name: "staging"}}
+	cfg := &ProjectConfig{
+		HiddenDestinations:    []string{"demo", "loadtest"},
+		ProtectedDestinations: []string{"production", "demo"},
+	}
+
+	names := func(ds []DeployDestination) []string {
+		var out []string
+		for _, d := range ds {
+			out = append(out, d.Name)
+		}
+		return out
+	}
+
+	visible, hidden := cfg.ApplyDestinations(dests, false)
+	if got, want := names(visible), []string{"production", "staging"}; !reflect.DeepEqual(got, want) || hidden != 2 {
+		t.Errorf("ApplyDestinations(showAll=false) = %v, %d; want %v, 2", got, hidden, want)
+	}
+	if !visible[0].Protected || visible[1].Protected {
+		t.Errorf("Protected = %v/%v, want true/false", visible[0].Protected, visible[1].Protected)
+	}
+
+	visible, hidden = cfg.ApplyDestinations(dests, true)
+	if got := names(visible); len(got) != 4 || hidden != 2 {
+		t.Errorf("ApplyDestinations(showAll=true) = %v, %d; want all 4, 2", got, hidden)
+	}
+	if !visible[0].Hidden || !visible[0].Protected || visible[3].Hidden {
+		t.Errorf("demo/staging flags = %+v / %+v", visible[0], visible[3])
+	}
+	if dests[0].Hidden || dests[2].Protected {
+		t.Error("ApplyDestinations modified its input")
+	}
+
+	visible, _ = (&ProjectConfig{}).ApplyDestinations(nil, false)
+	if visible == nil || len(visible) != 0 {
+		t.Errorf("ApplyDestinations(nil) = %#v, want empty non-nil slice", visible)
+	}
+}
+
+func TestHookCommandYAML(t *testing.T) {
+	dir := t.TempDir()
+	content := `post_deploy:
+  - scripts/smoke.sh
+  - run: curl -fsS https://example.com/up
+    timeout: 10s
+post_deploy_rollback: true
+`
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	want := []HookCommand{
+		{Run: "scripts/smoke.sh"},
+		{Run: "curl -fsS https://example.com/up", Timeout: 10 * time.Second},
+	}
+	if !reflect.DeepEqual(cfg.PostDeploy, want) || !cfg.PostDeployRollback {
+		t.Errorf("PostDeploy = %+v rollback=%v, want %+v rollback=true", cfg.PostDeploy, cfg.PostDeployRollback, want)
+	}
+
+	for _, bad := range []string{
+		"post_deploy:\n  - run: x\n    timeout: soon\n",
+		"post_deploy:\n  - timeout: 5s\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(bad), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadProjectConfig(dir); err == nil {
+			t.Errorf("LoadProjectConfig(%q) = nil error, want error", bad)
+		}
+	}
+}
+
+func TestPluginsYAML(t *testing.T) {
+	dir := t.TempDir()
+	content := `plugins:
+  on_deploy_success: [scripts/notify.sh]
+  on_command_error:
+    - run: scripts/page.sh
+      timeout: 5s
+  status_extra:
+    - scripts/sidekiq-depth.sh
+`
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil {
+		t.Fatalf("LoadProjectConfig() error: %v", err)
+	}
+	want := Plugins{
+		OnDeploySuccess: []HookCommand{{Run: "scripts/notify.sh"}},
+		OnCommandError:  []HookCommand{{Run: "scripts/page.sh", Timeout: 5 * time.Second}},
+		StatusExtra:     []HookCommand{{Run: "scripts/sidekiq-depth.sh"}},
+	}
+	if !reflect.DeepEqual(cfg.Plugins, want) {
+		t.Errorf("Plugins = %+v, want %+v", cfg.Plugins, want)
+	}
+}
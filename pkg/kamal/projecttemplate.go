@@ -0,0 +1,168 @@
+package kamal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrProjectConfigExists is returned by WriteProjectConfigTemplate when the
+// project already has a .lazykamal.yml and force is not set.
+var ErrProjectConfigExists = errors.New(ProjectConfigFile + " already exists")
+
+// projectOption documents one top-level ProjectConfig key for the template.
+type projectOption struct {
+	Key string
+	Doc []string
+	// Example is the key's YAML. It is written commented out unless
+	// Default is set, in which case it is the value the template sets.
+	Example string
+	Default bool
+}
+
+// projectOptions lists every ProjectConfig key in field order; a test keeps
+// it in step with the struct's yaml tags.
+var projectOptions = []projectOption{
+	{
+		Key:     "hidden_destinations",
+		Doc:     []string{"Destinations hidden from the Apps list (press a to show them)."},
+		Example: "hidden_destinations: [staging-old]",
+	},
+	{
+		Key: "protected_destinations",
+		Doc: []string{
+			"Destinations that need their name typed before any mutating command.",
+			"A destination named production also asks for it before deploys and removals.",
+		},
+		Example: "protected_destinations: [production]",
+		Default: true,
+	},
+	{
+		Key: "event_socket",
+		Doc: []string{
+			"Unix socket (relative to this directory unless absolute) on which",
+			"lazykamal publishes one JSON event per line.",
+		},
+		Example: "event_socket: tmp/lazykamal.sock",
+	},
+	{
+		Key: "post_deploy",
+		Doc: []string{
+			"Commands run from this directory after a deploy or redeploy succeeds,",
+			"as a string or {run, timeout}.",
+		},
+		Example: "post_deploy:\n  - scripts/smoke.sh\n  - run: curl -fsS https://example.com/up\n    timeout: 10s",
+	},
+	{
+		Key:     "post_deploy_rollback",
+		Doc:     []string{"Roll back to the previous version when a post_deploy command fails."},
+		Example: "post_deploy_rollback: false",
+		Default: true,
+	},
+	{
+		Key:     "depends_on",
+		Doc:     []string{"Accessories (or app) and the accessories that must be running first."},
+		Example: "depends_on:\n  app: [db, redis]",
+	},
+	{
+		Key: "commands",
+		Doc: []string{
+			"Per-command default options, keyed like deploy or app_logs:",
+			"skip_hooks, verbose, quiet, primary, roles and lines (logs only).",
+		},
+		Example: "commands:\n  deploy: {verbose: true}\n  app_logs: {lines: 500}",
+	},
+	{
+		Key:     "no_session_summary",
+		Doc:     []string{"Do not print the recap of the session's commands on quit."},
+		Example: "no_session_summary: false",
+		Default: true,
+	},
+	{
+		Key: "plugins",
+		Doc: []string{
+			"Commands run at hook points with a JSON payload on stdin:",
+			"on_deploy_success, on_command_error and status_extra (shown in Live status).",
+		},
+		Example: "plugins:\n  on_deploy_success: [scripts/notify.sh]\n  status_extra:\n    - run: scripts/queue-depth.sh\n      timeout: 3s",
+	},
+}
+
+// ProjectConfigTemplate is a commented .lazykamal.yml documenting every
+// option, with the defaults set and the other examples commented out.
+func ProjectConfigTemplate() string {
+	return projectConfigTemplate(false)
+}
+
+// projectConfigTemplate renders projectOptions; with all set, every example
+// is left uncommented.
+func projectConfigTemplate(all bool) string {
+	var b strings.Builder
+	b.WriteString("# lazykamal project settings, read from the project root next to config/.\n")
+	b.WriteString("# Uncomment an option to use it.\n")
+	for _, o := range projectOptions {
+		b.WriteString("\n")
+		for _, d := range o.Doc {
+			b.WriteString("# " + d + "\n")
+		}
+		for _, l := range strings.Split(o.Example, "\n") {
+			if !o.Default && !all {
+				l = "# " + l
+			}
+			b.WriteString(l + "\n")
+		}
+	}
+	return b.String()
+}
+
+// WriteProjectConfigTemplate writes ProjectConfigTemplate to
+// dir/.lazykamal.yml and returns its path. An existing file is only replaced
+// when force is set.
+func WriteProjectConfigTemplate(dir string, force bool) (string, error) {
+	path := filepath.Join(dir, ProjectConfigFile)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return path, ErrProjectConfigExists
+		}
+		return path, err
+	}
+	if _, err := f.WriteString(ProjectConfigTemplate()); err != nil {
+		f.Close()
+		return path, err
+	}
+	return path, f.Close()
+}
+
+// CheckProjectConfig loads dir/.lazykamal.yml and reports what would be
+// ignored: unknown keys and the CommandWarnings. err is set when the file
+// does not load at all.
+func CheckProjectConfig(dir string) (warnings []string, err error) {
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ProjectConfigFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		var strict ProjectConfig
+		if err := dec.Decode(&strict); err != nil && !errors.Is(err, io.EOF) {
+			warnings = append(warnings, fmt.Sprintf("%s: %v (ignored)", ProjectConfigFile, strings.TrimPrefix(err.Error(), "yaml: ")))
+		}
+	}
+	return append(warnings, cfg.CommandWarnings()...), nil
+}
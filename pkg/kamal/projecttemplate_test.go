@@ -0,0 +1,100 @@
+package kamal
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestProjectOptionsMatchProjectConfig(t *testing.T) {
+	typ := reflect.TypeOf(ProjectConfig{})
+	var keys []string
+	for i := 0; i < typ.NumField(); i++ {
+		keys = append(keys, strings.Split(typ.Field(i).Tag.Get("yaml"), ",")[0])
+	}
+	var documented []string
+	for _, o := range projectOptions {
+		documented = append(documented, o.Key)
+		if len(o.Doc) == 0 || !strings.HasPrefix(o.Example, o.Key+":") {
+			t.Errorf("option %s: needs a doc and an example setting it", o.Key)
+		}
+	}
+	if !reflect.DeepEqual(documented, keys) {
+		t.Errorf("projectOptions keys = %v, ProjectConfig yaml keys = %v", documented, keys)
+	}
+}
+
+func TestProjectConfigTemplateLoads(t *testing.T) {
+	for _, all := range []bool{false, true} {
+		dec := yaml.NewDecoder(bytes.NewReader([]byte(projectConfigTemplate(all))))
+		dec.KnownFields(true)
+		var cfg ProjectConfig
+		if err := dec.Decode(&cfg); err != nil {
+			t.Fatalf("template (all=%v) does not decode strictly: %v", all, err)
+		}
+		if w := cfg.CommandWarnings(); len(w) != 0 {
+			t.Errorf("template (all=%v) warnings: %v", all, w)
+		}
+		if all && (len(cfg.PostDeploy) != 2 || len(cfg.Plugins.StatusExtra) != 1 || cfg.EventSocket == "") {
+			t.Errorf("template examples decoded to %+v", cfg)
+		}
+	}
+}
+
+func TestWriteProjectConfigTemplate(t *testing.T) {
+	dir := t.TempDir()
+	path, err := WriteProjectConfigTemplate(dir, false)
+	if err != nil {
+		t.Fatalf("WriteProjectConfigTemplate() error: %v", err)
+	}
+	if path != filepath.Join(dir, ProjectConfigFile) {
+		t.Errorf("path = %q", path)
+	}
+	cfg, err := LoadProjectConfig(dir)
+	if err != nil || !reflect.DeepEqual(cfg.ProtectedDestinations, []string{"production"}) {
+		t.Errorf("LoadProjectConfig(template) = %+v, %v", cfg, err)
+	}
+
+	if err := os.WriteFile(path, []byte("hidden_destinations: [demo]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteProjectConfigTemplate(dir, false); !errors.Is(err, ErrProjectConfigExists) {
+		t.Errorf("WriteProjectConfigTemplate(existing) error = %v, want ErrProjectConfigExists", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "hidden_destinations: [demo]\n" {
+		t.Errorf("existing file overwritten: %q", data)
+	}
+	if _, err := WriteProjectConfigTemplate(dir, true); err != nil {
+		t.Fatalf("WriteProjectConfigTemplate(force) error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != ProjectConfigTemplate() {
+		t.Error("force did not replace the file with the template")
+	}
+}
+
+func TestCheckProjectConfig(t *testing.T) {
+	dir := t.TempDir()
+	if w, err := CheckProjectConfig(dir); err != nil || len(w) != 0 {
+		t.Errorf("CheckProjectConfig(missing) = %v, %v", w, err)
+	}
+	content := "protected_destinations: [production]\nhiden_destinations: [demo]\ncommands:\n  deplyo: {verbose: true}\n"
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	w, err := CheckProjectConfig(dir)
+	if err != nil || len(w) != 2 || !strings.Contains(w[0], "hiden_destinations") || !strings.Contains(w[1], "deplyo") {
+		t.Errorf("CheckProjectConfig() = %q, %v", w, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ProjectConfigFile), []byte("post_deploy: {"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CheckProjectConfig(dir); err == nil {
+		t.Error("CheckProjectConfig(invalid yaml) = nil error")
+	}
+}
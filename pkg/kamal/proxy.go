@@ -0,0 +1,73 @@
+package kamal
+
+import (
+	"strings"
+)
+
+// ProxyHost is kamal-proxy's state on one host, from `kamal proxy details`.
+type ProxyHost struct {
+	Host    string // "" when kamal printed no host headers (--quiet)
+	Running bool
+	Version string // image tag, e.g. "v0.8.2"; "" when no container is listed
+	Status  string // docker status of the proxy container, "" when none
+}
+
+// ParseProxyDetails parses `kamal proxy details` output: a docker ps table
+// filtered to the kamal-proxy container, under a "Proxy Host:" header per
+// host, or a bare table when kamal ran with --quiet. A host whose table has
+// no kamal-proxy row has no proxy running.
+func ParseProxyDetails(output string) []ProxyHost {
+	var out []ProxyHost
+	cur := -1
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isSSHKitLogLine(line) {
+			continue
+		}
+		if v, ok := cutField(line, "Proxy Host:"); ok {
+			out = append(out, ProxyHost{Host: v})
+			cur = len(out) - 1
+			continue
+		}
+		if strings.HasPrefix(line, "CONTAINER ID") {
+			if cur < 0 {
+				out = append(out, ProxyHost{})
+				cur = 0
+			}
+			continue
+		}
+		if cur < 0 {
+			continue
+		}
+		cols := columnSep.Split(line, -1)
+		if len(cols) < 2 || !isProxyImage(cols[1]) {
+			continue
+		}
+		h := &out[cur]
+		h.Version = proxyImageTag(cols[1])
+		for _, col := range cols[2:] {
+			if strings.HasPrefix(col, "Up ") || strings.HasPrefix(col, "Exited") || strings.HasPrefix(col, "Created") || strings.HasPrefix(col, "Restarting") {
+				h.Status = col
+				break
+			}
+		}
+		h.Running = strings.HasPrefix(h.Status, "Up ")
+	}
+	return out
+}
+
+// isProxyImage matches kamal-proxy images from Docker Hub or a mirror.
+func isProxyImage(image string) bool {
+	repo := image
+	if i := strings.LastIndex(repo, ":"); i > 0 && !strings.Contains(repo[i:], "/") {
+		repo = repo[:i]
+	}
+	return repo == "kamal-proxy" || strings.HasSuffix(repo, "/kamal-proxy")
+}
+
+func proxyImageTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i > 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
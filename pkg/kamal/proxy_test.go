@@ -0,0 +1,49 @@
+package kamal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProxyDetails(t *testing.T) {
+	// Default output: a "Proxy Host:" header per host. web-2's table is
+	// empty because docker ps only lists running containers.
+	byHost := `  INFO [4c5d6e7f] Running docker ps --filter name=^kamal-proxy$ on 10.0.0.1
+  INFO [4c5d6e7f] Finished in 0.312 seconds with exit status 0 (successful).
+Proxy Host: 10.0.0.1
+CONTAINER ID   IMAGE                         COMMAND                  CREATED      STATUS      PORTS                                                                      NAMES
+0b1c2d3e4f5a   basecamp/kamal-proxy:v0.8.2   "kamal-proxy run"        3 days ago   Up 3 days   0.0.0.0:80->80/tcp, [::]:80->80/tcp, 0.0.0.0:443->443/tcp, [::]:443->443/tcp   kamal-proxy
+
+Proxy Host: 10.0.0.2
+CONTAINER ID   IMAGE     COMMAND   CREATED   STATUS    PORTS     NAMES
+`
+	want := []ProxyHost{
+		{Host: "10.0.0.1", Running: true, Version: "v0.8.2", Status: "Up 3 days"},
+		{Host: "10.0.0.2"},
+	}
+	if got := ParseProxyDetails(byHost); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProxyDetails(by host) = %+v, want %+v", got, want)
+	}
+
+	// --quiet output: the bare table, here with an image from a mirror.
+	quiet := `CONTAINER ID   IMAGE                                   COMMAND             CREATED       STATUS        PORTS     NAMES
+0b1c2d3e4f5a   registry.example.com/kamal-proxy:v0.9.0   "kamal-proxy run"   2 hours ago   Up 2 hours              kamal-proxy
+`
+	want = []ProxyHost{{Running: true, Version: "v0.9.0", Status: "Up 2 hours"}}
+	if got := ParseProxyDetails(quiet); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProxyDetails(quiet) = %+v, want %+v", got, want)
+	}
+
+	restarting := `Proxy Host: 10.0.0.1
+CONTAINER ID   IMAGE                         COMMAND             CREATED      STATUS                         PORTS     NAMES
+0b1c2d3e4f5a   basecamp/kamal-proxy:v0.8.2   "kamal-proxy run"   3 days ago   Restarting (1) 5 seconds ago             kamal-proxy
+`
+	want = []ProxyHost{{Host: "10.0.0.1", Version: "v0.8.2", Status: "Restarting (1) 5 seconds ago"}}
+	if got := ParseProxyDetails(restarting); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProxyDetails(restarting) = %+v, want %+v", got, want)
+	}
+
+	if got := ParseProxyDetails("  ERROR (SSHKit::Runner::ExecuteError): connection refused"); got != nil {
+		t.Errorf("ParseProxyDetails(error) = %+v, want nil", got)
+	}
+}
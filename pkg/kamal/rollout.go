@@ -0,0 +1,308 @@
+package kamal
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LockInfo is the parsed output of `kamal lock status`.
+type LockInfo struct {
+	Held    bool
+	Holder  string // "Locked by:" value, e.g. "CI at 2024-05-01T10:00:00Z"
+	Version string // version being deployed under the lock, if reported
+	Message string
+}
+
+// sshkitPrefix is the "DEBUG [a1b2c3d4]" SSHKit puts before a command's
+// captured output when kamal runs with --verbose.
+var sshkitPrefix = regexp.MustCompile(`^(?:DEBUG|INFO) \[[0-9a-f]+\]\s+`)
+
+// ParseLockStatus parses `kamal lock status` output. Kamal prints
+// "There is no deploy lock" (or "No lock") when unlocked, otherwise a
+// "Locked by:" block, which --verbose prefixes with SSHKit's "DEBUG [id]".
+func ParseLockStatus(output string) LockInfo {
+	var info LockInfo
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(sshkitPrefix.ReplaceAllString(strings.TrimSpace(line), ""))
+		if v, ok := cutField(line, "Locked by:"); ok {
+			info.Held = true
+			info.Holder = v
+		} else if v, ok := cutField(line, "Version:"); ok {
+			info.Version = v
+		} else if v, ok := cutField(line, "Message:"); ok {
+			info.Message = v
+		}
+	}
+	return info
+}
+
+// ParseAppVersions parses `kamal app version` output into host -> running version.
+// Kamal prints an "App Host: <host>" header followed by the version on the
+// next line; SSHKit log lines ("INFO", "DEBUG", ...) in between are skipped.
+func ParseAppVersions(output string) map[string]string {
+	versions := map[string]string{}
+	host := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isSSHKitLogLine(line) {
+			continue
+		}
+		if v, ok := cutField(line, "App Host:"); ok {
+			host = v
+			continue
+		}
+		if host != "" {
+			versions[host] = line
+			host = ""
+		}
+	}
+	return versions
+}
+
+// HostContainer is one row of `kamal app containers` output.
+type HostContainer struct {
+	Host   string `json:"host"`
+	ID     string `json:"id"`
+	Image  string `json:"image"`
+	Status string `json:"status"`         // docker status, e.g. "Up 12 seconds"
+	Name   string `json:"name,omitempty"` // e.g. myapp-web-9f8e7d6c
+}
+
+// Running reports whether docker lists the container as up.
+func (c HostContainer) Running() bool {
+	return strings.HasPrefix(c.Status, "Up ")
+}
+
+// State is docker's state word for Status: "running", "exited", "created",
+// "restarting", "paused", or "" when Status is not one docker prints.
+func (c HostContainer) State() string {
+	switch {
+	case strings.Contains(c.Status, "(Paused)"):
+		return "paused"
+	case c.Running():
+		return "running"
+	}
+	for _, state := range []string{"Exited", "Created", "Restarting", "Dead", "Removal"} {
+		if strings.HasPrefix(c.Status, state) {
+			return strings.ToLower(state)
+		}
+	}
+	return ""
+}
+
+// Tag is the image tag, the app version for app containers, or "" for an
+// untagged image.
+func (c HostContainer) Tag() string {
+	i := strings.LastIndex(c.Image, ":")
+	if i < 0 || strings.Contains(c.Image[i:], "/") {
+		return ""
+	}
+	return c.Image[i+1:]
+}
+
+var columnSep = regexp.MustCompile(`\s{2,}`)
+
+// ParseAppContainers parses `kamal app containers` output: an "App Host:"
+// header per host followed by a docker ps table.
+func ParseAppContainers(output string) []HostContainer {
+	var out []HostContainer
+	host := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isSSHKitLogLine(line) || strings.HasPrefix(line, "CONTAINER ID") {
+			continue
+		}
+		if v, ok := cutField(line, "App Host:"); ok {
+			host = v
+			continue
+		}
+		if host == "" {
+			continue
+		}
+		cols := columnSep.Split(line, -1)
+		if len(cols) < 2 {
+			continue
+		}
+		c := HostContainer{Host: host, ID: cols[0], Image: cols[1]}
+		for i, col := range cols[2:] {
+			if c.Status == "" && containerStatus(col) {
+				c.Status = col
+				continue
+			}
+			// NAMES is the last column, after STATUS.
+			if c.Status != "" && i == len(cols)-3 {
+				c.Name = col
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// containerStatus reports whether col is a docker ps STATUS column.
+func containerStatus(col string) bool {
+	for _, p := range []string{"Up ", "Exited", "Created", "Restarting", "Dead", "Removal"} {
+		if strings.HasPrefix(col, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// LastAuditEntry returns the most recent entry from `kamal audit` output.
+// Audit entries start with a "[timestamp]" prefix.
+func LastAuditEntry(output string) string {
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "[") {
+			return line
+		}
+	}
+	return ""
+}
+
+func cutField(line, prefix string) (string, bool) {
+	if !strings.HasPrefix(line, prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, prefix)), true
+}
+
+func isSSHKitLogLine(line string) bool {
+	for _, p := range []string{"INFO ", "DEBUG ", "WARN ", "ERROR "} {
+		if strings.HasPrefix(line, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// RolloutPhase is the state of a deploy rollout as seen from outside.
+type RolloutPhase int
+
+const (
+	RolloutPending     RolloutPhase = iota // no host runs the target version yet
+	RolloutProgressing                     // some hosts run the target version
+	RolloutConverged                       // all hosts run the target version, lock still held
+	RolloutSucceeded                       // lock released with all hosts on the target version
+	RolloutFailed                          // lock released without convergence
+)
+
+func (p RolloutPhase) String() string {
+	switch p {
+	case RolloutPending:
+		return "pending"
+	case RolloutProgressing:
+		return "progressing"
+	case RolloutConverged:
+		return "converged"
+	case RolloutSucceeded:
+		return "succeeded"
+	case RolloutFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// RolloutObservation is one poll of the remote state.
+type RolloutObservation struct {
+	LockHeld bool
+	Versions map[string]string // host -> running version
+}
+
+// Rollout tracks convergence of hosts onto a target version. It never acts on
+// the servers; callers feed it observations and report phase changes.
+type Rollout struct {
+	Target   string // version being rolled out; empty if the lock did not report one
+	Phase    RolloutPhase
+	OnTarget int
+	Total    int
+}
+
+// NewRollout starts tracking a rollout of target.
+func NewRollout(target string) *Rollout {
+	return &Rollout{Target: target}
+}
+
+// Observe advances the state machine with a new observation and reports
+// whether the phase changed. Terminal phases are sticky.
+func (r *Rollout) Observe(o RolloutObservation) bool {
+	if r.Done() {
+		return false
+	}
+	r.Total = len(o.Versions)
+	r.OnTarget = 0
+	for _, v := range o.Versions {
+		if r.Target != "" && v == r.Target {
+			r.OnTarget++
+		}
+	}
+	allOnTarget := r.Total > 0 && r.OnTarget == r.Total
+
+	prev := r.Phase
+	switch {
+	case !o.LockHeld && r.Target == "":
+		// Without a known target, a released lock with a single version
+		// everywhere is the best available signal of success.
+		if v, ok := singleVersion(o.Versions); ok {
+			r.Target = v
+			r.OnTarget = r.Total
+			r.Phase = RolloutSucceeded
+		} else {
+			r.Phase = RolloutFailed
+		}
+	case !o.LockHeld && allOnTarget:
+		r.Phase = RolloutSucceeded
+	case !o.LockHeld:
+		r.Phase = RolloutFailed
+	case allOnTarget:
+		r.Phase = RolloutConverged
+	case r.OnTarget > 0:
+		r.Phase = RolloutProgressing
+	default:
+		r.Phase = RolloutPending
+	}
+	return r.Phase != prev
+}
+
+// Done reports whether the rollout reached a terminal phase.
+func (r *Rollout) Done() bool {
+	return r.Phase == RolloutSucceeded || r.Phase == RolloutFailed
+}
+
+// Summary returns a one-line progress description, e.g. "progressing: 2/3 hosts on abc123".
+func (r *Rollout) Summary() string {
+	target := r.Target
+	if target == "" {
+		target = "new version"
+	}
+	return fmt.Sprintf("%s: %d/%d hosts on %s", r.Phase, r.OnTarget, r.Total, target)
+}
+
+// CheckConvergence reports whether every host in versions runs target, and
+// otherwise which hosts, sorted, run something else. No hosts at all is not
+// convergence.
+func CheckConvergence(target string, versions map[string]string) (converged bool, off []string) {
+	for host, v := range versions {
+		if v != target {
+			off = append(off, host)
+		}
+	}
+	sort.Strings(off)
+	return target != "" && len(versions) > 0 && len(off) == 0, off
+}
+
+func singleVersion(versions map[string]string) (string, bool) {
+	found := ""
+	for _, v := range versions {
+		if found != "" && v != found {
+			return "", false
+		}
+		found = v
+	}
+	return found, found != ""
+}
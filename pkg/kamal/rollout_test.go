@@ -0,0 +1,199 @@
+package kamal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLockStatus(t *testing.T) {
+	held := `  INFO [1a2b3c4d] Running /usr/bin/env mkdir -p .kamal on 10.0.0.1
+Locked by: CI at 2024-05-01T10:00:00Z
+Version: 9f8e7d6c
+Message: Automatic deploy lock`
+	got := ParseLockStatus(held)
+	want := LockInfo{Held: true, Holder: "CI at 2024-05-01T10:00:00Z", Version: "9f8e7d6c", Message: "Automatic deploy lock"}
+	if got != want {
+		t.Errorf("ParseLockStatus(held) = %+v, want %+v", got, want)
+	}
+
+	for _, unlocked := range []string{"There is no deploy lock", "No lock", "  INFO [1a2b3c4d] Running /usr/bin/env stat .kamal/lock-myapp on 10.0.0.1\nNo lock"} {
+		if got := ParseLockStatus(unlocked); got.Held {
+			t.Errorf("ParseLockStatus(%q) = %+v, want not held", unlocked, got)
+		}
+	}
+
+	verbose := `  INFO [1a2b3c4d] Running /usr/bin/env stat .kamal/lock-myapp on 10.0.0.1
+ DEBUG [1a2b3c4d] 	Locked by: CI at 2024-05-01T10:00:00Z
+ DEBUG [1a2b3c4d] 	Version: 9f8e7d6c
+ DEBUG [1a2b3c4d] 	Message: Automatic deploy lock`
+	if got := ParseLockStatus(verbose); got != want {
+		t.Errorf("ParseLockStatus(verbose) = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAppVersions(t *testing.T) {
+	output := `  INFO [aa11] Running docker ps --latest on 10.0.0.1
+  INFO [aa11] Finished in 0.5 seconds with exit status 0 (successful).
+App Host: 10.0.0.1
+9f8e7d6c
+
+  INFO [bb22] Running docker ps --latest on 10.0.0.2
+App Host: 10.0.0.2
+1a2b3c4d
+`
+	want := map[string]string{"10.0.0.1": "9f8e7d6c", "10.0.0.2": "1a2b3c4d"}
+	if got := ParseAppVersions(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAppVersions() = %v, want %v", got, want)
+	}
+}
+
+func TestParseAppContainers(t *testing.T) {
+	output := `  INFO [dd44] Running docker container ls --all --filter label=service=myapp on 10.0.0.1
+App Host: 10.0.0.1
+CONTAINER ID   IMAGE                     COMMAND                  CREATED          STATUS                      PORTS     NAMES
+3f2a1b0c9d8e   registry/myapp:9f8e7d6c   "/rails/bin/docker-e…"   15 seconds ago   Up 12 seconds (healthy)     3000/tcp  myapp-web-9f8e7d6c
+7a6b5c4d3e2f   registry/myapp:1a2b3c4d   "/rails/bin/docker-e…"   2 days ago       Exited (0) 10 seconds ago             myapp-web-1a2b3c4d
+App Host: 10.0.0.2
+CONTAINER ID   IMAGE                     COMMAND                  CREATED          STATUS                      PORTS     NAMES
+aa11bb22cc33   registry/myapp:9f8e7d6c   "/rails/bin/docker-e…"   14 seconds ago   Up About a minute           3000/tcp  myapp-web-9f8e7d6c
+`
+	got := ParseAppContainers(output)
+	want := []HostContainer{
+		{Host: "10.0.0.1", ID: "3f2a1b0c9d8e", Image: "registry/myapp:9f8e7d6c", Status: "Up 12 seconds (healthy)", Name: "myapp-web-9f8e7d6c"},
+		{Host: "10.0.0.1", ID: "7a6b5c4d3e2f", Image: "registry/myapp:1a2b3c4d", Status: "Exited (0) 10 seconds ago", Name: "myapp-web-1a2b3c4d"},
+		{Host: "10.0.0.2", ID: "aa11bb22cc33", Image: "registry/myapp:9f8e7d6c", Status: "Up About a minute", Name: "myapp-web-9f8e7d6c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAppContainers() =\n  %+v\nwant\n  %+v", got, want)
+	}
+	if !got[0].Running() || got[1].Running() {
+		t.Errorf("Running() = %v/%v, want true/false", got[0].Running(), got[1].Running())
+	}
+}
+
+// kamal2ContainersOutput is `kamal app containers` from kamal 2.4 on two
+// hosts with web and job roles, a registry on a port and a stuck container.
+const kamal2ContainersOutput = `  INFO [5b1e0c2a] Running docker container ls --all --filter label=service=myapp --filter label=destination=staging --filter label=role=web on 10.0.1.5
+  INFO [5b1e0c2a] Finished in 0.311 seconds with exit status 0 (successful).
+App Host: 10.0.1.5
+CONTAINER ID   IMAGE                                        COMMAND                  CREATED        STATUS                    PORTS      NAMES
+c0ffee123456   registry.example.com:5000/myapp:4f1c2d9e     "/rails/bin/docker-e…"   3 hours ago    Up 3 hours (healthy)      80/tcp     myapp-web-staging-4f1c2d9e
+0badc0de7890   registry.example.com:5000/myapp:88aa77bb     "/rails/bin/docker-e…"   2 days ago     Exited (137) 3 hours ago             myapp-web-staging-88aa77bb
+
+  INFO [77d0f1e2] Running docker container ls --all --filter label=service=myapp --filter label=destination=staging --filter label=role=job on 10.0.1.6
+App Host: 10.0.1.6
+CONTAINER ID   IMAGE                                        COMMAND                  CREATED        STATUS                         PORTS     NAMES
+deadbeef0001   registry.example.com:5000/myapp:4f1c2d9e     "/rails/bin/jobs"        3 hours ago    Restarting (1) 4 seconds ago             myapp-job-staging-4f1c2d9e
+feedface0002   registry.example.com:5000/myapp              "/rails/bin/jobs"        5 days ago     Up 5 days (Paused)                       myapp-job-staging-latest
+`
+
+func TestParseAppContainersKamal2(t *testing.T) {
+	got := ParseAppContainers(kamal2ContainersOutput)
+	want := []struct{ host, name, state, tag string }{
+		{"10.0.1.5", "myapp-web-staging-4f1c2d9e", "running", "4f1c2d9e"},
+		{"10.0.1.5", "myapp-web-staging-88aa77bb", "exited", "88aa77bb"},
+		{"10.0.1.6", "myapp-job-staging-4f1c2d9e", "restarting", "4f1c2d9e"},
+		{"10.0.1.6", "myapp-job-staging-latest", "paused", ""},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseAppContainers() = %+v, want %d rows", got, len(want))
+	}
+	for i, w := range want {
+		c := got[i]
+		if c.Host != w.host || c.Name != w.name || c.State() != w.state || c.Tag() != w.tag {
+			t.Errorf("row %d = %s %s %q %q, want %s %s %q %q", i, c.Host, c.Name, c.State(), c.Tag(), w.host, w.name, w.state, w.tag)
+		}
+	}
+	if rows := ParseAppContainers("ERROR: config/deploy.yml not found"); rows != nil {
+		t.Errorf("unrelated output parsed as %+v", rows)
+	}
+}
+
+func TestLastAuditEntry(t *testing.T) {
+	output := `  INFO [cc33] Running tail -n 50 .kamal/app-audit.log on 10.0.0.1
+[2024-05-01T09:58:12Z] [ci] Pushed app version 9f8e7d6c
+[2024-05-01T10:00:01Z] [ci] Booted app version 9f8e7d6c
+`
+	if got, want := LastAuditEntry(output), "[2024-05-01T10:00:01Z] [ci] Booted app version 9f8e7d6c"; got != want {
+		t.Errorf("LastAuditEntry() = %q, want %q", got, want)
+	}
+	if got := LastAuditEntry("no entries"); got != "" {
+		t.Errorf("LastAuditEntry() = %q, want empty", got)
+	}
+}
+
+func TestRolloutObserve(t *testing.T) {
+	r := NewRollout("new")
+	steps := []struct {
+		obs         RolloutObservation
+		wantPhase   RolloutPhase
+		wantChanged bool
+	}{
+		{RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "old", "b": "old"}}, RolloutPending, false},
+		{RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "new", "b": "old"}}, RolloutProgressing, true},
+		{RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "new", "b": "old"}}, RolloutProgressing, false},
+		{RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "new", "b": "new"}}, RolloutConverged, true},
+		{RolloutObservation{LockHeld: false, Versions: map[string]string{"a": "new", "b": "new"}}, RolloutSucceeded, true},
+		{RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "old"}}, RolloutSucceeded, false},
+	}
+	for i, s := range steps {
+		changed := r.Observe(s.obs)
+		if r.Phase != s.wantPhase || changed != s.wantChanged {
+			t.Errorf("step %d: phase=%s changed=%v, want phase=%s changed=%v", i, r.Phase, changed, s.wantPhase, s.wantChanged)
+		}
+	}
+	if got, want := r.Summary(), "succeeded: 2/2 hosts on new"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func TestRolloutFailedWhenLockReleasedEarly(t *testing.T) {
+	r := NewRollout("new")
+	r.Observe(RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "new", "b": "old"}})
+	r.Observe(RolloutObservation{LockHeld: false, Versions: map[string]string{"a": "new", "b": "old"}})
+	if r.Phase != RolloutFailed || !r.Done() {
+		t.Errorf("phase = %s, want failed", r.Phase)
+	}
+}
+
+func TestRolloutUnknownTarget(t *testing.T) {
+	r := NewRollout("")
+	r.Observe(RolloutObservation{LockHeld: true, Versions: map[string]string{"a": "old"}})
+	if r.Phase != RolloutPending {
+		t.Errorf("phase = %s, want pending while lock held", r.Phase)
+	}
+	r.Observe(RolloutObservation{LockHeld: false, Versions: map[string]string{"a": "v2", "b": "v2"}})
+	if r.Phase != RolloutSucceeded || r.Target != "v2" {
+		t.Errorf("phase = %s target = %q, want succeeded on v2", r.Phase, r.Target)
+	}
+
+	mixed := NewRollout("")
+	mixed.Observe(RolloutObservation{LockHeld: false, Versions: map[string]string{"a": "v1", "b": "v2"}})
+	if mixed.Phase != RolloutFailed {
+		t.Errorf("phase = %s, want failed for mixed versions", mixed.Phase)
+	}
+}
+
+func TestCheckConvergence(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    string
+		versions  map[string]string
+		converged bool
+		off       []string
+	}{
+		{"all on target", "a1b2c3", map[string]string{"10.0.0.1": "a1b2c3", "10.0.0.2": "a1b2c3"}, true, nil},
+		{"some off", "a1b2c3", map[string]string{"10.0.0.3": "9f8e7d", "10.0.0.1": "a1b2c3", "10.0.0.2": "9f8e7d"}, false, []string{"10.0.0.2", "10.0.0.3"}},
+		{"none on target", "a1b2c3", map[string]string{"10.0.0.1": "9f8e7d"}, false, []string{"10.0.0.1"}},
+		{"no hosts", "a1b2c3", map[string]string{}, false, nil},
+		{"no target", "", map[string]string{"10.0.0.1": "a1b2c3"}, false, []string{"10.0.0.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converged, off := CheckConvergence(tt.target, tt.versions)
+			if converged != tt.converged || !reflect.DeepEqual(off, tt.off) {
+				t.Errorf("CheckConvergence(%q, %v) = %v, %q; want %v, %q", tt.target, tt.versions, converged, off, tt.converged, tt.off)
+			}
+		})
+	}
+}
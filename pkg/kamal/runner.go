@@ -6,12 +6,19 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/shuvro/lazykamal/pkg/ssh"
 )
 
-// DefaultCommandTimeout is the maximum time a blocking kamal command may run.
+// DefaultCommandTimeout is the maximum time a kamal command may run unless
+// CommandTimeout gives it another limit.
 const DefaultCommandTimeout = 10 * time.Minute
 
 // RunOptions are common options for Kamal CLI.
@@ -26,6 +33,50 @@ type RunOptions struct {
 	SkipHooks   bool
 	Verbose     bool
 	Quiet       bool
+	// Lines limits log output (--lines); only logs commands accept it.
+	Lines int
+	// Defaults are the per-command defaults from .lazykamal.yml, merged in
+	// by ForCommand when the command runs.
+	Defaults map[string]CommandDefaults
+	// OnRun, when set, is called with the full argv and the flags of it
+	// taken from Defaults (often none) before every command runs.
+	OnRun func(args, fromDefaults []string)
+	// Env, when set, is called as every command starts; the KEY=VALUE
+	// variables it returns are added to that command's environment only.
+	Env func() []string
+	// Runner runs the commands; nil runs the kamal on PATH (ExecRunner).
+	Runner Runner
+}
+
+// Runner runs a kamal argv as built from a subcommand and RunOptions. The
+// package's Run functions resolve the argv, timeout and cancellation and
+// hand the command to opts.Runner. ExecRunner is the default; FakeRunner
+// serves canned output for --demo and tests.
+type Runner interface {
+	// Run runs args until it exits or ctx is done. A non-zero exit is
+	// reported in Result.ExitCode, not as an error.
+	Run(ctx context.Context, args []string, opts RunOptions) (Result, error)
+	// RunStream is Run, passing every stdout and stderr line to onLine as
+	// it arrives, one call at a time. Result.Stdout holds them all.
+	RunStream(ctx context.Context, args []string, opts RunOptions, onLine func(line string)) (Result, error)
+}
+
+// runner is the Runner opts asks for.
+func runner(opts RunOptions) Runner {
+	if opts.Runner == nil {
+		return ExecRunner{}
+	}
+	return opts.Runner
+}
+
+// ExitError is a streamed command's non-zero exit, as RunKamalStream
+// returns it.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
 }
 
 // Result holds stdout, stderr and exit code.
@@ -33,6 +84,9 @@ type Result struct {
 	Stdout   string
 	Stderr   string
 	ExitCode int
+	// Streamed is set when each line was already passed to an onLine
+	// callback; Stdout then holds stdout and stderr as they interleaved.
+	Streamed bool
 }
 
 func (r Result) Combined() string {
@@ -71,145 +125,253 @@ func buildGlobalArgs(opts RunOptions) []string {
 	if opts.Quiet {
 		args = append(args, "--quiet")
 	}
+	if opts.Lines > 0 {
+		args = append(args, "--lines", strconv.Itoa(opts.Lines))
+	}
 	return args
 }
 
-// RunKamal runs the kamal CLI with the given subcommand and options.
+// CommandString is the command line subcommand runs as with opts, quoted
+// for a POSIX shell so it can be pasted into a terminal, e.g.
+// `kamal deploy --destination staging`. It does not call opts.OnRun.
+func CommandString(subcommand []string, opts RunOptions) string {
+	args, _ := resolveArgs(subcommand, opts)
+	return CommandLine(args)
+}
+
+// CommandLine quotes a kamal argv as returned to RunOptions.OnRun.
+func CommandLine(args []string) string {
+	return strings.TrimSuffix("kamal "+ssh.QuoteArgs(args...), " ")
+}
+
+// RunKamal runs the kamal CLI with the given subcommand and options,
+// limited to the subcommand's CommandTimeout.
 func RunKamal(subcommand []string, opts RunOptions) (Result, error) {
-	// Kamal expects: kamal <subcommand> [options]
-	args := append(subcommand, buildGlobalArgs(opts)...)
-	cmd := exec.Command("kamal", args...)
-	cmd.Dir = opts.Cwd
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	err := cmd.Run()
-	code := 0
-	if exitErr, ok := err.(*exec.ExitError); ok {
-		code = exitErr.ExitCode()
-		// Non-zero exit is not an error for us - we capture it in ExitCode
-	} else if err != nil {
-		return Result{}, err
-	}
-	return Result{
-		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
-		ExitCode: code,
-	}, nil
+	ctx, cancel := commandContext(subcommand, nil)
+	defer cancel()
+	return RunKamalContext(ctx, subcommand, opts)
 }
 
-// RunKamalWithStop runs the kamal CLI with cancellation support (via stopCh) and
-// a 10-minute timeout. If stopCh is closed, the command is killed immediately.
-// If stopCh is nil, only the timeout applies.
+// RunKamalWithStop is RunKamal with cancellation support: if stopCh is
+// closed, the command is killed immediately. If stopCh is nil, only the
+// timeout applies.
 func RunKamalWithStop(subcommand []string, opts RunOptions, stopCh <-chan struct{}) (Result, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultCommandTimeout)
+	ctx, cancel := commandContext(subcommand, stopCh)
 	defer cancel()
-
-	// If a stop channel is provided, wire it into the context.
-	if stopCh != nil {
-		go func() {
-			select {
-			case <-stopCh:
-				cancel()
-			case <-ctx.Done():
-			}
-		}()
+	return RunKamalContext(ctx, subcommand, opts)
+}
+
+// RunKamalContext runs the kamal CLI until it exits or ctx is done. Past
+// ctx's deadline it returns a *TimeoutError; when ctx is cancelled, a
+// "command cancelled" error. A non-zero exit is not an error: it is
+// reported in Result.ExitCode.
+func RunKamalContext(ctx context.Context, subcommand []string, opts RunOptions) (Result, error) {
+	limit := contextLimit(ctx)
+	res, err := runner(opts).Run(ctx, commandArgs(subcommand, opts), opts)
+	if err := contextError(ctx, limit); err != nil {
+		res.ExitCode = -1
+		return res, err
 	}
+	return res, err
+}
+
+// ExecRunner runs the kamal on PATH in opts.Cwd.
+type ExecRunner struct{}
 
-	args := append(subcommand, buildGlobalArgs(opts)...)
+// Run implements Runner.
+func (ExecRunner) Run(ctx context.Context, args []string, opts RunOptions) (Result, error) {
 	cmd := exec.CommandContext(ctx, "kamal", args...)
 	cmd.Dir = opts.Cwd
+	cmd.Env = commandEnv(opts)
+	// ssh processes kamal started may hold the output pipes after kamal
+	// itself is killed.
+	cmd.WaitDelay = killWaitDelay
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	err := cmd.Run()
 
-	if ctx.Err() == context.DeadlineExceeded {
-		return Result{
-			Stdout:   stdout.String(),
-			Stderr:   stderr.String(),
-			ExitCode: -1,
-		}, fmt.Errorf("command timed out after %s", DefaultCommandTimeout)
-	}
-	if ctx.Err() == context.Canceled {
-		return Result{
-			Stdout:   stdout.String(),
-			Stderr:   stderr.String(),
-			ExitCode: -1,
-		}, fmt.Errorf("command cancelled")
-	}
-
-	code := 0
+	res := Result{Stdout: stdout.String(), Stderr: stderr.String()}
 	if exitErr, ok := err.(*exec.ExitError); ok {
-		code = exitErr.ExitCode()
+		res.ExitCode = exitErr.ExitCode()
 	} else if err != nil {
 		return Result{}, err
 	}
-	return Result{
-		Stdout:   stdout.String(),
-		Stderr:   stderr.String(),
-		ExitCode: code,
-	}, nil
+	return res, nil
 }
 
-// RunKamalStream runs kamal with the given subcommand and streams stdout+stderr
-// line-by-line to onLine. It returns when the command exits or stopCh is closed.
-// onLine is called from a goroutine; the caller may use it to update UI (e.g. append to log).
-func RunKamalStream(subcommand []string, opts RunOptions, onLine func(line string), stopCh <-chan struct{}) error {
-	// Kamal expects: kamal <subcommand> [options]
-	args := append(subcommand, buildGlobalArgs(opts)...)
-	cmd := exec.Command("kamal", args...)
+// RunStream implements Runner.
+func (ExecRunner) RunStream(ctx context.Context, args []string, opts RunOptions, onLine func(line string)) (Result, error) {
+	cmd := exec.CommandContext(ctx, "kamal", args...)
 	cmd.Dir = opts.Cwd
+	cmd.Env = commandEnv(opts)
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return err
+		return Result{}, err
 	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return err
+		return Result{}, err
 	}
 	if err := cmd.Start(); err != nil {
-		return err
+		return Result{}, err
 	}
 
+	var mu sync.Mutex
+	var out strings.Builder
+	var readers sync.WaitGroup
 	readLines := func(r io.Reader) {
-		sc := bufio.NewScanner(r)
-		for sc.Scan() {
-			select {
-			case <-stopCh:
-				return
-			default:
-				onLine(sc.Text())
-			}
-		}
+		defer readers.Done()
+		scanLines(r, func(line string) {
+			mu.Lock()
+			defer mu.Unlock()
+			out.WriteString(line)
+			out.WriteByte('\n')
+			onLine(line)
+		})
 	}
+	readers.Add(2)
 	go readLines(stdout)
 	go readLines(stderr)
-	done := make(chan struct{})
+	// Wait closes the pipes, so all output is read first.
+	readers.Wait()
+	err = cmd.Wait()
+
+	res := Result{Stdout: out.String(), Streamed: true}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		res.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// commandEnv is the environment a command runs in: nil, inheriting ours,
+// unless opts.Env adds variables to it.
+func commandEnv(opts RunOptions) []string {
+	if opts.Env == nil {
+		return nil
+	}
+	extra := opts.Env()
+	if len(extra) == 0 {
+		return nil
+	}
+	return append(os.Environ(), extra...)
+}
+
+// killWaitDelay is how long a killed command's output is still read.
+const killWaitDelay = 2 * time.Second
+
+// contextError is why ctx ended a command early, or nil if it did not.
+func contextError(ctx context.Context, limit time.Duration) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return &TimeoutError{After: limit}
+	case context.Canceled:
+		return fmt.Errorf("command cancelled")
+	}
+	return nil
+}
+
+// RunKamalStream runs kamal with the given subcommand and streams stdout+stderr
+// line-by-line to onLine, with no timeout. It returns when the command exits
+// or stopCh is closed; a failed command returns an *ExitError, a stopped one nil.
+// onLine is called from a goroutine; the caller may use it to update UI (e.g. append to log).
+func RunKamalStream(subcommand []string, opts RunOptions, onLine func(line string), stopCh <-chan struct{}) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	go func() {
-		cmd.Wait()
-		close(done)
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
 	}()
+	res, err := runner(opts).RunStream(ctx, commandArgs(subcommand, opts), opts, func(line string) {
+		select {
+		case <-stopCh:
+		default:
+			onLine(line)
+		}
+	})
 	select {
-	case <-done:
-		return nil
 	case <-stopCh:
-		if cmd.Process != nil {
-			_ = cmd.Process.Kill()
-		}
-		<-done
 		return nil
+	default:
+	}
+	if err != nil {
+		return err
+	}
+	if res.ExitCode != 0 {
+		return &ExitError{Code: res.ExitCode}
+	}
+	return nil
+}
+
+// RunKamalStreamWithStop is RunKamalWithStop for long-running commands such
+// as deploy: every stdout and stderr line is passed to onLine as it arrives,
+// one call at a time, and the Result (with Streamed set) holds them all.
+func RunKamalStreamWithStop(subcommand []string, opts RunOptions, onLine func(line string), stopCh <-chan struct{}) (Result, error) {
+	ctx, cancel := commandContext(subcommand, stopCh)
+	defer cancel()
+	limit := contextLimit(ctx)
+	res, err := runner(opts).RunStream(ctx, commandArgs(subcommand, opts), opts, onLine)
+	res.Streamed = true
+	if err := contextError(ctx, limit); err != nil {
+		res.ExitCode = -1
+		return res, err
+	}
+	return res, err
+}
+
+// maxOutputLine is the longest output line read whole; longer lines are
+// split rather than stalling the command on a full pipe.
+const maxOutputLine = 4 << 20
+
+// scanLines calls fn for each line read from r until EOF.
+func scanLines(r io.Reader, fn func(line string)) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	var line []byte
+	for {
+		chunk, isPrefix, err := br.ReadLine()
+		if err != nil {
+			if len(line) > 0 {
+				fn(string(line))
+			}
+			return
+		}
+		line = append(line, chunk...)
+		if isPrefix && len(line) < maxOutputLine {
+			continue
+		}
+		fn(string(line))
+		line = line[:0]
 	}
 }
 
 // RunOpts builds RunOptions from CWD and optional destination.
-// Kamal resolves config files automatically from the working directory,
-// so we only need to pass the -d flag for named destinations.
+// Kamal reads config/deploy.yml from the working directory and merges the
+// overlay config/deploy.<name>.yml itself when given -d <name>, so an
+// overlay's own path is never passed. --config-file is only needed when
+// the base config has another name, such as config/deploy.yaml.
 func RunOpts(cwd string, dest *DeployDestination) RunOptions {
 	o := RunOptions{Cwd: cwd}
-	if dest != nil && dest.Name != "" {
+	if dest == nil {
+		return o
+	}
+	if dest.Name != "" {
 		o.Destination = dest.Name
 	}
+	base := dest.BasePath
+	if dest.Name == "" {
+		base = dest.ConfigPath
+	}
+	if base != "" && filepath.Base(base) != "deploy.yml" {
+		o.ConfigFile = base
+		if rel, err := filepath.Rel(cwd, base); err == nil && !strings.HasPrefix(rel, "..") {
+			o.ConfigFile = rel
+		}
+	}
 	return o
 }
 
@@ -239,9 +401,11 @@ func Setup(opts RunOptions) (Result, error) {
 	return RunKamal([]string{"setup"}, opts)
 }
 
-// Remove runs kamal remove.
+// Remove runs kamal remove, tearing down app, proxy and accessories. It
+// passes --confirmed: kamal would otherwise wait for a y/N on stdin, so the
+// caller must have asked already.
 func Remove(opts RunOptions) (Result, error) {
-	return RunKamal([]string{"remove"}, opts)
+	return RunKamal([]string{"remove", "--confirmed"}, opts)
 }
 
 // Prune runs kamal prune.
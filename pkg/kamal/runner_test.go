@@ -1,6 +1,9 @@
 package kamal
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -86,6 +89,13 @@ func TestBuildGlobalArgs(t *testing.T) {
 			},
 			expected: []string{"--quiet"},
 		},
+		{
+			name: "lines",
+			opts: RunOptions{
+				Lines: 500,
+			},
+			expected: []string{"--lines", "500"},
+		},
 		{
 			name: "combined options",
 			opts: RunOptions{
@@ -250,6 +260,40 @@ func TestRunOpts(t *testing.T) {
 			expectedConf: "",
 			expectedDest: "staging",
 		},
+		{
+			name: "overlay on deploy.yml",
+			cwd:  "/project",
+			dest: &DeployDestination{
+				Name:       "staging",
+				ConfigPath: "/project/config/deploy.staging.yml",
+				BasePath:   "/project/config/deploy.yml",
+			},
+			expectedCwd:  "/project",
+			expectedConf: "",
+			expectedDest: "staging",
+		},
+		{
+			name: "deploy.yaml base",
+			cwd:  "/project",
+			dest: &DeployDestination{
+				ConfigPath: "/project/config/deploy.yaml",
+			},
+			expectedCwd:  "/project",
+			expectedConf: "config/deploy.yaml",
+			expectedDest: "",
+		},
+		{
+			name: "overlay on deploy.yaml",
+			cwd:  "/project",
+			dest: &DeployDestination{
+				Name:       "production",
+				ConfigPath: "/project/config/deploy.production.yml",
+				BasePath:   "/project/config/deploy.yaml",
+			},
+			expectedCwd:  "/project",
+			expectedConf: "config/deploy.yaml",
+			expectedDest: "production",
+		},
 	}
 
 	for _, tt := range tests {
@@ -271,6 +315,36 @@ func TestRunOpts(t *testing.T) {
 
 // TestKamalNotInstalled tests behavior when kamal is not available
 // This test is skipped if kamal is installed
+func TestCommandString(t *testing.T) {
+	tests := []struct {
+		name       string
+		subcommand []string
+		opts       RunOptions
+		want       string
+	}{
+		{"plain", []string{"deploy", "--skip-push"}, RunOptions{Destination: "staging"}, "kamal deploy --skip-push --destination staging"},
+		{"space", []string{"app", "exec", "bin/rails runner 'puts 1'"}, RunOptions{}, `kamal app exec 'bin/rails runner '\''puts 1'\'''`},
+		{"empty arg", []string{"server", "exec", ""}, RunOptions{}, "kamal server exec ''"},
+		{"config file with space", []string{"config"}, RunOptions{ConfigFile: "my app/deploy.yml"}, "kamal config --config-file 'my app/deploy.yml'"},
+		{"version dropped", []string{"setup"}, RunOptions{Version: "abc"}, "kamal setup"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			tt.opts.OnRun = func(_, _ []string) { called = true }
+			if got := CommandString(tt.subcommand, tt.opts); got != tt.want {
+				t.Errorf("CommandString() = %s, want %s", got, tt.want)
+			}
+			if called {
+				t.Error("CommandString() called OnRun")
+			}
+		})
+	}
+	if got := CommandLine(nil); got != "kamal" {
+		t.Errorf("CommandLine(nil) = %q", got)
+	}
+}
+
 func TestKamalNotInstalled(t *testing.T) {
 	// Save original PATH and set to empty to simulate kamal not found
 	// This is a bit invasive so we skip in normal runs
@@ -383,3 +457,64 @@ func TestNewCommandBuilding(t *testing.T) {
 		})
 	}
 }
+
+// TestRunOptsLayouts checks the global flags for each destination of a
+// project as FindDeployConfigs discovers it.
+func TestRunOptsLayouts(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  map[string][]string // destination name -> global args
+	}{
+		{
+			name:  "base only",
+			files: []string{"deploy.yml"},
+			want:  map[string][]string{"": nil},
+		},
+		{
+			name:  "staging and production overlays",
+			files: []string{"deploy.yml", "deploy.staging.yml", "deploy.production.yml"},
+			want: map[string][]string{
+				"staging":    {"--destination", "staging"},
+				"production": {"--destination", "production"},
+			},
+		},
+		{
+			name:  "yaml extension, base only",
+			files: []string{"deploy.yaml"},
+			want:  map[string][]string{"": {"--config-file", "config/deploy.yaml"}},
+		},
+		{
+			name:  "yaml extension with overlays",
+			files: []string{"deploy.yaml", "deploy.staging.yml", "deploy.production.yml"},
+			want: map[string][]string{
+				"staging":    {"--config-file", "config/deploy.yaml", "--destination", "staging"},
+				"production": {"--config-file", "config/deploy.yaml", "--destination", "production"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(dir, "config"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range tt.files {
+				if err := os.WriteFile(filepath.Join(dir, "config", f), []byte("service: app\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dests, err := FindDeployConfigs(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string][]string{}
+			for i := range dests {
+				got[dests[i].Name] = buildGlobalArgs(RunOpts(dir, &dests[i]))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("global args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
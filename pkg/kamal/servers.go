@@ -0,0 +1,176 @@
+package kamal
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServerHost is one host from the servers section with the roles it serves.
+type ServerHost struct {
+	Host  string
+	Roles []string
+}
+
+// Servers returns the hosts configured for the destination, in config order.
+// Destination overlays often omit servers, so the base config is used as a
+// fallback.
+func (d *DeployDestination) Servers() []ServerHost {
+	if servers, ok := d.Config["servers"]; ok {
+		return parseServers(servers)
+	}
+	base := d.baseConfig()
+	if base == nil {
+		return nil
+	}
+	return parseServers(base["servers"])
+}
+
+// baseConfig reads the shared deploy.yml under a destination overlay, or
+// returns nil when there is none or it cannot be parsed.
+func (d *DeployDestination) baseConfig() map[string]interface{} {
+	if d.BasePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(d.BasePath)
+	if err != nil {
+		return nil
+	}
+	var base map[string]interface{}
+	if err := yaml.Unmarshal(data, &base); err != nil {
+		return nil
+	}
+	return base
+}
+
+// parseServers handles Kamal's servers forms: a plain host list (role web),
+// a role -> host list map, and a role -> {hosts: [...]} map. Hosts may carry
+// tags ("- 1.2.3.4: [tag]"); tags are ignored.
+func parseServers(v interface{}) []ServerHost {
+	var order []string
+	roles := map[string][]string{}
+	add := func(role string, hosts interface{}) {
+		list, _ := hosts.([]interface{})
+		for _, h := range list {
+			host := ""
+			switch h := h.(type) {
+			case string:
+				host = h
+			case map[string]interface{}:
+				for k := range h {
+					host = k
+				}
+			}
+			if host == "" {
+				continue
+			}
+			if _, seen := roles[host]; !seen {
+				order = append(order, host)
+			}
+			roles[host] = append(roles[host], role)
+		}
+	}
+
+	switch v := v.(type) {
+	case []interface{}:
+		add("web", v)
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			switch r := v[name].(type) {
+			case []interface{}:
+				add(name, r)
+			case map[string]interface{}:
+				add(name, r["hosts"])
+			}
+		}
+	}
+
+	out := make([]ServerHost, 0, len(order))
+	for _, h := range order {
+		out = append(out, ServerHost{Host: h, Roles: roles[h]})
+	}
+	return out
+}
+
+// SSHUser returns the user Kamal connects as, from ssh.user (or the older
+// top-level ssh_user), or "" when the config leaves it to ssh.
+func (d *DeployDestination) SSHUser() string {
+	if u := sshSetting(d.Config, "user", "ssh_user"); u != "" {
+		return u
+	}
+	return sshSetting(d.baseConfig(), "user", "ssh_user")
+}
+
+// SSHPort returns ssh.port, or "" for the default.
+func (d *DeployDestination) SSHPort() string {
+	if p := sshSetting(d.Config, "port", ""); p != "" {
+		return p
+	}
+	return sshSetting(d.baseConfig(), "port", "")
+}
+
+// sshSetting reads key from cfg's ssh section, or the top-level legacy key.
+func sshSetting(cfg map[string]interface{}, key, legacy string) string {
+	if section, ok := cfg["ssh"].(map[string]interface{}); ok {
+		if v, ok := section[key]; ok && v != nil {
+			return fmt.Sprint(v)
+		}
+	}
+	if legacy != "" {
+		if v, ok := cfg[legacy].(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// ServerRoles returns the roles the servers serve, sorted, for --roles.
+func ServerRoles(servers []ServerHost) []string {
+	seen := map[string]bool{}
+	var roles []string
+	for _, s := range servers {
+		for _, r := range s.Roles {
+			if !seen[r] {
+				seen[r] = true
+				roles = append(roles, r)
+			}
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+var hostnameLabel = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateHost checks that s is a bare IPv4/IPv6 address or hostname suitable
+// for --hosts. Commas, whitespace, users and ports are rejected.
+func ValidateHost(s string) error {
+	if s == "" {
+		return fmt.Errorf("host is empty")
+	}
+	if strings.ContainsAny(s, ", \t\n@") {
+		return fmt.Errorf("invalid host %q: use a single hostname or IP", s)
+	}
+	if net.ParseIP(s) != nil {
+		return nil
+	}
+	if len(s) > 253 {
+		return fmt.Errorf("invalid host %q: too long", s)
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		if !hostnameLabel.MatchString(label) {
+			return fmt.Errorf("invalid host %q: not a hostname or IP", s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func serversFrom(t *testing.T, src string) []ServerHost {
+	t.Helper()
+	var cfg map[string]interface{}
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatalf("yaml: %v", err)
+	}
+	d := DeployDestination{Config: cfg}
+	return d.Servers()
+}
+
+func TestServers(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []ServerHost
+	}{
+		{
+			name: "plain list",
+			yaml: "servers:\n  - 10.0.0.1\n  - 10.0.0.2\n",
+			want: []ServerHost{{"10.0.0.1", []string{"web"}}, {"10.0.0.2", []string{"web"}}},
+		},
+		{
+			name: "roles with hosts and tags",
+			yaml: `servers:
+  web:
+    - 10.0.0.1
+    - 10.0.0.2: [eu]
+  job:
+    hosts:
+      - 10.0.0.1
+      - 10.0.0.3
+    cmd: bin/jobs
+`,
+			want: []ServerHost{
+				{"10.0.0.1", []string{"job", "web"}},
+				{"10.0.0.3", []string{"job"}},
+				{"10.0.0.2", []string{"web"}},
+			},
+		},
+		{
+			name: "no servers",
+			yaml: "service: myapp\n",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := serversFrom(t, tt.yaml); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Servers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServersFallBackToBase(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "deploy.yml")
+	if err := os.WriteFile(base, []byte("servers:\n  - base-host\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	d := DeployDestination{Name: "staging", BasePath: base, Config: map[string]interface{}{"service": "myapp"}}
+	want := []ServerHost{{"base-host", []string{"web"}}}
+	if got := d.Servers(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Servers() = %v, want %v", got, want)
+	}
+}
+
+func TestSSHUserAndPort(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "deploy.yml")
+	if err := os.WriteFile(base, []byte("ssh:\n  user: deploy\n  port: 2222\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		yaml       string
+		base       string
+		user, port string
+	}{
+		{"unset", "service: myapp\n", "", "", ""},
+		{"ssh section", "ssh:\n  user: app\n  port: 22022\n", "", "app", "22022"},
+		{"legacy ssh_user", "ssh_user: ops\n", "", "ops", ""},
+		{"ssh.user wins", "ssh_user: ops\nssh:\n  user: app\n", "", "app", ""},
+		{"from base", "service: myapp\n", base, "deploy", "2222"},
+		{"overlay wins", "ssh:\n  user: staging\n", base, "staging", "2222"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg map[string]interface{}
+			if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+				t.Fatal(err)
+			}
+			d := DeployDestination{Config: cfg, BasePath: tt.base}
+			if got := d.SSHUser(); got != tt.user {
+				t.Errorf("SSHUser() = %q, want %q", got, tt.user)
+			}
+			if got := d.SSHPort(); got != tt.port {
+				t.Errorf("SSHPort() = %q, want %q", got, tt.port)
+			}
+		})
+	}
+}
+
+func TestServerRoles(t *testing.T) {
+	servers := serversFrom(t, "servers:\n  web: [10.0.0.1, 10.0.0.2]\n  job: [10.0.0.2]\n")
+	if got := ServerRoles(servers); !reflect.DeepEqual(got, []string{"job", "web"}) {
+		t.Errorf("ServerRoles() = %v", got)
+	}
+	if got := ServerRoles(nil); got != nil {
+		t.Errorf("ServerRoles(nil) = %v", got)
+	}
+}
+
+func TestValidateHost(t *testing.T) {
+	tests := []struct {
+		host  string
+		valid bool
+	}{
+		{"10.0.0.1", true},
+		{"2001:db8::1", true},
+		{"app-1.example.com", true},
+		{"localhost", true},
+		{"web01.", true},
+		{"", false},
+		{"10.0.0.1,10.0.0.2", false},
+		{"deploy@10.0.0.1", false},
+		{"my host", false},
+		{"-bad.example.com", false},
+		{"bad-.example.com", false},
+		{"under_score.example.com", false},
+		{"a..b", false},
+		{"host;rm -rf /", false},
+	}
+	for _, tt := range tests {
+		err := ValidateHost(tt.host)
+		if (err == nil) != tt.valid {
+			t.Errorf("ValidateHost(%q) error = %v, want valid=%v", tt.host, err, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package kamal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// StaleContainer is an app container left over from an older version, as
+// reported by `kamal app stale_containers`.
+type StaleContainer struct {
+	Host    string
+	Role    string
+	Version string
+}
+
+// ContainerName is the container's name on the host: Kamal 2 names app
+// containers <service>-<role>[-<destination>]-<version>.
+func (c StaleContainer) ContainerName(service, destination string) string {
+	parts := []string{service, c.Role}
+	if destination != "" {
+		parts = append(parts, destination)
+	}
+	return strings.Join(append(parts, c.Version), "-")
+}
+
+var staleLine = regexp.MustCompile(`(?:Detected|Stopping) stale container for role (\S+) with version (\S+)`)
+
+// ParseStaleContainers parses `kamal app stale_containers` output (with or
+// without --stop). Each host's report follows an "App Host:" header; a host
+// without stale containers prints nothing.
+func ParseStaleContainers(output string) []StaleContainer {
+	var out []StaleContainer
+	host := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isSSHKitLogLine(line) {
+			continue
+		}
+		if v, ok := cutField(line, "App Host:"); ok {
+			host = v
+			continue
+		}
+		if m := staleLine.FindStringSubmatch(line); m != nil && host != "" {
+			out = append(out, StaleContainer{Host: host, Role: m[1], Version: m[2]})
+		}
+	}
+	return out
+}
+
+// StaleVersions returns the distinct versions in containers, in order of
+// first appearance.
+func StaleVersions(containers []StaleContainer) []string {
+	seen := map[string]bool{}
+	var versions []string
+	for _, c := range containers {
+		if !seen[c.Version] {
+			seen[c.Version] = true
+			versions = append(versions, c.Version)
+		}
+	}
+	return versions
+}
@@ -0,0 +1,75 @@
+package kamal
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Kamal 2 output of `kamal app stale_containers` for two hosts, one clean.
+const staleFixture = `  INFO [5f3a9c21] Running docker ps --filter label=service=myapp --filter label=role=web --format "{{.Names}}" on 10.0.1.5
+  INFO [5f3a9c21] Finished in 0.512 seconds with exit status 0 (successful).
+  INFO [8e1d7b40] Running docker ps --filter label=service=myapp --filter label=role=web --format "{{.Names}}" on 10.0.1.6
+  INFO [8e1d7b40] Finished in 0.498 seconds with exit status 0 (successful).
+App Host: 10.0.1.5
+Detected stale container for role web with version 3c1a2b9 (use ` + "`kamal app stale_containers --stop`" + ` to stop)
+
+App Host: 10.0.1.5
+Detected stale container for role web with version 77de0f1 (use ` + "`kamal app stale_containers --stop`" + ` to stop)
+
+App Host: 10.0.1.5
+Detected stale container for role job with version 3c1a2b9 (use ` + "`kamal app stale_containers --stop`" + ` to stop)
+
+`
+
+const staleStopFixture = `App Host: 10.0.1.6
+Stopping stale container for role web with version 3c1a2b9
+
+  INFO [0b2c4d6e] Running docker container ls --all --filter name=^myapp-web-3c1a2b9$ --quiet | xargs docker stop on 10.0.1.6
+  INFO [0b2c4d6e] Finished in 1.204 seconds with exit status 0 (successful).
+`
+
+func TestParseStaleContainers(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []StaleContainer
+	}{
+		{
+			name:   "detected",
+			output: staleFixture,
+			want: []StaleContainer{
+				{Host: "10.0.1.5", Role: "web", Version: "3c1a2b9"},
+				{Host: "10.0.1.5", Role: "web", Version: "77de0f1"},
+				{Host: "10.0.1.5", Role: "job", Version: "3c1a2b9"},
+			},
+		},
+		{
+			name:   "stopping",
+			output: staleStopFixture,
+			want:   []StaleContainer{{Host: "10.0.1.6", Role: "web", Version: "3c1a2b9"}},
+		},
+		{
+			name:   "none",
+			output: "  INFO [5f3a9c21] Running docker ps on 10.0.1.5\n  INFO [5f3a9c21] Finished in 0.5 seconds with exit status 0 (successful).\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		if got := ParseStaleContainers(tt.output); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ParseStaleContainers() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStaleContainerName(t *testing.T) {
+	c := StaleContainer{Host: "10.0.1.5", Role: "web", Version: "3c1a2b9"}
+	if got := c.ContainerName("myapp", ""); got != "myapp-web-3c1a2b9" {
+		t.Errorf("ContainerName() = %q", got)
+	}
+	if got := c.ContainerName("myapp", "staging"); got != "myapp-web-staging-3c1a2b9" {
+		t.Errorf("ContainerName(staging) = %q", got)
+	}
+	if got := StaleVersions(ParseStaleContainers(staleFixture)); !reflect.DeepEqual(got, []string{"3c1a2b9", "77de0f1"}) {
+		t.Errorf("StaleVersions() = %v", got)
+	}
+}
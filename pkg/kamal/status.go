@@ -0,0 +1,97 @@
+package kamal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AppStatus is what the status panel polls for a destination: the version
+// running on each host and the app containers, for `lazykamal status`.
+type AppStatus struct {
+	App         string            `json:"app"`
+	Destination string            `json:"destination"`
+	Config      string            `json:"config"`
+	Versions    map[string]string `json:"versions"` // host -> running version
+	Containers  []HostContainer   `json:"containers"`
+	Errors      []string          `json:"errors,omitempty"`
+}
+
+// FetchStatus runs `kamal app version` and `kamal app containers` for dest,
+// like the status poller. A failed call is recorded in Errors and the other
+// one still runs.
+func FetchStatus(opts RunOptions, dest *DeployDestination) AppStatus {
+	s := AppStatus{
+		App:         dest.Label(),
+		Destination: dest.Name,
+		Config:      dest.ConfigSource(opts.Cwd),
+		Versions:    map[string]string{},
+		Containers:  []HostContainer{},
+	}
+	if r, err := AppVersion(opts); err == nil && r.ExitCode == 0 {
+		s.Versions = ParseAppVersions(r.Combined())
+	} else {
+		s.Errors = append(s.Errors, "app version: "+statusError(r, err))
+	}
+	if r, err := AppContainers(opts); err == nil && r.ExitCode == 0 {
+		if c := ParseAppContainers(r.Combined()); c != nil {
+			s.Containers = c
+		}
+	} else {
+		s.Errors = append(s.Errors, "app containers: "+statusError(r, err))
+	}
+	return s
+}
+
+// statusError is the first line explaining why a status call failed.
+func statusError(r Result, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	for _, l := range strings.Split(r.Stderr+"\n"+r.Stdout, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			return l
+		}
+	}
+	return fmt.Sprintf("exit %d", r.ExitCode)
+}
+
+// Summary renders the status for a terminal, e.g.
+//
+//	myapp (staging)  base: config/deploy.yml + overlay: config/deploy.staging.yml
+//	  10.0.0.1  abc123  1 container(s), 1 running
+func (s AppStatus) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s  %s\n", s.App, s.Config)
+	hosts := map[string]bool{}
+	for h := range s.Versions {
+		hosts[h] = true
+	}
+	total, running := map[string]int{}, map[string]int{}
+	for _, c := range s.Containers {
+		hosts[c.Host] = true
+		total[c.Host]++
+		if c.Running() {
+			running[c.Host]++
+		}
+	}
+	names := make([]string, 0, len(hosts))
+	for h := range hosts {
+		names = append(names, h)
+	}
+	sort.Strings(names)
+	for _, h := range names {
+		version := s.Versions[h]
+		if version == "" {
+			version = "-"
+		}
+		fmt.Fprintf(&b, "  %s  %s  %d container(s), %d running\n", h, version, total[h], running[h])
+	}
+	if len(names) == 0 && len(s.Errors) == 0 {
+		b.WriteString("  no hosts reported\n")
+	}
+	for _, e := range s.Errors {
+		fmt.Fprintf(&b, "  error: %s\n", e)
+	}
+	return b.String()
+}
@@ -0,0 +1,44 @@
+package kamal
+
+import (
+	"testing"
+)
+
+func TestFetchStatus(t *testing.T) {
+	fakeKamal(t, `case "$2" in
+version) printf 'App Host: 10.0.0.1\nabc123\nApp Host: 10.0.0.2\nabc123\n' ;;
+containers) printf 'App Host: 10.0.0.1\nCONTAINER ID   IMAGE          COMMAND   CREATED   STATUS          PORTS   NAMES\nf00   app:abc123   "run"   1 hour ago   Up 1 hour   80/tcp   app-web\nbad   app:old   "run"   2 days ago   Exited (0) 1 day ago      app-web-old\n' ;;
+esac
+`)
+	dir := t.TempDir()
+	dest := &DeployDestination{Name: "staging", Service: "myapp", ConfigPath: dir + "/config/deploy.staging.yml", BasePath: dir + "/config/deploy.yml"}
+	s := FetchStatus(RunOpts(dir, dest), dest)
+	if len(s.Errors) != 0 {
+		t.Fatalf("Errors = %q", s.Errors)
+	}
+	want := "myapp (staging)  base: config/deploy.yml + overlay: config/deploy.staging.yml\n" +
+		"  10.0.0.1  abc123  2 container(s), 1 running\n" +
+		"  10.0.0.2  abc123  0 container(s), 0 running\n"
+	if got := s.Summary(); got != want {
+		t.Errorf("Summary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFetchStatusErrors(t *testing.T) {
+	fakeKamal(t, "echo 'ERROR (SSHKit::Runner::ExecuteError): connection refused' >&2\nexit 1\n")
+	dir := t.TempDir()
+	dest := &DeployDestination{Service: "myapp", ConfigPath: dir + "/config/deploy.yml"}
+	s := FetchStatus(RunOpts(dir, dest), dest)
+	if len(s.Errors) != 2 {
+		t.Fatalf("Errors = %q, want both calls to fail", s.Errors)
+	}
+	if s.Versions == nil || s.Containers == nil {
+		t.Error("Versions and Containers should be empty, not nil, so JSON has {} and []")
+	}
+	want := "myapp  config/deploy.yml\n" +
+		"  error: app version: ERROR (SSHKit::Runner::ExecuteError): connection refused\n" +
+		"  error: app containers: ERROR (SSHKit::Runner::ExecuteError): connection refused\n"
+	if got := s.Summary(); got != want {
+		t.Errorf("Summary() =\n%s\nwant\n%s", got, want)
+	}
+}
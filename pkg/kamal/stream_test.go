@@ -0,0 +1,158 @@
+package kamal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeKamal puts a kamal shell script with the given body first on PATH.
+func fakeKamal(t *testing.T, body string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "kamal"), []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestRunKamalStreamWithStopExitCode(t *testing.T) {
+	fakeKamal(t, "echo building\necho 'ERROR boom' >&2\nexit 3\n")
+	var got []string
+	res, err := RunKamalStreamWithStop([]string{"deploy"}, RunOptions{}, func(line string) {
+		got = append(got, line)
+	}, nil)
+	if err != nil {
+		t.Fatalf("RunKamalStreamWithStop() error = %v", err)
+	}
+	if res.ExitCode != 3 || !res.Streamed {
+		t.Errorf("Result = %+v, want exit 3 and Streamed", res)
+	}
+	if len(got) != 2 || len(res.Lines()) != 2 {
+		t.Errorf("lines = %q, Result lines = %q; want both lines", got, res.Lines())
+	}
+}
+
+func TestRunKamalStreamWithStopCancel(t *testing.T) {
+	fakeKamal(t, "echo started\nexec sleep 30\n")
+	stopCh := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		<-started
+		close(stopCh)
+	}()
+	begin := time.Now()
+	res, err := RunKamalStreamWithStop([]string{"deploy"}, RunOptions{}, func(string) {
+		close(started)
+	}, stopCh)
+	if err == nil || !strings.Contains(err.Error(), "cancelled") || res.ExitCode != -1 {
+		t.Errorf("cancelled run = %+v, %v; want exit -1 and a cancelled error", res, err)
+	}
+	if time.Since(begin) > 10*time.Second {
+		t.Errorf("cancel took %s", time.Since(begin))
+	}
+}
+
+func TestScanLinesLong(t *testing.T) {
+	long := strings.Repeat("x", maxOutputLine+10)
+	var got []int
+	scanLines(strings.NewReader("a\r\n"+long+"\nlast"), func(line string) {
+		got = append(got, len(line))
+	})
+	want := []int{1, maxOutputLine, 10, 4}
+	if len(got) != len(want) {
+		t.Fatalf("line lengths = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line lengths = %v, want %v", got, want)
+			break
+		}
+	}
+}
+
+func TestRunKamalContextTimeout(t *testing.T) {
+	fakeKamal(t, "echo started\nexec sleep 5\n")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	res, err := RunKamalContext(ctx, []string{"app", "version"}, RunOptions{})
+	if took := time.Since(start); took > 3*time.Second {
+		t.Errorf("RunKamalContext() returned after %s, want the timeout", took)
+	}
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) {
+		t.Fatalf("err = %v, want *TimeoutError", err)
+	}
+	if got := err.Error(); got != "timed out after 200ms" {
+		t.Errorf("err = %q", got)
+	}
+	if res.ExitCode != -1 || !strings.Contains(res.Stdout, "started") {
+		t.Errorf("Result = %+v, want exit -1 and the output so far", res)
+	}
+
+	stopCh := make(chan struct{})
+	close(stopCh)
+	if _, err := RunKamalWithStop([]string{"app", "version"}, RunOptions{}, stopCh); err == nil || errors.As(err, &timeout) {
+		t.Errorf("stopped: err = %v, want cancelled", err)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	tests := []struct {
+		subcommand []string
+		want       time.Duration
+	}{
+		{[]string{"app", "version"}, StatusCommandTimeout},
+		{[]string{"app", "containers", "-q"}, StatusCommandTimeout},
+		{[]string{"details"}, StatusCommandTimeout},
+		{[]string{"deploy", "--skip-push"}, 0},
+		{[]string{"build", "push"}, 0},
+		{[]string{"app", "logs", "--follow"}, 0},
+		{[]string{"app", "exec", "bin/rails db:migrate"}, 0},
+		{[]string{"app", "boot"}, DefaultCommandTimeout},
+	}
+	for _, tt := range tests {
+		if got := CommandTimeout(tt.subcommand); got != tt.want {
+			t.Errorf("CommandTimeout(%v) = %s, want %s", tt.subcommand, got, tt.want)
+		}
+	}
+	if got := (&TimeoutError{After: StatusCommandTimeout}).Error(); got != "timed out after 60s" {
+		t.Errorf("TimeoutError = %q", got)
+	}
+	if got := (&TimeoutError{After: DefaultCommandTimeout}).Error(); got != "timed out after 10m" {
+		t.Errorf("TimeoutError = %q", got)
+	}
+}
+
+func TestRunKamalEnv(t *testing.T) {
+	fakeKamal(t, "echo \"token=${MIGRATION_TOKEN:-unset}\"\n")
+	calls := 0
+	opts := RunOptions{Env: func() []string {
+		calls++
+		if calls > 1 {
+			return nil
+		}
+		return []string{"MIGRATION_TOKEN=t0k3n"}
+	}}
+	for _, want := range []string{"token=t0k3n", "token=unset"} {
+		res, err := RunKamal([]string{"app", "exec", "true"}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSpace(res.Stdout); got != want {
+			t.Errorf("run %d: %q, want %q", calls, got, want)
+		}
+	}
+	if os.Getenv("MIGRATION_TOKEN") != "" {
+		t.Error("the variable leaked into our own environment")
+	}
+}
@@ -0,0 +1,105 @@
+package kamal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// versionCommands are the command keys (see CommandKey) that act on the app
+// containers of one version and so honor --version. Kamal accepts the flag
+// everywhere, but elsewhere it is ignored or, for rollback, replaced by the
+// positional version.
+var versionCommands = map[string]bool{
+	"deploy":               true,
+	"redeploy":             true,
+	"app_boot":             true,
+	"app_containers":       true,
+	"app_details":          true,
+	"app_exec":             true,
+	"app_live":             true,
+	"app_logs":             true,
+	"app_maintenance":      true,
+	"app_remove":           true,
+	"app_remove_container": true,
+	"app_start":            true,
+	"app_stop":             true,
+}
+
+// AcceptsVersion reports whether --version applies to subcommand.
+func AcceptsVersion(subcommand []string) bool {
+	return versionCommands[CommandKey(subcommand)]
+}
+
+var versionTag = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+// ValidateVersion checks that version is usable as a docker image tag.
+func ValidateVersion(version string) error {
+	if !versionTag.MatchString(version) {
+		return fmt.Errorf("%q is not a valid image tag", version)
+	}
+	return nil
+}
+
+// AppImage is one tagged app image found on the hosts.
+type AppImage struct {
+	Tag     string
+	Created string // docker's relative time, e.g. "2 hours ago"
+	Digest  string // registry digest, when the table has a DIGEST column
+	Hosts   []string
+}
+
+// ParseAppImages parses `kamal app images` output: an "App Host:" header
+// per host followed by a docker image ls table. Images are returned newest
+// first as docker lists them, one entry per tag across all hosts; "latest"
+// and untagged images are skipped since they name no specific version.
+// When the table was listed with --digests, the first digest seen for a tag
+// is kept.
+func ParseAppImages(output string) []AppImage {
+	var out []AppImage
+	index := map[string]int{}
+	host := ""
+	digests := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || isSSHKitLogLine(line) {
+			continue
+		}
+		if strings.HasPrefix(line, "REPOSITORY") {
+			digests = strings.Contains(line, "DIGEST")
+			continue
+		}
+		if v, ok := cutField(line, "App Host:"); ok {
+			host = v
+			continue
+		}
+		if host == "" {
+			continue
+		}
+		cols := columnSep.Split(line, -1)
+		digest := ""
+		if digests && len(cols) > 2 {
+			if cols[2] != "<none>" {
+				digest = cols[2]
+			}
+			cols = append(cols[:2:2], cols[3:]...)
+		}
+		if len(cols) < 4 {
+			continue
+		}
+		tag := cols[1]
+		if tag == "latest" || tag == "<none>" {
+			continue
+		}
+		if i, ok := index[tag]; ok {
+			out[i].Hosts = append(out[i].Hosts, host)
+			if out[i].Digest == "" {
+				out[i].Digest = digest
+			}
+			continue
+		}
+		index[tag] = len(out)
+		out = append(out, AppImage{Tag: tag, Created: cols[3], Digest: digest, Hosts: []string{host}})
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package kamal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandArgsVersion(t *testing.T) {
+	opts := RunOptions{Destination: "staging", Version: "a1b2c3"}
+	tests := []struct {
+		sub  []string
+		want []string
+	}{
+		{[]string{"app", "logs", "--follow"}, []string{"app", "logs", "--follow", "--destination", "staging", "--version", "a1b2c3"}},
+		{[]string{"app", "exec", "whoami"}, []string{"app", "exec", "whoami", "--destination", "staging", "--version", "a1b2c3"}},
+		{[]string{"deploy", "--skip-push"}, []string{"deploy", "--skip-push", "--destination", "staging", "--version", "a1b2c3"}},
+		{[]string{"app", "images"}, []string{"app", "images", "--destination", "staging"}},
+		{[]string{"rollback", "9f8e7d"}, []string{"rollback", "9f8e7d", "--destination", "staging"}},
+		{[]string{"proxy", "logs"}, []string{"proxy", "logs", "--destination", "staging"}},
+	}
+	for _, tt := range tests {
+		if got := commandArgs(tt.sub, opts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("commandArgs(%v) = %v, want %v", tt.sub, got, tt.want)
+		}
+	}
+}
+
+func TestValidateVersion(t *testing.T) {
+	for _, v := range []string{"a1b2c3", "v1.2.3", "9f8e7d6c_uncommitted_1a2b"} {
+		if err := ValidateVersion(v); err != nil {
+			t.Errorf("ValidateVersion(%q) = %v, want nil", v, err)
+		}
+	}
+	for _, v := range []string{"", "-rf", "a b", ".hidden", "x:y"} {
+		if err := ValidateVersion(v); err == nil {
+			t.Errorf("ValidateVersion(%q) = nil, want error", v)
+		}
+	}
+}
+
+func TestParseAppImages(t *testing.T) {
+	output := `  INFO [aa11] Running docker image ls registry/app on 10.0.0.1
+App Host: 10.0.0.1
+REPOSITORY     TAG        IMAGE ID       CREATED        SIZE
+registry/app   9f8e7d6c   1111aaaa2222   2 hours ago    210MB
+registry/app   latest     1111aaaa2222   2 hours ago    210MB
+registry/app   1a2b3c4d   3333bbbb4444   3 days ago     208MB
+registry/app   <none>     5555cccc6666   5 days ago     207MB
+
+App Host: 10.0.0.2
+REPOSITORY     TAG        IMAGE ID       CREATED        SIZE
+registry/app   9f8e7d6c   1111aaaa2222   2 hours ago    210MB
+`
+	want := []AppImage{
+		{Tag: "9f8e7d6c", Created: "2 hours ago", Hosts: []string{"10.0.0.1", "10.0.0.2"}},
+		{Tag: "1a2b3c4d", Created: "3 days ago", Hosts: []string{"10.0.0.1"}},
+	}
+	if got := ParseAppImages(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAppImages() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAppImagesDigests(t *testing.T) {
+	output := `App Host: 10.0.0.1
+REPOSITORY     TAG        DIGEST                                                                    IMAGE ID       CREATED        SIZE
+registry/app   9f8e7d6c   sha256:5d41402abc4b2a76b9719d911017c592ae2a1f6d5e0ad1f2b4f0e4a2c6d8e0f1   1111aaaa2222   2 hours ago    210MB
+registry/app   1a2b3c4d   <none>                                                                    3333bbbb4444   3 days ago     208MB
+
+App Host: 10.0.0.2
+REPOSITORY     TAG        IMAGE ID       CREATED        SIZE
+registry/app   1a2b3c4d   3333bbbb4444   3 days ago     208MB
+`
+	want := []AppImage{
+		{Tag: "9f8e7d6c", Created: "2 hours ago", Digest: "sha256:5d41402abc4b2a76b9719d911017c592ae2a1f6d5e0ad1f2b4f0e4a2c6d8e0f1", Hosts: []string{"10.0.0.1"}},
+		{Tag: "1a2b3c4d", Created: "3 days ago", Hosts: []string{"10.0.0.1", "10.0.0.2"}},
+	}
+	if got := ParseAppImages(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAppImages() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package kamal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusCommandTimeout limits the quick read-only commands (version,
+// containers, details, …). One that takes longer is almost always stuck on
+// an unreachable host.
+const StatusCommandTimeout = 60 * time.Second
+
+// statusCommands are the CommandKey values limited to StatusCommandTimeout.
+var statusCommands = map[string]bool{
+	"audit":                true,
+	"config":               true,
+	"details":              true,
+	"version":              true,
+	"accessory_details":    true,
+	"app_containers":       true,
+	"app_details":          true,
+	"app_images":           true,
+	"app_stale_containers": true,
+	"app_version":          true,
+	"build_details":        true,
+	"lock_status":          true,
+	"proxy_details":        true,
+}
+
+// longCommands run for as long as they need: they are cancelled, never
+// timed out. Builds and logs are included by prefix and suffix. App exec
+// runs whatever the user typed, e.g. a data migration.
+var longCommands = map[string]bool{
+	"deploy":   true,
+	"redeploy": true,
+	"rollback": true,
+	"setup":    true,
+	"app_exec": true,
+}
+
+// CommandTimeout is how long subcommand may run: StatusCommandTimeout for
+// status-style reads, 0 (no limit) for deploys, builds, logs and app exec,
+// and DefaultCommandTimeout for everything else.
+func CommandTimeout(subcommand []string) time.Duration {
+	key := CommandKey(subcommand)
+	switch {
+	case statusCommands[key]:
+		return StatusCommandTimeout
+	case longCommands[key], strings.HasPrefix(key, "build_"), strings.HasSuffix(key, "_logs"):
+		return 0
+	}
+	return DefaultCommandTimeout
+}
+
+// TimeoutError is returned when a command is killed for running longer
+// than its timeout, as opposed to being cancelled or failing.
+type TimeoutError struct {
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return "timed out after " + formatTimeout(e.After)
+}
+
+// formatTimeout renders a limit the way it is configured: "60s", "10m".
+func formatTimeout(d time.Duration) string {
+	switch {
+	case d >= 2*time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d >= time.Second && d%time.Second == 0:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return d.String()
+}
+
+// commandContext limits a run of subcommand to its CommandTimeout and
+// cancels it when stopCh is closed. A nil stopCh never cancels.
+func commandContext(subcommand []string, stopCh <-chan struct{}) (context.Context, context.CancelFunc) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if limit := CommandTimeout(subcommand); limit > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), limit)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	if stopCh != nil {
+		go func() {
+			select {
+			case <-stopCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+	return ctx, cancel
+}
+
+// contextLimit is the time left before ctx's deadline, rounded to what was
+// most likely configured, or 0 without a deadline.
+func contextLimit(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	left := time.Until(deadline)
+	if left >= time.Second {
+		return left.Round(time.Second)
+	}
+	return left.Round(time.Millisecond)
+}
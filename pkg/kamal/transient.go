@@ -0,0 +1,117 @@
+package kamal
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FailureKind is what made a kamal deploy fail, as far as its output tells.
+type FailureKind string
+
+const (
+	FailureRegistry     FailureKind = "registry"      // registry answered 5xx or timed out
+	FailureSSH          FailureKind = "ssh"           // SSH connection dropped on a host
+	FailureLock         FailureKind = "lock"          // another deploy holds the lock
+	FailureBuild        FailureKind = "build"         // the image did not build
+	FailureRegistryAuth FailureKind = "registry-auth" // the registry refused the credentials
+	FailureHealthcheck  FailureKind = "healthcheck"   // the new container never became healthy
+)
+
+// DeployFailure is the cause of a failed deploy found in its output.
+type DeployFailure struct {
+	Kind   FailureKind
+	Reason string // short description for the failure summary
+	Host   string // the host it happened on, when the output names one
+	Line   int    // index of the matching line
+}
+
+// Transient reports whether running the same deploy again may succeed.
+func (f DeployFailure) Transient() bool {
+	switch f.Kind {
+	case FailureRegistry, FailureSSH, FailureLock:
+		return true
+	}
+	return false
+}
+
+// deployFailures is the pattern table, checked in order against each line.
+var deployFailures = []struct {
+	kind    FailureKind
+	reason  string
+	pattern *regexp.Regexp
+}{
+	{FailureRegistry, "registry error (5xx)", regexp.MustCompile(`(?i)unexpected (?:http )?status.*:\s*5\d\d\b`)},
+	{FailureRegistry, "registry error (5xx)", regexp.MustCompile(`(?i)\b5\d\d (Bad Gateway|Service Unavailable|Gateway Time-?out|Internal Server Error)\b`)},
+	{FailureRegistry, "registry timed out", regexp.MustCompile(`(?i)TLS handshake timeout|i/o timeout.*registry|registry.*i/o timeout`)},
+	{FailureSSH, "SSH connection reset", regexp.MustCompile(`Errno::ECONNRESET|Connection reset by peer`)},
+	{FailureSSH, "SSH connection dropped", regexp.MustCompile(`Net::SSH::Disconnect|connection closed by remote host|Errno::EPIPE|Broken pipe`)},
+	{FailureSSH, "SSH connection timed out", regexp.MustCompile(`Net::SSH::ConnectionTimeout`)},
+	{FailureLock, "deploy lock held by another deploy", regexp.MustCompile(`Kamal::Cli::LockError|Deploy lock found|Deploy lock already in place`)},
+	{FailureRegistryAuth, "registry refused the credentials", regexp.MustCompile(`(?i)unauthorized: |authentication required|denied: requested access`)},
+	{FailureBuild, "image build failed", regexp.MustCompile(`failed to solve: process .* did not complete successfully|failed to compute cache key`)},
+	{FailureHealthcheck, "container did not become healthy", regexp.MustCompile(`(?i)failed to become healthy|target failed to become healthy|container not healthy`)},
+}
+
+// ClassifyDeployFailure finds why a deploy failed from its output lines.
+// The last matching line wins, since Kamal reports the error that stopped
+// it at the end; ok is false when no line matches the table.
+func ClassifyDeployFailure(lines []string) (f DeployFailure, ok bool) {
+	for n := len(lines) - 1; n >= 0; n-- {
+		line := lines[n]
+		for _, d := range deployFailures {
+			if !d.pattern.MatchString(line) {
+				continue
+			}
+			f = DeployFailure{Kind: d.kind, Reason: d.reason, Line: n}
+			if f.Kind == FailureSSH {
+				f.Host = failedHost(lines, n)
+			}
+			return f, true
+		}
+	}
+	return DeployFailure{}, false
+}
+
+// failedHost is the host named by the SSHKit exception on or before line n.
+func failedHost(lines []string, n int) string {
+	for ; n >= 0; n-- {
+		if m := sshkitFailed.FindStringSubmatch(lines[n]); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+var sshkitStarted = regexp.MustCompile(`^(?:INFO|DEBUG) \[([0-9a-f]+)\] Running (.*)$`)
+
+// PushSucceeded reports whether the output shows the image was pushed:
+// an SSHKit command that builds with --push (or to a registry output) or
+// runs docker push, finished with exit status 0. A retry can then pass
+// --skip-push.
+func PushSucceeded(lines []string) bool {
+	pushes := map[string]bool{}
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if m := sshkitStarted.FindStringSubmatch(line); m != nil {
+			if isPushCommand(m[2]) {
+				pushes[m[1]] = true
+			}
+			continue
+		}
+		if m := sshkitFinished.FindStringSubmatch(line); m != nil && pushes[m[1]] && m[3] == "0" {
+			return true
+		}
+	}
+	return false
+}
+
+func isPushCommand(cmd string) bool {
+	if !strings.Contains(cmd, "docker") {
+		return false
+	}
+	if strings.Contains(cmd, " push ") {
+		return true
+	}
+	return strings.Contains(cmd, "build") &&
+		(strings.Contains(cmd, " --push") || strings.Contains(cmd, "type=registry"))
+}
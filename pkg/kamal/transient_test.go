@@ -0,0 +1,128 @@
+package kamal
+
+import (
+	"strings"
+	"testing"
+)
+
+// pushFailedTranscript is `kamal deploy` whose build finished but whose push
+// to the registry got a 502.
+const pushFailedTranscript = `Log into image registry...
+  INFO [2b1e9c0a] Running docker login registry.example.com -u [REDACTED] -p [REDACTED] as deploy@localhost
+  INFO [2b1e9c0a] Finished in 1.204 seconds with exit status 0 (successful).
+Build and push app image...
+  INFO [7f3d5a21] Running docker buildx build --push --platform linux/amd64 --builder kamal-local-docker-container -t registry.example.com/myapp:4f2a9d1 -t registry.example.com/myapp:latest --label service="myapp" --file Dockerfile . as deploy@localhost
+ DEBUG [7f3d5a21] 	#14 exporting to image
+ DEBUG [7f3d5a21] 	#14 pushing layers 12.3s done
+ DEBUG [7f3d5a21] 	#14 ERROR: failed to push registry.example.com/myapp:4f2a9d1: unexpected status from PUT request to https://registry.example.com/v2/myapp/blobs/uploads/8c1f?digest=sha256%3A5003ab: 502 Bad Gateway
+ DEBUG [7f3d5a21] 	ERROR: failed to solve: failed to push registry.example.com/myapp:4f2a9d1: unexpected status from PUT request to https://registry.example.com/v2/myapp/blobs/uploads/8c1f?digest=sha256%3A5003ab: 502 Bad Gateway
+  Finished all in 96.4 seconds
+  ERROR (SSHKit::Command::Failed): docker exit status: 1
+docker stdout: Nothing written
+docker stderr: ERROR: failed to solve: failed to push registry.example.com/myapp:4f2a9d1: unexpected status from PUT request to https://registry.example.com/v2/myapp/blobs/uploads/8c1f?digest=sha256%3A5003ab: 502 Bad Gateway`
+
+// sshResetTranscript is `kamal deploy` that pushed the image, then lost
+// the connection to one of two hosts while pulling it.
+const sshResetTranscript = `Build and push app image...
+  INFO [7f3d5a21] Running docker buildx build --push --platform linux/amd64 -t registry.example.com/myapp:4f2a9d1 --file Dockerfile . as deploy@localhost
+  INFO [7f3d5a21] Finished in 88.019 seconds with exit status 0 (successful).
+Ensure kamal-proxy is running...
+  INFO [c0ffee12] Running docker container start kamal-proxy on 10.0.1.5
+  INFO [d00dfeed] Running docker container start kamal-proxy on 10.0.1.6
+  INFO [c0ffee12] Finished in 0.412 seconds with exit status 0 (successful).
+Pull app image...
+  INFO [a1b2c3d4] Running docker image pull registry.example.com/myapp:4f2a9d1 on 10.0.1.5
+  Releasing the deploy lock...
+  Finished all in 97.3 seconds
+  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.1.6: connection closed by remote host
+/usr/local/bundle/gems/net-ssh-7.2.0/lib/net/ssh/transport/packet_stream.rb:88:in 'next_packet': Errno::ECONNRESET`
+
+// lockTranscript is `kamal deploy` started while a teammate's deploy held
+// the lock.
+const lockTranscript = `Acquiring the deploy lock...
+  Finished all in 1.8 seconds
+  ERROR (Kamal::Cli::LockError): Deploy lock found. Run 'kamal lock help' for more information`
+
+// healthcheckTranscript is `kamal deploy` whose new container never became
+// healthy. Its app logs mention a reset connection, which is not the cause.
+const healthcheckTranscript = `Build and push app image...
+  INFO [7f3d5a21] Running docker buildx build --push -t registry.example.com/myapp:4f2a9d1 . as deploy@localhost
+  INFO [7f3d5a21] Finished in 61.2 seconds with exit status 0 (successful).
+  INFO [e5f6a7b8] Running docker container ls --all --filter name=^myapp-web-4f2a9d1$ --quiet | xargs docker logs --timestamps 2>&1 on 10.0.1.5
+ ERROR 2026-10-01T09:12:03Z PG::ConnectionBad: Connection reset by peer
+  INFO [e5f6a7b8] Finished in 0.301 seconds with exit status 0 (successful).
+  Releasing the deploy lock...
+  Finished all in 92.7 seconds
+  ERROR (SSHKit::Runner::ExecuteError): Exception while executing on host 10.0.1.5: docker exit status: 1
+docker stdout: Nothing written
+docker stderr: Error: target failed to become healthy within configured timeout (30s)`
+
+// buildFailedTranscript is `kamal deploy` whose Dockerfile step failed.
+const buildFailedTranscript = `Build and push app image...
+  INFO [7f3d5a21] Running docker buildx build --push -t registry.example.com/myapp:4f2a9d1 . as deploy@localhost
+ DEBUG [7f3d5a21] 	#9 ERROR: process "/bin/sh -c bundle install" did not complete successfully: exit code: 5
+  Finished all in 40.0 seconds
+  ERROR (SSHKit::Command::Failed): docker exit status: 1
+docker stdout: Nothing written
+docker stderr: ERROR: failed to solve: process "/bin/sh -c bundle install" did not complete successfully: exit code: 5`
+
+func TestClassifyDeployFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		ok         bool
+		kind       FailureKind
+		host       string
+		transient  bool
+	}{
+		{name: "registry 502 on push", transcript: pushFailedTranscript, ok: true, kind: FailureRegistry, transient: true},
+		{name: "ssh reset on one host", transcript: sshResetTranscript, ok: true, kind: FailureSSH, host: "10.0.1.6", transient: true},
+		{name: "lock contention", transcript: lockTranscript, ok: true, kind: FailureLock, transient: true},
+		{name: "healthcheck", transcript: healthcheckTranscript, ok: true, kind: FailureHealthcheck},
+		{name: "build failed", transcript: buildFailedTranscript, ok: true, kind: FailureBuild},
+		{name: "docker pull 503", transcript: "docker stderr: Error response from daemon: received unexpected HTTP status: 503 Service Unavailable", ok: true, kind: FailureRegistry, transient: true},
+		{name: "registry auth", transcript: "docker stderr: Error response from daemon: Get \"https://registry.example.com/v2/\": unauthorized: incorrect username or password", ok: true, kind: FailureRegistryAuth},
+		{name: "unknown", transcript: "  ERROR (ArgumentError): wrong number of arguments", ok: false},
+		{name: "empty", transcript: "", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := ClassifyDeployFailure(strings.Split(tt.transcript, "\n"))
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v (%+v)", ok, tt.ok, f)
+			}
+			if !ok {
+				return
+			}
+			if f.Kind != tt.kind || f.Host != tt.host || f.Transient() != tt.transient {
+				t.Errorf("got kind %q host %q transient %v, want %q %q %v", f.Kind, f.Host, f.Transient(), tt.kind, tt.host, tt.transient)
+			}
+			if f.Reason == "" {
+				t.Error("Reason is empty")
+			}
+		})
+	}
+}
+
+func TestPushSucceeded(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		want       bool
+	}{
+		{"push failed", pushFailedTranscript, false},
+		{"pushed then ssh reset", sshResetTranscript, true},
+		{"never got to build", lockTranscript, false},
+		{"build failed", buildFailedTranscript, false},
+		{"registry output", "  INFO [1a2b3c4d] Running docker buildx build --output=type=registry -t r/app:1 . as me@localhost\n  INFO [1a2b3c4d] Finished in 30.1 seconds with exit status 0 (successful).", true},
+		{"docker push", "  INFO [1a2b3c4d] Running docker push r/app:1 as me@localhost\n  INFO [1a2b3c4d] Finished in 3.0 seconds with exit status 0 (successful).", true},
+		{"other command finished", "  INFO [1a2b3c4d] Running docker buildx build --push -t r/app:1 . as me@localhost\n  INFO [9f9f9f9f] Finished in 1.0 seconds with exit status 0 (successful).", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PushSucceeded(strings.Split(tt.transcript, "\n")); got != tt.want {
+				t.Errorf("PushSucceeded = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
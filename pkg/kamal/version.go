@@ -0,0 +1,66 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BundledVersion returns the kamal version pinned in dir/Gemfile.lock, or ""
+// when there is no lockfile or it does not include kamal.
+func BundledVersion(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "Gemfile.lock"))
+	if err != nil {
+		return ""
+	}
+	return parseGemfileLock(string(data))
+}
+
+// parseGemfileLock finds the resolved kamal gem in a Gemfile.lock. Resolved
+// gems are listed under "specs:" with four-space indentation, e.g.
+// "    kamal (2.4.0)"; deeper-indented lines are dependency constraints.
+func parseGemfileLock(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "     ") {
+			continue
+		}
+		name, rest, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || name != "kamal" {
+			continue
+		}
+		version := strings.Trim(rest, "()")
+		// Platform-specific gems look like "kamal (2.4.0-x86_64-linux)".
+		if i := strings.IndexByte(version, '-'); i > 0 {
+			version = version[:i]
+		}
+		return version
+	}
+	return ""
+}
+
+// InstalledVersion returns the version reported by the kamal on PATH.
+func InstalledVersion(cwd string) (string, error) {
+	r, err := RunKamal([]string{"version"}, RunOptions{Cwd: cwd})
+	if err != nil {
+		return "", err
+	}
+	lines := r.Lines()
+	if r.ExitCode != 0 || len(lines) == 0 {
+		return "", nil
+	}
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+// VersionMismatch returns a warning when the kamal on PATH differs from the
+// version pinned in dir/Gemfile.lock, or "" when they match or either is unknown.
+func VersionMismatch(dir string) string {
+	bundled := BundledVersion(dir)
+	if bundled == "" {
+		return ""
+	}
+	installed, err := InstalledVersion(dir)
+	if err != nil || installed == "" || installed == bundled {
+		return ""
+	}
+	return "kamal " + installed + " on PATH, Gemfile.lock pins " + bundled
+}
@@ -0,0 +1,58 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const gemfileLockFixture = `GEM
+  remote: https://rubygems.org/
+  specs:
+    bcrypt_pbkdf (1.1.1)
+    kamal (2.4.0)
+      activesupport (>= 7.0)
+      bcrypt_pbkdf (~> 1.0)
+    kamal-custom (0.1.0)
+      kamal (>= 2.0)
+    rails (7.2.1)
+
+PLATFORMS
+  ruby
+
+DEPENDENCIES
+  kamal (~> 2.4)
+`
+
+func TestParseGemfileLock(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"resolved spec", gemfileLockFixture, "2.4.0"},
+		{"platform gem", "GEM\n  specs:\n    kamal (2.5.1-x86_64-linux)\n", "2.5.1"},
+		{"only as dependency", "GEM\n  specs:\n    other (1.0)\n      kamal (>= 2.0)\n", ""},
+		{"absent", "GEM\n  specs:\n    rails (7.2.1)\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseGemfileLock(tt.content); got != tt.want {
+				t.Errorf("parseGemfileLock() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBundledVersion(t *testing.T) {
+	dir := t.TempDir()
+	if got := BundledVersion(dir); got != "" {
+		t.Errorf("BundledVersion(no lockfile) = %q, want empty", got)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Gemfile.lock"), []byte(gemfileLockFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := BundledVersion(dir); got != "2.4.0" {
+		t.Errorf("BundledVersion() = %q, want 2.4.0", got)
+	}
+}
@@ -0,0 +1,285 @@
+package kamal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLChange is one targeted line edit: the Old lines starting at Line
+// (1-based) replaced by New. Inserts have no Old lines and start after
+// Line-1.
+type YAMLChange struct {
+	Line int
+	Old  []string
+	New  []string
+}
+
+// SplitYAMLPath splits a dotted path such as "env.clear.RAILS_LOG_LEVEL"
+// into mapping keys.
+func SplitYAMLPath(path string) ([]string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, errors.New("empty path")
+	}
+	keys := strings.Split(path, ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("path %q has an empty key", path)
+		}
+		if strings.ContainsAny(k, ": #") {
+			return nil, fmt.Errorf("key %q needs quoting; edit it by hand", k)
+		}
+	}
+	return keys, nil
+}
+
+// SetYAMLPath sets the value at the dotted path in content, creating
+// missing keys. Only the lines involved change, so comments, ordering and
+// quoting elsewhere are kept as written. It returns the new content and the
+// change; a zero change means the value was already set. Lists, flow-style
+// mappings and multi-line values are refused rather than rewritten.
+func SetYAMLPath(content, path, value string) (string, YAMLChange, error) {
+	keys, err := SplitYAMLPath(path)
+	if err != nil {
+		return "", YAMLChange{}, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return "", YAMLChange{}, err
+	}
+	lines := strings.Split(content, "\n")
+	scalar := yamlScalar(value)
+	if len(doc.Content) == 0 {
+		return insertKeys(lines, contentEnd(lines), 0, keys, scalar)
+	}
+	node := doc.Content[0]
+	if node.Kind != yaml.MappingNode {
+		return "", YAMLChange{}, errors.New("the top level is not a mapping")
+	}
+	for i, key := range keys {
+		where := strings.Join(keys[:i], ".")
+		if node.Style&yaml.FlowStyle != 0 {
+			return "", YAMLChange{}, fmt.Errorf("%s is written in flow style ({...}); edit it by hand", where)
+		}
+		k, v := mappingEntry(node, key)
+		if k == nil {
+			after := lastLine(node)
+			if node == doc.Content[0] {
+				after = contentEnd(lines)
+			}
+			return insertKeys(lines, after, node.Content[0].Column-1, keys[i:], scalar)
+		}
+		if i == len(keys)-1 {
+			return replaceValue(lines, path, k, v, scalar)
+		}
+		switch {
+		case v.Kind == yaml.MappingNode:
+			node = v
+		case v.Kind == yaml.ScalarNode && v.Tag == "!!null" && v.Value == "":
+			// "key:" with nothing under it yet.
+			return insertKeys(lines, k.Line, k.Column-1+2, keys[i+1:], scalar)
+		default:
+			return "", YAMLChange{}, fmt.Errorf("%s is %s, not a mapping", strings.Join(keys[:i+1], "."), yamlKind(v))
+		}
+	}
+	return "", YAMLChange{}, errors.New("unreachable")
+}
+
+// mappingEntry returns the key and value nodes for key in mapping n.
+func mappingEntry(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
+
+func yamlKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		return "a list"
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.AliasNode:
+		return "an alias"
+	}
+	return "a value"
+}
+
+// replaceValue rewrites the value of k on its line, keeping the key, its
+// indentation and any trailing comment.
+func replaceValue(lines []string, path string, k, v *yaml.Node, scalar string) (string, YAMLChange, error) {
+	if v.Kind != yaml.ScalarNode {
+		return "", YAMLChange{}, fmt.Errorf("%s is %s; only single values can be set", path, yamlKind(v))
+	}
+	if v.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 || (v.Value != "" && v.Line != k.Line) {
+		return "", YAMLChange{}, fmt.Errorf("%s is a multi-line value; edit it by hand", path)
+	}
+	line := []rune(lines[k.Line-1])
+	var head string
+	if v.Value == "" && v.Tag == "!!null" {
+		// "key:" or "key: ~"/"key: null": keep everything up to the colon.
+		rest := string(line[k.Column-1:])
+		colon := strings.Index(rest, ":")
+		if colon < 0 {
+			return "", YAMLChange{}, fmt.Errorf("cannot find the value of %s", path)
+		}
+		head = string(line[:k.Column-1]) + rest[:colon+1] + " "
+	} else {
+		head = string(line[:v.Column-1])
+	}
+	comment := v.LineComment
+	if comment == "" {
+		comment = k.LineComment
+	}
+	updated := head + scalar
+	if comment != "" {
+		updated += " " + comment
+	}
+	if updated == string(line) {
+		return strings.Join(lines, "\n"), YAMLChange{}, nil
+	}
+	change := YAMLChange{Line: k.Line, Old: []string{string(line)}, New: []string{updated}}
+	out := append([]string{}, lines...)
+	out[k.Line-1] = updated
+	return strings.Join(out, "\n"), change, nil
+}
+
+// insertKeys inserts keys as nested mappings after line after (1-based,
+// 0 for the top of the file), the first at indent columns, ending in
+// scalar.
+func insertKeys(lines []string, after, indent int, keys []string, scalar string) (string, YAMLChange, error) {
+	var added []string
+	for i, key := range keys {
+		l := strings.Repeat(" ", indent+2*i) + key + ":"
+		if i == len(keys)-1 {
+			l += " " + scalar
+		}
+		added = append(added, l)
+	}
+	out := append([]string{}, lines[:after]...)
+	out = append(out, added...)
+	out = append(out, lines[after:]...)
+	return strings.Join(out, "\n"), YAMLChange{Line: after + 1, New: added}, nil
+}
+
+// lastLine is the last line (1-based) n's content occupies.
+func lastLine(n *yaml.Node) int {
+	last := n.Line
+	if n.Kind == yaml.ScalarNode && n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		last += strings.Count(strings.TrimRight(n.Value, "\n"), "\n") + 1
+	}
+	for _, c := range n.Content {
+		if l := lastLine(c); l > last {
+			last = l
+		}
+	}
+	return last
+}
+
+// contentEnd is the number of lines up to the last non-blank one, where
+// top-level keys are appended.
+func contentEnd(lines []string) int {
+	n := len(lines)
+	for n > 0 && strings.TrimSpace(lines[n-1]) == "" {
+		n--
+	}
+	return n
+}
+
+// yamlScalar writes value as typed when YAML reads it back as one value
+// (so "80", "true" and "[a, b]" keep their types), and double-quoted
+// otherwise.
+func yamlScalar(value string) string {
+	if value != "" && !strings.ContainsAny(value, "\n#") {
+		var m map[string]interface{}
+		if err := yaml.Unmarshal([]byte("v: "+value), &m); err == nil && len(m) == 1 {
+			return value
+		}
+	}
+	out, err := yaml.Marshal(&yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: value})
+	if err != nil {
+		return fmt.Sprintf("%q", value)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// FileEdit is a planned rewrite of one file, with the content it was
+// planned against.
+type FileEdit struct {
+	Path   string
+	Before string
+	After  string
+}
+
+// WriteFileEdits applies edits all or nothing as far as the filesystem
+// allows: every file is checked against the content the edit was planned
+// on and written to a temporary file beside it before any is renamed into
+// place.
+func WriteFileEdits(edits []FileEdit) error {
+	var temps []string
+	cleanup := func() {
+		for _, t := range temps {
+			os.Remove(t)
+		}
+	}
+	for _, e := range edits {
+		data, err := os.ReadFile(e.Path)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		if string(data) != e.Before {
+			cleanup()
+			return fmt.Errorf("%s changed since the preview; nothing was written", filepath.Base(e.Path))
+		}
+		tmp, err := writeTemp(e.Path, e.After)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		temps = append(temps, tmp)
+	}
+	for i, e := range edits {
+		if err := os.Rename(temps[i], e.Path); err != nil {
+			for _, t := range temps[i:] {
+				os.Remove(t)
+			}
+			return fmt.Errorf("%w (%d of %d files written)", err, i, len(edits))
+		}
+	}
+	return nil
+}
+
+// writeTemp writes content to a new file next to path with path's mode.
+func writeTemp(path, content string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Chmod(fi.Mode().Perm()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,146 @@
+package kamal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const bulkEditFixture = `# Deploy to staging
+service: app
+image: acme/app
+
+registry:
+  server: ghcr.io # the org registry
+  username: deploy
+
+env:
+  clear:
+    RAILS_LOG_LEVEL: info
+  secret:
+    - RAILS_MASTER_KEY
+
+builder:
+  args:
+    NOTE: |
+      first
+      second
+proxy:
+`
+
+func TestSetYAMLPathReplace(t *testing.T) {
+	got, change, err := SetYAMLPath(bulkEditFixture, "registry.server", "registry.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(bulkEditFixture, "server: ghcr.io #", "server: registry.example.com #", 1)
+	if got != want {
+		t.Errorf("SetYAMLPath() =\n%s\nwant\n%s", got, want)
+	}
+	wantChange := YAMLChange{Line: 6, Old: []string{"  server: ghcr.io # the org registry"}, New: []string{"  server: registry.example.com # the org registry"}}
+	if !reflect.DeepEqual(change, wantChange) {
+		t.Errorf("change = %+v, want %+v", change, wantChange)
+	}
+
+	if _, change, err := SetYAMLPath(bulkEditFixture, "env.clear.RAILS_LOG_LEVEL", "info"); err != nil || change.Line != 0 {
+		t.Errorf("same value: change = %+v, err = %v; want no change", change, err)
+	}
+}
+
+func TestSetYAMLPathInsert(t *testing.T) {
+	got, change, err := SetYAMLPath(bulkEditFixture, "env.clear.WEB_CONCURRENCY", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(bulkEditFixture, "    RAILS_LOG_LEVEL: info\n", "    RAILS_LOG_LEVEL: info\n    WEB_CONCURRENCY: 2\n", 1)
+	if got != want {
+		t.Errorf("insert into mapping =\n%s", got)
+	}
+	if change.Line != 12 || len(change.Old) != 0 {
+		t.Errorf("change = %+v", change)
+	}
+
+	got, _, err = SetYAMLPath(bulkEditFixture, "proxy.ssl", "true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "proxy:\n  ssl: true\n") {
+		t.Errorf("insert under empty key =\n%s", got)
+	}
+
+	got, _, err = SetYAMLPath(bulkEditFixture, "builder.arch", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(got, "      second\n  arch: amd64\nproxy:") {
+		t.Errorf("insert after block scalar =\n%s", got)
+	}
+
+	got, _, err = SetYAMLPath(bulkEditFixture, "ssh.user", "app user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got, "proxy:\nssh:\n  user: app user\n") {
+		t.Errorf("insert at top level =\n%s", got)
+	}
+}
+
+func TestSetYAMLPathRefuses(t *testing.T) {
+	for _, path := range []string{"env.secret", "env", "builder.args.NOTE", "image.tag", "env..x"} {
+		if _, _, err := SetYAMLPath(bulkEditFixture, path, "x"); err == nil {
+			t.Errorf("SetYAMLPath(%q) = nil error", path)
+		}
+	}
+	if _, _, err := SetYAMLPath("servers: {web: [1.2.3.4]}\n", "servers.job", "x"); err == nil {
+		t.Error("SetYAMLPath(flow mapping) = nil error")
+	}
+}
+
+func TestYAMLScalar(t *testing.T) {
+	tests := map[string]string{
+		"ghcr.io":    "ghcr.io",
+		"80":         "80",
+		"[a, b]":     "[a, b]",
+		"a: b":       `"a: b"`,
+		"x # y":      `"x # y"`,
+		"":           `""`,
+		"*undefined": `"*undefined"`,
+	}
+	for in, want := range tests {
+		if got := yamlScalar(in); got != want {
+			t.Errorf("yamlScalar(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestWriteFileEdits(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "deploy.yml"), filepath.Join(dir, "deploy.staging.yml")
+	os.WriteFile(a, []byte("a: 1\n"), 0o600)
+	os.WriteFile(b, []byte("b: 1\n"), 0o644)
+
+	stale := []FileEdit{{Path: a, Before: "a: 1\n", After: "a: 2\n"}, {Path: b, Before: "b: 0\n", After: "b: 2\n"}}
+	if err := WriteFileEdits(stale); err == nil {
+		t.Fatal("WriteFileEdits(stale) = nil error")
+	}
+	if data, _ := os.ReadFile(a); string(data) != "a: 1\n" {
+		t.Errorf("a was written despite the stale edit: %q", data)
+	}
+
+	edits := []FileEdit{{Path: a, Before: "a: 1\n", After: "a: 2\n"}, {Path: b, Before: "b: 1\n", After: "b: 2\n"}}
+	if err := WriteFileEdits(edits); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(b); string(data) != "b: 2\n" {
+		t.Errorf("b = %q", data)
+	}
+	if fi, _ := os.Stat(a); fi.Mode().Perm() != 0o600 {
+		t.Errorf("a mode = %v, want 0600", fi.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,52 @@
+package ssh
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ValidateHost checks a server mode target of the form [user@]host[:port]
+// before anything is run with it.
+func ValidateHost(target string) error {
+	if strings.TrimSpace(target) == "" {
+		return fmt.Errorf("empty host; expected [user@]host[:port]")
+	}
+	if strings.ContainsAny(target, " \t\r\n") {
+		return fmt.Errorf("host %q contains whitespace; expected [user@]host[:port]", target)
+	}
+	if strings.HasPrefix(target, "-") {
+		return fmt.Errorf("host %q starts with '-'; expected [user@]host[:port]", target)
+	}
+	host := target
+	if i := strings.Index(host, "@"); i >= 0 {
+		if i == 0 {
+			return fmt.Errorf("host %q has an empty user before '@'", target)
+		}
+		host = host[i+1:]
+	}
+	if i := strings.Index(host, ":"); i >= 0 {
+		port := host[i+1:]
+		host = host[:i]
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("host %q has an invalid port %q; expected 1-65535", target, port)
+		}
+	}
+	if host == "" {
+		return fmt.Errorf("host %q has no host name; expected [user@]host[:port]", target)
+	}
+	if strings.HasPrefix(host, "-") || strings.Contains(host, "@") {
+		return fmt.Errorf("host %q is not a valid host name", target)
+	}
+	return nil
+}
+
+// CheckInstalled verifies that the ssh client server mode runs is on PATH.
+func CheckInstalled() error {
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return fmt.Errorf("ssh not found on PATH.\n\nServer mode connects with your OpenSSH client. Install it first:\n  macOS: included with the system\n  Debian/Ubuntu: sudo apt install openssh-client\n  Fedora/RHEL: sudo dnf install openssh-clients")
+	}
+	return nil
+}
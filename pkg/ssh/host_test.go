@@ -0,0 +1,38 @@
+package ssh
+
+import "testing"
+
+func TestValidateHost(t *testing.T) {
+	valid := []string{
+		"100.70.90.101",
+		"myserver.com",
+		"user@myserver.com",
+		"deploy@production:2222",
+		"host:22",
+	}
+	for _, h := range valid {
+		if err := ValidateHost(h); err != nil {
+			t.Errorf("ValidateHost(%q) = %v, want nil", h, err)
+		}
+	}
+	invalid := []string{
+		"",
+		"  ",
+		"my server",
+		"-oProxyCommand=x",
+		"user@-host",
+		"@host",
+		"user@",
+		":22",
+		"host:",
+		"host:ssh",
+		"host:0",
+		"host:70000",
+		"a@b@c",
+	}
+	for _, h := range invalid {
+		if err := ValidateHost(h); err == nil {
+			t.Errorf("ValidateHost(%q) = nil, want an error", h)
+		}
+	}
+}
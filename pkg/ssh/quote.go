@@ -0,0 +1,33 @@
+package ssh
+
+import "strings"
+
+// Quote quotes s for use as a single literal argument in a POSIX shell line.
+// Commands run over SSH are interpreted by the remote shell, so every
+// interpolated value (container names, image refs, user input) must go
+// through Quote or QuoteArgs.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("_-./:=@,+%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// QuoteArgs quotes each argument and joins them into one command line.
+func QuoteArgs(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = Quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
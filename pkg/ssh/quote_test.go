@@ -0,0 +1,88 @@
+package ssh
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// hostileInputs are values that would break out of an unquoted shell argument.
+var hostileInputs = []string{
+	"foo; rm -rf /",
+	"foo && touch /tmp/pwned",
+	"$(id)",
+	"`id`",
+	"it's",
+	`say "hi"`,
+	`back\slash`,
+	"line1\nline2",
+	"a | b > c",
+	"*",
+	"~root",
+	"${HOME}",
+	"-rf",
+	"",
+	" leading and trailing ",
+	"'",
+	"''",
+	`'\''`,
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"abc123", "abc123"},
+		{"basecamp/kamal-proxy:v0.8.0", "basecamp/kamal-proxy:v0.8.0"},
+		{"label=service=myapp", "label=service=myapp"},
+		{"", "''"},
+		{"foo; rm -rf /", "'foo; rm -rf /'"},
+		{"it's", `'it'\''s'`},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestQuoteShellRoundTrip runs each quoted value through a real POSIX shell
+// and checks it arrives as exactly one literal argument.
+func TestQuoteShellRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	for _, in := range hostileInputs {
+		// printf emits each argument between markers; one argument proves no word splitting.
+		line := "printf '<%s>' " + Quote(in)
+		out, err := exec.Command(sh, "-c", line).Output()
+		if err != nil {
+			t.Errorf("sh -c %q failed: %v", line, err)
+			continue
+		}
+		if got, want := string(out), "<"+in+">"; got != want {
+			t.Errorf("round trip of %q = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestQuoteArgsShellRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	line := "printf '<%s>' " + QuoteArgs(hostileInputs...)
+	out, err := exec.Command(sh, "-c", line).Output()
+	if err != nil {
+		t.Fatalf("sh -c failed: %v", err)
+	}
+	var want strings.Builder
+	for _, in := range hostileInputs {
+		want.WriteString("<" + in + ">")
+	}
+	if string(out) != want.String() {
+		t.Errorf("QuoteArgs round trip =\n  %q\nwant\n  %q", out, want.String())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -15,6 +16,11 @@ type Client struct {
 	Host string
 	User string
 	Port string
+	// Sudo runs docker commands through `sudo -n` for the session, for users
+	// that cannot reach the Docker daemon directly.
+	Sudo bool
+	// OnRun, when set, is called with each remote command before it runs.
+	OnRun func(command string)
 }
 
 // NewClient creates a new SSH client
@@ -50,6 +56,7 @@ func (c *Client) Run(command string) (string, error) {
 
 // RunWithTimeout executes a command with a custom timeout
 func (c *Client) RunWithTimeout(command string, timeout time.Duration) (string, error) {
+	c.echo(command)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
@@ -79,6 +86,7 @@ func (c *Client) RunWithTimeout(command string, timeout time.Duration) (string,
 // RunStream executes a command and streams output line by line.
 // Has a 10 minute timeout to prevent hanging on stuck SSH connections.
 func (c *Client) RunStream(command string, onLine func(string), stopCh <-chan struct{}) error {
+	c.echo(command)
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
@@ -159,6 +167,59 @@ func (c *Client) RunStream(command string, onLine func(string), stopCh <-chan st
 	return nil
 }
 
+// RunToWriter executes a command and copies its stdout to w as it arrives,
+// without buffering it. Stderr is kept (up to 4 KB) for the error message.
+// Has a 30 minute timeout; closing stopCh kills the command.
+func (c *Client) RunToWriter(command string, w io.Writer, stopCh <-chan struct{}) error {
+	c.echo(command)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if stopCh != nil {
+		go func() {
+			select {
+			case <-stopCh:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	args := c.buildSSHArgs()
+	args = append(args, command)
+
+	cmd := exec.CommandContext(ctx, "ssh", args...)
+	stderr := &tailBuffer{max: 4096}
+	cmd.Stdout = w
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("command timed out after 30m")
+	case context.Canceled:
+		return fmt.Errorf("cancelled")
+	}
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	n, _ := b.Buffer.Write(p)
+	if over := b.Len() - b.max; over > 0 {
+		b.Next(over)
+	}
+	return n, nil
+}
+
 // TestConnection tests if SSH connection works
 func (c *Client) TestConnection() error {
 	_, err := c.Run("echo ok")
@@ -194,6 +255,56 @@ func (c *Client) buildSSHArgs() []string {
 	return args
 }
 
+// InteractiveCommand returns an ssh command that runs command on the host
+// with a terminal attached, reusing the multiplexed connection.
+func (c *Client) InteractiveCommand(command string) *exec.Cmd {
+	args := append([]string{"-t"}, c.buildSSHArgs()...)
+	return exec.Command("ssh", append(args, command)...)
+}
+
+// ForwardCommand is the ssh command that forwards localPort on this machine
+// to host:port as the server sees it, as a user would type it.
+func (c *Client) ForwardCommand(localPort int, host string, port int) string {
+	args := []string{"ssh", "-N", "-L", forwardSpec(localPort, host, port)}
+	if c.Port != "22" {
+		args = append(args, "-p", c.Port)
+	}
+	return strings.Join(append(args, c.HostDisplay()), " ")
+}
+
+// ForwardCmd returns that tunnel as a command to run in the background. It
+// opens its own connection rather than the multiplexed one, so killing it
+// closes only the tunnel, and it exits when the local port is taken.
+func (c *Client) ForwardCmd(localPort int, host string, port int) *exec.Cmd {
+	args := []string{
+		"-N", "-L", forwardSpec(localPort, host, port),
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=accept-new",
+		"-o", "ConnectTimeout=10",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+		"-o", "ControlPath=none",
+	}
+	if c.Port != "22" {
+		args = append(args, "-p", c.Port)
+	}
+	return exec.Command("ssh", append(args, c.HostDisplay())...)
+}
+
+// forwardSpec is the -L argument; IPv6 hosts are bracketed.
+func forwardSpec(localPort int, host string, port int) string {
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	return fmt.Sprintf("%d:%s:%d", localPort, host, port)
+}
+
+func (c *Client) echo(command string) {
+	if c.OnRun != nil {
+		c.OnRun(command)
+	}
+}
+
 // HostDisplay returns a display string for the host
 func (c *Client) HostDisplay() string {
 	if c.User != "" {
@@ -0,0 +1,36 @@
+package ssh
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestForwardCommand(t *testing.T) {
+	tests := []struct {
+		host       string
+		targetHost string
+		want       string
+	}{
+		{"deploy@10.0.1.5", "127.0.0.1", "ssh -N -L 15432:127.0.0.1:5432 deploy@10.0.1.5"},
+		{"deploy@10.0.1.5:2222", "172.18.0.3", "ssh -N -L 15432:172.18.0.3:5432 -p 2222 deploy@10.0.1.5"},
+		{"10.0.1.5", "::1", "ssh -N -L 15432:[::1]:5432 10.0.1.5"},
+	}
+	for _, tt := range tests {
+		if got := NewClient(tt.host).ForwardCommand(15432, tt.targetHost, 5432); got != tt.want {
+			t.Errorf("ForwardCommand(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestForwardCmd(t *testing.T) {
+	cmd := NewClient("deploy@10.0.1.5:2222").ForwardCmd(15432, "127.0.0.1", 5432)
+	args := strings.Join(cmd.Args, " ")
+	for _, want := range []string{"-N -L 15432:127.0.0.1:5432", "ExitOnForwardFailure=yes", "ControlPath=none", "-p 2222 deploy@10.0.1.5"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("ForwardCmd args %q lack %q", args, want)
+		}
+	}
+	if !strings.HasSuffix(args, "deploy@10.0.1.5") {
+		t.Errorf("ForwardCmd args %q do not end with the target", args)
+	}
+}